@@ -16,9 +16,13 @@ import (
 
 	"github.com/devanshbhargava/stan-store/internal/adapters/ai"
 	"github.com/devanshbhargava/stan-store/internal/adapters/email"
+	"github.com/devanshbhargava/stan-store/internal/adapters/ffmpeg"
 	httpAdapter "github.com/devanshbhargava/stan-store/internal/adapters/http"
+	"github.com/devanshbhargava/stan-store/internal/adapters/scanner"
 	"github.com/devanshbhargava/stan-store/internal/adapters/storage"
+	"github.com/devanshbhargava/stan-store/internal/adapters/webp"
 	"github.com/devanshbhargava/stan-store/internal/config"
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
 	"github.com/devanshbhargava/stan-store/internal/core/services"
 	"github.com/devanshbhargava/stan-store/pkg/logger"
 
@@ -73,6 +77,18 @@ func main() {
 		rawRedisClient = redisClient.Client
 	}
 	authService := services.NewAuthService(userRepo, jwtService, rawRedisClient, emailAdapter)
+
+	// Refresh-token sessions live in Redis; without it logins fall back to long-lived tokens
+	var sessionService *services.SessionService
+	var twoFactorService *services.TwoFactorService
+	if rawRedisClient != nil {
+		sessionService = services.NewSessionService(rawRedisClient, jwtService, userRepo)
+		authService.SetSessionService(sessionService)
+		twoFactorService = services.NewTwoFactorService(userRepo, rawRedisClient, cfg.JWTSecret)
+		authService.SetTwoFactorService(twoFactorService)
+	} else {
+		logger.Warn("redis unavailable, sessions and two-factor authentication disabled")
+	}
 	usernameService := services.NewUsernameService(userRepo, platformReferralRepo)
 	profileService := services.NewProfileService(userRepo, cache)
 	productService := services.NewProductService(productRepo, cache)
@@ -83,20 +99,56 @@ func main() {
 	paymentRepo := storage.NewMongoPaymentRepository(mongoDB.Database)
 	paymentService := services.NewPaymentService(paymentRepo, cfg)
 
-	// ... (S3 storage init) ...
-	fileStorage, err := storage.NewS3Storage(
-		cfg.R2AccountID,
-		cfg.R2AccessKeyID,
-		cfg.R2SecretAccessKey,
-		cfg.R2BucketName,
-		cfg.R2Endpoint,
-	)
-	if err != nil {
-		logger.Error("failed to initialize s3 storage", "error", err.Error())
-		// non-fatal for now to allow app to start even if storage is misconfigured (unless it's critical)
+	// Object storage: S3-compatible in production, local disk for development and tests
+	var fileStorage domain.FileStorage
+	var localStorageHandler *httpAdapter.LocalStorageHandler
+	if cfg.StorageDriver == "local" {
+		localStorage, err := storage.NewLocalStorage(cfg.LocalStoragePath, cfg.APIBaseURL+"/api/v1/storage", cfg.JWTSecret)
+		if err != nil {
+			logger.Fatal("failed to initialize local storage", "error", err.Error())
+		}
+		fileStorage = localStorage
+		localStorageHandler = httpAdapter.NewLocalStorageHandler(localStorage)
+		logger.Warn("using local file storage; do not use in production", "path", cfg.LocalStoragePath)
+	} else {
+		s3Storage, err := storage.NewS3Storage(
+			cfg.R2AccountID,
+			cfg.R2AccessKeyID,
+			cfg.R2SecretAccessKey,
+			cfg.R2BucketName,
+			cfg.R2Endpoint,
+		)
+		if err != nil {
+			logger.Error("failed to initialize s3 storage", "error", err.Error())
+			// non-fatal for now to allow app to start even if storage is misconfigured (unless it's critical)
+		}
+		fileStorage = s3Storage
 	}
 
 	uploadService := services.NewUploadService(fileStorage)
+	var malwareScanner domain.MalwareScanner = scanner.NoopScanner{}
+	if cfg.ClamAVAddress != "" {
+		clamav, err := scanner.NewClamAVScanner(cfg.ClamAVAddress, 2*time.Minute)
+		if err != nil {
+			logger.Fatal("invalid CLAMAV_ADDRESS", "error", err.Error())
+		}
+		malwareScanner = clamav
+	} else {
+		logger.Warn("CLAMAV_ADDRESS not set; uploads will not be scanned for malware")
+	}
+	uploadRepo := storage.NewMongoUploadRepository(mongoDB)
+	uploadService.SetVerification(uploadRepo, malwareScanner)
+	productService.SetUploadService(uploadService)
+
+	// Image variants for covers and profile photos
+	imageService := services.NewImageService(fileStorage, uploadRepo, cache)
+	if codec, err := webp.NewLibWebPCodec(cfg.CWebPPath, cfg.DWebPPath); err == nil {
+		imageService.SetWebPCodec(codec)
+	} else {
+		logger.Warn("libwebp tools not found; image variants will be JPEG only", "error", err.Error())
+	}
+	uploadService.SetImageService(imageService)
+	storeService.SetImageService(imageService)
 
 	// Initialize Wallet Service
 	transactionRepo := storage.NewMongoTransactionRepository(mongoDB.Database)
@@ -105,8 +157,14 @@ func main() {
 	subscriberRepo := storage.NewMongoSubscriberRepository(mongoDB.Database)
 	subRepo := storage.NewMongoSubscriptionRepository(mongoDB)
 
+	// Append-only log of admin and payout-affecting actions
+	auditService := services.NewAuditService(storage.NewMongoAuditLogRepository(mongoDB), userRepo)
+
 	emailTemplateRepo := storage.NewMongoEmailTemplateRepository(mongoDB.Database)
 	emailTemplateService := services.NewEmailTemplateService(emailTemplateRepo)
+	emailTemplateService.SetAuditService(auditService)
+	authService.SetFrontendURL(cfg.FrontendURL)
+	authService.SetEmailTemplateService(emailTemplateService)
 	emailTemplateHandler := httpAdapter.NewEmailTemplateHandler(emailTemplateService)
 
 	campaignRepo := storage.NewMongoCampaignRepository(mongoDB.Database)
@@ -154,6 +212,18 @@ func main() {
 		cfg.GoogleRedirectURL,
 		cfg.FrontendURL,
 	)
+	var twoFactorHandler *httpAdapter.TwoFactorHandler
+	if twoFactorService != nil {
+		twoFactorHandler = httpAdapter.NewTwoFactorHandler(authService, twoFactorService)
+	}
+	// Admin impersonation needs revocable sessions, so it is only available with Redis
+	var impersonationService *services.ImpersonationService
+	var impersonationHandler *httpAdapter.ImpersonationHandler
+	if sessionService != nil {
+		impersonationService = services.NewImpersonationService(sessionService, userRepo, auditService)
+		impersonationHandler = httpAdapter.NewImpersonationHandler(impersonationService)
+		authHandler.SetImpersonationService(impersonationService)
+	}
 	usernameHandler := httpAdapter.NewUsernameHandler(usernameService)
 	profileHandler := httpAdapter.NewProfileHandler(profileService)
 	productHandler := httpAdapter.NewProductHandler(productService)
@@ -162,8 +232,11 @@ func main() {
 	walletHandler := httpAdapter.NewWalletHandler(walletService)
 
 	adminService := services.NewAdminService(userRepo, transactionRepo, orderRepo, cache)
+	adminService.SetAuditService(auditService)
+	if sessionService != nil {
+		adminService.SetSessionService(sessionService)
+	}
 	adminHandler := httpAdapter.NewAdminHandler(adminService)
-	buyerHandler := httpAdapter.NewBuyerHandler(orderService, authService)
 	bookingHandler := httpAdapter.NewBookingHandler(bookingService)
 
 	// Initialize Payout Service (reuses Razorpay credentials)
@@ -177,6 +250,7 @@ func main() {
 		cfg.RazorpayKeyID,
 		cfg.RazorpayKeySecret,
 	)
+	payoutService.SetAuditService(auditService)
 	payoutHandler := httpAdapter.NewPayoutHandler(payoutService)
 
 	// Initialize Webhook Event Repository for immutable logging & resilience
@@ -190,13 +264,108 @@ func main() {
 	couponHandler := httpAdapter.NewCouponHandler(couponService)
 
 	courseService := services.NewCourseService(courseRepo, productRepo, orderRepo, userRepo, cache)
+	courseProgressRepo := storage.NewMongoCourseProgressRepository(mongoDB)
+	courseService.SetProgressRepo(courseProgressRepo)
+	courseService.SetNotificationDeps(emailAdapter, cfg.FrontendURL)
+	certificateRepo := storage.NewMongoCertificateRepository(mongoDB)
+	certificateService := services.NewCertificateService(certificateRepo, courseRepo, productRepo, userRepo, courseProgressRepo, fileStorage, cfg.FrontendURL)
+	courseService.AddCompletionHook(certificateService.IssueOnCompletion)
+	certificateHandler := httpAdapter.NewCertificateHandler(certificateService)
+	invoiceService := services.NewInvoiceService(
+		storage.NewMongoInvoiceRepository(mongoDB),
+		storage.NewMongoInvoiceSettingsRepository(mongoDB),
+		orderRepo,
+		userRepo,
+		fileStorage,
+	)
+	orderService.SetInvoiceService(invoiceService)
+	downloadEntitlementRepo := storage.NewMongoDownloadEntitlementRepository(mongoDB)
+	downloadLogRepo := storage.NewMongoDownloadLogRepository(mongoDB)
+	downloadService := services.NewDownloadService(downloadEntitlementRepo, downloadLogRepo, productRepo, orderRepo, userRepo, fileStorage, cfg.DownloadTokenSecret, cfg.APIBaseURL)
+	downloadService.SetEmailService(emailAdapter)
+	orderService.SetDownloadService(downloadService)
+	productService.SetDownloadService(downloadService)
+	downloadHandler := httpAdapter.NewDownloadHandler(downloadService)
+	videoService := services.NewVideoService(fileStorage, uploadRepo, courseService, cfg.DownloadTokenSecret, cfg.APIBaseURL)
+	if transcoder, err := ffmpeg.NewTranscoder(cfg.FFmpegPath, cfg.FFprobePath); err == nil {
+		videoService.SetTranscoder(transcoder)
+	} else {
+		logger.Warn("ffmpeg not found; lesson videos will be served as MP4 without HLS", "error", err.Error())
+	}
+	courseService.SetVideoService(videoService)
+	videoHandler := httpAdapter.NewVideoHandler(videoService)
 	courseHandler := httpAdapter.NewCourseHandler(courseService)
+	buyerHandler := httpAdapter.NewBuyerHandler(orderService, authService, courseService)
+
+	teamMemberRepo := storage.NewMongoTeamMemberRepository(mongoDB)
+	teamActivityRepo := storage.NewMongoTeamActivityRepository(mongoDB)
+	teamService := services.NewTeamService(teamMemberRepo, teamActivityRepo, userRepo, cfg.FrontendURL)
+	teamService.SetEmailService(emailAdapter)
+	teamHandler := httpAdapter.NewTeamHandler(teamService)
+
+	// Creator API keys for the public REST API
+	apiKeyService := services.NewAPIKeyService(storage.NewMongoAPIKeyRepository(mongoDB), userRepo)
+	apiKeyService.SetAuditService(auditService)
+	if rawRedisClient != nil {
+		apiKeyService.SetRedis(rawRedisClient)
+	}
+
+	// Outbound webhooks creators subscribe to their store events with
+	creatorWebhookService := services.NewCreatorWebhookService(
+		storage.NewMongoWebhookEndpointRepository(mongoDB),
+		storage.NewMongoWebhookDeliveryRepository(mongoDB),
+		userRepo,
+		cfg.FrontendURL,
+	)
+	creatorWebhookService.SetEmailService(emailAdapter)
+	orderService.SetWebhookService(creatorWebhookService)
+	bookingService.SetWebhookService(creatorWebhookService)
+	affiliateSvc.SetWebhookService(creatorWebhookService)
+	subscriberHandler := httpAdapter.NewSubscriberHandler(subscriberRepo)
+	subscriberHandler.SetWebhookService(creatorWebhookService)
+
+	storageGCService := services.NewStorageGCService(
+		fileStorage,
+		productRepo,
+		courseRepo,
+		testimonialRepo,
+		userRepo,
+		blogRepo,
+		orderRepo,
+		uploadRepo,
+		time.Duration(cfg.StorageGCGraceHours)*time.Hour,
+	)
+	adminHandler.SetStorageGCService(storageGCService)
+
+	// Self-service data export and account deletion
+	privacyService := services.NewPrivacyService(
+		userRepo,
+		storage.NewMongoDataExportRepository(mongoDB),
+		orderRepo,
+		subRepo,
+		bookingRepo,
+		courseProgressRepo,
+		subscriberRepo,
+		affiliateRepo,
+		affiliateSaleRepo,
+		platformSubRepo,
+		productRepo,
+		teamMemberRepo,
+		fileStorage,
+		cfg.FrontendURL,
+	)
+	privacyService.SetEmailService(emailAdapter)
+	privacyService.SetAuditService(auditService)
+	if sessionService != nil {
+		privacyService.SetSessionService(sessionService)
+	}
 
 	blogService := services.NewBlogService(blogRepo)
 	blogHandler := httpAdapter.NewBlogHandler(blogService)
 
 	// Platform Subscription
 	platformSubService := services.NewPlatformSubscriptionService(platformSubRepo, userRepo, platformReferralRepo, transactionRepo)
+	platformSubService.SetAuditService(auditService)
 	platformSubHandler := httpAdapter.NewPlatformSubscriptionHandler(platformSubService)
 
 	// Platform Referral
@@ -259,12 +428,26 @@ func main() {
 	)
 	gcalHandler := httpAdapter.NewGoogleCalendarHandler(gcalService, cfg.FrontendURL)
 	bookingService.SetGoogleCalendarService(gcalService)
+	bookingService.SetNotificationDeps(userRepo, emailAdapter)
+	bookingService.SetWorkerClient(workerService.GetClient())
+	bookingService.AddCompletionHook(services.BookingFollowUpHook(emailTemplateRepo, userRepo, emailAdapter))
 
 	// Inject Worker to dependent services
 	orderService.SetWorkerClient(workerService.GetClient())
 	orderService.SetFrontendURL(cfg.FrontendURL)
 	workerService.SetDependencies(orderService, emailAdapter, igConnRepo, igAutoRepo, analyticsService, analyticsDailyRepo, analyticsRepo)
 	workerService.SetInstagramDeliverService(igService)
+	workerService.SetBookingService(bookingService)
+	downloadService.SetWorkerClient(workerService.GetClient())
+	workerService.SetDownloadService(downloadService)
+	imageService.SetWorkerClient(workerService.GetClient())
+	workerService.SetImageService(imageService)
+	videoService.SetWorkerClient(workerService.GetClient())
+	workerService.SetVideoService(videoService)
+	privacyService.SetWorkerClient(workerService.GetClient())
+	workerService.SetPrivacyService(privacyService)
+	creatorWebhookService.SetWorkerClient(workerService.GetClient())
+	workerService.SetCreatorWebhookService(creatorWebhookService)
 	adminService.SetWorkerService(workerService)
 
 	// Initialize Cron Scheduling
@@ -278,6 +461,44 @@ func main() {
 	if err != nil {
 		logger.Fatal("Failed to set up cron jobs", "error", err.Error())
 	}
+	_, err = c.AddFunc("*/30 * * * *", func() {
+		// Safety net for completion tasks lost from the queue
+		if sweepErr := bookingService.CompleteElapsedBookings(context.Background()); sweepErr != nil {
+			logger.Error("Cron: Failed to complete elapsed bookings", "error", sweepErr.Error())
+		}
+	})
+	if err != nil {
+		logger.Error("Failed to set up booking completion cron job", "error", err.Error())
+	}
+	_, err = c.AddFunc("5 * * * *", func() {
+		if sweepErr := courseService.NotifyDripUnlocks(context.Background()); sweepErr != nil {
+			logger.Error("Cron: Failed to notify drip unlocks", "error", sweepErr.Error())
+		}
+	})
+	if err != nil {
+		logger.Error("Failed to set up drip unlock cron job", "error", err.Error())
+	}
+	_, err = c.AddFunc("0 3 * * *", func() { // Runs at 3 AM UTC
+		logger.Info("Cron: Sweeping orphaned uploads...")
+		if _, sweepErr := storageGCService.Sweep(context.Background(), false); sweepErr != nil {
+			logger.Error("Cron: Failed to sweep orphaned uploads", "error", sweepErr.Error())
+		}
+	})
+	if err != nil {
+		logger.Error("Failed to set up storage sweep cron job", "error", err.Error())
+	}
+	_, err = c.AddFunc("30 3 * * *", func() { // Runs at 3:30 AM UTC
+		logger.Info("Cron: Deleting accounts past their cooling-off period...")
+		if sweepErr := privacyService.ProcessDueDeletions(context.Background()); sweepErr != nil {
+			logger.Error("Cron: Failed to process account deletions", "error", sweepErr.Error())
+		}
+		if sweepErr := privacyService.PurgeExpiredExports(context.Background()); sweepErr != nil {
+			logger.Error("Cron: Failed to purge expired data exports", "error", sweepErr.Error())
+		}
+	})
+	if err != nil {
+		logger.Error("Failed to set up account deletion cron job", "error", err.Error())
+	}
 	_, err = c.AddFunc("0 1 * * *", func() { // Runs at 1 AM UTC
 		logger.Info("Cron: Queuing daily analytics aggregation...")
 		// Enqueue the task for yesterday
@@ -296,6 +517,7 @@ func main() {
 	app := fiber.New(fiber.Config{
 		AppName:               "Stan-store API v0.1.0",
 		DisableStartupMessage: true,
+		StreamRequestBody:     cfg.StorageDriver == "local", // Presigned PUTs of product files arrive through the API; see BodyLimit
 		ErrorHandler:          globalErrorHandler,
 	})
 
@@ -303,9 +525,17 @@ func main() {
 	httpAdapter.SetupRouter(app, &httpAdapter.RouterDeps{
 		FrontendURL:     cfg.FrontendURL,
 		JWTService:      jwtService,
+		SessionService:  sessionService,
 		UserRepo:        userRepo,
 		PlatformSubRepo: platformSubRepo,
 		AuthHandler:     authHandler,
+		TwoFactorHandler: twoFactorHandler,
+		TeamService:      teamService,
+		TeamHandler:      teamHandler,
+		APIKeyService:    apiKeyService,
+		APIKeyHandler:    httpAdapter.NewAPIKeyHandler(apiKeyService),
+		CreatorWebhookHandler: httpAdapter.NewCreatorWebhookHandler(creatorWebhookService),
+		InvoiceHandler:        httpAdapter.NewInvoiceHandler(invoiceService),
 		UsernameHandler: usernameHandler,
 		ProfileHandler:  profileHandler,
 
@@ -316,13 +546,20 @@ func main() {
 		OrderHandler:          orderHandler,
 		WalletHandler:         walletHandler,
 		AdminHandler:          adminHandler,
+		AuditHandler:          httpAdapter.NewAuditHandler(auditService),
+		ImpersonationService:  impersonationService,
+		ImpersonationHandler:  impersonationHandler,
+		PrivacyHandler:        httpAdapter.NewPrivacyHandler(privacyService),
 		BuyerHandler:          buyerHandler,
 		PayoutHandler:         payoutHandler,
-		SubscriberHandler:     httpAdapter.NewSubscriberHandler(subscriberRepo),
+		SubscriberHandler:     subscriberHandler,
 		NewsletterHandler:     httpAdapter.NewNewsletterHandler(subscriberRepo, emailAdapter),
 		CouponHandler:         couponHandler,
 		BookingHandler:        bookingHandler,
 		CourseHandler:         courseHandler,
+		CertificateHandler:    certificateHandler,
+		DownloadHandler:       downloadHandler,
+		VideoHandler:          videoHandler,
 		AIHandler:             aiHandler,
 		EmailTemplateHandler:  emailTemplateHandler,
 		CampaignHandler:       campaignHandler,
@@ -334,6 +571,7 @@ func main() {
 		BlogHandler:           blogHandler,
 		PlatformSubHandler:    platformSubHandler,
 		PlatformReferralHandler: platformReferralHandler,
+		LocalStorageHandler:     localStorageHandler,
 		WorkerService:         workerService,
 	})
 
@@ -1,9 +1,14 @@
 package email
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
+	"mime"
+	"mime/multipart"
 	"net/smtp"
+	"net/textproto"
 
 	"github.com/devanshbhargava/stan-store/internal/core/domain"
 )
@@ -26,7 +31,7 @@ func NewSMTPEmailAdapter(host, port, user, pass, fromAddr string) *SMTPEmailAdap
 	}
 }
 
-func (s *SMTPEmailAdapter) SendOrderConfirmation(ctx context.Context, order *domain.Order, product *domain.Product, downloadURL string) error {
+func (s *SMTPEmailAdapter) SendOrderConfirmation(ctx context.Context, order *domain.Order, product *domain.Product, downloadURL string, attachments ...domain.EmailAttachment) error {
 	addr := fmt.Sprintf("%s:%s", s.host, s.port)
 	auth := smtp.PlainAuth("", s.user, s.pass, s.host)
 
@@ -44,13 +49,10 @@ func (s *SMTPEmailAdapter) SendOrderConfirmation(ctx context.Context, order *dom
 		<p>Best,<br/>Mio Store Team</p>
 	`, order.CustomerName, product.Title, order.ID.Hex(), order.Currency, float64(order.Amount)/100, downloadURL)
 
-	msg := []byte(fmt.Sprintf("To: %s\r\n"+
-		"From: %s\r\n"+
-		"Subject: %s\r\n"+
-		"MIME-Version: 1.0\r\n"+
-		"Content-Type: text/html; charset=\"UTF-8\"\r\n"+
-		"\r\n"+
-		"%s", order.CustomerEmail, s.fromAddr, subject, body))
+	msg, err := buildMessage(order.CustomerEmail, s.fromAddr, subject, body, attachments)
+	if err != nil {
+		return fmt.Errorf("failed to build email: %w", err)
+	}
 
 	// In a real production app, we might use a worker queue or a more robust library.
 	// For MVP/Story 5.2, net/smtp is sufficient.
@@ -92,3 +94,52 @@ func (s *SMTPEmailAdapter) Send(ctx context.Context, recipient string, subject s
 
 	return nil
 }
+
+// buildMessage renders an HTML email, as multipart/mixed when there are attachments.
+func buildMessage(to, from, subject, body string, attachments []domain.EmailAttachment) ([]byte, error) {
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "To: %s\r\nFrom: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\n", to, from, subject)
+	if len(attachments) == 0 {
+		fmt.Fprintf(&msg, "Content-Type: text/html; charset=\"UTF-8\"\r\n\r\n%s", body)
+		return msg.Bytes(), nil
+	}
+
+	var parts bytes.Buffer
+	mw := multipart.NewWriter(&parts)
+	fmt.Fprintf(&msg, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", mw.Boundary())
+
+	html, err := mw.CreatePart(textproto.MIMEHeader{"Content-Type": {`text/html; charset="UTF-8"`}})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := html.Write([]byte(body)); err != nil {
+		return nil, err
+	}
+
+	for _, a := range attachments {
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {a.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		// Base64 lines must not exceed 76 characters
+		encoded := base64.StdEncoding.EncodeToString(a.Data)
+		for len(encoded) > 76 {
+			if _, err := part.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+				return nil, err
+			}
+			encoded = encoded[76:]
+		}
+		if _, err := part.Write([]byte(encoded + "\r\n")); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	msg.Write(parts.Bytes())
+	return msg.Bytes(), nil
+}
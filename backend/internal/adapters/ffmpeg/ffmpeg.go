@@ -0,0 +1,118 @@
+// Package ffmpeg transcodes lesson videos to HLS with the ffmpeg and ffprobe command-line tools.
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+)
+
+const (
+	// probeTimeout bounds a single ffprobe run.
+	probeTimeout = time.Minute
+	// transcodeTimeout bounds one rendition; long lessons at 1080p take a while.
+	transcodeTimeout = 90 * time.Minute
+	// segmentSeconds is the target HLS segment length.
+	segmentSeconds = 6
+)
+
+// Transcoder shells out to ffmpeg and ffprobe.
+type Transcoder struct {
+	ffmpeg  string
+	ffprobe string
+}
+
+// NewTranscoder resolves the ffmpeg and ffprobe executables, which may be names on PATH or
+// absolute paths. It fails if either tool is missing.
+func NewTranscoder(ffmpegPath, ffprobePath string) (*Transcoder, error) {
+	ffmpeg, err := exec.LookPath(ffmpegPath)
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg not found: %w", err)
+	}
+	ffprobe, err := exec.LookPath(ffprobePath)
+	if err != nil {
+		return nil, fmt.Errorf("ffprobe not found: %w", err)
+	}
+	return &Transcoder{ffmpeg: ffmpeg, ffprobe: ffprobe}, nil
+}
+
+// Probe reads the dimensions of the first video stream and the container duration.
+func (t *Transcoder) Probe(ctx context.Context, inputPath string) (*domain.VideoProbe, error) {
+	out, err := t.run(ctx, probeTimeout, t.ffprobe,
+		"-v", "error",
+		"-select_streams", "v:0",
+		"-show_entries", "stream=width,height:format=duration",
+		"-of", "json",
+		inputPath,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Streams []struct {
+			Width  int `json:"width"`
+			Height int `json:"height"`
+		} `json:"streams"`
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return nil, fmt.Errorf("invalid ffprobe output: %w", err)
+	}
+	if len(result.Streams) == 0 || result.Streams[0].Height == 0 {
+		return nil, fmt.Errorf("no video stream found")
+	}
+	duration, _ := strconv.ParseFloat(result.Format.Duration, 64)
+	return &domain.VideoProbe{
+		Width:           result.Streams[0].Width,
+		Height:          result.Streams[0].Height,
+		DurationSeconds: int(math.Ceil(duration)),
+	}, nil
+}
+
+// TranscodeHLS encodes one H.264/AAC rendition as a VOD HLS playlist with fixed-length segments.
+func (t *Transcoder) TranscodeHLS(ctx context.Context, inputPath string, outDir string, spec domain.HLSRenditionSpec) error {
+	_, err := t.run(ctx, transcodeTimeout, t.ffmpeg,
+		"-hide_banner", "-loglevel", "error", "-y",
+		"-i", inputPath,
+		"-map", "0:v:0", "-map", "0:a:0?",
+		"-vf", fmt.Sprintf("scale=-2:%d", spec.Height),
+		"-c:v", "libx264", "-preset", "veryfast", "-profile:v", "main",
+		"-b:v", strconv.Itoa(spec.VideoBitrate),
+		"-maxrate", strconv.Itoa(spec.VideoBitrate*107/100),
+		"-bufsize", strconv.Itoa(spec.VideoBitrate*3/2),
+		"-force_key_frames", fmt.Sprintf("expr:gte(t,n_forced*%d)", segmentSeconds),
+		"-c:a", "aac", "-b:a", strconv.Itoa(spec.AudioBitrate), "-ac", "2",
+		"-map_metadata", "-1",
+		"-f", "hls",
+		"-hls_time", strconv.Itoa(segmentSeconds),
+		"-hls_playlist_type", "vod",
+		"-hls_segment_filename", filepath.Join(outDir, spec.Name+"_%04d.ts"),
+		filepath.Join(outDir, spec.Name+".m3u8"),
+	)
+	return err
+}
+
+func (t *Transcoder) run(ctx context.Context, timeout time.Duration, tool string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, tool, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s failed: %w: %s", filepath.Base(tool), err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return stdout.Bytes(), nil
+}
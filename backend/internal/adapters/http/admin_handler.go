@@ -8,6 +8,7 @@ import (
 
 type AdminHandler struct {
 	adminService *services.AdminService
+	storageGC    *services.StorageGCService
 }
 
 func NewAdminHandler(adminService *services.AdminService) *AdminHandler {
@@ -16,6 +17,11 @@ func NewAdminHandler(adminService *services.AdminService) *AdminHandler {
 	}
 }
 
+// SetStorageGCService enables the orphaned file report and sweep endpoints.
+func (h *AdminHandler) SetStorageGCService(storageGC *services.StorageGCService) {
+	h.storageGC = storageGC
+}
+
 // GetMetrics returns the platform-wide metrics.
 // GET /api/v1/admin/metrics
 func (h *AdminHandler) GetMetrics(c *fiber.Ctx) error {
@@ -47,7 +53,7 @@ func (h *AdminHandler) BanCreator(c *fiber.Ctx) error {
 		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Ban reason is required", nil)
 	}
 
-	if err := h.adminService.BanCreator(c.Context(), creatorID, body.Reason); err != nil {
+	if err := h.adminService.BanCreator(c.Context(), auditActor(c), creatorID, body.Reason); err != nil {
 		if err.Error() == "user not found" {
 			return SendError(c, fiber.StatusNotFound, ErrNotFound, "Creator not found", nil)
 		}
@@ -68,7 +74,17 @@ func (h *AdminHandler) UnbanCreator(c *fiber.Ctx) error {
 		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Creator ID is required", nil)
 	}
 
-	if err := h.adminService.UnbanCreator(c.Context(), creatorID); err != nil {
+	// The reason is optional and only recorded in the audit log
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&body); err != nil {
+			return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
+		}
+	}
+
+	if err := h.adminService.UnbanCreator(c.Context(), auditActor(c), creatorID, body.Reason); err != nil {
 		if err.Error() == "user not found" {
 			return SendError(c, fiber.StatusNotFound, ErrNotFound, "Creator not found", nil)
 		}
@@ -113,3 +129,25 @@ func (h *AdminHandler) GetWebhookStats(c *fiber.Ctx) error {
 
 	return SendSuccess(c, fiber.StatusOK, stats, nil)
 }
+
+// GetOrphanedFiles reports uploads nothing references without deleting them.
+// GET /api/v1/admin/storage/orphans
+func (h *AdminHandler) GetOrphanedFiles(c *fiber.Ctx) error {
+	report, err := h.storageGC.Sweep(c.Context(), true)
+	if err != nil {
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to scan storage", err)
+	}
+
+	return SendSuccess(c, fiber.StatusOK, report, nil)
+}
+
+// SweepOrphanedFiles deletes unreferenced uploads older than the grace period.
+// POST /api/v1/admin/storage/orphans/sweep
+func (h *AdminHandler) SweepOrphanedFiles(c *fiber.Ctx) error {
+	report, err := h.storageGC.Sweep(c.Context(), false)
+	if err != nil {
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to sweep storage", err)
+	}
+
+	return SendSuccess(c, fiber.StatusOK, report, nil)
+}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/devanshbhargava/stan-store/internal/core/services"
+)
+
+// APIKeyHandler lets creators manage the API keys used by their scripts and integrations.
+type APIKeyHandler struct {
+	service *services.APIKeyService
+}
+
+// NewAPIKeyHandler creates a new APIKeyHandler.
+func NewAPIKeyHandler(service *services.APIKeyService) *APIKeyHandler {
+	return &APIKeyHandler{service: service}
+}
+
+// ListKeys returns the creator's API keys. Secrets are never returned.
+// GET /api/v1/creator/api-keys
+func (h *APIKeyHandler) ListKeys(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userId").(string)
+	keys, err := h.service.List(c.Context(), userID)
+	if err != nil {
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to fetch API keys", err)
+	}
+	return SendOK(c, keys)
+}
+
+// CreateKey mints a new API key. The secret is only included in this response.
+// POST /api/v1/creator/api-keys
+func (h *APIKeyHandler) CreateKey(c *fiber.Ctx) error {
+	var req struct {
+		Name      string   `json:"name"`
+		Scopes    []string `json:"scopes"`
+		RateLimit int      `json:"rate_limit"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
+	}
+
+	userID, _ := c.Locals("userId").(string)
+	key, secret, err := h.service.Create(c.Context(), auditActor(c), userID, req.Name, req.Scopes, req.RateLimit)
+	if err != nil {
+		if errors.Is(err, services.ErrAPIKeyLimitReached) {
+			return SendError(c, fiber.StatusConflict, ErrConflict, err.Error(), nil)
+		}
+		return SendError(c, fiber.StatusBadRequest, ErrValidation, err.Error(), nil)
+	}
+	return SendSuccess(c, fiber.StatusCreated, fiber.Map{"key": key, "secret": secret}, nil)
+}
+
+// RevokeKey revokes an API key. Requests using it fail immediately.
+// DELETE /api/v1/creator/api-keys/:id
+func (h *APIKeyHandler) RevokeKey(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userId").(string)
+	if err := h.service.Revoke(c.Context(), auditActor(c), userID, c.Params("id")); err != nil {
+		if errors.Is(err, services.ErrAPIKeyNotFound) {
+			return SendError(c, fiber.StatusNotFound, ErrNotFound, err.Error(), nil)
+		}
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to revoke API key", err)
+	}
+	return SendOK(c, map[string]bool{"revoked": true})
+}
@@ -0,0 +1,171 @@
+package http
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/internal/core/services"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+// AuditHandler serves the admin audit log.
+type AuditHandler struct {
+	auditService *services.AuditService
+}
+
+// NewAuditHandler creates a new AuditHandler.
+func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// ListAuditLog returns a page of audit entries, newest first.
+// GET /api/v1/admin/audit-log?actor_id=&action=&target_type=&target_id=&from=&to=&page=1&pageSize=50
+func (h *AuditHandler) ListAuditLog(c *fiber.Ctx) error {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, err.Error(), nil)
+	}
+
+	page, _ := strconv.ParseInt(c.Query("page", "1"), 10, 64)
+	pageSize, _ := strconv.ParseInt(c.Query("pageSize", "50"), 10, 64)
+
+	entries, meta, err := h.auditService.List(c.Context(), filter, &domain.Pagination{
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to fetch audit log", err)
+	}
+
+	return SendSuccess(c, fiber.StatusOK, entries, meta)
+}
+
+// ExportAuditLog downloads the matching audit entries as CSV. Takes the same filters as ListAuditLog.
+// GET /api/v1/admin/audit-log/export
+func (h *AuditHandler) ExportAuditLog(c *fiber.Ctx) error {
+	filter, err := parseAuditFilter(c)
+	if err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, err.Error(), nil)
+	}
+
+	// Streamed after the handler returns, so the request context can't be used
+	ctx := c.UserContext()
+	filename := fmt.Sprintf("audit-log-%s.csv", time.Now().UTC().Format("20060102-150405"))
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		out := csv.NewWriter(w)
+		_ = out.Write([]string{
+			"created_at", "actor_id", "actor_email", "actor_role", "action",
+			"target_type", "target_id", "reason", "changes", "request", "status", "ip", "user_agent", "request_id",
+		})
+
+		err := h.auditService.Export(ctx, filter, func(entry *domain.AuditEntry) error {
+			actorID := ""
+			if !entry.ActorID.IsZero() {
+				actorID = entry.ActorID.Hex()
+			}
+			changes := ""
+			if len(entry.Changes) > 0 {
+				raw, _ := json.Marshal(entry.Changes)
+				changes = string(raw)
+			}
+			status := ""
+			if entry.Status != 0 {
+				status = strconv.Itoa(entry.Status)
+			}
+			if err := out.Write([]string{
+				entry.CreatedAt.UTC().Format(time.RFC3339), actorID, entry.ActorEmail, entry.ActorRole, entry.Action,
+				entry.TargetType, entry.TargetID, entry.Reason, changes, entry.Request, status,
+				entry.IP, entry.UserAgent, entry.RequestID,
+			}); err != nil {
+				return err
+			}
+			out.Flush()
+			return out.Error()
+		})
+		if err != nil {
+			logger.Error("audit log export failed", "error", err)
+		}
+		out.Flush()
+	})
+	return nil
+}
+
+// parseAuditFilter reads the audit log filters from the query string. from and to accept
+// RFC 3339 timestamps or YYYY-MM-DD dates; a date in to includes that whole day.
+func parseAuditFilter(c *fiber.Ctx) (domain.AuditFilter, error) {
+	// Copied: the export reads the filter after the handler returns
+	filter := domain.AuditFilter{
+		Action:     utils.CopyString(c.Query("action")),
+		TargetType: utils.CopyString(c.Query("target_type")),
+		TargetID:   utils.CopyString(c.Query("target_id")),
+	}
+	if actorID := c.Query("actor_id"); actorID != "" {
+		oid, err := primitive.ObjectIDFromHex(actorID)
+		if err != nil {
+			return filter, fmt.Errorf("invalid actor_id")
+		}
+		filter.ActorID = oid
+	}
+
+	var err error
+	if filter.From, err = parseTimeQuery(c.Query("from"), false); err != nil {
+		return filter, fmt.Errorf("invalid from: use RFC 3339 or YYYY-MM-DD")
+	}
+	if filter.To, err = parseTimeQuery(c.Query("to"), true); err != nil {
+		return filter, fmt.Errorf("invalid to: use RFC 3339 or YYYY-MM-DD")
+	}
+	return filter, nil
+}
+
+// parseTimeQuery parses an RFC 3339 timestamp or a YYYY-MM-DD date. With endOfDay a date
+// becomes the start of the next day, for use as an exclusive upper bound.
+func parseTimeQuery(value string, endOfDay bool) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// auditActor describes the signed-in user making the request, for the audit log.
+// Team members acting on a creator's account, and admins impersonating one, are recorded
+// as themselves.
+func auditActor(c *fiber.Ctx) services.AuditActor {
+	userID, _ := c.Locals("userId").(string)
+	role, _ := c.Locals("role").(string)
+	if actorID, _ := c.Locals("actorId").(string); actorID != "" && actorID != userID {
+		userID, role = actorID, "team_member"
+	}
+	if adminID, _ := c.Locals("impersonatorId").(string); adminID != "" {
+		userID, role = adminID, domain.RoleAdmin
+	}
+	requestID, _ := c.Locals("request_id").(string)
+	return services.AuditActor{
+		UserID:    userID,
+		Role:      role,
+		IP:        c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		RequestID: requestID,
+	}
+}
@@ -3,7 +3,9 @@ package http
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
+	"net/url"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -16,16 +18,23 @@ import (
 
 const (
 	cookieName        = "stan_token"
+	refreshCookieName = "stan_refresh"
+	refreshCookiePath = "/api/v1/auth"   // The refresh token is only sent to auth endpoints
 	cookieMaxAge      = 7 * 24 * 60 * 60 // 7 days in seconds (creators)
 	buyerCookieMaxAge = 24 * 60 * 60     // 24 hours in seconds (buyers)
 	googleUserURL     = "https://www.googleapis.com/oauth2/v2/userinfo"
+
+	magicLinkNonceCookie = "stan_ml_nonce"
+	magicLinkNoncePath   = "/api/v1/auth/buyer"
+	magicLinkNonceMaxAge = 15 * 60 // Matches the magic link lifetime
 )
 
 // AuthHandler handles authentication HTTP endpoints.
 type AuthHandler struct {
-	authService *services.AuthService
-	oauthConfig *oauth2.Config
-	frontendURL string
+	authService   *services.AuthService
+	oauthConfig   *oauth2.Config
+	frontendURL   string
+	impersonation *services.ImpersonationService
 }
 
 // NewAuthHandler creates a new AuthHandler.
@@ -45,6 +54,11 @@ func NewAuthHandler(authService *services.AuthService, clientID, clientSecret, r
 	}
 }
 
+// SetImpersonationService lets /auth/me report an active impersonation session.
+func (h *AuthHandler) SetImpersonationService(impersonation *services.ImpersonationService) {
+	h.impersonation = impersonation
+}
+
 // GoogleLogin redirects the user to Google's consent screen for creators.
 // GET /api/v1/auth/google
 func (h *AuthHandler) GoogleLogin(c *fiber.Ctx) error {
@@ -109,26 +123,23 @@ func (h *AuthHandler) GoogleCallback(c *fiber.Ctx) error {
 	}
 
 	// Process auth (find or create user, generate JWT)
-	result, err := h.authService.HandleGoogleCallback(ctx, &gUser, requestedRole)
+	result, err := h.authService.HandleGoogleCallback(ctx, &gUser, requestedRole, clientInfo(c))
 	if err != nil {
 		logger.Error("auth callback failed", "error", err.Error(), "email", gUser.Email)
 		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Authentication failed", nil)
 	}
 
+	// Accounts with 2FA finish signing in on the frontend with the challenge token
+	if result.TwoFactorRequired {
+		return c.Redirect(h.frontendURL+twoFactorLoginPath(result), fiber.StatusTemporaryRedirect)
+	}
+
 	// Set JWT as HTTP-Only cookie (buyer gets 24h, creator gets 7d)
 	maxAge := cookieMaxAge
 	if result.User != nil && result.User.Role == "buyer" {
 		maxAge = buyerCookieMaxAge
 	}
-	c.Cookie(&fiber.Cookie{
-		Name:     cookieName,
-		Value:    result.Token,
-		MaxAge:   maxAge,
-		HTTPOnly: true,
-		Secure:   true,
-		SameSite: "Strict",
-		Path:     "/",
-	})
+	setAuthCookies(c, result, maxAge)
 
 	// Determine where to redirect
 	finalPath := result.RedirectURL // Default from service (handles onboarding check)
@@ -157,23 +168,178 @@ func (h *AuthHandler) GetMe(c *fiber.Ctx) error {
 		return SendError(c, fiber.StatusNotFound, ErrNotFound, "User not found", nil)
 	}
 
+	// Flag impersonation so the frontend shows a banner
+	if adminID, _ := c.Locals("impersonatorId").(string); adminID != "" && h.impersonation != nil {
+		sessionID, _ := c.Locals("sessionId").(string)
+		imp, err := h.impersonation.Get(c.Context(), sessionID)
+		if err != nil {
+			return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to load impersonation session", err)
+		}
+		user.Impersonation = imp
+	}
+
 	return SendSuccess(c, fiber.StatusOK, user, nil)
 }
 
-// Logout clears the authentication cookie.
+// Logout ends the current session and clears the authentication cookies. It works with an
+// expired access token as long as the refresh token is still presented.
 // POST /api/v1/auth/logout
 func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	if err := h.authService.Logout(c.Context(), extractAccessToken(c), extractRefreshToken(c)); err != nil {
+		logger.Error("failed to revoke session on logout", "error", err)
+	}
+	clearAuthCookies(c)
+
+	return SendSuccess(c, fiber.StatusOK, map[string]string{"message": "Logged out successfully"}, nil)
+}
+
+// Refresh exchanges a refresh token for a new access token, rotating the refresh token.
+// POST /api/v1/auth/refresh
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	refreshToken := extractRefreshToken(c)
+	if refreshToken == "" {
+		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Refresh token required", nil)
+	}
+
+	result, err := h.authService.RefreshSession(c.Context(), refreshToken, clientInfo(c))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRefreshToken) || errors.Is(err, services.ErrRefreshTokenReused) || errors.Is(err, services.ErrSessionRevoked) {
+			clearAuthCookies(c)
+			return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, err.Error(), nil)
+		}
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to refresh session", err)
+	}
+
+	maxAge := cookieMaxAge
+	if result.User.Role == "buyer" {
+		maxAge = buyerCookieMaxAge
+	}
+	setAuthCookies(c, result, maxAge)
+
+	body := map[string]interface{}{
+		"token":     result.Token,
+		"expiresIn": int(services.AccessTokenExpiry.Seconds()),
+	}
+	if wantsRefreshToken(c) {
+		body["refreshToken"] = result.RefreshToken
+	}
+	return SendSuccess(c, fiber.StatusOK, body, nil)
+}
+
+// GetSessions lists the devices the user is signed in on.
+// GET /api/v1/auth/sessions
+func (h *AuthHandler) GetSessions(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userId").(string)
+	sessionID, _ := c.Locals("sessionId").(string)
+
+	sessions, err := h.authService.ListSessions(c.Context(), userID, sessionID)
+	if err != nil {
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to list sessions", err)
+	}
+	return SendSuccess(c, fiber.StatusOK, sessions, nil)
+}
+
+// RevokeSession signs the user out of one device.
+// DELETE /api/v1/auth/sessions/:id
+func (h *AuthHandler) RevokeSession(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userId").(string)
+	sessionID, _ := c.Locals("sessionId").(string)
+
+	if err := h.authService.RevokeSession(c.Context(), userID, c.Params("id")); err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			return SendError(c, fiber.StatusNotFound, ErrNotFound, "Session not found", nil)
+		}
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to revoke session", err)
+	}
+	if c.Params("id") == sessionID {
+		clearAuthCookies(c)
+	}
+	return SendSuccess(c, fiber.StatusOK, map[string]string{"message": "Session revoked"}, nil)
+}
+
+// RevokeAllSessions signs the user out on every device, including this one.
+// DELETE /api/v1/auth/sessions
+func (h *AuthHandler) RevokeAllSessions(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userId").(string)
+
+	n, err := h.authService.RevokeAllSessions(c.Context(), userID)
+	if err != nil {
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to revoke sessions", err)
+	}
+	clearAuthCookies(c)
+	return SendSuccess(c, fiber.StatusOK, map[string]int{"revoked": n}, nil)
+}
+
+// setAuthCookies stores the access token, and the refresh token when sessions are enabled,
+// as HTTP-Only cookies. Nothing is set while a two-factor challenge is pending.
+func setAuthCookies(c *fiber.Ctx, result *services.AuthResult, maxAge int) {
+	if result.Token == "" {
+		return
+	}
 	c.Cookie(&fiber.Cookie{
 		Name:     cookieName,
-		Value:    "",
-		MaxAge:   -1,
+		Value:    result.Token,
+		MaxAge:   maxAge,
 		HTTPOnly: true,
 		Secure:   true,
 		SameSite: "Strict",
 		Path:     "/",
 	})
+	if result.RefreshToken != "" {
+		c.Cookie(&fiber.Cookie{
+			Name:     refreshCookieName,
+			Value:    result.RefreshToken,
+			MaxAge:   maxAge,
+			HTTPOnly: true,
+			Secure:   true,
+			SameSite: "Strict",
+			Path:     refreshCookiePath,
+		})
+	}
+}
 
-	return SendSuccess(c, fiber.StatusOK, map[string]string{"message": "Logged out successfully"}, nil)
+// refreshTokenHeader is sent by non-browser clients that can't hold the refresh cookie and
+// need the refresh token in the response body instead.
+const refreshTokenHeader = "X-Refresh-Token-Delivery"
+
+func wantsRefreshToken(c *fiber.Ctx) bool {
+	return c.Get(refreshTokenHeader) == "body"
+}
+
+// authResponse is the body for a completed login. The refresh token stays in its HTTP-Only
+// cookie unless the client asked for it with refreshTokenHeader.
+func authResponse(c *fiber.Ctx, result *services.AuthResult) interface{} {
+	if result.RefreshToken == "" || !wantsRefreshToken(c) {
+		return result
+	}
+	return struct {
+		*services.AuthResult
+		RefreshToken string `json:"refreshToken"`
+	}{result, result.RefreshToken}
+}
+
+// twoFactorLoginPath is the frontend page that collects the second factor after a redirect
+// based login (Google, magic link).
+func twoFactorLoginPath(result *services.AuthResult) string {
+	step := "verify"
+	if result.TwoFactorSetupRequired {
+		step = "setup"
+	}
+	return "/login?twoFactor=" + step + "&challenge=" + url.QueryEscape(result.ChallengeToken)
+}
+
+func clearAuthCookies(c *fiber.Ctx) {
+	for name, path := range map[string]string{cookieName: "/", refreshCookieName: refreshCookiePath} {
+		c.Cookie(&fiber.Cookie{
+			Name:     name,
+			Value:    "",
+			MaxAge:   -1,
+			HTTPOnly: true,
+			Secure:   true,
+			SameSite: "Strict",
+			Path:     path,
+		})
+	}
 }
 
 // extractTokenFromCookie is a helper used by auth middleware.
@@ -181,6 +347,38 @@ func extractTokenFromCookie(c *fiber.Ctx) string {
 	return c.Cookies(cookieName)
 }
 
+// extractAccessToken reads the access token from the cookie or the Authorization header.
+func extractAccessToken(c *fiber.Ctx) string {
+	if token := extractTokenFromCookie(c); token != "" {
+		return token
+	}
+	authHeader := c.Get("Authorization")
+	if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
+		return authHeader[7:]
+	}
+	return ""
+}
+
+// extractRefreshToken reads the refresh token from its cookie or, for non-browser clients,
+// the JSON body.
+func extractRefreshToken(c *fiber.Ctx) string {
+	if token := c.Cookies(refreshCookieName); token != "" {
+		return token
+	}
+	var req struct {
+		RefreshToken string `json:"refreshToken"`
+	}
+	if len(c.Body()) > 0 {
+		_ = c.BodyParser(&req)
+	}
+	return req.RefreshToken
+}
+
+// clientInfo describes the device making the request, for the session list.
+func clientInfo(c *fiber.Ctx) services.ClientInfo {
+	return services.ClientInfo{UserAgent: c.Get(fiber.HeaderUserAgent), IP: c.IP()}
+}
+
 // BuyerMagicLinkRequest handles sending a magic link to a buyer.
 // POST /api/v1/auth/buyer/magic-link
 func (h *AuthHandler) BuyerMagicLinkRequest(c *fiber.Ctx) error {
@@ -196,12 +394,27 @@ func (h *AuthHandler) BuyerMagicLinkRequest(c *fiber.Ctx) error {
 		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Email is required", nil)
 	}
 
-	err := h.authService.HandleMagicLinkRequest(c.Context(), req.Email)
+	nonce, err := h.authService.HandleMagicLinkRequest(c.Context(), req.Email, c.IP())
+	if errors.Is(err, services.ErrMagicLinkRateLimited) {
+		return SendError(c, fiber.StatusTooManyRequests, ErrTooManyRequests, err.Error(), nil)
+	}
 	if err != nil {
 		logger.Error("magic link request failed", "error", err, "email", req.Email)
 		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to send magic link", nil)
 	}
 
+	// The link only works in the browser holding this nonce. Lax so it is sent when the link
+	// is opened from an email client.
+	c.Cookie(&fiber.Cookie{
+		Name:     magicLinkNonceCookie,
+		Value:    nonce,
+		MaxAge:   magicLinkNonceMaxAge,
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: "Lax",
+		Path:     magicLinkNoncePath,
+	})
+
 	return SendSuccess(c, fiber.StatusOK, map[string]string{"message": "If that email is valid, a magic link has been sent."}, nil)
 }
 
@@ -213,24 +426,33 @@ func (h *AuthHandler) BuyerMagicLinkVerify(c *fiber.Ctx) error {
 		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Token is required", nil)
 	}
 
-	result, err := h.authService.HandleMagicLinkVerify(c.Context(), token)
+	result, err := h.authService.HandleMagicLinkVerify(c.Context(), token, c.Cookies(magicLinkNonceCookie), clientInfo(c))
+	if errors.Is(err, services.ErrMagicLinkWrongBrowser) {
+		return c.Redirect(h.frontendURL+"/buyer/login?error=different_browser", fiber.StatusTemporaryRedirect)
+	}
 	if err != nil {
-		logger.Error("magic link verify failed", "error", err, "token", token)
+		logger.Error("magic link verify failed", "error", err)
 		// Redirect to frontend login with an error so they can try again instead of dropping them on a blank JSON screen
-		return c.Redirect(h.frontendURL+"/login?error=invalid_token", fiber.StatusTemporaryRedirect)
+		return c.Redirect(h.frontendURL+"/buyer/login?error=invalid_token", fiber.StatusTemporaryRedirect)
 	}
 
-	// Set JWT as HTTP-Only cookie (buyers get 24h expiry)
 	c.Cookie(&fiber.Cookie{
-		Name:     cookieName,
-		Value:    result.Token,
-		MaxAge:   buyerCookieMaxAge,
+		Name:     magicLinkNonceCookie,
+		Value:    "",
+		MaxAge:   -1,
 		HTTPOnly: true,
 		Secure:   true,
-		SameSite: "Strict",
-		Path:     "/",
+		SameSite: "Lax",
+		Path:     magicLinkNoncePath,
 	})
 
+	if result.TwoFactorRequired {
+		return c.Redirect(h.frontendURL+twoFactorLoginPath(result), fiber.StatusTemporaryRedirect)
+	}
+
+	// Set JWT as HTTP-Only cookie (buyers get 24h expiry)
+	setAuthCookies(c, result, buyerCookieMaxAge)
+
 	// Redirect to the frontend dashboard for buyers
 	return c.Redirect(h.frontendURL+"/my-purchases", fiber.StatusTemporaryRedirect)
 }
@@ -252,6 +474,9 @@ func (h *AuthHandler) CreatorSignup(c *fiber.Ctx) error {
 	}
 
 	err := h.authService.HandleCreatorSignup(c.Context(), req.Email, req.Password)
+	if errors.Is(err, services.ErrTooManyOTPAttempts) {
+		return SendError(c, fiber.StatusTooManyRequests, ErrTooManyRequests, err.Error(), nil)
+	}
 	if err != nil {
 		logger.Error("creator signup failed", "error", err, "email", req.Email)
 		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, err.Error(), nil)
@@ -278,24 +503,116 @@ func (h *AuthHandler) CreatorVerifyOTP(c *fiber.Ctx) error {
 		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Email and OTP are required", nil)
 	}
 
-	result, err := h.authService.HandleCreatorVerifyOTP(c.Context(), req.Email, req.OTP)
+	result, err := h.authService.HandleCreatorVerifyOTP(c.Context(), req.Email, req.OTP, clientInfo(c))
+	if errors.Is(err, services.ErrTooManyOTPAttempts) {
+		return SendError(c, fiber.StatusTooManyRequests, ErrTooManyRequests, err.Error(), nil)
+	}
 	if err != nil {
 		logger.Error("OTP verification failed", "error", err, "email", req.Email)
 		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, err.Error(), nil)
 	}
 
 	// Set JWT as HTTP-Only cookie
-	c.Cookie(&fiber.Cookie{
-		Name:     cookieName,
-		Value:    result.Token,
-		MaxAge:   cookieMaxAge,
-		HTTPOnly: true,
-		Secure:   true,
-		SameSite: "Strict",
-		Path:     "/",
-	})
+	setAuthCookies(c, result, cookieMaxAge)
+
+	return SendSuccess(c, fiber.StatusOK, authResponse(c, result), nil)
+}
+
+// CreatorResendOTP sends a new verification code for a pending signup.
+// POST /api/v1/auth/creator/resend-otp
+func (h *AuthHandler) CreatorResendOTP(c *fiber.Ctx) error {
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.Email == "" {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Email is required", nil)
+	}
 
-	return SendSuccess(c, fiber.StatusOK, result, nil)
+	err := h.authService.HandleCreatorResendOTP(c.Context(), req.Email)
+	if errors.Is(err, services.ErrTooManyOTPAttempts) {
+		return SendError(c, fiber.StatusTooManyRequests, ErrTooManyRequests, err.Error(), nil)
+	}
+	if err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, err.Error(), nil)
+	}
+	return SendSuccess(c, fiber.StatusOK, map[string]string{"message": "A new code has been sent to your email."}, nil)
+}
+
+// ForgotPassword emails a password reset link.
+// POST /api/v1/auth/password/forgot
+func (h *AuthHandler) ForgotPassword(c *fiber.Ctx) error {
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.Email == "" {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Email is required", nil)
+	}
+
+	err := h.authService.RequestPasswordReset(c.Context(), req.Email, c.IP())
+	if errors.Is(err, services.ErrPasswordResetRateLimited) {
+		return SendError(c, fiber.StatusTooManyRequests, ErrTooManyRequests, err.Error(), nil)
+	}
+	if err != nil {
+		logger.Error("password reset request failed", "error", err, "email", req.Email)
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to send reset link", nil)
+	}
+
+	return SendSuccess(c, fiber.StatusOK, map[string]string{"message": "If an account exists for that email, a reset link has been sent."}, nil)
+}
+
+// ResetPassword sets a new password from a reset link. All sessions are signed out.
+// POST /api/v1/auth/password/reset
+func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
+	var req struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
+	}
+	if req.Token == "" || req.Password == "" {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Token and password are required", nil)
+	}
+
+	if err := h.authService.ResetPassword(c.Context(), req.Token, req.Password); err != nil {
+		return sendPasswordError(c, err)
+	}
+	return SendSuccess(c, fiber.StatusOK, map[string]string{"message": "Your password has been reset. Please sign in."}, nil)
+}
+
+// ChangePassword replaces the signed-in user's password. Other devices are signed out.
+// POST /api/v1/auth/password/change
+func (h *AuthHandler) ChangePassword(c *fiber.Ctx) error {
+	var req struct {
+		CurrentPassword string `json:"currentPassword"`
+		NewPassword     string `json:"newPassword"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
+	}
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Current and new password are required", nil)
+	}
+
+	userID, _ := c.Locals("userId").(string)
+	sessionID, _ := c.Locals("sessionId").(string)
+	if err := h.authService.ChangePassword(c.Context(), userID, sessionID, req.CurrentPassword, req.NewPassword); err != nil {
+		return sendPasswordError(c, err)
+	}
+	return SendSuccess(c, fiber.StatusOK, map[string]string{"message": "Your password has been changed."}, nil)
+}
+
+func sendPasswordError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrWeakPassword), errors.Is(err, services.ErrInvalidResetToken),
+		errors.Is(err, services.ErrSamePassword), errors.Is(err, services.ErrNoPassword):
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, err.Error(), nil)
+	case errors.Is(err, services.ErrIncorrectPassword):
+		// Not 401: that would make the client try to refresh the session
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Current password is incorrect", nil)
+	}
+	logger.Error("password update failed", "error", err)
+	return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to update password", nil)
 }
 
 // CreatorLogin handles email/password login for creators.
@@ -314,22 +631,14 @@ func (h *AuthHandler) CreatorLogin(c *fiber.Ctx) error {
 		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Email and password are required", nil)
 	}
 
-	result, err := h.authService.HandleCreatorLogin(c.Context(), req.Email, req.Password)
+	result, err := h.authService.HandleCreatorLogin(c.Context(), req.Email, req.Password, clientInfo(c))
 	if err != nil {
 		logger.Error("creator login failed", "error", err, "email", req.Email)
 		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, err.Error(), nil)
 	}
 
 	// Set JWT as HTTP-Only cookie
-	c.Cookie(&fiber.Cookie{
-		Name:     cookieName,
-		Value:    result.Token,
-		MaxAge:   cookieMaxAge,
-		HTTPOnly: true,
-		Secure:   true,
-		SameSite: "Strict",
-		Path:     "/",
-	})
+	setAuthCookies(c, result, cookieMaxAge)
 
-	return SendSuccess(c, fiber.StatusOK, result, nil)
+	return SendSuccess(c, fiber.StatusOK, authResponse(c, result), nil)
 }
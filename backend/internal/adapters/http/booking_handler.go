@@ -22,7 +22,8 @@ func NewBookingHandler(bookingService *services.BookingService) *BookingHandler
 	}
 }
 
-// GetSlots returns available slots for a product on a given date
+// GetSlots returns available slots for a product on a given date.
+// Pass ?with_seats=true to receive each slot's capacity and remaining seats instead of bare timestamps.
 func (h *BookingHandler) GetSlots(c *fiber.Ctx) error {
 	productIDStr := c.Params("id")
 	dateStr := c.Query("date") // Format: YYYY-MM-DD
@@ -36,13 +37,17 @@ func (h *BookingHandler) GetSlots(c *fiber.Ctx) error {
 		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid product ID", nil)
 	}
 
+	if c.QueryBool("with_seats") {
+		availability, err := h.bookingService.GetSlotAvailability(c.Context(), productID, dateStr)
+		if err != nil {
+			return h.slotsError(c, err)
+		}
+		return SendOK(c, availability)
+	}
+
 	slots, err := h.bookingService.GetAvailableSlots(c.Context(), productID, dateStr)
 	if err != nil {
-		logger.Error("failed to get available slots", "error", err)
-		if err.Error() == "product not found" || err.Error() == "product is not a booking product" {
-			return SendError(c, fiber.StatusNotFound, ErrNotFound, err.Error(), nil)
-		}
-		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to get availability", nil)
+		return h.slotsError(c, err)
 	}
 
 	// Format slots to ISO8601 strings
@@ -58,6 +63,15 @@ func (h *BookingHandler) GetSlots(c *fiber.Ctx) error {
 	return SendOK(c, formattedSlots)
 }
 
+// slotsError maps availability lookup failures to HTTP responses.
+func (h *BookingHandler) slotsError(c *fiber.Ctx, err error) error {
+	logger.Error("failed to get available slots", "error", err)
+	if err.Error() == "product not found" || err.Error() == "product is not a booking product" {
+		return SendError(c, fiber.StatusNotFound, ErrNotFound, err.Error(), nil)
+	}
+	return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to get availability", nil)
+}
+
 // CancelBooking allows a user to cancel their booking if policy allows
 func (h *BookingHandler) CancelBooking(c *fiber.Ctx) error {
 	bookingIDStr := c.Params("id")
@@ -92,3 +106,72 @@ func (h *BookingHandler) CancelBooking(c *fiber.Ctx) error {
 
 	return SendOK(c, map[string]string{"message": "Booking cancelled successfully"})
 }
+
+// MarkNoShow allows the creator to record that the buyer missed the session
+// POST /api/v1/bookings/:id/no-show
+func (h *BookingHandler) MarkNoShow(c *fiber.Ctx) error {
+	bookingIDStr := c.Params("id")
+	bookingID, err := primitive.ObjectIDFromHex(bookingIDStr)
+	if err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid booking ID", nil)
+	}
+
+	userIDStr, ok := c.Locals("userId").(string)
+	if !ok || userIDStr == "" {
+		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Authentication required", nil)
+	}
+	creatorID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Invalid user ID", nil)
+	}
+
+	if err := h.bookingService.MarkNoShow(c.Context(), bookingID, creatorID); err != nil {
+		logger.Error("failed to mark booking as no-show", "error", err, "booking_id", bookingIDStr)
+		switch err.Error() {
+		case "booking not found":
+			return SendError(c, fiber.StatusNotFound, ErrNotFound, "Booking not found", nil)
+		case "unauthorized to update this booking":
+			return SendError(c, fiber.StatusForbidden, ErrForbidden, err.Error(), nil)
+		}
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, err.Error(), nil)
+	}
+
+	return SendOK(c, map[string]string{"message": "Booking marked as no-show"})
+}
+
+// GetSlotAttendees returns the attendee list for one session of a group booking product
+// GET /api/v1/sales/attendees/:id?start=RFC3339 (:id is the product)
+func (h *BookingHandler) GetSlotAttendees(c *fiber.Ctx) error {
+	productID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid product ID", nil)
+	}
+
+	slotStart, err := time.Parse(time.RFC3339, c.Query("start"))
+	if err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "start must be an RFC3339 timestamp", nil)
+	}
+
+	userIDStr, ok := c.Locals("userId").(string)
+	if !ok || userIDStr == "" {
+		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Authentication required", nil)
+	}
+	creatorID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Invalid user ID", nil)
+	}
+
+	attendees, err := h.bookingService.GetSlotAttendees(c.Context(), productID, creatorID, slotStart)
+	if err != nil {
+		logger.Error("failed to get slot attendees", "error", err, "product_id", productID.Hex())
+		switch err.Error() {
+		case "product not found":
+			return SendError(c, fiber.StatusNotFound, ErrNotFound, "Product not found", nil)
+		case "unauthorized to view this session":
+			return SendError(c, fiber.StatusForbidden, ErrForbidden, err.Error(), nil)
+		}
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to get attendees", nil)
+	}
+
+	return SendOK(c, attendees)
+}
@@ -8,18 +8,22 @@ import (
 )
 
 type BuyerHandler struct {
-	orderService *services.OrderService
-	authService  *services.AuthService
+	orderService  *services.OrderService
+	authService   *services.AuthService
+	courseService *services.CourseService
 }
 
-func NewBuyerHandler(orderService *services.OrderService, authService *services.AuthService) *BuyerHandler {
+func NewBuyerHandler(orderService *services.OrderService, authService *services.AuthService, courseService *services.CourseService) *BuyerHandler {
 	return &BuyerHandler{
-		orderService: orderService,
-		authService:  authService,
+		orderService:  orderService,
+		authService:   authService,
+		courseService: courseService,
 	}
 }
 
 // GetPurchases handles GET /api/v1/buyer/purchases
+// meta.course_progress maps course product IDs to the buyer's completion percentage, and
+// meta.product_files maps product IDs to the latest version of each of their files.
 func (h *BuyerHandler) GetPurchases(c *fiber.Ctx) error {
 	// The auth middleware guarantees a user ID in locals
 	userIDStr, ok := c.Locals("userId").(string)
@@ -40,7 +44,24 @@ func (h *BuyerHandler) GetPurchases(c *fiber.Ctx) error {
 		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to fetch purchases", nil)
 	}
 
-	return SendOK(c, orders)
+	courseProgress := map[string]int{}
+	if h.courseService != nil {
+		if buyerID, err := primitive.ObjectIDFromHex(userIDStr); err == nil {
+			if progress, err := h.courseService.GetBuyerCourseProgress(c.Context(), buyerID); err == nil {
+				courseProgress = progress
+			} else {
+				logger.Error("buyer course progress fetch failed", "error", err, "userId", userIDStr)
+			}
+		}
+	}
+
+	productFiles, err := h.orderService.GetPurchasedFiles(c.Context(), orders)
+	if err != nil {
+		logger.Error("buyer product files fetch failed", "error", err, "email", user.Email)
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to fetch purchases", nil)
+	}
+
+	return SendSuccess(c, fiber.StatusOK, orders, fiber.Map{"course_progress": courseProgress, "product_files": productFiles})
 }
 
 // GetSubscriptions handles GET /api/v1/buyer/subscriptions
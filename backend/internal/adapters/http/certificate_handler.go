@@ -0,0 +1,106 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/internal/core/services"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+// CertificateHandler handles HTTP requests for course completion certificates.
+type CertificateHandler struct {
+	service *services.CertificateService
+}
+
+// NewCertificateHandler creates a new CertificateHandler.
+func NewCertificateHandler(service *services.CertificateService) *CertificateHandler {
+	return &CertificateHandler{service: service}
+}
+
+// UpdateTemplate saves the certificate template for a course
+// PUT /api/v1/products/:id/course/certificate
+func (h *CertificateHandler) UpdateTemplate(c *fiber.Ctx) error {
+	productID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid product ID", nil)
+	}
+
+	var req domain.CertificateTemplate
+	if err := c.BodyParser(&req); err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
+	}
+
+	creatorID, err := primitive.ObjectIDFromHex(c.Locals("userId").(string))
+	if err != nil {
+		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Invalid user ID", nil)
+	}
+
+	course, err := h.service.UpdateTemplate(c.Context(), productID, creatorID, &req)
+	if err != nil {
+		switch err.Error() {
+		case "unauthorized access to product":
+			return SendError(c, fiber.StatusForbidden, ErrForbidden, "Unauthorized", nil)
+		case "course not found":
+			return SendError(c, fiber.StatusNotFound, ErrNotFound, "Course not found", nil)
+		}
+		return SendError(c, fiber.StatusBadRequest, ErrValidation, err.Error(), nil)
+	}
+
+	return SendOK(c, course.Certificate)
+}
+
+// GetMyCertificate returns the buyer's certificate for a completed course with a download link
+// GET /api/v1/buyer/courses/:id/certificate
+func (h *CertificateHandler) GetMyCertificate(c *fiber.Ctx) error {
+	productID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid product ID", nil)
+	}
+
+	userIDStr, ok := c.Locals("userId").(string)
+	if !ok || userIDStr == "" {
+		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "User ID not found in context", nil)
+	}
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Invalid user ID", nil)
+	}
+
+	cert, url, err := h.service.GetDownloadURL(c.Context(), productID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrCertificatesDisabled):
+			return SendError(c, fiber.StatusNotFound, ErrNotFound, err.Error(), nil)
+		case errors.Is(err, services.ErrCourseIncomplete):
+			return SendError(c, fiber.StatusForbidden, ErrForbidden, err.Error(), nil)
+		case err.Error() == "course not found":
+			return SendError(c, fiber.StatusNotFound, ErrNotFound, "Course not found", nil)
+		}
+		logger.Error("failed to issue certificate", "error", err, "product_id", productID.Hex())
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to generate certificate", nil)
+	}
+
+	return SendOK(c, fiber.Map{
+		"certificate":  cert,
+		"download_url": url,
+	})
+}
+
+// VerifyCertificate is the public lookup behind the certificate verification page
+// GET /api/v1/certificates/:verificationId
+func (h *CertificateHandler) VerifyCertificate(c *fiber.Ctx) error {
+	verification, err := h.service.Verify(c.Context(), c.Params("verificationId"))
+	if err != nil {
+		if errors.Is(err, services.ErrCertificateNotFound) {
+			return SendError(c, fiber.StatusNotFound, ErrNotFound, "Certificate not found", nil)
+		}
+		logger.Error("failed to verify certificate", "error", err)
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to verify certificate", nil)
+	}
+
+	return SendOK(c, verification)
+}
@@ -1,6 +1,9 @@
 package http
 
 import (
+	"errors"
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
@@ -53,8 +56,9 @@ func (h *CourseHandler) GetCourse(c *fiber.Ctx) error {
 
 // CreateModuleRequest is the payload to add a module
 type CreateModuleRequest struct {
-	Title     string `json:"title"`
-	SortOrder int    `json:"sort_order"`
+	Title     string           `json:"title"`
+	SortOrder int              `json:"sort_order"`
+	Drip      *domain.DripRule `json:"drip,omitempty"`
 }
 
 func (h *CourseHandler) CreateModule(c *fiber.Ctx) error {
@@ -75,8 +79,11 @@ func (h *CourseHandler) CreateModule(c *fiber.Ctx) error {
 	}
 	creatorID, _ := primitive.ObjectIDFromHex(userIDStr)
 
-	course, err := h.service.CreateModule(c.Context(), productID, creatorID, req.Title, req.SortOrder)
+	course, err := h.service.CreateModule(c.Context(), productID, creatorID, req.Title, req.SortOrder, req.Drip)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidDripRule) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
@@ -102,11 +109,14 @@ func (h *CourseHandler) UpdateModule(c *fiber.Ctx) error {
 	}
 	creatorID, _ := primitive.ObjectIDFromHex(userIDStr)
 
-	course, err := h.service.UpdateModule(c.Context(), productID, creatorID, moduleID, req.Title, req.SortOrder)
+	course, err := h.service.UpdateModule(c.Context(), productID, creatorID, moduleID, req.Title, req.SortOrder, req.Drip)
 	if err != nil {
 		if err == services.ErrModuleNotFound {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Module not found"})
 		}
+		if errors.Is(err, services.ErrInvalidDripRule) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
@@ -162,6 +172,9 @@ func (h *CourseHandler) CreateLesson(c *fiber.Ctx) error {
 		if err == services.ErrModuleNotFound {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Module not found"})
 		}
+		if errors.Is(err, services.ErrInvalidDripRule) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
@@ -193,6 +206,9 @@ func (h *CourseHandler) UpdateLesson(c *fiber.Ctx) error {
 		if err == services.ErrLessonNotFound || err == services.ErrModuleNotFound {
 			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Lesson/Module not found"})
 		}
+		if errors.Is(err, services.ErrInvalidDripRule) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
 	}
 
@@ -250,3 +266,108 @@ func (h *CourseHandler) ReorderStructure(c *fiber.Ctx) error {
 
 	return c.JSON(course)
 }
+
+// UpdateLessonProgressRequest is the payload a buyer sends while working through a lesson.
+// Omitted fields are left unchanged.
+type UpdateLessonProgressRequest struct {
+	Completed       *bool `json:"completed"`
+	PositionSeconds *int  `json:"position_seconds"`
+}
+
+// UpdateLessonProgress marks a lesson complete and/or saves the video resume position
+// PUT /api/v1/buyer/courses/:id/progress/:lesId
+func (h *CourseHandler) UpdateLessonProgress(c *fiber.Ctx) error {
+	productID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	lessonID := c.Params("lesId")
+	if err != nil || lessonID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid ID parameters"})
+	}
+
+	var req UpdateLessonProgressRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid request body"})
+	}
+
+	userIDStr, ok := c.Locals("userId").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User context not found"})
+	}
+	buyerID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	progress, err := h.service.UpdateLessonProgress(c.Context(), productID, buyerID, lessonID, req.Completed, req.PositionSeconds)
+	if err != nil {
+		return h.progressError(c, err)
+	}
+
+	return c.JSON(progress)
+}
+
+// GetCourseProgress returns the buyer's progress so the course player can resume
+// GET /api/v1/buyer/courses/:id/progress
+func (h *CourseHandler) GetCourseProgress(c *fiber.Ctx) error {
+	productID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid product ID"})
+	}
+
+	userIDStr, ok := c.Locals("userId").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User context not found"})
+	}
+	buyerID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Invalid user ID"})
+	}
+
+	progress, err := h.service.GetCourseProgress(c.Context(), productID, buyerID)
+	if err != nil {
+		return h.progressError(c, err)
+	}
+
+	return c.JSON(progress)
+}
+
+// GetCourseAnalytics returns completion and per-lesson drop-off for the creator's course
+// GET /api/v1/products/:id/course/analytics
+func (h *CourseHandler) GetCourseAnalytics(c *fiber.Ctx) error {
+	productID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid product ID"})
+	}
+
+	userIDStr, ok := c.Locals("userId").(string)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "User context not found"})
+	}
+	creatorID, _ := primitive.ObjectIDFromHex(userIDStr)
+
+	report, err := h.service.GetCourseAnalytics(c.Context(), productID, creatorID)
+	if err != nil {
+		if err.Error() == "unauthorized access to product" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Unauthorized"})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	return c.JSON(report)
+}
+
+// progressError maps course progress failures to HTTP responses.
+func (h *CourseHandler) progressError(c *fiber.Ctx, err error) error {
+	if err == services.ErrLessonNotFound {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Lesson not found"})
+	}
+	if err == services.ErrLessonLocked {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Lesson is locked"})
+	}
+	if strings.HasPrefix(err.Error(), "unauthorized") {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	}
+	if err.Error() == "course not found" {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Course not found"})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": err.Error()})
+}
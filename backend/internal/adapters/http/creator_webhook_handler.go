@@ -0,0 +1,136 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/internal/core/services"
+)
+
+// CreatorWebhookHandler lets creators manage the endpoints their store events are sent to.
+type CreatorWebhookHandler struct {
+	service *services.CreatorWebhookService
+}
+
+// NewCreatorWebhookHandler creates a new CreatorWebhookHandler.
+func NewCreatorWebhookHandler(service *services.CreatorWebhookService) *CreatorWebhookHandler {
+	return &CreatorWebhookHandler{service: service}
+}
+
+// ListEventTypes returns the events an endpoint can subscribe to.
+// GET /api/v1/creator/webhooks/events
+func (h *CreatorWebhookHandler) ListEventTypes(c *fiber.Ctx) error {
+	return SendOK(c, domain.WebhookEventTypes)
+}
+
+// ListEndpoints returns the creator's webhook endpoints. Signing secrets are never returned.
+// GET /api/v1/creator/webhooks
+func (h *CreatorWebhookHandler) ListEndpoints(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userId").(string)
+	endpoints, err := h.service.ListEndpoints(c.Context(), userID)
+	if err != nil {
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to fetch webhooks", err)
+	}
+	return SendOK(c, endpoints)
+}
+
+// CreateEndpoint registers an endpoint. The signing secret is only included in this response.
+// POST /api/v1/creator/webhooks
+func (h *CreatorWebhookHandler) CreateEndpoint(c *fiber.Ctx) error {
+	var req struct {
+		URL         string   `json:"url"`
+		Description string   `json:"description"`
+		Events      []string `json:"events"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
+	}
+
+	userID, _ := c.Locals("userId").(string)
+	endpoint, secret, err := h.service.CreateEndpoint(c.Context(), userID, req.URL, req.Description, req.Events)
+	if err != nil {
+		return h.sendServiceError(c, err, "Failed to create webhook")
+	}
+	return SendSuccess(c, fiber.StatusCreated, fiber.Map{"endpoint": endpoint, "secret": secret}, nil)
+}
+
+// UpdateEndpoint changes an endpoint's URL, description, events or enabled state.
+// PATCH /api/v1/creator/webhooks/:id
+func (h *CreatorWebhookHandler) UpdateEndpoint(c *fiber.Ctx) error {
+	var req services.WebhookEndpointUpdate
+	if err := c.BodyParser(&req); err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
+	}
+
+	userID, _ := c.Locals("userId").(string)
+	endpoint, err := h.service.UpdateEndpoint(c.Context(), userID, c.Params("id"), req)
+	if err != nil {
+		return h.sendServiceError(c, err, "Failed to update webhook")
+	}
+	return SendOK(c, endpoint)
+}
+
+// RotateSecret replaces an endpoint's signing secret. The new secret is only included in this response.
+// POST /api/v1/creator/webhooks/:id/rotate-secret
+func (h *CreatorWebhookHandler) RotateSecret(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userId").(string)
+	secret, err := h.service.RotateSecret(c.Context(), userID, c.Params("id"))
+	if err != nil {
+		return h.sendServiceError(c, err, "Failed to rotate webhook secret")
+	}
+	return SendOK(c, fiber.Map{"secret": secret})
+}
+
+// DeleteEndpoint removes an endpoint and its delivery log.
+// DELETE /api/v1/creator/webhooks/:id
+func (h *CreatorWebhookHandler) DeleteEndpoint(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userId").(string)
+	if err := h.service.DeleteEndpoint(c.Context(), userID, c.Params("id")); err != nil {
+		return h.sendServiceError(c, err, "Failed to delete webhook")
+	}
+	return SendOK(c, map[string]bool{"deleted": true})
+}
+
+// ListDeliveries returns a page of an endpoint's delivery log, newest first.
+// GET /api/v1/creator/webhooks/:id/deliveries?page=1&pageSize=25
+func (h *CreatorWebhookHandler) ListDeliveries(c *fiber.Ctx) error {
+	page, _ := strconv.ParseInt(c.Query("page", "1"), 10, 64)
+	pageSize, _ := strconv.ParseInt(c.Query("pageSize", "25"), 10, 64)
+
+	userID, _ := c.Locals("userId").(string)
+	deliveries, meta, err := h.service.ListDeliveries(c.Context(), userID, c.Params("id"), &domain.Pagination{
+		Page:     page,
+		PageSize: pageSize,
+	})
+	if err != nil {
+		return h.sendServiceError(c, err, "Failed to fetch webhook deliveries")
+	}
+	return SendSuccess(c, fiber.StatusOK, deliveries, meta)
+}
+
+// Redeliver sends a logged delivery again and returns it with the new attempt.
+// POST /api/v1/creator/webhooks/deliveries/:deliveryId/redeliver
+func (h *CreatorWebhookHandler) Redeliver(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userId").(string)
+	delivery, err := h.service.Redeliver(c.Context(), userID, c.Params("deliveryId"))
+	if err != nil {
+		return h.sendServiceError(c, err, "Failed to redeliver webhook")
+	}
+	return SendOK(c, delivery)
+}
+
+// sendServiceError maps webhook service errors to responses.
+func (h *CreatorWebhookHandler) sendServiceError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, services.ErrWebhookEndpointNotFound), errors.Is(err, services.ErrWebhookDeliveryNotFound):
+		return SendError(c, fiber.StatusNotFound, ErrNotFound, err.Error(), nil)
+	case errors.Is(err, services.ErrInvalidWebhookURL), errors.Is(err, services.ErrInvalidWebhookEvents):
+		return SendError(c, fiber.StatusBadRequest, ErrValidation, err.Error(), nil)
+	case errors.Is(err, services.ErrWebhookEndpointLimit):
+		return SendError(c, fiber.StatusConflict, ErrConflict, err.Error(), nil)
+	}
+	return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, fallback, err)
+}
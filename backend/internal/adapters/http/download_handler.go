@@ -0,0 +1,118 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/internal/core/services"
+)
+
+// DownloadHandler handles signed download links and the creator's download audit log.
+type DownloadHandler struct {
+	service *services.DownloadService
+}
+
+// NewDownloadHandler creates a new DownloadHandler.
+func NewDownloadHandler(service *services.DownloadService) *DownloadHandler {
+	return &DownloadHandler{service: service}
+}
+
+// Redeem handles GET /api/v1/downloads/:token and redirects to the file.
+func (h *DownloadHandler) Redeem(c *fiber.Ctx) error {
+	url, err := h.service.RedeemToken(c.Context(), c.Params("token"), c.IP(), c.Get(fiber.HeaderUserAgent))
+	if err != nil {
+		if errors.Is(err, services.ErrDownloadPreparing) {
+			return sendPreparing(c)
+		}
+		if errors.Is(err, services.ErrInvalidDownloadToken) || errors.Is(err, services.ErrProductFileNotFound) {
+			return SendError(c, fiber.StatusNotFound, ErrNotFound, err.Error(), nil)
+		}
+		if status, ok := downloadErrorStatus(err); ok {
+			return SendError(c, status, ErrForbidden, err.Error(), nil)
+		}
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to generate download link", err)
+	}
+
+	return c.Redirect(url, fiber.StatusFound)
+}
+
+// GetLogs handles GET /api/v1/sales/downloads (Creator only).
+func (h *DownloadHandler) GetLogs(c *fiber.Ctx) error {
+	creatorID, err := primitive.ObjectIDFromHex(c.Locals("userId").(string))
+	if err != nil {
+		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Invalid user ID", nil)
+	}
+
+	var filter domain.DownloadLogFilter
+	if v := c.Query("product_id"); v != "" {
+		id, err := primitive.ObjectIDFromHex(v)
+		if err != nil {
+			return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid product ID", nil)
+		}
+		filter.ProductID = &id
+	}
+	if v := c.Query("order_id"); v != "" {
+		id, err := primitive.ObjectIDFromHex(v)
+		if err != nil {
+			return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid order ID", nil)
+		}
+		filter.OrderID = &id
+	}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid limit", nil)
+		}
+		filter.Limit = limit
+	}
+
+	logs, err := h.service.GetLogs(c.Context(), creatorID, filter)
+	if err != nil {
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to fetch download log", err)
+	}
+	return SendOK(c, logs)
+}
+
+// Unlock handles POST /api/v1/sales/downloads/:entitlementId/unlock (Creator only).
+func (h *DownloadHandler) Unlock(c *fiber.Ctx) error {
+	creatorID, err := primitive.ObjectIDFromHex(c.Locals("userId").(string))
+	if err != nil {
+		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Invalid user ID", nil)
+	}
+	entitlementID, err := primitive.ObjectIDFromHex(c.Params("entitlementId"))
+	if err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid entitlement ID", nil)
+	}
+
+	if err := h.service.UnlockEntitlement(c.Context(), entitlementID, creatorID); err != nil {
+		if errors.Is(err, services.ErrEntitlementNotFound) {
+			return SendError(c, fiber.StatusNotFound, ErrNotFound, err.Error(), nil)
+		}
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to unlock downloads", err)
+	}
+	return SendOK(c, fiber.Map{"unlocked": true})
+}
+
+// downloadErrorStatus maps entitlement denials to HTTP statuses.
+func downloadErrorStatus(err error) (int, bool) {
+	switch {
+	case errors.Is(err, services.ErrDownloadLocked), errors.Is(err, services.ErrDownloadLimitReached):
+		return fiber.StatusForbidden, true
+	case errors.Is(err, services.ErrDownloadExpired):
+		return fiber.StatusGone, true
+	}
+	return 0, false
+}
+
+// sendPreparing tells the client a personalised file is being generated and to retry shortly.
+func sendPreparing(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderRetryAfter, "30")
+	return SendSuccess(c, fiber.StatusAccepted, fiber.Map{
+		"status":  "preparing",
+		"message": "Your personalised copy is being prepared. Please try again in a minute.",
+	}, nil)
+}
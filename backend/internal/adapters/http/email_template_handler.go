@@ -51,3 +51,29 @@ func (h *EmailTemplateHandler) UpdateTemplate(c *fiber.Ctx) error {
 
 	return SendOK(c, updatedTemplate)
 }
+
+// GetPlatformTemplate handles GET /api/v1/admin/email-templates/:type
+func (h *EmailTemplateHandler) GetPlatformTemplate(c *fiber.Ctx) error {
+	template, err := h.service.GetPlatformTemplate(c.Context(), c.Params("type"))
+	if err != nil {
+		return SendError(c, fiber.StatusNotFound, ErrNotFound, err.Error(), nil)
+	}
+	return SendOK(c, template)
+}
+
+// UpdatePlatformTemplate handles PUT /api/v1/admin/email-templates/:type
+func (h *EmailTemplateHandler) UpdatePlatformTemplate(c *fiber.Ctx) error {
+	templateType := c.Params("type")
+
+	var input services.UpdateTemplateInput
+	if err := c.BodyParser(&input); err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrValidation, "Invalid request body", nil)
+	}
+
+	if err := h.service.UpdatePlatformTemplate(c.Context(), auditActor(c), templateType, &input); err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrValidation, err.Error(), nil)
+	}
+
+	updatedTemplate, _ := h.service.GetPlatformTemplate(c.Context(), templateType)
+	return SendOK(c, updatedTemplate)
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/devanshbhargava/stan-store/internal/core/services"
+)
+
+// ImpersonationHandler lets admins view a creator's account for support.
+type ImpersonationHandler struct {
+	service *services.ImpersonationService
+}
+
+// NewImpersonationHandler creates a new ImpersonationHandler.
+func NewImpersonationHandler(service *services.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{service: service}
+}
+
+// Start opens a read-only impersonation session on a creator's account. The admin's access
+// cookie is replaced by the impersonation token while their refresh cookie is kept, so when
+// the session ends or expires the browser's next refresh signs the admin back in as themselves.
+// POST /api/v1/admin/creators/:id/impersonate
+func (h *ImpersonationHandler) Start(c *fiber.Ctx) error {
+	var body struct {
+		Reason  string `json:"reason"`
+		Minutes int    `json:"minutes"` // 5–60, default 30
+	}
+	if err := c.BodyParser(&body); err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
+	}
+
+	imp, token, err := h.service.Start(c.Context(), auditActor(c), c.Params("id"), body.Reason, time.Duration(body.Minutes)*time.Minute)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrImpersonationReasonRequired):
+			return SendError(c, fiber.StatusBadRequest, ErrBadRequest, err.Error(), nil)
+		case errors.Is(err, services.ErrCannotImpersonate):
+			return SendError(c, fiber.StatusForbidden, ErrForbidden, err.Error(), nil)
+		case err.Error() == "user not found":
+			return SendError(c, fiber.StatusNotFound, ErrNotFound, "Creator not found", nil)
+		}
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to start impersonation", err)
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     cookieName,
+		Value:    token,
+		Expires:  imp.ExpiresAt,
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: "Strict",
+		Path:     "/",
+	})
+	return SendSuccess(c, fiber.StatusCreated, imp, nil)
+}
+
+// Stop ends the impersonation session making the request and drops its access cookie.
+// POST /api/v1/auth/impersonation/stop
+func (h *ImpersonationHandler) Stop(c *fiber.Ctx) error {
+	if adminID, _ := c.Locals("impersonatorId").(string); adminID == "" {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, services.ErrNotImpersonating.Error(), nil)
+	}
+
+	sessionID, _ := c.Locals("sessionId").(string)
+	if err := h.service.Stop(c.Context(), auditActor(c), sessionID); err != nil && !errors.Is(err, services.ErrNotImpersonating) {
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to stop impersonation", err)
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     cookieName,
+		Value:    "",
+		MaxAge:   -1,
+		HTTPOnly: true,
+		Secure:   true,
+		SameSite: "Strict",
+		Path:     "/",
+	})
+	return SendOK(c, map[string]bool{"stopped": true})
+}
@@ -0,0 +1,202 @@
+package http
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/internal/core/services"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+// InvoiceHandler handles HTTP requests for GST invoices and creator tax settings.
+type InvoiceHandler struct {
+	service *services.InvoiceService
+}
+
+// NewInvoiceHandler creates a new InvoiceHandler.
+func NewInvoiceHandler(service *services.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{service: service}
+}
+
+// GetSettings returns the creator's business details and tax setup.
+// GET /api/v1/creator/invoice-settings
+func (h *InvoiceHandler) GetSettings(c *fiber.Ctx) error {
+	creatorID, err := primitive.ObjectIDFromHex(c.Locals("userId").(string))
+	if err != nil {
+		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Invalid user ID", nil)
+	}
+
+	settings, err := h.service.GetSettings(c.Context(), creatorID)
+	if err != nil {
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to fetch invoice settings", err)
+	}
+	return SendOK(c, fiber.Map{"settings": settings, "states": domain.GSTStates, "rates": domain.GSTRates})
+}
+
+// UpdateSettings saves the creator's business details and tax setup.
+// PUT /api/v1/creator/invoice-settings
+func (h *InvoiceHandler) UpdateSettings(c *fiber.Ctx) error {
+	creatorID, err := primitive.ObjectIDFromHex(c.Locals("userId").(string))
+	if err != nil {
+		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Invalid user ID", nil)
+	}
+
+	var req domain.InvoiceSettings
+	if err := c.BodyParser(&req); err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
+	}
+
+	settings, err := h.service.UpdateSettings(c.Context(), creatorID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrIncompleteBusiness),
+			errors.Is(err, services.ErrInvalidStateCode),
+			errors.Is(err, services.ErrInvalidGSTIN),
+			errors.Is(err, services.ErrGSTINStateMismatch),
+			errors.Is(err, services.ErrInvalidTaxRate),
+			errors.Is(err, services.ErrInvalidInvoicePrefix):
+			return SendError(c, fiber.StatusBadRequest, ErrValidation, err.Error(), nil)
+		}
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to save invoice settings", err)
+	}
+	return SendOK(c, settings)
+}
+
+// GetCreatorInvoice returns the invoice for one of the creator's orders with a download link,
+// issuing it if needed.
+// GET /api/v1/creator/orders/:id/invoice
+func (h *InvoiceHandler) GetCreatorInvoice(c *fiber.Ctx) error {
+	orderID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid order ID", nil)
+	}
+	creatorID, err := primitive.ObjectIDFromHex(c.Locals("userId").(string))
+	if err != nil {
+		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Invalid user ID", nil)
+	}
+
+	invoice, url, err := h.service.GetCreatorInvoiceURL(c.Context(), orderID, creatorID)
+	if err != nil {
+		return h.sendInvoiceError(c, err, orderID)
+	}
+	return SendOK(c, fiber.Map{"invoice": invoice, "download_url": url})
+}
+
+// GetMyInvoice returns the invoice for one of the buyer's orders with a download link.
+// GET /api/v1/buyer/orders/:id/invoice
+func (h *InvoiceHandler) GetMyInvoice(c *fiber.Ctx) error {
+	orderID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid order ID", nil)
+	}
+	userIDStr, ok := c.Locals("userId").(string)
+	if !ok || userIDStr == "" {
+		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "User ID not found in context", nil)
+	}
+	buyerID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Invalid user ID", nil)
+	}
+
+	invoice, url, err := h.service.GetBuyerInvoiceURL(c.Context(), orderID, buyerID)
+	if err != nil {
+		return h.sendInvoiceError(c, err, orderID)
+	}
+	return SendOK(c, fiber.Map{"invoice": invoice, "download_url": url})
+}
+
+// GetTaxSummary totals the creator's invoices for a month.
+// GET /api/v1/creator/tax-summary?month=2026-04
+func (h *InvoiceHandler) GetTaxSummary(c *fiber.Ctx) error {
+	creatorID, err := primitive.ObjectIDFromHex(c.Locals("userId").(string))
+	if err != nil {
+		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Invalid user ID", nil)
+	}
+
+	summary, _, err := h.service.TaxSummary(c.Context(), creatorID, taxMonth(c))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTaxMonth) {
+			return SendError(c, fiber.StatusBadRequest, ErrValidation, err.Error(), nil)
+		}
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to build tax summary", err)
+	}
+	return SendOK(c, summary)
+}
+
+// ExportTaxSummary downloads the month's invoices as CSV, one row per invoice, for GST filing.
+// GET /api/v1/creator/tax-summary/export?month=2026-04
+func (h *InvoiceHandler) ExportTaxSummary(c *fiber.Ctx) error {
+	creatorID, err := primitive.ObjectIDFromHex(c.Locals("userId").(string))
+	if err != nil {
+		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Invalid user ID", nil)
+	}
+
+	month := taxMonth(c)
+	_, invoices, err := h.service.TaxSummary(c.Context(), creatorID, month)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTaxMonth) {
+			return SendError(c, fiber.StatusBadRequest, ErrValidation, err.Error(), nil)
+		}
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to export tax summary", err)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{
+		"invoice_number", "invoice_date", "type", "buyer_name", "buyer_gstin", "place_of_supply",
+		"tax_rate", "taxable_value", "cgst", "sgst", "igst", "total",
+	})
+	for _, inv := range invoices {
+		_ = w.Write([]string{
+			inv.Number,
+			inv.IssuedAt.In(domain.InvoiceZone).Format("2006-01-02"),
+			string(inv.Type),
+			inv.Buyer.Name,
+			inv.Buyer.GSTIN,
+			inv.PlaceOfSupply,
+			strconv.FormatFloat(inv.TaxRate, 'f', -1, 64),
+			rupees(inv.TaxableAmount),
+			rupees(inv.CGST),
+			rupees(inv.SGST),
+			rupees(inv.IGST),
+			rupees(inv.Total),
+		})
+	}
+	w.Flush()
+
+	c.Set(fiber.HeaderContentType, "text/csv")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="tax-summary-%s.csv"`, month))
+	return c.Send(buf.Bytes())
+}
+
+func (h *InvoiceHandler) sendInvoiceError(c *fiber.Ctx, err error, orderID primitive.ObjectID) error {
+	switch {
+	case errors.Is(err, services.ErrInvoiceNotFound):
+		return SendError(c, fiber.StatusNotFound, ErrNotFound, "Invoice not found", nil)
+	case errors.Is(err, services.ErrOrderNotInvoiceable), errors.Is(err, services.ErrInvoicePending):
+		return SendError(c, fiber.StatusConflict, ErrConflict, err.Error(), nil)
+	}
+	logger.Error("invoice fetch failed", "error", err, "order_id", orderID.Hex())
+	return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to fetch invoice", nil)
+}
+
+// taxMonth reads ?month=YYYY-MM, defaulting to the current month.
+func taxMonth(c *fiber.Ctx) string {
+	if month := c.Query("month"); month != "" {
+		return month
+	}
+	return time.Now().In(domain.InvoiceZone).Format("2006-01")
+}
+
+// rupees formats paise as a plain decimal for spreadsheets.
+func rupees(paise int64) string {
+	return strconv.FormatFloat(float64(paise)/100, 'f', 2, 64)
+}
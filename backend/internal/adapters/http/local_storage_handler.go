@@ -0,0 +1,74 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/devanshbhargava/stan-store/internal/adapters/storage"
+)
+
+// LocalStorageHandler serves the presigned upload and download URLs issued by LocalStorage,
+// standing in for the S3 endpoints during development and tests.
+type LocalStorageHandler struct {
+	storage *storage.LocalStorage
+}
+
+// NewLocalStorageHandler creates a new LocalStorageHandler.
+func NewLocalStorageHandler(storage *storage.LocalStorage) *LocalStorageHandler {
+	return &LocalStorageHandler{storage: storage}
+}
+
+// PutObject handles PUT /api/v1/storage/* with a presigned upload URL. The body is streamed
+// to disk when the server streams request bodies.
+func (h *LocalStorageHandler) PutObject(c *fiber.Ctx) error {
+	key, err := url.PathUnescape(c.Params("*"))
+	if err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, storage.ErrInvalidStorageKey.Error(), nil)
+	}
+	if err := h.storage.VerifySignature(fiber.MethodPut, key, c.Query("expires"), c.Query("signature")); err != nil {
+		return SendError(c, fiber.StatusForbidden, ErrForbidden, err.Error(), nil)
+	}
+
+	var body io.Reader = bytes.NewReader(c.Body())
+	if c.Request().IsBodyStream() {
+		body = c.Request().BodyStream()
+	}
+	if err := h.storage.Write(c.Context(), key, body); err != nil {
+		if errors.Is(err, storage.ErrInvalidStorageKey) {
+			return SendError(c, fiber.StatusBadRequest, ErrBadRequest, err.Error(), nil)
+		}
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to store object", err)
+	}
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// GetObject handles GET /api/v1/storage/*. Signed URLs may read any object; unsigned requests
+// are limited to cover images and their variants, which a public bucket would serve.
+func (h *LocalStorageHandler) GetObject(c *fiber.Ctx) error {
+	key, err := url.PathUnescape(c.Params("*"))
+	if err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, storage.ErrInvalidStorageKey.Error(), nil)
+	}
+	if c.Query("signature") != "" {
+		if err := h.storage.VerifySignature(fiber.MethodGet, key, c.Query("expires"), c.Query("signature")); err != nil {
+			return SendError(c, fiber.StatusForbidden, ErrForbidden, err.Error(), nil)
+		}
+	} else if !strings.Contains(key, "/products/covers/") {
+		return SendError(c, fiber.StatusForbidden, ErrForbidden, "Object is not public", nil)
+	}
+
+	p, err := h.storage.Path(key)
+	if err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, err.Error(), nil)
+	}
+	if _, err := os.Stat(p); err != nil {
+		return SendError(c, fiber.StatusNotFound, ErrNotFound, "Object not found", nil)
+	}
+	return c.SendFile(p)
+}
@@ -2,7 +2,9 @@ package http
 
 import (
 	"fmt"
+	"io"
 	"runtime/debug"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -70,3 +72,29 @@ func Recovery() fiber.Handler {
 		return c.Next()
 	}
 }
+
+// BodyLimit caps request bodies at limit when the server streams them
+// (fiber.Config.StreamRequestBody), which otherwise lets bodies of any size through. PUTs
+// under streamPrefix are left alone; their handlers read the body as a stream.
+func BodyLimit(limit int, streamPrefix string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		req := c.Request()
+		if !req.IsBodyStream() || (c.Method() == fiber.MethodPut && strings.HasPrefix(c.Path(), streamPrefix)) {
+			return c.Next()
+		}
+		if req.Header.ContentLength() > limit {
+			return SendError(c, fiber.StatusRequestEntityTooLarge, ErrBadRequest, "Request body too large", nil)
+		}
+
+		// Chunked bodies have no length up front
+		body, err := io.ReadAll(io.LimitReader(req.BodyStream(), int64(limit)+1))
+		if err != nil {
+			return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Failed to read request body", nil)
+		}
+		if len(body) > limit {
+			return SendError(c, fiber.StatusRequestEntityTooLarge, ErrBadRequest, "Request body too large", nil)
+		}
+		req.SetBody(body)
+		return c.Next()
+	}
+}
@@ -0,0 +1,64 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/internal/core/services"
+)
+
+// APIKeyAuth lets a route accept a creator API key, sent as "Authorization: Bearer mio_sk_...",
+// alongside the browser session. Requests without an API key are passed to sessionAuth
+// (normally AuthRequired). A key must hold readScope for GET requests and writeScope for
+// anything else; an empty scope means keys can't use that method. Each key is rate limited
+// to its own requests per minute, reported in the X-RateLimit-* headers.
+// On success c.Locals("userId") is the key's creator and c.Locals("apiKey") the key.
+func APIKeyAuth(apiKeys *services.APIKeyService, sessionAuth fiber.Handler, readScope, writeScope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		secret := strings.TrimPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if !strings.HasPrefix(secret, services.APIKeyPrefix) {
+			return sessionAuth(c)
+		}
+
+		key, err := apiKeys.Authenticate(c.Context(), secret, c.IP())
+		if errors.Is(err, services.ErrInvalidAPIKey) {
+			return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, err.Error(), nil)
+		}
+		if err != nil {
+			return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to validate API key", err)
+		}
+
+		scope := readScope
+		if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+			scope = writeScope
+		}
+		if scope == "" {
+			return SendError(c, fiber.StatusForbidden, ErrForbidden, "This endpoint can't be used with an API key", nil)
+		}
+		if !key.HasScope(scope) {
+			return SendError(c, fiber.StatusForbidden, ErrForbidden, "API key is missing the "+scope+" scope", nil)
+		}
+
+		allowed, remaining, reset, err := apiKeys.Allow(c.Context(), key)
+		if err != nil {
+			return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to check rate limit", err)
+		}
+		resetSeconds := strconv.Itoa(int(reset.Seconds()) + 1)
+		c.Set("X-RateLimit-Limit", strconv.Itoa(key.RateLimit))
+		c.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		c.Set("X-RateLimit-Reset", resetSeconds)
+		if !allowed {
+			c.Set(fiber.HeaderRetryAfter, resetSeconds)
+			return SendError(c, fiber.StatusTooManyRequests, ErrTooManyRequests, "API key rate limit exceeded", nil)
+		}
+
+		c.Locals("userId", key.CreatorID.Hex())
+		c.Locals("role", domain.RoleCreator)
+		c.Locals("apiKey", key)
+		return c.Next()
+	}
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"errors"
 
 	"github.com/gofiber/fiber/v2"
 
@@ -11,19 +12,15 @@ import (
 )
 
 // AuthRequired middleware extracts and validates the JWT from the HTTP-Only cookie.
-// On success, it sets c.Locals("userId") and c.Locals("role").
+// On success, it sets c.Locals("userId"), c.Locals("role") and c.Locals("sessionId").
 // On failure, it returns 401 ERR_UNAUTHORIZED.
-func AuthRequired(jwtService *services.JWTService) fiber.Handler {
+// When sessions is set, the token's session must still be live in Redis, so revoked sessions
+// are rejected immediately rather than when the access token expires.
+// Impersonation tokens also set c.Locals("impersonatorId") and c.Locals("impersonatedId")
+// and are read-only: any request that could change data is rejected with 403.
+func AuthRequired(jwtService *services.JWTService, sessions *services.SessionService) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		token := extractTokenFromCookie(c)
-		if token == "" {
-			// Fallback to Authorization header
-			authHeader := c.Get("Authorization")
-			if len(authHeader) > 7 && authHeader[:7] == "Bearer " {
-				token = authHeader[7:]
-			}
-		}
-
+		token := extractAccessToken(c)
 		if token == "" {
 			return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Authentication required - No Token", nil)
 		}
@@ -33,9 +30,30 @@ func AuthRequired(jwtService *services.JWTService) fiber.Handler {
 			return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Invalid or expired token", nil)
 		}
 
+		if sessions != nil {
+			if claims.SessionID == "" {
+				return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Invalid or expired token", nil)
+			}
+			if err := sessions.Validate(c.Context(), claims.SessionID, claims.UserID, c.IP()); err != nil {
+				if errors.Is(err, services.ErrSessionRevoked) {
+					return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Session has been revoked", nil)
+				}
+				return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to validate session", err)
+			}
+		}
+
 		// Inject user identity into request context
 		c.Locals("userId", claims.UserID)
 		c.Locals("role", claims.Role)
+		c.Locals("sessionId", claims.SessionID)
+
+		if claims.ImpersonatorID != "" {
+			c.Locals("impersonatorId", claims.ImpersonatorID)
+			c.Locals("impersonatedId", claims.UserID)
+			if !impersonationAllows(c) {
+				return SendError(c, fiber.StatusForbidden, ErrImpersonationReadOnly, "Impersonation sessions are read-only", nil)
+			}
+		}
 
 		return c.Next()
 	}
@@ -63,7 +81,7 @@ func RoleRequired(roles ...string) fiber.Handler {
 	}
 }
 
-// BanCheck middleware verifies that the authenticated user is not banned.
+// BanCheck middleware verifies that the authenticated user is not banned or deleted.
 // Must be used AFTER AuthRequired in the middleware chain.
 // Admin users bypass this check so they can still manage bans.
 func BanCheck(userRepo domain.UserRepository) fiber.Handler {
@@ -89,6 +107,9 @@ func BanCheck(userRepo domain.UserRepository) fiber.Handler {
 		if user.Status == domain.UserStatusBanned {
 			return SendError(c, fiber.StatusForbidden, ErrAccountBanned, "Your account has been suspended", nil)
 		}
+		if user.Status == domain.UserStatusDeleted {
+			return SendError(c, fiber.StatusUnauthorized, ErrAccountDeleted, "This account has been deleted", nil)
+		}
 
 		return c.Next()
 	}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+
+	"github.com/devanshbhargava/stan-store/internal/core/services"
+)
+
+// impersonationStopPath is the one write an impersonation session may make.
+const impersonationStopPath = "/api/v1/auth/impersonation/stop"
+
+// impersonationAllows reports whether an impersonation session may make this request:
+// reads only, plus ending the session.
+func impersonationAllows(c *fiber.Ctx) bool {
+	switch c.Method() {
+	case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		return true
+	}
+	return c.Path() == impersonationStopPath
+}
+
+// ImpersonationAudit writes every request made with an impersonation token to the audit log,
+// including those rejected as writes. It runs globally, ahead of AuthRequired, and records
+// once the request has been handled.
+func ImpersonationAudit(impersonation *services.ImpersonationService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+
+		creatorID, _ := c.Locals("impersonatedId").(string)
+		if creatorID == "" {
+			return err
+		}
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fe *fiber.Error
+			if errors.As(err, &fe) {
+				status = fe.Code
+			}
+		}
+		impersonation.RecordRequest(c.Context(), auditActor(c), creatorID, c.Method(), utils.CopyString(c.Path()), status)
+		return err
+	}
+}
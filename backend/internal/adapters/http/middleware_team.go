@@ -0,0 +1,83 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/internal/core/services"
+)
+
+// creatorAccountHeader names the creator account a team member is acting on.
+const creatorAccountHeader = "X-Creator-Id"
+
+// TeamAccess lets team members work on a creator's account. A request that names another
+// account in the X-Creator-Id header must come from an active member with access to one of
+// areas: GET needs read access, anything else write access. On success c.Locals("userId")
+// becomes the owning creator, so handlers keep working on the creator's data, while
+// c.Locals("actorId") stays the signed-in member. Member writes are added to the creator's
+// team activity. Without the header the caller acts on their own account. API keys always act
+// on the account that created them, and impersonation sessions on the impersonated account.
+// Must be used AFTER AuthRequired and BEFORE BanCheck, so the ban check applies to the creator;
+// the member's own account is checked here before the swap.
+func TeamAccess(teams *services.TeamService, userRepo domain.UserRepository, areas ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, _ := c.Locals("userId").(string)
+		c.Locals("actorId", userID)
+
+		creatorID := c.Get(creatorAccountHeader)
+		if creatorID == "" || creatorID == userID {
+			return c.Next()
+		}
+		if _, ok := c.Locals("apiKey").(*domain.APIKey); ok {
+			return SendError(c, fiber.StatusForbidden, ErrForbidden, "API keys can only act on their own account", nil)
+		}
+		if adminID, _ := c.Locals("impersonatorId").(string); adminID != "" {
+			// The impersonation is audited against the creator it was opened for
+			return SendError(c, fiber.StatusForbidden, ErrForbidden, "Impersonation sessions can only view the impersonated account", nil)
+		}
+
+		actor, err := userRepo.FindByID(c.Context(), userID)
+		if err != nil || actor == nil {
+			return SendError(c, fiber.StatusForbidden, ErrForbidden, services.ErrNotTeamMember.Error(), nil)
+		}
+		if actor.Status == domain.UserStatusBanned {
+			return SendError(c, fiber.StatusForbidden, ErrAccountBanned, "Your account has been suspended", nil)
+		}
+		if actor.Status == domain.UserStatusDeleted {
+			return SendError(c, fiber.StatusUnauthorized, ErrAccountDeleted, "This account has been deleted", nil)
+		}
+
+		write := c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead
+		member, err := teams.Authorize(c.Context(), creatorID, userID, areas, write)
+		if errors.Is(err, services.ErrNotTeamMember) || errors.Is(err, services.ErrTeamPermissionDenied) {
+			return SendError(c, fiber.StatusForbidden, ErrForbidden, err.Error(), nil)
+		}
+		if err != nil {
+			return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to check team access", err)
+		}
+
+		c.Locals("userId", creatorID)
+		c.Locals("role", domain.RoleCreator)
+		c.Locals("teamMember", member)
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		if status := c.Response().StatusCode(); write && status < fiber.StatusBadRequest {
+			teams.Record(c.Context(), &domain.TeamActivity{
+				CreatorID:  member.CreatorID,
+				ActorID:    member.UserID,
+				ActorEmail: member.Email,
+				Action:     c.Method() + " " + c.Route().Path,
+				Target:     utils.CopyString(c.Path()),
+				Status:     status,
+				IP:         c.IP(),
+			})
+		}
+		return nil
+	}
+}
@@ -1,6 +1,12 @@
 package http
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
 	"github.com/devanshbhargava/stan-store/internal/core/services"
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -25,6 +31,9 @@ func (h *OrderHandler) CreateOrder(c *fiber.Ctx) error {
 		BumpAccepted     bool   `json:"bump_accepted"`
 		BookingSlotStart string `json:"booking_slot_start,omitempty"`
 		ReferralCode     string `json:"referral_code,omitempty"`
+		BillingState     string `json:"billing_state,omitempty"` // GST state code
+		GSTIN            string `json:"gstin,omitempty"`
+		BusinessName     string `json:"business_name,omitempty"`
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -42,13 +51,19 @@ func (h *OrderHandler) CreateOrder(c *fiber.Ctx) error {
 		referralCode = c.Cookies("stan_ref")
 	}
 
+	billing, err := parseBillingDetails(req.BillingState, req.GSTIN, req.BusinessName)
+	if err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrValidation, err.Error(), nil)
+	}
+
 	// Create Order
-	order, err := h.service.CreateOrder(c.Context(), productID, req.CustomerName, req.CustomerEmail, req.BumpAccepted, req.BookingSlotStart, referralCode)
+	order, err := h.service.CreateOrder(c.Context(), productID, req.CustomerName, req.CustomerEmail, req.BumpAccepted, req.BookingSlotStart, referralCode, billing)
 	if err != nil {
 		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to create order", err)
 	}
 
-	return SendCreated(c, order)
+	// The token opens the order page's downloads once the payment goes through
+	return SendSuccess(c, fiber.StatusCreated, order, fiber.Map{"access_token": h.service.OrderAccessToken(order)})
 }
 
 // GetOrder handles GET /api/v1/orders/:id
@@ -70,7 +85,36 @@ func (h *OrderHandler) GetOrder(c *fiber.Ctx) error {
 	return SendOK(c, order)
 }
 
+// orderAccess collects the caller's proof of access to an order: the ?token= from the order
+// link on the public routes, or the signed-in buyer on the /buyer routes.
+func orderAccess(c *fiber.Ctx) services.OrderAccess {
+	buyerID, _ := c.Locals("userId").(string)
+	return services.OrderAccess{Token: c.Query("token"), BuyerID: buyerID}
+}
+
+// GetOrderFiles lists the latest version of each file in a paid order, keyed by product ID.
+// GET /api/v1/orders/:id/files?token= and GET /api/v1/buyer/orders/:id/files
+func (h *OrderHandler) GetOrderFiles(c *fiber.Ctx) error {
+	orderID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid order ID", nil)
+	}
+
+	files, err := h.service.GetOrderFiles(c.Context(), orderID, orderAccess(c))
+	if err != nil {
+		if errors.Is(err, services.ErrOrderAccessDenied) {
+			return SendError(c, fiber.StatusForbidden, ErrForbidden, err.Error(), nil)
+		}
+		if err.Error() == "order not found" {
+			return SendError(c, fiber.StatusNotFound, ErrNotFound, "Order not found", nil)
+		}
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to fetch order files", err)
+	}
+	return SendOK(c, files)
+}
+
 // DownloadOrder generates a secure download link for a purchased product.
+// GET /api/v1/orders/:id/download?token= and GET /api/v1/buyer/orders/:id/download
 func (h *OrderHandler) DownloadOrder(c *fiber.Ctx) error {
 	orderIDHex := c.Params("id")
 	orderID, err := primitive.ObjectIDFromHex(orderIDHex)
@@ -78,11 +122,20 @@ func (h *OrderHandler) DownloadOrder(c *fiber.Ctx) error {
 		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid order ID", nil)
 	}
 
-	url, err := h.service.GetOrderDownloadURL(c.Context(), orderID, c.Query("product_id"))
+	url, err := h.service.GetOrderDownloadURL(c.Context(), orderID, orderAccess(c), c.Query("product_id"), c.Query("file_id"), c.IP(), c.Get(fiber.HeaderUserAgent))
 	if err != nil {
+		if errors.Is(err, services.ErrDownloadPreparing) {
+			return sendPreparing(c)
+		}
+		if errors.Is(err, services.ErrOrderAccessDenied) {
+			return SendError(c, fiber.StatusForbidden, ErrForbidden, err.Error(), nil)
+		}
+		if status, ok := downloadErrorStatus(err); ok {
+			return SendError(c, status, ErrForbidden, err.Error(), nil)
+		}
 		// Differentiate errors if needed (Order Not Found vs Not Paid vs System Error)
 		// For now generic 400 or 500
-		if err.Error() == "order not found" || err.Error() == "product not found" {
+		if err.Error() == "order not found" || err.Error() == "product not found" || errors.Is(err, services.ErrProductFileNotFound) {
 			return SendError(c, fiber.StatusNotFound, ErrNotFound, err.Error(), nil)
 		}
 		if err.Error() == "order not paid" {
@@ -96,7 +149,14 @@ func (h *OrderHandler) DownloadOrder(c *fiber.Ctx) error {
 	})
 }
 
-// GetSalesHistory handles GET /api/v1/sales (Creator only)
+// salesMeta is the pagination for a page of sales plus totals for every matching order.
+type salesMeta struct {
+	*domain.PaginationMeta
+	Totals *domain.OrderTotals `json:"totals"`
+}
+
+// GetSalesHistory returns a page of the creator's orders.
+// GET /api/v1/sales?status=&product_id=&from=&to=&coupon=&affiliate_id=&min_amount=&max_amount=&q=&sort=newest&page=1&pageSize=25
 func (h *OrderHandler) GetSalesHistory(c *fiber.Ctx) error {
 	userIDStr := c.Locals("userId").(string)
 	creatorID, err := primitive.ObjectIDFromHex(userIDStr)
@@ -104,10 +164,109 @@ func (h *OrderHandler) GetSalesHistory(c *fiber.Ctx) error {
 		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Invalid user ID", nil)
 	}
 
-	orders, err := h.service.GetCreatorOrders(c.Context(), creatorID)
+	filter, err := parseOrderFilter(c)
+	if err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, err.Error(), nil)
+	}
+	filter.CreatorID = creatorID
+
+	sort := domain.OrderSort(c.Query("sort", string(domain.OrderSortNewest)))
+	if !domain.IsValidOrderSort(sort) {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "invalid sort: use newest, oldest, amount_desc or amount_asc", nil)
+	}
+
+	page, _ := strconv.ParseInt(c.Query("page", "1"), 10, 64)
+	pageSize, _ := strconv.ParseInt(c.Query("pageSize", "25"), 10, 64)
+
+	orders, meta, totals, err := h.service.SearchCreatorOrders(c.Context(), filter, sort, &domain.Pagination{
+		Page:     page,
+		PageSize: pageSize,
+	})
 	if err != nil {
 		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to fetch sales history", err)
 	}
 
-	return SendOK(c, orders)
+	return SendSuccess(c, fiber.StatusOK, orders, salesMeta{PaginationMeta: meta, Totals: totals})
+}
+
+// parseOrderFilter reads the sales filters from the query string. Amounts are in paise; from
+// and to take the same formats as the audit log filters.
+func parseOrderFilter(c *fiber.Ctx) (domain.OrderFilter, error) {
+	filter := domain.OrderFilter{
+		Status:     domain.OrderStatus(c.Query("status")),
+		CouponCode: strings.TrimSpace(c.Query("coupon")),
+		Search:     c.Query("q"),
+	}
+	switch filter.Status {
+	case "", domain.OrderStatusCreated, domain.OrderStatusPaid, domain.OrderStatusFailed:
+	default:
+		return filter, fmt.Errorf("invalid status")
+	}
+
+	var err error
+	if productID := c.Query("product_id"); productID != "" {
+		if filter.ProductID, err = primitive.ObjectIDFromHex(productID); err != nil {
+			return filter, fmt.Errorf("invalid product_id")
+		}
+	}
+	if affiliateID := c.Query("affiliate_id"); affiliateID != "" {
+		if filter.AffiliateID, err = primitive.ObjectIDFromHex(affiliateID); err != nil {
+			return filter, fmt.Errorf("invalid affiliate_id")
+		}
+	}
+	if filter.From, err = parseTimeQuery(c.Query("from"), false); err != nil {
+		return filter, fmt.Errorf("invalid from: use RFC 3339 or YYYY-MM-DD")
+	}
+	if filter.To, err = parseTimeQuery(c.Query("to"), true); err != nil {
+		return filter, fmt.Errorf("invalid to: use RFC 3339 or YYYY-MM-DD")
+	}
+	if filter.MinAmount, err = parseAmountQuery(c.Query("min_amount")); err != nil {
+		return filter, fmt.Errorf("invalid min_amount")
+	}
+	if filter.MaxAmount, err = parseAmountQuery(c.Query("max_amount")); err != nil {
+		return filter, fmt.Errorf("invalid max_amount")
+	}
+	if len(filter.Search) > 100 {
+		return filter, fmt.Errorf("q must be at most 100 characters")
+	}
+	return filter, nil
+}
+
+// parseAmountQuery parses an optional non-negative amount in paise.
+func parseAmountQuery(value string) (*int64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	amount, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || amount < 0 {
+		return nil, errors.New("invalid amount")
+	}
+	return &amount, nil
+}
+
+// parseBillingDetails validates the optional GST details a buyer gives at checkout.
+func parseBillingDetails(stateCode, gstin, businessName string) (*domain.BillingDetails, error) {
+	billing := &domain.BillingDetails{
+		StateCode:    strings.TrimSpace(stateCode),
+		GSTIN:        strings.ToUpper(strings.TrimSpace(gstin)),
+		BusinessName: strings.TrimSpace(businessName),
+	}
+	if *billing == (domain.BillingDetails{}) {
+		return nil, nil
+	}
+	if billing.StateCode != "" {
+		if _, ok := domain.GSTStates[billing.StateCode]; !ok {
+			return nil, errors.New("invalid billing state")
+		}
+	}
+	if billing.GSTIN != "" {
+		if !domain.IsValidGSTIN(billing.GSTIN) {
+			return nil, errors.New("invalid GSTIN")
+		}
+		billing.StateCode = billing.GSTIN[:2]
+	}
+	if len(billing.BusinessName) > 120 {
+		return nil, errors.New("business name is too long")
+	}
+	return billing, nil
 }
@@ -30,7 +30,7 @@ func (h *PayoutHandler) SavePayoutSettings(c *fiber.Ctx) error {
 		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
 	}
 
-	config, err := h.service.SavePayoutConfig(c.Context(), creatorID, req)
+	config, err := h.service.SavePayoutConfig(c.Context(), auditActor(c), creatorID, req)
 	if err != nil {
 		if isValidationError(err) {
 			return SendError(c, fiber.StatusBadRequest, ErrBadRequest, err.Error(), nil)
@@ -90,7 +90,7 @@ func (h *PayoutHandler) WithdrawFunds(c *fiber.Ctx) error {
 		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Amount must be positive", nil)
 	}
 
-	payout, err := h.service.WithdrawFunds(c.Context(), creatorID, req.Amount)
+	payout, err := h.service.WithdrawFunds(c.Context(), auditActor(c), creatorID, req.Amount)
 	if err != nil {
 		msg := err.Error()
 		if msg == "payout already in progress" {
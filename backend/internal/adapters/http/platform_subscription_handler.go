@@ -108,6 +108,7 @@ func (h *PlatformSubscriptionHandler) GrantSubscription(c *fiber.Ctx) error {
 	var body struct {
 		CreatorID string `json:"creator_id" validate:"required"`
 		Months    int    `json:"months" validate:"required,min=1"`
+		Reason    string `json:"reason"`
 	}
 	if err := c.BodyParser(&body); err != nil {
 		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
@@ -118,7 +119,7 @@ func (h *PlatformSubscriptionHandler) GrantSubscription(c *fiber.Ctx) error {
 		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid creator ID", nil)
 	}
 
-	if err := h.service.AdminGrantSubscription(c.Context(), objID, body.Months); err != nil {
+	if err := h.service.AdminGrantSubscription(c.Context(), auditActor(c), objID, body.Months, body.Reason); err != nil {
 		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to grant subscription", err)
 	}
 
@@ -130,6 +131,7 @@ func (h *PlatformSubscriptionHandler) GrantSubscription(c *fiber.Ctx) error {
 func (h *PlatformSubscriptionHandler) RevokeSubscription(c *fiber.Ctx) error {
 	var body struct {
 		CreatorID string `json:"creator_id" validate:"required"`
+		Reason    string `json:"reason"`
 	}
 	if err := c.BodyParser(&body); err != nil {
 		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
@@ -140,7 +142,7 @@ func (h *PlatformSubscriptionHandler) RevokeSubscription(c *fiber.Ctx) error {
 		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid creator ID", nil)
 	}
 
-	if err := h.service.AdminRevokeSubscription(c.Context(), objID); err != nil {
+	if err := h.service.AdminRevokeSubscription(c.Context(), auditActor(c), objID, body.Reason); err != nil {
 		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to revoke subscription", err)
 	}
 
@@ -161,7 +163,7 @@ func (h *PlatformSubscriptionHandler) AddCreator(c *fiber.Ctx) error {
 		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Email and Name are required", nil)
 	}
 
-	user, err := h.service.AdminAddCreator(c.Context(), body.Email, body.Name)
+	user, err := h.service.AdminAddCreator(c.Context(), auditActor(c), body.Email, body.Name)
 	if err != nil {
 		if err.Error() == "user with this email already exists" {
 			return SendError(c, fiber.StatusConflict, ErrConflict, err.Error(), nil)
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/devanshbhargava/stan-store/internal/core/services"
+)
+
+// PrivacyHandler serves self-service data export and account deletion.
+type PrivacyHandler struct {
+	service *services.PrivacyService
+}
+
+// NewPrivacyHandler creates a new PrivacyHandler.
+func NewPrivacyHandler(service *services.PrivacyService) *PrivacyHandler {
+	return &PrivacyHandler{service: service}
+}
+
+// GetExport returns the user's most recent data export, or null if there is none.
+// GET /api/v1/account/export
+func (h *PrivacyHandler) GetExport(c *fiber.Ctx) error {
+	userID := c.Locals("userId").(string)
+	export, err := h.service.GetLatestExport(c.Context(), userID)
+	if err != nil {
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to fetch data export", err)
+	}
+	return SendOK(c, export)
+}
+
+// RequestExport starts building a zip of the user's data. The user is emailed when it is ready.
+// POST /api/v1/account/export
+func (h *PrivacyHandler) RequestExport(c *fiber.Ctx) error {
+	userID := c.Locals("userId").(string)
+	export, err := h.service.RequestExport(c.Context(), userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrExportInProgress):
+			return SendError(c, fiber.StatusConflict, ErrConflict, err.Error(), nil)
+		case errors.Is(err, services.ErrExportRateLimited):
+			return SendError(c, fiber.StatusTooManyRequests, ErrTooManyRequests, err.Error(), nil)
+		case errors.Is(err, services.ErrAccountDeleted):
+			return SendError(c, fiber.StatusForbidden, ErrAccountDeleted, err.Error(), nil)
+		}
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to request data export", err)
+	}
+	return SendSuccess(c, fiber.StatusAccepted, export, nil)
+}
+
+// DownloadExport returns a short-lived link to a finished export. Admins impersonating the
+// account can't download it.
+// GET /api/v1/account/export/:id/download
+func (h *PrivacyHandler) DownloadExport(c *fiber.Ctx) error {
+	if adminID, _ := c.Locals("impersonatorId").(string); adminID != "" {
+		return SendError(c, fiber.StatusForbidden, ErrImpersonationReadOnly, "Data exports can't be downloaded while impersonating", nil)
+	}
+
+	userID := c.Locals("userId").(string)
+	url, err := h.service.DownloadURL(c.Context(), userID, c.Params("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrExportNotFound):
+			return SendError(c, fiber.StatusNotFound, ErrNotFound, err.Error(), nil)
+		case errors.Is(err, services.ErrExportNotReady):
+			return SendError(c, fiber.StatusConflict, ErrConflict, err.Error(), nil)
+		}
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to generate download link", err)
+	}
+	return SendOK(c, fiber.Map{"url": url})
+}
+
+// RequestDeletion schedules the account for deletion after the cooling-off period.
+// POST /api/v1/account/deletion
+func (h *PrivacyHandler) RequestDeletion(c *fiber.Ctx) error {
+	userID := c.Locals("userId").(string)
+	user, err := h.service.RequestDeletion(c.Context(), auditActor(c), userID)
+	if err != nil {
+		return h.deletionError(c, err)
+	}
+	return SendOK(c, fiber.Map{
+		"deletionRequestedAt":  user.DeletionRequestedAt,
+		"deletionScheduledFor": user.DeletionScheduledFor,
+	})
+}
+
+// CancelDeletion withdraws a pending deletion request.
+// DELETE /api/v1/account/deletion
+func (h *PrivacyHandler) CancelDeletion(c *fiber.Ctx) error {
+	userID := c.Locals("userId").(string)
+	if _, err := h.service.CancelDeletion(c.Context(), auditActor(c), userID); err != nil {
+		return h.deletionError(c, err)
+	}
+	return SendOK(c, fiber.Map{"message": "Account deletion cancelled"})
+}
+
+func (h *PrivacyHandler) deletionError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrDeletionScheduled), errors.Is(err, services.ErrNoDeletionPending),
+		errors.Is(err, services.ErrDeletionBlocked):
+		return SendError(c, fiber.StatusConflict, ErrConflict, err.Error(), nil)
+	case errors.Is(err, services.ErrAdminSelfDeletion):
+		return SendError(c, fiber.StatusForbidden, ErrForbidden, err.Error(), nil)
+	case errors.Is(err, services.ErrAccountDeleted):
+		return SendError(c, fiber.StatusForbidden, ErrAccountDeleted, err.Error(), nil)
+	}
+	return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to update account deletion", err)
+}
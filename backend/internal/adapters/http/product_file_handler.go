@@ -0,0 +1,95 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/internal/core/services"
+)
+
+// AddFile handles POST /api/v1/products/:id/files.
+func (h *ProductHandler) AddFile(c *fiber.Ctx) error {
+	var req domain.AddProductFileRequest
+	if err := c.BodyParser(&req); err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
+	}
+
+	product, err := h.service.AddFile(c.Context(), c.Params("id"), c.Locals("userId").(string), &req)
+	if err != nil {
+		return sendProductFileError(c, err)
+	}
+	return SendCreated(c, product)
+}
+
+// UpdateFile handles PUT /api/v1/products/:id/files/:fileId.
+func (h *ProductHandler) UpdateFile(c *fiber.Ctx) error {
+	var req domain.UpdateProductFileRequest
+	if err := c.BodyParser(&req); err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
+	}
+
+	product, err := h.service.UpdateFile(c.Context(), c.Params("id"), c.Locals("userId").(string), c.Params("fileId"), &req)
+	if err != nil {
+		return sendProductFileError(c, err)
+	}
+	return SendOK(c, product)
+}
+
+// ReplaceFile handles POST /api/v1/products/:id/files/:fileId/versions.
+func (h *ProductHandler) ReplaceFile(c *fiber.Ctx) error {
+	var req domain.ReplaceProductFileRequest
+	if err := c.BodyParser(&req); err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
+	}
+
+	product, err := h.service.ReplaceFile(c.Context(), c.Params("id"), c.Locals("userId").(string), c.Params("fileId"), &req)
+	if err != nil {
+		return sendProductFileError(c, err)
+	}
+	return SendCreated(c, product)
+}
+
+// RemoveFile handles DELETE /api/v1/products/:id/files/:fileId.
+func (h *ProductHandler) RemoveFile(c *fiber.Ctx) error {
+	product, err := h.service.RemoveFile(c.Context(), c.Params("id"), c.Locals("userId").(string), c.Params("fileId"))
+	if err != nil {
+		return sendProductFileError(c, err)
+	}
+	return SendOK(c, product)
+}
+
+// ReorderFiles handles PATCH /api/v1/products/:id/files/reorder.
+func (h *ProductHandler) ReorderFiles(c *fiber.Ctx) error {
+	var req domain.ReorderFilesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
+	}
+
+	product, err := h.service.ReorderFiles(c.Context(), c.Params("id"), c.Locals("userId").(string), req.FileIDs)
+	if err != nil {
+		return sendProductFileError(c, err)
+	}
+	return SendOK(c, product)
+}
+
+func sendProductFileError(c *fiber.Ctx, err error) error {
+	switch {
+	case err.Error() == "unauthorized: you do not own this product":
+		return SendError(c, fiber.StatusForbidden, ErrForbidden, err.Error(), nil)
+	case err.Error() == "product not found", errors.Is(err, services.ErrProductFileNotFound):
+		return SendError(c, fiber.StatusNotFound, ErrNotFound, err.Error(), nil)
+	case errors.Is(err, services.ErrUploadUnverified):
+		return SendError(c, fiber.StatusUnprocessableEntity, ErrValidation, err.Error(), nil)
+	case err.Error() == "invalid product ID",
+		errors.Is(err, services.ErrFileLabelRequired),
+		errors.Is(err, services.ErrFileLabelTooLong),
+		errors.Is(err, services.ErrTooManyFiles),
+		errors.Is(err, services.ErrLastProductFile),
+		errors.Is(err, services.ErrFileOrderMismatch),
+		errors.Is(err, services.ErrFileUnchanged):
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, err.Error(), nil)
+	}
+	return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to update product files", err)
+}
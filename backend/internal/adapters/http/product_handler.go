@@ -31,6 +31,10 @@ type CreateProductDTO struct {
 	DurationMinutes         int                         `json:"duration_minutes,omitempty"`
 	Timezone                string                      `json:"timezone,omitempty"`
 	CancellationWindowHours int                         `json:"cancellation_window_hours,omitempty"`
+	SeatsPerSlot            int                         `json:"seats_per_slot,omitempty"`
+	DownloadLimit           int                         `json:"download_limit,omitempty"`
+	DownloadExpiryDays      int                         `json:"download_expiry_days,omitempty"`
+	StampPDF                bool                        `json:"stamp_pdf,omitempty"`
 	Availability              []domain.AvailabilityWindow `json:"availability,omitempty"`
 	SubscriptionInterval      string                      `json:"subscription_interval,omitempty"`
 	SubscriptionBillingCycles int                         `json:"subscription_billing_cycles,omitempty"`
@@ -47,6 +51,9 @@ type UpdateProductDTO struct {
 	DurationMinutes         int                         `json:"duration_minutes,omitempty"`
 	Timezone                string                      `json:"timezone,omitempty"`
 	CancellationWindowHours int                         `json:"cancellation_window_hours,omitempty"`
+	SeatsPerSlot            int                         `json:"seats_per_slot,omitempty"`
+	DownloadLimit           int                         `json:"download_limit,omitempty"`
+	DownloadExpiryDays      int                         `json:"download_expiry_days,omitempty"`
 	Availability              []domain.AvailabilityWindow `json:"availability,omitempty"`
 	SubscriptionInterval      string                      `json:"subscription_interval,omitempty"`
 	SubscriptionBillingCycles int                         `json:"subscription_billing_cycles,omitempty"`
@@ -92,6 +99,10 @@ func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
 		DurationMinutes:           req.DurationMinutes,
 		Timezone:                  req.Timezone,
 		CancellationWindowHours:   req.CancellationWindowHours,
+		SeatsPerSlot:              req.SeatsPerSlot,
+		DownloadLimit:             req.DownloadLimit,
+		DownloadExpiryDays:        req.DownloadExpiryDays,
+		StampPDF:                  req.StampPDF,
 		Availability:              req.Availability,
 		SubscriptionInterval:      req.SubscriptionInterval,
 		SubscriptionBillingCycles: req.SubscriptionBillingCycles,
@@ -184,6 +195,15 @@ func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
 	if req.CancellationWindowHours != 0 {
 		updateData.CancellationWindowHours = req.CancellationWindowHours
 	}
+	if req.SeatsPerSlot != 0 {
+		updateData.SeatsPerSlot = req.SeatsPerSlot
+	}
+	if req.DownloadLimit != 0 {
+		updateData.DownloadLimit = req.DownloadLimit
+	}
+	if req.DownloadExpiryDays != 0 {
+		updateData.DownloadExpiryDays = req.DownloadExpiryDays
+	}
 	if len(req.Availability) > 0 {
 		updateData.Availability = req.Availability
 	}
@@ -305,6 +325,37 @@ func (h *ProductHandler) ReorderProducts(c *fiber.Ctx) error {
 	return SendSuccess(c, fiber.StatusOK, map[string]string{"message": "Products reordered successfully"}, nil)
 }
 
+// UpdatePDFStampingDTO defines the request body for toggling PDF stamping.
+type UpdatePDFStampingDTO struct {
+	Enabled bool `json:"enabled"`
+}
+
+// UpdatePDFStamping handles PUT /api/v1/products/:id/stamping.
+func (h *ProductHandler) UpdatePDFStamping(c *fiber.Ctx) error {
+	productID := c.Params("id")
+	userID := c.Locals("userId").(string)
+
+	var req UpdatePDFStampingDTO
+	if err := c.BodyParser(&req); err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
+	}
+
+	product, err := h.service.UpdatePDFStamping(c.Context(), productID, userID, req.Enabled)
+	if err != nil {
+		status := fiber.StatusInternalServerError
+		if err.Error() == "unauthorized: you do not own this product" {
+			status = fiber.StatusForbidden
+		} else if err.Error() == "product not found" {
+			status = fiber.StatusNotFound
+		} else if err.Error() == "invalid product ID" || err.Error() == "stamping is only available for products with a PDF file" {
+			status = fiber.StatusBadRequest
+		}
+		return SendError(c, status, "UPDATE_STAMPING_FAILED", err.Error(), nil)
+	}
+
+	return SendOK(c, product)
+}
+
 // UpdateBumpConfig defines the request body for updating a product's bump configuration.
 type UpdateBumpConfigDTO struct {
 	BumpProductID string `json:"bump_product_id"` // Empty string removes the bump
@@ -31,6 +31,9 @@ const (
 	ErrAccountBanned       = "ERR_ACCOUNT_BANNED"
 	ErrStoreBanned         = "ERR_STORE_BANNED"
 	ErrSubscriptionRequired = "ERR_SUBSCRIPTION_REQUIRED"
+	ErrStepUpRequired       = "ERR_STEP_UP_REQUIRED"
+	ErrImpersonationReadOnly = "ERR_IMPERSONATION_READ_ONLY"
+	ErrAccountDeleted        = "ERR_ACCOUNT_DELETED"
 )
 
 // SendSuccess sends a successful response with the standardized envelope.
@@ -17,9 +17,16 @@ import (
 type RouterDeps struct {
 	FrontendURL           string
 	JWTService            *services.JWTService
+	SessionService        *services.SessionService // Nil when Redis is unavailable; tokens are then not session-bound
 	UserRepo              domain.UserRepository
 	PlatformSubRepo       domain.PlatformSubscriptionRepository
 	AuthHandler           *AuthHandler
+	TwoFactorHandler      *TwoFactorHandler // Nil when Redis is unavailable
+	TeamService           *services.TeamService
+	TeamHandler           *TeamHandler
+	APIKeyService         *services.APIKeyService
+	APIKeyHandler         *APIKeyHandler
+	CreatorWebhookHandler *CreatorWebhookHandler
 	UsernameHandler       *UsernameHandler
 	ProfileHandler        *ProfileHandler
 	ProductHandler        *ProductHandler
@@ -29,12 +36,20 @@ type RouterDeps struct {
 	OrderHandler          *OrderHandler
 	WalletHandler         *WalletHandler
 	AdminHandler          *AdminHandler
+	AuditHandler          *AuditHandler
+	ImpersonationService  *services.ImpersonationService // Nil when Redis is unavailable
+	ImpersonationHandler  *ImpersonationHandler
+	PrivacyHandler        *PrivacyHandler
 	BuyerHandler          *BuyerHandler
 	PayoutHandler         *PayoutHandler
 	SubscriberHandler     *SubscriberHandler
 	CouponHandler         *CouponHandler
 	BookingHandler        *BookingHandler
 	CourseHandler         *CourseHandler
+	CertificateHandler    *CertificateHandler
+	InvoiceHandler        *InvoiceHandler
+	DownloadHandler       *DownloadHandler
+	VideoHandler          *VideoHandler
 	AIHandler             *AIHandler
 	EmailTemplateHandler  *EmailTemplateHandler
 	CampaignHandler       *CampaignHandler
@@ -47,6 +62,7 @@ type RouterDeps struct {
 	BlogHandler           *BlogHandler
 	PlatformSubHandler    *PlatformSubscriptionHandler
 	PlatformReferralHandler *PlatformReferralHandler
+	LocalStorageHandler   *LocalStorageHandler // Only set when STORAGE_DRIVER=local
 	WorkerService         *services.WorkerService
 }
 
@@ -56,22 +72,51 @@ func SetupRouter(app *fiber.App, deps *RouterDeps) {
 	app.Use(RequestID())
 	app.Use(Recovery())
 	app.Use(RequestLogger())
+	app.Use(BodyLimit(fiber.DefaultBodyLimit, "/api/v1/storage/"))
+	if deps.ImpersonationService != nil {
+		app.Use(ImpersonationAudit(deps.ImpersonationService))
+	}
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     deps.FrontendURL,
 		AllowMethods:     "GET,POST,PUT,DELETE,PATCH,OPTIONS",
-		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,X-Request-Id,X-Razorpay-Signature",
+		AllowHeaders:     "Origin,Content-Type,Accept,Authorization,X-Request-Id,X-Razorpay-Signature,X-Creator-Id",
+		ExposeHeaders:    "X-RateLimit-Limit,X-RateLimit-Remaining,X-RateLimit-Reset,Retry-After",
 		AllowCredentials: true,
 	}))
 
 	// Auth middleware (reusable)
-	authRequired := AuthRequired(deps.JWTService)
+	authRequired := AuthRequired(deps.JWTService, deps.SessionService)
 	banCheck := BanCheck(deps.UserRepo)
 	subscriptionCheck := SubscriptionRequired(deps.PlatformSubRepo)
 
+	// Team members act on a creator's account (X-Creator-Id) only on routes wrapped in team;
+	// everything else, such as payouts changes and integrations, stays owner-only
+	team := func(areas ...string) fiber.Handler {
+		if deps.TeamService == nil {
+			return func(c *fiber.Ctx) error { return c.Next() }
+		}
+		return TeamAccess(deps.TeamService, deps.UserRepo, areas...)
+	}
+
+	// Routes wrapped in apiAuth also accept creator API keys holding the given scopes (read
+	// scope for GET, write scope otherwise); all other routes need a browser session
+	apiAuth := func(readScope, writeScope string) fiber.Handler {
+		if deps.APIKeyService == nil {
+			return authRequired
+		}
+		return APIKeyAuth(deps.APIKeyService, authRequired, readScope, writeScope)
+	}
+
 	// API v1 routes
 	v1 := app.Group("/api/v1")
 	v1.Get("/health", HealthHandler())
 
+	// Local object storage (development only; authorised by presigned URL signatures)
+	if deps.LocalStorageHandler != nil {
+		v1.Put("/storage/*", deps.LocalStorageHandler.PutObject)
+		v1.Get("/storage/*", deps.LocalStorageHandler.GetObject)
+	}
+
 	// Generalized limit reached handler for 429
 	limitReachedHandler := func(c *fiber.Ctx) error {
 		c.Set("Retry-After", "60")
@@ -114,6 +159,58 @@ func SetupRouter(app *fiber.App, deps *RouterDeps) {
 		Expiration:   1 * time.Minute,
 		LimitReached: limitReachedHandler,
 	}), deps.AuthHandler.CreatorLogin)
+	creatorAuth.Post("/resend-otp", limiter.New(limiter.Config{
+		Max:          3,
+		Expiration:   1 * time.Minute,
+		LimitReached: limitReachedHandler,
+	}), deps.AuthHandler.CreatorResendOTP)
+
+	// Password reset (public, rate limited)
+	passwordLimit := limiter.New(limiter.Config{
+		Max:          5,
+		Expiration:   1 * time.Minute,
+		LimitReached: limitReachedHandler,
+	})
+	auth.Post("/password/forgot", passwordLimit, deps.AuthHandler.ForgotPassword)
+	auth.Post("/password/reset", passwordLimit, deps.AuthHandler.ResetPassword)
+	auth.Post("/password/change", authRequired, banCheck, passwordLimit, deps.AuthHandler.ChangePassword)
+
+	// Session renewal and logout (public: they authenticate with the refresh token, since the
+	// access token may already have expired)
+	auth.Post("/refresh", limiter.New(limiter.Config{
+		Max:          30,
+		Expiration:   1 * time.Minute,
+		LimitReached: limitReachedHandler,
+	}), deps.AuthHandler.Refresh)
+	auth.Post("/logout", deps.AuthHandler.Logout)
+
+	// Step-up re-authentication guards sensitive actions; it needs the two-factor service
+	stepUp := func(c *fiber.Ctx) error { return c.Next() }
+	if deps.TwoFactorHandler != nil {
+		stepUp = StepUpRequired(deps.TwoFactorHandler.authService)
+
+		// Second login step (public, rate limited; authenticated by the challenge token)
+		twoFactorLimit := limiter.New(limiter.Config{
+			Max:          10,
+			Expiration:   1 * time.Minute,
+			LimitReached: limitReachedHandler,
+		})
+		auth.Post("/2fa/verify", twoFactorLimit, deps.TwoFactorHandler.VerifyLogin)
+		auth.Post("/2fa/login-setup", twoFactorLimit, deps.TwoFactorHandler.BeginLoginSetup)
+
+		// Enrolment and step-up (protected)
+		auth.Get("/2fa", authRequired, banCheck, deps.TwoFactorHandler.GetStatus)
+		auth.Post("/2fa/setup", authRequired, banCheck, deps.TwoFactorHandler.BeginSetup)
+		auth.Post("/2fa/enable", authRequired, banCheck, twoFactorLimit, deps.TwoFactorHandler.Enable)
+		auth.Post("/2fa/disable", authRequired, banCheck, twoFactorLimit, deps.TwoFactorHandler.Disable)
+		auth.Post("/2fa/recovery-codes", authRequired, banCheck, twoFactorLimit, deps.TwoFactorHandler.RegenerateRecoveryCodes)
+		auth.Post("/step-up", authRequired, banCheck, twoFactorLimit, deps.TwoFactorHandler.StepUp)
+		auth.Post("/step-up/email", authRequired, banCheck, limiter.New(limiter.Config{
+			Max:          3,
+			Expiration:   5 * time.Minute,
+			LimitReached: limitReachedHandler,
+		}), deps.TwoFactorHandler.RequestStepUpCode)
+	}
 
 	// Buyer functionality routes (protected)
 	buyer := v1.Group("/buyer")
@@ -121,19 +218,34 @@ func SetupRouter(app *fiber.App, deps *RouterDeps) {
 
 	// Auth routes (protected)
 	auth.Get("/me", authRequired, banCheck, deps.AuthHandler.GetMe)
-	auth.Post("/logout", authRequired, deps.AuthHandler.Logout)
+	if deps.ImpersonationHandler != nil {
+		auth.Post("/impersonation/stop", authRequired, deps.ImpersonationHandler.Stop)
+	}
+	auth.Get("/sessions", authRequired, deps.AuthHandler.GetSessions)
+	auth.Delete("/sessions", authRequired, deps.AuthHandler.RevokeAllSessions)
+	auth.Delete("/sessions/:id", authRequired, deps.AuthHandler.RevokeSession)
 	auth.Post("/username", authRequired, banCheck, deps.UsernameHandler.ClaimUsername)
 
+	// Personal data export and account deletion (buyers and creators)
+	if deps.PrivacyHandler != nil {
+		account := v1.Group("/account", authRequired, banCheck)
+		account.Get("/export", deps.PrivacyHandler.GetExport)
+		account.Post("/export", deps.PrivacyHandler.RequestExport)
+		account.Get("/export/:id/download", deps.PrivacyHandler.DownloadExport)
+		account.Post("/deletion", stepUp, deps.PrivacyHandler.RequestDeletion)
+		account.Delete("/deletion", deps.PrivacyHandler.CancelDeletion)
+	}
+
 	// Platform Subscription routes (protected - but NOT gated by subscription check itself)
 	platformSub := v1.Group("/platform")
 	platformSub.Get("/subscription", authRequired, banCheck, deps.PlatformSubHandler.GetMySubscription)
 
 	// Creator routes (protected + subscription required)
 	creator := v1.Group("/creator")
-	creator.Get("/profile", authRequired, banCheck, subscriptionCheck, deps.ProfileHandler.GetProfile)
-	creator.Put("/profile", authRequired, banCheck, subscriptionCheck, deps.ProfileHandler.UpdateProfile)
-	creator.Post("/payout-settings", authRequired, banCheck, subscriptionCheck, deps.PayoutHandler.SavePayoutSettings)
-	creator.Get("/payout-settings", authRequired, banCheck, subscriptionCheck, deps.PayoutHandler.GetPayoutSettings)
+	creator.Get("/profile", authRequired, team(domain.TeamAreaProducts), banCheck, subscriptionCheck, deps.ProfileHandler.GetProfile)
+	creator.Put("/profile", authRequired, team(domain.TeamAreaProducts), banCheck, subscriptionCheck, deps.ProfileHandler.UpdateProfile)
+	creator.Post("/payout-settings", authRequired, banCheck, subscriptionCheck, stepUp, deps.PayoutHandler.SavePayoutSettings)
+	creator.Get("/payout-settings", authRequired, team(domain.TeamAreaFinance), banCheck, subscriptionCheck, deps.PayoutHandler.GetPayoutSettings)
 
 	// Concurrency limiter for withdrawals
 	var withdrawMu sync.Map
@@ -149,29 +261,76 @@ func SetupRouter(app *fiber.App, deps *RouterDeps) {
 		defer withdrawMu.Delete(uid)
 		return c.Next()
 	}
-	creator.Post("/payouts/withdraw", authRequired, banCheck, preventConcurrentWithdrawals, deps.PayoutHandler.WithdrawFunds)
-
-	creator.Get("/payouts", authRequired, banCheck, deps.PayoutHandler.GetPayoutHistory)
-	creator.Get("/payouts/balance", authRequired, banCheck, deps.PayoutHandler.GetBalance)
-	creator.Get("/subscribers", authRequired, banCheck, deps.SubscriberHandler.GetSubscribers)
+	creator.Post("/payouts/withdraw", authRequired, banCheck, stepUp, preventConcurrentWithdrawals, deps.PayoutHandler.WithdrawFunds)
+
+	creator.Get("/payouts", authRequired, team(domain.TeamAreaFinance), banCheck, deps.PayoutHandler.GetPayoutHistory)
+	creator.Get("/payouts/balance", authRequired, team(domain.TeamAreaFinance), banCheck, deps.PayoutHandler.GetBalance)
+	subscribersAuth := apiAuth(domain.APIScopeSubscribersRead, domain.APIScopeSubscribersWrite)
+	creator.Get("/subscribers", subscribersAuth, team(domain.TeamAreaMarketing), banCheck, deps.SubscriberHandler.GetSubscribers)
+	creator.Post("/subscribers", subscribersAuth, team(domain.TeamAreaMarketing), banCheck, deps.SubscriberHandler.AddSubscriber)
+	creator.Post("/subscribers/unsubscribe", subscribersAuth, team(domain.TeamAreaMarketing), banCheck, deps.SubscriberHandler.Unsubscribe)
 	if deps.NewsletterHandler != nil {
-		creator.Post("/newsletter", authRequired, banCheck, deps.NewsletterHandler.SendNewsletter)
+		creator.Post("/newsletter", authRequired, team(domain.TeamAreaMarketing), banCheck, deps.NewsletterHandler.SendNewsletter)
 	}
-	creator.Get("/analytics", authRequired, banCheck, deps.AnalyticsHandler.GetDashboardMetrics)
+	creator.Get("/analytics", authRequired, team(domain.TeamAreaMarketing, domain.TeamAreaFinance), banCheck, deps.AnalyticsHandler.GetDashboardMetrics)
 	if deps.PlatformReferralHandler != nil {
 		creator.Get("/referrals", authRequired, banCheck, deps.PlatformReferralHandler.GetMyReferrals)
 	}
 
-	creator.Get("/email-templates/:type", authRequired, banCheck, deps.EmailTemplateHandler.GetTemplate)
-	creator.Put("/email-templates/:type", authRequired, banCheck, deps.EmailTemplateHandler.UpdateTemplate)
+	// Team management (owner only)
+	if deps.TeamHandler != nil {
+		creator.Get("/team", authRequired, banCheck, RoleRequired(domain.RoleCreator), deps.TeamHandler.ListMembers)
+		creator.Post("/team", authRequired, banCheck, RoleRequired(domain.RoleCreator), stepUp, deps.TeamHandler.Invite)
+		creator.Get("/team/activity", authRequired, banCheck, RoleRequired(domain.RoleCreator), deps.TeamHandler.GetActivity)
+		creator.Patch("/team/:id", authRequired, banCheck, RoleRequired(domain.RoleCreator), stepUp, deps.TeamHandler.UpdateRoles)
+		creator.Delete("/team/:id", authRequired, banCheck, RoleRequired(domain.RoleCreator), deps.TeamHandler.RemoveMember)
+
+		// Accounts the signed-in user works on as a team member
+		teamAccounts := v1.Group("/team", authRequired, banCheck)
+		teamAccounts.Get("/accounts", deps.TeamHandler.ListAccounts)
+		teamAccounts.Delete("/accounts/:creatorId", deps.TeamHandler.Leave)
+		teamAccounts.Post("/invitations/accept", deps.TeamHandler.AcceptInvite)
+	}
+
+	// API keys (owner only; keys can't manage keys)
+	if deps.APIKeyHandler != nil {
+		creator.Get("/api-keys", authRequired, banCheck, RoleRequired(domain.RoleCreator), deps.APIKeyHandler.ListKeys)
+		creator.Post("/api-keys", authRequired, banCheck, RoleRequired(domain.RoleCreator), stepUp, deps.APIKeyHandler.CreateKey)
+		creator.Delete("/api-keys/:id", authRequired, banCheck, RoleRequired(domain.RoleCreator), deps.APIKeyHandler.RevokeKey)
+	}
+
+	// Outbound webhooks (owner only; creating an endpoint or rotating its secret needs step-up)
+	if deps.CreatorWebhookHandler != nil {
+		webhooks := creator.Group("/webhooks", authRequired, banCheck, RoleRequired(domain.RoleCreator))
+		webhooks.Get("/events", deps.CreatorWebhookHandler.ListEventTypes)
+		webhooks.Get("/", deps.CreatorWebhookHandler.ListEndpoints)
+		webhooks.Post("/", stepUp, deps.CreatorWebhookHandler.CreateEndpoint)
+		webhooks.Post("/deliveries/:deliveryId/redeliver", deps.CreatorWebhookHandler.Redeliver)
+		webhooks.Patch("/:id", deps.CreatorWebhookHandler.UpdateEndpoint)
+		webhooks.Delete("/:id", deps.CreatorWebhookHandler.DeleteEndpoint)
+		webhooks.Post("/:id/rotate-secret", stepUp, deps.CreatorWebhookHandler.RotateSecret)
+		webhooks.Get("/:id/deliveries", deps.CreatorWebhookHandler.ListDeliveries)
+	}
+
+	// GST invoicing (finance team members can read; only the owner changes business details)
+	if deps.InvoiceHandler != nil {
+		creator.Get("/invoice-settings", authRequired, team(domain.TeamAreaFinance), banCheck, deps.InvoiceHandler.GetSettings)
+		creator.Put("/invoice-settings", authRequired, banCheck, RoleRequired(domain.RoleCreator), deps.InvoiceHandler.UpdateSettings)
+		creator.Get("/orders/:id/invoice", authRequired, team(domain.TeamAreaOrders, domain.TeamAreaFinance), banCheck, deps.InvoiceHandler.GetCreatorInvoice)
+		creator.Get("/tax-summary", authRequired, team(domain.TeamAreaFinance), banCheck, deps.InvoiceHandler.GetTaxSummary)
+		creator.Get("/tax-summary/export", authRequired, team(domain.TeamAreaFinance), banCheck, deps.InvoiceHandler.ExportTaxSummary)
+	}
 
-	creator.Post("/campaigns", authRequired, banCheck, deps.CampaignHandler.CreateCampaign)
-	creator.Get("/campaigns", authRequired, banCheck, deps.CampaignHandler.GetCampaigns)
-	creator.Patch("/campaigns/:id", authRequired, banCheck, deps.CampaignHandler.UpdateCampaignStatus)
+	creator.Get("/email-templates/:type", authRequired, team(domain.TeamAreaMarketing), banCheck, deps.EmailTemplateHandler.GetTemplate)
+	creator.Put("/email-templates/:type", authRequired, team(domain.TeamAreaMarketing), banCheck, deps.EmailTemplateHandler.UpdateTemplate)
+
+	creator.Post("/campaigns", authRequired, team(domain.TeamAreaMarketing), banCheck, deps.CampaignHandler.CreateCampaign)
+	creator.Get("/campaigns", authRequired, team(domain.TeamAreaMarketing), banCheck, deps.CampaignHandler.GetCampaigns)
+	creator.Patch("/campaigns/:id", authRequired, team(domain.TeamAreaMarketing), banCheck, deps.CampaignHandler.UpdateCampaignStatus)
 
 	// Instagram Creator Automations (protected)
-	creator.Get("/automations/instagram", authRequired, banCheck, deps.InstagramHandler.GetAutomations)
-	creator.Post("/automations/instagram", authRequired, banCheck, limiter.New(limiter.Config{
+	creator.Get("/automations/instagram", authRequired, team(domain.TeamAreaMarketing), banCheck, deps.InstagramHandler.GetAutomations)
+	creator.Post("/automations/instagram", authRequired, team(domain.TeamAreaMarketing), banCheck, limiter.New(limiter.Config{
 		Max:          50,
 		Expiration:   24 * time.Hour,
 		LimitReached: limitReachedHandler,
@@ -182,7 +341,7 @@ func SetupRouter(app *fiber.App, deps *RouterDeps) {
 			return c.IP()
 		},
 	}), deps.InstagramHandler.CreateAutomation)
-	creator.Delete("/automations/instagram/:id", authRequired, banCheck, deps.InstagramHandler.DeleteAutomation)
+	creator.Delete("/automations/instagram/:id", authRequired, team(domain.TeamAreaMarketing), banCheck, deps.InstagramHandler.DeleteAutomation)
 
 	// Affiliate Protected Routes
 	creator.Post("/products/:id/affiliates", deps.AffiliateHandler.EnableAffiliate)
@@ -194,7 +353,7 @@ func SetupRouter(app *fiber.App, deps *RouterDeps) {
 	creator.Post("/affiliates/manual-grant", deps.AffiliateHandler.ManualGrantAffiliate)
 
 	// Coupon routes (protected)
-	coupons := v1.Group("/coupons", authRequired, banCheck)
+	coupons := v1.Group("/coupons", apiAuth(domain.APIScopeCouponsRead, domain.APIScopeCouponsWrite), team(domain.TeamAreaMarketing), banCheck)
 	coupons.Post("/", deps.CouponHandler.CreateCoupon)
 	coupons.Get("/", deps.CouponHandler.GetCoupons)
 	coupons.Patch("/:id", deps.CouponHandler.UpdateCoupon)
@@ -213,18 +372,31 @@ func SetupRouter(app *fiber.App, deps *RouterDeps) {
 	// Course structure route (requires auth to check if buyer actually bought it)
 	v1.Get("/products/:id/course", authRequired, banCheck, deps.CourseHandler.GetCourse)
 
+	// Certificate verification (public - linked from issued certificates)
+	v1.Get("/certificates/:verificationId", limiter.New(limiter.Config{
+		Max:          30,
+		Expiration:   1 * time.Minute,
+		LimitReached: limitReachedHandler,
+	}), deps.CertificateHandler.VerifyCertificate)
+
 	// Public Testimonials route (called from storefront)
 	v1.Get("/products/:id/testimonials", deps.TestimonialHandler.GetPublic)
 
 	// Product routes (protected)
 	products := v1.Group("/products")
-	products.Use(authRequired, banCheck)
+	products.Use(apiAuth(domain.APIScopeProductsRead, domain.APIScopeProductsWrite), team(domain.TeamAreaProducts), banCheck)
 	products.Post("/", deps.ProductHandler.CreateProduct)
 	products.Get("/", deps.ProductHandler.GetProducts)
 	products.Put("/:id", deps.ProductHandler.UpdateProduct)
 	products.Delete("/:id", deps.ProductHandler.DeleteProduct)
 	products.Patch("/:id/visibility", deps.ProductHandler.UpdateVisibility)
 	products.Put("/:id/bump", deps.ProductHandler.UpdateBumpConfig)
+	products.Put("/:id/stamping", deps.ProductHandler.UpdatePDFStamping)
+	products.Post("/:id/files", deps.ProductHandler.AddFile)
+	products.Patch("/:id/files/reorder", deps.ProductHandler.ReorderFiles)
+	products.Put("/:id/files/:fileId", deps.ProductHandler.UpdateFile)
+	products.Delete("/:id/files/:fileId", deps.ProductHandler.RemoveFile)
+	products.Post("/:id/files/:fileId/versions", deps.ProductHandler.ReplaceFile)
 	products.Patch("/reorder", deps.ProductHandler.ReorderProducts)
 
 	// Protected testimonial sub-routes
@@ -276,15 +448,19 @@ func SetupRouter(app *fiber.App, deps *RouterDeps) {
 	products.Put("/:id/course/modules/:modId/lessons/:lesId", deps.CourseHandler.UpdateLesson)
 	products.Delete("/:id/course/modules/:modId/lessons/:lesId", deps.CourseHandler.DeleteLesson)
 	products.Put("/:id/course/reorder", deps.CourseHandler.ReorderStructure)
+	products.Get("/:id/course/analytics", deps.CourseHandler.GetCourseAnalytics)
+	products.Put("/:id/course/certificate", deps.CertificateHandler.UpdateTemplate)
 
 	// Booking routes (protected)
-	bookings := v1.Group("/bookings", authRequired, banCheck)
+	bookings := v1.Group("/bookings", authRequired, team(domain.TeamAreaOrders), banCheck)
 	bookings.Post("/:id/cancel", deps.BookingHandler.CancelBooking)
+	bookings.Post("/:id/no-show", RoleRequired(domain.RoleCreator), deps.BookingHandler.MarkNoShow)
 
 	// Upload routes (protected)
 	uploads := v1.Group("/uploads")
-	uploads.Use(authRequired, banCheck)
+	uploads.Use(authRequired, team(domain.TeamAreaProducts, domain.TeamAreaMarketing), banCheck)
 	uploads.Post("/presigned", deps.UploadHandler.GeneratePresignedURL)
+	uploads.Post("/complete", deps.UploadHandler.CompleteUpload)
 
 	// Payment routes (protected/webhook)
 	payments := v1.Group("/payments")
@@ -311,11 +487,23 @@ func SetupRouter(app *fiber.App, deps *RouterDeps) {
 		},
 	}), deps.OrderHandler.CreateOrder)
 	orders.Get("/:id", deps.OrderHandler.GetOrder)
+	// Files need the signed token from the order link; signed-in buyers use /buyer/orders
+	orders.Get("/:id/files", deps.OrderHandler.GetOrderFiles)
 	orders.Get("/:id/download", deps.OrderHandler.DownloadOrder)
 
+	// Signed download links from confirmation emails (Public)
+	v1.Get("/downloads/:token", limiter.New(limiter.Config{
+		Max:          30,
+		Expiration:   1 * time.Minute,
+		LimitReached: limitReachedHandler,
+	}), deps.DownloadHandler.Redeem)
+
+	// Signed HLS playlists for lesson videos (Public; the token identifies the viewer)
+	v1.Get("/videos/:token/:playlist", deps.VideoHandler.GetPlaylist)
+
 	// AI routes (Protected)
 	if deps.AIHandler != nil {
-		v1.Post("/ai/generate-copy", authRequired, banCheck, limiter.New(limiter.Config{
+		v1.Post("/ai/generate-copy", authRequired, team(domain.TeamAreaProducts, domain.TeamAreaMarketing), banCheck, limiter.New(limiter.Config{
 			Max:          10,
 			Expiration:   24 * time.Hour,
 			LimitReached: limitReachedHandler,
@@ -330,11 +518,15 @@ func SetupRouter(app *fiber.App, deps *RouterDeps) {
 
 	// Sales routes (Creator - Protected)
 	sales := v1.Group("/sales")
-	sales.Get("/", authRequired, banCheck, deps.OrderHandler.GetSalesHistory)
+	sales.Get("/", apiAuth(domain.APIScopeOrdersRead, ""), team(domain.TeamAreaOrders), banCheck, deps.OrderHandler.GetSalesHistory)
+	sales.Get("/downloads", apiAuth(domain.APIScopeOrdersRead, ""), team(domain.TeamAreaOrders), banCheck, deps.DownloadHandler.GetLogs)
+	sales.Post("/downloads/:entitlementId/unlock", authRequired, team(domain.TeamAreaOrders), banCheck, deps.DownloadHandler.Unlock)
+	// Attendee lists hold buyer details, so they need order access rather than product access
+	sales.Get("/attendees/:id", apiAuth(domain.APIScopeOrdersRead, ""), team(domain.TeamAreaOrders), banCheck, deps.BookingHandler.GetSlotAttendees)
 
 	// Wallet routes (Creator - Protected)
 	wallet := v1.Group("/wallet")
-	wallet.Get("/", authRequired, banCheck, deps.WalletHandler.GetWalletDetails)
+	wallet.Get("/", authRequired, team(domain.TeamAreaFinance), banCheck, deps.WalletHandler.GetWalletDetails)
 
 	// Storefront routes (PUBLIC)
 	store := v1.Group("/store")
@@ -355,6 +547,17 @@ func SetupRouter(app *fiber.App, deps *RouterDeps) {
 		admin.Get("/jobs/stats", authRequired, RoleRequired("admin"), deps.AdminHandler.GetJobStats)
 	}
 	admin.Get("/webhooks/stats", authRequired, RoleRequired("admin"), deps.AdminHandler.GetWebhookStats)
+	admin.Get("/email-templates/:type", authRequired, RoleRequired("admin"), deps.EmailTemplateHandler.GetPlatformTemplate)
+	admin.Put("/email-templates/:type", authRequired, RoleRequired("admin"), deps.EmailTemplateHandler.UpdatePlatformTemplate)
+	admin.Get("/storage/orphans", authRequired, RoleRequired("admin"), deps.AdminHandler.GetOrphanedFiles)
+	admin.Post("/storage/orphans/sweep", authRequired, RoleRequired("admin"), deps.AdminHandler.SweepOrphanedFiles)
+	if deps.ImpersonationHandler != nil {
+		admin.Post("/creators/:id/impersonate", authRequired, RoleRequired("admin"), stepUp, deps.ImpersonationHandler.Start)
+	}
+	if deps.AuditHandler != nil {
+		admin.Get("/audit-log", authRequired, RoleRequired("admin"), deps.AuditHandler.ListAuditLog)
+		admin.Get("/audit-log/export", authRequired, RoleRequired("admin"), deps.AuditHandler.ExportAuditLog)
+	}
 
 	// Admin Subscription Management routes
 	if deps.PlatformSubHandler != nil {
@@ -375,7 +578,15 @@ func SetupRouter(app *fiber.App, deps *RouterDeps) {
 	// Protected buyer routes (with CSRF protection for state-changing endpoints)
 	buyers := v1.Group("/buyer", authRequired, banCheck, CsrfProtection())
 	buyers.Get("/orders", deps.BuyerHandler.GetPurchases)
+	buyers.Get("/orders/:id/files", deps.OrderHandler.GetOrderFiles)
+	buyers.Get("/orders/:id/download", deps.OrderHandler.DownloadOrder)
+	if deps.InvoiceHandler != nil {
+		buyers.Get("/orders/:id/invoice", deps.InvoiceHandler.GetMyInvoice)
+	}
 	buyers.Get("/courses/:id", deps.CourseHandler.GetCourse)
+	buyers.Get("/courses/:id/progress", deps.CourseHandler.GetCourseProgress)
+	buyers.Put("/courses/:id/progress/:lesId", deps.CourseHandler.UpdateLessonProgress)
+	buyers.Get("/courses/:id/certificate", deps.CertificateHandler.GetMyCertificate)
 	buyers.Get("/subscriptions", deps.BuyerHandler.GetSubscriptions)
 	buyers.Post("/subscriptions/:id/cancel", deps.BuyerHandler.CancelSubscription)
 
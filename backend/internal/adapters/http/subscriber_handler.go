@@ -2,15 +2,18 @@ package http
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/internal/core/services"
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // SubscriberHandler handles email subscriber endpoints.
 type SubscriberHandler struct {
-	repo domain.EmailSubscriberRepository
+	repo     domain.EmailSubscriberRepository
+	webhooks *services.CreatorWebhookService
 }
 
 // NewSubscriberHandler creates a new SubscriberHandler.
@@ -18,6 +21,11 @@ func NewSubscriberHandler(repo domain.EmailSubscriberRepository) *SubscriberHand
 	return &SubscriberHandler{repo: repo}
 }
 
+// SetWebhookService sends subscriber.added events for addresses added through the API.
+func (h *SubscriberHandler) SetWebhookService(webhooks *services.CreatorWebhookService) {
+	h.webhooks = webhooks
+}
+
 // GetSubscribers handles GET /api/v1/creator/subscribers
 func (h *SubscriberHandler) GetSubscribers(c *fiber.Ctx) error {
 	userIDStr := c.Locals("userId").(string)
@@ -45,3 +53,76 @@ func (h *SubscriberHandler) GetSubscribers(c *fiber.Ctx) error {
 		"per_page":    limit,
 	})
 }
+
+// AddSubscriber adds an address to the creator's list, or updates the name of one already on it.
+// People who unsubscribed stay unsubscribed.
+// POST /api/v1/creator/subscribers
+func (h *SubscriberHandler) AddSubscriber(c *fiber.Ctx) error {
+	userIDStr := c.Locals("userId").(string)
+	creatorID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Invalid user ID", nil)
+	}
+
+	var req struct {
+		Email        string `json:"email"`
+		Name         string `json:"name"`
+		ConsentGiven bool   `json:"consent_given"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
+	}
+	email := strings.TrimSpace(req.Email)
+	if email == "" || !strings.Contains(email, "@") {
+		return SendError(c, fiber.StatusBadRequest, ErrValidation, "A valid email is required", nil)
+	}
+
+	newSub := &domain.EmailSubscriber{
+		CreatorID:    creatorID,
+		Email:        email,
+		Name:         strings.TrimSpace(req.Name),
+		ConsentGiven: req.ConsentGiven,
+	}
+	created, err := h.repo.Upsert(c.Context(), newSub)
+	if err != nil {
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to save subscriber", err)
+	}
+	if created {
+		h.webhooks.Publish(creatorID, domain.WebhookEventSubscriberAdded, newSub)
+	}
+
+	sub, err := h.repo.FindByEmail(c.Context(), email, userIDStr)
+	if err != nil {
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to fetch subscriber", err)
+	}
+	if sub == nil {
+		return SendError(c, fiber.StatusConflict, ErrConflict, "This address has unsubscribed from your list", nil)
+	}
+	return SendSuccess(c, fiber.StatusCreated, sub, nil)
+}
+
+// Unsubscribe removes an address from the creator's mailing list.
+// POST /api/v1/creator/subscribers/unsubscribe
+func (h *SubscriberHandler) Unsubscribe(c *fiber.Ctx) error {
+	userIDStr := c.Locals("userId").(string)
+	creatorID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Invalid user ID", nil)
+	}
+
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
+	}
+	email := strings.TrimSpace(req.Email)
+	if email == "" {
+		return SendError(c, fiber.StatusBadRequest, ErrValidation, "Email is required", nil)
+	}
+
+	if err := h.repo.Unsubscribe(c.Context(), creatorID, email); err != nil {
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to unsubscribe", err)
+	}
+	return SendOK(c, map[string]bool{"unsubscribed": true})
+}
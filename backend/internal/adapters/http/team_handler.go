@@ -0,0 +1,149 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/devanshbhargava/stan-store/internal/core/services"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+// TeamHandler handles team invites, roles and the team audit trail.
+type TeamHandler struct {
+	teamService *services.TeamService
+}
+
+// NewTeamHandler creates a new TeamHandler.
+func NewTeamHandler(teamService *services.TeamService) *TeamHandler {
+	return &TeamHandler{teamService: teamService}
+}
+
+// ListMembers returns the creator's team and pending invites.
+// GET /api/v1/creator/team
+func (h *TeamHandler) ListMembers(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userId").(string)
+	members, err := h.teamService.ListMembers(c.Context(), userID)
+	if err != nil {
+		return sendTeamError(c, err)
+	}
+	return SendSuccess(c, fiber.StatusOK, members, nil)
+}
+
+// Invite invites a collaborator by email.
+// POST /api/v1/creator/team
+func (h *TeamHandler) Invite(c *fiber.Ctx) error {
+	var req struct {
+		Email string   `json:"email"`
+		Roles []string `json:"roles"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
+	}
+
+	userID, _ := c.Locals("userId").(string)
+	member, err := h.teamService.Invite(c.Context(), userID, req.Email, req.Roles)
+	if err != nil {
+		return sendTeamError(c, err)
+	}
+	return SendSuccess(c, fiber.StatusCreated, member, nil)
+}
+
+// UpdateRoles changes a team member's roles.
+// PATCH /api/v1/creator/team/:id
+func (h *TeamHandler) UpdateRoles(c *fiber.Ctx) error {
+	var req struct {
+		Roles []string `json:"roles"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
+	}
+
+	userID, _ := c.Locals("userId").(string)
+	member, err := h.teamService.UpdateRoles(c.Context(), userID, c.Params("id"), req.Roles)
+	if err != nil {
+		return sendTeamError(c, err)
+	}
+	return SendSuccess(c, fiber.StatusOK, member, nil)
+}
+
+// RemoveMember removes a team member or cancels an invite.
+// DELETE /api/v1/creator/team/:id
+func (h *TeamHandler) RemoveMember(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userId").(string)
+	if err := h.teamService.RemoveMember(c.Context(), userID, c.Params("id")); err != nil {
+		return sendTeamError(c, err)
+	}
+	return SendOK(c, map[string]bool{"removed": true})
+}
+
+// GetActivity returns what team members did on the account, newest first.
+// GET /api/v1/creator/team/activity?limit=100
+func (h *TeamHandler) GetActivity(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userId").(string)
+	limit, _ := strconv.ParseInt(c.Query("limit"), 10, 64)
+
+	activity, err := h.teamService.ListActivity(c.Context(), userID, limit)
+	if err != nil {
+		return sendTeamError(c, err)
+	}
+	return SendSuccess(c, fiber.StatusOK, activity, nil)
+}
+
+// AcceptInvite joins the signed-in user to the team that invited them.
+// POST /api/v1/team/invitations/accept
+func (h *TeamHandler) AcceptInvite(c *fiber.Ctx) error {
+	var req struct {
+		Token string `json:"token"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.Token == "" {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invite token is required", nil)
+	}
+
+	userID, _ := c.Locals("userId").(string)
+	member, err := h.teamService.AcceptInvite(c.Context(), userID, req.Token)
+	if err != nil {
+		return sendTeamError(c, err)
+	}
+	return SendSuccess(c, fiber.StatusOK, member, nil)
+}
+
+// ListAccounts returns the creator accounts the user can act on. Send the chosen account's
+// creator_id in the X-Creator-Id header to work on it.
+// GET /api/v1/team/accounts
+func (h *TeamHandler) ListAccounts(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userId").(string)
+	accounts, err := h.teamService.ListAccounts(c.Context(), userID)
+	if err != nil {
+		return sendTeamError(c, err)
+	}
+	return SendSuccess(c, fiber.StatusOK, accounts, nil)
+}
+
+// Leave removes the user from a creator's team.
+// DELETE /api/v1/team/accounts/:creatorId
+func (h *TeamHandler) Leave(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userId").(string)
+	if err := h.teamService.Leave(c.Context(), userID, c.Params("creatorId")); err != nil {
+		return sendTeamError(c, err)
+	}
+	return SendOK(c, map[string]bool{"removed": true})
+}
+
+func sendTeamError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrTeamMemberNotFound):
+		return SendError(c, fiber.StatusNotFound, ErrNotFound, err.Error(), nil)
+	case errors.Is(err, services.ErrTeamMemberExists):
+		return SendError(c, fiber.StatusConflict, ErrConflict, err.Error(), nil)
+	case errors.Is(err, services.ErrTeamInviteWrongAccount), errors.Is(err, services.ErrNotTeamMember),
+		errors.Is(err, services.ErrTeamPermissionDenied):
+		return SendError(c, fiber.StatusForbidden, ErrForbidden, err.Error(), nil)
+	case errors.Is(err, services.ErrTeamInviteInvalid), errors.Is(err, services.ErrInvalidTeamRoles),
+		errors.Is(err, services.ErrCannotInviteSelf):
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, err.Error(), nil)
+	}
+	logger.Error("team request failed", "error", err)
+	return SendError(c, fiber.StatusBadRequest, ErrBadRequest, err.Error(), nil)
+}
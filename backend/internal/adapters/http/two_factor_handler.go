@@ -0,0 +1,236 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/devanshbhargava/stan-store/internal/core/services"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+// TwoFactorHandler handles TOTP enrolment, two-factor login and step-up re-authentication.
+type TwoFactorHandler struct {
+	authService *services.AuthService
+	twoFactor   *services.TwoFactorService
+}
+
+// NewTwoFactorHandler creates a new TwoFactorHandler.
+func NewTwoFactorHandler(authService *services.AuthService, twoFactor *services.TwoFactorService) *TwoFactorHandler {
+	return &TwoFactorHandler{authService: authService, twoFactor: twoFactor}
+}
+
+// VerifyLogin completes a login that returned twoFactorRequired.
+// POST /api/v1/auth/2fa/verify
+func (h *TwoFactorHandler) VerifyLogin(c *fiber.Ctx) error {
+	var req struct {
+		ChallengeToken string `json:"challengeToken"`
+		Code           string `json:"code"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
+	}
+	if req.ChallengeToken == "" || req.Code == "" {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Challenge token and code are required", nil)
+	}
+
+	result, err := h.authService.CompleteTwoFactorLogin(c.Context(), req.ChallengeToken, req.Code, clientInfo(c))
+	if err != nil {
+		return sendTwoFactorError(c, err)
+	}
+
+	maxAge := cookieMaxAge
+	if result.User.Role == "buyer" {
+		maxAge = buyerCookieMaxAge
+	}
+	setAuthCookies(c, result, maxAge)
+
+	return SendSuccess(c, fiber.StatusOK, authResponse(c, result), nil)
+}
+
+// BeginLoginSetup starts enrolment for an admin who must set up 2FA before signing in.
+// POST /api/v1/auth/2fa/login-setup
+func (h *TwoFactorHandler) BeginLoginSetup(c *fiber.Ctx) error {
+	var req struct {
+		ChallengeToken string `json:"challengeToken"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.ChallengeToken == "" {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Challenge token is required", nil)
+	}
+
+	setup, err := h.authService.BeginChallengeTwoFactorSetup(c.Context(), req.ChallengeToken)
+	if err != nil {
+		return sendTwoFactorError(c, err)
+	}
+	return SendSuccess(c, fiber.StatusOK, setup, nil)
+}
+
+// GetStatus returns whether 2FA is enabled and how many recovery codes remain.
+// GET /api/v1/auth/2fa
+func (h *TwoFactorHandler) GetStatus(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userId").(string)
+	user, err := h.authService.GetCurrentUser(c.Context(), userID)
+	if err != nil {
+		return SendError(c, fiber.StatusNotFound, ErrNotFound, "User not found", nil)
+	}
+
+	remaining := 0
+	if user.HasTwoFactor() {
+		remaining = len(user.TwoFactor.RecoveryCodes)
+	}
+	return SendSuccess(c, fiber.StatusOK, map[string]interface{}{
+		"enabled":                user.HasTwoFactor(),
+		"required":               user.RequiresTwoFactor(),
+		"recoveryCodesRemaining": remaining,
+	}, nil)
+}
+
+// BeginSetup generates a TOTP secret to scan into an authenticator app.
+// POST /api/v1/auth/2fa/setup
+func (h *TwoFactorHandler) BeginSetup(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userId").(string)
+	user, err := h.authService.GetCurrentUser(c.Context(), userID)
+	if err != nil {
+		return SendError(c, fiber.StatusNotFound, ErrNotFound, "User not found", nil)
+	}
+
+	setup, err := h.twoFactor.BeginSetup(c.Context(), user)
+	if err != nil {
+		return sendTwoFactorError(c, err)
+	}
+	return SendSuccess(c, fiber.StatusOK, setup, nil)
+}
+
+// Enable confirms enrolment with a code and returns the recovery codes.
+// POST /api/v1/auth/2fa/enable
+func (h *TwoFactorHandler) Enable(c *fiber.Ctx) error {
+	return h.withCode(c, func(code string) (interface{}, error) {
+		user, err := h.authService.GetCurrentUser(c.Context(), c.Locals("userId").(string))
+		if err != nil {
+			return nil, err
+		}
+		codes, err := h.twoFactor.Enable(c.Context(), user, code)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"enabled": true, "recoveryCodes": codes}, nil
+	})
+}
+
+// Disable turns 2FA off after checking a current code.
+// POST /api/v1/auth/2fa/disable
+func (h *TwoFactorHandler) Disable(c *fiber.Ctx) error {
+	return h.withCode(c, func(code string) (interface{}, error) {
+		user, err := h.authService.GetCurrentUser(c.Context(), c.Locals("userId").(string))
+		if err != nil {
+			return nil, err
+		}
+		if err := h.twoFactor.Disable(c.Context(), user, code); err != nil {
+			return nil, err
+		}
+		return map[string]bool{"enabled": false}, nil
+	})
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes after checking a current code.
+// POST /api/v1/auth/2fa/recovery-codes
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	return h.withCode(c, func(code string) (interface{}, error) {
+		user, err := h.authService.GetCurrentUser(c.Context(), c.Locals("userId").(string))
+		if err != nil {
+			return nil, err
+		}
+		codes, err := h.twoFactor.RegenerateRecoveryCodes(c.Context(), user, code)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"recoveryCodes": codes}, nil
+	})
+}
+
+// StepUp confirms the user's identity before a sensitive action. It accepts a 2FA code, the
+// account password, or a code sent by RequestStepUpCode.
+// POST /api/v1/auth/step-up
+func (h *TwoFactorHandler) StepUp(c *fiber.Ctx) error {
+	var req struct {
+		Code     string `json:"code"`
+		Password string `json:"password"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Invalid request body", nil)
+	}
+
+	userID, _ := c.Locals("userId").(string)
+	if err := h.authService.StepUp(c.Context(), userID, stepUpSubject(c), req.Code, req.Password); err != nil {
+		return sendTwoFactorError(c, err)
+	}
+	return SendSuccess(c, fiber.StatusOK, map[string]int{"expiresIn": int(services.StepUpWindow.Seconds())}, nil)
+}
+
+// RequestStepUpCode emails a step-up code to accounts without 2FA or a password.
+// POST /api/v1/auth/step-up/email
+func (h *TwoFactorHandler) RequestStepUpCode(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userId").(string)
+	if err := h.authService.RequestStepUpCode(c.Context(), userID); err != nil {
+		logger.Error("step-up code request failed", "error", err, "user_id", userID)
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to send confirmation code", nil)
+	}
+	return SendSuccess(c, fiber.StatusOK, map[string]string{"message": "A confirmation code has been sent to your email."}, nil)
+}
+
+func (h *TwoFactorHandler) withCode(c *fiber.Ctx, fn func(code string) (interface{}, error)) error {
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.Code == "" {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "Code is required", nil)
+	}
+	data, err := fn(req.Code)
+	if err != nil {
+		return sendTwoFactorError(c, err)
+	}
+	return SendSuccess(c, fiber.StatusOK, data, nil)
+}
+
+// StepUpRequired middleware rejects sensitive requests unless the session re-authenticated
+// within the last few minutes. Must be used AFTER AuthRequired.
+func StepUpRequired(authService *services.AuthService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ok, err := authService.HasStepUp(c.Context(), stepUpSubject(c))
+		if err != nil {
+			return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to check re-authentication", err)
+		}
+		if !ok {
+			return SendError(c, fiber.StatusForbidden, ErrStepUpRequired, "Please confirm your identity to continue", nil)
+		}
+		return c.Next()
+	}
+}
+
+// stepUpSubject scopes a step-up confirmation to the current session, or to the user when
+// sessions are disabled.
+func stepUpSubject(c *fiber.Ctx) string {
+	if sessionID, _ := c.Locals("sessionId").(string); sessionID != "" {
+		return "session:" + sessionID
+	}
+	userID, _ := c.Locals("userId").(string)
+	return "user:" + userID
+}
+
+func sendTwoFactorError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidTwoFactorCode), errors.Is(err, services.ErrIncorrectPassword):
+		// Not 401: that would make the client refresh the session and resend the wrong credential
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, err.Error(), nil)
+	case errors.Is(err, services.ErrInvalidTwoFactorLogin), errors.Is(err, services.ErrTooManyTwoFactorErrors):
+		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, err.Error(), nil)
+	case errors.Is(err, services.ErrTwoFactorMandatory):
+		return SendError(c, fiber.StatusForbidden, ErrForbidden, err.Error(), nil)
+	case errors.Is(err, services.ErrTwoFactorAlreadyOn), errors.Is(err, services.ErrTwoFactorNotEnabled):
+		return SendError(c, fiber.StatusConflict, ErrConflict, err.Error(), nil)
+	case errors.Is(err, services.ErrTwoFactorSetupExpired), errors.Is(err, services.ErrStepUpCredentialsRequired):
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, err.Error(), nil)
+	}
+	logger.Error("two-factor request failed", "error", err)
+	return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Two-factor request failed", nil)
+}
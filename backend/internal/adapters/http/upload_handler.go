@@ -1,6 +1,8 @@
 package http
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/devanshbhargava/stan-store/internal/core/domain"
@@ -58,3 +60,31 @@ func (h *UploadHandler) GeneratePresignedURL(c *fiber.Ctx) error {
 		"error": nil,
 	})
 }
+
+// CompleteUpload handles POST /api/v1/uploads/complete.
+// It verifies the uploaded object before its key can be attached to a product.
+func (h *UploadHandler) CompleteUpload(c *fiber.Ctx) error {
+	userID := c.Locals("userId").(string)
+
+	var req domain.CompleteUploadRequest
+	if err := c.BodyParser(&req); err != nil || req.FileKey == "" {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, "file_key is required", nil)
+	}
+
+	upload, err := h.service.CompleteUpload(c.Context(), userID, req.FileKey)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUploadNotFound):
+			return SendError(c, fiber.StatusNotFound, ErrNotFound, err.Error(), nil)
+		case errors.Is(err, services.ErrUploadNotReceived):
+			return SendError(c, fiber.StatusConflict, ErrConflict, err.Error(), nil)
+		case errors.Is(err, services.ErrUploadRejected):
+			return SendError(c, fiber.StatusUnprocessableEntity, ErrValidation, err.Error(), nil)
+		case errors.Is(err, services.ErrScanUnavailable):
+			return SendError(c, fiber.StatusServiceUnavailable, ErrInternalServer, err.Error(), nil)
+		}
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to verify upload", err)
+	}
+
+	return SendOK(c, upload)
+}
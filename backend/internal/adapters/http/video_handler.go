@@ -0,0 +1,50 @@
+package http
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/devanshbhargava/stan-store/internal/core/services"
+)
+
+// VideoHandler serves HLS playlists for uploaded lesson videos behind signed links.
+type VideoHandler struct {
+	service *services.VideoService
+}
+
+// NewVideoHandler creates a new VideoHandler.
+func NewVideoHandler(service *services.VideoService) *VideoHandler {
+	return &VideoHandler{service: service}
+}
+
+// GetPlaylist handles GET /api/v1/videos/:token/:playlist, where playlist is master.m3u8
+// or a rendition such as 720p.m3u8.
+func (h *VideoHandler) GetPlaylist(c *fiber.Ctx) error {
+	name, ok := strings.CutSuffix(c.Params("playlist"), ".m3u8")
+	if !ok {
+		return SendError(c, fiber.StatusNotFound, ErrNotFound, "Playlist not found", nil)
+	}
+
+	var playlist string
+	var err error
+	if name == "master" {
+		playlist, err = h.service.MasterPlaylist(c.Context(), c.Params("token"))
+	} else {
+		playlist, err = h.service.RenditionPlaylist(c.Context(), c.Params("token"), name)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidStreamToken), errors.Is(err, services.ErrLessonLocked), strings.HasPrefix(err.Error(), "unauthorized"):
+			return SendError(c, fiber.StatusForbidden, ErrForbidden, err.Error(), nil)
+		case errors.Is(err, services.ErrStreamNotReady), errors.Is(err, services.ErrLessonNotFound), err.Error() == services.ErrCourseNotFound.Error():
+			return SendError(c, fiber.StatusNotFound, ErrNotFound, err.Error(), nil)
+		}
+		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to load playlist", err)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/vnd.apple.mpegurl")
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	return c.SendString(playlist)
+}
@@ -1,6 +1,10 @@
 package http
 
 import (
+	"fmt"
+	"strconv"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
 	"github.com/devanshbhargava/stan-store/internal/core/services"
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -16,7 +20,8 @@ func NewWalletHandler(service *services.WalletService) *WalletHandler {
 	}
 }
 
-// GetWalletDetails handles GET /api/v1/wallet
+// GetWalletDetails returns the balance and a page of wallet transactions, newest first.
+// GET /api/v1/wallet?type=&source=&from=&to=&page=1&pageSize=25
 func (h *WalletHandler) GetWalletDetails(c *fiber.Ctx) error {
 	userIDStr := c.Locals("userId").(string)
 	creatorID, err := primitive.ObjectIDFromHex(userIDStr)
@@ -24,13 +29,52 @@ func (h *WalletHandler) GetWalletDetails(c *fiber.Ctx) error {
 		return SendError(c, fiber.StatusUnauthorized, ErrUnauthorized, "Invalid user ID", nil)
 	}
 
-	balance, transactions, err := h.service.GetWalletDetails(c.Context(), creatorID)
+	filter, err := parseTransactionFilter(c)
+	if err != nil {
+		return SendError(c, fiber.StatusBadRequest, ErrBadRequest, err.Error(), nil)
+	}
+	filter.CreatorID = creatorID
+
+	page, _ := strconv.ParseInt(c.Query("page", "1"), 10, 64)
+	pageSize, _ := strconv.ParseInt(c.Query("pageSize", "25"), 10, 64)
+
+	balance, transactions, meta, err := h.service.GetWalletDetails(c.Context(), filter, &domain.Pagination{
+		Page:     page,
+		PageSize: pageSize,
+	})
 	if err != nil {
 		return SendError(c, fiber.StatusInternalServerError, ErrInternalServer, "Failed to fetch wallet details", err)
 	}
 
-	return SendOK(c, fiber.Map{
+	return SendSuccess(c, fiber.StatusOK, fiber.Map{
 		"balance":      balance,
 		"transactions": transactions,
-	})
+	}, meta)
+}
+
+// parseTransactionFilter reads the wallet transaction filters from the query string.
+func parseTransactionFilter(c *fiber.Ctx) (domain.TransactionFilter, error) {
+	filter := domain.TransactionFilter{
+		Type:   domain.TransactionType(c.Query("type")),
+		Source: domain.TransactionSource(c.Query("source")),
+	}
+	switch filter.Type {
+	case "", domain.TransactionTypeCredit, domain.TransactionTypeDebit:
+	default:
+		return filter, fmt.Errorf("invalid type")
+	}
+	switch filter.Source {
+	case "", domain.TransactionSourceOrder, domain.TransactionSourcePayout, domain.TransactionSourceReferral:
+	default:
+		return filter, fmt.Errorf("invalid source")
+	}
+
+	var err error
+	if filter.From, err = parseTimeQuery(c.Query("from"), false); err != nil {
+		return filter, fmt.Errorf("invalid from: use RFC 3339 or YYYY-MM-DD")
+	}
+	if filter.To, err = parseTimeQuery(c.Query("to"), true); err != nil {
+		return filter, fmt.Errorf("invalid to: use RFC 3339 or YYYY-MM-DD")
+	}
+	return filter, nil
 }
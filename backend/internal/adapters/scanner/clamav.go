@@ -0,0 +1,99 @@
+// Package scanner provides malware scanners for uploaded files.
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+)
+
+// chunkSize is the size of each INSTREAM chunk sent to clamd.
+const chunkSize = 64 * 1024
+
+// ClamAVScanner scans content by streaming it to a clamd daemon with the INSTREAM command.
+type ClamAVScanner struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// NewClamAVScanner creates a scanner for a clamd address such as
+// "unix:///var/run/clamav/clamd.ctl" or "tcp://127.0.0.1:3310".
+func NewClamAVScanner(address string, timeout time.Duration) (*ClamAVScanner, error) {
+	network, addr, ok := strings.Cut(address, "://")
+	if !ok || (network != "unix" && network != "tcp") || addr == "" {
+		return nil, fmt.Errorf("invalid clamd address %q: expected unix:///path or tcp://host:port", address)
+	}
+	return &ClamAVScanner{network: network, address: addr, timeout: timeout}, nil
+}
+
+// Scan streams r to clamd and parses its verdict.
+func (s *ClamAVScanner) Scan(ctx context.Context, r io.Reader) (*domain.ScanResult, error) {
+	dialer := net.Dialer{Timeout: 10 * time.Second}
+	conn, err := dialer.DialContext(ctx, s.network, s.address)
+	if err != nil {
+		return nil, fmt.Errorf("connect to clamd: %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(s.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return nil, fmt.Errorf("start clamd stream: %w", err)
+	}
+
+	buf := make([]byte, chunkSize)
+	size := make([]byte, 4)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			binary.BigEndian.PutUint32(size, uint32(n))
+			if _, err := conn.Write(size); err != nil {
+				return nil, fmt.Errorf("write to clamd: %w", err)
+			}
+			if _, err := conn.Write(buf[:n]); err != nil {
+				return nil, fmt.Errorf("write to clamd: %w", err)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("read upload: %w", readErr)
+		}
+	}
+	// A zero-length chunk terminates the stream.
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return nil, fmt.Errorf("finish clamd stream: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && reply == "" {
+		return nil, fmt.Errorf("read clamd reply: %w", err)
+	}
+	return parseReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// parseReply interprets replies such as "stream: OK" and "stream: Eicar-Signature FOUND".
+func parseReply(reply string) (*domain.ScanResult, error) {
+	verdict := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case verdict == "OK":
+		return &domain.ScanResult{Clean: true}, nil
+	case strings.HasSuffix(verdict, " FOUND"):
+		return &domain.ScanResult{Clean: false, Signature: strings.TrimSuffix(verdict, " FOUND")}, nil
+	default:
+		return nil, fmt.Errorf("clamd error: %s", reply)
+	}
+}
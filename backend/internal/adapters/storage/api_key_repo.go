@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+const apiKeysCollection = "api_keys"
+
+// MongoAPIKeyRepository implements domain.APIKeyRepository using MongoDB.
+type MongoAPIKeyRepository struct {
+	*BaseRepository[domain.APIKey]
+}
+
+// NewMongoAPIKeyRepository creates a new MongoAPIKeyRepository.
+func NewMongoAPIKeyRepository(db *MongoDB) *MongoAPIKeyRepository {
+	repo := &MongoAPIKeyRepository{
+		BaseRepository: NewBaseRepository[domain.APIKey](db, apiKeysCollection),
+	}
+	repo.ensureIndexes()
+	return repo
+}
+
+// ensureIndexes creates indexes for the api_keys collection.
+func (r *MongoAPIKeyRepository) ensureIndexes() {
+	ctx := context.Background()
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "key_hash", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("idx_key_hash_unique"),
+		},
+		{
+			Keys:    bson.D{{Key: "creator_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_creator_created"),
+		},
+	}
+	if _, err := r.Collection().Indexes().CreateMany(ctx, indexes); err != nil {
+		logger.Error("Failed to ensure indexes for API keys", "error", err)
+	}
+}
+
+// Create inserts a new API key.
+func (r *MongoAPIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	key.CreatedAt = time.Now()
+	result, err := r.Collection().InsertOne(ctx, key)
+	if err != nil {
+		return fmt.Errorf("insert api key: %w", err)
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		key.ID = oid
+	}
+	return nil
+}
+
+// FindByHash returns the unrevoked key with this hash.
+func (r *MongoAPIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	filter := bson.M{"key_hash": keyHash, "revoked_at": bson.M{"$exists": false}}
+	if err := r.Collection().FindOne(ctx, filter).Decode(&key); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find api key: %w", err)
+	}
+	return &key, nil
+}
+
+// FindByCreator lists a creator's keys, newest first.
+func (r *MongoAPIKeyRepository) FindByCreator(ctx context.Context, creatorID primitive.ObjectID) ([]*domain.APIKey, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.Collection().Find(ctx, bson.M{"creator_id": creatorID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find api keys: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var keys []*domain.APIKey
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, fmt.Errorf("decode api keys: %w", err)
+	}
+	return keys, nil
+}
+
+// Revoke revokes one of the creator's keys.
+func (r *MongoAPIKeyRepository) Revoke(ctx context.Context, creatorID, id primitive.ObjectID) (bool, error) {
+	result, err := r.Collection().UpdateOne(ctx,
+		bson.M{"_id": id, "creator_id": creatorID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("revoke api key: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// TouchLastUsed records the key's last use.
+func (r *MongoAPIKeyRepository) TouchLastUsed(ctx context.Context, id primitive.ObjectID, at time.Time, ip string) error {
+	_, err := r.Collection().UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"last_used_at": at, "last_used_ip": ip}},
+	)
+	if err != nil {
+		return fmt.Errorf("touch api key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+const auditLogCollection = "audit_log"
+
+// MongoAuditLogRepository implements domain.AuditLogRepository using MongoDB.
+// It only inserts and reads; nothing in the application updates or deletes entries.
+type MongoAuditLogRepository struct {
+	*BaseRepository[domain.AuditEntry]
+}
+
+// NewMongoAuditLogRepository creates a new MongoAuditLogRepository.
+func NewMongoAuditLogRepository(db *MongoDB) *MongoAuditLogRepository {
+	repo := &MongoAuditLogRepository{
+		BaseRepository: NewBaseRepository[domain.AuditEntry](db, auditLogCollection),
+	}
+	repo.ensureIndexes()
+	return repo
+}
+
+// ensureIndexes creates indexes for the audit_log collection.
+func (r *MongoAuditLogRepository) ensureIndexes() {
+	ctx := context.Background()
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_created"),
+		},
+		{
+			Keys:    bson.D{{Key: "actor_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_actor_created"),
+		},
+		{
+			Keys:    bson.D{{Key: "target_type", Value: 1}, {Key: "target_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_target_created"),
+		},
+		{
+			Keys:    bson.D{{Key: "action", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_action_created"),
+		},
+	}
+	if _, err := r.Collection().Indexes().CreateMany(ctx, indexes); err != nil {
+		logger.Error("Failed to ensure indexes for audit log", "error", err)
+	}
+}
+
+// Create appends an entry to the audit log.
+func (r *MongoAuditLogRepository) Create(ctx context.Context, entry *domain.AuditEntry) error {
+	entry.CreatedAt = time.Now()
+	result, err := r.Collection().InsertOne(ctx, entry)
+	if err != nil {
+		return fmt.Errorf("insert audit entry: %w", err)
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		entry.ID = oid
+	}
+	return nil
+}
+
+// Find returns a page of matching entries, newest first.
+func (r *MongoAuditLogRepository) Find(ctx context.Context, filter domain.AuditFilter, pagination *domain.Pagination) ([]*domain.AuditEntry, *domain.PaginationMeta, error) {
+	page := int64(1)
+	pageSize := int64(50)
+	if pagination != nil {
+		if pagination.Page > 0 {
+			page = pagination.Page
+		}
+		if pagination.PageSize > 0 && pagination.PageSize <= 200 {
+			pageSize = pagination.PageSize
+		}
+	}
+
+	query := auditQuery(filter)
+	total, err := r.Collection().CountDocuments(ctx, query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("count audit entries: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip((page - 1) * pageSize).
+		SetLimit(pageSize)
+	cursor, err := r.Collection().Find(ctx, query, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("find audit entries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	entries := []*domain.AuditEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, nil, fmt.Errorf("decode audit entries: %w", err)
+	}
+
+	totalPages := total / pageSize
+	if total%pageSize != 0 {
+		totalPages++
+	}
+	return entries, &domain.PaginationMeta{
+		Page:       page,
+		PageSize:   pageSize,
+		TotalCount: total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// Iterate streams every matching entry, newest first, without loading them all at once.
+func (r *MongoAuditLogRepository) Iterate(ctx context.Context, filter domain.AuditFilter, fn func(*domain.AuditEntry) error) error {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := r.Collection().Find(ctx, auditQuery(filter), opts)
+	if err != nil {
+		return fmt.Errorf("find audit entries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var entry domain.AuditEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return fmt.Errorf("decode audit entry: %w", err)
+		}
+		if err := fn(&entry); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func auditQuery(filter domain.AuditFilter) bson.M {
+	query := bson.M{}
+	if !filter.ActorID.IsZero() {
+		query["actor_id"] = filter.ActorID
+	}
+	if filter.Action != "" {
+		if strings.HasSuffix(filter.Action, ".") {
+			query["action"] = primitive.Regex{Pattern: "^" + regexp.QuoteMeta(filter.Action)}
+		} else {
+			query["action"] = filter.Action
+		}
+	}
+	if filter.TargetType != "" {
+		query["target_type"] = filter.TargetType
+	}
+	if filter.TargetID != "" {
+		query["target_id"] = filter.TargetID
+	}
+
+	created := bson.M{}
+	if !filter.From.IsZero() {
+		created["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		created["$lt"] = filter.To
+	}
+	if len(created) > 0 {
+		query["created_at"] = created
+	}
+	return query
+}
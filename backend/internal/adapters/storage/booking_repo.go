@@ -14,7 +14,10 @@ import (
 	"github.com/devanshbhargava/stan-store/pkg/logger"
 )
 
-const bookingsCollection = "bookings"
+const (
+	bookingsCollection     = "bookings"
+	bookingSlotsCollection = "booking_slots" // Seat counters, one per product and slot start
+)
 
 // MongoBookingRepository implements domain.BookingRepository using MongoDB.
 type MongoBookingRepository struct {
@@ -60,6 +63,45 @@ func (r *MongoBookingRepository) ensureIndexes() {
 	} else {
 		logger.Info("booking indexes ensured", "collection", bookingsCollection)
 	}
+
+	_, err = r.slots().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "product_id", Value: 1}, {Key: "slot_start", Value: 1}},
+		Options: options.Index().SetName("idx_product_slot").SetUnique(true),
+	})
+	if err != nil {
+		logger.Error("failed to create booking slot indexes", "error", err.Error())
+	}
+}
+
+func (r *MongoBookingRepository) slots() *mongo.Collection {
+	return r.Collection().Database().Collection(bookingSlotsCollection)
+}
+
+// ReserveSeat increments the slot's seat counter if it is below capacity.
+func (r *MongoBookingRepository) ReserveSeat(ctx context.Context, productID primitive.ObjectID, slotStart time.Time, capacity, taken int) (bool, error) {
+	key := bson.M{"product_id": productID, "slot_start": slotStart}
+
+	// Seed the counter on first use; concurrent seeds race on the unique index, which is fine
+	seed := bson.M{"$setOnInsert": bson.M{"seats_taken": taken}}
+	if _, err := r.slots().UpdateOne(ctx, key, seed, options.Update().SetUpsert(true)); err != nil && !mongo.IsDuplicateKeyError(err) {
+		return false, fmt.Errorf("seed booking slot: %w", err)
+	}
+
+	filter := bson.M{"product_id": productID, "slot_start": slotStart, "seats_taken": bson.M{"$lt": capacity}}
+	result, err := r.slots().UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"seats_taken": 1}})
+	if err != nil {
+		return false, fmt.Errorf("reserve booking seat: %w", err)
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// ReleaseSeat decrements the slot's seat counter.
+func (r *MongoBookingRepository) ReleaseSeat(ctx context.Context, productID primitive.ObjectID, slotStart time.Time) error {
+	filter := bson.M{"product_id": productID, "slot_start": slotStart, "seats_taken": bson.M{"$gt": 0}}
+	if _, err := r.slots().UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"seats_taken": -1}}); err != nil {
+		return fmt.Errorf("release booking seat: %w", err)
+	}
+	return nil
 }
 
 // Create inserts a new booking.
@@ -167,17 +209,90 @@ func (r *MongoBookingRepository) FindByBuyerEmail(ctx context.Context, email str
 	return results, nil
 }
 
+// FindBySlot returns the non-cancelled bookings for a product that start at the given time.
+func (r *MongoBookingRepository) FindBySlot(ctx context.Context, productID primitive.ObjectID, slotStart time.Time) ([]*domain.Booking, error) {
+	filter := bson.M{
+		"product_id": productID,
+		"slot_start": slotStart,
+		"status":     bson.M{"$ne": domain.BookingStatusCancelled},
+	}
+
+	cursor, err := r.Collection().Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("find bookings by slot: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []*domain.Booking
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("decode slot bookings: %w", err)
+	}
+	return results, nil
+}
+
 // UpdateStatus updates the status of a booking.
+// Transitions to completed also stamp completed_at.
 func (r *MongoBookingRepository) UpdateStatus(ctx context.Context, id primitive.ObjectID, status domain.BookingStatus) error {
-	update := bson.M{
-		"$set": bson.M{
-			"status":     status,
-			"updated_at": time.Now(),
-		},
-	}
-	_, err := r.Collection().UpdateByID(ctx, id, update)
+	_, err := r.Collection().UpdateByID(ctx, id, bookingStatusUpdate(status))
 	if err != nil {
 		return fmt.Errorf("update booking status: %w", err)
 	}
 	return nil
 }
+
+// TransitionStatus updates the status only if the booking is still in the from status.
+func (r *MongoBookingRepository) TransitionStatus(ctx context.Context, id primitive.ObjectID, from, to domain.BookingStatus) (bool, error) {
+	result, err := r.Collection().UpdateOne(ctx, bson.M{"_id": id, "status": from}, bookingStatusUpdate(to))
+	if err != nil {
+		return false, fmt.Errorf("transition booking status: %w", err)
+	}
+	return result.MatchedCount == 1, nil
+}
+
+func bookingStatusUpdate(status domain.BookingStatus) bson.M {
+	now := time.Now()
+	set := bson.M{
+		"status":     status,
+		"updated_at": now,
+	}
+	if status == domain.BookingStatusCompleted {
+		set["completed_at"] = now
+	}
+	return bson.M{"$set": set}
+}
+
+// FindConfirmedEndedBefore returns confirmed bookings whose slot ended before the given time.
+func (r *MongoBookingRepository) FindConfirmedEndedBefore(ctx context.Context, before time.Time) ([]*domain.Booking, error) {
+	filter := bson.M{
+		"status":   domain.BookingStatusConfirmed,
+		"slot_end": bson.M{"$lt": before},
+	}
+
+	cursor, err := r.Collection().Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "slot_end", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("find ended bookings: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []*domain.Booking
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("decode ended bookings: %w", err)
+	}
+	return results, nil
+}
+
+// AnonymiseBuyer replaces the buyer's email and name on all their bookings.
+func (r *MongoBookingRepository) AnonymiseBuyer(ctx context.Context, email, anonEmail, anonName string) error {
+	_, err := r.Collection().UpdateMany(ctx,
+		bson.M{"buyer_email": email},
+		bson.M{"$set": bson.M{
+			"buyer_email": anonEmail,
+			"buyer_name":  anonName,
+			"updated_at":  time.Now(),
+		}},
+	)
+	if err != nil {
+		return fmt.Errorf("anonymise bookings: %w", err)
+	}
+	return nil
+}
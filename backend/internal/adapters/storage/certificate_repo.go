@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+const certificatesCollection = "certificates"
+
+// MongoCertificateRepository implements domain.CertificateRepository using MongoDB.
+type MongoCertificateRepository struct {
+	*BaseRepository[domain.Certificate]
+}
+
+// NewMongoCertificateRepository creates a new MongoCertificateRepository.
+func NewMongoCertificateRepository(db *MongoDB) *MongoCertificateRepository {
+	repo := &MongoCertificateRepository{
+		BaseRepository: NewBaseRepository[domain.Certificate](db, certificatesCollection),
+	}
+	repo.ensureIndexes()
+	return repo
+}
+
+// ensureIndexes creates indexes for the certificates collection.
+func (r *MongoCertificateRepository) ensureIndexes() {
+	ctx := context.Background()
+	col := r.Collection()
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "verification_id", Value: 1}},
+			Options: options.Index().SetName("idx_verification_id").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "product_id", Value: 1}},
+			Options: options.Index().SetName("idx_user_product").SetUnique(true),
+		},
+	}
+
+	_, err := col.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		logger.Error("Failed to ensure indexes for certificates", "error", err)
+	}
+}
+
+// Create inserts a newly issued certificate.
+func (r *MongoCertificateRepository) Create(ctx context.Context, cert *domain.Certificate) error {
+	cert.CreatedAt = time.Now()
+	result, err := r.Collection().InsertOne(ctx, cert)
+	if err != nil {
+		return fmt.Errorf("insert certificate: %w", err)
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		cert.ID = oid
+	}
+	return nil
+}
+
+// FindByVerificationID looks up a certificate by its public verification ID.
+func (r *MongoCertificateRepository) FindByVerificationID(ctx context.Context, verificationID string) (*domain.Certificate, error) {
+	return r.findOne(ctx, bson.M{"verification_id": verificationID})
+}
+
+// FindByUserAndProduct returns the certificate a buyer earned for a course.
+func (r *MongoCertificateRepository) FindByUserAndProduct(ctx context.Context, userID, productID primitive.ObjectID) (*domain.Certificate, error) {
+	return r.findOne(ctx, bson.M{"user_id": userID, "product_id": productID})
+}
+
+func (r *MongoCertificateRepository) findOne(ctx context.Context, filter bson.M) (*domain.Certificate, error) {
+	var cert domain.Certificate
+	err := r.Collection().FindOne(ctx, filter).Decode(&cert)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find certificate: %w", err)
+	}
+	return &cert, nil
+}
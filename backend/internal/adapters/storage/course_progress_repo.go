@@ -0,0 +1,215 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+const (
+	courseProgressCollection     = "course_progress"
+	courseUnlockNotifsCollection = "course_unlock_notifications" // One per buyer and lesson
+)
+
+// MongoCourseProgressRepository implements domain.CourseProgressRepository using MongoDB.
+type MongoCourseProgressRepository struct {
+	*BaseRepository[domain.CourseProgress]
+}
+
+// NewMongoCourseProgressRepository creates a new MongoCourseProgressRepository.
+func NewMongoCourseProgressRepository(db *MongoDB) *MongoCourseProgressRepository {
+	repo := &MongoCourseProgressRepository{
+		BaseRepository: NewBaseRepository[domain.CourseProgress](db, courseProgressCollection),
+	}
+	repo.ensureIndexes()
+	return repo
+}
+
+// ensureIndexes creates indexes for the course_progress collection.
+func (r *MongoCourseProgressRepository) ensureIndexes() {
+	ctx := context.Background()
+	col := r.Collection()
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "product_id", Value: 1}},
+			Options: options.Index().SetName("idx_user_product").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "product_id", Value: 1}},
+			Options: options.Index().SetName("idx_product_id"),
+		},
+	}
+
+	_, err := col.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		logger.Error("Failed to ensure indexes for course progress", "error", err)
+	}
+
+	_, err = r.unlockNotifs().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "product_id", Value: 1}, {Key: "email", Value: 1}, {Key: "lesson_id", Value: 1}},
+		Options: options.Index().SetName("idx_product_email_lesson").SetUnique(true),
+	})
+	if err != nil {
+		logger.Error("Failed to ensure indexes for course unlock notifications", "error", err)
+	}
+}
+
+func (r *MongoCourseProgressRepository) unlockNotifs() *mongo.Collection {
+	return r.Collection().Database().Collection(courseUnlockNotifsCollection)
+}
+
+// MarkUnlockNotified inserts the notification record; the unique index rejects repeats.
+func (r *MongoCourseProgressRepository) MarkUnlockNotified(ctx context.Context, productID primitive.ObjectID, email, lessonID string) (bool, error) {
+	_, err := r.unlockNotifs().InsertOne(ctx, bson.M{
+		"product_id": productID,
+		"email":      strings.ToLower(email),
+		"lesson_id":  lessonID,
+		"created_at": time.Now(),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("record unlock notification: %w", err)
+	}
+	return true, nil
+}
+
+// FindByUserAndProduct returns a buyer's progress for a course, or nil if none is recorded.
+func (r *MongoCourseProgressRepository) FindByUserAndProduct(ctx context.Context, userID, productID primitive.ObjectID) (*domain.CourseProgress, error) {
+	var progress domain.CourseProgress
+	err := r.Collection().FindOne(ctx, bson.M{"user_id": userID, "product_id": productID}).Decode(&progress)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find course progress: %w", err)
+	}
+	return &progress, nil
+}
+
+// FindByUser returns every course progress record for a buyer.
+func (r *MongoCourseProgressRepository) FindByUser(ctx context.Context, userID primitive.ObjectID) ([]*domain.CourseProgress, error) {
+	return r.findMany(ctx, bson.M{"user_id": userID})
+}
+
+// FindByProduct returns every learner's progress for a course.
+func (r *MongoCourseProgressRepository) FindByProduct(ctx context.Context, productID primitive.ObjectID) ([]*domain.CourseProgress, error) {
+	return r.findMany(ctx, bson.M{"product_id": productID})
+}
+
+func (r *MongoCourseProgressRepository) findMany(ctx context.Context, filter bson.M) ([]*domain.CourseProgress, error) {
+	cursor, err := r.Collection().Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("find course progress: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []*domain.CourseProgress
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("decode course progress: %w", err)
+	}
+	return results, nil
+}
+
+// UpdateLesson updates the lesson's entry in place with array filters, appending the entry
+// first if the buyer hasn't opened the lesson before.
+func (r *MongoCourseProgressRepository) UpdateLesson(ctx context.Context, userID, productID, creatorID primitive.ObjectID, update domain.LessonProgressUpdate) (*domain.CourseProgress, error) {
+	key := bson.M{"user_id": userID, "product_id": productID}
+	create := bson.M{"$setOnInsert": bson.M{
+		"creator_id":         creatorID,
+		"lessons":            bson.A{},
+		"completion_percent": 0,
+		"created_at":         update.At,
+		"updated_at":         update.At,
+	}}
+	if _, err := r.Collection().UpdateOne(ctx, key, create, options.Update().SetUpsert(true)); err != nil && !mongo.IsDuplicateKeyError(err) {
+		return nil, fmt.Errorf("create course progress: %w", err)
+	}
+
+	// Add the entry if it's missing; a concurrent request may get there first, which is fine
+	missing := bson.M{"user_id": userID, "product_id": productID, "lessons.lesson_id": bson.M{"$ne": update.LessonID}}
+	push := bson.M{"$push": bson.M{"lessons": domain.LessonProgress{LessonID: update.LessonID, UpdatedAt: update.At}}}
+	if _, err := r.Collection().UpdateOne(ctx, missing, push); err != nil {
+		return nil, fmt.Errorf("add lesson progress: %w", err)
+	}
+
+	set := bson.M{
+		"lessons.$[l].updated_at": update.At,
+		"last_lesson_id":          update.LessonID,
+		"updated_at":              update.At,
+	}
+	unset := bson.M{}
+	filters := []interface{}{bson.M{"l.lesson_id": update.LessonID}}
+	if update.PositionSeconds != nil && *update.PositionSeconds >= 0 {
+		set["lessons.$[l].position_seconds"] = *update.PositionSeconds
+	}
+	// Only a change of state touches completed_at, so repeating a completion keeps the first time
+	if update.Completed != nil {
+		set["lessons.$[c].completed"] = *update.Completed
+		if *update.Completed {
+			set["lessons.$[c].completed_at"] = update.At
+		} else {
+			unset["lessons.$[c].completed_at"] = ""
+		}
+		filters = append(filters, bson.M{"c.lesson_id": update.LessonID, "c.completed": bson.M{"$ne": *update.Completed}})
+	}
+	change := bson.M{"$set": set}
+	if len(unset) > 0 {
+		change["$unset"] = unset
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetArrayFilters(options.ArrayFilters{Filters: filters}).
+		SetReturnDocument(options.After)
+	var progress domain.CourseProgress
+	if err := r.Collection().FindOneAndUpdate(ctx, key, change, opts).Decode(&progress); err != nil {
+		return nil, fmt.Errorf("update lesson progress: %w", err)
+	}
+	return &progress, nil
+}
+
+// SetCompletion stores the percentage and sets or clears completed_at. completed_at is only
+// set where it is still empty, which is how the first completion is detected.
+func (r *MongoCourseProgressRepository) SetCompletion(ctx context.Context, userID, productID primitive.ObjectID, percent int, at time.Time) (bool, error) {
+	key := bson.M{"user_id": userID, "product_id": productID}
+	if percent < 100 {
+		update := bson.M{"$set": bson.M{"completion_percent": percent}, "$unset": bson.M{"completed_at": ""}}
+		if _, err := r.Collection().UpdateOne(ctx, key, update); err != nil {
+			return false, fmt.Errorf("update course completion: %w", err)
+		}
+		return false, nil
+	}
+
+	first := bson.M{"user_id": userID, "product_id": productID, "completed_at": nil}
+	update := bson.M{"$set": bson.M{"completion_percent": percent, "completed_at": at}}
+	result, err := r.Collection().UpdateOne(ctx, first, update)
+	if err != nil {
+		return false, fmt.Errorf("update course completion: %w", err)
+	}
+	if result.MatchedCount == 1 {
+		return true, nil
+	}
+	if _, err := r.Collection().UpdateOne(ctx, key, bson.M{"$set": bson.M{"completion_percent": percent}}); err != nil {
+		return false, fmt.Errorf("update course completion: %w", err)
+	}
+	return false, nil
+}
+
+// DeleteByUser removes every course progress record of a buyer.
+func (r *MongoCourseProgressRepository) DeleteByUser(ctx context.Context, userID primitive.ObjectID) error {
+	if _, err := r.Collection().DeleteMany(ctx, bson.M{"user_id": userID}); err != nil {
+		return fmt.Errorf("delete course progress: %w", err)
+	}
+	return nil
+}
@@ -108,6 +108,25 @@ func (r *MongoCourseRepository) Update(ctx context.Context, course *domain.Cours
 	return nil
 }
 
+// UpdateCertificate replaces the course's certificate template
+func (r *MongoCourseRepository) UpdateCertificate(ctx context.Context, courseID primitive.ObjectID, tmpl *domain.CertificateTemplate) error {
+	updateDoc := bson.M{
+		"$set": bson.M{
+			"certificate": tmpl,
+			"updated_at":  time.Now(),
+		},
+	}
+
+	result, err := r.Collection().UpdateByID(ctx, courseID, updateDoc)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrCourseNotFound
+	}
+	return nil
+}
+
 // DeleteByProductID removes the course document mapping to a specific product
 func (r *MongoCourseRepository) DeleteByProductID(ctx context.Context, productID primitive.ObjectID) error {
 	result, err := r.Collection().DeleteOne(ctx, bson.M{"product_id": productID})
@@ -119,3 +138,25 @@ func (r *MongoCourseRepository) DeleteByProductID(ctx context.Context, productID
 	}
 	return nil
 }
+
+// FindWithDripRules returns courses where at least one module or lesson is drip-released
+func (r *MongoCourseRepository) FindWithDripRules(ctx context.Context) ([]*domain.Course, error) {
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"modules.drip": bson.M{"$exists": true}},
+			bson.M{"modules.lessons.drip": bson.M{"$exists": true}},
+		},
+	}
+
+	cursor, err := r.Collection().Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var courses []*domain.Course
+	if err := cursor.All(ctx, &courses); err != nil {
+		return nil, err
+	}
+	return courses, nil
+}
@@ -0,0 +1,283 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+const (
+	webhookEndpointsCollection  = "webhook_endpoints"
+	webhookDeliveriesCollection = "webhook_deliveries"
+
+	webhookDeliveryRetention = 30 * 24 * time.Hour
+	webhookMaxLoggedAttempts = 20
+)
+
+// MongoWebhookEndpointRepository implements domain.WebhookEndpointRepository using MongoDB.
+type MongoWebhookEndpointRepository struct {
+	*BaseRepository[domain.WebhookEndpoint]
+}
+
+// NewMongoWebhookEndpointRepository creates a new MongoWebhookEndpointRepository.
+func NewMongoWebhookEndpointRepository(db *MongoDB) *MongoWebhookEndpointRepository {
+	repo := &MongoWebhookEndpointRepository{
+		BaseRepository: NewBaseRepository[domain.WebhookEndpoint](db, webhookEndpointsCollection),
+	}
+	repo.ensureIndexes()
+	return repo
+}
+
+// ensureIndexes creates indexes for the webhook_endpoints collection.
+func (r *MongoWebhookEndpointRepository) ensureIndexes() {
+	ctx := context.Background()
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "creator_id", Value: 1}, {Key: "events", Value: 1}},
+			Options: options.Index().SetName("idx_creator_events"),
+		},
+	}
+	if _, err := r.Collection().Indexes().CreateMany(ctx, indexes); err != nil {
+		logger.Error("Failed to ensure indexes for webhook endpoints", "error", err)
+	}
+}
+
+// Create inserts a new webhook endpoint.
+func (r *MongoWebhookEndpointRepository) Create(ctx context.Context, endpoint *domain.WebhookEndpoint) error {
+	now := time.Now()
+	endpoint.CreatedAt = now
+	endpoint.UpdatedAt = now
+	result, err := r.Collection().InsertOne(ctx, endpoint)
+	if err != nil {
+		return fmt.Errorf("insert webhook endpoint: %w", err)
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		endpoint.ID = oid
+	}
+	return nil
+}
+
+// FindByID returns a webhook endpoint by ID.
+func (r *MongoWebhookEndpointRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.WebhookEndpoint, error) {
+	var endpoint domain.WebhookEndpoint
+	if err := r.Collection().FindOne(ctx, bson.M{"_id": id}).Decode(&endpoint); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find webhook endpoint: %w", err)
+	}
+	return &endpoint, nil
+}
+
+// FindByCreator lists a creator's endpoints, oldest first.
+func (r *MongoWebhookEndpointRepository) FindByCreator(ctx context.Context, creatorID primitive.ObjectID) ([]*domain.WebhookEndpoint, error) {
+	return r.find(ctx, bson.M{"creator_id": creatorID})
+}
+
+// FindEnabledForEvent returns the creator's enabled endpoints subscribed to event.
+func (r *MongoWebhookEndpointRepository) FindEnabledForEvent(ctx context.Context, creatorID primitive.ObjectID, event string) ([]*domain.WebhookEndpoint, error) {
+	return r.find(ctx, bson.M{"creator_id": creatorID, "events": event, "enabled": true})
+}
+
+// Update saves changes to an endpoint.
+func (r *MongoWebhookEndpointRepository) Update(ctx context.Context, endpoint *domain.WebhookEndpoint) error {
+	endpoint.UpdatedAt = time.Now()
+	if _, err := r.Collection().ReplaceOne(ctx, bson.M{"_id": endpoint.ID}, endpoint); err != nil {
+		return fmt.Errorf("update webhook endpoint: %w", err)
+	}
+	return nil
+}
+
+// Delete removes an endpoint.
+func (r *MongoWebhookEndpointRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	if _, err := r.Collection().DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		return fmt.Errorf("delete webhook endpoint: %w", err)
+	}
+	return nil
+}
+
+// RecordSuccess clears the endpoint's failure streak.
+func (r *MongoWebhookEndpointRepository) RecordSuccess(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.Collection().UpdateOne(ctx,
+		bson.M{"_id": id, "consecutive_failures": bson.M{"$gt": 0}},
+		bson.M{
+			"$set":   bson.M{"consecutive_failures": 0},
+			"$unset": bson.M{"failing_since": ""},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("record webhook success: %w", err)
+	}
+	return nil
+}
+
+// RecordFailure extends the endpoint's failure streak, starting it at the given time if needed.
+func (r *MongoWebhookEndpointRepository) RecordFailure(ctx context.Context, id primitive.ObjectID, at time.Time) (*domain.WebhookEndpoint, error) {
+	var endpoint domain.WebhookEndpoint
+	err := r.Collection().FindOneAndUpdate(ctx,
+		bson.M{"_id": id},
+		bson.M{
+			"$inc": bson.M{"consecutive_failures": 1},
+			"$min": bson.M{"failing_since": at},
+		},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&endpoint)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("record webhook failure: %w", err)
+	}
+	return &endpoint, nil
+}
+
+// Disable turns the endpoint off.
+func (r *MongoWebhookEndpointRepository) Disable(ctx context.Context, id primitive.ObjectID, reason string) error {
+	_, err := r.Collection().UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"enabled": false, "disabled_reason": reason, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		return fmt.Errorf("disable webhook endpoint: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoWebhookEndpointRepository) find(ctx context.Context, filter bson.M) ([]*domain.WebhookEndpoint, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.Collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find webhook endpoints: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var endpoints []*domain.WebhookEndpoint
+	if err := cursor.All(ctx, &endpoints); err != nil {
+		return nil, fmt.Errorf("decode webhook endpoints: %w", err)
+	}
+	return endpoints, nil
+}
+
+// MongoWebhookDeliveryRepository implements domain.WebhookDeliveryRepository using MongoDB.
+// Deliveries expire from the log after 30 days.
+type MongoWebhookDeliveryRepository struct {
+	*BaseRepository[domain.WebhookDelivery]
+}
+
+// NewMongoWebhookDeliveryRepository creates a new MongoWebhookDeliveryRepository.
+func NewMongoWebhookDeliveryRepository(db *MongoDB) *MongoWebhookDeliveryRepository {
+	repo := &MongoWebhookDeliveryRepository{
+		BaseRepository: NewBaseRepository[domain.WebhookDelivery](db, webhookDeliveriesCollection),
+	}
+	repo.ensureIndexes()
+	return repo
+}
+
+// ensureIndexes creates indexes for the webhook_deliveries collection.
+func (r *MongoWebhookDeliveryRepository) ensureIndexes() {
+	ctx := context.Background()
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "endpoint_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_endpoint_created"),
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetName("idx_created_ttl").SetExpireAfterSeconds(int32(webhookDeliveryRetention.Seconds())),
+		},
+	}
+	if _, err := r.Collection().Indexes().CreateMany(ctx, indexes); err != nil {
+		logger.Error("Failed to ensure indexes for webhook deliveries", "error", err)
+	}
+}
+
+// Create inserts a new delivery.
+func (r *MongoWebhookDeliveryRepository) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	delivery.CreatedAt = time.Now()
+	if delivery.Attempts == nil {
+		delivery.Attempts = []domain.WebhookAttempt{}
+	}
+	result, err := r.Collection().InsertOne(ctx, delivery)
+	if err != nil {
+		return fmt.Errorf("insert webhook delivery: %w", err)
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		delivery.ID = oid
+	}
+	return nil
+}
+
+// FindByID returns a delivery by ID.
+func (r *MongoWebhookDeliveryRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	if err := r.Collection().FindOne(ctx, bson.M{"_id": id}).Decode(&delivery); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find webhook delivery: %w", err)
+	}
+	return &delivery, nil
+}
+
+// FindByEndpoint lists an endpoint's deliveries, newest first, with the total count.
+func (r *MongoWebhookDeliveryRepository) FindByEndpoint(ctx context.Context, endpointID primitive.ObjectID, limit, offset int64) ([]*domain.WebhookDelivery, int64, error) {
+	filter := bson.M{"endpoint_id": endpointID}
+	total, err := r.Collection().CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count webhook deliveries: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetSkip(offset).
+		SetLimit(limit)
+	cursor, err := r.Collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("find webhook deliveries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var deliveries []*domain.WebhookDelivery
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, 0, fmt.Errorf("decode webhook deliveries: %w", err)
+	}
+	return deliveries, total, nil
+}
+
+// RecordAttempt appends an attempt, keeping the latest few, and updates the delivery status.
+func (r *MongoWebhookDeliveryRepository) RecordAttempt(ctx context.Context, id primitive.ObjectID, attempt domain.WebhookAttempt, status domain.WebhookDeliveryStatus, nextAttemptAt *time.Time) error {
+	set := bson.M{"status": status, "response_status": attempt.ResponseStatus}
+	update := bson.M{
+		"$push": bson.M{"attempts": bson.M{"$each": []domain.WebhookAttempt{attempt}, "$slice": -webhookMaxLoggedAttempts}},
+		"$set":  set,
+	}
+	if status == domain.WebhookDeliverySucceeded {
+		set["delivered_at"] = attempt.At
+	}
+	if nextAttemptAt != nil {
+		set["next_attempt_at"] = *nextAttemptAt
+	} else {
+		update["$unset"] = bson.M{"next_attempt_at": ""}
+	}
+
+	if _, err := r.Collection().UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		return fmt.Errorf("record webhook attempt: %w", err)
+	}
+	return nil
+}
+
+// DeleteByEndpoint removes an endpoint's delivery log.
+func (r *MongoWebhookDeliveryRepository) DeleteByEndpoint(ctx context.Context, endpointID primitive.ObjectID) error {
+	if _, err := r.Collection().DeleteMany(ctx, bson.M{"endpoint_id": endpointID}); err != nil {
+		return fmt.Errorf("delete webhook deliveries: %w", err)
+	}
+	return nil
+}
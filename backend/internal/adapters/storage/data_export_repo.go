@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+const dataExportsCollection = "data_exports"
+
+// MongoDataExportRepository implements domain.DataExportRepository using MongoDB.
+type MongoDataExportRepository struct {
+	*BaseRepository[domain.DataExport]
+}
+
+// NewMongoDataExportRepository creates a new MongoDataExportRepository.
+func NewMongoDataExportRepository(db *MongoDB) *MongoDataExportRepository {
+	repo := &MongoDataExportRepository{
+		BaseRepository: NewBaseRepository[domain.DataExport](db, dataExportsCollection),
+	}
+	repo.ensureIndexes()
+	return repo
+}
+
+// ensureIndexes creates indexes for the data_exports collection.
+func (r *MongoDataExportRepository) ensureIndexes() {
+	ctx := context.Background()
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_user_created"),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}},
+			Options: options.Index().SetName("idx_status_expires"),
+		},
+	}
+	if _, err := r.Collection().Indexes().CreateMany(ctx, indexes); err != nil {
+		logger.Error("Failed to ensure indexes for data exports", "error", err)
+	}
+}
+
+// Create inserts a new data export.
+func (r *MongoDataExportRepository) Create(ctx context.Context, export *domain.DataExport) error {
+	export.CreatedAt = time.Now()
+	result, err := r.Collection().InsertOne(ctx, export)
+	if err != nil {
+		return fmt.Errorf("insert data export: %w", err)
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		export.ID = oid
+	}
+	return nil
+}
+
+// FindByID returns a data export by ID.
+func (r *MongoDataExportRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.DataExport, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+// FindLatestByUser returns the user's most recent export.
+func (r *MongoDataExportRepository) FindLatestByUser(ctx context.Context, userID primitive.ObjectID) (*domain.DataExport, error) {
+	return r.findOne(ctx, bson.M{"user_id": userID}, options.FindOne().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+}
+
+// Update saves changes to a data export.
+func (r *MongoDataExportRepository) Update(ctx context.Context, export *domain.DataExport) error {
+	if _, err := r.Collection().ReplaceOne(ctx, bson.M{"_id": export.ID}, export); err != nil {
+		return fmt.Errorf("update data export: %w", err)
+	}
+	return nil
+}
+
+// FindExpired returns ready exports whose archive expired before the given time.
+func (r *MongoDataExportRepository) FindExpired(ctx context.Context, before time.Time) ([]*domain.DataExport, error) {
+	filter := bson.M{"status": domain.DataExportReady, "expires_at": bson.M{"$lt": before}}
+	cursor, err := r.Collection().Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("find expired data exports: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []*domain.DataExport
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("decode data exports: %w", err)
+	}
+	return results, nil
+}
+
+// DeleteByUser removes every export record of the user.
+func (r *MongoDataExportRepository) DeleteByUser(ctx context.Context, userID primitive.ObjectID) error {
+	if _, err := r.Collection().DeleteMany(ctx, bson.M{"user_id": userID}); err != nil {
+		return fmt.Errorf("delete data exports: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoDataExportRepository) findOne(ctx context.Context, filter bson.M, opts ...*options.FindOneOptions) (*domain.DataExport, error) {
+	var export domain.DataExport
+	if err := r.Collection().FindOne(ctx, filter, opts...).Decode(&export); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find data export: %w", err)
+	}
+	return &export, nil
+}
@@ -0,0 +1,238 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+const (
+	downloadEntitlementsCollection = "download_entitlements"
+	downloadLogsCollection         = "download_logs"
+)
+
+// MongoDownloadEntitlementRepository implements domain.DownloadEntitlementRepository using MongoDB.
+type MongoDownloadEntitlementRepository struct {
+	*BaseRepository[domain.DownloadEntitlement]
+}
+
+// NewMongoDownloadEntitlementRepository creates a new MongoDownloadEntitlementRepository.
+func NewMongoDownloadEntitlementRepository(db *MongoDB) *MongoDownloadEntitlementRepository {
+	repo := &MongoDownloadEntitlementRepository{
+		BaseRepository: NewBaseRepository[domain.DownloadEntitlement](db, downloadEntitlementsCollection),
+	}
+	repo.ensureIndexes()
+	return repo
+}
+
+// ensureIndexes creates indexes for the download_entitlements collection.
+func (r *MongoDownloadEntitlementRepository) ensureIndexes() {
+	ctx := context.Background()
+	_, err := r.Collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "order_id", Value: 1}, {Key: "product_id", Value: 1}},
+		Options: options.Index().SetName("idx_order_product").SetUnique(true),
+	})
+	if err != nil {
+		logger.Error("Failed to ensure indexes for download entitlements", "error", err)
+	}
+}
+
+// FindOrCreate returns the entitlement for ent's order and product, inserting ent when none exists yet.
+func (r *MongoDownloadEntitlementRepository) FindOrCreate(ctx context.Context, ent *domain.DownloadEntitlement) (*domain.DownloadEntitlement, error) {
+	now := time.Now()
+	filter := bson.M{"order_id": ent.OrderID, "product_id": ent.ProductID}
+	update := bson.M{"$setOnInsert": bson.M{
+		"order_id":       ent.OrderID,
+		"product_id":     ent.ProductID,
+		"creator_id":     ent.CreatorID,
+		"buyer_email":    ent.BuyerEmail,
+		"buyer_name":     ent.BuyerName,
+		"download_count": 0,
+		"max_downloads":  ent.MaxDownloads,
+		"expires_at":     ent.ExpiresAt,
+		"created_at":     now,
+		"updated_at":     now,
+	}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var result domain.DownloadEntitlement
+	if err := r.Collection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&result); err != nil {
+		return nil, fmt.Errorf("upsert download entitlement: %w", err)
+	}
+	return &result, nil
+}
+
+// FindByID returns an entitlement by ID.
+func (r *MongoDownloadEntitlementRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.DownloadEntitlement, error) {
+	var ent domain.DownloadEntitlement
+	err := r.Collection().FindOne(ctx, bson.M{"_id": id}).Decode(&ent)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find download entitlement: %w", err)
+	}
+	return &ent, nil
+}
+
+// Consume increments the download count unless the entitlement's limit has been reached.
+func (r *MongoDownloadEntitlementRepository) Consume(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	now := time.Now()
+	filter := bson.M{
+		"_id": id,
+		"$or": bson.A{
+			bson.M{"max_downloads": 0},
+			bson.M{"$expr": bson.M{"$lt": bson.A{"$download_count", "$max_downloads"}}},
+		},
+	}
+	update := bson.M{
+		"$inc": bson.M{"download_count": 1},
+		"$set": bson.M{"last_download_at": now, "updated_at": now},
+	}
+	result, err := r.Collection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("consume download entitlement: %w", err)
+	}
+	return result.ModifiedCount == 1, nil
+}
+
+// Lock blocks further downloads for an entitlement.
+func (r *MongoDownloadEntitlementRepository) Lock(ctx context.Context, id primitive.ObjectID, reason string) error {
+	now := time.Now()
+	_, err := r.Collection().UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{"locked_at": now, "lock_reason": reason, "updated_at": now},
+	})
+	if err != nil {
+		return fmt.Errorf("lock download entitlement: %w", err)
+	}
+	return nil
+}
+
+// Unlock lifts a lock and resets the download count so the buyer starts fresh.
+func (r *MongoDownloadEntitlementRepository) Unlock(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.Collection().UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set":   bson.M{"download_count": 0, "updated_at": time.Now()},
+		"$unset": bson.M{"locked_at": "", "lock_reason": ""},
+	})
+	if err != nil {
+		return fmt.Errorf("unlock download entitlement: %w", err)
+	}
+	return nil
+}
+
+// SetStampResult stores the outcome of personalising one file version of the entitlement.
+func (r *MongoDownloadEntitlementRepository) SetStampResult(ctx context.Context, id primitive.ObjectID, stampKey string, stamped domain.StampedCopy) error {
+	_, err := r.Collection().UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{"stamps." + stampKey: stamped, "updated_at": time.Now()},
+	})
+	if err != nil {
+		return fmt.Errorf("set stamp result: %w", err)
+	}
+	return nil
+}
+
+// MongoDownloadLogRepository implements domain.DownloadLogRepository using MongoDB.
+type MongoDownloadLogRepository struct {
+	*BaseRepository[domain.DownloadLog]
+}
+
+// NewMongoDownloadLogRepository creates a new MongoDownloadLogRepository.
+func NewMongoDownloadLogRepository(db *MongoDB) *MongoDownloadLogRepository {
+	repo := &MongoDownloadLogRepository{
+		BaseRepository: NewBaseRepository[domain.DownloadLog](db, downloadLogsCollection),
+	}
+	repo.ensureIndexes()
+	return repo
+}
+
+// ensureIndexes creates indexes for the download_logs collection.
+func (r *MongoDownloadLogRepository) ensureIndexes() {
+	ctx := context.Background()
+	col := r.Collection()
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "creator_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_creator_created"),
+		},
+		{
+			Keys:    bson.D{{Key: "entitlement_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_entitlement_created"),
+		},
+	}
+
+	_, err := col.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		logger.Error("Failed to ensure indexes for download logs", "error", err)
+	}
+}
+
+// Create appends an entry to the audit log.
+func (r *MongoDownloadLogRepository) Create(ctx context.Context, log *domain.DownloadLog) error {
+	log.CreatedAt = time.Now()
+	result, err := r.Collection().InsertOne(ctx, log)
+	if err != nil {
+		return fmt.Errorf("insert download log: %w", err)
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		log.ID = oid
+	}
+	return nil
+}
+
+// FindByCreator lists a creator's download log, newest first.
+func (r *MongoDownloadLogRepository) FindByCreator(ctx context.Context, creatorID primitive.ObjectID, filter domain.DownloadLogFilter) ([]*domain.DownloadLog, error) {
+	query := bson.M{"creator_id": creatorID}
+	if filter.ProductID != nil {
+		query["product_id"] = *filter.ProductID
+	}
+	if filter.OrderID != nil {
+		query["order_id"] = *filter.OrderID
+	}
+
+	limit := filter.Limit
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+
+	cursor, err := r.Collection().Find(ctx, query, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find download logs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	logs := []*domain.DownloadLog{}
+	if err := cursor.All(ctx, &logs); err != nil {
+		return nil, fmt.Errorf("decode download logs: %w", err)
+	}
+	return logs, nil
+}
+
+// DistinctIPsSince returns the distinct IPs with a successful download since the given time.
+func (r *MongoDownloadLogRepository) DistinctIPsSince(ctx context.Context, entitlementID primitive.ObjectID, since time.Time) ([]string, error) {
+	values, err := r.Collection().Distinct(ctx, "ip", bson.M{
+		"entitlement_id": entitlementID,
+		"allowed":        true,
+		"created_at":     bson.M{"$gte": since},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("distinct download ips: %w", err)
+	}
+
+	ips := make([]string, 0, len(values))
+	for _, v := range values {
+		if ip, ok := v.(string); ok {
+			ips = append(ips, ip)
+		}
+	}
+	return ips, nil
+}
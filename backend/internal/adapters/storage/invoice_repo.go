@@ -0,0 +1,254 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+const (
+	invoiceSettingsCollection = "invoice_settings"
+	invoicesCollection        = "invoices"
+)
+
+var errInvoiceClaimLost = errors.New("invoice claim was taken over")
+
+// MongoInvoiceSettingsRepository implements domain.InvoiceSettingsRepository using MongoDB.
+// Each creator's document also holds their invoice number counters, one per financial year.
+type MongoInvoiceSettingsRepository struct {
+	*BaseRepository[domain.InvoiceSettings]
+}
+
+// NewMongoInvoiceSettingsRepository creates a new MongoInvoiceSettingsRepository.
+func NewMongoInvoiceSettingsRepository(db *MongoDB) *MongoInvoiceSettingsRepository {
+	repo := &MongoInvoiceSettingsRepository{
+		BaseRepository: NewBaseRepository[domain.InvoiceSettings](db, invoiceSettingsCollection),
+	}
+	repo.ensureIndexes()
+	return repo
+}
+
+// ensureIndexes creates indexes for the invoice_settings collection.
+func (r *MongoInvoiceSettingsRepository) ensureIndexes() {
+	ctx := context.Background()
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "creator_id", Value: 1}},
+			Options: options.Index().SetName("idx_creator_id").SetUnique(true),
+		},
+	}
+	if _, err := r.Collection().Indexes().CreateMany(ctx, indexes); err != nil {
+		logger.Error("Failed to ensure indexes for invoice settings", "error", err)
+	}
+}
+
+// FindByCreator returns the creator's invoice settings.
+func (r *MongoInvoiceSettingsRepository) FindByCreator(ctx context.Context, creatorID primitive.ObjectID) (*domain.InvoiceSettings, error) {
+	var settings domain.InvoiceSettings
+	if err := r.Collection().FindOne(ctx, bson.M{"creator_id": creatorID}).Decode(&settings); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find invoice settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// Upsert saves the creator's editable settings without touching their counters.
+func (r *MongoInvoiceSettingsRepository) Upsert(ctx context.Context, settings *domain.InvoiceSettings) error {
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"legal_name":         settings.LegalName,
+			"gstin":              settings.GSTIN,
+			"address_line1":      settings.AddressLine1,
+			"address_line2":      settings.AddressLine2,
+			"city":               settings.City,
+			"postal_code":        settings.PostalCode,
+			"state_code":         settings.StateCode,
+			"tax_rate":           settings.TaxRate,
+			"prices_include_tax": settings.PricesIncludeTax,
+			"invoice_prefix":     settings.InvoicePrefix,
+			"sac_code":           settings.SACCode,
+			"updated_at":         now,
+		},
+		"$setOnInsert": bson.M{"created_at": now},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var saved domain.InvoiceSettings
+	if err := r.Collection().FindOneAndUpdate(ctx, bson.M{"creator_id": settings.CreatorID}, update, opts).Decode(&saved); err != nil {
+		return fmt.Errorf("upsert invoice settings: %w", err)
+	}
+	settings.ID = saved.ID
+	settings.CreatedAt = saved.CreatedAt
+	settings.UpdatedAt = saved.UpdatedAt
+	return nil
+}
+
+// NextSequence increments the creator's counter for financialYear and returns the new value.
+// Creators who haven't saved settings yet get a document holding only their counters.
+func (r *MongoInvoiceSettingsRepository) NextSequence(ctx context.Context, creatorID primitive.ObjectID, financialYear string) (int64, error) {
+	field := "counters." + financialYear
+	update := bson.M{
+		"$inc":         bson.M{field: 1},
+		"$setOnInsert": bson.M{"created_at": time.Now()},
+	}
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{field: 1})
+
+	var result struct {
+		Counters map[string]int64 `bson:"counters"`
+	}
+	if err := r.Collection().FindOneAndUpdate(ctx, bson.M{"creator_id": creatorID}, update, opts).Decode(&result); err != nil {
+		return 0, fmt.Errorf("allocate invoice number: %w", err)
+	}
+	return result.Counters[financialYear], nil
+}
+
+// MongoInvoiceRepository implements domain.InvoiceRepository using MongoDB.
+type MongoInvoiceRepository struct {
+	*BaseRepository[domain.Invoice]
+}
+
+// NewMongoInvoiceRepository creates a new MongoInvoiceRepository.
+func NewMongoInvoiceRepository(db *MongoDB) *MongoInvoiceRepository {
+	repo := &MongoInvoiceRepository{
+		BaseRepository: NewBaseRepository[domain.Invoice](db, invoicesCollection),
+	}
+	repo.ensureIndexes()
+	return repo
+}
+
+// ensureIndexes creates indexes for the invoices collection.
+func (r *MongoInvoiceRepository) ensureIndexes() {
+	ctx := context.Background()
+	// Replaced by idx_creator_number_issued, which leaves pending claims without numbers out
+	_, _ = r.Collection().Indexes().DropOne(ctx, "idx_creator_number")
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "order_id", Value: 1}},
+			Options: options.Index().SetName("idx_order_id").SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "creator_id", Value: 1}, {Key: "number", Value: 1}},
+			Options: options.Index().SetName("idx_creator_number_issued").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"number": bson.M{"$exists": true}}),
+		},
+		{
+			Keys:    bson.D{{Key: "creator_id", Value: 1}, {Key: "issued_at", Value: 1}},
+			Options: options.Index().SetName("idx_creator_issued"),
+		},
+	}
+	if _, err := r.Collection().Indexes().CreateMany(ctx, indexes); err != nil {
+		logger.Error("Failed to ensure indexes for invoices", "error", err)
+	}
+}
+
+// Claim inserts a pending invoice for the order; the unique order index lets only one
+// request through.
+func (r *MongoInvoiceRepository) Claim(ctx context.Context, creatorID, orderID primitive.ObjectID, staleBefore time.Time) (string, error) {
+	token := primitive.NewObjectID().Hex()
+	now := time.Now()
+	_, err := r.Collection().InsertOne(ctx, bson.M{
+		"creator_id":  creatorID,
+		"order_id":    orderID,
+		"status":      domain.InvoiceStatusPending,
+		"claim_token": token,
+		"created_at":  now,
+	})
+	if err == nil {
+		return token, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return "", fmt.Errorf("claim invoice: %w", err)
+	}
+
+	// Take over a claim whose holder never finished
+	filter := bson.M{
+		"order_id":   orderID,
+		"status":     domain.InvoiceStatusPending,
+		"created_at": bson.M{"$lt": staleBefore},
+	}
+	result, err := r.Collection().UpdateOne(ctx, filter, bson.M{"$set": bson.M{"claim_token": token, "created_at": now}})
+	if err != nil {
+		return "", fmt.Errorf("claim invoice: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return "", nil
+	}
+	return token, nil
+}
+
+// Issue fills in the claimed invoice and marks it issued.
+func (r *MongoInvoiceRepository) Issue(ctx context.Context, invoice *domain.Invoice, claimToken string) error {
+	invoice.Status = domain.InvoiceStatusIssued
+	invoice.CreatedAt = time.Now()
+	raw, err := bson.Marshal(invoice)
+	if err != nil {
+		return fmt.Errorf("encode invoice: %w", err)
+	}
+	var set bson.M
+	if err := bson.Unmarshal(raw, &set); err != nil {
+		return fmt.Errorf("encode invoice: %w", err)
+	}
+	delete(set, "_id")
+
+	filter := bson.M{"order_id": invoice.OrderID, "claim_token": claimToken}
+	update := bson.M{"$set": set, "$unset": bson.M{"claim_token": ""}}
+	opts := options.FindOneAndUpdate().SetProjection(bson.M{"_id": 1})
+	var saved struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := r.Collection().FindOneAndUpdate(ctx, filter, update, opts).Decode(&saved); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return errInvoiceClaimLost
+		}
+		return fmt.Errorf("issue invoice: %w", err)
+	}
+	invoice.ID = saved.ID
+	return nil
+}
+
+// FindByOrder returns the invoice issued for an order.
+func (r *MongoInvoiceRepository) FindByOrder(ctx context.Context, orderID primitive.ObjectID) (*domain.Invoice, error) {
+	var invoice domain.Invoice
+	if err := r.Collection().FindOne(ctx, bson.M{"order_id": orderID}).Decode(&invoice); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find invoice: %w", err)
+	}
+	return &invoice, nil
+}
+
+// FindByCreatorAndPeriod lists the creator's invoices issued in [from, to).
+func (r *MongoInvoiceRepository) FindByCreatorAndPeriod(ctx context.Context, creatorID primitive.ObjectID, from, to time.Time) ([]*domain.Invoice, error) {
+	filter := bson.M{
+		"creator_id": creatorID,
+		"issued_at":  bson.M{"$gte": from, "$lt": to},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "issued_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := r.Collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find invoices: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	invoices := []*domain.Invoice{}
+	if err := cursor.All(ctx, &invoices); err != nil {
+		return nil, fmt.Errorf("decode invoices: %w", err)
+	}
+	return invoices, nil
+}
@@ -0,0 +1,236 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+)
+
+var (
+	ErrInvalidStorageKey       = errors.New("invalid storage key")
+	ErrInvalidStorageSignature = errors.New("invalid or expired storage signature")
+)
+
+// LocalStorage implements the FileStorage interface on the local disk for development and
+// tests. Presigned URLs point at the API's /storage endpoints and carry an HMAC signature
+// over the method, key and expiry, mirroring S3's presigned requests.
+type LocalStorage struct {
+	root    string
+	baseURL string // e.g. http://localhost:8080/api/v1/storage
+	secret  []byte
+}
+
+// NewLocalStorage creates the root directory if needed and returns a LocalStorage adapter.
+// URLs are signed with a key derived from secret.
+func NewLocalStorage(root string, baseURL string, secret string) (*LocalStorage, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage path: %w", err)
+	}
+	if err := os.MkdirAll(abs, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+	key := sha256.Sum256([]byte("local-storage:" + secret))
+	return &LocalStorage{
+		root:    abs,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		secret:  key[:],
+	}, nil
+}
+
+// GeneratePresignedURL returns a signed URL that accepts a PUT of the object until expiry.
+func (s *LocalStorage) GeneratePresignedURL(ctx context.Context, key string, contentType string, expiry time.Duration) (string, error) {
+	return s.presign("PUT", key, expiry)
+}
+
+// GeneratePresignedDownloadURL returns a signed URL that serves the object until expiry.
+func (s *LocalStorage) GeneratePresignedDownloadURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	return s.presign("GET", key, expiry)
+}
+
+func (s *LocalStorage) presign(method, key string, expiry time.Duration) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+	expires := strconv.FormatInt(time.Now().Add(expiry).Unix(), 10)
+	q := url.Values{}
+	q.Set("expires", expires)
+	q.Set("signature", s.signature(method, key, expires))
+	escaped := (&url.URL{Path: key}).EscapedPath()
+	return s.baseURL + "/" + escaped + "?" + q.Encode(), nil
+}
+
+// VerifySignature checks a presigned request for key made with method.
+func (s *LocalStorage) VerifySignature(method, key, expires, signature string) error {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return ErrInvalidStorageSignature
+	}
+	expected := s.signature(method, key, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidStorageSignature
+	}
+	return nil
+}
+
+func (s *LocalStorage) signature(method, key, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(method + "\n" + key + "\n" + expires))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Upload writes an object to disk.
+func (s *LocalStorage) Upload(ctx context.Context, key string, contentType string, data []byte) error {
+	return s.Write(ctx, key, bytes.NewReader(data))
+}
+
+// Write streams an object to disk. The file is written under a temporary name and renamed
+// so readers never see a partial object.
+func (s *LocalStorage) Write(ctx context.Context, key string, r io.Reader) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	return nil
+}
+
+// Head returns an object's size and modification time. The content type is derived from the
+// key's extension.
+func (s *LocalStorage) Head(ctx context.Context, key string) (*domain.FileInfo, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	st, err := os.Stat(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to head object: %w", err)
+	}
+	return &domain.FileInfo{
+		Key:          key,
+		Size:         st.Size(),
+		ContentType:  mime.TypeByExtension(path.Ext(key)),
+		LastModified: st.ModTime(),
+	}, nil
+}
+
+// Download reads a whole object from disk.
+func (s *LocalStorage) Download(ctx context.Context, key string) ([]byte, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object: %w", err)
+	}
+	return data, nil
+}
+
+// Open streams an object from disk.
+func (s *LocalStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	p, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object: %w", err)
+	}
+	return f, nil
+}
+
+// Delete removes an object. Like S3, deleting a missing object is not an error.
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	return nil
+}
+
+// List walks the directory tree for objects under prefix.
+func (s *LocalStorage) List(ctx context.Context, prefix string) ([]domain.FileInfo, error) {
+	var files []domain.FileInfo
+	err := filepath.WalkDir(s.root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.root, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, domain.FileInfo{Key: key, Size: info.Size(), LastModified: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %w", err)
+	}
+	return files, nil
+}
+
+// Path returns the file on disk backing key.
+func (s *LocalStorage) Path(key string) (string, error) {
+	return s.path(key)
+}
+
+// path maps a key to a file under the root, rejecting keys that would escape it.
+func (s *LocalStorage) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidStorageKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return "", ErrInvalidStorageKey
+		}
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLocalStorage(t *testing.T, secret string) *LocalStorage {
+	t.Helper()
+	s, err := NewLocalStorage(t.TempDir(), "http://localhost:8080/api/v1/storage/", secret)
+	require.NoError(t, err)
+	return s
+}
+
+func TestLocalStorage_Path(t *testing.T) {
+	s := newTestLocalStorage(t, "secret")
+
+	tests := []struct {
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{key: "products/abc/file.pdf", want: "products/abc/file.pdf"},
+		{key: "a b/ü.txt", want: "a b/ü.txt"},
+		{key: "dots..in..name.txt", want: "dots..in..name.txt"},
+		{key: "", wantErr: true},
+		{key: "/etc/passwd", wantErr: true},
+		{key: "../secret", wantErr: true},
+		{key: "products/../../secret", wantErr: true},
+		{key: "products/..", wantErr: true},
+		{key: "./products/file", wantErr: true},
+		{key: "products//file", wantErr: true},
+		{key: "products/", wantErr: true},
+		{key: `products\..\..\secret`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			got, err := s.path(tt.key)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidStorageKey)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join(s.root, filepath.FromSlash(tt.want)), got)
+			assert.True(t, strings.HasPrefix(got, s.root+string(filepath.Separator)))
+		})
+	}
+}
+
+func TestLocalStorage_VerifySignature(t *testing.T) {
+	s := newTestLocalStorage(t, "secret")
+	other := newTestLocalStorage(t, "other-secret")
+
+	const key = "products/abc/file.pdf"
+	future := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	past := strconv.FormatInt(time.Now().Add(-time.Second).Unix(), 10)
+	valid := s.signature("GET", key, future)
+
+	tests := []struct {
+		name      string
+		method    string
+		key       string
+		expires   string
+		signature string
+		wantErr   bool
+	}{
+		{"valid", "GET", key, future, valid, false},
+		{"expired", "GET", key, past, s.signature("GET", key, past), true},
+		{"expiry moved later", "GET", key, strconv.FormatInt(time.Now().Add(2*time.Hour).Unix(), 10), valid, true},
+		{"other method", "PUT", key, future, valid, true},
+		{"other key", "GET", "products/abc/other.pdf", future, valid, true},
+		{"tampered signature", "GET", key, future, valid[:len(valid)-1] + "A", true},
+		{"signed with another secret", "GET", key, future, other.signature("GET", key, future), true},
+		{"empty signature", "GET", key, future, "", true},
+		{"non-numeric expiry", "GET", key, "tomorrow", valid, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.VerifySignature(tt.method, tt.key, tt.expires, tt.signature)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidStorageSignature)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLocalStorage_PresignRoundTrip(t *testing.T) {
+	s := newTestLocalStorage(t, "secret")
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		key    string
+		method string
+	}{
+		{"plain key", "products/abc/file.pdf", "GET"},
+		{"spaces and unicode", "products/abc/my notes ü.pdf", "GET"},
+		{"reserved characters", "products/abc/50%?#&=.pdf", "PUT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var raw string
+			var err error
+			if tt.method == "PUT" {
+				raw, err = s.GeneratePresignedURL(ctx, tt.key, "application/pdf", time.Minute)
+			} else {
+				raw, err = s.GeneratePresignedDownloadURL(ctx, tt.key, time.Minute)
+			}
+			require.NoError(t, err)
+
+			u, err := url.Parse(raw)
+			require.NoError(t, err)
+			assert.Equal(t, "localhost:8080", u.Host)
+			escaped := strings.TrimPrefix(u.EscapedPath(), "/api/v1/storage/")
+			key, err := url.PathUnescape(escaped)
+			require.NoError(t, err)
+			assert.Equal(t, tt.key, key)
+
+			q := u.Query()
+			assert.NoError(t, s.VerifySignature(tt.method, key, q.Get("expires"), q.Get("signature")))
+		})
+	}
+
+	_, err := s.GeneratePresignedDownloadURL(ctx, "../secret", time.Minute)
+	assert.ErrorIs(t, err, ErrInvalidStorageKey)
+}
+
+func TestLocalStorage_WriteListDelete(t *testing.T) {
+	s := newTestLocalStorage(t, "secret")
+	ctx := context.Background()
+
+	require.NoError(t, s.Write(ctx, "products/abc/file.txt", strings.NewReader("hello")))
+	require.NoError(t, s.Upload(ctx, "products/def/other.txt", "text/plain", []byte("world")))
+	// A temporary file from an interrupted upload is not an object
+	require.NoError(t, os.WriteFile(filepath.Join(s.root, "products", "abc", ".upload-123"), []byte("x"), 0o644))
+
+	data, err := s.Download(ctx, "products/abc/file.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	info, err := s.Head(ctx, "products/abc/file.txt")
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, info.Size)
+	assert.True(t, strings.HasPrefix(info.ContentType, "text/plain"))
+
+	files, err := s.List(ctx, "products/abc/")
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "products/abc/file.txt", files[0].Key)
+
+	require.NoError(t, s.Delete(ctx, "products/abc/file.txt"))
+	require.NoError(t, s.Delete(ctx, "products/abc/file.txt"))
+	_, err = s.Head(ctx, "products/abc/file.txt")
+	assert.Error(t, err)
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -178,6 +179,36 @@ func (r *MongoAffiliateRepository) UpdateCommission(ctx context.Context, affilia
 	return err
 }
 
+func (r *MongoAffiliateRepository) FindAllByEmail(ctx context.Context, email string) ([]*domain.Affiliate, error) {
+	cursor, err := r.affiliateCollection.Find(ctx, bson.M{"email": email})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var affiliates []*domain.Affiliate
+	if err = cursor.All(ctx, &affiliates); err != nil {
+		return nil, err
+	}
+	return affiliates, nil
+}
+
+// Anonymise replaces the affiliate's email and name and suspends their referral codes. Sales
+// and earnings are kept so creators' commission records still balance.
+func (r *MongoAffiliateRepository) Anonymise(ctx context.Context, email, anonEmail, anonName string) error {
+	_, err := r.affiliateCollection.UpdateMany(
+		ctx,
+		bson.M{"email": email},
+		bson.M{"$set": bson.M{
+			"email":      anonEmail,
+			"name":       anonName,
+			"status":     "suspended",
+			"updated_at": time.Now(),
+		}},
+	)
+	return err
+}
+
 // AffiliateSale Operations
 
 func (r *MongoAffiliateSaleRepository) Create(ctx context.Context, sale *domain.AffiliateSale) error {
@@ -3,12 +3,17 @@ package storage
 import (
 	"context"
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type MongoOrderRepository struct {
@@ -16,9 +21,54 @@ type MongoOrderRepository struct {
 }
 
 func NewMongoOrderRepository(db *mongo.Database) *MongoOrderRepository {
-	return &MongoOrderRepository{
+	repo := &MongoOrderRepository{
 		collection: db.Collection("orders"),
 	}
+	repo.ensureIndexes()
+	return repo
+}
+
+// ensureIndexes creates the indexes behind creator order search. Every search is scoped to
+// one creator, so each index leads with creator_id.
+func (r *MongoOrderRepository) ensureIndexes() {
+	ctx := context.Background()
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "creator_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_creator_created"),
+		},
+		{
+			Keys:    bson.D{{Key: "creator_id", Value: 1}, {Key: "status", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_creator_status_created"),
+		},
+		{
+			Keys:    bson.D{{Key: "creator_id", Value: 1}, {Key: "amount", Value: -1}},
+			Options: options.Index().SetName("idx_creator_amount"),
+		},
+		{
+			Keys:    bson.D{{Key: "creator_id", Value: 1}, {Key: "line_items.product_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_creator_line_item_product"),
+		},
+		{
+			Keys:    bson.D{{Key: "creator_id", Value: 1}, {Key: "product_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_creator_product"),
+		},
+		{
+			Keys:    bson.D{{Key: "creator_id", Value: 1}, {Key: "coupon_code", Value: 1}},
+			Options: options.Index().SetName("idx_creator_coupon").SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "creator_id", Value: 1}, {Key: "affiliate_id", Value: 1}},
+			Options: options.Index().SetName("idx_creator_affiliate").SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "customer_email", Value: 1}},
+			Options: options.Index().SetName("idx_customer_email"),
+		},
+	}
+	if _, err := r.collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		logger.Error("Failed to ensure indexes for orders", "error", err)
+	}
 }
 
 func (r *MongoOrderRepository) Create(ctx context.Context, order *domain.Order) error {
@@ -32,13 +82,16 @@ func (r *MongoOrderRepository) Create(ctx context.Context, order *domain.Order)
 
 func (r *MongoOrderRepository) UpdateStatus(ctx context.Context, razorpayOrderID string, status domain.OrderStatus, paymentID string) error {
 	filter := bson.M{"razorpay_order_id": razorpayOrderID}
-	update := bson.M{
-		"$set": bson.M{
-			"status":              status,
-			"razorpay_payment_id": paymentID,
-			"updated_at":          time.Now(),
-		},
+	now := time.Now()
+	set := bson.M{
+		"status":              status,
+		"razorpay_payment_id": paymentID,
+		"updated_at":          now,
+	}
+	if status == domain.OrderStatusPaid {
+		set["paid_at"] = now
 	}
+	update := bson.M{"$set": set}
 
 	result, err := r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
@@ -100,6 +153,154 @@ func (r *MongoOrderRepository) FindAllByCreatorID(ctx context.Context, creatorID
 	return orders, nil
 }
 
+// Search returns a page of the creator's orders matching filter.
+func (r *MongoOrderRepository) Search(ctx context.Context, filter domain.OrderFilter, sort domain.OrderSort, pagination *domain.Pagination) ([]*domain.Order, *domain.PaginationMeta, error) {
+	page := int64(1)
+	pageSize := int64(25)
+	if pagination != nil {
+		if pagination.Page > 0 {
+			page = pagination.Page
+		}
+		if pagination.PageSize > 0 && pagination.PageSize <= 100 {
+			pageSize = pagination.PageSize
+		}
+	}
+
+	query := orderQuery(filter)
+	total, err := r.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("count orders: %w", err)
+	}
+
+	// _id breaks ties so pages don't overlap when many orders share a sort value
+	var sortDoc bson.D
+	switch sort {
+	case domain.OrderSortOldest:
+		sortDoc = bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}
+	case domain.OrderSortAmountDesc:
+		sortDoc = bson.D{{Key: "amount", Value: -1}, {Key: "_id", Value: -1}}
+	case domain.OrderSortAmountAsc:
+		sortDoc = bson.D{{Key: "amount", Value: 1}, {Key: "_id", Value: 1}}
+	default:
+		sortDoc = bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}
+	}
+
+	opts := options.Find().
+		SetSort(sortDoc).
+		SetSkip((page - 1) * pageSize).
+		SetLimit(pageSize)
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("find orders: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	orders := []*domain.Order{}
+	if err := cursor.All(ctx, &orders); err != nil {
+		return nil, nil, fmt.Errorf("decode orders: %w", err)
+	}
+
+	totalPages := total / pageSize
+	if total%pageSize != 0 {
+		totalPages++
+	}
+	return orders, &domain.PaginationMeta{
+		Page:       page,
+		PageSize:   pageSize,
+		TotalCount: total,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// Totals summarises every order matching filter in one aggregation.
+func (r *MongoOrderRepository) Totals(ctx context.Context, filter domain.OrderFilter) (*domain.OrderTotals, error) {
+	paid := bson.D{{Key: "$eq", Value: bson.A{"$status", domain.OrderStatusPaid}}}
+	sumIfPaid := func(field string) bson.D {
+		return bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{paid, bson.D{{Key: "$ifNull", Value: bson.A{field, 0}}}, 0}}}}}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: orderQuery(filter)}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: nil},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+			{Key: "paid_count", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$cond", Value: bson.A{paid, 1, 0}}}}}},
+			{Key: "revenue", Value: sumIfPaid("$amount")},
+			{Key: "discounts", Value: sumIfPaid("$discount_amount")},
+			{Key: "platform_fees", Value: sumIfPaid("$platform_fee")},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate order totals: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	totals := &domain.OrderTotals{}
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(totals); err != nil {
+			return nil, fmt.Errorf("decode order totals: %w", err)
+		}
+	}
+	totals.Net = totals.Revenue - totals.PlatformFees
+	return totals, cursor.Err()
+}
+
+// orderQuery builds the Mongo query for an order filter.
+func orderQuery(filter domain.OrderFilter) bson.M {
+	query := bson.M{"creator_id": filter.CreatorID}
+	var and bson.A
+
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if !filter.ProductID.IsZero() {
+		and = append(and, bson.M{"$or": bson.A{
+			bson.M{"product_id": filter.ProductID},
+			bson.M{"line_items.product_id": filter.ProductID},
+		}})
+	}
+	if !filter.From.IsZero() || !filter.To.IsZero() {
+		createdAt := bson.M{}
+		if !filter.From.IsZero() {
+			createdAt["$gte"] = filter.From
+		}
+		if !filter.To.IsZero() {
+			createdAt["$lt"] = filter.To
+		}
+		query["created_at"] = createdAt
+	}
+	if filter.CouponCode != "" {
+		query["coupon_code"] = strings.ToUpper(filter.CouponCode)
+	}
+	if !filter.AffiliateID.IsZero() {
+		query["affiliate_id"] = filter.AffiliateID
+	}
+	if filter.MinAmount != nil || filter.MaxAmount != nil {
+		amount := bson.M{}
+		if filter.MinAmount != nil {
+			amount["$gte"] = *filter.MinAmount
+		}
+		if filter.MaxAmount != nil {
+			amount["$lte"] = *filter.MaxAmount
+		}
+		query["amount"] = amount
+	}
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(search), Options: "i"}
+		and = append(and, bson.M{"$or": bson.A{
+			bson.M{"customer_email": pattern},
+			bson.M{"customer_name": pattern},
+		}})
+	}
+
+	if len(and) > 0 {
+		query["$and"] = and
+	}
+	return query
+}
+
 func (r *MongoOrderRepository) FindAllByCustomerEmail(ctx context.Context, email string) ([]*domain.Order, error) {
 	cursor, err := r.collection.Find(ctx, bson.M{"customer_email": email})
 	if err != nil {
@@ -114,6 +315,29 @@ func (r *MongoOrderRepository) FindAllByCustomerEmail(ctx context.Context, email
 	return orders, nil
 }
 
+// FindPaidByProductID returns paid orders that include the product, as the legacy
+// product_id or as a line item.
+func (r *MongoOrderRepository) FindPaidByProductID(ctx context.Context, productID primitive.ObjectID) ([]*domain.Order, error) {
+	filter := bson.M{
+		"status": domain.OrderStatusPaid,
+		"$or": bson.A{
+			bson.M{"product_id": productID},
+			bson.M{"line_items.product_id": productID},
+		},
+	}
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var orders []*domain.Order
+	if err = cursor.All(ctx, &orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
 func (r *MongoOrderRepository) Count(ctx context.Context) (int64, error) {
 	return r.collection.CountDocuments(ctx, bson.M{})
 }
@@ -161,3 +385,17 @@ func (r *MongoOrderRepository) MarkReminderSent(ctx context.Context, orderID pri
 	}
 	return nil
 }
+
+// AnonymiseCustomer replaces the customer's email and name on all their orders. Amounts, fees
+// and payment IDs are kept for accounting.
+func (r *MongoOrderRepository) AnonymiseCustomer(ctx context.Context, email, anonEmail, anonName string) error {
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"customer_email": email},
+		bson.M{"$set": bson.M{
+			"customer_email": anonEmail,
+			"customer_name":  anonName,
+			"updated_at":     time.Now(),
+		}},
+	)
+	return err
+}
@@ -103,6 +103,26 @@ func (r *MongoProductRepository) FindAllByCreatorID(ctx context.Context, creator
 	return products, nil
 }
 
+// FindDeletedByCreatorID finds a creator's soft-deleted products.
+func (r *MongoProductRepository) FindDeletedByCreatorID(ctx context.Context, creatorID primitive.ObjectID) ([]*domain.Product, error) {
+	filter := bson.M{
+		"creator_id": creatorID,
+		"deleted_at": bson.M{"$exists": true},
+	}
+
+	cursor, err := r.Collection().Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("find deleted by creator: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var products []*domain.Product
+	if err := cursor.All(ctx, &products); err != nil {
+		return nil, fmt.Errorf("decode products: %w", err)
+	}
+	return products, nil
+}
+
 // FindVisibleByCreatorID finds all visible products for a specific creator.
 func (r *MongoProductRepository) FindVisibleByCreatorID(ctx context.Context, creatorID primitive.ObjectID) ([]*domain.Product, error) {
 	filter := bson.M{
@@ -1,14 +1,18 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
 )
 
 // S3Storage implements the FileStorage interface for S3-compatible services (AWS, R2, MinIO).
@@ -92,6 +96,64 @@ func (s *S3Storage) GeneratePresignedDownloadURL(ctx context.Context, key string
 	return req.URL, nil
 }
 
+// Upload puts an object into the bucket.
+func (s *S3Storage) Upload(ctx context.Context, key string, contentType string, data []byte) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucketName),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+		Body:        bytes.NewReader(data),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	return nil
+}
+
+// Head fetches an object's metadata.
+func (s *S3Storage) Head(ctx context.Context, key string) (*domain.FileInfo, error) {
+	out, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to head object: %w", err)
+	}
+
+	info := &domain.FileInfo{Key: key, Size: aws.ToInt64(out.ContentLength), ContentType: aws.ToString(out.ContentType)}
+	if out.LastModified != nil {
+		info.LastModified = *out.LastModified
+	}
+	return info, nil
+}
+
+// Open streams an object from the bucket.
+func (s *S3Storage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object: %w", err)
+	}
+	return out.Body, nil
+}
+
+// Download reads an object from the bucket.
+func (s *S3Storage) Download(ctx context.Context, key string) ([]byte, error) {
+	body, err := s.Open(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %w", err)
+	}
+	return data, nil
+}
+
 // Delete removes a file from R2.
 func (s *S3Storage) Delete(ctx context.Context, key string) error {
 	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
@@ -103,3 +165,26 @@ func (s *S3Storage) Delete(ctx context.Context, key string) error {
 	}
 	return nil
 }
+
+// List pages through every object under prefix.
+func (s *S3Storage) List(ctx context.Context, prefix string) ([]domain.FileInfo, error) {
+	var files []domain.FileInfo
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucketName),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+		for _, obj := range page.Contents {
+			info := domain.FileInfo{Key: aws.ToString(obj.Key), Size: aws.ToInt64(obj.Size)}
+			if obj.LastModified != nil {
+				info.LastModified = *obj.LastModified
+			}
+			files = append(files, info)
+		}
+	}
+	return files, nil
+}
@@ -37,8 +37,11 @@ func (r *MongoSubscriberRepository) ensureIndexes() {
 	}
 }
 
-// Upsert creates or updates a subscriber (no duplicate on creator_id + email).
-func (r *MongoSubscriberRepository) Upsert(ctx context.Context, sub *domain.EmailSubscriber) error {
+// Upsert creates or updates a subscriber (no duplicate on creator_id + email). A new
+// subscriber's ID and subscribed_at are filled in on sub.
+func (r *MongoSubscriberRepository) Upsert(ctx context.Context, sub *domain.EmailSubscriber) (bool, error) {
+	id := primitive.NewObjectID()
+	now := time.Now()
 	filter := bson.M{
 		"creator_id": sub.CreatorID,
 		"email":      sub.Email,
@@ -50,13 +53,21 @@ func (r *MongoSubscriberRepository) Upsert(ctx context.Context, sub *domain.Emai
 			"consent_given": sub.ConsentGiven,
 		},
 		"$setOnInsert": bson.M{
-			"_id":           primitive.NewObjectID(),
-			"subscribed_at": time.Now(),
+			"_id":           id,
+			"subscribed_at": now,
 		},
 	}
 	opts := options.Update().SetUpsert(true)
-	_, err := r.collection.UpdateOne(ctx, filter, update, opts)
-	return err
+	result, err := r.collection.UpdateOne(ctx, filter, update, opts)
+	if err != nil {
+		return false, err
+	}
+	if result.UpsertedCount == 0 {
+		return false, nil
+	}
+	sub.ID = id
+	sub.SubscribedAt = now
+	return true, nil
 }
 
 // FindByEmail attempts to find a single active subscriber by their mail and creator.
@@ -124,3 +135,24 @@ func (r *MongoSubscriberRepository) Count(ctx context.Context, creatorID primiti
 		"unsubscribed_at": bson.M{"$exists": false},
 	})
 }
+
+// FindAllByEmail returns every creator list the address is on, including unsubscribed entries.
+func (r *MongoSubscriberRepository) FindAllByEmail(ctx context.Context, email string) ([]*domain.EmailSubscriber, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"email": email})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var subs []*domain.EmailSubscriber
+	if err = cursor.All(ctx, &subs); err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+// DeleteByEmail removes the address from every creator's list.
+func (r *MongoSubscriberRepository) DeleteByEmail(ctx context.Context, email string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"email": email})
+	return err
+}
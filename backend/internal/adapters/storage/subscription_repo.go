@@ -135,3 +135,16 @@ func (r *MongoSubscriptionRepository) FindAllByCustomerEmail(ctx context.Context
 
 	return subscriptions, nil
 }
+
+// AnonymiseCustomer replaces the customer's email and name on all their subscriptions
+func (r *MongoSubscriptionRepository) AnonymiseCustomer(ctx context.Context, email, anonEmail, anonName string) error {
+	_, err := r.Collection().UpdateMany(ctx,
+		bson.M{"customer_email": email},
+		bson.M{"$set": bson.M{
+			"customer_email": anonEmail,
+			"customer_name":  anonName,
+			"updated_at":     time.Now(),
+		}},
+	)
+	return err
+}
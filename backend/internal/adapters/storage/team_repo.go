@@ -0,0 +1,207 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+const (
+	teamMembersCollection  = "team_members"
+	teamActivityCollection = "team_activity"
+)
+
+// MongoTeamMemberRepository implements domain.TeamMemberRepository using MongoDB.
+type MongoTeamMemberRepository struct {
+	*BaseRepository[domain.TeamMember]
+}
+
+// NewMongoTeamMemberRepository creates a new MongoTeamMemberRepository.
+func NewMongoTeamMemberRepository(db *MongoDB) *MongoTeamMemberRepository {
+	repo := &MongoTeamMemberRepository{
+		BaseRepository: NewBaseRepository[domain.TeamMember](db, teamMembersCollection),
+	}
+	repo.ensureIndexes()
+	return repo
+}
+
+// ensureIndexes creates indexes for the team_members collection.
+func (r *MongoTeamMemberRepository) ensureIndexes() {
+	ctx := context.Background()
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "creator_id", Value: 1}, {Key: "email", Value: 1}},
+			Options: options.Index().SetName("idx_creator_email").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}},
+			Options: options.Index().SetName("idx_user_status"),
+		},
+		{
+			Keys:    bson.D{{Key: "invite_token_hash", Value: 1}},
+			Options: options.Index().SetName("idx_invite_token").SetSparse(true),
+		},
+	}
+	if _, err := r.Collection().Indexes().CreateMany(ctx, indexes); err != nil {
+		logger.Error("Failed to ensure indexes for team members", "error", err)
+	}
+}
+
+// Create inserts a new team member.
+func (r *MongoTeamMemberRepository) Create(ctx context.Context, member *domain.TeamMember) error {
+	now := time.Now()
+	member.Email = strings.ToLower(member.Email)
+	member.CreatedAt = now
+	member.UpdatedAt = now
+	result, err := r.Collection().InsertOne(ctx, member)
+	if err != nil {
+		return fmt.Errorf("insert team member: %w", err)
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		member.ID = oid
+	}
+	return nil
+}
+
+// FindByID returns a team member by ID.
+func (r *MongoTeamMemberRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.TeamMember, error) {
+	return r.findOne(ctx, bson.M{"_id": id})
+}
+
+// FindByInviteToken returns the pending invite with the given token hash.
+func (r *MongoTeamMemberRepository) FindByInviteToken(ctx context.Context, tokenHash string) (*domain.TeamMember, error) {
+	return r.findOne(ctx, bson.M{"invite_token_hash": tokenHash, "status": domain.TeamMemberInvited})
+}
+
+// FindActive returns a user's active membership on a creator's account.
+func (r *MongoTeamMemberRepository) FindActive(ctx context.Context, creatorID, userID primitive.ObjectID) (*domain.TeamMember, error) {
+	return r.findOne(ctx, bson.M{"creator_id": creatorID, "user_id": userID, "status": domain.TeamMemberActive})
+}
+
+// FindByEmail returns a creator's team member with the given email.
+func (r *MongoTeamMemberRepository) FindByEmail(ctx context.Context, creatorID primitive.ObjectID, email string) (*domain.TeamMember, error) {
+	return r.findOne(ctx, bson.M{"creator_id": creatorID, "email": strings.ToLower(email)})
+}
+
+// FindByCreator lists a creator's team, oldest first.
+func (r *MongoTeamMemberRepository) FindByCreator(ctx context.Context, creatorID primitive.ObjectID) ([]*domain.TeamMember, error) {
+	return r.find(ctx, bson.M{"creator_id": creatorID})
+}
+
+// FindActiveByUser lists the user's active memberships.
+func (r *MongoTeamMemberRepository) FindActiveByUser(ctx context.Context, userID primitive.ObjectID) ([]*domain.TeamMember, error) {
+	return r.find(ctx, bson.M{"user_id": userID, "status": domain.TeamMemberActive})
+}
+
+// Update saves changes to a team member.
+func (r *MongoTeamMemberRepository) Update(ctx context.Context, member *domain.TeamMember) error {
+	member.UpdatedAt = time.Now()
+	_, err := r.Collection().ReplaceOne(ctx, bson.M{"_id": member.ID}, member)
+	if err != nil {
+		return fmt.Errorf("update team member: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a team member.
+func (r *MongoTeamMemberRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	if _, err := r.Collection().DeleteOne(ctx, bson.M{"_id": id}); err != nil {
+		return fmt.Errorf("delete team member: %w", err)
+	}
+	return nil
+}
+
+func (r *MongoTeamMemberRepository) findOne(ctx context.Context, filter bson.M) (*domain.TeamMember, error) {
+	var member domain.TeamMember
+	err := r.Collection().FindOne(ctx, filter).Decode(&member)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find team member: %w", err)
+	}
+	return &member, nil
+}
+
+func (r *MongoTeamMemberRepository) find(ctx context.Context, filter bson.M) ([]*domain.TeamMember, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.Collection().Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find team members: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	members := []*domain.TeamMember{}
+	if err := cursor.All(ctx, &members); err != nil {
+		return nil, fmt.Errorf("decode team members: %w", err)
+	}
+	return members, nil
+}
+
+// MongoTeamActivityRepository implements domain.TeamActivityRepository using MongoDB.
+type MongoTeamActivityRepository struct {
+	*BaseRepository[domain.TeamActivity]
+}
+
+// NewMongoTeamActivityRepository creates a new MongoTeamActivityRepository.
+func NewMongoTeamActivityRepository(db *MongoDB) *MongoTeamActivityRepository {
+	repo := &MongoTeamActivityRepository{
+		BaseRepository: NewBaseRepository[domain.TeamActivity](db, teamActivityCollection),
+	}
+	repo.ensureIndexes()
+	return repo
+}
+
+// ensureIndexes creates indexes for the team_activity collection.
+func (r *MongoTeamActivityRepository) ensureIndexes() {
+	ctx := context.Background()
+	_, err := r.Collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "creator_id", Value: 1}, {Key: "created_at", Value: -1}},
+		Options: options.Index().SetName("idx_creator_created"),
+	})
+	if err != nil {
+		logger.Error("Failed to ensure indexes for team activity", "error", err)
+	}
+}
+
+// Create appends an entry to the team audit trail.
+func (r *MongoTeamActivityRepository) Create(ctx context.Context, activity *domain.TeamActivity) error {
+	activity.CreatedAt = time.Now()
+	result, err := r.Collection().InsertOne(ctx, activity)
+	if err != nil {
+		return fmt.Errorf("insert team activity: %w", err)
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		activity.ID = oid
+	}
+	return nil
+}
+
+// FindByCreator lists a creator's team activity, newest first.
+func (r *MongoTeamActivityRepository) FindByCreator(ctx context.Context, creatorID primitive.ObjectID, limit int64) ([]*domain.TeamActivity, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+
+	cursor, err := r.Collection().Find(ctx, bson.M{"creator_id": creatorID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("find team activity: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	activity := []*domain.TeamActivity{}
+	if err := cursor.All(ctx, &activity); err != nil {
+		return nil, fmt.Errorf("decode team activity: %w", err)
+	}
+	return activity, nil
+}
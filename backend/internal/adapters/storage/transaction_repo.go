@@ -2,9 +2,11 @@ package storage
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -16,9 +18,29 @@ type MongoTransactionRepository struct {
 }
 
 func NewMongoTransactionRepository(db *mongo.Database) *MongoTransactionRepository {
-	return &MongoTransactionRepository{
+	repo := &MongoTransactionRepository{
 		collection: db.Collection("transactions"),
 	}
+	repo.ensureIndexes()
+	return repo
+}
+
+// ensureIndexes creates indexes for the transactions collection.
+func (r *MongoTransactionRepository) ensureIndexes() {
+	ctx := context.Background()
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "creator_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_creator_created"),
+		},
+		{
+			Keys:    bson.D{{Key: "creator_id", Value: 1}, {Key: "source", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_creator_source_created"),
+		},
+	}
+	if _, err := r.collection.Indexes().CreateMany(ctx, indexes); err != nil {
+		logger.Error("Failed to ensure indexes for transactions", "error", err)
+	}
 }
 
 func (r *MongoTransactionRepository) Create(ctx context.Context, tx *domain.Transaction) error {
@@ -43,6 +65,69 @@ func (r *MongoTransactionRepository) FindAllByCreatorID(ctx context.Context, cre
 	return transactions, nil
 }
 
+// Search returns a page of the creator's transactions matching filter, newest first.
+func (r *MongoTransactionRepository) Search(ctx context.Context, filter domain.TransactionFilter, pagination *domain.Pagination) ([]*domain.Transaction, *domain.PaginationMeta, error) {
+	page := int64(1)
+	pageSize := int64(25)
+	if pagination != nil {
+		if pagination.Page > 0 {
+			page = pagination.Page
+		}
+		if pagination.PageSize > 0 && pagination.PageSize <= 100 {
+			pageSize = pagination.PageSize
+		}
+	}
+
+	query := bson.M{"creator_id": filter.CreatorID}
+	if filter.Type != "" {
+		query["type"] = filter.Type
+	}
+	if filter.Source != "" {
+		query["source"] = filter.Source
+	}
+	if !filter.From.IsZero() || !filter.To.IsZero() {
+		createdAt := bson.M{}
+		if !filter.From.IsZero() {
+			createdAt["$gte"] = filter.From
+		}
+		if !filter.To.IsZero() {
+			createdAt["$lt"] = filter.To
+		}
+		query["created_at"] = createdAt
+	}
+
+	total, err := r.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("count transactions: %w", err)
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip((page - 1) * pageSize).
+		SetLimit(pageSize)
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("find transactions: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	transactions := []*domain.Transaction{}
+	if err := cursor.All(ctx, &transactions); err != nil {
+		return nil, nil, fmt.Errorf("decode transactions: %w", err)
+	}
+
+	totalPages := total / pageSize
+	if total%pageSize != 0 {
+		totalPages++
+	}
+	return transactions, &domain.PaginationMeta{
+		Page:       page,
+		PageSize:   pageSize,
+		TotalCount: total,
+		TotalPages: totalPages,
+	}, nil
+}
+
 func (r *MongoTransactionRepository) GetBalance(ctx context.Context, creatorID primitive.ObjectID) (int64, error) {
 	// Aggregation pipeline to calculate balance
 	// Balance = Sum(Credit) - Sum(Debit)
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+const uploadsCollection = "uploads"
+
+// MongoUploadRepository implements domain.UploadRepository using MongoDB.
+type MongoUploadRepository struct {
+	*BaseRepository[domain.Upload]
+}
+
+// NewMongoUploadRepository creates a new MongoUploadRepository.
+func NewMongoUploadRepository(db *MongoDB) *MongoUploadRepository {
+	repo := &MongoUploadRepository{
+		BaseRepository: NewBaseRepository[domain.Upload](db, uploadsCollection),
+	}
+	repo.ensureIndexes()
+	return repo
+}
+
+// ensureIndexes creates indexes for the uploads collection.
+func (r *MongoUploadRepository) ensureIndexes() {
+	ctx := context.Background()
+	col := r.Collection()
+
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "file_key", Value: 1}},
+			Options: options.Index().SetName("idx_file_key").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "creator_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("idx_creator_created"),
+		},
+	}
+
+	_, err := col.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		logger.Error("Failed to ensure indexes for uploads", "error", err)
+	}
+}
+
+// Create records a newly issued upload.
+func (r *MongoUploadRepository) Create(ctx context.Context, upload *domain.Upload) error {
+	now := time.Now()
+	upload.CreatedAt = now
+	upload.UpdatedAt = now
+	result, err := r.Collection().InsertOne(ctx, upload)
+	if err != nil {
+		return fmt.Errorf("insert upload: %w", err)
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		upload.ID = oid
+	}
+	return nil
+}
+
+// FindByKey returns the upload record for a storage key.
+func (r *MongoUploadRepository) FindByKey(ctx context.Context, fileKey string) (*domain.Upload, error) {
+	var upload domain.Upload
+	err := r.Collection().FindOne(ctx, bson.M{"file_key": fileKey}).Decode(&upload)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find upload: %w", err)
+	}
+	return &upload, nil
+}
+
+// FindByKeys returns the upload records for a set of storage keys.
+func (r *MongoUploadRepository) FindByKeys(ctx context.Context, fileKeys []string) ([]*domain.Upload, error) {
+	cursor, err := r.Collection().Find(ctx, bson.M{"file_key": bson.M{"$in": fileKeys}})
+	if err != nil {
+		return nil, fmt.Errorf("find uploads: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	uploads := []*domain.Upload{}
+	if err := cursor.All(ctx, &uploads); err != nil {
+		return nil, fmt.Errorf("decode uploads: %w", err)
+	}
+	return uploads, nil
+}
+
+// Update saves the verification and processing outcome of an upload.
+func (r *MongoUploadRepository) Update(ctx context.Context, upload *domain.Upload) error {
+	upload.UpdatedAt = time.Now()
+	_, err := r.Collection().ReplaceOne(ctx, bson.M{"_id": upload.ID}, upload)
+	if err != nil {
+		return fmt.Errorf("update upload: %w", err)
+	}
+	return nil
+}
@@ -31,7 +31,8 @@ func NewMongoUserRepository(db *MongoDB) *MongoUserRepository {
 	return repo
 }
 
-// ensureIndexes creates unique indexes on email, username, and google_id.
+// ensureIndexes creates unique indexes on email, username, and google_id, and an index for
+// the account deletion sweep.
 func (r *MongoUserRepository) ensureIndexes() {
 	ctx := context.Background()
 	col := r.Collection()
@@ -49,6 +50,10 @@ func (r *MongoUserRepository) ensureIndexes() {
 			Keys:    bson.D{{Key: "google_id", Value: 1}},
 			Options: options.Index().SetUnique(true),
 		},
+		{
+			Keys:    bson.D{{Key: "deletion_scheduled_for", Value: 1}},
+			Options: options.Index().SetSparse(true),
+		},
 	}
 
 	_, err := col.Indexes().CreateMany(ctx, indexes)
@@ -74,6 +79,26 @@ func (r *MongoUserRepository) FindByEmail(ctx context.Context, email string) (*d
 	return &user, nil
 }
 
+// FindByEmails finds the users with any of the given email addresses.
+func (r *MongoUserRepository) FindByEmails(ctx context.Context, emails []string) ([]*domain.User, error) {
+	lower := make([]string, len(emails))
+	for i, email := range emails {
+		lower[i] = strings.ToLower(email)
+	}
+
+	cursor, err := r.Collection().Find(ctx, bson.M{"email": bson.M{"$in": lower}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var users []*domain.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 // FindByGoogleID finds a user by their Google OAuth ID.
 func (r *MongoUserRepository) FindByGoogleID(ctx context.Context, googleID string) (*domain.User, error) {
 	col := r.Collection()
@@ -142,3 +167,177 @@ func (r *MongoUserRepository) UpdateStatus(ctx context.Context, id string, statu
 
 	return nil
 }
+
+// UpdatePassword replaces a user's bcrypt password hash.
+func (r *MongoUserRepository) UpdatePassword(ctx context.Context, id string, passwordHash string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid id: %w", err)
+	}
+
+	result, err := r.Collection().UpdateByID(ctx, objectID, bson.M{"$set": bson.M{
+		"password_hash": passwordHash,
+		"updated_at":    time.Now(),
+	}})
+	if err != nil {
+		return fmt.Errorf("update password: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// UpdateTwoFactor replaces a user's 2FA enrolment; nil removes it.
+func (r *MongoUserRepository) UpdateTwoFactor(ctx context.Context, id string, twoFactor *domain.TwoFactor) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid id: %w", err)
+	}
+
+	update := bson.M{"$set": bson.M{"updated_at": time.Now()}}
+	if twoFactor == nil {
+		update["$unset"] = bson.M{"two_factor": ""}
+	} else {
+		update["$set"].(bson.M)["two_factor"] = twoFactor
+	}
+
+	result, err := r.Collection().UpdateByID(ctx, objectID, update)
+	if err != nil {
+		return fmt.Errorf("update two factor: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// ConsumeTOTPStep records step as the last accepted TOTP time step. The conditional update
+// makes a code usable only once, even under concurrent logins.
+func (r *MongoUserRepository) ConsumeTOTPStep(ctx context.Context, id string, step int64) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid id: %w", err)
+	}
+
+	filter := bson.M{
+		"_id": objectID,
+		"$or": bson.A{
+			bson.M{"two_factor.last_used_step": bson.M{"$exists": false}},
+			bson.M{"two_factor.last_used_step": bson.M{"$lt": step}},
+		},
+	}
+	result, err := r.Collection().UpdateOne(ctx, filter, bson.M{"$set": bson.M{"two_factor.last_used_step": step}})
+	if err != nil {
+		return false, fmt.Errorf("consume totp step: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// ConsumeRecoveryCode removes a recovery code hash, returning false if it was not present.
+func (r *MongoUserRepository) ConsumeRecoveryCode(ctx context.Context, id string, codeHash string) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, fmt.Errorf("invalid id: %w", err)
+	}
+
+	result, err := r.Collection().UpdateOne(ctx,
+		bson.M{"_id": objectID, "two_factor.recovery_codes": codeHash},
+		bson.M{"$pull": bson.M{"two_factor.recovery_codes": codeHash}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("consume recovery code: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// UpdateDeletionSchedule schedules the account for deletion at scheduledFor, recording when it
+// was requested; nil cancels a pending deletion.
+func (r *MongoUserRepository) UpdateDeletionSchedule(ctx context.Context, id string, scheduledFor *time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid id: %w", err)
+	}
+
+	now := time.Now()
+	update := bson.M{"$set": bson.M{"updated_at": now}}
+	if scheduledFor == nil {
+		update["$unset"] = bson.M{"deletion_requested_at": "", "deletion_scheduled_for": ""}
+	} else {
+		update["$set"].(bson.M)["deletion_requested_at"] = now
+		update["$set"].(bson.M)["deletion_scheduled_for"] = *scheduledFor
+	}
+
+	result, err := r.Collection().UpdateByID(ctx, objectID, update)
+	if err != nil {
+		return fmt.Errorf("update deletion schedule: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// FindDueForDeletion returns accounts whose deletion is scheduled at or before the given time.
+func (r *MongoUserRepository) FindDueForDeletion(ctx context.Context, before time.Time) ([]*domain.User, error) {
+	filter := bson.M{
+		"deletion_scheduled_for": bson.M{"$lte": before},
+		"status":                 bson.M{"$ne": domain.UserStatusDeleted},
+	}
+	cursor, err := r.Collection().Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("find users due for deletion: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var users []*domain.User
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, fmt.Errorf("decode users due for deletion: %w", err)
+	}
+	return users, nil
+}
+
+// Anonymise strips a user's personal data, replaces their email and marks the account deleted.
+// Role, fee rate and timestamps are kept so the user's orders and payouts still add up.
+func (r *MongoUserRepository) Anonymise(ctx context.Context, id string, email string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("invalid id: %w", err)
+	}
+
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"email":          email,
+			"display_name":   domain.DeletedUserName,
+			"avatar_url":     "",
+			"email_verified": false,
+			"status":         domain.UserStatusDeleted,
+			"deleted_at":     now,
+			"updated_at":     now,
+		},
+		"$unset": bson.M{
+			"username":               "",
+			"password_hash":          "",
+			"google_id":              "",
+			"bio":                    "",
+			"cover_photo_url":        "",
+			"social_links":           "",
+			"payout_config":          "",
+			"two_factor":             "",
+			"banned_at":              "",
+			"ban_reason":             "",
+			"deletion_requested_at":  "",
+			"deletion_scheduled_for": "",
+		},
+	}
+
+	result, err := r.Collection().UpdateByID(ctx, objectID, update)
+	if err != nil {
+		return fmt.Errorf("anonymise user: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Package webp encodes and decodes WebP images with the libwebp command-line tools.
+package webp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// toolTimeout bounds a single cwebp or dwebp run.
+const toolTimeout = time.Minute
+
+// LibWebPCodec shells out to cwebp and dwebp.
+type LibWebPCodec struct {
+	cwebp string
+	dwebp string
+}
+
+// NewLibWebPCodec resolves the cwebp and dwebp executables, which may be names on PATH or
+// absolute paths. It fails if either tool is missing.
+func NewLibWebPCodec(cwebpPath, dwebpPath string) (*LibWebPCodec, error) {
+	cwebp, err := exec.LookPath(cwebpPath)
+	if err != nil {
+		return nil, fmt.Errorf("cwebp not found: %w", err)
+	}
+	dwebp, err := exec.LookPath(dwebpPath)
+	if err != nil {
+		return nil, fmt.Errorf("dwebp not found: %w", err)
+	}
+	return &LibWebPCodec{cwebp: cwebp, dwebp: dwebp}, nil
+}
+
+// Encode converts img to a lossy WebP at the given quality (0-100) without metadata.
+func (c *LibWebPCodec) Encode(ctx context.Context, img image.Image, quality int) ([]byte, error) {
+	var src bytes.Buffer
+	if err := png.Encode(&src, img); err != nil {
+		return nil, err
+	}
+	return c.run(ctx, src.Bytes(), ".png", c.cwebp, "-quiet", "-metadata", "none", "-q", strconv.Itoa(quality), "{in}", "-o", "-")
+}
+
+// Decode converts WebP data to an image.
+func (c *LibWebPCodec) Decode(ctx context.Context, data []byte) (image.Image, error) {
+	out, err := c.run(ctx, data, ".webp", c.dwebp, "-quiet", "{in}", "-png", "-o", "-")
+	if err != nil {
+		return nil, err
+	}
+	return png.Decode(bytes.NewReader(out))
+}
+
+// run writes input to a temporary file, substitutes its path for "{in}" in args and
+// returns the tool's standard output.
+func (c *LibWebPCodec) run(ctx context.Context, input []byte, ext, tool string, args ...string) ([]byte, error) {
+	f, err := os.CreateTemp("", "webp-*"+ext)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(input); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	for i, a := range args {
+		if a == "{in}" {
+			args[i] = f.Name()
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, toolTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, tool, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s failed: %w: %s", tool, err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return stdout.Bytes(), nil
+}
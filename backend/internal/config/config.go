@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 )
@@ -14,14 +15,24 @@ type Config struct {
 	RedisURL                  string `json:"redisUrl"`
 	JWTSecret                 string `json:"jwtSecret"`
 	FrontendURL               string `json:"frontendUrl"`
+	APIBaseURL                string `json:"apiBaseUrl"`          // Public backend URL used in links sent by email
+	DownloadTokenSecret       string `json:"downloadTokenSecret"` // Signs download links; defaults to JWTSecret
 	GoogleClientID            string `json:"googleClientId"`
 	GoogleClientSecret        string `json:"googleClientSecret"`
 	GoogleRedirectURL         string `json:"googleRedirectUrl"`
+	StorageDriver             string `json:"storageDriver"`    // "s3" (default) or "local"
+	LocalStoragePath          string `json:"localStoragePath"` // Root directory for the local driver
 	R2AccountID               string `json:"r2AccountId"`
 	R2AccessKeyID             string `json:"r2AccessKeyId"`
 	R2SecretAccessKey         string `json:"r2SecretAccessKey"`
 	R2BucketName              string `json:"r2BucketName"`
 	R2Endpoint                string `json:"r2Endpoint"`
+	ClamAVAddress             string `json:"clamavAddress"` // e.g. unix:///var/run/clamav/clamd.ctl; empty disables scanning
+	CWebPPath                 string `json:"cwebpPath"`     // libwebp tools for WebP image variants; skipped when not installed
+	DWebPPath                 string `json:"dwebpPath"`
+	FFmpegPath                string `json:"ffmpegPath"` // Lesson video transcoding to HLS; skipped when not installed
+	FFprobePath               string `json:"ffprobePath"`
+	StorageGCGraceHours       int    `json:"storageGcGraceHours"` // Unreferenced uploads younger than this are kept
 	RazorpayKeyID             string `json:"razorpayKeyId"`
 	RazorpayKeySecret         string `json:"razorpayKeySecret"`
 	RazorpayWebhookSecret     string `json:"razorpayWebhookSecret"`
@@ -52,14 +63,24 @@ func Load() (*Config, error) {
 		RedisURL:                  getEnv("REDIS_URL", "redis://localhost:6379"),
 		JWTSecret:                 os.Getenv("JWT_SECRET"),
 		FrontendURL:               getEnv("FRONTEND_URL", "http://localhost:5173"),
+		APIBaseURL:                getEnv("API_BASE_URL", "http://localhost:8080"),
+		DownloadTokenSecret:       os.Getenv("DOWNLOAD_TOKEN_SECRET"),
 		GoogleClientID:            os.Getenv("GOOGLE_CLIENT_ID"),
 		GoogleClientSecret:        os.Getenv("GOOGLE_CLIENT_SECRET"),
 		GoogleRedirectURL:         getEnv("GOOGLE_REDIRECT_URL", "http://localhost:8080/api/v1/auth/google/callback"),
+		StorageDriver:             getEnv("STORAGE_DRIVER", "s3"),
+		LocalStoragePath:          getEnv("LOCAL_STORAGE_PATH", "./data/storage"),
 		R2AccountID:               os.Getenv("R2_ACCOUNT_ID"),
 		R2AccessKeyID:             os.Getenv("R2_ACCESS_KEY_ID"),
 		R2SecretAccessKey:         os.Getenv("R2_SECRET_ACCESS_KEY"),
 		R2BucketName:              os.Getenv("R2_BUCKET_NAME"),
 		R2Endpoint:                os.Getenv("R2_ENDPOINT"),
+		ClamAVAddress:             os.Getenv("CLAMAV_ADDRESS"),
+		CWebPPath:                 getEnv("CWEBP_PATH", "cwebp"),
+		DWebPPath:                 getEnv("DWEBP_PATH", "dwebp"),
+		FFmpegPath:                getEnv("FFMPEG_PATH", "ffmpeg"),
+		FFprobePath:               getEnv("FFPROBE_PATH", "ffprobe"),
+		StorageGCGraceHours:       getEnvInt("STORAGE_GC_GRACE_HOURS", 72),
 		RazorpayKeyID:             os.Getenv("RAZORPAY_KEY_ID"),
 		RazorpayKeySecret:         os.Getenv("RAZORPAY_KEY_SECRET"),
 		RazorpayWebhookSecret:     os.Getenv("RAZORPAY_WEBHOOK_SECRET"),
@@ -82,6 +103,13 @@ func Load() (*Config, error) {
 		// In development, use a default; in production this should fail
 		cfg.JWTSecret = "dev-secret-change-in-production"
 	}
+	if cfg.DownloadTokenSecret == "" {
+		cfg.DownloadTokenSecret = cfg.JWTSecret
+	}
+	if cfg.StorageGCGraceHours < 1 {
+		// Must outlive presigned upload URLs so in-flight uploads are never swept
+		cfg.StorageGCGraceHours = 1
+	}
 
 	if err := cfg.validate(); err != nil {
 		return nil, err
@@ -101,6 +129,9 @@ func (c *Config) validate() error {
 	if c.RedisURL == "" {
 		return fmt.Errorf("config: REDIS_URL is required")
 	}
+	if c.StorageDriver != "s3" && c.StorageDriver != "local" {
+		return fmt.Errorf("config: STORAGE_DRIVER must be \"s3\" or \"local\"")
+	}
 	// For MVP, allow empty SMTP credentials if we are testing locally or using mock
 	// But in production robust apps would check.
 	return nil
@@ -113,3 +144,11 @@ func getEnv(key, defaultVal string) string {
 	}
 	return defaultVal
 }
+
+// getEnvInt returns an integer environment variable, or defaultVal when unset or invalid.
+func getEnvInt(key string, defaultVal int) int {
+	if n, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return n
+	}
+	return defaultVal
+}
@@ -55,6 +55,9 @@ type AffiliateRepository interface {
 	UpdateStats(ctx context.Context, affiliateID primitive.ObjectID, addedEarned int64, isSale bool, isClick bool) error
 	UpdateStatus(ctx context.Context, affiliateID primitive.ObjectID, status string) error
 	UpdateCommission(ctx context.Context, affiliateID primitive.ObjectID, rate float64) error
+	FindAllByEmail(ctx context.Context, email string) ([]*Affiliate, error)
+	// Anonymise replaces the affiliate's email and name on every creator's programme and suspends them.
+	Anonymise(ctx context.Context, email, anonEmail, anonName string) error
 }
 
 type AffiliateSaleRepository interface {
@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// API key scopes a creator can grant. Write scopes also allow reading.
+const (
+	APIScopeProductsRead     = "products:read"
+	APIScopeProductsWrite    = "products:write"
+	APIScopeOrdersRead       = "orders:read"
+	APIScopeSubscribersRead  = "subscribers:read"
+	APIScopeSubscribersWrite = "subscribers:write"
+	APIScopeCouponsRead      = "coupons:read"
+	APIScopeCouponsWrite     = "coupons:write"
+)
+
+// apiScopeImplies lists the scopes each write scope also grants.
+var apiScopeImplies = map[string][]string{
+	APIScopeProductsRead:     nil,
+	APIScopeProductsWrite:    {APIScopeProductsRead},
+	APIScopeOrdersRead:       nil,
+	APIScopeSubscribersRead:  nil,
+	APIScopeSubscribersWrite: {APIScopeSubscribersRead},
+	APIScopeCouponsRead:      nil,
+	APIScopeCouponsWrite:     {APIScopeCouponsRead},
+}
+
+// IsValidAPIScope reports whether scope is one of the API key scopes.
+func IsValidAPIScope(scope string) bool {
+	_, ok := apiScopeImplies[scope]
+	return ok
+}
+
+// APIKey lets a creator call the API from scripts and other tools. Only a hash of the secret
+// is stored; the key itself is shown once, when it is created.
+type APIKey struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CreatorID  primitive.ObjectID `bson:"creator_id" json:"creator_id"`
+	Name       string             `bson:"name" json:"name"`
+	Prefix     string             `bson:"prefix" json:"prefix"` // First characters of the key, to tell keys apart
+	KeyHash    string             `bson:"key_hash" json:"-"`
+	Scopes     []string           `bson:"scopes" json:"scopes"`
+	RateLimit  int                `bson:"rate_limit" json:"rate_limit"` // Requests per minute
+	LastUsedAt *time.Time         `bson:"last_used_at,omitempty" json:"last_used_at,omitempty"`
+	LastUsedIP string             `bson:"last_used_ip,omitempty" json:"last_used_ip,omitempty"`
+	RevokedAt  *time.Time         `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// HasScope reports whether the key grants scope, directly or through a write scope.
+func (k *APIKey) HasScope(scope string) bool {
+	for _, granted := range k.Scopes {
+		if granted == scope {
+			return true
+		}
+		for _, implied := range apiScopeImplies[granted] {
+			if implied == scope {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// APIKeyRepository defines the interface for API key storage.
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *APIKey) error
+	// FindByHash returns the unrevoked key with this hash, or nil.
+	FindByHash(ctx context.Context, keyHash string) (*APIKey, error)
+	// FindByCreator lists a creator's keys, including revoked ones, newest first.
+	FindByCreator(ctx context.Context, creatorID primitive.ObjectID) ([]*APIKey, error)
+	// Revoke revokes the creator's key. It returns false if no unrevoked key matched.
+	Revoke(ctx context.Context, creatorID, id primitive.ObjectID) (bool, error)
+	// TouchLastUsed records when and from where the key was last used.
+	TouchLastUsed(ctx context.Context, id primitive.ObjectID, at time.Time, ip string) error
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Audit actions. Names are "<target>.<verb>" so related actions sort and filter together.
+const (
+	AuditCreatorBan           = "creator.ban"
+	AuditCreatorUnban         = "creator.unban"
+	AuditCreatorCreate        = "creator.create"
+	AuditSubscriptionGrant    = "subscription.grant"
+	AuditSubscriptionRevoke   = "subscription.revoke"
+	AuditEmailTemplateUpdate  = "email_template.update"
+	AuditPayoutSettings       = "payout.settings_update"
+	AuditPayoutWithdraw       = "payout.withdraw"
+	AuditPayoutStatus         = "payout.status_update"
+	AuditImpersonationStart   = "impersonation.start"
+	AuditImpersonationStop    = "impersonation.stop"
+	AuditImpersonatedRequest  = "impersonation.request"
+	AuditAccountDeleteRequest = "account.deletion_request"
+	AuditAccountDeleteCancel  = "account.deletion_cancel"
+	AuditAccountDelete        = "account.delete"
+	AuditAPIKeyCreate         = "api_key.create"
+	AuditAPIKeyRevoke         = "api_key.revoke"
+)
+
+// Audit target types
+const (
+	AuditTargetUser          = "user"
+	AuditTargetSubscription  = "subscription"
+	AuditTargetEmailTemplate = "email_template"
+	AuditTargetPayout        = "payout"
+	AuditTargetAPIKey        = "api_key"
+)
+
+// AuditActorSystem is the actor role recorded for changes made by webhooks and background jobs.
+const AuditActorSystem = "system"
+
+// AuditChange is the before and after value of one changed field.
+type AuditChange struct {
+	Field string      `bson:"field" json:"field"` // dotted JSON path, e.g. "payoutConfig.ifsc"
+	From  interface{} `bson:"from,omitempty" json:"from,omitempty"`
+	To    interface{} `bson:"to,omitempty" json:"to,omitempty"`
+}
+
+// AuditEntry records an admin, payout-affecting, API key or account deletion action. Entries
+// are never updated or deleted.
+type AuditEntry struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ActorID    primitive.ObjectID `bson:"actor_id,omitempty" json:"actor_id,omitempty"` // zero for system actions
+	ActorEmail string             `bson:"actor_email,omitempty" json:"actor_email,omitempty"`
+	ActorRole  string             `bson:"actor_role" json:"actor_role"`
+	Action     string             `bson:"action" json:"action"`
+	TargetType string             `bson:"target_type" json:"target_type"`
+	TargetID   string             `bson:"target_id" json:"target_id"`
+	Reason     string             `bson:"reason,omitempty" json:"reason,omitempty"`
+	Changes    []AuditChange      `bson:"changes,omitempty" json:"changes,omitempty"`
+	Request    string             `bson:"request,omitempty" json:"request,omitempty"` // "METHOD /path" for request-level entries
+	Status     int                `bson:"status,omitempty" json:"status,omitempty"`   // HTTP status of that request
+	IP         string             `bson:"ip,omitempty" json:"ip,omitempty"`
+	UserAgent  string             `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+	RequestID  string             `bson:"request_id,omitempty" json:"request_id,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// AuditFilter narrows an audit log query. Zero fields match everything.
+type AuditFilter struct {
+	ActorID    primitive.ObjectID
+	Action     string // exact action, or a prefix ending in "." such as "payout."
+	TargetType string
+	TargetID   string
+	From       time.Time
+	To         time.Time
+}
+
+// AuditLogRepository defines the interface for the append-only audit log.
+type AuditLogRepository interface {
+	Create(ctx context.Context, entry *AuditEntry) error
+	// Find returns a page of matching entries, newest first.
+	Find(ctx context.Context, filter AuditFilter, pagination *Pagination) ([]*AuditEntry, *PaginationMeta, error)
+	// Iterate calls fn for every matching entry, newest first, stopping at the first error.
+	Iterate(ctx context.Context, filter AuditFilter, fn func(*AuditEntry) error) error
+}
@@ -14,6 +14,7 @@ const (
 	BookingStatusConfirmed BookingStatus = "confirmed"
 	BookingStatusCancelled BookingStatus = "cancelled"
 	BookingStatusCompleted BookingStatus = "completed"
+	BookingStatusNoShow    BookingStatus = "no_show"
 )
 
 // Booking represents a scheduled coaching arrangement
@@ -28,10 +29,19 @@ type Booking struct {
 	SlotEnd     time.Time          `bson:"slot_end" json:"slot_end"`
 	MeetingLink string             `bson:"meeting_link,omitempty" json:"meeting_link,omitempty"`
 	Status      BookingStatus      `bson:"status" json:"status"`
+	CompletedAt *time.Time         `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
 	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
 }
 
+// SlotAvailability describes a bookable slot and how many seats are still open in it.
+// One-to-one products have a capacity of 1.
+type SlotAvailability struct {
+	Start          time.Time `json:"start"`
+	Capacity       int       `json:"capacity"`
+	SeatsRemaining int       `json:"seats_remaining"`
+}
+
 // BookingRepository defines the interface for booking storage
 type BookingRepository interface {
 	Create(ctx context.Context, booking *Booking) error
@@ -40,5 +50,20 @@ type BookingRepository interface {
 	FindByProductID(ctx context.Context, productID primitive.ObjectID, from, to time.Time) ([]*Booking, error)
 	FindByCreatorID(ctx context.Context, creatorID primitive.ObjectID) ([]*Booking, error)
 	FindByBuyerEmail(ctx context.Context, email string) ([]*Booking, error)
+	// FindBySlot returns the non-cancelled bookings that share a slot start, i.e. the attendees of a session.
+	FindBySlot(ctx context.Context, productID primitive.ObjectID, slotStart time.Time) ([]*Booking, error)
 	UpdateStatus(ctx context.Context, id primitive.ObjectID, status BookingStatus) error
+	// TransitionStatus moves a booking from one status to another and reports whether it was
+	// still in the from status, so concurrent transitions only take effect once.
+	TransitionStatus(ctx context.Context, id primitive.ObjectID, from, to BookingStatus) (bool, error)
+	// ReserveSeat atomically takes a seat in a slot if fewer than capacity are taken. The slot's
+	// counter starts at taken the first time it is used, so bookings made before counters existed
+	// are still counted.
+	ReserveSeat(ctx context.Context, productID primitive.ObjectID, slotStart time.Time, capacity, taken int) (bool, error)
+	// ReleaseSeat gives back a seat taken with ReserveSeat.
+	ReleaseSeat(ctx context.Context, productID primitive.ObjectID, slotStart time.Time) error
+	// FindConfirmedEndedBefore returns confirmed bookings whose slot ended before the given time.
+	FindConfirmedEndedBefore(ctx context.Context, before time.Time) ([]*Booking, error)
+	// AnonymiseBuyer replaces the buyer's email and name on all their bookings.
+	AnonymiseBuyer(ctx context.Context, email, anonEmail, anonName string) error
 }
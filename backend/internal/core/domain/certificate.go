@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CertificateTemplate is the creator's certificate design for a course
+type CertificateTemplate struct {
+	Enabled       bool   `bson:"enabled" json:"enabled"`
+	Heading       string `bson:"heading,omitempty" json:"heading,omitempty"`               // Defaults to "Certificate of Completion"
+	BodyText      string `bson:"body_text,omitempty" json:"body_text,omitempty"`           // Line shown above the course title
+	SignatureName string `bson:"signature_name,omitempty" json:"signature_name,omitempty"` // Defaults to the creator's name
+	AccentColor   string `bson:"accent_color,omitempty" json:"accent_color,omitempty"`     // Hex, e.g. "#4f46e5"
+}
+
+// Certificate is an issued course completion certificate
+type Certificate struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	VerificationID string             `bson:"verification_id" json:"verification_id"`
+	ProductID      primitive.ObjectID `bson:"product_id" json:"product_id"`
+	CreatorID      primitive.ObjectID `bson:"creator_id" json:"creator_id"`
+	UserID         primitive.ObjectID `bson:"user_id" json:"user_id"`
+	BuyerName      string             `bson:"buyer_name" json:"buyer_name"`
+	CreatorName    string             `bson:"creator_name" json:"creator_name"`
+	CourseTitle    string             `bson:"course_title" json:"course_title"`
+	FileKey        string             `bson:"file_key" json:"-"`
+	IssuedAt       time.Time          `bson:"issued_at" json:"issued_at"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// CertificateVerification is the public view of a certificate shown on the verification page
+type CertificateVerification struct {
+	VerificationID string    `json:"verification_id"`
+	BuyerName      string    `json:"buyer_name"`
+	CreatorName    string    `json:"creator_name"`
+	CourseTitle    string    `json:"course_title"`
+	IssuedAt       time.Time `json:"issued_at"`
+}
+
+// CertificateRepository defines the interface for certificate storage
+type CertificateRepository interface {
+	Create(ctx context.Context, cert *Certificate) error
+	// FindByVerificationID returns nil, nil when no certificate matches.
+	FindByVerificationID(ctx context.Context, verificationID string) (*Certificate, error)
+	// FindByUserAndProduct returns nil, nil when the buyer has no certificate for the course.
+	FindByUserAndProduct(ctx context.Context, userID, productID primitive.ObjectID) (*Certificate, error)
+}
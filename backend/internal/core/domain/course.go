@@ -17,6 +17,23 @@ const (
 	LessonTypeLink       LessonType = "link"
 )
 
+// DripType defines how drip-released content unlocks
+type DripType string
+
+const (
+	DripDaysAfterPurchase DripType = "days_after_purchase"
+	DripFixedDate         DripType = "fixed_date"
+	DripAfterLesson       DripType = "after_lesson"
+)
+
+// DripRule schedules when a module or lesson becomes available to a buyer
+type DripRule struct {
+	Type                 DripType   `bson:"type" json:"type"`
+	Days                 int        `bson:"days,omitempty" json:"days,omitempty"`                                     // DripDaysAfterPurchase
+	Date                 *time.Time `bson:"date,omitempty" json:"date,omitempty"`                                     // DripFixedDate
+	PrerequisiteLessonID string     `bson:"prerequisite_lesson_id,omitempty" json:"prerequisite_lesson_id,omitempty"` // DripAfterLesson
+}
+
 // Lesson represents an individual item in a course module
 type Lesson struct {
 	ID              string     `bson:"lesson_id" json:"id"`
@@ -25,14 +42,21 @@ type Lesson struct {
 	Content         string     `bson:"content" json:"content"` // URL, text, or file key depending on type
 	SortOrder       int        `bson:"sort_order" json:"sort_order"`
 	DurationMinutes int        `bson:"duration_minutes,omitempty" json:"duration_minutes,omitempty"` // For videos
+	Drip            *DripRule  `bson:"drip,omitempty" json:"drip,omitempty"`
+
+	// Computed per buyer; never persisted
+	Locked    bool          `bson:"-" json:"locked,omitempty"`
+	UnlocksAt *time.Time    `bson:"-" json:"unlocks_at,omitempty"` // Nil while locked behind a prerequisite
+	Stream    *LessonStream `bson:"-" json:"stream,omitempty"`     // Uploaded videos only
 }
 
 // Module represents a section within a course
 type Module struct {
-	ID        string   `bson:"module_id" json:"id"`
-	Title     string   `bson:"title" json:"title" validate:"required"`
-	SortOrder int      `bson:"sort_order" json:"sort_order"`
-	Lessons   []Lesson `bson:"lessons" json:"lessons"`
+	ID        string    `bson:"module_id" json:"id"`
+	Title     string    `bson:"title" json:"title" validate:"required"`
+	SortOrder int       `bson:"sort_order" json:"sort_order"`
+	Lessons   []Lesson  `bson:"lessons" json:"lessons"`
+	Drip      *DripRule `bson:"drip,omitempty" json:"drip,omitempty"` // Applies to every lesson in the module
 }
 
 // Course represents the structure of an educational product
@@ -41,8 +65,10 @@ type Course struct {
 	ProductID primitive.ObjectID `bson:"product_id" json:"product_id"`
 	CreatorID primitive.ObjectID `bson:"creator_id" json:"creator_id"`
 	Modules   []Module           `bson:"modules" json:"modules"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+	// Certificate is awarded to buyers who complete every lesson
+	Certificate *CertificateTemplate `bson:"certificate,omitempty" json:"certificate,omitempty"`
+	CreatedAt   time.Time            `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time            `bson:"updated_at" json:"updated_at"`
 }
 
 // CourseRepository defines the interface for course storage
@@ -51,5 +77,8 @@ type CourseRepository interface {
 	FindByProductID(ctx context.Context, productID primitive.ObjectID) (*Course, error)
 	FindByID(ctx context.Context, id string) (*Course, error)
 	Update(ctx context.Context, course *Course) error
+	UpdateCertificate(ctx context.Context, courseID primitive.ObjectID, tmpl *CertificateTemplate) error
 	DeleteByProductID(ctx context.Context, productID primitive.ObjectID) error
+	// FindWithDripRules returns courses where at least one module or lesson has a drip rule.
+	FindWithDripRules(ctx context.Context) ([]*Course, error)
 }
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LessonProgress records how far a buyer got through a single lesson
+type LessonProgress struct {
+	LessonID        string     `bson:"lesson_id" json:"lesson_id"`
+	Completed       bool       `bson:"completed" json:"completed"`
+	CompletedAt     *time.Time `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	PositionSeconds int        `bson:"position_seconds,omitempty" json:"position_seconds,omitempty"` // Resume point for video lessons
+	UpdatedAt       time.Time  `bson:"updated_at" json:"updated_at"`
+}
+
+// CourseProgress tracks a buyer's progress through one course
+type CourseProgress struct {
+	ID                primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID            primitive.ObjectID `bson:"user_id" json:"user_id"`
+	ProductID         primitive.ObjectID `bson:"product_id" json:"product_id"`
+	CreatorID         primitive.ObjectID `bson:"creator_id" json:"creator_id"`
+	Lessons           []LessonProgress   `bson:"lessons" json:"lessons"`
+	LastLessonID      string             `bson:"last_lesson_id,omitempty" json:"last_lesson_id,omitempty"`
+	CompletionPercent int                `bson:"completion_percent" json:"completion_percent"`
+	CompletedAt       *time.Time         `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	CreatedAt         time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt         time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Lesson returns the progress entry for a lesson, or nil if the buyer hasn't opened it.
+func (p *CourseProgress) Lesson(lessonID string) *LessonProgress {
+	for i := range p.Lessons {
+		if p.Lessons[i].LessonID == lessonID {
+			return &p.Lessons[i]
+		}
+	}
+	return nil
+}
+
+// LessonProgressUpdate is a change to one lesson's progress. Nil fields leave the stored
+// value untouched.
+type LessonProgressUpdate struct {
+	LessonID        string
+	Completed       *bool
+	PositionSeconds *int
+	At              time.Time
+}
+
+// LessonDropOff summarises learner engagement with one lesson for creator analytics
+type LessonDropOff struct {
+	ModuleID       string  `json:"module_id"`
+	LessonID       string  `json:"lesson_id"`
+	Title          string  `json:"title"`
+	Started        int     `json:"started"`
+	Completed      int     `json:"completed"`
+	DropOffPercent float64 `json:"drop_off_percent"` // Learners who finished the previous lesson but not this one
+}
+
+// CourseProgressAnalytics is the creator-facing progress report for a course
+type CourseProgressAnalytics struct {
+	ProductID      primitive.ObjectID `json:"product_id"`
+	Learners       int                `json:"learners"`
+	CompletedCount int                `json:"completed_count"`
+	AvgCompletion  float64            `json:"avg_completion"`
+	Lessons        []LessonDropOff    `json:"lessons"`
+}
+
+// CourseProgressRepository defines the interface for course progress storage
+type CourseProgressRepository interface {
+	// FindByUserAndProduct returns nil, nil when the buyer has no progress yet.
+	FindByUserAndProduct(ctx context.Context, userID, productID primitive.ObjectID) (*CourseProgress, error)
+	FindByUser(ctx context.Context, userID primitive.ObjectID) ([]*CourseProgress, error)
+	FindByProduct(ctx context.Context, productID primitive.ObjectID) ([]*CourseProgress, error)
+	// UpdateLesson applies update to a single lesson entry, creating the record and the entry
+	// as needed, and returns the record afterwards. Other lessons are left as stored, so
+	// concurrent updates to different lessons don't overwrite each other.
+	UpdateLesson(ctx context.Context, userID, productID, creatorID primitive.ObjectID, update LessonProgressUpdate) (*CourseProgress, error)
+	// SetCompletion stores the completion percentage. It reports whether this call marked the
+	// course completed, which happens once however many requests reach 100 together.
+	SetCompletion(ctx context.Context, userID, productID primitive.ObjectID, percent int, at time.Time) (bool, error)
+	// MarkUnlockNotified records that email was told about a lesson unlocking. It returns false
+	// if that was already recorded.
+	MarkUnlockNotified(ctx context.Context, productID primitive.ObjectID, email, lessonID string) (bool, error)
+	DeleteByUser(ctx context.Context, userID primitive.ObjectID) error
+}
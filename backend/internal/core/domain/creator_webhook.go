@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Store events a creator can subscribe a webhook endpoint to.
+const (
+	WebhookEventOrderPaid             = "order.paid"
+	WebhookEventSubscriptionCancelled = "subscription.cancelled"
+	WebhookEventBookingCreated        = "booking.created"
+	WebhookEventSubscriberAdded       = "subscriber.added"
+	WebhookEventAffiliateSale         = "affiliate.sale"
+)
+
+// WebhookEventTypes lists every event type, in the order they are shown to creators.
+var WebhookEventTypes = []string{
+	WebhookEventOrderPaid,
+	WebhookEventSubscriptionCancelled,
+	WebhookEventBookingCreated,
+	WebhookEventSubscriberAdded,
+	WebhookEventAffiliateSale,
+}
+
+// IsValidWebhookEvent reports whether event is one of the webhook event types.
+func IsValidWebhookEvent(event string) bool {
+	for _, e := range WebhookEventTypes {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEndpoint is a creator's HTTPS URL that receives signed store events. Endpoints that
+// keep failing are disabled until the creator re-enables them.
+type WebhookEndpoint struct {
+	ID                  primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CreatorID           primitive.ObjectID `bson:"creator_id" json:"creator_id"`
+	URL                 string             `bson:"url" json:"url"`
+	Description         string             `bson:"description,omitempty" json:"description,omitempty"`
+	Events              []string           `bson:"events" json:"events"`
+	Secret              string             `bson:"secret" json:"-"` // HMAC signing secret
+	Enabled             bool               `bson:"enabled" json:"enabled"`
+	DisabledReason      string             `bson:"disabled_reason,omitempty" json:"disabled_reason,omitempty"`
+	ConsecutiveFailures int                `bson:"consecutive_failures" json:"consecutive_failures"` // Failed attempts since the last success
+	FailingSince        *time.Time         `bson:"failing_since,omitempty" json:"failing_since,omitempty"`
+	CreatedAt           time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt           time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Subscribes reports whether the endpoint receives event.
+func (e *WebhookEndpoint) Subscribes(event string) bool {
+	for _, ev := range e.Events {
+		if ev == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus defines the status of an outbound webhook delivery.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"   // Waiting for its next attempt
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded" // The endpoint answered 2xx
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"    // Out of attempts
+)
+
+// WebhookAttempt is one try at delivering an event.
+type WebhookAttempt struct {
+	At             time.Time `bson:"at" json:"at"`
+	ResponseStatus int       `bson:"response_status,omitempty" json:"response_status,omitempty"`
+	Error          string    `bson:"error,omitempty" json:"error,omitempty"`
+	DurationMs     int64     `bson:"duration_ms" json:"duration_ms"`
+	Manual         bool      `bson:"manual,omitempty" json:"manual,omitempty"` // Redelivered by the creator
+}
+
+// WebhookDelivery records one event sent to one endpoint. The payload is stored as sent so
+// redeliveries are byte-for-byte identical.
+type WebhookDelivery struct {
+	ID             primitive.ObjectID    `bson:"_id,omitempty" json:"id"`
+	EndpointID     primitive.ObjectID    `bson:"endpoint_id" json:"endpoint_id"`
+	CreatorID      primitive.ObjectID    `bson:"creator_id" json:"creator_id"`
+	EventID        string                `bson:"event_id" json:"event_id"` // Same for every endpoint receiving the event
+	EventType      string                `bson:"event_type" json:"event_type"`
+	Payload        string                `bson:"payload" json:"payload"`
+	Status         WebhookDeliveryStatus `bson:"status" json:"status"`
+	Attempts       []WebhookAttempt      `bson:"attempts" json:"attempts"`
+	ResponseStatus int                   `bson:"response_status,omitempty" json:"response_status,omitempty"` // Of the latest attempt
+	NextAttemptAt  *time.Time            `bson:"next_attempt_at,omitempty" json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time            `bson:"delivered_at,omitempty" json:"delivered_at,omitempty"`
+	CreatedAt      time.Time             `bson:"created_at" json:"created_at"`
+}
+
+// WebhookEndpointRepository defines the interface for creator webhook endpoint storage.
+type WebhookEndpointRepository interface {
+	Create(ctx context.Context, endpoint *WebhookEndpoint) error
+	// FindByID returns nil, nil when no endpoint matches.
+	FindByID(ctx context.Context, id primitive.ObjectID) (*WebhookEndpoint, error)
+	FindByCreator(ctx context.Context, creatorID primitive.ObjectID) ([]*WebhookEndpoint, error)
+	// FindEnabledForEvent returns the creator's enabled endpoints subscribed to event.
+	FindEnabledForEvent(ctx context.Context, creatorID primitive.ObjectID, event string) ([]*WebhookEndpoint, error)
+	Update(ctx context.Context, endpoint *WebhookEndpoint) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	// RecordSuccess clears the endpoint's failure streak.
+	RecordSuccess(ctx context.Context, id primitive.ObjectID) error
+	// RecordFailure extends the endpoint's failure streak and returns the updated endpoint.
+	RecordFailure(ctx context.Context, id primitive.ObjectID, at time.Time) (*WebhookEndpoint, error)
+	// Disable turns the endpoint off, keeping reason for the creator to see.
+	Disable(ctx context.Context, id primitive.ObjectID, reason string) error
+}
+
+// WebhookDeliveryRepository defines the interface for the outbound webhook delivery log.
+type WebhookDeliveryRepository interface {
+	Create(ctx context.Context, delivery *WebhookDelivery) error
+	// FindByID returns nil, nil when no delivery matches.
+	FindByID(ctx context.Context, id primitive.ObjectID) (*WebhookDelivery, error)
+	// FindByEndpoint lists an endpoint's deliveries, newest first.
+	FindByEndpoint(ctx context.Context, endpointID primitive.ObjectID, limit, offset int64) ([]*WebhookDelivery, int64, error)
+	// RecordAttempt appends an attempt and moves the delivery to status. nextAttemptAt is nil
+	// when no further automatic attempt is planned.
+	RecordAttempt(ctx context.Context, id primitive.ObjectID, attempt WebhookAttempt, status WebhookDeliveryStatus, nextAttemptAt *time.Time) error
+	DeleteByEndpoint(ctx context.Context, endpointID primitive.ObjectID) error
+}
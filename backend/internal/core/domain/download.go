@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Download sources recorded in the audit log
+const (
+	DownloadSourceEmailLink = "email_link"
+	DownloadSourceOrderPage = "order_page"
+)
+
+// StampedCopy is the buyer's personalised copy of one version of a product file
+type StampedCopy struct {
+	FileKey string `bson:"file_key,omitempty" json:"-"`
+	Error   string `bson:"error,omitempty" json:"error,omitempty"` // Set when the file could not be stamped; the original is served
+}
+
+// DownloadEntitlement is a buyer's right to download the files of one product from one order.
+// The download limit is shared across all of the product's files.
+type DownloadEntitlement struct {
+	ID             primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	OrderID        primitive.ObjectID     `bson:"order_id" json:"order_id"`
+	ProductID      primitive.ObjectID     `bson:"product_id" json:"product_id"`
+	CreatorID      primitive.ObjectID     `bson:"creator_id" json:"creator_id"`
+	BuyerEmail     string                 `bson:"buyer_email" json:"buyer_email"`
+	BuyerName      string                 `bson:"buyer_name,omitempty" json:"buyer_name,omitempty"`
+	DownloadCount  int                    `bson:"download_count" json:"download_count"`
+	MaxDownloads   int                    `bson:"max_downloads" json:"max_downloads"` // 0 = unlimited
+	ExpiresAt      *time.Time             `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	LockedAt       *time.Time             `bson:"locked_at,omitempty" json:"locked_at,omitempty"`
+	LockReason     string                 `bson:"lock_reason,omitempty" json:"lock_reason,omitempty"`
+	LastDownloadAt *time.Time             `bson:"last_download_at,omitempty" json:"last_download_at,omitempty"`
+	Stamps         map[string]StampedCopy `bson:"stamps,omitempty" json:"stamps,omitempty"` // Keyed by StampKey
+	CreatedAt      time.Time              `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time              `bson:"updated_at" json:"updated_at"`
+}
+
+// DownloadLog is one download attempt in the creator-visible audit log
+type DownloadLog struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	EntitlementID primitive.ObjectID `bson:"entitlement_id" json:"entitlement_id"`
+	OrderID       primitive.ObjectID `bson:"order_id" json:"order_id"`
+	ProductID     primitive.ObjectID `bson:"product_id" json:"product_id"`
+	FileID        primitive.ObjectID `bson:"file_id,omitempty" json:"file_id,omitempty"`
+	FileVersion   int                `bson:"file_version,omitempty" json:"file_version,omitempty"`
+	CreatorID     primitive.ObjectID `bson:"creator_id" json:"creator_id"`
+	BuyerEmail    string             `bson:"buyer_email" json:"buyer_email"`
+	IP            string             `bson:"ip" json:"ip"`
+	UserAgent     string             `bson:"user_agent" json:"user_agent"`
+	Source        string             `bson:"source" json:"source"`
+	Allowed       bool               `bson:"allowed" json:"allowed"`
+	DenyReason    string             `bson:"deny_reason,omitempty" json:"deny_reason,omitempty"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// DownloadLogFilter narrows the audit log listing
+type DownloadLogFilter struct {
+	ProductID *primitive.ObjectID
+	OrderID   *primitive.ObjectID
+	Limit     int64
+}
+
+// DownloadEntitlementRepository defines the interface for entitlement storage
+type DownloadEntitlementRepository interface {
+	// FindOrCreate returns the entitlement for the order/product pair, inserting ent if none exists.
+	FindOrCreate(ctx context.Context, ent *DownloadEntitlement) (*DownloadEntitlement, error)
+	// FindByID returns nil, nil when no entitlement matches.
+	FindByID(ctx context.Context, id primitive.ObjectID) (*DownloadEntitlement, error)
+	// Consume atomically counts one download, returning false if the limit is already reached.
+	Consume(ctx context.Context, id primitive.ObjectID) (bool, error)
+	Lock(ctx context.Context, id primitive.ObjectID, reason string) error
+	// Unlock clears a lock and resets the download count.
+	Unlock(ctx context.Context, id primitive.ObjectID) error
+	// SetStampResult records the personalised copy for a stamp key, or why stamping failed.
+	SetStampResult(ctx context.Context, id primitive.ObjectID, stampKey string, stamped StampedCopy) error
+}
+
+// StampKey identifies one version of one product file within an entitlement's stamps.
+func StampKey(fileID primitive.ObjectID, version int) string {
+	return fmt.Sprintf("%s_v%d", fileID.Hex(), version)
+}
+
+// DownloadLogRepository defines the interface for the download audit log
+type DownloadLogRepository interface {
+	Create(ctx context.Context, log *DownloadLog) error
+	FindByCreator(ctx context.Context, creatorID primitive.ObjectID, filter DownloadLogFilter) ([]*DownloadLog, error)
+	// DistinctIPsSince returns the IPs that successfully downloaded an entitlement since the given time.
+	DistinctIPsSince(ctx context.Context, entitlementID primitive.ObjectID, since time.Time) ([]string, error)
+}
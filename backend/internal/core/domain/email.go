@@ -7,9 +7,17 @@ import (
 // EmailService defines the interface for sending emails.
 // EmailService defines the interface for sending emails.
 type EmailService interface {
-	// SendOrderConfirmation sends an email to the customer with purchase details and download link.
-	SendOrderConfirmation(ctx context.Context, order *Order, product *Product, downloadURL string) error
+	// SendOrderConfirmation sends an email to the customer with purchase details and download link,
+	// plus any attachments such as the order's invoice.
+	SendOrderConfirmation(ctx context.Context, order *Order, product *Product, downloadURL string, attachments ...EmailAttachment) error
 
 	// Send exposes a generic template-less email sender
 	Send(ctx context.Context, recipient string, subject string, body string) error
 }
+
+// EmailAttachment is a file attached to an email.
+type EmailAttachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
@@ -11,10 +11,21 @@ import (
 type EmailTemplateType string
 
 const (
-	TemplateTypePostPurchase EmailTemplateType = "post_purchase"
+	TemplateTypePostPurchase    EmailTemplateType = "post_purchase"
+	TemplateTypeBookingFollowUp EmailTemplateType = "booking_follow_up" // Sent once a booked session is completed
+	TemplateTypeBuyerMagicLink  EmailTemplateType = "buyer_magic_link"  // Platform-wide; edited by admins
 	// Future expansions: abandoned_cart, welcome_sequence, etc.
 )
 
+// PlatformTemplateCreatorID is the CreatorID platform-wide templates are stored under.
+var PlatformTemplateCreatorID = primitive.NilObjectID
+
+// IsPlatformTemplate reports whether a template type is sent by the platform rather than a
+// creator.
+func IsPlatformTemplate(t EmailTemplateType) bool {
+	return t == TemplateTypeBuyerMagicLink
+}
+
 // EmailTemplate represents a customizable email sequence payload per creator
 type EmailTemplate struct {
 	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
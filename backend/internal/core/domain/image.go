@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"context"
+	"image"
+)
+
+// Image variant names, from smallest to largest
+const (
+	ImageVariantThumb = "thumb"
+	ImageVariantCard  = "card"
+	ImageVariantFull  = "full"
+)
+
+// Image variant formats
+const (
+	ImageFormatJPEG = "jpeg"
+	ImageFormatWebP = "webp"
+)
+
+// ImageVariant is a resized, metadata-free copy of an uploaded image
+type ImageVariant struct {
+	Name    string `bson:"name" json:"name"`
+	Format  string `bson:"format" json:"format"`
+	FileKey string `bson:"file_key" json:"file_key"`
+	Width   int    `bson:"width" json:"width"`
+	Height  int    `bson:"height" json:"height"`
+}
+
+// ResponsiveImage is the set of URLs a storefront uses to render an uploaded image.
+// SrcSet and WebPSrcSet use width descriptors and are suitable for <img srcset> and
+// <source type="image/webp" srcset>.
+type ResponsiveImage struct {
+	Src           string `json:"src"`
+	Width         int    `json:"width"`
+	Height        int    `json:"height"`
+	SrcSet        string `json:"srcset"`
+	WebPSrcSet    string `json:"webp_srcset,omitempty"`
+	Thumbnail     string `json:"thumbnail"`
+	ThumbnailWebP string `json:"thumbnail_webp,omitempty"`
+}
+
+// WebPCodec decodes and encodes WebP, which the standard library cannot write
+type WebPCodec interface {
+	Decode(ctx context.Context, data []byte) (image.Image, error)
+	Encode(ctx context.Context, img image.Image, quality int) ([]byte, error)
+}
@@ -0,0 +1,224 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GSTStates maps GST state codes to state and union territory names. A GSTIN starts with
+// its holder's state code.
+var GSTStates = map[string]string{
+	"01": "Jammu and Kashmir",
+	"02": "Himachal Pradesh",
+	"03": "Punjab",
+	"04": "Chandigarh",
+	"05": "Uttarakhand",
+	"06": "Haryana",
+	"07": "Delhi",
+	"08": "Rajasthan",
+	"09": "Uttar Pradesh",
+	"10": "Bihar",
+	"11": "Sikkim",
+	"12": "Arunachal Pradesh",
+	"13": "Nagaland",
+	"14": "Manipur",
+	"15": "Mizoram",
+	"16": "Tripura",
+	"17": "Meghalaya",
+	"18": "Assam",
+	"19": "West Bengal",
+	"20": "Jharkhand",
+	"21": "Odisha",
+	"22": "Chhattisgarh",
+	"23": "Madhya Pradesh",
+	"24": "Gujarat",
+	"26": "Dadra and Nagar Haveli and Daman and Diu",
+	"27": "Maharashtra",
+	"29": "Karnataka",
+	"30": "Goa",
+	"31": "Lakshadweep",
+	"32": "Kerala",
+	"33": "Tamil Nadu",
+	"34": "Puducherry",
+	"35": "Andaman and Nicobar Islands",
+	"36": "Telangana",
+	"37": "Andhra Pradesh",
+	"38": "Ladakh",
+}
+
+var gstinPattern = regexp.MustCompile(`^[0-9]{2}[A-Z]{5}[0-9]{4}[A-Z][1-9A-Z]Z[0-9A-Z]$`)
+
+// IsValidGSTIN reports whether gstin is a well-formed GSTIN for a known state.
+func IsValidGSTIN(gstin string) bool {
+	if !gstinPattern.MatchString(gstin) {
+		return false
+	}
+	_, ok := GSTStates[gstin[:2]]
+	return ok
+}
+
+// InvoiceZone is the time zone invoice dates, financial years and monthly tax summaries use.
+var InvoiceZone = time.FixedZone("IST", 5*60*60+30*60)
+
+// GST rates (in percent) a creator can charge.
+var GSTRates = []float64{0, 5, 12, 18, 28}
+
+// BillingDetails are the optional tax details a buyer gives at checkout. StateCode decides
+// whether GST is charged as IGST or CGST/SGST; a GSTIN takes precedence over it.
+type BillingDetails struct {
+	StateCode    string `bson:"state_code,omitempty" json:"state_code,omitempty"`
+	GSTIN        string `bson:"gstin,omitempty" json:"gstin,omitempty"`
+	BusinessName string `bson:"business_name,omitempty" json:"business_name,omitempty"`
+}
+
+// InvoiceSettings holds the business details and tax setup printed on a creator's invoices.
+// Creators without a GSTIN issue bills of supply that carry no GST.
+type InvoiceSettings struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CreatorID        primitive.ObjectID `bson:"creator_id" json:"creator_id"`
+	LegalName        string             `bson:"legal_name" json:"legal_name"`
+	GSTIN            string             `bson:"gstin,omitempty" json:"gstin,omitempty"`
+	AddressLine1     string             `bson:"address_line1" json:"address_line1"`
+	AddressLine2     string             `bson:"address_line2,omitempty" json:"address_line2,omitempty"`
+	City             string             `bson:"city" json:"city"`
+	PostalCode       string             `bson:"postal_code" json:"postal_code"`
+	StateCode        string             `bson:"state_code" json:"state_code"`
+	TaxRate          float64            `bson:"tax_rate" json:"tax_rate"`                     // Percent; only charged with a GSTIN
+	PricesIncludeTax bool               `bson:"prices_include_tax" json:"prices_include_tax"` // Otherwise GST is added at checkout
+	InvoicePrefix    string             `bson:"invoice_prefix" json:"invoice_prefix"`
+	SACCode          string             `bson:"sac_code" json:"sac_code"` // Services Accounting Code printed on every line
+	CreatedAt        time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt        time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Registered reports whether the creator charges GST.
+func (s *InvoiceSettings) Registered() bool {
+	return s != nil && s.GSTIN != ""
+}
+
+// InvoiceType distinguishes GST tax invoices from bills of supply.
+type InvoiceType string
+
+const (
+	InvoiceTypeTax          InvoiceType = "tax_invoice"    // Issued by GST-registered creators
+	InvoiceTypeBillOfSupply InvoiceType = "bill_of_supply" // Issued by unregistered creators; no GST
+)
+
+// InvoiceStatus tracks an invoice through issuing.
+type InvoiceStatus string
+
+const (
+	// InvoiceStatusPending marks an order claimed for invoicing that has no number yet. Claiming
+	// before numbering means only one request allocates a number per order, keeping the series
+	// free of gaps.
+	InvoiceStatusPending InvoiceStatus = "pending"
+	InvoiceStatusIssued  InvoiceStatus = "issued" // Also assumed when unset
+)
+
+// InvoiceParty is the seller or buyer printed on an invoice.
+type InvoiceParty struct {
+	Name      string   `bson:"name" json:"name"`
+	Email     string   `bson:"email,omitempty" json:"email,omitempty"`
+	GSTIN     string   `bson:"gstin,omitempty" json:"gstin,omitempty"`
+	Address   []string `bson:"address,omitempty" json:"address,omitempty"`
+	StateCode string   `bson:"state_code,omitempty" json:"state_code,omitempty"`
+}
+
+// InvoiceLine is one product on an invoice. Amounts are in paise.
+type InvoiceLine struct {
+	Description   string `bson:"description" json:"description"`
+	SACCode       string `bson:"sac_code,omitempty" json:"sac_code,omitempty"`
+	TaxableAmount int64  `bson:"taxable_amount" json:"taxable_amount"`
+	CGST          int64  `bson:"cgst" json:"cgst"`
+	SGST          int64  `bson:"sgst" json:"sgst"`
+	IGST          int64  `bson:"igst" json:"igst"`
+	Total         int64  `bson:"total" json:"total"`
+}
+
+// Invoice is the invoice issued for a paid order. Seller and buyer details are copied at
+// issue time so later profile changes don't alter issued invoices. Amounts are in paise.
+type Invoice struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CreatorID     primitive.ObjectID `bson:"creator_id" json:"creator_id"`
+	OrderID       primitive.ObjectID `bson:"order_id" json:"order_id"`
+	Number        string             `bson:"number,omitempty" json:"number"` // e.g. INV/26-27/00042, sequential per creator and financial year
+	Status        InvoiceStatus      `bson:"status,omitempty" json:"-"`
+	FinancialYear string             `bson:"financial_year" json:"financial_year"`
+	Type          InvoiceType        `bson:"type" json:"type"`
+	IssuedAt      time.Time          `bson:"issued_at" json:"issued_at"`
+	Seller        InvoiceParty       `bson:"seller" json:"seller"`
+	Buyer         InvoiceParty       `bson:"buyer" json:"buyer"`
+	PlaceOfSupply string             `bson:"place_of_supply,omitempty" json:"place_of_supply,omitempty"` // State code
+	Interstate    bool               `bson:"interstate" json:"interstate"`                               // IGST rather than CGST/SGST
+	TaxRate       float64            `bson:"tax_rate" json:"tax_rate"`
+	Lines         []InvoiceLine      `bson:"lines" json:"lines"`
+	TaxableAmount int64              `bson:"taxable_amount" json:"taxable_amount"`
+	CGST          int64              `bson:"cgst" json:"cgst"`
+	SGST          int64              `bson:"sgst" json:"sgst"`
+	IGST          int64              `bson:"igst" json:"igst"`
+	TotalTax      int64              `bson:"total_tax" json:"total_tax"`
+	Total         int64              `bson:"total" json:"total"`
+	Currency      string             `bson:"currency" json:"currency"`
+	FileKey       string             `bson:"file_key" json:"-"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// Pending reports whether the invoice is still being issued.
+func (i *Invoice) Pending() bool {
+	return i.Status == InvoiceStatusPending
+}
+
+// TaxSummaryRow totals a month's invoices for one place of supply and tax rate.
+type TaxSummaryRow struct {
+	PlaceOfSupply string  `json:"place_of_supply"`
+	StateName     string  `json:"state_name"`
+	Interstate    bool    `json:"interstate"`
+	TaxRate       float64 `json:"tax_rate"`
+	Invoices      int     `json:"invoices"`
+	TaxableAmount int64   `json:"taxable_amount"`
+	CGST          int64   `json:"cgst"`
+	SGST          int64   `json:"sgst"`
+	IGST          int64   `json:"igst"`
+	Total         int64   `json:"total"`
+}
+
+// TaxSummary totals a creator's invoices for one calendar month, for filing GST returns.
+type TaxSummary struct {
+	Month         string          `json:"month"` // YYYY-MM
+	Invoices      int             `json:"invoices"`
+	TaxableAmount int64           `json:"taxable_amount"`
+	CGST          int64           `json:"cgst"`
+	SGST          int64           `json:"sgst"`
+	IGST          int64           `json:"igst"`
+	TotalTax      int64           `json:"total_tax"`
+	Total         int64           `json:"total"`
+	Rows          []TaxSummaryRow `json:"rows"`
+}
+
+// InvoiceSettingsRepository defines the interface for creator invoice settings storage.
+type InvoiceSettingsRepository interface {
+	// FindByCreator returns nil, nil when the creator has not set up invoicing.
+	FindByCreator(ctx context.Context, creatorID primitive.ObjectID) (*InvoiceSettings, error)
+	// Upsert saves the creator's settings, creating them if needed.
+	Upsert(ctx context.Context, settings *InvoiceSettings) error
+	// NextSequence atomically allocates the creator's next invoice number for a financial year,
+	// starting at 1.
+	NextSequence(ctx context.Context, creatorID primitive.ObjectID, financialYear string) (int64, error)
+}
+
+// InvoiceRepository defines the interface for issued invoice storage.
+type InvoiceRepository interface {
+	// Claim reserves an order for invoicing with a pending invoice and returns a token for
+	// Issue. It returns "" when another claim holds the order, unless that claim was made
+	// before staleBefore, in which case it is taken over.
+	Claim(ctx context.Context, creatorID, orderID primitive.ObjectID, staleBefore time.Time) (string, error)
+	// Issue completes a claimed invoice. It fails if the claim has since been taken over.
+	Issue(ctx context.Context, invoice *Invoice, claimToken string) error
+	// FindByOrder returns nil, nil when the order has no invoice yet. The invoice may be pending.
+	FindByOrder(ctx context.Context, orderID primitive.ObjectID) (*Invoice, error)
+	// FindByCreatorAndPeriod returns the creator's invoices issued in [from, to), oldest first.
+	FindByCreatorAndPeriod(ctx context.Context, creatorID primitive.ObjectID, from, to time.Time) ([]*Invoice, error)
+}
@@ -43,6 +43,12 @@ type Order struct {
 	RazorpayPaymentID string             `bson:"razorpay_payment_id,omitempty" json:"razorpay_payment_id,omitempty"`
 	Status            OrderStatus        `bson:"status" json:"status"`
 	ReminderSentAt    *time.Time         `bson:"reminder_sent_at,omitempty" json:"reminder_sent_at,omitempty"`
+	PaidAt            *time.Time         `bson:"paid_at,omitempty" json:"paid_at,omitempty"` // Unset on orders paid before it was recorded
+
+	// Tax Fields
+	TaxRate   float64         `bson:"tax_rate,omitempty" json:"tax_rate,omitempty"`     // GST percent charged at checkout
+	TaxAmount int64           `bson:"tax_amount,omitempty" json:"tax_amount,omitempty"` // In paise, included in Amount
+	Billing   *BillingDetails `bson:"billing,omitempty" json:"billing,omitempty"`
 
 	// Affiliate Fields
 	AffiliateID  *primitive.ObjectID `bson:"affiliate_id,omitempty" json:"affiliate_id,omitempty"`
@@ -52,6 +58,50 @@ type Order struct {
 	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
 }
 
+// OrderSort defines the order in which order search results are returned.
+type OrderSort string
+
+const (
+	OrderSortNewest     OrderSort = "newest" // Default
+	OrderSortOldest     OrderSort = "oldest"
+	OrderSortAmountDesc OrderSort = "amount_desc"
+	OrderSortAmountAsc  OrderSort = "amount_asc"
+)
+
+// IsValidOrderSort reports whether sort is one of the order sort options.
+func IsValidOrderSort(sort OrderSort) bool {
+	switch sort {
+	case OrderSortNewest, OrderSortOldest, OrderSortAmountDesc, OrderSortAmountAsc:
+		return true
+	}
+	return false
+}
+
+// OrderFilter narrows a creator's order search. Zero values are ignored.
+type OrderFilter struct {
+	CreatorID   primitive.ObjectID
+	Status      OrderStatus
+	ProductID   primitive.ObjectID // Matches the legacy product_id or any line item
+	From        time.Time          // Inclusive
+	To          time.Time          // Exclusive
+	CouponCode  string
+	AffiliateID primitive.ObjectID
+	MinAmount   *int64 // In paise, inclusive
+	MaxAmount   *int64 // In paise, inclusive
+	Search      string // Case-insensitive match on the buyer's email or name
+}
+
+// OrderTotals summarises every order matching a filter, not just one page.
+// Revenue figures only count paid orders.
+type OrderTotals struct {
+	Count        int64 `bson:"count" json:"count"`
+	PaidCount    int64 `bson:"paid_count" json:"paid_count"`
+	Revenue      int64 `bson:"revenue" json:"revenue"`             // Sum of paid order amounts, in paise
+	Discounts    int64 `bson:"discounts" json:"discounts"`         // In paise
+	PlatformFees int64 `bson:"platform_fees" json:"platform_fees"` // In paise
+	Net          int64 `bson:"net" json:"net"`                     // Revenue minus platform fees
+}
+
 // OrderRepository defines the interface for order storage
 type OrderRepository interface {
 	Create(ctx context.Context, order *Order) error
@@ -60,7 +110,14 @@ type OrderRepository interface {
 	FindByRazorpayOrderID(ctx context.Context, razorpayOrderID string) (*Order, error)
 	FindByID(ctx context.Context, id primitive.ObjectID) (*Order, error)
 	FindAllByCreatorID(ctx context.Context, creatorID primitive.ObjectID) ([]*Order, error)
+	// Search returns a page of orders matching filter.
+	Search(ctx context.Context, filter OrderFilter, sort OrderSort, pagination *Pagination) ([]*Order, *PaginationMeta, error)
+	// Totals summarises every order matching filter.
+	Totals(ctx context.Context, filter OrderFilter) (*OrderTotals, error)
 	FindAllByCustomerEmail(ctx context.Context, email string) ([]*Order, error)
+	FindPaidByProductID(ctx context.Context, productID primitive.ObjectID) ([]*Order, error)
 	FindAbandonedOrders(ctx context.Context, since time.Time, until time.Time) ([]*Order, error)
 	MarkReminderSent(ctx context.Context, orderID primitive.ObjectID) error
+	// AnonymiseCustomer replaces the customer's email and name on all their orders.
+	AnonymiseCustomer(ctx context.Context, email, anonEmail, anonName string) error
 }
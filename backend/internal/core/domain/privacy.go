@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DeletedUserName replaces a person's name on the records kept after their account is deleted.
+const DeletedUserName = "Deleted user"
+
+// DataExportStatus defines the lifecycle status of a personal data export
+type DataExportStatus string
+
+const (
+	DataExportPending DataExportStatus = "pending"
+	DataExportReady   DataExportStatus = "ready"
+	DataExportFailed  DataExportStatus = "failed"
+)
+
+// DataExport is a user's request for a copy of their personal data. The archive is built in the
+// background, stored as a zip and removed from storage once it expires.
+type DataExport struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Status      DataExportStatus   `bson:"status" json:"status"`
+	FileKey     string             `bson:"file_key,omitempty" json:"-"`
+	Size        int64              `bson:"size,omitempty" json:"size,omitempty"` // Bytes
+	Error       string             `bson:"error,omitempty" json:"error,omitempty"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	CompletedAt *time.Time         `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time         `bson:"expires_at,omitempty" json:"expires_at,omitempty"` // When the archive is deleted
+}
+
+// DataExportRepository defines the interface for data export storage
+type DataExportRepository interface {
+	Create(ctx context.Context, export *DataExport) error
+	// FindByID returns nil, nil when no export matches.
+	FindByID(ctx context.Context, id primitive.ObjectID) (*DataExport, error)
+	// FindLatestByUser returns the user's most recent export, or nil.
+	FindLatestByUser(ctx context.Context, userID primitive.ObjectID) (*DataExport, error)
+	Update(ctx context.Context, export *DataExport) error
+	// FindExpired returns ready exports whose archive expired before the given time.
+	FindExpired(ctx context.Context, before time.Time) ([]*DataExport, error)
+	// DeleteByUser removes every export record of the user.
+	DeleteByUser(ctx context.Context, userID primitive.ObjectID) error
+}
@@ -32,6 +32,26 @@ type AvailabilityWindow struct {
 	EndTime   string `bson:"end_time" json:"end_time"`       // Format: "HH:MM"
 }
 
+// FileVersion is a release of a product file that has since been replaced
+type FileVersion struct {
+	Version    int       `bson:"version" json:"version"`
+	FileKey    string    `bson:"file_key" json:"-"`
+	Notes      string    `bson:"notes,omitempty" json:"notes,omitempty"`
+	ReleasedAt time.Time `bson:"released_at" json:"released_at"`
+	ReplacedAt time.Time `bson:"replaced_at" json:"replaced_at"`
+}
+
+// ProductFile is one labelled file delivered with a product, e.g. the main PDF or a bonus pack
+type ProductFile struct {
+	ID         primitive.ObjectID `bson:"_id" json:"id"`
+	Label      string             `bson:"label" json:"label"`
+	FileKey    string             `bson:"file_key" json:"-"`
+	Version    int                `bson:"version" json:"version"`
+	Notes      string             `bson:"notes,omitempty" json:"notes,omitempty"` // Release notes for the current version
+	ReleasedAt time.Time          `bson:"released_at" json:"released_at"`
+	History    []FileVersion      `bson:"history,omitempty" json:"history,omitempty"` // Replaced versions, oldest first
+}
+
 // Product represents a digital product in the store
 type Product struct {
 	ID                      primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
@@ -40,7 +60,9 @@ type Product struct {
 	Description             string               `bson:"description" json:"description"`
 	Price                   int64                `bson:"price" json:"price"` // In paise/cents
 	CoverImageURL           string               `bson:"cover_image_url" json:"cover_image_url"`
-	FileURL                 string               `bson:"file_url,omitempty" json:"-"` // Never return file URL in JSON
+	CoverImage              *ResponsiveImage     `bson:"-" json:"cover_image,omitempty"` // Resized variants, set on storefront responses
+	FileURL                 string               `bson:"file_url,omitempty" json:"-"`    // Never return file URL in JSON; mirrors the first entry of Files
+	Files                   []ProductFile        `bson:"files,omitempty" json:"files,omitempty"`
 	ProductType             ProductType          `bson:"product_type" json:"product_type"`
 	IsVisible               bool                 `bson:"is_visible" json:"is_visible"`
 	SortOrder               int                  `bson:"sort_order" json:"sort_order"`
@@ -49,6 +71,11 @@ type Product struct {
 	Timezone                string               `bson:"timezone,omitempty" json:"timezone,omitempty"`
 	Availability            []AvailabilityWindow `bson:"availability,omitempty" json:"availability,omitempty"`
 	CancellationWindowHours int                  `bson:"cancellation_window_hours,omitempty" json:"cancellation_window_hours,omitempty"`
+	SeatsPerSlot            int                  `bson:"seats_per_slot,omitempty" json:"seats_per_slot,omitempty"` // Group sessions; 0 or 1 = 1:1
+	// Download Entitlement Fields
+	DownloadLimit      int  `bson:"download_limit,omitempty" json:"download_limit,omitempty"`             // Downloads allowed per purchase; 0 = unlimited
+	DownloadExpiryDays int  `bson:"download_expiry_days,omitempty" json:"download_expiry_days,omitempty"` // Days after purchase the link works; 0 = never expires
+	StampPDF           bool `bson:"stamp_pdf,omitempty" json:"stamp_pdf,omitempty"`                       // Personalise PDF files with the buyer's details
 	// Subscription Fields
 	SubscriptionInterval      string `bson:"subscription_interval,omitempty" json:"subscription_interval,omitempty"`             // "daily", "weekly", "monthly", "yearly"
 	SubscriptionBillingCycles int    `bson:"subscription_billing_cycles,omitempty" json:"subscription_billing_cycles,omitempty"` // 0 = indefinite
@@ -66,6 +93,74 @@ type Product struct {
 	UpdatedAt time.Time  `bson:"updated_at" json:"updated_at"`
 }
 
+// SlotCapacity returns how many buyers can book a single slot of this product.
+func (p *Product) SlotCapacity() int {
+	if p.SeatsPerSlot < 1 {
+		return 1
+	}
+	return p.SeatsPerSlot
+}
+
+// DownloadFiles returns the product's files in display order. Products created before
+// multi-file support have only FileURL, which is presented as a single file whose ID is
+// the product ID.
+func (p *Product) DownloadFiles() []ProductFile {
+	if len(p.Files) > 0 {
+		return p.Files
+	}
+	if p.FileURL == "" {
+		return nil
+	}
+	return []ProductFile{{
+		ID:         p.ID,
+		Label:      p.Title,
+		FileKey:    p.FileURL,
+		Version:    1,
+		ReleasedAt: p.CreatedAt,
+	}}
+}
+
+// DownloadFile returns the file with the given ID, or the first file when id is zero.
+func (p *Product) DownloadFile(id primitive.ObjectID) *ProductFile {
+	files := p.DownloadFiles()
+	if len(files) == 0 {
+		return nil
+	}
+	if id.IsZero() {
+		return &files[0]
+	}
+	for i := range files {
+		if files[i].ID == id {
+			return &files[i]
+		}
+	}
+	return nil
+}
+
+// AddProductFileRequest represents the payload for attaching a file to a product
+type AddProductFileRequest struct {
+	Label   string `json:"label"`
+	FileKey string `json:"file_key"`
+	Notes   string `json:"notes"`
+}
+
+// ReplaceProductFileRequest represents the payload for releasing a new version of a file
+type ReplaceProductFileRequest struct {
+	FileKey      string `json:"file_key"`
+	Notes        string `json:"notes"`
+	NotifyBuyers bool   `json:"notify_buyers"`
+}
+
+// UpdateProductFileRequest represents the payload for relabelling a file
+type UpdateProductFileRequest struct {
+	Label string `json:"label"`
+}
+
+// ReorderFilesRequest represents the payload for reordering a product's files
+type ReorderFilesRequest struct {
+	FileIDs []string `json:"file_ids"`
+}
+
 // UpdateVisibilityRequest represents the payload for toggling visibility
 type UpdateVisibilityRequest struct {
 	IsVisible bool `json:"is_visible"`
@@ -81,6 +176,7 @@ type ProductRepository interface {
 	Create(ctx context.Context, product *Product) error
 	FindByID(ctx context.Context, id primitive.ObjectID) (*Product, error)
 	FindAllByCreatorID(ctx context.Context, creatorID primitive.ObjectID) ([]*Product, error)
+	FindDeletedByCreatorID(ctx context.Context, creatorID primitive.ObjectID) ([]*Product, error)
 	Update(ctx context.Context, product *Product) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
 	UpdateVisibility(ctx context.Context, id primitive.ObjectID, creatorID primitive.ObjectID, isVisible bool) error
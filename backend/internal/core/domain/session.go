@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// Session is one signed-in device. Each session owns a rotating refresh token; revoking the
+// session invalidates both the refresh token and any access tokens issued from it.
+type Session struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"-"`
+	Device     string    `json:"device"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"` // Set when listing: the session making the request
+}
+
+// Impersonation is a time-boxed, read-only session an admin opens on a creator's account to
+// investigate a support issue. It is returned with the user from /auth/me so the frontend
+// can show a banner.
+type Impersonation struct {
+	SessionID  string    `json:"session_id"`
+	AdminID    string    `json:"admin_id"`
+	AdminEmail string    `json:"admin_email,omitempty"`
+	CreatorID  string    `json:"creator_id"`
+	Reason     string    `json:"reason"`
+	ReadOnly   bool      `json:"read_only"`
+	StartedAt  time.Time `json:"started_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
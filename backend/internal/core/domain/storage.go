@@ -2,9 +2,18 @@ package domain
 
 import (
 	"context"
+	"io"
 	"time"
 )
 
+// FileInfo describes a stored object.
+type FileInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
 // FileStorage defines the interface for interacting with object storage (S3/GCS/R2).
 type FileStorage interface {
 	// GeneratePresignedURL generates a pre-signed URL for uploading a file.
@@ -13,6 +22,46 @@ type FileStorage interface {
 	// GeneratePresignedDownloadURL generates a pre-signed URL for downloading a file.
 	GeneratePresignedDownloadURL(ctx context.Context, key string, expiry time.Duration) (string, error)
 
+	// Upload writes a server-generated file (certificates, invoices) directly to storage.
+	Upload(ctx context.Context, key string, contentType string, data []byte) error
+
+	// Head returns an object's metadata without downloading it.
+	Head(ctx context.Context, key string) (*FileInfo, error)
+
+	// Download reads a whole object into memory. Callers should check the size with Head first.
+	Download(ctx context.Context, key string) ([]byte, error)
+
+	// Open streams an object's contents. The caller must close the reader.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+
 	// Delete removes a file from storage.
 	Delete(ctx context.Context, key string) error
+
+	// List returns every object whose key starts with prefix. ContentType is not populated.
+	List(ctx context.Context, prefix string) ([]FileInfo, error)
+}
+
+// OrphanedFile is a stored object that nothing references any more.
+type OrphanedFile struct {
+	Key          string    `json:"key"`
+	CreatorID    string    `json:"creator_id"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+	Deleted      bool      `json:"deleted"`
+	Error        string    `json:"error,omitempty"` // Why the delete failed
+}
+
+// StorageSweepReport summarises one pass of the orphaned file sweeper.
+type StorageSweepReport struct {
+	DryRun         bool           `json:"dry_run"`
+	GracePeriod    string         `json:"grace_period"`
+	Scanned        int            `json:"scanned"`
+	Referenced     int            `json:"referenced"`
+	InGracePeriod  int            `json:"in_grace_period"` // Unreferenced but too new to delete, e.g. uploads not yet attached
+	Orphans        []OrphanedFile `json:"orphans"`
+	OrphanBytes    int64          `json:"orphan_bytes"`
+	Deleted        int            `json:"deleted"`
+	FailedCreators []string       `json:"failed_creators,omitempty"` // Skipped because their references could not be loaded
+	StartedAt      time.Time      `json:"started_at"`
+	FinishedAt     time.Time      `json:"finished_at"`
 }
@@ -21,8 +21,9 @@ type EmailSubscriber struct {
 
 // EmailSubscriberRepository defines the interface for subscriber storage.
 type EmailSubscriberRepository interface {
-	// Upsert creates or updates a subscriber (unique on creator_id + email)
-	Upsert(ctx context.Context, sub *EmailSubscriber) error
+	// Upsert creates or updates a subscriber (unique on creator_id + email). It reports
+	// whether the subscriber is new to the creator's list.
+	Upsert(ctx context.Context, sub *EmailSubscriber) (bool, error)
 
 	// FindByEmail attempts to find a single active subscriber by their mail and creator.
 	FindByEmail(ctx context.Context, email string, creatorID string) (*EmailSubscriber, error)
@@ -35,4 +36,10 @@ type EmailSubscriberRepository interface {
 
 	// Count returns the total subscriber count for a creator
 	Count(ctx context.Context, creatorID primitive.ObjectID) (int64, error)
+
+	// FindAllByEmail returns every creator list the address is on, including unsubscribed entries
+	FindAllByEmail(ctx context.Context, email string) ([]*EmailSubscriber, error)
+
+	// DeleteByEmail removes the address from every creator's list
+	DeleteByEmail(ctx context.Context, email string) error
 }
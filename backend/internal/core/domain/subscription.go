@@ -48,4 +48,6 @@ type SubscriptionRepository interface {
 	FindByID(ctx context.Context, id primitive.ObjectID) (*Subscription, error)
 	FindAllByCreatorID(ctx context.Context, creatorID primitive.ObjectID) ([]*Subscription, error)
 	FindAllByCustomerEmail(ctx context.Context, email string) ([]*Subscription, error)
+	// AnonymiseCustomer replaces the customer's email and name on all their subscriptions.
+	AnonymiseCustomer(ctx context.Context, email, anonEmail, anonName string) error
 }
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Team roles a creator can grant to a collaborator. A member can hold several.
+const (
+	TeamRoleProductsEditor = "products_editor" // Store profile, products, courses and uploads
+	TeamRoleSupport        = "support"         // Orders, bookings and download unlocks; read-only products
+	TeamRoleMarketing      = "marketing"       // Coupons, campaigns, newsletter, affiliates, automations
+	TeamRoleFinance        = "finance"         // Read-only earnings, payouts and orders
+)
+
+// Team areas group the creator routes a role can reach.
+const (
+	TeamAreaProducts  = "products"
+	TeamAreaOrders    = "orders"
+	TeamAreaMarketing = "marketing"
+	TeamAreaFinance   = "finance"
+)
+
+// TeamAccess is what a role may do in an area.
+type TeamAccess int
+
+const (
+	TeamAccessNone TeamAccess = iota
+	TeamAccessRead
+	TeamAccessWrite
+)
+
+// teamRoleAccess maps each role to the areas it can reach.
+var teamRoleAccess = map[string]map[string]TeamAccess{
+	TeamRoleProductsEditor: {TeamAreaProducts: TeamAccessWrite},
+	TeamRoleSupport:        {TeamAreaOrders: TeamAccessWrite, TeamAreaProducts: TeamAccessRead},
+	TeamRoleMarketing:      {TeamAreaMarketing: TeamAccessWrite, TeamAreaProducts: TeamAccessRead},
+	TeamRoleFinance:        {TeamAreaFinance: TeamAccessRead, TeamAreaOrders: TeamAccessRead},
+}
+
+// IsValidTeamRole reports whether role is one of the team roles.
+func IsValidTeamRole(role string) bool {
+	_, ok := teamRoleAccess[role]
+	return ok
+}
+
+// Team member statuses
+const (
+	TeamMemberInvited = "invited"
+	TeamMemberActive  = "active"
+)
+
+// TeamMember is a collaborator on a creator's account. Until the invite is accepted, UserID
+// is empty and the member is identified by Email.
+type TeamMember struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CreatorID       primitive.ObjectID `bson:"creator_id" json:"creator_id"`
+	UserID          primitive.ObjectID `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	Email           string             `bson:"email" json:"email"`
+	Roles           []string           `bson:"roles" json:"roles"`
+	Status          string             `bson:"status" json:"status"`
+	InvitedBy       primitive.ObjectID `bson:"invited_by" json:"invited_by"`
+	InviteTokenHash string             `bson:"invite_token_hash,omitempty" json:"-"`
+	InviteExpiresAt *time.Time         `bson:"invite_expires_at,omitempty" json:"invite_expires_at,omitempty"`
+	AcceptedAt      *time.Time         `bson:"accepted_at,omitempty" json:"accepted_at,omitempty"`
+	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Access returns the highest access any of the member's roles grants in area.
+func (m *TeamMember) Access(area string) TeamAccess {
+	best := TeamAccessNone
+	for _, role := range m.Roles {
+		if access := teamRoleAccess[role][area]; access > best {
+			best = access
+		}
+	}
+	return best
+}
+
+// TeamAccount is a creator account the user can act on as a team member.
+type TeamAccount struct {
+	CreatorID   primitive.ObjectID `json:"creator_id"`
+	DisplayName string             `json:"display_name"`
+	Username    string             `json:"username,omitempty"`
+	AvatarURL   string             `json:"avatar_url,omitempty"`
+	Roles       []string           `json:"roles"`
+}
+
+// TeamActivity records a change a team member made on a creator's account.
+type TeamActivity struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CreatorID  primitive.ObjectID `bson:"creator_id" json:"creator_id"`
+	ActorID    primitive.ObjectID `bson:"actor_id" json:"actor_id"`
+	ActorEmail string             `bson:"actor_email" json:"actor_email"`
+	Action     string             `bson:"action" json:"action"` // e.g. "PUT /api/v1/products/:id", or "team.invite"
+	Target     string             `bson:"target,omitempty" json:"target,omitempty"`
+	Status     int                `bson:"status,omitempty" json:"status,omitempty"` // HTTP status of the request
+	IP         string             `bson:"ip,omitempty" json:"ip,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+}
+
+// TeamMemberRepository defines the interface for team member storage.
+type TeamMemberRepository interface {
+	Create(ctx context.Context, member *TeamMember) error
+	// FindByID returns nil, nil when no member matches.
+	FindByID(ctx context.Context, id primitive.ObjectID) (*TeamMember, error)
+	// FindByInviteToken returns the pending invite with this token hash, or nil.
+	FindByInviteToken(ctx context.Context, tokenHash string) (*TeamMember, error)
+	// FindActive returns the active membership of userID on creatorID's account, or nil.
+	FindActive(ctx context.Context, creatorID, userID primitive.ObjectID) (*TeamMember, error)
+	FindByCreator(ctx context.Context, creatorID primitive.ObjectID) ([]*TeamMember, error)
+	// FindActiveByUser lists the accounts userID is an active member of.
+	FindActiveByUser(ctx context.Context, userID primitive.ObjectID) ([]*TeamMember, error)
+	// FindByEmail returns creatorID's member (invited or active) with this email, or nil.
+	FindByEmail(ctx context.Context, creatorID primitive.ObjectID, email string) (*TeamMember, error)
+	Update(ctx context.Context, member *TeamMember) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+}
+
+// TeamActivityRepository defines the interface for the team audit trail.
+type TeamActivityRepository interface {
+	Create(ctx context.Context, activity *TeamActivity) error
+	// FindByCreator lists a creator's team activity, newest first.
+	FindByCreator(ctx context.Context, creatorID primitive.ObjectID, limit int64) ([]*TeamActivity, error)
+}
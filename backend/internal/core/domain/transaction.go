@@ -36,9 +36,20 @@ type Transaction struct {
 	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
 }
 
+// TransactionFilter narrows a creator's wallet transactions. Zero values are ignored.
+type TransactionFilter struct {
+	CreatorID primitive.ObjectID
+	Type      TransactionType
+	Source    TransactionSource
+	From      time.Time // Inclusive
+	To        time.Time // Exclusive
+}
+
 // TransactionRepository defines the interface for transaction storage
 type TransactionRepository interface {
 	Create(ctx context.Context, tx *Transaction) error
 	FindAllByCreatorID(ctx context.Context, creatorID primitive.ObjectID) ([]*Transaction, error)
+	// Search returns a page of matching transactions, newest first.
+	Search(ctx context.Context, filter TransactionFilter, pagination *Pagination) ([]*Transaction, *PaginationMeta, error)
 	GetBalance(ctx context.Context, creatorID primitive.ObjectID) (int64, error)
 }
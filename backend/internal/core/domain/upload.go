@@ -1,5 +1,13 @@
 package domain
 
+import (
+	"context"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
 type FilePurpose string
 
 const (
@@ -19,3 +27,61 @@ type UploadResponse struct {
 	UploadURL string `json:"upload_url"`
 	FileKey   string `json:"file_key"`
 }
+
+// CompleteUploadRequest asks the server to verify an object after the client's PUT.
+type CompleteUploadRequest struct {
+	FileKey string `json:"file_key"`
+}
+
+type UploadStatus string
+
+const (
+	UploadStatusPending  UploadStatus = "pending"
+	UploadStatusVerified UploadStatus = "verified"
+	UploadStatusRejected UploadStatus = "rejected"
+)
+
+// Upload tracks a presigned upload from issue through verification.
+// Only verified uploads may be attached to products.
+type Upload struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	CreatorID    primitive.ObjectID `bson:"creator_id" json:"creator_id"`
+	FileKey      string             `bson:"file_key" json:"file_key"`
+	Purpose      FilePurpose        `bson:"purpose" json:"purpose"`
+	Status       UploadStatus       `bson:"status" json:"status"`
+	Size         int64              `bson:"size,omitempty" json:"size,omitempty"`                 // Bytes, from storage
+	ContentType  string             `bson:"content_type,omitempty" json:"content_type,omitempty"` // Detected from the file's magic bytes
+	RejectReason string             `bson:"reject_reason,omitempty" json:"reject_reason,omitempty"`
+	VerifiedAt   *time.Time         `bson:"verified_at,omitempty" json:"verified_at,omitempty"`
+	// Media processing (cover images, profile photos and lesson videos)
+	Width           int            `bson:"width,omitempty" json:"width,omitempty"`
+	Height          int            `bson:"height,omitempty" json:"height,omitempty"`
+	Variants        []ImageVariant `bson:"variants,omitempty" json:"variants,omitempty"`
+	DurationSeconds int            `bson:"duration_seconds,omitempty" json:"duration_seconds,omitempty"`
+	HLSPrefix       string         `bson:"hls_prefix,omitempty" json:"-"` // Storage prefix holding the playlists and segments
+	Renditions      []HLSRendition `bson:"renditions,omitempty" json:"renditions,omitempty"`
+	ProcessError    string         `bson:"process_error,omitempty" json:"process_error,omitempty"`
+	ProcessedAt     *time.Time     `bson:"processed_at,omitempty" json:"processed_at,omitempty"`
+	CreatedAt       time.Time      `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time      `bson:"updated_at" json:"updated_at"`
+}
+
+// UploadRepository defines the interface for upload records
+type UploadRepository interface {
+	Create(ctx context.Context, upload *Upload) error
+	// FindByKey returns nil, nil when no upload matches.
+	FindByKey(ctx context.Context, fileKey string) (*Upload, error)
+	FindByKeys(ctx context.Context, fileKeys []string) ([]*Upload, error)
+	Update(ctx context.Context, upload *Upload) error
+}
+
+// ScanResult is the verdict of a malware scan.
+type ScanResult struct {
+	Clean     bool
+	Signature string // Name of the detected threat when not clean
+}
+
+// MalwareScanner inspects uploaded content before it is made available to buyers.
+type MalwareScanner interface {
+	Scan(ctx context.Context, r io.Reader) (*ScanResult, error)
+}
@@ -19,8 +19,9 @@ const (
 )
 
 const (
-	UserStatusActive = "active"
-	UserStatusBanned = "banned"
+	UserStatusActive  = "active"
+	UserStatusBanned  = "banned"
+	UserStatusDeleted = "deleted" // Account erased on request; the record is kept, anonymised, for financial history
 )
 
 // PayoutConfig holds a creator's payout/bank account details.
@@ -33,6 +34,15 @@ type PayoutConfig struct {
 	IsVerified          bool   `bson:"is_verified" json:"is_verified"`
 }
 
+// TwoFactor holds a user's TOTP enrolment.
+type TwoFactor struct {
+	Enabled       bool       `bson:"enabled" json:"enabled"`
+	Secret        string     `bson:"secret,omitempty" json:"-"`         // Encrypted TOTP secret
+	RecoveryCodes []string   `bson:"recovery_codes,omitempty" json:"-"` // SHA-256 hashes of unused codes
+	LastUsedStep  int64      `bson:"last_used_step,omitempty" json:"-"` // Last accepted time step; codes can't be replayed
+	EnabledAt     *time.Time `bson:"enabled_at,omitempty" json:"enabledAt,omitempty"`
+}
+
 // User represents a user in the system.
 type User struct {
 	ID                   primitive.ObjectID `bson:"_id,omitempty" json:"id"`
@@ -57,6 +67,11 @@ type User struct {
 	AbandonedCartEnabled bool               `bson:"abandoned_cart_enabled" json:"abandonedCartEnabled"`
 	BannedAt             *time.Time         `bson:"banned_at,omitempty" json:"bannedAt,omitempty"`
 	BanReason            string             `bson:"ban_reason,omitempty" json:"banReason,omitempty"`
+	TwoFactor            *TwoFactor         `bson:"two_factor,omitempty" json:"twoFactor,omitempty"`
+	DeletionRequestedAt  *time.Time         `bson:"deletion_requested_at,omitempty" json:"deletionRequestedAt,omitempty"`
+	DeletionScheduledFor *time.Time         `bson:"deletion_scheduled_for,omitempty" json:"deletionScheduledFor,omitempty"` // End of the cooling-off period
+	DeletedAt            *time.Time         `bson:"deleted_at,omitempty" json:"deletedAt,omitempty"`
+	Impersonation        *Impersonation     `bson:"-" json:"impersonation,omitempty"` // Set by /auth/me while an admin is impersonating
 	CreatedAt            time.Time          `bson:"created_at" json:"createdAt"`
 	UpdatedAt            time.Time          `bson:"updated_at" json:"updatedAt"`
 }
@@ -85,3 +100,14 @@ func NewUserFromGoogle(email, displayName, avatarURL, googleID string) *User {
 func (u *User) HasUsername() bool {
 	return u.Username != ""
 }
+
+// HasTwoFactor returns true if the user has enrolled in TOTP two-factor authentication.
+func (u *User) HasTwoFactor() bool {
+	return u.TwoFactor != nil && u.TwoFactor.Enabled
+}
+
+// RequiresTwoFactor returns true if logging in needs a second factor. Admins must use 2FA;
+// for everyone else it is optional.
+func (u *User) RequiresTwoFactor() bool {
+	return u.HasTwoFactor() || u.Role == RoleAdmin
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // UserRepository extends the base Repository with user-specific query methods.
 type UserRepository interface {
@@ -9,6 +12,9 @@ type UserRepository interface {
 	// FindByEmail finds a user by their email address (case-insensitive).
 	FindByEmail(ctx context.Context, email string) (*User, error)
 
+	// FindByEmails returns the users with any of the given email addresses (case-insensitive).
+	FindByEmails(ctx context.Context, emails []string) ([]*User, error)
+
 	// FindByGoogleID finds a user by their Google OAuth ID.
 	FindByGoogleID(ctx context.Context, googleID string) (*User, error)
 
@@ -17,4 +23,26 @@ type UserRepository interface {
 
 	// UpdateStatus updates a user's status (active/banned) along with ban metadata.
 	UpdateStatus(ctx context.Context, id string, status string, reason string) error
+
+	// UpdatePassword replaces a user's bcrypt password hash.
+	UpdatePassword(ctx context.Context, id string, passwordHash string) error
+
+	// UpdateTwoFactor replaces a user's 2FA enrolment; nil removes it.
+	UpdateTwoFactor(ctx context.Context, id string, twoFactor *TwoFactor) error
+
+	// ConsumeTOTPStep records step as the last accepted TOTP time step. It returns false if a
+	// code from this or a later step was already used.
+	ConsumeTOTPStep(ctx context.Context, id string, step int64) (bool, error)
+
+	// ConsumeRecoveryCode removes a recovery code hash, returning false if it was not present.
+	ConsumeRecoveryCode(ctx context.Context, id string, codeHash string) (bool, error)
+
+	// UpdateDeletionSchedule schedules the account for deletion at scheduledFor; nil cancels it.
+	UpdateDeletionSchedule(ctx context.Context, id string, scheduledFor *time.Time) error
+
+	// FindDueForDeletion returns accounts whose deletion is scheduled at or before the given time.
+	FindDueForDeletion(ctx context.Context, before time.Time) ([]*User, error)
+
+	// Anonymise strips a user's personal data, replaces their email and marks the account deleted.
+	Anonymise(ctx context.Context, id string, email string) error
 }
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Lesson video stream states
+const (
+	VideoStatusProcessing = "processing"
+	VideoStatusReady      = "ready"
+	VideoStatusFailed     = "failed"
+)
+
+// HLSRenditionSpec describes one quality level a lesson video is transcoded to
+type HLSRenditionSpec struct {
+	Name         string // e.g. "720p"; also the playlist and segment file prefix
+	Height       int
+	VideoBitrate int // Bits per second
+	AudioBitrate int
+}
+
+// HLSRendition is a transcoded quality level stored next to the source video
+type HLSRendition struct {
+	Name      string `bson:"name" json:"name"`
+	Width     int    `bson:"width" json:"width"`
+	Height    int    `bson:"height" json:"height"`
+	Bandwidth int    `bson:"bandwidth" json:"bandwidth"` // Peak bits per second, for EXT-X-STREAM-INF
+}
+
+// VideoProbe is what the transcoder learns about a source video
+type VideoProbe struct {
+	Width           int
+	Height          int
+	DurationSeconds int
+}
+
+// LessonStream tells the course player how to play an uploaded lesson video.
+// Computed per viewer; URLs are signed and expire.
+type LessonStream struct {
+	Status      string    `json:"status"`
+	URL         string    `json:"url,omitempty"`          // HLS master playlist, once transcoded
+	FallbackURL string    `json:"fallback_url,omitempty"` // Original MP4 while the HLS renditions are not available
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// VideoTranscoder converts uploaded videos to HLS. TranscodeHLS writes {spec.Name}.m3u8 and
+// its {spec.Name}_NNNN.ts segments into outDir.
+type VideoTranscoder interface {
+	Probe(ctx context.Context, inputPath string) (*VideoProbe, error)
+	TranscodeHLS(ctx context.Context, inputPath string, outDir string, spec HLSRenditionSpec) error
+}
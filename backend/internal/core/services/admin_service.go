@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
 )
 
 type AdminService struct {
@@ -18,6 +19,8 @@ type AdminService struct {
 	workerService *WorkerService
 	cache         domain.Cache
 	webhookRepo   domain.WebhookEventRepository
+	sessions      *SessionService
+	audit         *AuditService
 }
 
 // NewAdminService creates a new AdminService.
@@ -46,6 +49,16 @@ func (s *AdminService) SetWorkerService(ws *WorkerService) {
 	s.workerService = ws
 }
 
+// SetSessionService injects the session store (optional, needed to sign banned creators out)
+func (s *AdminService) SetSessionService(sessions *SessionService) {
+	s.sessions = sessions
+}
+
+// SetAuditService injects the audit log (optional, records bans and unbans)
+func (s *AdminService) SetAuditService(audit *AuditService) {
+	s.audit = audit
+}
+
 // GetCacheStats retrieves cache stats (memory usage, keys, connection status).
 func (s *AdminService) GetCacheStats(ctx context.Context) (map[string]interface{}, error) {
 	if s.cache == nil {
@@ -98,7 +111,7 @@ func (s *AdminService) GetPlatformMetrics(ctx context.Context) (*PlatformMetrics
 }
 
 // BanCreator sets a creator's status to banned with a reason.
-func (s *AdminService) BanCreator(ctx context.Context, creatorID string, reason string) error {
+func (s *AdminService) BanCreator(ctx context.Context, actor AuditActor, creatorID string, reason string) error {
 	// Verify user exists
 	user, err := s.userRepo.FindByID(ctx, creatorID)
 	if err != nil {
@@ -111,11 +124,25 @@ func (s *AdminService) BanCreator(ctx context.Context, creatorID string, reason
 		return fmt.Errorf("user is already banned")
 	}
 
-	return s.userRepo.UpdateStatus(ctx, creatorID, domain.UserStatusBanned, reason)
+	if err := s.userRepo.UpdateStatus(ctx, creatorID, domain.UserStatusBanned, reason); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, actor, domain.AuditCreatorBan, domain.AuditTargetUser, creatorID,
+		userStatusSnapshot(user.Status, user.BanReason), userStatusSnapshot(domain.UserStatusBanned, reason), reason)
+
+	// Sign the creator out everywhere; BanCheck still blocks any request that slips through
+	if s.sessions != nil {
+		if n, err := s.sessions.RevokeAll(ctx, creatorID); err != nil {
+			logger.Error("failed to revoke sessions of banned creator", "creator_id", creatorID, "error", err)
+		} else {
+			logger.Info("revoked sessions of banned creator", "creator_id", creatorID, "sessions", n)
+		}
+	}
+	return nil
 }
 
 // UnbanCreator restores a creator's status to active.
-func (s *AdminService) UnbanCreator(ctx context.Context, creatorID string) error {
+func (s *AdminService) UnbanCreator(ctx context.Context, actor AuditActor, creatorID string, reason string) error {
 	// Verify user exists
 	user, err := s.userRepo.FindByID(ctx, creatorID)
 	if err != nil {
@@ -128,7 +155,17 @@ func (s *AdminService) UnbanCreator(ctx context.Context, creatorID string) error
 		return fmt.Errorf("user is not banned")
 	}
 
-	return s.userRepo.UpdateStatus(ctx, creatorID, domain.UserStatusActive, "")
+	if err := s.userRepo.UpdateStatus(ctx, creatorID, domain.UserStatusActive, ""); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, actor, domain.AuditCreatorUnban, domain.AuditTargetUser, creatorID,
+		userStatusSnapshot(user.Status, user.BanReason), userStatusSnapshot(domain.UserStatusActive, ""), reason)
+	return nil
+}
+
+// userStatusSnapshot is the audited part of a user for bans and unbans.
+func userStatusSnapshot(status, banReason string) map[string]string {
+	return map[string]string{"status": status, "banReason": banReason}
 }
 
 // GetJobStats returns metrics about the background job queue
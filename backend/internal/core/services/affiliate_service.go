@@ -16,6 +16,7 @@ type AffiliateService struct {
 	repo     domain.AffiliateRepository
 	saleRepo domain.AffiliateSaleRepository
 	userRepo domain.UserRepository
+	webhooks *CreatorWebhookService
 }
 
 func NewAffiliateService(repo domain.AffiliateRepository, saleRepo domain.AffiliateSaleRepository, userRepo domain.UserRepository) *AffiliateService {
@@ -26,6 +27,11 @@ func NewAffiliateService(repo domain.AffiliateRepository, saleRepo domain.Affili
 	}
 }
 
+// SetWebhookService sends affiliate.sale events to creators' webhooks.
+func (s *AffiliateService) SetWebhookService(webhooks *CreatorWebhookService) {
+	s.webhooks = webhooks
+}
+
 // Generate unique 8 char referral code
 func generateReferralCode() string {
 	b := make([]byte, 6)
@@ -130,6 +136,7 @@ func (s *AffiliateService) TrackSale(ctx context.Context, order *domain.Order, p
 	if err := s.saleRepo.Create(ctx, sale); err != nil {
 		return err
 	}
+	s.webhooks.Publish(product.CreatorID, domain.WebhookEventAffiliateSale, sale)
 
 	// Update raw counts
 	order.AffiliateID = &aff.ID
@@ -68,6 +68,16 @@ func (m *MockAffiliateRepo) UpdateStatus(ctx context.Context, id primitive.Objec
 	return args.Error(0)
 }
 
+func (m *MockAffiliateRepo) FindAllByEmail(ctx context.Context, email string) ([]*domain.Affiliate, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).([]*domain.Affiliate), args.Error(1)
+}
+
+func (m *MockAffiliateRepo) Anonymise(ctx context.Context, email, anonEmail, anonName string) error {
+	args := m.Called(ctx, email, anonEmail, anonName)
+	return args.Error(0)
+}
+
 type MockAffiliateSaleRepo struct {
 	mock.Mock
 }
@@ -120,6 +130,14 @@ func (m *MockUserRepo) FindByEmail(ctx context.Context, email string) (*domain.U
 	return args.Get(0).(*domain.User), args.Error(1)
 }
 
+func (m *MockUserRepo) FindByEmails(ctx context.Context, emails []string) ([]*domain.User, error) {
+	args := m.Called(ctx, emails)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.User), args.Error(1)
+}
+
 func (m *MockUserRepo) FindByGoogleID(ctx context.Context, googleID string) (*domain.User, error) {
 	args := m.Called(ctx, googleID)
 	if args.Get(0) == nil {
@@ -170,6 +188,44 @@ func (m *MockUserRepo) UpdateStatus(ctx context.Context, id string, status strin
 	return args.Error(0)
 }
 
+func (m *MockUserRepo) UpdatePassword(ctx context.Context, id string, passwordHash string) error {
+	args := m.Called(ctx, id, passwordHash)
+	return args.Error(0)
+}
+
+func (m *MockUserRepo) UpdateTwoFactor(ctx context.Context, id string, twoFactor *domain.TwoFactor) error {
+	args := m.Called(ctx, id, twoFactor)
+	return args.Error(0)
+}
+
+func (m *MockUserRepo) ConsumeTOTPStep(ctx context.Context, id string, step int64) (bool, error) {
+	args := m.Called(ctx, id, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepo) ConsumeRecoveryCode(ctx context.Context, id string, codeHash string) (bool, error) {
+	args := m.Called(ctx, id, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepo) UpdateDeletionSchedule(ctx context.Context, id string, scheduledFor *time.Time) error {
+	args := m.Called(ctx, id, scheduledFor)
+	return args.Error(0)
+}
+
+func (m *MockUserRepo) FindDueForDeletion(ctx context.Context, before time.Time) ([]*domain.User, error) {
+	args := m.Called(ctx, before)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.User), args.Error(1)
+}
+
+func (m *MockUserRepo) Anonymise(ctx context.Context, id string, email string) error {
+	args := m.Called(ctx, id, email)
+	return args.Error(0)
+}
+
 func (m *MockUserRepo) Count(ctx context.Context, filter domain.Filter) (int64, error) {
 	args := m.Called(ctx, filter)
 	return args.Get(0).(int64), args.Error(1)
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+var (
+	ErrInvalidAPIKey      = errors.New("invalid or revoked API key")
+	ErrAPIKeyNotFound     = errors.New("API key not found")
+	ErrInvalidAPIScopes   = errors.New("choose at least one valid scope")
+	ErrAPIKeyLimitReached = errors.New("you have reached the maximum number of API keys")
+)
+
+// APIKeyPrefix starts every API key, so keys are recognisable in code and secret scanners.
+const APIKeyPrefix = "mio_sk_"
+
+const (
+	defaultAPIKeyRateLimit = 60  // Requests per minute
+	maxAPIKeyRateLimit     = 600 // Requests per minute
+	maxAPIKeysPerCreator   = 20
+	apiKeyRateWindow       = time.Minute
+	apiKeyTouchInterval    = time.Minute // How stale last-used may get before it is rewritten
+	apiKeyDisplayPrefixLen = len(APIKeyPrefix) + 6
+)
+
+// APIKeyService mints, authenticates and rate limits creator API keys.
+type APIKeyService struct {
+	repo     domain.APIKeyRepository
+	userRepo domain.UserRepository
+	redis    *redis.Client
+	audit    *AuditService
+
+	// In-process rate limit windows, used when Redis is unavailable
+	mu      sync.Mutex
+	windows map[primitive.ObjectID]*apiKeyWindow
+}
+
+type apiKeyWindow struct {
+	start time.Time
+	count int
+}
+
+// NewAPIKeyService creates a new APIKeyService.
+func NewAPIKeyService(repo domain.APIKeyRepository, userRepo domain.UserRepository) *APIKeyService {
+	return &APIKeyService{
+		repo:     repo,
+		userRepo: userRepo,
+		windows:  make(map[primitive.ObjectID]*apiKeyWindow),
+	}
+}
+
+// SetRedis shares rate limit counters between API instances. Without it each instance counts
+// requests on its own.
+func (s *APIKeyService) SetRedis(client *redis.Client) {
+	s.redis = client
+}
+
+// SetAuditService records key creation and revocation in the audit log.
+func (s *APIKeyService) SetAuditService(audit *AuditService) {
+	s.audit = audit
+}
+
+// Create mints a new key for the creator. The returned secret is shown to the creator once
+// and cannot be recovered. rateLimit is in requests per minute; zero uses the default.
+func (s *APIKeyService) Create(ctx context.Context, actor AuditActor, creatorID, name string, scopes []string, rateLimit int) (*domain.APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > 64 {
+		return nil, "", fmt.Errorf("name must be between 1 and 64 characters")
+	}
+	scopes, err := normaliseAPIScopes(scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	if rateLimit == 0 {
+		rateLimit = defaultAPIKeyRateLimit
+	}
+	if rateLimit < 1 || rateLimit > maxAPIKeyRateLimit {
+		return nil, "", fmt.Errorf("rate limit must be between 1 and %d requests per minute", maxAPIKeyRateLimit)
+	}
+
+	creator, err := s.userRepo.FindByID(ctx, creatorID)
+	if err != nil || creator == nil {
+		return nil, "", fmt.Errorf("creator not found")
+	}
+
+	keys, err := s.repo.FindByCreator(ctx, creator.ID)
+	if err != nil {
+		return nil, "", err
+	}
+	active := 0
+	for _, k := range keys {
+		if k.RevokedAt == nil {
+			active++
+		}
+	}
+	if active >= maxAPIKeysPerCreator {
+		return nil, "", ErrAPIKeyLimitReached
+	}
+
+	token, err := randomToken(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return nil, "", err
+	}
+	secret := APIKeyPrefix + token
+
+	key := &domain.APIKey{
+		CreatorID: creator.ID,
+		Name:      name,
+		Prefix:    secret[:apiKeyDisplayPrefixLen],
+		KeyHash:   hashToken(secret),
+		Scopes:    scopes,
+		RateLimit: rateLimit,
+	}
+	if err := s.repo.Create(ctx, key); err != nil {
+		return nil, "", err
+	}
+
+	s.audit.Record(ctx, actor, domain.AuditAPIKeyCreate, domain.AuditTargetAPIKey, key.ID.Hex(), nil,
+		map[string]interface{}{"name": key.Name, "scopes": key.Scopes, "rate_limit": key.RateLimit}, "")
+	return key, secret, nil
+}
+
+// List returns the creator's keys, including revoked ones, newest first.
+func (s *APIKeyService) List(ctx context.Context, creatorID string) ([]*domain.APIKey, error) {
+	oid, err := primitive.ObjectIDFromHex(creatorID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid creator id")
+	}
+	keys, err := s.repo.FindByCreator(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	if keys == nil {
+		keys = []*domain.APIKey{}
+	}
+	return keys, nil
+}
+
+// Revoke stops a key from working immediately.
+func (s *APIKeyService) Revoke(ctx context.Context, actor AuditActor, creatorID, keyID string) error {
+	creatorOID, err := primitive.ObjectIDFromHex(creatorID)
+	if err != nil {
+		return fmt.Errorf("invalid creator id")
+	}
+	keyOID, err := primitive.ObjectIDFromHex(keyID)
+	if err != nil {
+		return ErrAPIKeyNotFound
+	}
+	revoked, err := s.repo.Revoke(ctx, creatorOID, keyOID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrAPIKeyNotFound
+	}
+
+	s.audit.Record(ctx, actor, domain.AuditAPIKeyRevoke, domain.AuditTargetAPIKey, keyID, nil, nil, "")
+	return nil
+}
+
+// Authenticate returns the live key matching secret and records its use.
+func (s *APIKeyService) Authenticate(ctx context.Context, secret, ip string) (*domain.APIKey, error) {
+	if !strings.HasPrefix(secret, APIKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+	key, err := s.repo.FindByHash(ctx, hashToken(secret))
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// Last-used only needs to be roughly right; don't write on every request
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval || key.LastUsedIP != ip {
+		if err := s.repo.TouchLastUsed(ctx, key.ID, now, ip); err != nil {
+			logger.Error("failed to record API key use", "key_id", key.ID.Hex(), "error", err)
+		}
+	}
+	return key, nil
+}
+
+// Allow counts a request against the key's per-minute limit. It reports whether the request
+// is allowed, how many requests remain and when the window resets.
+func (s *APIKeyService) Allow(ctx context.Context, key *domain.APIKey) (bool, int, time.Duration, error) {
+	limit := key.RateLimit
+	if limit <= 0 {
+		limit = defaultAPIKeyRateLimit
+	}
+
+	var count int
+	var reset time.Duration
+	if s.redis != nil {
+		windowStart := time.Now().Truncate(apiKeyRateWindow)
+		redisKey := fmt.Sprintf("api_key:rate:%s:%d", key.ID.Hex(), windowStart.Unix())
+		n, err := s.redis.Incr(ctx, redisKey).Result()
+		if err != nil {
+			return false, 0, 0, fmt.Errorf("redis incr: %w", err)
+		}
+		if n == 1 {
+			s.redis.Expire(ctx, redisKey, apiKeyRateWindow)
+		}
+		count = int(n)
+		reset = time.Until(windowStart.Add(apiKeyRateWindow))
+	} else {
+		s.mu.Lock()
+		now := time.Now()
+		w := s.windows[key.ID]
+		if w == nil || now.Sub(w.start) >= apiKeyRateWindow {
+			w = &apiKeyWindow{start: now}
+			s.windows[key.ID] = w
+		}
+		w.count++
+		count = w.count
+		reset = apiKeyRateWindow - now.Sub(w.start)
+		s.mu.Unlock()
+	}
+
+	remaining := limit - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	return count <= limit, remaining, reset, nil
+}
+
+// normaliseAPIScopes validates scopes and removes duplicates.
+func normaliseAPIScopes(scopes []string) ([]string, error) {
+	seen := make(map[string]bool, len(scopes))
+	out := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !domain.IsValidAPIScope(scope) {
+			return nil, ErrInvalidAPIScopes
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			out = append(out, scope)
+		}
+	}
+	if len(out) == 0 {
+		return nil, ErrInvalidAPIScopes
+	}
+	return out, nil
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+// AuditActor identifies who performed an audited action and from where.
+type AuditActor struct {
+	UserID    string
+	Role      string
+	IP        string
+	UserAgent string
+	RequestID string
+}
+
+// SystemActor is the actor for changes driven by webhooks and background jobs.
+var SystemActor = AuditActor{Role: domain.AuditActorSystem}
+
+// AuditService writes and queries the append-only audit log of admin and payout-affecting actions.
+// A nil *AuditService is valid and records nothing, so services can call it unconditionally.
+type AuditService struct {
+	repo     domain.AuditLogRepository
+	userRepo domain.UserRepository
+}
+
+// NewAuditService creates a new AuditService.
+func NewAuditService(repo domain.AuditLogRepository, userRepo domain.UserRepository) *AuditService {
+	return &AuditService{repo: repo, userRepo: userRepo}
+}
+
+// Record appends an entry for action on the target. before and after are snapshots of the
+// target (structs, maps or nil) and are diffed through their JSON form, so fields hidden
+// from JSON such as secrets and password hashes never reach the log. Failures are logged
+// rather than returned: the action has already happened and must not be reported as failed.
+func (s *AuditService) Record(ctx context.Context, actor AuditActor, action, targetType, targetID string, before, after interface{}, reason string) {
+	if s == nil {
+		return
+	}
+
+	entry := s.newEntry(ctx, actor, action, targetType, targetID)
+	entry.Reason = reason
+	changes, err := auditDiff(before, after)
+	if err != nil {
+		logger.Error("failed to diff audit snapshots", "action", action, "target_id", targetID, "error", err)
+	}
+	entry.Changes = changes
+	s.write(ctx, entry)
+}
+
+// RecordRequest appends an entry for an HTTP request made on the target's behalf, such as
+// one made while impersonating, with its method, path and response status.
+func (s *AuditService) RecordRequest(ctx context.Context, actor AuditActor, action, targetType, targetID, method, path string, status int) {
+	if s == nil {
+		return
+	}
+
+	entry := s.newEntry(ctx, actor, action, targetType, targetID)
+	entry.Request = method + " " + path
+	entry.Status = status
+	s.write(ctx, entry)
+}
+
+func (s *AuditService) newEntry(ctx context.Context, actor AuditActor, action, targetType, targetID string) *domain.AuditEntry {
+	entry := &domain.AuditEntry{
+		ActorRole:  actor.Role,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		IP:         actor.IP,
+		UserAgent:  actor.UserAgent,
+		RequestID:  actor.RequestID,
+	}
+	if oid, err := primitive.ObjectIDFromHex(actor.UserID); err == nil {
+		entry.ActorID = oid
+		if user, err := s.userRepo.FindByID(ctx, actor.UserID); err == nil && user != nil {
+			entry.ActorEmail = user.Email
+		}
+	}
+	return entry
+}
+
+func (s *AuditService) write(ctx context.Context, entry *domain.AuditEntry) {
+	if err := s.repo.Create(ctx, entry); err != nil {
+		logger.Error("failed to write audit entry", "action", entry.Action, "target_id", entry.TargetID, "error", err)
+	}
+}
+
+// List returns a page of audit entries, newest first.
+func (s *AuditService) List(ctx context.Context, filter domain.AuditFilter, pagination *domain.Pagination) ([]*domain.AuditEntry, *domain.PaginationMeta, error) {
+	return s.repo.Find(ctx, filter, pagination)
+}
+
+// Export calls fn for every matching entry, newest first.
+func (s *AuditService) Export(ctx context.Context, filter domain.AuditFilter, fn func(*domain.AuditEntry) error) error {
+	return s.repo.Iterate(ctx, filter, fn)
+}
+
+// auditDiff returns the fields that differ between before and after, sorted by dotted
+// JSON path. Nested objects are compared field by field; arrays are compared whole.
+func auditDiff(before, after interface{}) ([]domain.AuditChange, error) {
+	from, err := auditFlatten(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := auditFlatten(after)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []domain.AuditChange
+	for key, old := range from {
+		if cur, ok := to[key]; !ok || !reflect.DeepEqual(old, cur) {
+			changes = append(changes, domain.AuditChange{Field: key, From: old, To: to[key]})
+		}
+	}
+	for key, cur := range to {
+		if _, ok := from[key]; !ok {
+			changes = append(changes, domain.AuditChange{Field: key, To: cur})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes, nil
+}
+
+// auditFlatten turns a snapshot into a flat map of JSON paths to values.
+func auditFlatten(snapshot interface{}) (map[string]interface{}, error) {
+	flat := map[string]interface{}{}
+	if snapshot == nil || reflect.ValueOf(snapshot).Kind() == reflect.Ptr && reflect.ValueOf(snapshot).IsNil() {
+		return flat, nil
+	}
+
+	raw, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("marshal audit snapshot: %w", err)
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, fmt.Errorf("unmarshal audit snapshot: %w", err)
+	}
+
+	var walk func(prefix string, v interface{})
+	walk = func(prefix string, v interface{}) {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			flat[prefix] = v
+			return
+		}
+		for key, child := range obj {
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			walk(key, child)
+		}
+	}
+	walk("", value)
+	return flat, nil
+}
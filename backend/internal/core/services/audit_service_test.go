@@ -0,0 +1,127 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+)
+
+type auditTestPayout struct {
+	Account string `json:"account"`
+	IFSC    string `json:"ifsc"`
+}
+
+type auditTestUser struct {
+	Name     string           `json:"name"`
+	Status   string           `json:"status,omitempty"`
+	Tags     []string         `json:"tags,omitempty"`
+	Limit    int              `json:"limit"`
+	Payout   *auditTestPayout `json:"payout,omitempty"`
+	Password string           `json:"-"`
+}
+
+func TestAuditDiff(t *testing.T) {
+	base := auditTestUser{
+		Name:     "Priya",
+		Status:   "active",
+		Tags:     []string{"a", "b"},
+		Limit:    10,
+		Payout:   &auditTestPayout{Account: "1234", IFSC: "HDFC0001"},
+		Password: "hash-1",
+	}
+	with := func(change func(u *auditTestUser)) auditTestUser {
+		u := base
+		u.Payout = &auditTestPayout{Account: base.Payout.Account, IFSC: base.Payout.IFSC}
+		change(&u)
+		return u
+	}
+
+	tests := []struct {
+		name   string
+		before interface{}
+		after  interface{}
+		want   []domain.AuditChange
+	}{
+		{name: "both empty", before: nil, after: nil},
+		{name: "unchanged", before: base, after: with(func(u *auditTestUser) {})},
+		{
+			name:   "scalar change",
+			before: base,
+			after:  with(func(u *auditTestUser) { u.Status = "banned" }),
+			want:   []domain.AuditChange{{Field: "status", From: "active", To: "banned"}},
+		},
+		{
+			name:   "nested field uses a dotted path",
+			before: base,
+			after:  with(func(u *auditTestUser) { u.Payout.IFSC = "ICIC0002" }),
+			want:   []domain.AuditChange{{Field: "payout.ifsc", From: "HDFC0001", To: "ICIC0002"}},
+		},
+		{
+			name:   "arrays compare whole",
+			before: base,
+			after:  with(func(u *auditTestUser) { u.Tags = []string{"a", "c"} }),
+			want:   []domain.AuditChange{{Field: "tags", From: []interface{}{"a", "b"}, To: []interface{}{"a", "c"}}},
+		},
+		{
+			name:   "omitted field is a removal",
+			before: base,
+			after:  with(func(u *auditTestUser) { u.Status = "" }),
+			want:   []domain.AuditChange{{Field: "status", From: "active"}},
+		},
+		{
+			name:   "hidden fields are never recorded",
+			before: base,
+			after:  with(func(u *auditTestUser) { u.Password = "hash-2" }),
+		},
+		{
+			name:   "changes sorted by path",
+			before: base,
+			after: with(func(u *auditTestUser) {
+				u.Name = "Priya S"
+				u.Limit = 20
+				u.Payout = nil
+			}),
+			want: []domain.AuditChange{
+				{Field: "limit", From: float64(10), To: float64(20)},
+				{Field: "name", From: "Priya", To: "Priya S"},
+				{Field: "payout.account", From: "1234"},
+				{Field: "payout.ifsc", From: "HDFC0001"},
+			},
+		},
+		{
+			name:   "created",
+			before: (*auditTestPayout)(nil),
+			after:  &auditTestPayout{Account: "1234", IFSC: "HDFC0001"},
+			want: []domain.AuditChange{
+				{Field: "account", To: "1234"},
+				{Field: "ifsc", To: "HDFC0001"},
+			},
+		},
+		{
+			name:   "deleted",
+			before: map[string]interface{}{"limit": 5},
+			after:  nil,
+			want:   []domain.AuditChange{{Field: "limit", From: float64(5)}},
+		},
+		{
+			name:   "numbers compare by value",
+			before: map[string]interface{}{"limit": 5},
+			after:  map[string]interface{}{"limit": 5.0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := auditDiff(tt.before, tt.after)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAuditDiff_UnmarshalableSnapshot(t *testing.T) {
+	_, err := auditDiff(map[string]interface{}{"ch": make(chan int)}, nil)
+	assert.Error(t, err)
+}
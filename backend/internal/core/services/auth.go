@@ -3,9 +3,15 @@ package services
 import (
 	"context"
 	"crypto/rand"
+	"crypto/subtle"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
 	"math/big"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,6 +21,26 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	ErrIncorrectPassword         = errors.New("incorrect password")
+	ErrStepUpCredentialsRequired = errors.New("a verification code or password is required")
+	ErrTwoFactorUnavailable      = errors.New("two-factor sign-in is temporarily unavailable")
+	ErrMagicLinkInvalid          = errors.New("magic link is invalid or has expired")
+	ErrMagicLinkWrongBrowser     = errors.New("magic link must be opened in the browser it was requested from")
+	ErrMagicLinkRateLimited      = errors.New("too many sign-in links requested, please try again later")
+	ErrTooManyOTPAttempts        = errors.New("too many incorrect codes, please try again in 15 minutes")
+)
+
+const (
+	magicLinkTTL         = 15 * time.Minute
+	magicLinkEmailLimit  = 3 // Links per email address per window
+	magicLinkEmailWindow = 15 * time.Minute
+	magicLinkIPLimit     = 10 // Links per client IP per window
+	magicLinkIPWindow    = time.Hour
+	maxOTPAttempts       = 5 // Wrong signup OTPs before the email is locked out
+	otpLockout           = 15 * time.Minute
+)
+
 // GoogleUser holds user info from Google OAuth.
 type GoogleUser struct {
 	ID      string `json:"id"`
@@ -25,10 +51,18 @@ type GoogleUser struct {
 
 // AuthResult holds the result of authentication.
 type AuthResult struct {
-	User        *domain.User `json:"user"`
-	Token       string       `json:"token"`
-	RedirectURL string       `json:"redirectUrl"`
-	IsNewUser   bool         `json:"isNewUser"`
+	User         *domain.User `json:"user"`
+	Token        string       `json:"token"`
+	RefreshToken string       `json:"-"` // Cookie only unless the client asks; empty when sessions are disabled
+	RedirectURL  string       `json:"redirectUrl"`
+	IsNewUser    bool         `json:"isNewUser"`
+
+	// Set instead of tokens when the password or Google step passed but a second factor is
+	// still needed. The login is finished through /auth/2fa/verify with ChallengeToken.
+	TwoFactorRequired      bool     `json:"twoFactorRequired,omitempty"`
+	TwoFactorSetupRequired bool     `json:"twoFactorSetupRequired,omitempty"` // Admins must enrol first
+	ChallengeToken         string   `json:"challengeToken,omitempty"`
+	RecoveryCodes          []string `json:"recoveryCodes,omitempty"` // Only when enrolment completes during login
 }
 
 // AuthService handles authentication business logic.
@@ -37,6 +71,11 @@ type AuthService struct {
 	jwtService   *JWTService
 	redis        *redis.Client
 	emailService domain.EmailService
+	sessions     *SessionService
+	twoFactor    *TwoFactorService
+
+	emailTemplates *EmailTemplateService
+	frontendURL    string
 }
 
 // NewAuthService creates a new AuthService.
@@ -49,9 +88,90 @@ func NewAuthService(userRepo domain.UserRepository, jwtService *JWTService, redi
 	}
 }
 
+// SetSessionService enables refresh-token sessions. Without it (e.g. when Redis is down),
+// logins issue the long-lived tokens used before sessions existed.
+func (s *AuthService) SetSessionService(sessions *SessionService) {
+	s.sessions = sessions
+}
+
+// SetFrontendURL sets the public base URL used to build links in auth emails.
+func (s *AuthService) SetFrontendURL(frontendURL string) {
+	s.frontendURL = strings.TrimRight(frontendURL, "/")
+}
+
+// SetEmailTemplateService lets admins customise auth emails. Without it the built-in templates
+// are used.
+func (s *AuthService) SetEmailTemplateService(templates *EmailTemplateService) {
+	s.emailTemplates = templates
+}
+
+// SetTwoFactorService enables TOTP two-factor authentication and step-up re-authentication.
+func (s *AuthService) SetTwoFactorService(twoFactor *TwoFactorService) {
+	s.twoFactor = twoFactor
+}
+
+// signIn completes a login whose first factor has been checked: it issues tokens, or a
+// two-factor challenge if the account needs one.
+func (s *AuthService) signIn(ctx context.Context, user *domain.User, client ClientInfo, redirectURL string, isNewUser bool) (*AuthResult, error) {
+	if user.RequiresTwoFactor() {
+		if s.twoFactor == nil {
+			// Fail closed rather than skip the second factor
+			return nil, ErrTwoFactorUnavailable
+		}
+		challenge, err := s.twoFactor.CreateLoginChallenge(ctx, user.ID.Hex())
+		if err != nil {
+			return nil, err
+		}
+		return &AuthResult{
+			RedirectURL:            redirectURL,
+			IsNewUser:              isNewUser,
+			TwoFactorRequired:      true,
+			TwoFactorSetupRequired: !user.HasTwoFactor(),
+			ChallengeToken:         challenge,
+		}, nil
+	}
+
+	token, refreshToken, err := s.issueTokens(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthResult{
+		User:         user,
+		Token:        token,
+		RefreshToken: refreshToken,
+		RedirectURL:  redirectURL,
+		IsNewUser:    isNewUser,
+	}, nil
+}
+
+// issueTokens signs user in on the requesting device, returning an access token and, when
+// sessions are enabled, the session's refresh token.
+func (s *AuthService) issueTokens(ctx context.Context, user *domain.User, client ClientInfo) (string, string, error) {
+	if s.sessions != nil {
+		tokens, err := s.sessions.Create(ctx, user, client)
+		if err != nil {
+			return "", "", err
+		}
+		return tokens.AccessToken, tokens.RefreshToken, nil
+	}
+
+	// Buyers get 24h expiry, creators get 7 days
+	var token string
+	var err error
+	if user.Role == domain.RoleBuyer {
+		token, err = s.jwtService.GenerateTokenWithExpiry(user.ID.Hex(), user.Role, BuyerJWTExpiry)
+	} else {
+		token, err = s.jwtService.GenerateToken(user.ID.Hex(), user.Role)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("generate token: %w", err)
+	}
+	return token, "", nil
+}
+
 // HandleGoogleCallback processes the Google OAuth callback.
 // It finds or creates a user, generates a JWT, and determines the redirect URL.
-func (s *AuthService) HandleGoogleCallback(ctx context.Context, gUser *GoogleUser, requestedRole string) (*AuthResult, error) {
+func (s *AuthService) HandleGoogleCallback(ctx context.Context, gUser *GoogleUser, requestedRole string, client ClientInfo) (*AuthResult, error) {
 	email := strings.ToLower(gUser.Email)
 
 	// Check if user exists by Google ID first, then by email
@@ -84,15 +204,8 @@ func (s *AuthService) HandleGoogleCallback(ctx context.Context, gUser *GoogleUse
 		logger.Info("new user created via google oauth", "email", email, "role", user.Role)
 	}
 
-	// Generate JWT (buyers get 24h expiry, creators get 7 days)
-	var token string
-	if user.Role == domain.RoleBuyer {
-		token, err = s.jwtService.GenerateTokenWithExpiry(user.ID.Hex(), user.Role, BuyerJWTExpiry)
-	} else {
-		token, err = s.jwtService.GenerateToken(user.ID.Hex(), user.Role)
-	}
-	if err != nil {
-		return nil, fmt.Errorf("generate token: %w", err)
+	if user.Status == domain.UserStatusBanned {
+		return nil, fmt.Errorf("account has been suspended")
 	}
 
 	// Determine redirect
@@ -103,12 +216,7 @@ func (s *AuthService) HandleGoogleCallback(ctx context.Context, gUser *GoogleUse
 		redirectURL = "/dashboard"
 	}
 
-	return &AuthResult{
-		User:        user,
-		Token:       token,
-		RedirectURL: redirectURL,
-		IsNewUser:   isNewUser,
-	}, nil
+	return s.signIn(ctx, user, client, redirectURL, isNewUser)
 }
 
 // GetCurrentUser retrieves the current user by their ID.
@@ -130,45 +238,110 @@ func generateMagicLinkToken() string {
 	return base64.URLEncoding.EncodeToString(b)
 }
 
-// HandleMagicLinkRequest generates and stores a magic link token for the buyer.
-func (s *AuthService) HandleMagicLinkRequest(ctx context.Context, email string) error {
+// magicLinkEntry is what a magic link token points to in Redis.
+type magicLinkEntry struct {
+	Email     string `json:"email"`
+	NonceHash string `json:"nonce_hash"` // SHA-256 of the nonce cookie given to the requesting browser
+}
+
+// HandleMagicLinkRequest emails a sign-in link to the buyer. It returns a nonce that the caller
+// must hand to the requesting browser; the link only works when the same nonce is presented.
+func (s *AuthService) HandleMagicLinkRequest(ctx context.Context, email, ip string) (string, error) {
 	email = strings.ToLower(strings.TrimSpace(email))
 	if email == "" {
-		return fmt.Errorf("email is required")
+		return "", fmt.Errorf("email is required")
+	}
+
+	if ok, err := s.allowAttempt(ctx, "auth:magic_link_rate:email:"+email, magicLinkEmailLimit, magicLinkEmailWindow); err != nil {
+		return "", err
+	} else if !ok {
+		return "", ErrMagicLinkRateLimited
+	}
+	if ip != "" {
+		if ok, err := s.allowAttempt(ctx, "auth:magic_link_rate:ip:"+ip, magicLinkIPLimit, magicLinkIPWindow); err != nil {
+			return "", err
+		} else if !ok {
+			return "", ErrMagicLinkRateLimited
+		}
 	}
 
 	token := generateMagicLinkToken()
+	nonce := generateMagicLinkToken()
+	entry, _ := json.Marshal(magicLinkEntry{Email: email, NonceHash: hashToken(nonce)})
 	key := fmt.Sprintf("auth:magic_link:%s", token)
 
-	// Store in Redis for 15 minutes
-	err := s.redis.Set(ctx, key, email, 15*time.Minute).Err()
-	if err != nil {
-		return fmt.Errorf("redis set: %w", err)
+	if err := s.redis.Set(ctx, key, entry, magicLinkTTL).Err(); err != nil {
+		return "", fmt.Errorf("redis set: %w", err)
 	}
 
-	// In a real system, we'd fire an event or call an email service here.
-	// For now, log the token to the console so we can "click" it manually in dev.
-	logger.Info("[MOCK EMAIL] MAGIC LINK GENERATED", "email", email, "token", token, "url", fmt.Sprintf("http://localhost:5173/verify?token=%s", token))
+	loginURL := fmt.Sprintf("%s/api/v1/auth/buyer/verify?token=%s", s.frontendURL, url.QueryEscape(token))
+	if s.emailService == nil {
+		// No mail provider configured (local dev): log the link so it can be opened by hand.
+		logger.Info("magic link generated (email disabled)", "email", email, "url", loginURL)
+		return nonce, nil
+	}
 
-	return nil
+	subject, body := s.magicLinkEmail(ctx, loginURL)
+	if err := s.emailService.Send(ctx, email, subject, body); err != nil {
+		s.redis.Del(ctx, key)
+		return "", fmt.Errorf("send magic link: %w", err)
+	}
+	return nonce, nil
+}
+
+// magicLinkEmail renders the buyer magic link template, falling back to the built-in default
+// when the template service is missing or the stored template can't be loaded.
+func (s *AuthService) magicLinkEmail(ctx context.Context, loginURL string) (string, string) {
+	template := defaultPlatformTemplates[domain.TemplateTypeBuyerMagicLink]
+	if s.emailTemplates != nil {
+		stored, err := s.emailTemplates.GetPlatformTemplate(ctx, string(domain.TemplateTypeBuyerMagicLink))
+		if err != nil {
+			logger.Error("failed to load magic link template, using default", "error", err)
+		} else {
+			template = *stored
+		}
+	}
+
+	expires := strconv.Itoa(int(magicLinkTTL.Minutes()))
+	subject := strings.ReplaceAll(template.Subject, "{expires_minutes}", expires)
+	body := strings.NewReplacer(
+		"{login_url}", html.EscapeString(loginURL),
+		"{expires_minutes}", expires,
+	).Replace(template.BodyHTML)
+	return subject, body
 }
 
-// HandleMagicLinkVerify consumes the token, creates a buyer if needed, and logs them in.
-func (s *AuthService) HandleMagicLinkVerify(ctx context.Context, token string) (*AuthResult, error) {
+// HandleMagicLinkVerify consumes the token, creates a buyer if needed, and logs them in. The
+// nonce must match the one issued to the browser that requested the link; on a mismatch the
+// token is left in place so the link still works from the right browser.
+func (s *AuthService) HandleMagicLinkVerify(ctx context.Context, token, nonce string, client ClientInfo) (*AuthResult, error) {
 	key := fmt.Sprintf("auth:magic_link:%s", token)
 
-	email, err := s.redis.Get(ctx, key).Result()
+	raw, err := s.redis.Get(ctx, key).Result()
 	if err == redis.Nil {
-		return nil, fmt.Errorf("token invalid or expired")
+		return nil, ErrMagicLinkInvalid
 	} else if err != nil {
 		return nil, fmt.Errorf("redis get: %w", err)
 	}
 
-	// Immediately delete the token so it can't be reused
-	if err := s.redis.Del(ctx, key).Err(); err != nil {
-		logger.Error("failed to delete used magic link token", "error", err, "token", token)
+	var entry magicLinkEntry
+	if err := json.Unmarshal([]byte(raw), &entry); err != nil || entry.Email == "" {
+		return nil, ErrMagicLinkInvalid
+	}
+	if nonce == "" || subtle.ConstantTimeCompare([]byte(hashToken(nonce)), []byte(entry.NonceHash)) != 1 {
+		return nil, ErrMagicLinkWrongBrowser
 	}
 
+	// Delete the token so it can't be reused. Only the caller that actually removed it continues.
+	deleted, err := s.redis.Del(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis del: %w", err)
+	}
+	if deleted == 0 {
+		return nil, ErrMagicLinkInvalid
+	}
+	email := entry.Email
+
 	// Find or Create user as Buyer
 	isNewUser := false
 	user, err := s.userRepo.FindByEmail(ctx, email)
@@ -195,18 +368,24 @@ func (s *AuthService) HandleMagicLinkVerify(ctx context.Context, token string) (
 		logger.Info("new buyer created via magic link", "email", email)
 	}
 
-	// Generate JWT (buyers get 24h expiry)
-	jwtToken, err := s.jwtService.GenerateTokenWithExpiry(user.ID.Hex(), user.Role, BuyerJWTExpiry)
-	if err != nil {
-		return nil, fmt.Errorf("generate token: %w", err)
+	if user.Status == domain.UserStatusBanned {
+		return nil, fmt.Errorf("account has been suspended")
 	}
 
-	return &AuthResult{
-		User:        user,
-		Token:       jwtToken,
-		RedirectURL: "/my-purchases",
-		IsNewUser:   isNewUser,
-	}, nil
+	return s.signIn(ctx, user, client, "/my-purchases", isNewUser)
+}
+
+// allowAttempt counts an attempt against key and reports whether it is within limit for the
+// current window. The window starts with the first attempt.
+func (s *AuthService) allowAttempt(ctx context.Context, key string, limit int64, window time.Duration) (bool, error) {
+	count, err := s.redis.Incr(ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("redis incr: %w", err)
+	}
+	if count == 1 {
+		s.redis.Expire(ctx, key, window)
+	}
+	return count <= limit, nil
 }
 
 // generateOTP generates a 6-digit numeric OTP.
@@ -221,8 +400,12 @@ func (s *AuthService) HandleCreatorSignup(ctx context.Context, email, password s
 	if email == "" || password == "" {
 		return fmt.Errorf("email and password are required")
 	}
-	if len(password) < 8 {
-		return fmt.Errorf("password must be at least 8 characters")
+	if err := validatePassword(password, email); err != nil {
+		return err
+	}
+
+	if err := s.checkOTPLock(ctx, email); err != nil {
+		return err
 	}
 
 	// Check if user already exists
@@ -247,7 +430,39 @@ func (s *AuthService) HandleCreatorSignup(ctx context.Context, email, password s
 		return fmt.Errorf("redis set: %w", err)
 	}
 
-	// Generate and store OTP
+	return s.sendSignupOTP(ctx, email)
+}
+
+// HandleCreatorResendOTP sends a fresh verification code for a signup that is still pending.
+func (s *AuthService) HandleCreatorResendOTP(ctx context.Context, email string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if err := s.checkOTPLock(ctx, email); err != nil {
+		return err
+	}
+
+	pending, err := s.redis.Exists(ctx, fmt.Sprintf("auth:signup:%s", email)).Result()
+	if err != nil {
+		return fmt.Errorf("redis exists: %w", err)
+	}
+	if pending == 0 {
+		return fmt.Errorf("signup data expired, please sign up again")
+	}
+	return s.sendSignupOTP(ctx, email)
+}
+
+func (s *AuthService) checkOTPLock(ctx context.Context, email string) error {
+	locked, err := s.redis.Exists(ctx, fmt.Sprintf("auth:otp_lock:%s", email)).Result()
+	if err != nil {
+		return fmt.Errorf("redis exists: %w", err)
+	}
+	if locked > 0 {
+		return ErrTooManyOTPAttempts
+	}
+	return nil
+}
+
+// sendSignupOTP generates, stores and emails the signup verification code.
+func (s *AuthService) sendSignupOTP(ctx context.Context, email string) error {
 	otp := generateOTP()
 	otpKey := fmt.Sprintf("auth:otp:%s", email)
 	if err := s.redis.Set(ctx, otpKey, otp, 5*time.Minute).Err(); err != nil {
@@ -278,7 +493,7 @@ func (s *AuthService) HandleCreatorSignup(ctx context.Context, email, password s
 }
 
 // HandleCreatorVerifyOTP verifies the OTP and creates the creator account.
-func (s *AuthService) HandleCreatorVerifyOTP(ctx context.Context, email, otp string) (*AuthResult, error) {
+func (s *AuthService) HandleCreatorVerifyOTP(ctx context.Context, email, otp string, client ClientInfo) (*AuthResult, error) {
 	email = strings.ToLower(strings.TrimSpace(email))
 
 	// Verify OTP
@@ -290,12 +505,22 @@ func (s *AuthService) HandleCreatorVerifyOTP(ctx context.Context, email, otp str
 		return nil, fmt.Errorf("redis get: %w", err)
 	}
 
-	if storedOTP != otp {
+	if subtle.ConstantTimeCompare([]byte(storedOTP), []byte(strings.TrimSpace(otp))) != 1 {
+		attemptsKey := fmt.Sprintf("auth:otp_attempts:%s", email)
+		if ok, err := s.allowAttempt(ctx, attemptsKey, maxOTPAttempts, otpLockout); err != nil {
+			return nil, err
+		} else if !ok {
+			// Burn the code and the pending signup; the user has to start over once the lock expires.
+			s.redis.Del(ctx, otpKey, fmt.Sprintf("auth:signup:%s", email), attemptsKey)
+			s.redis.Set(ctx, fmt.Sprintf("auth:otp_lock:%s", email), 1, otpLockout)
+			logger.Warn("OTP verification locked after too many attempts", "email", email)
+			return nil, ErrTooManyOTPAttempts
+		}
 		return nil, fmt.Errorf("incorrect OTP")
 	}
 
 	// Delete OTP
-	s.redis.Del(ctx, otpKey)
+	s.redis.Del(ctx, otpKey, fmt.Sprintf("auth:otp_attempts:%s", email))
 
 	// Get stored password hash
 	signupKey := fmt.Sprintf("auth:signup:%s", email)
@@ -319,15 +544,11 @@ func (s *AuthService) HandleCreatorVerifyOTP(ctx context.Context, email, otp str
 		if err != nil {
 			return nil, fmt.Errorf("update user: %w", err)
 		}
-		token, err := s.jwtService.GenerateToken(updated.ID.Hex(), updated.Role)
-		if err != nil {
-			return nil, fmt.Errorf("generate token: %w", err)
-		}
 		redirectURL := "/onboarding"
 		if updated.HasUsername() {
 			redirectURL = "/dashboard"
 		}
-		return &AuthResult{User: updated, Token: token, RedirectURL: redirectURL, IsNewUser: false}, nil
+		return s.signIn(ctx, updated, client, redirectURL, false)
 	}
 
 	// Create new creator
@@ -353,23 +574,13 @@ func (s *AuthService) HandleCreatorVerifyOTP(ctx context.Context, email, otp str
 		return nil, fmt.Errorf("create user: %w", err)
 	}
 
-	token, err := s.jwtService.GenerateToken(user.ID.Hex(), user.Role)
-	if err != nil {
-		return nil, fmt.Errorf("generate token: %w", err)
-	}
-
 	logger.Info("new creator created via email/password", "email", email)
 
-	return &AuthResult{
-		User:        user,
-		Token:       token,
-		RedirectURL: "/onboarding",
-		IsNewUser:   true,
-	}, nil
+	return s.signIn(ctx, user, client, "/onboarding", true)
 }
 
 // HandleCreatorLogin authenticates a creator with email and password.
-func (s *AuthService) HandleCreatorLogin(ctx context.Context, email, password string) (*AuthResult, error) {
+func (s *AuthService) HandleCreatorLogin(ctx context.Context, email, password string, client ClientInfo) (*AuthResult, error) {
 	email = strings.ToLower(strings.TrimSpace(email))
 	if email == "" || password == "" {
 		return nil, fmt.Errorf("email and password are required")
@@ -395,20 +606,231 @@ func (s *AuthService) HandleCreatorLogin(ctx context.Context, email, password st
 		return nil, fmt.Errorf("account has been suspended")
 	}
 
-	token, err := s.jwtService.GenerateToken(user.ID.Hex(), user.Role)
-	if err != nil {
-		return nil, fmt.Errorf("generate token: %w", err)
-	}
-
 	redirectURL := "/onboarding"
 	if user.HasUsername() {
 		redirectURL = "/dashboard"
 	}
 
+	return s.signIn(ctx, user, client, redirectURL, false)
+}
+
+// loginRedirect is where a user lands after signing in.
+func loginRedirect(user *domain.User) string {
+	if user.Role == domain.RoleBuyer {
+		return "/my-purchases"
+	}
+	if user.HasUsername() {
+		return "/dashboard"
+	}
+	return "/onboarding"
+}
+
+// BeginChallengeTwoFactorSetup starts TOTP enrolment for an account that must have 2FA
+// (admins) but does not yet, using the login challenge in place of a session.
+func (s *AuthService) BeginChallengeTwoFactorSetup(ctx context.Context, challenge string) (*TwoFactorSetup, error) {
+	if s.twoFactor == nil {
+		return nil, ErrInvalidTwoFactorLogin
+	}
+	user, err := s.challengeUser(ctx, challenge)
+	if err != nil {
+		return nil, err
+	}
+	return s.twoFactor.BeginSetup(ctx, user)
+}
+
+// CompleteTwoFactorLogin finishes a challenged login with a TOTP or recovery code. Accounts
+// enrolling during login confirm their new secret with the code and get recovery codes back.
+func (s *AuthService) CompleteTwoFactorLogin(ctx context.Context, challenge, code string, client ClientInfo) (*AuthResult, error) {
+	if s.twoFactor == nil {
+		return nil, ErrInvalidTwoFactorLogin
+	}
+	user, err := s.challengeUser(ctx, challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	var recoveryCodes []string
+	if user.HasTwoFactor() {
+		err = s.twoFactor.Verify(ctx, user, code)
+	} else {
+		recoveryCodes, err = s.twoFactor.Enable(ctx, user, code)
+	}
+	if errors.Is(err, ErrInvalidTwoFactorCode) {
+		if failErr := s.twoFactor.FailLoginChallenge(ctx, challenge); failErr != nil {
+			return nil, failErr
+		}
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+	s.twoFactor.CompleteLoginChallenge(ctx, challenge)
+
+	token, refreshToken, err := s.issueTokens(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
 	return &AuthResult{
-		User:        user,
-		Token:       token,
-		RedirectURL: redirectURL,
-		IsNewUser:   false,
+		User:          user,
+		Token:         token,
+		RefreshToken:  refreshToken,
+		RedirectURL:   loginRedirect(user),
+		RecoveryCodes: recoveryCodes,
 	}, nil
 }
+
+func (s *AuthService) challengeUser(ctx context.Context, challenge string) (*domain.User, error) {
+	userID, err := s.twoFactor.LoginChallengeUser(ctx, challenge)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("find user: %w", err)
+	}
+	if user == nil {
+		return nil, ErrInvalidTwoFactorLogin
+	}
+	if user.Status == domain.UserStatusBanned {
+		return nil, fmt.Errorf("account has been suspended")
+	}
+	return user, nil
+}
+
+// StepUp re-authenticates the user before a sensitive action, such as changing payout
+// details or withdrawing funds. Accounts with 2FA confirm with a TOTP or recovery code,
+// password accounts with their password, and Google-only accounts with a code emailed by
+// RequestStepUpCode. key identifies what the confirmation applies to (the session).
+func (s *AuthService) StepUp(ctx context.Context, userID, key, code, password string) error {
+	if s.twoFactor == nil {
+		return nil
+	}
+	user, err := s.GetCurrentUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case user.HasTwoFactor():
+		if code == "" {
+			return ErrStepUpCredentialsRequired
+		}
+		if err := s.twoFactor.Verify(ctx, user, code); err != nil {
+			return err
+		}
+	case user.PasswordHash != "" && password != "":
+		if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+			return ErrIncorrectPassword
+		}
+	case code != "":
+		// Single attempt per emailed code: a wrong guess burns it
+		stored, err := s.redis.GetDel(ctx, stepUpOTPKey(userID)).Result()
+		if err == redis.Nil || (err == nil && stored != code) {
+			return ErrInvalidTwoFactorCode
+		} else if err != nil {
+			return fmt.Errorf("redis get: %w", err)
+		}
+	default:
+		return ErrStepUpCredentialsRequired
+	}
+
+	return s.twoFactor.MarkStepUp(ctx, key)
+}
+
+// RequestStepUpCode emails a one-time code for step-up re-authentication, for accounts that
+// have neither 2FA nor a password.
+func (s *AuthService) RequestStepUpCode(ctx context.Context, userID string) error {
+	user, err := s.GetCurrentUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	otp := generateOTP()
+	if err := s.redis.Set(ctx, stepUpOTPKey(userID), otp, 5*time.Minute).Err(); err != nil {
+		return fmt.Errorf("redis set: %w", err)
+	}
+
+	body := fmt.Sprintf(
+		"<div style='font-family:sans-serif;max-width:480px;margin:0 auto;padding:32px;'>"+
+			"<h2 style='color:#6C5CE7;'>Confirm it's you</h2>"+
+			"<p>Use this code to confirm a change to your payout settings or a withdrawal:</p>"+
+			"<div style='font-size:32px;font-weight:bold;letter-spacing:8px;color:#1a1a2e;text-align:center;padding:20px;background:#f5f3ff;border-radius:12px;margin:16px 0;'>%s</div>"+
+			"<p style='color:#888;font-size:14px;'>This code expires in 5 minutes. If you didn't request this, secure your account.</p>"+
+			"</div>",
+		otp,
+	)
+	if s.emailService != nil {
+		if err := s.emailService.Send(ctx, user.Email, "Your Mio Store confirmation code", body); err != nil {
+			return fmt.Errorf("send email: %w", err)
+		}
+	}
+	return nil
+}
+
+// HasStepUp reports whether key re-authenticated recently. Without 2FA support (no Redis)
+// step-up is not enforced.
+func (s *AuthService) HasStepUp(ctx context.Context, key string) (bool, error) {
+	if s.twoFactor == nil {
+		return true, nil
+	}
+	return s.twoFactor.HasStepUp(ctx, key)
+}
+
+func stepUpOTPKey(userID string) string {
+	return fmt.Sprintf("auth:step_up_otp:%s", userID)
+}
+
+// RefreshSession rotates a refresh token into a new token pair.
+func (s *AuthService) RefreshSession(ctx context.Context, refreshToken string, client ClientInfo) (*AuthResult, error) {
+	if s.sessions == nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	tokens, user, err := s.sessions.Refresh(ctx, refreshToken, client)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthResult{User: user, Token: tokens.AccessToken, RefreshToken: tokens.RefreshToken}, nil
+}
+
+// Logout ends the session identified by the access token's session ID or, if the access
+// token has already expired, by the refresh token.
+func (s *AuthService) Logout(ctx context.Context, accessToken, refreshToken string) error {
+	if s.sessions == nil {
+		return nil
+	}
+	if claims, err := s.jwtService.ValidateToken(accessToken); err == nil && claims.SessionID != "" {
+		err := s.sessions.Revoke(ctx, claims.UserID, claims.SessionID)
+		if err == nil || !errors.Is(err, ErrSessionNotFound) {
+			return err
+		}
+	}
+	if refreshToken != "" {
+		if err := s.sessions.RevokeByRefreshToken(ctx, refreshToken); err != nil && !errors.Is(err, ErrInvalidRefreshToken) {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListSessions returns the user's signed-in devices.
+func (s *AuthService) ListSessions(ctx context.Context, userID, currentSessionID string) ([]domain.Session, error) {
+	if s.sessions == nil {
+		return []domain.Session{}, nil
+	}
+	return s.sessions.List(ctx, userID, currentSessionID)
+}
+
+// RevokeSession signs the user out of one device.
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	if s.sessions == nil {
+		return ErrSessionNotFound
+	}
+	return s.sessions.Revoke(ctx, userID, sessionID)
+}
+
+// RevokeAllSessions signs the user out everywhere.
+func (s *AuthService) RevokeAllSessions(ctx context.Context, userID string) (int, error) {
+	if s.sessions == nil {
+		return 0, nil
+	}
+	return s.sessions.RevokeAll(ctx, userID)
+}
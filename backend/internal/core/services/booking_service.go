@@ -3,21 +3,43 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
+	"strings"
 	"time"
 
+	"github.com/hibiken/asynq"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/devanshbhargava/stan-store/internal/core/domain"
 	"github.com/devanshbhargava/stan-store/pkg/logger"
 )
 
+// bookingReminderLeadTimes lists how long before SlotStart reminder emails go out.
+var bookingReminderLeadTimes = []struct {
+	Offset time.Duration
+	Label  string
+}{
+	{Offset: 24 * time.Hour, Label: "24 hours"},
+	{Offset: 1 * time.Hour, Label: "1 hour"},
+}
+
+// BookingCompletionHook runs after a booking transitions to completed.
+// Hooks are best-effort: errors are logged and do not roll back the transition.
+type BookingCompletionHook func(ctx context.Context, booking *domain.Booking, product *domain.Product) error
+
 // BookingService handles the business logic for bookings and availability.
 type BookingService struct {
-	bookingRepo  domain.BookingRepository
-	productRepo  domain.ProductRepository
-	cache        domain.Cache
-	googleCalSvc *GoogleCalendarService
+	bookingRepo     domain.BookingRepository
+	productRepo     domain.ProductRepository
+	cache           domain.Cache
+	googleCalSvc    *GoogleCalendarService
+	userRepo        domain.UserRepository
+	emailSvc        domain.EmailService
+	workerClient    *asynq.Client
+	completionHooks []BookingCompletionHook
+	webhooks        *CreatorWebhookService
 }
 
 // NewBookingService creates a new BookingService.
@@ -34,12 +56,54 @@ func (s *BookingService) SetGoogleCalendarService(svc *GoogleCalendarService) {
 	s.googleCalSvc = svc
 }
 
+// SetNotificationDeps injects the dependencies needed to email buyers and creators about their sessions.
+func (s *BookingService) SetNotificationDeps(userRepo domain.UserRepository, emailSvc domain.EmailService) {
+	s.userRepo = userRepo
+	s.emailSvc = emailSvc
+}
+
+// SetWorkerClient attaches the enqueue bus used to schedule reminders and completion.
+func (s *BookingService) SetWorkerClient(client *asynq.Client) {
+	s.workerClient = client
+}
+
+// SetWebhookService sends booking.created events to creators' webhooks.
+func (s *BookingService) SetWebhookService(webhooks *CreatorWebhookService) {
+	s.webhooks = webhooks
+}
+
+// AddCompletionHook registers a hook that runs whenever a booking is completed.
+func (s *BookingService) AddCompletionHook(hook BookingCompletionHook) {
+	s.completionHooks = append(s.completionHooks, hook)
+}
+
+// slotCacheKey returns the cache key holding a product's slot availability for one day.
+func slotCacheKey(productID primitive.ObjectID, dateStr string) string {
+	return fmt.Sprintf("cache:slots:%s:%s", productID.Hex(), dateStr)
+}
+
 // GetAvailableSlots returns available time slots in UTC for a specific date (YYYY-MM-DD).
+// A slot is available while it still has at least one open seat.
 func (s *BookingService) GetAvailableSlots(ctx context.Context, productID primitive.ObjectID, targetDateStr string) ([]time.Time, error) {
-	cacheKey := fmt.Sprintf("cache:slots:%s:%s", productID.Hex(), targetDateStr)
+	availability, err := s.GetSlotAvailability(ctx, productID, targetDateStr)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := make([]time.Time, 0, len(availability))
+	for _, a := range availability {
+		slots = append(slots, a.Start)
+	}
+	return slots, nil
+}
+
+// GetSlotAvailability returns the open slots for a specific date (YYYY-MM-DD) together with
+// their seat capacity and the number of seats still remaining. Full slots are omitted.
+func (s *BookingService) GetSlotAvailability(ctx context.Context, productID primitive.ObjectID, targetDateStr string) ([]domain.SlotAvailability, error) {
+	cacheKey := slotCacheKey(productID, targetDateStr)
 	if s.cache != nil {
 		if cached, err := s.cache.Get(ctx, cacheKey); err == nil && cached != "" {
-			var slots []time.Time
+			var slots []domain.SlotAvailability
 			if err := json.Unmarshal([]byte(cached), &slots); err == nil {
 				return slots, nil
 			}
@@ -60,7 +124,7 @@ func (s *BookingService) GetAvailableSlots(ctx context.Context, productID primit
 	}
 
 	if len(product.Availability) == 0 {
-		return []time.Time{}, nil // No availability set
+		return []domain.SlotAvailability{}, nil // No availability set
 	}
 
 	durationMins := product.DurationMinutes
@@ -124,10 +188,10 @@ func (s *BookingService) GetAvailableSlots(ctx context.Context, productID primit
 
 	// If no slots generated, short circuit
 	if len(availableSlots) == 0 {
-		return []time.Time{}, nil
+		return []domain.SlotAvailability{}, nil
 	}
 
-	// Fetch existing bookings to filter out taken slots
+	// Fetch existing bookings to count taken seats
 	// We load bookings from Start of Day to End of Day
 	sod := targetDate.UTC()
 	eod := targetDate.Add(24 * time.Hour).UTC()
@@ -137,21 +201,25 @@ func (s *BookingService) GetAvailableSlots(ctx context.Context, productID primit
 		return nil, fmt.Errorf("failed to fetch existing bookings: %w", err)
 	}
 
-	// Map taken times for O(1) lookup
-	takenSlots := make(map[int64]bool)
+	// Count booked seats per slot start for O(1) lookup
+	takenSeats := make(map[int64]int)
 	for _, b := range bookings {
-		// A slot is taken if its exact start time is already booked
-		takenSlots[b.SlotStart.UTC().Unix()] = true
+		takenSeats[b.SlotStart.UTC().Unix()]++
 	}
 
-	var filteredSlots []time.Time
+	capacity := product.SlotCapacity()
+	filteredSlots := []domain.SlotAvailability{}
 	for _, slot := range availableSlots {
-		if !takenSlots[slot.Unix()] {
-			// Also ensure we don't return slots in the past
-			if slot.After(time.Now().UTC()) {
-				filteredSlots = append(filteredSlots, slot)
-			}
+		remaining := capacity - takenSeats[slot.Unix()]
+		// Skip full slots and slots in the past
+		if remaining <= 0 || !slot.After(time.Now().UTC()) {
+			continue
 		}
+		filteredSlots = append(filteredSlots, domain.SlotAvailability{
+			Start:          slot,
+			Capacity:       capacity,
+			SeatsRemaining: remaining,
+		})
 	}
 
 	if s.cache != nil {
@@ -164,14 +232,46 @@ func (s *BookingService) GetAvailableSlots(ctx context.Context, productID primit
 }
 
 // CreateBooking creates a new confirmed booking.
+// Group products accept bookings until every seat in the slot is taken; attendees of the
+// same slot share a single meeting link.
 func (s *BookingService) CreateBooking(ctx context.Context, booking *domain.Booking) error {
-	// Re-verify the slot is still available
+	product, err := s.productRepo.FindByID(ctx, booking.ProductID)
+	if err != nil {
+		return fmt.Errorf("failed to get product: %w", err)
+	}
+	capacity := 1
+	if product != nil {
+		capacity = product.SlotCapacity()
+	}
+
+	// Re-verify the slot still has a free seat
 	overlaps, err := s.bookingRepo.FindOverlapping(ctx, booking.ProductID, booking.SlotStart, booking.SlotEnd)
 	if err != nil {
 		return fmt.Errorf("failed to check overlapping bookings: %w", err)
 	}
 
-	if len(overlaps) > 0 {
+	seatsTaken := 0
+	for _, existing := range overlaps {
+		if !existing.SlotStart.Equal(booking.SlotStart) {
+			// Overlaps a different session
+			return fmt.Errorf("SLOT_UNAVAILABLE")
+		}
+		seatsTaken++
+		if booking.MeetingLink == "" && existing.MeetingLink != "" {
+			booking.MeetingLink = existing.MeetingLink
+		}
+	}
+
+	if seatsTaken >= capacity {
+		return fmt.Errorf("SLOT_UNAVAILABLE")
+	}
+
+	// The count above can race with another checkout; the reservation is what holds the seat
+	reserved, err := s.bookingRepo.ReserveSeat(ctx, booking.ProductID, booking.SlotStart, capacity, seatsTaken)
+	if err != nil {
+		return fmt.Errorf("failed to reserve seat: %w", err)
+	}
+	if !reserved {
 		return fmt.Errorf("SLOT_UNAVAILABLE")
 	}
 
@@ -179,10 +279,13 @@ func (s *BookingService) CreateBooking(ctx context.Context, booking *domain.Book
 
 	// Try to create a Google Calendar event with Meet link if creator has connected Google Calendar
 	if s.googleCalSvc != nil && booking.MeetingLink == "" {
-		product, prodErr := s.productRepo.FindByID(ctx, booking.ProductID)
 		summary := "1:1 Coaching Session"
-		if prodErr == nil && product != nil {
-			summary = product.Title + " — 1:1 Session"
+		if product != nil {
+			if capacity > 1 {
+				summary = product.Title + " — Group Session"
+			} else {
+				summary = product.Title + " — 1:1 Session"
+			}
 		}
 
 		description := fmt.Sprintf("Booking with %s\nBooked by: %s (%s)", summary, booking.BuyerName, booking.BuyerEmail)
@@ -212,11 +315,70 @@ func (s *BookingService) CreateBooking(ctx context.Context, booking *domain.Book
 	}
 
 	err = s.bookingRepo.Create(ctx, booking)
-	if err == nil && s.cache != nil {
+	if err != nil {
+		if releaseErr := s.bookingRepo.ReleaseSeat(ctx, booking.ProductID, booking.SlotStart); releaseErr != nil {
+			logger.Error("failed to release seat", "error", releaseErr, "product_id", booking.ProductID.Hex())
+		}
+		return err
+	}
+	if s.cache != nil {
 		dateStr := booking.SlotStart.UTC().Format("2006-01-02")
-		_ = s.cache.Delete(ctx, fmt.Sprintf("cache:slots:%s:%s", booking.ProductID.Hex(), dateStr))
+		_ = s.cache.Delete(ctx, slotCacheKey(booking.ProductID, dateStr))
+	}
+
+	s.scheduleLifecycleTasks(booking)
+	s.webhooks.Publish(booking.CreatorID, domain.WebhookEventBookingCreated, booking)
+	return nil
+}
+
+// GetSlotAttendees returns the attendee list for one session of a creator's booking product.
+func (s *BookingService) GetSlotAttendees(ctx context.Context, productID, creatorID primitive.ObjectID, slotStart time.Time) ([]*domain.Booking, error) {
+	product, err := s.productRepo.FindByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil || product.ProductType != domain.ProductTypeBooking {
+		return nil, fmt.Errorf("product not found")
+	}
+	if product.CreatorID != creatorID {
+		return nil, fmt.Errorf("unauthorized to view this session")
+	}
+
+	attendees, err := s.bookingRepo.FindBySlot(ctx, productID, slotStart.UTC())
+	if err != nil {
+		return nil, err
+	}
+	if attendees == nil {
+		attendees = []*domain.Booking{}
+	}
+	return attendees, nil
+}
+
+// scheduleLifecycleTasks enqueues the reminder emails and the completion transition for a booking.
+func (s *BookingService) scheduleLifecycleTasks(booking *domain.Booking) {
+	if s.workerClient == nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	for _, lead := range bookingReminderLeadTimes {
+		at := booking.SlotStart.Add(-lead.Offset)
+		if at.Before(now) {
+			continue // Booked too close to the session for this reminder
+		}
+		if err := EnqueueBookingReminderTask(s.workerClient, booking.ID.Hex(), lead.Label, at); err != nil {
+			logger.Error("failed to schedule booking reminder", "error", err, "booking_id", booking.ID.Hex(), "lead_time", lead.Label)
+		}
+		// One creator reminder per slot, however many seats are booked in it
+		err := EnqueueSlotReminderTask(s.workerClient, booking.ProductID.Hex(), booking.SlotStart, lead.Label, at)
+		if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+			logger.Error("failed to schedule creator slot reminder", "error", err, "booking_id", booking.ID.Hex(), "lead_time", lead.Label)
+		}
+	}
+
+	if err := EnqueueBookingCompleteTask(s.workerClient, booking.ID.Hex(), booking.SlotEnd); err != nil {
+		logger.Error("failed to schedule booking completion", "error", err, "booking_id", booking.ID.Hex())
 	}
-	return err
 }
 
 // CancelBooking cancels a booking if within the cancellation window.
@@ -253,10 +415,256 @@ func (s *BookingService) CancelBooking(ctx context.Context, bookingID primitive.
 		return fmt.Errorf("cancellation period has expired (requires %d hours notice)", windowHours)
 	}
 
-	err = s.bookingRepo.UpdateStatus(ctx, bookingID, domain.BookingStatusCancelled)
-	if err == nil && s.cache != nil {
+	cancelled, err := s.bookingRepo.TransitionStatus(ctx, bookingID, booking.Status, domain.BookingStatusCancelled)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return fmt.Errorf("booking status changed, please try again")
+	}
+	if err := s.bookingRepo.ReleaseSeat(ctx, booking.ProductID, booking.SlotStart); err != nil {
+		logger.Error("failed to release seat", "error", err, "booking_id", bookingID.Hex())
+	}
+	if s.cache != nil {
 		dateStr := booking.SlotStart.UTC().Format("2006-01-02")
-		_ = s.cache.Delete(ctx, fmt.Sprintf("cache:slots:%s:%s", booking.ProductID.Hex(), dateStr))
+		_ = s.cache.Delete(ctx, slotCacheKey(booking.ProductID, dateStr))
+	}
+	return nil
+}
+
+// ExecuteBookingReminder runs inside the worker and emails the buyer ahead of a session.
+func (s *BookingService) ExecuteBookingReminder(ctx context.Context, bookingID string, leadTime string) error {
+	id, err := primitive.ObjectIDFromHex(bookingID)
+	if err != nil {
+		return err
+	}
+
+	booking, err := s.bookingRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if booking == nil {
+		return fmt.Errorf("booking %s not found", bookingID)
+	}
+
+	// Cancelled or already finished sessions don't need a reminder
+	if booking.Status != domain.BookingStatusConfirmed || booking.SlotStart.Before(time.Now().UTC()) {
+		return nil
+	}
+
+	if s.emailSvc == nil {
+		return fmt.Errorf("email service not configured")
+	}
+
+	title := "your session"
+	if product, err := s.productRepo.FindByID(ctx, booking.ProductID); err == nil && product != nil {
+		title = product.Title
+	}
+
+	startStr := booking.SlotStart.UTC().Format("Mon, 02 Jan 2006 15:04 MST")
+	subject := fmt.Sprintf("Reminder: %s starts in %s", title, leadTime)
+
+	buyerBody := fmt.Sprintf(`
+		<p>Hi %s,</p>
+		<p>This is a reminder that <strong>%s</strong> starts in %s (%s).</p>
+		<p><a href="%s">Join the meeting</a></p>
+	`, html.EscapeString(booking.BuyerName), html.EscapeString(title), leadTime, startStr, html.EscapeString(booking.MeetingLink))
+
+	if err := s.emailSvc.Send(ctx, booking.BuyerEmail, subject, buyerBody); err != nil {
+		return fmt.Errorf("failed sending buyer reminder: %w", err)
+	}
+	return nil
+}
+
+// ExecuteSlotReminder runs inside the worker and emails the creator once ahead of a session,
+// listing everyone booked into the slot.
+func (s *BookingService) ExecuteSlotReminder(ctx context.Context, productIDHex string, slotStart time.Time, leadTime string) error {
+	productID, err := primitive.ObjectIDFromHex(productIDHex)
+	if err != nil {
+		return err
+	}
+	if slotStart.Before(time.Now().UTC()) {
+		return nil
+	}
+
+	attendees, err := s.bookingRepo.FindBySlot(ctx, productID, slotStart.UTC())
+	if err != nil {
+		return err
+	}
+	var confirmed []*domain.Booking
+	for _, b := range attendees {
+		if b.Status == domain.BookingStatusConfirmed {
+			confirmed = append(confirmed, b)
+		}
+	}
+	// Everyone cancelled
+	if len(confirmed) == 0 {
+		return nil
+	}
+
+	if s.emailSvc == nil || s.userRepo == nil {
+		return fmt.Errorf("email service not configured")
+	}
+	creator, err := s.userRepo.FindByID(ctx, confirmed[0].CreatorID.Hex())
+	if err != nil || creator == nil {
+		return fmt.Errorf("creator not found for slot reminder")
+	}
+
+	title := "your session"
+	if product, err := s.productRepo.FindByID(ctx, productID); err == nil && product != nil {
+		title = product.Title
+	}
+
+	var list strings.Builder
+	for _, b := range confirmed {
+		list.WriteString("<li>" + html.EscapeString(b.BuyerName) + " (" + html.EscapeString(b.BuyerEmail) + ")</li>")
+	}
+	startStr := slotStart.UTC().Format("Mon, 02 Jan 2006 15:04 MST")
+	subject := fmt.Sprintf("Reminder: %s starts in %s", title, leadTime)
+	body := fmt.Sprintf(`
+		<p>Hi %s,</p>
+		<p>Your session <strong>%s</strong> starts in %s (%s). Booked:</p>
+		<ul>%s</ul>
+		<p><a href="%s">Join the meeting</a></p>
+	`, html.EscapeString(creator.DisplayName), html.EscapeString(title), leadTime, startStr, list.String(), html.EscapeString(confirmed[0].MeetingLink))
+
+	return s.emailSvc.Send(ctx, creator.Email, subject, body)
+}
+
+// CompleteBooking marks a confirmed booking as completed once its slot has ended and runs completion hooks.
+func (s *BookingService) CompleteBooking(ctx context.Context, bookingID string) error {
+	id, err := primitive.ObjectIDFromHex(bookingID)
+	if err != nil {
+		return err
+	}
+
+	booking, err := s.bookingRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if booking == nil {
+		return fmt.Errorf("booking %s not found", bookingID)
+	}
+
+	return s.completeBooking(ctx, booking)
+}
+
+// CompleteElapsedBookings sweeps confirmed bookings whose slot has ended.
+// It backs up the scheduled completion task in case the queue lost it.
+func (s *BookingService) CompleteElapsedBookings(ctx context.Context) error {
+	bookings, err := s.bookingRepo.FindConfirmedEndedBefore(ctx, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed fetching elapsed bookings: %w", err)
+	}
+
+	for _, booking := range bookings {
+		if err := s.completeBooking(ctx, booking); err != nil {
+			logger.Error("failed to complete elapsed booking", "error", err, "booking_id", booking.ID.Hex())
+		}
+	}
+	return nil
+}
+
+func (s *BookingService) completeBooking(ctx context.Context, booking *domain.Booking) error {
+	// Only confirmed sessions complete; cancelled and no-show bookings keep their status
+	if booking.Status != domain.BookingStatusConfirmed {
+		return nil
+	}
+	if time.Now().UTC().Before(booking.SlotEnd) {
+		return nil
+	}
+
+	// The scheduled task and the sweep can both get here; only the one that moves the booking
+	// out of confirmed runs the hooks. A no-show recorded in the meantime is left alone.
+	completed, err := s.bookingRepo.TransitionStatus(ctx, booking.ID, domain.BookingStatusConfirmed, domain.BookingStatusCompleted)
+	if err != nil {
+		return err
+	}
+	if !completed {
+		return nil
+	}
+	booking.Status = domain.BookingStatusCompleted
+
+	if len(s.completionHooks) == 0 {
+		return nil
+	}
+
+	product, err := s.productRepo.FindByID(ctx, booking.ProductID)
+	if err != nil || product == nil {
+		logger.Error("failed to load product for booking completion hooks", "error", err, "booking_id", booking.ID.Hex())
+		return nil
+	}
+
+	for _, hook := range s.completionHooks {
+		if err := hook(ctx, booking, product); err != nil {
+			logger.Error("booking completion hook failed", "error", err, "booking_id", booking.ID.Hex())
+		}
+	}
+	return nil
+}
+
+// MarkNoShow lets the creator record that the buyer did not attend a session.
+func (s *BookingService) MarkNoShow(ctx context.Context, bookingID primitive.ObjectID, creatorID primitive.ObjectID) error {
+	booking, err := s.bookingRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+	if booking == nil {
+		return fmt.Errorf("booking not found")
+	}
+
+	if booking.CreatorID != creatorID {
+		return fmt.Errorf("unauthorized to update this booking")
+	}
+
+	if booking.Status != domain.BookingStatusConfirmed && booking.Status != domain.BookingStatusCompleted {
+		return fmt.Errorf("only confirmed or completed bookings can be marked as no-show")
+	}
+
+	if time.Now().UTC().Before(booking.SlotStart) {
+		return fmt.Errorf("session has not started yet")
+	}
+
+	// Conditional, so a no-show can't overwrite a cancellation made meanwhile. A confirmed booking
+	// the completion sweep finishes in the meantime is still marked from completed.
+	for _, from := range []domain.BookingStatus{domain.BookingStatusConfirmed, domain.BookingStatusCompleted} {
+		marked, err := s.bookingRepo.TransitionStatus(ctx, bookingID, from, domain.BookingStatusNoShow)
+		if err != nil {
+			return err
+		}
+		if marked {
+			return nil
+		}
+	}
+	return fmt.Errorf("only confirmed or completed bookings can be marked as no-show")
+}
+
+// BookingFollowUpHook returns a completion hook that sends the creator's booking follow-up template,
+// e.g. a thank-you note or a testimonial request, to the buyer.
+func BookingFollowUpHook(templateRepo domain.EmailTemplateRepository, userRepo domain.UserRepository, emailSvc domain.EmailService) BookingCompletionHook {
+	return func(ctx context.Context, booking *domain.Booking, product *domain.Product) error {
+		template, err := templateRepo.FindByCreatorAndType(ctx, booking.CreatorID, domain.TemplateTypeBookingFollowUp)
+		if err != nil || template == nil || !template.IsActive {
+			return nil // Creator hasn't enabled follow-ups
+		}
+
+		creatorName := "Creator"
+		if creator, err := userRepo.FindByID(ctx, booking.CreatorID.Hex()); err == nil && creator != nil {
+			creatorName = creator.DisplayName
+		}
+
+		// The subject is plain text; values placed in the HTML body are escaped
+		subject := strings.NewReplacer(
+			"{product_title}", product.Title,
+			"{creator_name}", creatorName,
+			"{buyer_name}", booking.BuyerName,
+		).Replace(template.Subject)
+		body := strings.NewReplacer(
+			"{product_title}", html.EscapeString(product.Title),
+			"{creator_name}", html.EscapeString(creatorName),
+			"{buyer_name}", html.EscapeString(booking.BuyerName),
+		).Replace(template.BodyHTML)
+
+		return emailSvc.Send(ctx, booking.BuyerEmail, subject, body)
 	}
-	return err
 }
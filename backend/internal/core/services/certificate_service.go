@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+	"github.com/devanshbhargava/stan-store/pkg/pdf"
+)
+
+var (
+	ErrCertificateNotFound  = errors.New("certificate not found")
+	ErrCertificatesDisabled = errors.New("certificates are not enabled for this course")
+	ErrCourseIncomplete     = errors.New("course has not been completed")
+)
+
+// certificateIDAlphabet avoids characters that are easily confused when typed (0/O, 1/I).
+const certificateIDAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// CertificateService issues course completion certificates as PDFs.
+type CertificateService struct {
+	certRepo     domain.CertificateRepository
+	courseRepo   domain.CourseRepository
+	productRepo  domain.ProductRepository
+	userRepo     domain.UserRepository
+	progressRepo domain.CourseProgressRepository
+	storage      domain.FileStorage
+	frontendURL  string
+}
+
+// NewCertificateService creates a new CertificateService.
+func NewCertificateService(
+	certRepo domain.CertificateRepository,
+	courseRepo domain.CourseRepository,
+	productRepo domain.ProductRepository,
+	userRepo domain.UserRepository,
+	progressRepo domain.CourseProgressRepository,
+	storage domain.FileStorage,
+	frontendURL string,
+) *CertificateService {
+	return &CertificateService{
+		certRepo:     certRepo,
+		courseRepo:   courseRepo,
+		productRepo:  productRepo,
+		userRepo:     userRepo,
+		progressRepo: progressRepo,
+		storage:      storage,
+		frontendURL:  frontendURL,
+	}
+}
+
+// UpdateTemplate saves the certificate template for a creator's course.
+func (s *CertificateService) UpdateTemplate(ctx context.Context, productID, creatorID primitive.ObjectID, tmpl *domain.CertificateTemplate) (*domain.Course, error) {
+	product, err := s.productRepo.FindByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil || product.CreatorID != creatorID {
+		return nil, errors.New("unauthorized access to product")
+	}
+
+	if tmpl.AccentColor != "" && !isHexColor(tmpl.AccentColor) {
+		return nil, errors.New("accent color must be a hex value like #4f46e5")
+	}
+	if len(tmpl.Heading) > 60 || len(tmpl.BodyText) > 200 || len(tmpl.SignatureName) > 60 {
+		return nil, errors.New("certificate text is too long")
+	}
+
+	course, err := s.courseRepo.FindByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.courseRepo.UpdateCertificate(ctx, course.ID, tmpl); err != nil {
+		return nil, err
+	}
+	course.Certificate = tmpl
+	return course, nil
+}
+
+// IssueOnCompletion is a course completion hook that awards the certificate as soon as
+// a buyer finishes the course.
+func (s *CertificateService) IssueOnCompletion(ctx context.Context, progress *domain.CourseProgress) error {
+	_, err := s.IssueCertificate(ctx, progress.ProductID, progress.UserID)
+	if errors.Is(err, ErrCertificatesDisabled) {
+		return nil
+	}
+	return err
+}
+
+// IssueCertificate returns the buyer's certificate for a course, generating it on first request.
+func (s *CertificateService) IssueCertificate(ctx context.Context, productID, userID primitive.ObjectID) (*domain.Certificate, error) {
+	existing, err := s.certRepo.FindByUserAndProduct(ctx, userID, productID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	course, err := s.courseRepo.FindByProductID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if course.Certificate == nil || !course.Certificate.Enabled {
+		return nil, ErrCertificatesDisabled
+	}
+
+	progress, err := s.progressRepo.FindByUserAndProduct(ctx, userID, productID)
+	if err != nil {
+		return nil, err
+	}
+	if progress == nil || progress.CompletedAt == nil {
+		return nil, ErrCourseIncomplete
+	}
+
+	product, err := s.productRepo.FindByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+
+	buyer, err := s.userRepo.FindByID(ctx, userID.Hex())
+	if err != nil || buyer == nil {
+		return nil, errors.New("buyer not found")
+	}
+	creator, err := s.userRepo.FindByID(ctx, product.CreatorID.Hex())
+	if err != nil || creator == nil {
+		return nil, errors.New("creator not found")
+	}
+
+	verificationID, err := generateCertificateID()
+	if err != nil {
+		return nil, err
+	}
+
+	cert := &domain.Certificate{
+		VerificationID: verificationID,
+		ProductID:      productID,
+		CreatorID:      product.CreatorID,
+		UserID:         userID,
+		BuyerName:      displayNameOrEmail(buyer),
+		CreatorName:    displayNameOrEmail(creator),
+		CourseTitle:    product.Title,
+		IssuedAt:       *progress.CompletedAt,
+		FileKey:        fmt.Sprintf("creators/%s/certificates/%s.pdf", product.CreatorID.Hex(), verificationID),
+	}
+
+	doc := renderCertificate(cert, course.Certificate, s.verificationURL(verificationID))
+	if err := s.storage.Upload(ctx, cert.FileKey, "application/pdf", doc); err != nil {
+		return nil, fmt.Errorf("failed to store certificate: %w", err)
+	}
+
+	if err := s.certRepo.Create(ctx, cert); err != nil {
+		// A concurrent request may have issued it first; prefer the stored record.
+		if again, findErr := s.certRepo.FindByUserAndProduct(ctx, userID, productID); findErr == nil && again != nil {
+			_ = s.storage.Delete(ctx, cert.FileKey)
+			return again, nil
+		}
+		return nil, err
+	}
+
+	logger.Info("certificate issued", "verification_id", verificationID, "product_id", productID.Hex())
+	return cert, nil
+}
+
+// GetDownloadURL issues the certificate if needed and returns a short-lived download link.
+func (s *CertificateService) GetDownloadURL(ctx context.Context, productID, userID primitive.ObjectID) (*domain.Certificate, string, error) {
+	cert, err := s.IssueCertificate(ctx, productID, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	url, err := s.storage.GeneratePresignedDownloadURL(ctx, cert.FileKey, 15*time.Minute)
+	if err != nil {
+		return nil, "", err
+	}
+	return cert, url, nil
+}
+
+// Verify returns the public details of a certificate for the verification page.
+func (s *CertificateService) Verify(ctx context.Context, verificationID string) (*domain.CertificateVerification, error) {
+	cert, err := s.certRepo.FindByVerificationID(ctx, strings.ToUpper(strings.TrimSpace(verificationID)))
+	if err != nil {
+		return nil, err
+	}
+	if cert == nil {
+		return nil, ErrCertificateNotFound
+	}
+
+	return &domain.CertificateVerification{
+		VerificationID: cert.VerificationID,
+		BuyerName:      cert.BuyerName,
+		CreatorName:    cert.CreatorName,
+		CourseTitle:    cert.CourseTitle,
+		IssuedAt:       cert.IssuedAt,
+	}, nil
+}
+
+func (s *CertificateService) verificationURL(verificationID string) string {
+	return fmt.Sprintf("%s/certificates/%s", strings.TrimRight(s.frontendURL, "/"), verificationID)
+}
+
+// generateCertificateID returns an ID such as CERT-7KQ2-M9XD-4TPA.
+func generateCertificateID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate certificate id: %w", err)
+	}
+	for i := range b {
+		b[i] = certificateIDAlphabet[int(b[i])%len(certificateIDAlphabet)]
+	}
+	return fmt.Sprintf("CERT-%s-%s-%s", b[0:4], b[4:8], b[8:12]), nil
+}
+
+// isHexColor reports whether s looks like "#RRGGBB".
+func isHexColor(s string) bool {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 6 {
+		return false
+	}
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
+
+func displayNameOrEmail(u *domain.User) string {
+	return pdfName(u.DisplayName, strings.Split(u.Email, "@")[0])
+}
+
+// pdfName returns name if generated PDFs can print it, possibly transliterated, and
+// otherwise fallback. The PDF fonts cover Latin and the Indian scripts but not, for example,
+// CJK or Arabic, which would print as "???".
+func pdfName(name, fallback string) string {
+	if name == "" {
+		return fallback
+	}
+	if !pdf.Representable(name) {
+		logger.Warn("name cannot be printed in PDFs, using fallback", "name", name)
+		return fallback
+	}
+	return name
+}
+
+// renderCertificate lays out a landscape A4 certificate.
+func renderCertificate(cert *domain.Certificate, tmpl *domain.CertificateTemplate, verifyURL string) []byte {
+	heading := tmpl.Heading
+	if heading == "" {
+		heading = "Certificate of Completion"
+	}
+	body := tmpl.BodyText
+	if body == "" {
+		body = "has successfully completed the course"
+	}
+	signature := tmpl.SignatureName
+	if signature == "" {
+		signature = cert.CreatorName
+	}
+	accent := pdf.Color{R: 0.31, G: 0.27, B: 0.9}
+	if tmpl.AccentColor != "" {
+		accent = pdf.HexColor(tmpl.AccentColor)
+	}
+	muted := pdf.Color{R: 0.4, G: 0.45, B: 0.53}
+
+	doc := pdf.New(pdf.A4Height, pdf.A4Width) // landscape
+	doc.SetTitle(heading + " — " + cert.CourseTitle)
+	w, h := doc.Width(), doc.Height()
+	page := doc.AddPage()
+
+	page.Rect(24, 24, w-48, h-48, 3, accent)
+	page.Rect(34, 34, w-68, h-68, 0.75, accent)
+
+	page.TextCentered(130, pdf.HelveticaBold, 34, accent, heading)
+	page.TextCentered(190, pdf.Helvetica, 14, muted, "This is to certify that")
+	page.TextCentered(245, pdf.HelveticaBold, 32, pdf.Black, cert.BuyerName)
+	page.Line(w/2-180, 260, w/2+180, 260, 0.75, muted)
+
+	y := 295.0
+	for _, line := range pdf.WrapText(pdf.Helvetica, 14, body, w-200) {
+		page.TextCentered(y, pdf.Helvetica, 14, muted, line)
+		y += 20
+	}
+	for _, line := range pdf.WrapText(pdf.HelveticaBold, 22, cert.CourseTitle, w-200) {
+		page.TextCentered(y+14, pdf.HelveticaBold, 22, pdf.Black, line)
+		y += 28
+	}
+
+	// Footer: date on the left, signature on the right
+	footerY := h - 130
+	page.Line(110, footerY, 300, footerY, 0.75, muted)
+	page.Text(110, footerY+18, pdf.Helvetica, 11, muted, "Date")
+	page.Text(110, footerY-8, pdf.HelveticaBold, 13, pdf.Black, cert.IssuedAt.Format("2 January 2006"))
+
+	page.Line(w-300, footerY, w-110, footerY, 0.75, muted)
+	page.TextRight(w-110, footerY+18, pdf.Helvetica, 11, muted, "Instructor")
+	page.TextRight(w-110, footerY-8, pdf.HelveticaOblique, 13, pdf.Black, signature)
+
+	page.TextCentered(h-60, pdf.Helvetica, 9, muted, "Certificate ID: "+cert.VerificationID+"  •  Verify at "+verifyURL)
+
+	return doc.Bytes()
+}
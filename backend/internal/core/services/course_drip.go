@@ -0,0 +1,304 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+const dripSweepCacheKey = "course:drip:last_sweep"
+
+// validateDripRule checks a creator-supplied drip rule against the course it belongs to.
+// selfLessonID is the lesson carrying the rule (empty for module rules).
+func validateDripRule(course *domain.Course, rule *domain.DripRule, selfLessonID string) error {
+	if rule == nil {
+		return nil
+	}
+
+	switch rule.Type {
+	case domain.DripDaysAfterPurchase:
+		if rule.Days < 1 {
+			return fmt.Errorf("%w: days must be at least 1", ErrInvalidDripRule)
+		}
+	case domain.DripFixedDate:
+		if rule.Date == nil || rule.Date.IsZero() {
+			return fmt.Errorf("%w: date is required", ErrInvalidDripRule)
+		}
+	case domain.DripAfterLesson:
+		if rule.PrerequisiteLessonID == "" {
+			return fmt.Errorf("%w: prerequisite lesson is required", ErrInvalidDripRule)
+		}
+		if rule.PrerequisiteLessonID == selfLessonID {
+			return fmt.Errorf("%w: a lesson cannot be its own prerequisite", ErrInvalidDripRule)
+		}
+		if !courseHasLesson(course, rule.PrerequisiteLessonID) {
+			return fmt.Errorf("%w: prerequisite lesson not found", ErrInvalidDripRule)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidDripRule, rule.Type)
+	}
+	return nil
+}
+
+// courseHasDrip reports whether any module or lesson in the course is drip-released.
+func courseHasDrip(course *domain.Course) bool {
+	for _, mod := range course.Modules {
+		if mod.Drip != nil {
+			return true
+		}
+		for _, les := range mod.Lessons {
+			if les.Drip != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// allLessons flattens the course into its lessons in display order.
+func allLessons(course *domain.Course) []domain.Lesson {
+	var lessons []domain.Lesson
+	for _, mod := range course.Modules {
+		lessons = append(lessons, mod.Lessons...)
+	}
+	return lessons
+}
+
+// dripRuleState evaluates a single rule for a buyer. unlocksAt is nil when the rule
+// is not time based (or has no rule at all).
+func dripRuleState(rule *domain.DripRule, purchasedAt time.Time, progress *domain.CourseProgress, at time.Time) (unlocked bool, unlocksAt *time.Time) {
+	if rule == nil {
+		return true, nil
+	}
+
+	switch rule.Type {
+	case domain.DripDaysAfterPurchase:
+		t := purchasedAt.AddDate(0, 0, rule.Days)
+		return !at.Before(t), &t
+	case domain.DripFixedDate:
+		if rule.Date == nil {
+			return true, nil
+		}
+		t := *rule.Date
+		return !at.Before(t), &t
+	case domain.DripAfterLesson:
+		if progress == nil {
+			return false, nil
+		}
+		entry := progress.Lesson(rule.PrerequisiteLessonID)
+		return entry != nil && entry.Completed, nil
+	}
+	return true, nil
+}
+
+// lessonUnlockState combines the module and lesson rules. A lesson is open only when both are.
+// unlocksAt is the time the lesson opens, or nil if that depends on a prerequisite.
+func lessonUnlockState(mod domain.Module, les domain.Lesson, purchasedAt time.Time, progress *domain.CourseProgress, at time.Time) (unlocked bool, unlocksAt *time.Time) {
+	unlocked = true
+	waitingOnPrerequisite := false
+
+	for _, rule := range []*domain.DripRule{mod.Drip, les.Drip} {
+		open, t := dripRuleState(rule, purchasedAt, progress, at)
+		if open {
+			continue
+		}
+		unlocked = false
+		if t == nil {
+			waitingOnPrerequisite = true
+		} else if unlocksAt == nil || t.After(*unlocksAt) {
+			unlocksAt = t
+		}
+	}
+
+	if unlocked || waitingOnPrerequisite {
+		return unlocked, nil
+	}
+	return false, unlocksAt
+}
+
+// unlockedLessons returns the set of lesson IDs the buyer can open at the given time.
+func unlockedLessons(course *domain.Course, purchasedAt time.Time, progress *domain.CourseProgress, at time.Time) map[string]bool {
+	unlocked := make(map[string]bool)
+	for _, mod := range course.Modules {
+		for _, les := range mod.Lessons {
+			if open, _ := lessonUnlockState(mod, les, purchasedAt, progress, at); open {
+				unlocked[les.ID] = true
+			}
+		}
+	}
+	return unlocked
+}
+
+// applyDripLocks marks locked lessons for a buyer and withholds their content.
+func applyDripLocks(course *domain.Course, purchasedAt time.Time, progress *domain.CourseProgress, at time.Time) {
+	for i := range course.Modules {
+		mod := course.Modules[i]
+		for j := range course.Modules[i].Lessons {
+			les := &course.Modules[i].Lessons[j]
+			open, unlocksAt := lessonUnlockState(mod, *les, purchasedAt, progress, at)
+			if open {
+				continue
+			}
+			les.Locked = true
+			les.UnlocksAt = unlocksAt
+			les.Content = ""
+		}
+	}
+}
+
+// findProgress returns the buyer's stored progress, or nil when none exists or tracking is disabled.
+func (s *CourseService) findProgress(ctx context.Context, userID, productID primitive.ObjectID) (*domain.CourseProgress, error) {
+	if s.progressRepo == nil {
+		return nil, nil
+	}
+	return s.progressRepo.FindByUserAndProduct(ctx, userID, productID)
+}
+
+// NotifyDripUnlocks emails buyers about lessons that unlocked since the previous sweep.
+// Called periodically; unlocks caused by completing a prerequisite are notified immediately instead.
+// Sent notifications are recorded, so overlapping or repeated sweeps don't email twice.
+func (s *CourseService) NotifyDripUnlocks(ctx context.Context) error {
+	if s.emailSvc == nil {
+		return nil
+	}
+
+	now := time.Now()
+	since := now.Add(-1 * time.Hour)
+	if s.cache != nil {
+		if cached, err := s.cache.Get(ctx, dripSweepCacheKey); err == nil && cached != "" {
+			if t, err := time.Parse(time.RFC3339, cached); err == nil && t.Before(now) {
+				since = t
+			}
+		}
+	}
+
+	courses, err := s.courseRepo.FindWithDripRules(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch drip courses: %w", err)
+	}
+
+	for _, course := range courses {
+		orders, err := s.orderRepo.FindPaidByProductID(ctx, course.ProductID)
+		if err != nil {
+			logger.Error("failed to fetch orders for drip sweep", "error", err, "product_id", course.ProductID.Hex())
+			continue
+		}
+
+		// Earliest paid purchase per buyer
+		purchases := make(map[string]time.Time)
+		for _, order := range orders {
+			email := strings.ToLower(order.CustomerEmail)
+			if t, ok := purchases[email]; !ok || order.CreatedAt.Before(t) {
+				purchases[email] = order.CreatedAt
+			}
+		}
+		if len(purchases) == 0 {
+			continue
+		}
+
+		progressByEmail, err := s.progressByEmail(ctx, course.ProductID, purchases)
+		if err != nil {
+			logger.Error("failed to fetch progress for drip sweep", "error", err, "product_id", course.ProductID.Hex())
+			continue
+		}
+
+		for email, purchasedAt := range purchases {
+			progress := progressByEmail[email]
+			before := unlockedLessons(course, purchasedAt, progress, since)
+			after := unlockedLessons(course, purchasedAt, progress, now)
+
+			var newlyUnlocked []domain.Lesson
+			for _, les := range allLessons(course) {
+				if before[les.ID] || !after[les.ID] {
+					continue
+				}
+				if s.progressRepo != nil {
+					first, err := s.progressRepo.MarkUnlockNotified(ctx, course.ProductID, email, les.ID)
+					if err != nil {
+						logger.Error("failed to record drip unlock", "error", err, "product_id", course.ProductID.Hex())
+						continue
+					}
+					if !first {
+						continue
+					}
+				}
+				newlyUnlocked = append(newlyUnlocked, les)
+			}
+			if len(newlyUnlocked) > 0 {
+				s.sendUnlockEmail(ctx, email, course.ProductID, newlyUnlocked)
+			}
+		}
+	}
+
+	if s.cache != nil {
+		_ = s.cache.Set(ctx, dripSweepCacheKey, now.Format(time.RFC3339), 7*24*time.Hour)
+	}
+	return nil
+}
+
+// progressByEmail loads the course progress of the buyers in purchases with one query for the
+// users and one for the progress records.
+func (s *CourseService) progressByEmail(ctx context.Context, productID primitive.ObjectID, purchases map[string]time.Time) (map[string]*domain.CourseProgress, error) {
+	result := make(map[string]*domain.CourseProgress)
+	if s.progressRepo == nil {
+		return result, nil
+	}
+
+	emails := make([]string, 0, len(purchases))
+	for email := range purchases {
+		emails = append(emails, email)
+	}
+	buyers, err := s.userRepo.FindByEmails(ctx, emails)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.progressRepo.FindByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := make(map[primitive.ObjectID]*domain.CourseProgress, len(records))
+	for _, p := range records {
+		byUser[p.UserID] = p
+	}
+	for _, buyer := range buyers {
+		if p, ok := byUser[buyer.ID]; ok {
+			result[strings.ToLower(buyer.Email)] = p
+		}
+	}
+	return result, nil
+}
+
+// sendUnlockEmail tells a buyer which lessons just became available.
+func (s *CourseService) sendUnlockEmail(ctx context.Context, email string, productID primitive.ObjectID, lessons []domain.Lesson) {
+	if s.emailSvc == nil {
+		return
+	}
+
+	courseTitle := "your course"
+	if product, err := s.productRepo.FindByID(ctx, productID); err == nil && product != nil {
+		courseTitle = product.Title
+	}
+
+	var items strings.Builder
+	for _, les := range lessons {
+		items.WriteString("<li>" + html.EscapeString(les.Title) + "</li>")
+	}
+
+	subject := fmt.Sprintf("New content unlocked in %s", courseTitle)
+	body := fmt.Sprintf(
+		"<p>Good news! New lessons are now available in <strong>%s</strong>:</p><ul>%s</ul><p><a href=\"%s/course-player/%s\">Continue learning</a></p>",
+		html.EscapeString(courseTitle), items.String(), s.frontendURL, productID.Hex(),
+	)
+
+	if err := s.emailSvc.Send(ctx, email, subject, body); err != nil {
+		logger.Error("failed to send drip unlock email", "error", err, "product_id", productID.Hex())
+	}
+}
@@ -11,20 +11,35 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
 )
 
 var (
 	ErrCourseNotFound = errors.New("course not found")
 	ErrModuleNotFound = errors.New("module not found")
 	ErrLessonNotFound = errors.New("lesson not found")
+	ErrLessonLocked   = errors.New("lesson is locked")
+
+	ErrInvalidDripRule = errors.New("invalid drip rule")
 )
 
+// CourseCompletionHook runs after a buyer completes every lesson in a course.
+// Hooks are best-effort: errors are logged and do not affect the progress update.
+type CourseCompletionHook func(ctx context.Context, progress *domain.CourseProgress) error
+
 type CourseService struct {
 	courseRepo  domain.CourseRepository
 	productRepo domain.ProductRepository
 	orderRepo   domain.OrderRepository
 	userRepo    domain.UserRepository // To fetch buyer's email if needed
 	cache       domain.Cache
+
+	progressRepo domain.CourseProgressRepository
+	emailSvc     domain.EmailService
+	frontendURL  string
+	videoSvc     *VideoService
+
+	completionHooks []CourseCompletionHook
 }
 
 func NewCourseService(courseRepo domain.CourseRepository, productRepo domain.ProductRepository, orderRepo domain.OrderRepository, userRepo domain.UserRepository, cache domain.Cache) *CourseService {
@@ -37,6 +52,27 @@ func NewCourseService(courseRepo domain.CourseRepository, productRepo domain.Pro
 	}
 }
 
+// SetProgressRepo injects the store used to track buyer progress through courses.
+func (s *CourseService) SetProgressRepo(repo domain.CourseProgressRepository) {
+	s.progressRepo = repo
+}
+
+// SetNotificationDeps injects what is needed to email buyers when drip content unlocks.
+func (s *CourseService) SetNotificationDeps(emailSvc domain.EmailService, frontendURL string) {
+	s.emailSvc = emailSvc
+	s.frontendURL = frontendURL
+}
+
+// SetVideoService enables HLS streaming of uploaded lesson videos.
+func (s *CourseService) SetVideoService(videoSvc *VideoService) {
+	s.videoSvc = videoSvc
+}
+
+// AddCompletionHook registers a hook that runs when a buyer completes a course.
+func (s *CourseService) AddCompletionHook(hook CourseCompletionHook) {
+	s.completionHooks = append(s.completionHooks, hook)
+}
+
 func (s *CourseService) invalidateCache(ctx context.Context, productID primitive.ObjectID) {
 	if s.cache != nil {
 		cacheKey := fmt.Sprintf("cache:course:preview:%s", productID.Hex())
@@ -46,6 +82,22 @@ func (s *CourseService) invalidateCache(ctx context.Context, productID primitive
 
 // getOrCreateCourse gets a course by product ID, creating a default one if it doesn't exist
 func (s *CourseService) getOrCreateCourse(ctx context.Context, productID primitive.ObjectID, creatorID primitive.ObjectID) (*domain.Course, error) {
+	course, err := s.findCourse(ctx, productID, creatorID)
+	if err != nil {
+		return nil, err
+	}
+	if course.ID.IsZero() {
+		if err := s.courseRepo.Create(ctx, course); err != nil {
+			return nil, err
+		}
+		s.invalidateCache(ctx, productID)
+	}
+	return course, nil
+}
+
+// findCourse gets a creator's course by product ID without writing anything. A course that
+// hasn't been saved yet comes back empty, with a zero ID.
+func (s *CourseService) findCourse(ctx context.Context, productID primitive.ObjectID, creatorID primitive.ObjectID) (*domain.Course, error) {
 	product, err := s.productRepo.FindByID(ctx, productID)
 	if err != nil {
 		return nil, err
@@ -58,16 +110,11 @@ func (s *CourseService) getOrCreateCourse(ctx context.Context, productID primiti
 	if err != nil {
 		// Simplistic check for ErrNotFound or mongo.ErrNoDocuments from repository
 		if err.Error() == "not found" || err.Error() == "mongo: no documents in result" {
-			course = &domain.Course{
+			return &domain.Course{
 				ProductID: productID,
 				CreatorID: creatorID,
 				Modules:   []domain.Module{},
-			}
-			if err := s.courseRepo.Create(ctx, course); err != nil {
-				return nil, err
-			}
-			s.invalidateCache(ctx, productID)
-			return course, nil
+			}, nil
 		}
 		return nil, err
 	}
@@ -78,46 +125,126 @@ func (s *CourseService) getOrCreateCourse(ctx context.Context, productID primiti
 func (s *CourseService) GetCourse(ctx context.Context, productID primitive.ObjectID, requesterID primitive.ObjectID, isCreator bool) (*domain.Course, error) {
 	// If creator, verify ownership and return/create
 	if isCreator {
-		return s.getOrCreateCourse(ctx, productID, requesterID)
+		course, err := s.getOrCreateCourse(ctx, productID, requesterID)
+		if err != nil {
+			return nil, err
+		}
+		if s.videoSvc != nil {
+			if err := s.videoSvc.AttachStreams(ctx, course, requesterID, false); err != nil {
+				return nil, err
+			}
+		}
+		return course, nil
+	}
+
+	purchasedAt, err := s.verifyPurchase(ctx, productID, requesterID)
+	if err != nil {
+		return nil, err
+	}
+
+	course, err := s.loadCourse(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if courseHasDrip(course) {
+		progress, err := s.findProgress(ctx, requesterID, productID)
+		if err != nil {
+			return nil, err
+		}
+		applyDripLocks(course, purchasedAt, progress, time.Now())
+	}
+	if s.videoSvc != nil {
+		if err := s.videoSvc.AttachStreams(ctx, course, requesterID, true); err != nil {
+			return nil, err
+		}
 	}
+	return course, nil
+}
 
+// AuthorizeLesson returns a lesson the viewer may open: any lesson for the course's creator,
+// otherwise an unlocked lesson of a course the viewer has purchased.
+func (s *CourseService) AuthorizeLesson(ctx context.Context, productID primitive.ObjectID, viewerID primitive.ObjectID, lessonID string) (*domain.Lesson, error) {
+	course, err := s.loadCourse(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	var lesson *domain.Lesson
+	for _, les := range allLessons(course) {
+		if les.ID == lessonID {
+			lesson = &les
+			break
+		}
+	}
+	if lesson == nil {
+		return nil, ErrLessonNotFound
+	}
+	if course.CreatorID == viewerID {
+		return lesson, nil
+	}
+
+	purchasedAt, err := s.verifyPurchase(ctx, productID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if courseHasDrip(course) {
+		progress, err := s.findProgress(ctx, viewerID, productID)
+		if err != nil {
+			return nil, err
+		}
+		if !unlockedLessons(course, purchasedAt, progress, time.Now())[lessonID] {
+			return nil, ErrLessonLocked
+		}
+	}
+	return lesson, nil
+}
+
+// verifyPurchase checks that the buyer has a paid order containing the course product
+// and returns when they first bought it.
+func (s *CourseService) verifyPurchase(ctx context.Context, productID primitive.ObjectID, buyerID primitive.ObjectID) (time.Time, error) {
 	// 1. Fetch user to get their email
-	buyer, err := s.userRepo.FindByID(ctx, requesterID.Hex())
+	buyer, err := s.userRepo.FindByID(ctx, buyerID.Hex())
 	if err != nil {
-		return nil, errors.New("unauthorized: user not found")
+		return time.Time{}, errors.New("unauthorized: user not found")
 	}
 
 	// 2. Fetch all orders for this buyer to see if they bought this product
 	orders, err := s.orderRepo.FindAllByCustomerEmail(ctx, buyer.Email)
 	if err != nil {
-		return nil, errors.New("failed to verify course purchase")
+		return time.Time{}, errors.New("failed to verify course purchase")
 	}
 
-	hasPurchased := false
+	var purchasedAt time.Time
 	for _, order := range orders {
-		if order.Status != domain.OrderStatusPaid {
+		if order.Status != domain.OrderStatusPaid || !orderContainsProduct(order, productID) {
 			continue
 		}
-		// Check both legacy ProductID and new LineItems array
-		if order.ProductID == productID {
-			hasPurchased = true
-			break
-		}
-		for _, item := range order.LineItems {
-			if item.ProductID == productID {
-				hasPurchased = true
-				break
-			}
-		}
-		if hasPurchased {
-			break
+		if purchasedAt.IsZero() || order.CreatedAt.Before(purchasedAt) {
+			purchasedAt = order.CreatedAt
 		}
 	}
 
-	if !hasPurchased {
-		return nil, errors.New("unauthorized: you have not purchased this course")
+	if purchasedAt.IsZero() {
+		return time.Time{}, errors.New("unauthorized: you have not purchased this course")
+	}
+	return purchasedAt, nil
+}
+
+// orderContainsProduct checks both the legacy ProductID and the LineItems array.
+func orderContainsProduct(order *domain.Order, productID primitive.ObjectID) bool {
+	if order.ProductID == productID {
+		return true
+	}
+	for _, item := range order.LineItems {
+		if item.ProductID == productID {
+			return true
+		}
 	}
+	return false
+}
 
+// loadCourse returns the course structure for a product, served from cache when possible.
+func (s *CourseService) loadCourse(ctx context.Context, productID primitive.ObjectID) (*domain.Course, error) {
 	cacheKey := fmt.Sprintf("cache:course:preview:%s", productID.Hex())
 	if s.cache != nil {
 		if cached, err := s.cache.Get(ctx, cacheKey); err == nil && cached != "" {
@@ -143,17 +270,21 @@ func (s *CourseService) GetCourse(ctx context.Context, productID primitive.Objec
 }
 
 // CreateModule adds a new module to a course
-func (s *CourseService) CreateModule(ctx context.Context, productID primitive.ObjectID, creatorID primitive.ObjectID, title string, sortOrder int) (*domain.Course, error) {
+func (s *CourseService) CreateModule(ctx context.Context, productID primitive.ObjectID, creatorID primitive.ObjectID, title string, sortOrder int, drip *domain.DripRule) (*domain.Course, error) {
 	course, err := s.getOrCreateCourse(ctx, productID, creatorID)
 	if err != nil {
 		return nil, err
 	}
+	if err := validateDripRule(course, drip, ""); err != nil {
+		return nil, err
+	}
 
 	newModule := domain.Module{
 		ID:        uuid.New().String(),
 		Title:     title,
 		SortOrder: sortOrder,
 		Lessons:   []domain.Lesson{},
+		Drip:      drip,
 	}
 
 	course.Modules = append(course.Modules, newModule)
@@ -165,18 +296,22 @@ func (s *CourseService) CreateModule(ctx context.Context, productID primitive.Ob
 	return course, nil
 }
 
-// UpdateModule updates a module's title/order
-func (s *CourseService) UpdateModule(ctx context.Context, productID primitive.ObjectID, creatorID primitive.ObjectID, moduleID string, title string, sortOrder int) (*domain.Course, error) {
+// UpdateModule updates a module's title/order and drip rule
+func (s *CourseService) UpdateModule(ctx context.Context, productID primitive.ObjectID, creatorID primitive.ObjectID, moduleID string, title string, sortOrder int, drip *domain.DripRule) (*domain.Course, error) {
 	course, err := s.getOrCreateCourse(ctx, productID, creatorID)
 	if err != nil {
 		return nil, err
 	}
+	if err := validateDripRule(course, drip, ""); err != nil {
+		return nil, err
+	}
 
 	found := false
 	for i := range course.Modules {
 		if course.Modules[i].ID == moduleID {
 			course.Modules[i].Title = title
 			course.Modules[i].SortOrder = sortOrder
+			course.Modules[i].Drip = drip
 			found = true
 			break
 		}
@@ -230,6 +365,9 @@ func (s *CourseService) CreateLesson(ctx context.Context, productID primitive.Ob
 	if err != nil {
 		return nil, err
 	}
+	if err := validateDripRule(course, lesson.Drip, ""); err != nil {
+		return nil, err
+	}
 
 	found := false
 	for i := range course.Modules {
@@ -249,6 +387,9 @@ func (s *CourseService) CreateLesson(ctx context.Context, productID primitive.Ob
 		return nil, err
 	}
 	s.invalidateCache(ctx, productID)
+	if s.videoSvc != nil && lesson.Type == domain.LessonTypeVideo {
+		s.videoSvc.Prepare(ctx, creatorID, lesson.Content)
+	}
 
 	return course, nil
 }
@@ -259,6 +400,9 @@ func (s *CourseService) UpdateLesson(ctx context.Context, productID primitive.Ob
 	if err != nil {
 		return nil, err
 	}
+	if err := validateDripRule(course, updatedLesson.Drip, lessonID); err != nil {
+		return nil, err
+	}
 
 	foundMod := false
 	foundLes := false
@@ -289,6 +433,9 @@ func (s *CourseService) UpdateLesson(ctx context.Context, productID primitive.Ob
 		return nil, err
 	}
 	s.invalidateCache(ctx, productID)
+	if s.videoSvc != nil && updatedLesson.Type == domain.LessonTypeVideo {
+		s.videoSvc.Prepare(ctx, creatorID, updatedLesson.Content)
+	}
 
 	return course, nil
 }
@@ -342,6 +489,25 @@ func (s *CourseService) ReorderStructure(ctx context.Context, productID primitiv
 
 	// Could add validation here that no modules/lessons were lost or added that are unknown,
 	// but for now trusting the creator UI provided structure IDs.
+	// Drip rules are edited through the module/lesson endpoints, so keep any the payload omits.
+	existingModDrip := make(map[string]*domain.DripRule)
+	existingLesDrip := make(map[string]*domain.DripRule)
+	for _, mod := range course.Modules {
+		existingModDrip[mod.ID] = mod.Drip
+		for _, les := range mod.Lessons {
+			existingLesDrip[les.ID] = les.Drip
+		}
+	}
+	for i := range modules {
+		if modules[i].Drip == nil {
+			modules[i].Drip = existingModDrip[modules[i].ID]
+		}
+		for j := range modules[i].Lessons {
+			if modules[i].Lessons[j].Drip == nil {
+				modules[i].Lessons[j].Drip = existingLesDrip[modules[i].Lessons[j].ID]
+			}
+		}
+	}
 	course.Modules = modules
 	if err := s.courseRepo.Update(ctx, course); err != nil {
 		return nil, err
@@ -350,3 +516,239 @@ func (s *CourseService) ReorderStructure(ctx context.Context, productID primitiv
 
 	return course, nil
 }
+
+// UpdateLessonProgress records a buyer's progress on a lesson: completion and/or the last
+// playback position for video lessons. Nil arguments leave the stored value untouched.
+func (s *CourseService) UpdateLessonProgress(ctx context.Context, productID primitive.ObjectID, buyerID primitive.ObjectID, lessonID string, completed *bool, positionSeconds *int) (*domain.CourseProgress, error) {
+	if s.progressRepo == nil {
+		return nil, errors.New("course progress tracking is not configured")
+	}
+	purchasedAt, err := s.verifyPurchase(ctx, productID, buyerID)
+	if err != nil {
+		return nil, err
+	}
+
+	course, err := s.loadCourse(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if !courseHasLesson(course, lessonID) {
+		return nil, ErrLessonNotFound
+	}
+
+	progress, err := s.progressRepo.FindByUserAndProduct(ctx, buyerID, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	unlockedBefore := unlockedLessons(course, purchasedAt, progress, now)
+	if !unlockedBefore[lessonID] {
+		return nil, ErrLessonLocked
+	}
+
+	progress, err = s.progressRepo.UpdateLesson(ctx, buyerID, productID, course.CreatorID, domain.LessonProgressUpdate{
+		LessonID:        lessonID,
+		Completed:       completed,
+		PositionSeconds: positionSeconds,
+		At:              now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	progress.CompletionPercent = courseCompletionPercent(course, progress)
+	justCompleted, err := s.progressRepo.SetCompletion(ctx, buyerID, productID, progress.CompletionPercent, now)
+	if err != nil {
+		return nil, err
+	}
+	if justCompleted {
+		progress.CompletedAt = &now
+	} else if progress.CompletionPercent < 100 {
+		progress.CompletedAt = nil
+	}
+
+	if justCompleted && len(s.completionHooks) > 0 {
+		completed := *progress
+		go func() {
+			bgCtx := context.Background()
+			for _, hook := range s.completionHooks {
+				if err := hook(bgCtx, &completed); err != nil {
+					logger.Error("course completion hook failed", "error", err, "product_id", productID.Hex())
+				}
+			}
+		}()
+	}
+
+	// Completing a prerequisite may have unlocked further lessons
+	unlockedAfter := unlockedLessons(course, purchasedAt, progress, now)
+	var newlyUnlocked []domain.Lesson
+	for _, les := range allLessons(course) {
+		if !unlockedBefore[les.ID] && unlockedAfter[les.ID] {
+			newlyUnlocked = append(newlyUnlocked, les)
+		}
+	}
+	if len(newlyUnlocked) > 0 {
+		if buyer, err := s.userRepo.FindByID(ctx, buyerID.Hex()); err == nil && buyer != nil {
+			go s.sendUnlockEmail(context.Background(), buyer.Email, productID, newlyUnlocked)
+		}
+	}
+
+	return progress, nil
+}
+
+// GetCourseProgress returns a buyer's progress for a course so the player can resume where they left off.
+func (s *CourseService) GetCourseProgress(ctx context.Context, productID primitive.ObjectID, buyerID primitive.ObjectID) (*domain.CourseProgress, error) {
+	if s.progressRepo == nil {
+		return nil, errors.New("course progress tracking is not configured")
+	}
+	if _, err := s.verifyPurchase(ctx, productID, buyerID); err != nil {
+		return nil, err
+	}
+
+	progress, err := s.progressRepo.FindByUserAndProduct(ctx, buyerID, productID)
+	if err != nil {
+		return nil, err
+	}
+	if progress == nil {
+		return &domain.CourseProgress{
+			UserID:    buyerID,
+			ProductID: productID,
+			Lessons:   []domain.LessonProgress{},
+		}, nil
+	}
+
+	// Lessons may have been added or removed since the last update, so recompute against the live structure
+	if course, err := s.loadCourse(ctx, productID); err == nil {
+		progress.CompletionPercent = courseCompletionPercent(course, progress)
+	}
+	return progress, nil
+}
+
+// GetBuyerCourseProgress returns the completion percentage of every course the buyer has started, keyed by product ID.
+func (s *CourseService) GetBuyerCourseProgress(ctx context.Context, buyerID primitive.ObjectID) (map[string]int, error) {
+	result := map[string]int{}
+	if s.progressRepo == nil {
+		return result, nil
+	}
+
+	records, err := s.progressRepo.FindByUser(ctx, buyerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range records {
+		// Recompute against the live structure, as GetCourseProgress does
+		percent := p.CompletionPercent
+		if course, err := s.loadCourse(ctx, p.ProductID); err == nil {
+			percent = courseCompletionPercent(course, p)
+		}
+		result[p.ProductID.Hex()] = percent
+	}
+	return result, nil
+}
+
+// GetCourseAnalytics reports learner progress for a creator's course, including drop-off per lesson.
+func (s *CourseService) GetCourseAnalytics(ctx context.Context, productID primitive.ObjectID, creatorID primitive.ObjectID) (*domain.CourseProgressAnalytics, error) {
+	if s.progressRepo == nil {
+		return nil, errors.New("course progress tracking is not configured")
+	}
+
+	course, err := s.findCourse(ctx, productID, creatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := s.progressRepo.FindByProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.CourseProgressAnalytics{
+		ProductID: productID,
+		Learners:  len(records),
+		Lessons:   []domain.LessonDropOff{},
+	}
+
+	totalPercent := 0
+	for _, p := range records {
+		percent := courseCompletionPercent(course, p)
+		totalPercent += percent
+		if percent == 100 {
+			report.CompletedCount++
+		}
+	}
+	if len(records) > 0 {
+		report.AvgCompletion = float64(totalPercent) / float64(len(records))
+	}
+
+	prevLessonID := ""
+	for _, mod := range course.Modules {
+		for _, les := range mod.Lessons {
+			stat := domain.LessonDropOff{
+				ModuleID: mod.ID,
+				LessonID: les.ID,
+				Title:    les.Title,
+			}
+
+			reached, dropped := 0, 0
+			for _, p := range records {
+				entry := p.Lesson(les.ID)
+				if entry != nil {
+					stat.Started++
+					if entry.Completed {
+						stat.Completed++
+					}
+				}
+
+				// A learner "reached" this lesson if they finished the previous one (every learner reaches the first)
+				if prevLessonID != "" {
+					prev := p.Lesson(prevLessonID)
+					if prev == nil || !prev.Completed {
+						continue
+					}
+				}
+				reached++
+				if entry == nil || !entry.Completed {
+					dropped++
+				}
+			}
+			if reached > 0 {
+				stat.DropOffPercent = float64(dropped) / float64(reached) * 100
+			}
+
+			report.Lessons = append(report.Lessons, stat)
+			prevLessonID = les.ID
+		}
+	}
+
+	return report, nil
+}
+
+// courseHasLesson reports whether the lesson exists anywhere in the course.
+func courseHasLesson(course *domain.Course, lessonID string) bool {
+	for _, mod := range course.Modules {
+		for _, les := range mod.Lessons {
+			if les.ID == lessonID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// courseCompletionPercent computes the share of the course's current lessons the buyer has completed.
+func courseCompletionPercent(course *domain.Course, progress *domain.CourseProgress) int {
+	total, done := 0, 0
+	for _, mod := range course.Modules {
+		for _, les := range mod.Lessons {
+			total++
+			if entry := progress.Lesson(les.ID); entry != nil && entry.Completed {
+				done++
+			}
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return done * 100 / total
+}
@@ -0,0 +1,591 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+var (
+	ErrWebhookEndpointNotFound = errors.New("webhook endpoint not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrInvalidWebhookURL       = errors.New("webhook URL must be a public https:// address")
+	ErrInvalidWebhookEvents    = errors.New("choose at least one valid event")
+	ErrWebhookEndpointLimit    = errors.New("you have reached the maximum number of webhook endpoints")
+	errWebhookPrivateAddress   = errors.New("webhook URL resolves to a private address")
+)
+
+const (
+	maxWebhookEndpoints       = 10
+	webhookMaxAttempts        = 8           // Automatic attempts per delivery
+	webhookBaseBackoff        = time.Minute // Doubles after each failed attempt: 1m, 2m, 4m ... about 4h in total
+	webhookTimeout            = 10 * time.Second
+	webhookDisableAfter       = 72 * time.Hour // Endpoints failing for this long are disabled...
+	webhookDisableMinFailures = 10             // ...once they have failed at least this many times
+	webhookMaxResponseRead    = 4 << 10
+
+	// WebhookSignatureHeader carries "t=<unix time>,v1=<hex HMAC-SHA256 of "<t>.<body>">".
+	WebhookSignatureHeader = "X-Mio-Signature"
+)
+
+// WebhookEndpointUpdate holds the endpoint fields a creator can change. Nil fields are left as they are.
+type WebhookEndpointUpdate struct {
+	URL         *string  `json:"url"`
+	Description *string  `json:"description"`
+	Events      []string `json:"events"`
+	Enabled     *bool    `json:"enabled"`
+}
+
+// webhookEvent is the JSON body sent to endpoints.
+type webhookEvent struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// CreatorWebhookService sends signed store events to HTTPS endpoints registered by creators
+// and keeps a log of every delivery.
+type CreatorWebhookService struct {
+	endpointRepo domain.WebhookEndpointRepository
+	deliveryRepo domain.WebhookDeliveryRepository
+	userRepo     domain.UserRepository
+	emailService domain.EmailService
+	workerClient *asynq.Client
+	httpClient   *http.Client
+	frontendURL  string
+}
+
+// NewCreatorWebhookService creates a new CreatorWebhookService.
+func NewCreatorWebhookService(endpointRepo domain.WebhookEndpointRepository, deliveryRepo domain.WebhookDeliveryRepository, userRepo domain.UserRepository, frontendURL string) *CreatorWebhookService {
+	return &CreatorWebhookService{
+		endpointRepo: endpointRepo,
+		deliveryRepo: deliveryRepo,
+		userRepo:     userRepo,
+		httpClient:   newWebhookHTTPClient(),
+		frontendURL:  strings.TrimRight(frontendURL, "/"),
+	}
+}
+
+// SetWorkerClient delivers events through the background queue. Without it deliveries and
+// retries run in-process.
+func (s *CreatorWebhookService) SetWorkerClient(client *asynq.Client) {
+	s.workerClient = client
+}
+
+// SetEmailService lets the service tell creators when an endpoint is disabled.
+func (s *CreatorWebhookService) SetEmailService(emailService domain.EmailService) {
+	s.emailService = emailService
+}
+
+// CreateEndpoint registers a new endpoint. The returned signing secret is shown to the creator
+// once; it can be rotated later.
+func (s *CreatorWebhookService) CreateEndpoint(ctx context.Context, creatorID, rawURL, description string, events []string) (*domain.WebhookEndpoint, string, error) {
+	creatorOID, err := primitive.ObjectIDFromHex(creatorID)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid creator id")
+	}
+	endpointURL, err := validateWebhookURL(rawURL)
+	if err != nil {
+		return nil, "", err
+	}
+	events, err = normaliseWebhookEvents(events)
+	if err != nil {
+		return nil, "", err
+	}
+
+	existing, err := s.endpointRepo.FindByCreator(ctx, creatorOID)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(existing) >= maxWebhookEndpoints {
+		return nil, "", ErrWebhookEndpointLimit
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	endpoint := &domain.WebhookEndpoint{
+		CreatorID:   creatorOID,
+		URL:         endpointURL,
+		Description: strings.TrimSpace(description),
+		Events:      events,
+		Secret:      secret,
+		Enabled:     true,
+	}
+	if err := s.endpointRepo.Create(ctx, endpoint); err != nil {
+		return nil, "", err
+	}
+	return endpoint, secret, nil
+}
+
+// ListEndpoints returns the creator's endpoints.
+func (s *CreatorWebhookService) ListEndpoints(ctx context.Context, creatorID string) ([]*domain.WebhookEndpoint, error) {
+	creatorOID, err := primitive.ObjectIDFromHex(creatorID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid creator id")
+	}
+	endpoints, err := s.endpointRepo.FindByCreator(ctx, creatorOID)
+	if err != nil {
+		return nil, err
+	}
+	return emptyIfNil(endpoints), nil
+}
+
+// UpdateEndpoint changes an endpoint. Re-enabling it clears its failure streak.
+func (s *CreatorWebhookService) UpdateEndpoint(ctx context.Context, creatorID, endpointID string, update WebhookEndpointUpdate) (*domain.WebhookEndpoint, error) {
+	endpoint, err := s.ownedEndpoint(ctx, creatorID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.URL != nil {
+		if endpoint.URL, err = validateWebhookURL(*update.URL); err != nil {
+			return nil, err
+		}
+	}
+	if update.Description != nil {
+		endpoint.Description = strings.TrimSpace(*update.Description)
+	}
+	if update.Events != nil {
+		if endpoint.Events, err = normaliseWebhookEvents(update.Events); err != nil {
+			return nil, err
+		}
+	}
+	if update.Enabled != nil {
+		if *update.Enabled && !endpoint.Enabled {
+			endpoint.DisabledReason = ""
+			endpoint.ConsecutiveFailures = 0
+			endpoint.FailingSince = nil
+		}
+		endpoint.Enabled = *update.Enabled
+	}
+
+	if err := s.endpointRepo.Update(ctx, endpoint); err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// RotateSecret replaces an endpoint's signing secret and returns the new one.
+func (s *CreatorWebhookService) RotateSecret(ctx context.Context, creatorID, endpointID string) (string, error) {
+	endpoint, err := s.ownedEndpoint(ctx, creatorID, endpointID)
+	if err != nil {
+		return "", err
+	}
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return "", err
+	}
+	endpoint.Secret = secret
+	if err := s.endpointRepo.Update(ctx, endpoint); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// DeleteEndpoint removes an endpoint and its delivery log.
+func (s *CreatorWebhookService) DeleteEndpoint(ctx context.Context, creatorID, endpointID string) error {
+	endpoint, err := s.ownedEndpoint(ctx, creatorID, endpointID)
+	if err != nil {
+		return err
+	}
+	if err := s.endpointRepo.Delete(ctx, endpoint.ID); err != nil {
+		return err
+	}
+	if err := s.deliveryRepo.DeleteByEndpoint(ctx, endpoint.ID); err != nil {
+		logger.Error("failed to delete webhook deliveries", "endpoint_id", endpoint.ID.Hex(), "error", err)
+	}
+	return nil
+}
+
+// ListDeliveries returns a page of an endpoint's delivery log, newest first.
+func (s *CreatorWebhookService) ListDeliveries(ctx context.Context, creatorID, endpointID string, pagination *domain.Pagination) ([]*domain.WebhookDelivery, *domain.PaginationMeta, error) {
+	endpoint, err := s.ownedEndpoint(ctx, creatorID, endpointID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pagination.Page < 1 {
+		pagination.Page = 1
+	}
+	if pagination.PageSize < 1 || pagination.PageSize > 100 {
+		pagination.PageSize = 25
+	}
+
+	deliveries, total, err := s.deliveryRepo.FindByEndpoint(ctx, endpoint.ID, pagination.PageSize, (pagination.Page-1)*pagination.PageSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	return emptyIfNil(deliveries), &domain.PaginationMeta{
+		Page:       pagination.Page,
+		PageSize:   pagination.PageSize,
+		TotalCount: total,
+		TotalPages: (total + pagination.PageSize - 1) / pagination.PageSize,
+	}, nil
+}
+
+// Redeliver sends a logged delivery again, right away, and returns it with the new attempt.
+// It works for disabled endpoints too, so creators can check a fix before re-enabling.
+func (s *CreatorWebhookService) Redeliver(ctx context.Context, creatorID, deliveryID string) (*domain.WebhookDelivery, error) {
+	creatorOID, err := primitive.ObjectIDFromHex(creatorID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid creator id")
+	}
+	oid, err := primitive.ObjectIDFromHex(deliveryID)
+	if err != nil {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	delivery, err := s.deliveryRepo.FindByID(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil || delivery.CreatorID != creatorOID {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	endpoint, err := s.endpointRepo.FindByID(ctx, delivery.EndpointID)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint == nil {
+		return nil, ErrWebhookEndpointNotFound
+	}
+
+	if err := s.attempt(ctx, endpoint, delivery, 0, true); err != nil {
+		return nil, err
+	}
+	return s.deliveryRepo.FindByID(ctx, oid)
+}
+
+// Publish sends event to every enabled endpoint of the creator subscribed to it. data is
+// encoded straight away and delivered in the background. Safe to call on a nil service.
+func (s *CreatorWebhookService) Publish(creatorID primitive.ObjectID, event string, data interface{}) {
+	if s == nil || creatorID.IsZero() {
+		return
+	}
+	eventID := "evt_" + primitive.NewObjectID().Hex()
+	payload, err := json.Marshal(webhookEvent{ID: eventID, Type: event, CreatedAt: time.Now().UTC(), Data: data})
+	if err != nil {
+		logger.Error("failed to encode webhook event", "event", event, "error", err)
+		return
+	}
+
+	go func() {
+		ctx := context.Background()
+		endpoints, err := s.endpointRepo.FindEnabledForEvent(ctx, creatorID, event)
+		if err != nil {
+			logger.Error("failed to find webhook endpoints", "creator_id", creatorID.Hex(), "event", event, "error", err)
+			return
+		}
+
+		for _, endpoint := range endpoints {
+			delivery := &domain.WebhookDelivery{
+				EndpointID: endpoint.ID,
+				CreatorID:  creatorID,
+				EventID:    eventID,
+				EventType:  event,
+				Payload:    string(payload),
+				Status:     domain.WebhookDeliveryPending,
+			}
+			if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+				logger.Error("failed to log webhook delivery", "endpoint_id", endpoint.ID.Hex(), "error", err)
+				continue
+			}
+			s.schedule(delivery.ID.Hex(), 1, 0)
+		}
+	}()
+}
+
+// Deliver makes automatic attempt number n at a delivery. Failed attempts are rescheduled
+// with exponential backoff until the attempts run out.
+func (s *CreatorWebhookService) Deliver(ctx context.Context, deliveryID string, n int) error {
+	oid, err := primitive.ObjectIDFromHex(deliveryID)
+	if err != nil {
+		return ErrWebhookDeliveryNotFound
+	}
+	delivery, err := s.deliveryRepo.FindByID(ctx, oid)
+	if err != nil {
+		return err
+	}
+	// Gone from the log, or already delivered by a manual redelivery
+	if delivery == nil || delivery.Status != domain.WebhookDeliveryPending {
+		return nil
+	}
+
+	endpoint, err := s.endpointRepo.FindByID(ctx, delivery.EndpointID)
+	if err != nil {
+		return err
+	}
+	if endpoint == nil || !endpoint.Enabled {
+		reason := "endpoint deleted"
+		if endpoint != nil {
+			reason = "endpoint disabled"
+		}
+		return s.deliveryRepo.RecordAttempt(ctx, delivery.ID, domain.WebhookAttempt{At: time.Now(), Error: reason},
+			domain.WebhookDeliveryFailed, nil)
+	}
+	return s.attempt(ctx, endpoint, delivery, n, false)
+}
+
+// attempt sends a delivery once and records the outcome. Manual attempts are never retried.
+func (s *CreatorWebhookService) attempt(ctx context.Context, endpoint *domain.WebhookEndpoint, delivery *domain.WebhookDelivery, n int, manual bool) error {
+	result := s.send(ctx, endpoint, delivery)
+	result.Manual = manual
+
+	if result.Error == "" {
+		if err := s.deliveryRepo.RecordAttempt(ctx, delivery.ID, result, domain.WebhookDeliverySucceeded, nil); err != nil {
+			return err
+		}
+		return s.endpointRepo.RecordSuccess(ctx, endpoint.ID)
+	}
+
+	status := domain.WebhookDeliveryFailed
+	var next *time.Time
+	var backoff time.Duration
+	switch {
+	case manual:
+		// A failed redelivery leaves a pending automatic retry in place
+		if delivery.Status == domain.WebhookDeliveryPending {
+			status = domain.WebhookDeliveryPending
+			next = delivery.NextAttemptAt
+		}
+	case n < webhookMaxAttempts:
+		status = domain.WebhookDeliveryPending
+		backoff = webhookBaseBackoff << (n - 1)
+		at := time.Now().Add(backoff)
+		next = &at
+	}
+	if err := s.deliveryRepo.RecordAttempt(ctx, delivery.ID, result, status, next); err != nil {
+		return err
+	}
+	if backoff > 0 {
+		s.schedule(delivery.ID.Hex(), n+1, backoff)
+	}
+
+	updated, err := s.endpointRepo.RecordFailure(ctx, endpoint.ID, result.At)
+	if err != nil {
+		return err
+	}
+	if updated != nil && updated.Enabled && updated.FailingSince != nil &&
+		updated.ConsecutiveFailures >= webhookDisableMinFailures && time.Since(*updated.FailingSince) >= webhookDisableAfter {
+		s.disable(ctx, updated)
+	}
+	return nil
+}
+
+// send POSTs the delivery's payload to the endpoint and describes the outcome.
+func (s *CreatorWebhookService) send(ctx context.Context, endpoint *domain.WebhookEndpoint, delivery *domain.WebhookDelivery) domain.WebhookAttempt {
+	start := time.Now()
+	result := domain.WebhookAttempt{At: start}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	timestamp := strconv.FormatInt(start.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "MioStore-Webhooks/1.0")
+	req.Header.Set("X-Mio-Event", delivery.EventType)
+	req.Header.Set("X-Mio-Event-Id", delivery.EventID)
+	req.Header.Set("X-Mio-Delivery", delivery.ID.Hex())
+	req.Header.Set(WebhookSignatureHeader, "t="+timestamp+",v1="+signWebhookPayload(endpoint.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.httpClient.Do(req)
+	result.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Error = err.Error()
+		if errors.Is(err, errWebhookPrivateAddress) {
+			result.Error = errWebhookPrivateAddress.Error()
+		}
+		return result
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, webhookMaxResponseRead))
+
+	result.ResponseStatus = resp.StatusCode
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		result.Error = fmt.Sprintf("endpoint responded %d", resp.StatusCode)
+	}
+	return result
+}
+
+// schedule queues attempt n of a delivery after delay.
+func (s *CreatorWebhookService) schedule(deliveryID string, n int, delay time.Duration) {
+	if s.workerClient != nil {
+		err := EnqueueWebhookDeliveryTask(s.workerClient, deliveryID, n, delay)
+		if err == nil || errors.Is(err, asynq.ErrTaskIDConflict) {
+			return
+		}
+		logger.Error("failed to enqueue webhook delivery, sending in-process", "delivery_id", deliveryID, "error", err)
+	}
+	time.AfterFunc(delay, func() {
+		if err := s.Deliver(context.Background(), deliveryID, n); err != nil {
+			logger.Error("webhook delivery failed", "delivery_id", deliveryID, "error", err)
+		}
+	})
+}
+
+// disable turns off an endpoint that keeps failing and lets the creator know.
+func (s *CreatorWebhookService) disable(ctx context.Context, endpoint *domain.WebhookEndpoint) {
+	reason := fmt.Sprintf("Disabled after %d failed deliveries since %s", endpoint.ConsecutiveFailures,
+		endpoint.FailingSince.UTC().Format("2 January 2006"))
+	if err := s.endpointRepo.Disable(ctx, endpoint.ID, reason); err != nil {
+		logger.Error("failed to disable webhook endpoint", "endpoint_id", endpoint.ID.Hex(), "error", err)
+		return
+	}
+	logger.Warn("webhook endpoint disabled", "endpoint_id", endpoint.ID.Hex(), "failures", endpoint.ConsecutiveFailures)
+
+	if s.emailService == nil {
+		return
+	}
+	creator, err := s.userRepo.FindByID(ctx, endpoint.CreatorID.Hex())
+	if err != nil || creator == nil {
+		return
+	}
+	body := fmt.Sprintf(
+		"<div style='font-family:sans-serif;max-width:480px;margin:0 auto;padding:32px;'>"+
+			"<h2 style='color:#6C5CE7;'>Webhook endpoint disabled</h2>"+
+			"<p>We stopped sending events to <strong>%s</strong> because it has been failing since %s. "+
+			"Check the delivery log, fix the endpoint and turn it back on from your settings.</p>"+
+			"<p style='text-align:center;margin:24px 0;'><a href='%s' style='background:#6C5CE7;color:#fff;padding:12px 24px;border-radius:8px;text-decoration:none;font-weight:bold;'>View webhooks</a></p></div>",
+		html.EscapeString(endpoint.URL), endpoint.FailingSince.UTC().Format("2 January 2006"),
+		html.EscapeString(s.frontendURL+"/dashboard/settings"),
+	)
+	if err := s.emailService.Send(ctx, creator.Email, "Your webhook endpoint was disabled", body); err != nil {
+		logger.Error("failed to send webhook disabled email", "endpoint_id", endpoint.ID.Hex(), "error", err)
+	}
+}
+
+func (s *CreatorWebhookService) ownedEndpoint(ctx context.Context, creatorID, endpointID string) (*domain.WebhookEndpoint, error) {
+	creatorOID, err := primitive.ObjectIDFromHex(creatorID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid creator id")
+	}
+	oid, err := primitive.ObjectIDFromHex(endpointID)
+	if err != nil {
+		return nil, ErrWebhookEndpointNotFound
+	}
+	endpoint, err := s.endpointRepo.FindByID(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint == nil || endpoint.CreatorID != creatorOID {
+		return nil, ErrWebhookEndpointNotFound
+	}
+	return endpoint, nil
+}
+
+// signWebhookPayload is the hex HMAC-SHA256 of "<timestamp>.<payload>" keyed with secret.
+func signWebhookPayload(secret, timestamp, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newWebhookSecret() (string, error) {
+	token, err := randomToken(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return "", err
+	}
+	return "whsec_" + token, nil
+}
+
+// validateWebhookURL accepts absolute https URLs whose host isn't obviously internal. Hosts
+// are checked again when each request connects, after DNS resolution.
+func validateWebhookURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil {
+		return "", ErrInvalidWebhookURL
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return "", ErrInvalidWebhookURL
+	}
+	if ip := net.ParseIP(host); ip != nil && !isPublicIP(ip) {
+		return "", ErrInvalidWebhookURL
+	}
+	return u.String(), nil
+}
+
+func normaliseWebhookEvents(events []string) ([]string, error) {
+	seen := make(map[string]bool, len(events))
+	out := make([]string, 0, len(events))
+	for _, event := range events {
+		event = strings.TrimSpace(event)
+		if !domain.IsValidWebhookEvent(event) {
+			return nil, ErrInvalidWebhookEvents
+		}
+		if !seen[event] {
+			seen[event] = true
+			out = append(out, event)
+		}
+	}
+	if len(out) == 0 {
+		return nil, ErrInvalidWebhookEvents
+	}
+	return out, nil
+}
+
+// newWebhookHTTPClient returns a client that only connects to public addresses and doesn't
+// follow redirects, so creator-supplied URLs can't reach internal services.
+func newWebhookHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return errWebhookPrivateAddress
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: webhookTimeout,
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   5 * time.Second,
+			ResponseHeaderTimeout: webhookTimeout,
+			MaxIdleConnsPerHost:   2,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsMulticast() || ip.IsInterfaceLocalMulticast() ||
+		// Carrier-grade NAT, often used for cloud metadata and internal networks
+		(ip.To4() != nil && ip.To4()[0] == 100 && ip.To4()[1]&0xc0 == 64))
+}
@@ -0,0 +1,369 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+var (
+	ErrInvalidDownloadToken = errors.New("invalid or expired download link")
+	ErrDownloadLocked       = errors.New("download link has been locked")
+	ErrDownloadExpired      = errors.New("download access has expired")
+	ErrDownloadLimitReached = errors.New("download limit reached")
+	ErrEntitlementNotFound  = errors.New("download entitlement not found")
+	ErrDownloadPreparing    = errors.New("your personalised copy is being prepared")
+	ErrProductFileNotFound  = errors.New("file not found")
+)
+
+const (
+	// defaultDownloadTokenTTL bounds email links for products without an expiry window.
+	defaultDownloadTokenTTL = 30 * 24 * time.Hour
+	// fileURLTTL is how long the storage URL handed out after a successful check stays valid.
+	fileURLTTL = 5 * time.Minute
+	// shareWindow and shareIPThreshold define "shared widely": this many distinct IPs within the window.
+	shareWindow      = 24 * time.Hour
+	shareIPThreshold = 5
+)
+
+// DownloadService issues signed download links and enforces per-purchase download limits.
+type DownloadService struct {
+	entRepo     domain.DownloadEntitlementRepository
+	logRepo     domain.DownloadLogRepository
+	productRepo domain.ProductRepository
+	orderRepo   domain.OrderRepository
+	userRepo    domain.UserRepository
+	storage     domain.FileStorage
+	emailSvc    domain.EmailService
+	worker      *asynq.Client
+	secret      []byte
+	apiBaseURL  string
+}
+
+// deriveKey returns the HMAC key for one purpose, so a secret shared with other signers
+// (by default the JWT secret) never signs two kinds of token with the same key.
+func deriveKey(secret, purpose string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+// NewDownloadService creates a new DownloadService. Links are signed with a key derived from secret.
+func NewDownloadService(
+	entRepo domain.DownloadEntitlementRepository,
+	logRepo domain.DownloadLogRepository,
+	productRepo domain.ProductRepository,
+	orderRepo domain.OrderRepository,
+	userRepo domain.UserRepository,
+	storage domain.FileStorage,
+	secret string,
+	apiBaseURL string,
+) *DownloadService {
+	return &DownloadService{
+		entRepo:     entRepo,
+		logRepo:     logRepo,
+		productRepo: productRepo,
+		orderRepo:   orderRepo,
+		userRepo:    userRepo,
+		storage:     storage,
+		secret:      deriveKey(secret, "downloads"),
+		apiBaseURL:  strings.TrimRight(apiBaseURL, "/"),
+	}
+}
+
+// SetEmailService enables alert emails to creators when a link is locked for sharing,
+// and update emails to buyers when a file is replaced.
+func (s *DownloadService) SetEmailService(emailSvc domain.EmailService) {
+	s.emailSvc = emailSvc
+}
+
+// SetWorkerClient lets large PDFs be stamped in the background.
+func (s *DownloadService) SetWorkerClient(client *asynq.Client) {
+	s.worker = client
+}
+
+// EnsureEntitlement returns the entitlement for a purchased product, creating it from the
+// product's download settings on first use.
+func (s *DownloadService) EnsureEntitlement(ctx context.Context, order *domain.Order, product *domain.Product) (*domain.DownloadEntitlement, error) {
+	ent := &domain.DownloadEntitlement{
+		OrderID:      order.ID,
+		ProductID:    product.ID,
+		CreatorID:    product.CreatorID,
+		BuyerEmail:   strings.ToLower(strings.TrimSpace(order.CustomerEmail)),
+		BuyerName:    order.CustomerName,
+		MaxDownloads: product.DownloadLimit,
+	}
+	if product.DownloadExpiryDays > 0 {
+		expiresAt := order.CreatedAt.AddDate(0, 0, product.DownloadExpiryDays)
+		ent.ExpiresAt = &expiresAt
+	}
+	return s.entRepo.FindOrCreate(ctx, ent)
+}
+
+// PrepareDownloads creates the buyer's entitlement and starts personalising stamped files so
+// they are ready when the buyer opens the order page.
+func (s *DownloadService) PrepareDownloads(ctx context.Context, order *domain.Order, product *domain.Product) error {
+	ent, err := s.EnsureEntitlement(ctx, order, product)
+	if err != nil {
+		return err
+	}
+	s.enqueueStamps(ent, product)
+	return nil
+}
+
+// EmailDownloadURL returns a signed link to one of the product's files for use in emails.
+// A zero fileID selects the product's first file.
+func (s *DownloadService) EmailDownloadURL(ctx context.Context, order *domain.Order, product *domain.Product, fileID primitive.ObjectID) (string, error) {
+	file := product.DownloadFile(fileID)
+	if file == nil {
+		return "", ErrProductFileNotFound
+	}
+	ent, err := s.EnsureEntitlement(ctx, order, product)
+	if err != nil {
+		return "", err
+	}
+
+	// Prepare personalised copies before the buyer clicks the link.
+	s.enqueueStamps(ent, product)
+
+	expiresAt := time.Now().Add(defaultDownloadTokenTTL)
+	if ent.ExpiresAt != nil {
+		expiresAt = *ent.ExpiresAt
+	}
+	return fmt.Sprintf("%s/api/v1/downloads/%s", s.apiBaseURL, s.signToken(ent, file.ID, expiresAt)), nil
+}
+
+// RedeemToken validates a signed link and, if the buyer still has downloads left,
+// returns a short-lived storage URL for the latest version of the file.
+func (s *DownloadService) RedeemToken(ctx context.Context, token, ip, userAgent string) (string, error) {
+	ent, fileID, err := s.verifyToken(ctx, token)
+	if err != nil {
+		return "", err
+	}
+
+	product, err := s.productRepo.FindByID(ctx, ent.ProductID)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch product: %w", err)
+	}
+	if product == nil {
+		return "", ErrProductFileNotFound
+	}
+	file := product.DownloadFile(fileID)
+	if file == nil {
+		return "", ErrProductFileNotFound
+	}
+
+	return s.consume(ctx, ent, product, file, ip, userAgent, domain.DownloadSourceEmailLink)
+}
+
+// Redeem counts a download made from the order page and returns a short-lived storage URL
+// for the latest version of the file. A zero fileID selects the product's first file.
+func (s *DownloadService) Redeem(ctx context.Context, order *domain.Order, product *domain.Product, fileID primitive.ObjectID, ip, userAgent string) (string, error) {
+	file := product.DownloadFile(fileID)
+	if file == nil {
+		return "", ErrProductFileNotFound
+	}
+	ent, err := s.EnsureEntitlement(ctx, order, product)
+	if err != nil {
+		return "", err
+	}
+	return s.consume(ctx, ent, product, file, ip, userAgent, domain.DownloadSourceOrderPage)
+}
+
+// GetLogs returns the creator's download audit log.
+func (s *DownloadService) GetLogs(ctx context.Context, creatorID primitive.ObjectID, filter domain.DownloadLogFilter) ([]*domain.DownloadLog, error) {
+	return s.logRepo.FindByCreator(ctx, creatorID, filter)
+}
+
+// UnlockEntitlement lets a creator restore access after a lockout or an exhausted limit.
+func (s *DownloadService) UnlockEntitlement(ctx context.Context, entitlementID, creatorID primitive.ObjectID) error {
+	ent, err := s.entRepo.FindByID(ctx, entitlementID)
+	if err != nil {
+		return err
+	}
+	if ent == nil || ent.CreatorID != creatorID {
+		return ErrEntitlementNotFound
+	}
+	return s.entRepo.Unlock(ctx, entitlementID)
+}
+
+func (s *DownloadService) consume(ctx context.Context, ent *domain.DownloadEntitlement, product *domain.Product, file *domain.ProductFile, ip, userAgent, source string) (string, error) {
+	entry := &domain.DownloadLog{
+		EntitlementID: ent.ID,
+		OrderID:       ent.OrderID,
+		ProductID:     ent.ProductID,
+		FileID:        file.ID,
+		FileVersion:   file.Version,
+		CreatorID:     ent.CreatorID,
+		BuyerEmail:    ent.BuyerEmail,
+		IP:            ip,
+		UserAgent:     userAgent,
+		Source:        source,
+	}
+	deny := func(reason error) (string, error) {
+		entry.DenyReason = reason.Error()
+		if err := s.logRepo.Create(ctx, entry); err != nil {
+			logger.Error("failed to record download attempt", "entitlement_id", ent.ID.Hex(), "error", err)
+		}
+		return "", reason
+	}
+
+	if ent.LockedAt != nil {
+		return deny(ErrDownloadLocked)
+	}
+	if ent.ExpiresAt != nil && time.Now().After(*ent.ExpiresAt) {
+		return deny(ErrDownloadExpired)
+	}
+
+	shared, err := s.isShared(ctx, ent, ip)
+	if err != nil {
+		return "", err
+	}
+	if shared {
+		reason := fmt.Sprintf("downloaded from more than %d IP addresses within %d hours", shareIPThreshold, int(shareWindow.Hours()))
+		if err := s.entRepo.Lock(ctx, ent.ID, reason); err != nil {
+			return "", err
+		}
+		logger.Warn("download entitlement locked for sharing", "entitlement_id", ent.ID.Hex(), "order_id", ent.OrderID.Hex())
+		go s.alertCreator(context.Background(), ent, product, reason)
+		return deny(ErrDownloadLocked)
+	}
+
+	fileKey, err := s.fileKey(ctx, ent, product, file)
+	if err != nil {
+		return "", err
+	}
+
+	ok, err := s.entRepo.Consume(ctx, ent.ID)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return deny(ErrDownloadLimitReached)
+	}
+
+	url, err := s.storage.GeneratePresignedDownloadURL(ctx, fileKey, fileURLTTL)
+	if err != nil {
+		return "", err
+	}
+
+	entry.Allowed = true
+	if err := s.logRepo.Create(ctx, entry); err != nil {
+		logger.Error("failed to record download", "entitlement_id", ent.ID.Hex(), "error", err)
+	}
+	return url, nil
+}
+
+// isShared reports whether a download from ip would push the entitlement past the sharing threshold.
+func (s *DownloadService) isShared(ctx context.Context, ent *domain.DownloadEntitlement, ip string) (bool, error) {
+	ips, err := s.logRepo.DistinctIPsSince(ctx, ent.ID, time.Now().Add(-shareWindow))
+	if err != nil {
+		return false, err
+	}
+	for _, seen := range ips {
+		if seen == ip {
+			return false, nil
+		}
+	}
+	return len(ips)+1 > shareIPThreshold, nil
+}
+
+func (s *DownloadService) alertCreator(ctx context.Context, ent *domain.DownloadEntitlement, product *domain.Product, reason string) {
+	if s.emailSvc == nil {
+		return
+	}
+	creator, err := s.userRepo.FindByID(ctx, ent.CreatorID.Hex())
+	if err != nil || creator == nil {
+		return
+	}
+
+	subject := fmt.Sprintf("Download link locked: %s", product.Title)
+	body := fmt.Sprintf(
+		"<p>We locked the download link for <strong>%s</strong> bought by %s (order %s) because it was %s.</p><p>This usually means the link was shared. You can review the download log and unlock it from your sales dashboard.</p>",
+		html.EscapeString(product.Title), html.EscapeString(ent.BuyerEmail), ent.OrderID.Hex(), reason,
+	)
+	if err := s.emailSvc.Send(ctx, creator.Email, subject, body); err != nil {
+		logger.Error("failed to send download lock alert", "entitlement_id", ent.ID.Hex(), "error", err)
+	}
+}
+
+// signToken builds "<entitlementId>.<fileId>.<expiresUnix>.<signature>". The signature covers
+// the buyer's email, so a token stops working if the entitlement is reassigned.
+func (s *DownloadService) signToken(ent *domain.DownloadEntitlement, fileID primitive.ObjectID, expiresAt time.Time) string {
+	payload := ent.ID.Hex() + "." + fileID.Hex() + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return payload + "." + s.signature(payload, ent.BuyerEmail)
+}
+
+func (s *DownloadService) signature(payload, email string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload + "." + strings.ToLower(email)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// OrderToken signs an order page link. Like download links it is bound to the buyer's email,
+// and it lets the holder list and download the order's files.
+func (s *DownloadService) OrderToken(order *domain.Order) string {
+	return s.signature("order."+order.ID.Hex(), order.CustomerEmail)
+}
+
+// VerifyOrderToken reports whether token was issued by OrderToken for this order.
+func (s *DownloadService) VerifyOrderToken(order *domain.Order, token string) bool {
+	return token != "" && hmac.Equal([]byte(s.OrderToken(order)), []byte(token))
+}
+
+// verifyToken checks a signed link and returns its entitlement and file. Links issued before
+// products had multiple files omit the file ID; they resolve to the zero ID (the first file).
+func (s *DownloadService) verifyToken(ctx context.Context, token string) (*domain.DownloadEntitlement, primitive.ObjectID, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) == 3 {
+		parts = []string{parts[0], "", parts[1], parts[2]}
+	}
+	if len(parts) != 4 {
+		return nil, primitive.NilObjectID, ErrInvalidDownloadToken
+	}
+	entID, err := primitive.ObjectIDFromHex(parts[0])
+	if err != nil {
+		return nil, primitive.NilObjectID, ErrInvalidDownloadToken
+	}
+	var fileID primitive.ObjectID
+	if parts[1] != "" {
+		if fileID, err = primitive.ObjectIDFromHex(parts[1]); err != nil {
+			return nil, primitive.NilObjectID, ErrInvalidDownloadToken
+		}
+	}
+	expires, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return nil, primitive.NilObjectID, ErrInvalidDownloadToken
+	}
+
+	ent, err := s.entRepo.FindByID(ctx, entID)
+	if err != nil {
+		return nil, primitive.NilObjectID, err
+	}
+	if ent == nil {
+		return nil, primitive.NilObjectID, ErrInvalidDownloadToken
+	}
+
+	payload := parts[0] + "." + parts[2]
+	if parts[1] != "" {
+		payload = parts[0] + "." + parts[1] + "." + parts[2]
+	}
+	expected := s.signature(payload, ent.BuyerEmail)
+	if !hmac.Equal([]byte(expected), []byte(parts[3])) {
+		return nil, primitive.NilObjectID, ErrInvalidDownloadToken
+	}
+	return ent, fileID, nil
+}
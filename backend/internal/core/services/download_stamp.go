@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/hibiken/asynq"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+	"github.com/devanshbhargava/stan-store/pkg/pdf"
+)
+
+// inlineStampMaxBytes is the largest PDF stamped during the download request itself;
+// anything bigger is handed to the worker and the buyer is asked to retry shortly.
+const inlineStampMaxBytes = 10 << 20
+
+var stampColor = pdf.Color{R: 0.35, G: 0.35, B: 0.4}
+
+// fileKey returns the storage key to serve for one file of an entitlement: the buyer's
+// personalised copy when the product has stamping enabled, otherwise the file itself.
+func (s *DownloadService) fileKey(ctx context.Context, ent *domain.DownloadEntitlement, product *domain.Product, file *domain.ProductFile) (string, error) {
+	if !product.StampPDF || !isPDFKey(file.FileKey) {
+		return file.FileKey, nil
+	}
+	if stamped, ok := ent.Stamps[domain.StampKey(file.ID, file.Version)]; ok {
+		if stamped.FileKey != "" {
+			return stamped.FileKey, nil
+		}
+		return file.FileKey, nil
+	}
+
+	info, err := s.storage.Head(ctx, file.FileKey)
+	if err != nil {
+		return "", err
+	}
+	if info.Size > inlineStampMaxBytes && s.worker != nil {
+		s.enqueueStamp(ent, file)
+		return "", ErrDownloadPreparing
+	}
+	return s.stamp(ctx, ent, file)
+}
+
+// StampEntitlement generates the personalised copy of the current version of one file.
+// It is run by the background worker for large files and is a no-op once a copy exists.
+func (s *DownloadService) StampEntitlement(ctx context.Context, entitlementID, fileID string) error {
+	id, err := primitive.ObjectIDFromHex(entitlementID)
+	if err != nil {
+		return fmt.Errorf("invalid entitlement ID: %w", err)
+	}
+	fid, err := primitive.ObjectIDFromHex(fileID)
+	if err != nil {
+		return fmt.Errorf("invalid file ID: %w", err)
+	}
+	ent, err := s.entRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if ent == nil {
+		return ErrEntitlementNotFound
+	}
+
+	product, err := s.productRepo.FindByID(ctx, ent.ProductID)
+	if err != nil {
+		return err
+	}
+	if product == nil || !product.StampPDF {
+		return nil
+	}
+	file := product.DownloadFile(fid)
+	if file == nil || !isPDFKey(file.FileKey) {
+		return nil
+	}
+	if _, ok := ent.Stamps[domain.StampKey(file.ID, file.Version)]; ok {
+		return nil
+	}
+	_, err = s.stamp(ctx, ent, file)
+	return err
+}
+
+// stamp writes the buyer's details into the footer of every page and stores the result
+// under a buyer-specific key. Documents that cannot be stamped (e.g. encrypted PDFs) are
+// recorded so the original file is served instead of retrying forever.
+func (s *DownloadService) stamp(ctx context.Context, ent *domain.DownloadEntitlement, file *domain.ProductFile) (string, error) {
+	stampKey := domain.StampKey(file.ID, file.Version)
+
+	src, err := s.storage.Download(ctx, file.FileKey)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := pdf.Stamp(src, stampText(ent), stampColor)
+	if err != nil {
+		logger.Warn("pdf stamping failed, serving original file", "entitlement_id", ent.ID.Hex(), "file_id", file.ID.Hex(), "error", err)
+		if setErr := s.entRepo.SetStampResult(ctx, ent.ID, stampKey, domain.StampedCopy{Error: err.Error()}); setErr != nil {
+			return "", setErr
+		}
+		return file.FileKey, nil
+	}
+
+	key := fmt.Sprintf("creators/%s/stamped/%s/%s.pdf", ent.CreatorID.Hex(), ent.OrderID.Hex(), stampKey)
+	if err := s.storage.Upload(ctx, key, "application/pdf", out); err != nil {
+		return "", fmt.Errorf("failed to store stamped pdf: %w", err)
+	}
+	if err := s.entRepo.SetStampResult(ctx, ent.ID, stampKey, domain.StampedCopy{FileKey: key}); err != nil {
+		return "", err
+	}
+
+	logger.Info("pdf stamped", "entitlement_id", ent.ID.Hex(), "file_id", file.ID.Hex(), "version", file.Version, "bytes", len(out))
+	return key, nil
+}
+
+// enqueueStamps prepares personalised copies of every PDF file of a stamped product.
+func (s *DownloadService) enqueueStamps(ent *domain.DownloadEntitlement, product *domain.Product) {
+	if !product.StampPDF {
+		return
+	}
+	files := product.DownloadFiles()
+	for i := range files {
+		if isPDFKey(files[i].FileKey) {
+			s.enqueueStamp(ent, &files[i])
+		}
+	}
+}
+
+func (s *DownloadService) enqueueStamp(ent *domain.DownloadEntitlement, file *domain.ProductFile) {
+	if s.worker == nil {
+		return
+	}
+	if _, ok := ent.Stamps[domain.StampKey(file.ID, file.Version)]; ok {
+		return
+	}
+	if err := EnqueuePDFStampTask(s.worker, ent.ID.Hex(), file.ID.Hex(), file.Version); err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+		logger.Error("failed to enqueue pdf stamp", "entitlement_id", ent.ID.Hex(), "file_id", file.ID.Hex(), "error", err)
+	}
+}
+
+func stampText(ent *domain.DownloadEntitlement) string {
+	name := pdfName(strings.TrimSpace(ent.BuyerName), ent.BuyerEmail)
+	if name == ent.BuyerEmail {
+		return fmt.Sprintf("Licensed to %s • Order %s", name, ent.OrderID.Hex())
+	}
+	return fmt.Sprintf("Licensed to %s (%s) • Order %s", name, ent.BuyerEmail, ent.OrderID.Hex())
+}
+
+func isPDFKey(key string) bool {
+	return strings.EqualFold(path.Ext(key), ".pdf")
+}
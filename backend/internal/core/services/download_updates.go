@@ -0,0 +1,109 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+// ScheduleFileUpdateNotice emails the product's buyers about a new file version, in the
+// background worker when available.
+func (s *DownloadService) ScheduleFileUpdateNotice(product *domain.Product, file *domain.ProductFile) {
+	if s.worker != nil {
+		err := EnqueueFileUpdateNotifyTask(s.worker, product.ID.Hex(), file.ID.Hex(), file.Version)
+		if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+			logger.Error("failed to enqueue file update notice", "product_id", product.ID.Hex(), "file_id", file.ID.Hex(), "error", err)
+		}
+		return
+	}
+	go func() {
+		if err := s.NotifyFileUpdate(context.Background(), product.ID.Hex(), file.ID.Hex(), file.Version); err != nil {
+			logger.Error("failed to notify buyers of file update", "product_id", product.ID.Hex(), "file_id", file.ID.Hex(), "error", err)
+		}
+	}()
+}
+
+// NotifyFileUpdate sends each buyer of the product a signed link to the new version of a file.
+// Buyers whose access is locked or expired are skipped, and a release that has already been
+// superseded is not announced.
+func (s *DownloadService) NotifyFileUpdate(ctx context.Context, productID, fileID string, version int) error {
+	if s.emailSvc == nil || s.orderRepo == nil {
+		return errors.New("file update notifications are not configured")
+	}
+	pid, err := primitive.ObjectIDFromHex(productID)
+	if err != nil {
+		return fmt.Errorf("invalid product ID: %w", err)
+	}
+	fid, err := primitive.ObjectIDFromHex(fileID)
+	if err != nil {
+		return fmt.Errorf("invalid file ID: %w", err)
+	}
+
+	product, err := s.productRepo.FindByID(ctx, pid)
+	if err != nil {
+		return err
+	}
+	if product == nil || product.DeletedAt != nil {
+		return nil
+	}
+	file := product.DownloadFile(fid)
+	if file == nil || file.Version != version {
+		return nil
+	}
+
+	orders, err := s.orderRepo.FindPaidByProductID(ctx, pid)
+	if err != nil {
+		return fmt.Errorf("failed to fetch buyers: %w", err)
+	}
+
+	subject := fmt.Sprintf("New version of %s available", product.Title)
+	sent := map[string]bool{}
+	for _, order := range orders {
+		email := strings.ToLower(strings.TrimSpace(order.CustomerEmail))
+		if email == "" || sent[email] {
+			continue
+		}
+		sent[email] = true
+
+		ent, err := s.EnsureEntitlement(ctx, order, product)
+		if err != nil {
+			logger.Error("failed to load entitlement for update notice", "order_id", order.ID.Hex(), "error", err)
+			continue
+		}
+		if ent.LockedAt != nil || (ent.ExpiresAt != nil && time.Now().After(*ent.ExpiresAt)) {
+			continue
+		}
+
+		link, err := s.EmailDownloadURL(ctx, order, product, file.ID)
+		if err != nil {
+			logger.Error("failed to build update download link", "order_id", order.ID.Hex(), "error", err)
+			continue
+		}
+		if err := s.emailSvc.Send(ctx, order.CustomerEmail, subject, fileUpdateBody(order, product, file, link)); err != nil {
+			logger.Error("failed to send file update notice", "order_id", order.ID.Hex(), "error", err)
+		}
+	}
+
+	logger.Info("file update notice sent", "product_id", productID, "file_id", fileID, "version", version, "recipients", len(sent))
+	return nil
+}
+
+func fileUpdateBody(order *domain.Order, product *domain.Product, file *domain.ProductFile, link string) string {
+	var notes string
+	if file.Notes != "" {
+		notes = fmt.Sprintf("<p><strong>What's new:</strong><br>%s</p>", strings.ReplaceAll(html.EscapeString(file.Notes), "\n", "<br>"))
+	}
+	return fmt.Sprintf(
+		"<p>Hi %s,</p><p>A new version (v%d) of <strong>%s</strong> from <strong>%s</strong> is available.</p>%s<p><a href=\"%s\">Download the latest version</a></p><p>You can also find every file of your purchase on your purchases page.</p>",
+		html.EscapeString(order.CustomerName), file.Version, html.EscapeString(file.Label), html.EscapeString(product.Title), notes, link,
+	)
+}
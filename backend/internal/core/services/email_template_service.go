@@ -10,13 +10,19 @@ import (
 )
 
 type EmailTemplateService struct {
-	repo domain.EmailTemplateRepository
+	repo  domain.EmailTemplateRepository
+	audit *AuditService
 }
 
 func NewEmailTemplateService(repo domain.EmailTemplateRepository) *EmailTemplateService {
 	return &EmailTemplateService{repo: repo}
 }
 
+// SetAuditService injects the audit log (optional, records platform template edits).
+func (s *EmailTemplateService) SetAuditService(audit *AuditService) {
+	s.audit = audit
+}
+
 type UpdateTemplateInput struct {
 	Subject   string `json:"subject"`
 	BodyHTML  string `json:"bodyHtml"`
@@ -24,6 +30,78 @@ type UpdateTemplateInput struct {
 	IsActive  bool   `json:"isActive"`
 }
 
+// defaultPlatformTemplates are used until an admin customises a platform template.
+var defaultPlatformTemplates = map[domain.EmailTemplateType]domain.EmailTemplate{
+	domain.TemplateTypeBuyerMagicLink: {
+		Subject: "Your sign-in link for Mio Store",
+		BodyHTML: "<div style='font-family:sans-serif;max-width:480px;margin:0 auto;padding:32px;'>" +
+			"<h2 style='color:#6C5CE7;'>Sign in to Mio Store</h2>" +
+			"<p>Click the button below to access your purchases.</p>" +
+			"<p style='text-align:center;margin:24px 0;'><a href='{login_url}' style='background:#6C5CE7;color:#fff;padding:12px 24px;border-radius:8px;text-decoration:none;font-weight:bold;'>Sign in</a></p>" +
+			"<p style='color:#888;font-size:14px;'>This link expires in {expires_minutes} minutes and only works in the browser you requested it from. If you didn't request it, you can ignore this email.</p>" +
+			"</div>",
+	},
+}
+
+// GetPlatformTemplate returns a platform-wide template, or its default if it has not been
+// customised.
+func (s *EmailTemplateService) GetPlatformTemplate(ctx context.Context, templateType string) (*domain.EmailTemplate, error) {
+	tType := domain.EmailTemplateType(templateType)
+	if !domain.IsPlatformTemplate(tType) {
+		return nil, errors.New("unknown platform template")
+	}
+
+	template, err := s.repo.FindByCreatorAndType(ctx, domain.PlatformTemplateCreatorID, tType)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		def := defaultPlatformTemplates[tType]
+		def.CreatorID = domain.PlatformTemplateCreatorID
+		def.TemplateType = tType
+		def.IsActive = true
+		return &def, nil
+	}
+	return template, nil
+}
+
+// UpdatePlatformTemplate saves an admin's changes to a platform-wide template.
+func (s *EmailTemplateService) UpdatePlatformTemplate(ctx context.Context, actor AuditActor, templateType string, input *UpdateTemplateInput) error {
+	tType := domain.EmailTemplateType(templateType)
+	if !domain.IsPlatformTemplate(tType) {
+		return errors.New("unknown platform template")
+	}
+	if strings.TrimSpace(input.Subject) == "" {
+		return errors.New("subject is required")
+	}
+	if strings.TrimSpace(input.BodyHTML) == "" {
+		return errors.New("body is required")
+	}
+	if tType == domain.TemplateTypeBuyerMagicLink && !strings.Contains(input.BodyHTML, "{login_url}") {
+		return errors.New("body must include the {login_url} placeholder")
+	}
+
+	before, err := s.GetPlatformTemplate(ctx, templateType)
+	if err != nil {
+		return err
+	}
+	updated := &domain.EmailTemplate{
+		CreatorID:    domain.PlatformTemplateCreatorID,
+		TemplateType: tType,
+		Subject:      strings.TrimSpace(input.Subject),
+		BodyHTML:     strings.TrimSpace(input.BodyHTML),
+		IsActive:     true, // Platform emails are always sent
+	}
+	if err := s.repo.Upsert(ctx, updated); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, actor, domain.AuditEmailTemplateUpdate, domain.AuditTargetEmailTemplate, templateType,
+		map[string]string{"subject": before.Subject, "bodyHtml": before.BodyHTML},
+		map[string]string{"subject": updated.Subject, "bodyHtml": updated.BodyHTML}, "")
+	return nil
+}
+
 func (s *EmailTemplateService) GetTemplate(ctx context.Context, creatorIDStr string, templateType string) (*domain.EmailTemplate, error) {
 	creatorID, err := primitive.ObjectIDFromHex(creatorIDStr)
 	if err != nil {
@@ -48,6 +126,11 @@ func (s *EmailTemplateService) GetTemplate(ctx context.Context, creatorIDStr str
 			DelayDays:    3,
 			IsActive:     false,
 		}
+		if tType == domain.TemplateTypeBookingFollowUp {
+			defaultTemplate.Subject = "Thanks for joining {product_title}!"
+			defaultTemplate.BodyHTML = "<p>Hi {buyer_name},</p><p>Thank you for our session on <strong>{product_title}</strong>. I hope it was helpful!</p><p>If you have a minute, hit reply and tell me how it went — I'd love to feature your feedback as a testimonial.</p><p><br></p><p>Best,</p><p>{creator_name}</p>"
+			defaultTemplate.DelayDays = 0
+		}
 		return defaultTemplate, nil
 	}
 
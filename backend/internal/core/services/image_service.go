@@ -0,0 +1,248 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/imaging"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+const (
+	jpegVariantQuality = 82
+	webpVariantQuality = 78
+)
+
+// imageVariantSpec describes one resized copy. Square variants are centre-cropped;
+// the others keep the original aspect ratio within a maxSize x maxSize box.
+type imageVariantSpec struct {
+	name    string
+	maxSize int
+	square  bool
+}
+
+var imageVariantSpecs = []imageVariantSpec{
+	{name: domain.ImageVariantThumb, maxSize: 200, square: true},
+	{name: domain.ImageVariantCard, maxSize: 600},
+	{name: domain.ImageVariantFull, maxSize: 1600},
+}
+
+// ImageService strips metadata from uploaded images and generates resized variants for
+// storefronts.
+type ImageService struct {
+	storage    domain.FileStorage
+	uploadRepo domain.UploadRepository
+	cache      domain.Cache
+	webp       domain.WebPCodec
+	worker     *asynq.Client
+}
+
+// NewImageService creates a new ImageService.
+func NewImageService(storage domain.FileStorage, uploadRepo domain.UploadRepository, cache domain.Cache) *ImageService {
+	return &ImageService{
+		storage:    storage,
+		uploadRepo: uploadRepo,
+		cache:      cache,
+	}
+}
+
+// SetWebPCodec enables WebP variants and WebP source images. Without it only JPEG variants
+// are produced and WebP uploads are left as uploaded, minus their metadata.
+func (s *ImageService) SetWebPCodec(codec domain.WebPCodec) {
+	s.webp = codec
+}
+
+// SetWorkerClient lets images be processed in the background worker.
+func (s *ImageService) SetWorkerClient(client *asynq.Client) {
+	s.worker = client
+}
+
+// Schedule queues processing of a verified image upload.
+func (s *ImageService) Schedule(fileKey string) {
+	if s.worker != nil {
+		if err := EnqueueImageProcessTask(s.worker, fileKey); err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+			logger.Error("failed to enqueue image processing", "file_key", fileKey, "error", err)
+		}
+		return
+	}
+	go func() {
+		if err := s.ProcessUpload(context.Background(), fileKey); err != nil {
+			logger.Error("image processing failed", "file_key", fileKey, "error", err)
+		}
+	}()
+}
+
+// ProcessUpload removes EXIF and other metadata from the stored image in place and writes
+// thumbnail, card and full-size variants next to it. It is a no-op for uploads that are not
+// verified images or have already been processed. Images that cannot be decoded are marked
+// as failed rather than retried.
+func (s *ImageService) ProcessUpload(ctx context.Context, fileKey string) error {
+	upload, err := s.uploadRepo.FindByKey(ctx, fileKey)
+	if err != nil {
+		return err
+	}
+	if upload == nil {
+		return ErrUploadNotFound
+	}
+	if upload.Purpose != domain.PurposeCoverImage || upload.Status != domain.UploadStatusVerified || upload.ProcessedAt != nil {
+		return nil
+	}
+
+	data, err := s.storage.Download(ctx, fileKey)
+	if err != nil {
+		return err
+	}
+
+	stripped, err := imaging.StripMetadata(data)
+	if err != nil {
+		return s.finish(ctx, upload, fmt.Errorf("strip metadata: %w", err))
+	}
+	if !bytes.Equal(stripped, data) {
+		if err := s.storage.Upload(ctx, fileKey, upload.ContentType, stripped); err != nil {
+			return fmt.Errorf("failed to store stripped image: %w", err)
+		}
+		upload.Size = int64(len(stripped))
+	}
+
+	img, err := s.decode(ctx, stripped)
+	if err != nil {
+		return s.finish(ctx, upload, err)
+	}
+	upload.Width, upload.Height = img.Bounds().Dx(), img.Bounds().Dy()
+
+	upload.Variants = nil
+	base := strings.TrimSuffix(fileKey, path.Ext(fileKey))
+	for _, spec := range imageVariantSpecs {
+		var resized image.Image
+		if spec.square {
+			resized = imaging.Fill(img, spec.maxSize)
+		} else {
+			resized = imaging.Fit(img, spec.maxSize, spec.maxSize)
+		}
+		b := resized.Bounds()
+
+		jpg, err := imaging.EncodeJPEG(resized, jpegVariantQuality)
+		if err != nil {
+			return s.finish(ctx, upload, err)
+		}
+		key := fmt.Sprintf("%s_%s.jpg", base, spec.name)
+		if err := s.storage.Upload(ctx, key, "image/jpeg", jpg); err != nil {
+			return fmt.Errorf("failed to store %s variant: %w", spec.name, err)
+		}
+		upload.Variants = append(upload.Variants, domain.ImageVariant{Name: spec.name, Format: domain.ImageFormatJPEG, FileKey: key, Width: b.Dx(), Height: b.Dy()})
+
+		if s.webp == nil {
+			continue
+		}
+		webp, err := s.webp.Encode(ctx, resized, webpVariantQuality)
+		if err != nil {
+			logger.Warn("webp encoding failed, keeping jpeg only", "file_key", fileKey, "variant", spec.name, "error", err)
+			continue
+		}
+		key = fmt.Sprintf("%s_%s.webp", base, spec.name)
+		if err := s.storage.Upload(ctx, key, "image/webp", webp); err != nil {
+			return fmt.Errorf("failed to store %s variant: %w", spec.name, err)
+		}
+		upload.Variants = append(upload.Variants, domain.ImageVariant{Name: spec.name, Format: domain.ImageFormatWebP, FileKey: key, Width: b.Dx(), Height: b.Dy()})
+	}
+
+	return s.finish(ctx, upload, nil)
+}
+
+func (s *ImageService) decode(ctx context.Context, data []byte) (image.Image, error) {
+	img, _, err := imaging.Decode(data)
+	if errors.Is(err, imaging.ErrUnsupportedFormat) && sniffExtension(data) == ".webp" {
+		if s.webp == nil {
+			return nil, errors.New("webp images need the WebP tools to generate variants")
+		}
+		return s.webp.Decode(ctx, data)
+	}
+	return img, err
+}
+
+// finish records the processing outcome and drops the creator's cached storefront so the
+// new variants are picked up.
+func (s *ImageService) finish(ctx context.Context, upload *domain.Upload, procErr error) error {
+	now := time.Now()
+	upload.ProcessedAt = &now
+	upload.ProcessError = ""
+	if procErr != nil {
+		logger.Warn("image processing failed", "file_key", upload.FileKey, "error", procErr)
+		upload.ProcessError = procErr.Error()
+		upload.Variants = nil
+	}
+	if err := s.uploadRepo.Update(ctx, upload); err != nil {
+		return err
+	}
+	if s.cache != nil {
+		_ = s.cache.Delete(ctx, fmt.Sprintf("cache:store:id:%s", upload.CreatorID.Hex()))
+	}
+	return nil
+}
+
+// ResponsiveImages returns the responsive URL sets for images that were uploaded and
+// processed, keyed by the URL passed in. URLs of unprocessed or external images are omitted.
+// Variant URLs share the original URL's host, so they work with any public bucket domain.
+func (s *ImageService) ResponsiveImages(ctx context.Context, urls []string) (map[string]*domain.ResponsiveImage, error) {
+	byKey := map[string]string{}
+	keys := []string{}
+	for _, u := range urls {
+		key := uploadKey(u)
+		if key == "" {
+			continue
+		}
+		if _, ok := byKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		byKey[key] = u
+	}
+	result := map[string]*domain.ResponsiveImage{}
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	uploads, err := s.uploadRepo.FindByKeys(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	for _, upload := range uploads {
+		if len(upload.Variants) == 0 {
+			continue
+		}
+		original := byKey[upload.FileKey]
+		prefix := original[:strings.Index(original, upload.FileKey)]
+		result[original] = responsiveImage(prefix, upload)
+	}
+	return result, nil
+}
+
+func responsiveImage(prefix string, upload *domain.Upload) *domain.ResponsiveImage {
+	img := &domain.ResponsiveImage{Width: upload.Width, Height: upload.Height}
+	var jpegSet, webpSet []string
+	for _, v := range upload.Variants {
+		url := prefix + v.FileKey
+		switch {
+		case v.Name == domain.ImageVariantThumb && v.Format == domain.ImageFormatJPEG:
+			img.Thumbnail = url
+		case v.Name == domain.ImageVariantThumb:
+			img.ThumbnailWebP = url
+		case v.Format == domain.ImageFormatJPEG:
+			jpegSet = append(jpegSet, fmt.Sprintf("%s %dw", url, v.Width))
+			img.Src = url // variants are ordered smallest first, so this ends on the largest
+		default:
+			webpSet = append(webpSet, fmt.Sprintf("%s %dw", url, v.Width))
+		}
+	}
+	img.SrcSet = strings.Join(jpegSet, ", ")
+	img.WebPSrcSet = strings.Join(webpSet, ", ")
+	return img
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+)
+
+var (
+	ErrImpersonationReasonRequired = errors.New("a reason is required to impersonate a creator")
+	ErrCannotImpersonate           = errors.New("only creator accounts can be impersonated")
+	ErrNotImpersonating            = errors.New("this session is not an impersonation session")
+)
+
+const (
+	defaultImpersonationTTL = 30 * time.Minute
+	minImpersonationTTL     = 5 * time.Minute
+	maxImpersonationTTL     = time.Hour
+)
+
+// ImpersonationService lets admins open a time-boxed, read-only view of a creator's account
+// for support. Sessions live in the session store, so they need Redis; starting, stopping and
+// every request made while impersonating are written to the audit log.
+type ImpersonationService struct {
+	sessions *SessionService
+	userRepo domain.UserRepository
+	audit    *AuditService
+}
+
+// NewImpersonationService creates a new ImpersonationService.
+func NewImpersonationService(sessions *SessionService, userRepo domain.UserRepository, audit *AuditService) *ImpersonationService {
+	return &ImpersonationService{sessions: sessions, userRepo: userRepo, audit: audit}
+}
+
+// Start opens an impersonation session on the creator's account for the acting admin and
+// returns it with its access token. duration is clamped to 5–60 minutes; zero means 30.
+func (s *ImpersonationService) Start(ctx context.Context, actor AuditActor, creatorID, reason string, duration time.Duration) (*domain.Impersonation, string, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, "", ErrImpersonationReasonRequired
+	}
+	switch {
+	case duration == 0:
+		duration = defaultImpersonationTTL
+	case duration < minImpersonationTTL:
+		duration = minImpersonationTTL
+	case duration > maxImpersonationTTL:
+		duration = maxImpersonationTTL
+	}
+
+	creator, err := s.userRepo.FindByID(ctx, creatorID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to find user: %w", err)
+	}
+	if creator == nil {
+		return nil, "", fmt.Errorf("user not found")
+	}
+	if creator.Role != domain.RoleCreator {
+		return nil, "", ErrCannotImpersonate
+	}
+
+	imp, token, err := s.sessions.CreateImpersonation(ctx, actor.UserID, creator, reason, duration,
+		ClientInfo{UserAgent: actor.UserAgent, IP: actor.IP})
+	if err != nil {
+		return nil, "", err
+	}
+	s.fillAdminEmail(ctx, imp)
+
+	s.audit.Record(ctx, actor, domain.AuditImpersonationStart, domain.AuditTargetUser, imp.CreatorID, nil,
+		map[string]interface{}{"session_id": imp.SessionID, "expires_at": imp.ExpiresAt}, reason)
+	return imp, token, nil
+}
+
+// Get returns the live impersonation session with this ID.
+func (s *ImpersonationService) Get(ctx context.Context, sessionID string) (*domain.Impersonation, error) {
+	imp, err := s.sessions.Impersonation(ctx, sessionID)
+	if errors.Is(err, ErrSessionNotFound) {
+		return nil, ErrNotImpersonating
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.fillAdminEmail(ctx, imp)
+	return imp, nil
+}
+
+// Stop ends an impersonation session before it expires.
+func (s *ImpersonationService) Stop(ctx context.Context, actor AuditActor, sessionID string) error {
+	imp, err := s.Get(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if err := s.sessions.Revoke(ctx, imp.CreatorID, sessionID); err != nil && !errors.Is(err, ErrSessionNotFound) {
+		return err
+	}
+
+	s.audit.Record(ctx, actor, domain.AuditImpersonationStop, domain.AuditTargetUser, imp.CreatorID, nil,
+		map[string]interface{}{"session_id": sessionID}, "")
+	return nil
+}
+
+// RecordRequest writes a request made while impersonating to the audit log.
+func (s *ImpersonationService) RecordRequest(ctx context.Context, actor AuditActor, creatorID, method, path string, status int) {
+	s.audit.RecordRequest(ctx, actor, domain.AuditImpersonatedRequest, domain.AuditTargetUser, creatorID, method, path, status)
+}
+
+func (s *ImpersonationService) fillAdminEmail(ctx context.Context, imp *domain.Impersonation) {
+	if admin, err := s.userRepo.FindByID(ctx, imp.AdminID); err == nil && admin != nil {
+		imp.AdminEmail = admin.Email
+	}
+}
@@ -0,0 +1,588 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+	"github.com/devanshbhargava/stan-store/pkg/pdf"
+)
+
+var (
+	ErrInvoiceNotFound      = errors.New("invoice not found")
+	ErrOrderNotInvoiceable  = errors.New("only paid orders with an amount can be invoiced")
+	ErrInvoicePending       = errors.New("invoice is still being issued, try again shortly")
+	ErrInvalidGSTIN         = errors.New("GSTIN is not valid")
+	ErrGSTINStateMismatch   = errors.New("GSTIN does not belong to the selected state")
+	ErrInvalidStateCode     = errors.New("choose a valid state")
+	ErrInvalidTaxRate       = errors.New("GST rate must be 0, 5, 12, 18 or 28")
+	ErrInvalidInvoicePrefix = errors.New("invoice prefix must be 1-5 letters or digits")
+	ErrIncompleteBusiness   = errors.New("legal name, address, city and postal code are required")
+	ErrInvalidTaxMonth      = errors.New("month must look like 2026-04")
+)
+
+// Defaults used until a creator saves their invoice settings.
+const (
+	defaultInvoicePrefix = "INV"
+	defaultTaxRate       = 18.0
+	defaultSACCode       = "998439" // Other online content
+
+	// invoiceClaimTimeout is how long a pending claim may go unfinished before another request
+	// takes it over.
+	invoiceClaimTimeout = 2 * time.Minute
+)
+
+var invoicePrefixPattern = regexp.MustCompile(`^[A-Z0-9]{1,5}$`)
+
+// InvoiceService calculates GST at checkout and issues PDF invoices for paid orders.
+type InvoiceService struct {
+	invoiceRepo  domain.InvoiceRepository
+	settingsRepo domain.InvoiceSettingsRepository
+	orderRepo    domain.OrderRepository
+	userRepo     domain.UserRepository
+	storage      domain.FileStorage
+}
+
+// NewInvoiceService creates a new InvoiceService.
+func NewInvoiceService(
+	invoiceRepo domain.InvoiceRepository,
+	settingsRepo domain.InvoiceSettingsRepository,
+	orderRepo domain.OrderRepository,
+	userRepo domain.UserRepository,
+	storage domain.FileStorage,
+) *InvoiceService {
+	return &InvoiceService{
+		invoiceRepo:  invoiceRepo,
+		settingsRepo: settingsRepo,
+		orderRepo:    orderRepo,
+		userRepo:     userRepo,
+		storage:      storage,
+	}
+}
+
+// GetSettings returns the creator's invoice settings, or defaults if they haven't saved any.
+func (s *InvoiceService) GetSettings(ctx context.Context, creatorID primitive.ObjectID) (*domain.InvoiceSettings, error) {
+	settings, err := s.settingsRepo.FindByCreator(ctx, creatorID)
+	if err != nil {
+		return nil, err
+	}
+	// Issuing an invoice before setup leaves a record holding only the number counters
+	if settings == nil || settings.UpdatedAt.IsZero() {
+		return &domain.InvoiceSettings{
+			CreatorID:        creatorID,
+			TaxRate:          defaultTaxRate,
+			PricesIncludeTax: true,
+			InvoicePrefix:    defaultInvoicePrefix,
+			SACCode:          defaultSACCode,
+		}, nil
+	}
+	return settings, nil
+}
+
+// UpdateSettings validates and saves the creator's business details and tax setup.
+func (s *InvoiceService) UpdateSettings(ctx context.Context, creatorID primitive.ObjectID, settings *domain.InvoiceSettings) (*domain.InvoiceSettings, error) {
+	settings.CreatorID = creatorID
+	settings.LegalName = strings.TrimSpace(settings.LegalName)
+	settings.GSTIN = strings.ToUpper(strings.TrimSpace(settings.GSTIN))
+	settings.AddressLine1 = strings.TrimSpace(settings.AddressLine1)
+	settings.AddressLine2 = strings.TrimSpace(settings.AddressLine2)
+	settings.City = strings.TrimSpace(settings.City)
+	settings.PostalCode = strings.TrimSpace(settings.PostalCode)
+	settings.InvoicePrefix = strings.ToUpper(strings.TrimSpace(settings.InvoicePrefix))
+	settings.SACCode = strings.TrimSpace(settings.SACCode)
+	if settings.InvoicePrefix == "" {
+		settings.InvoicePrefix = defaultInvoicePrefix
+	}
+	if settings.SACCode == "" {
+		settings.SACCode = defaultSACCode
+	}
+
+	if settings.LegalName == "" || settings.AddressLine1 == "" || settings.City == "" || settings.PostalCode == "" {
+		return nil, ErrIncompleteBusiness
+	}
+	if _, ok := domain.GSTStates[settings.StateCode]; !ok {
+		return nil, ErrInvalidStateCode
+	}
+	if settings.GSTIN != "" {
+		if !domain.IsValidGSTIN(settings.GSTIN) {
+			return nil, ErrInvalidGSTIN
+		}
+		if settings.GSTIN[:2] != settings.StateCode {
+			return nil, ErrGSTINStateMismatch
+		}
+	}
+	if !isGSTRate(settings.TaxRate) {
+		return nil, ErrInvalidTaxRate
+	}
+	if !invoicePrefixPattern.MatchString(settings.InvoicePrefix) {
+		return nil, ErrInvalidInvoicePrefix
+	}
+
+	if err := s.settingsRepo.Upsert(ctx, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// ApplyTax works out the GST on a checkout of amount paise. It returns the amount the buyer
+// pays, the tax included in it and the rate. Creators without a GSTIN charge no tax.
+func (s *InvoiceService) ApplyTax(ctx context.Context, creatorID primitive.ObjectID, amount int64) (int64, int64, float64, error) {
+	settings, err := s.settingsRepo.FindByCreator(ctx, creatorID)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if !settings.Registered() || settings.TaxRate <= 0 || amount <= 0 {
+		return amount, 0, 0, nil
+	}
+	if settings.PricesIncludeTax {
+		return amount, inclusiveTax(amount, settings.TaxRate), settings.TaxRate, nil
+	}
+	tax := int64(math.Round(float64(amount) * settings.TaxRate / 100))
+	return amount + tax, tax, settings.TaxRate, nil
+}
+
+// IssueForOrder returns the order's invoice and PDF, issuing the invoice with the creator's
+// next number if it doesn't have one yet. The order is claimed before a number is allocated,
+// so concurrent requests can't use up numbers and leave gaps in the series.
+func (s *InvoiceService) IssueForOrder(ctx context.Context, order *domain.Order) (*domain.Invoice, []byte, error) {
+	if order.Status != domain.OrderStatusPaid || order.Amount <= 0 {
+		return nil, nil, ErrOrderNotInvoiceable
+	}
+
+	existing, err := s.invoiceRepo.FindByOrder(ctx, order.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if existing != nil && !existing.Pending() {
+		return existing, renderInvoice(existing), nil
+	}
+
+	token, err := s.invoiceRepo.Claim(ctx, order.CreatorID, order.ID, time.Now().Add(-invoiceClaimTimeout))
+	if err != nil {
+		return nil, nil, err
+	}
+	if token == "" {
+		again, err := s.invoiceRepo.FindByOrder(ctx, order.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if again != nil && !again.Pending() {
+			return again, renderInvoice(again), nil
+		}
+		return nil, nil, ErrInvoicePending
+	}
+
+	settings, err := s.settingsRepo.FindByCreator(ctx, order.CreatorID)
+	if err != nil {
+		return nil, nil, err
+	}
+	creator, err := s.userRepo.FindByID(ctx, order.CreatorID.Hex())
+	if err != nil || creator == nil {
+		return nil, nil, errors.New("creator not found")
+	}
+
+	invoice := buildInvoice(order, settings, creator)
+
+	seq, err := s.settingsRepo.NextSequence(ctx, order.CreatorID, invoice.FinancialYear)
+	if err != nil {
+		return nil, nil, err
+	}
+	prefix := defaultInvoicePrefix
+	if settings != nil && settings.InvoicePrefix != "" {
+		prefix = settings.InvoicePrefix
+	}
+	invoice.Number = fmt.Sprintf("%s/%s/%05d", prefix, invoice.FinancialYear, seq)
+	invoice.FileKey = fmt.Sprintf("creators/%s/invoices/%s.pdf", order.CreatorID.Hex(), strings.ReplaceAll(invoice.Number, "/", "-"))
+
+	if err := s.invoiceRepo.Issue(ctx, invoice, token); err != nil {
+		logger.Error("allocated invoice number was not recorded", "error", err, "number", invoice.Number, "order_id", order.ID.Hex())
+		return nil, nil, err
+	}
+	logger.Info("invoice issued", "number", invoice.Number, "order_id", order.ID.Hex())
+
+	// The PDF can be rebuilt from the record, so a failed upload is retried on download
+	doc := renderInvoice(invoice)
+	if err := s.storage.Upload(ctx, invoice.FileKey, "application/pdf", doc); err != nil {
+		logger.Error("failed to store invoice", "error", err, "number", invoice.Number)
+	}
+	return invoice, doc, nil
+}
+
+// GetBuyerInvoiceURL returns a short-lived download link for an order the buyer placed.
+// Purchases are matched to buyers by email.
+func (s *InvoiceService) GetBuyerInvoiceURL(ctx context.Context, orderID, buyerID primitive.ObjectID) (*domain.Invoice, string, error) {
+	buyer, err := s.userRepo.FindByID(ctx, buyerID.Hex())
+	if err != nil {
+		return nil, "", err
+	}
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, "", err
+	}
+	if order == nil || buyer == nil || !strings.EqualFold(order.CustomerEmail, buyer.Email) {
+		return nil, "", ErrInvoiceNotFound
+	}
+	return s.downloadURL(ctx, order)
+}
+
+// GetCreatorInvoiceURL returns a short-lived download link for one of the creator's orders.
+func (s *InvoiceService) GetCreatorInvoiceURL(ctx context.Context, orderID, creatorID primitive.ObjectID) (*domain.Invoice, string, error) {
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, "", err
+	}
+	if order == nil || order.CreatorID != creatorID {
+		return nil, "", ErrInvoiceNotFound
+	}
+	return s.downloadURL(ctx, order)
+}
+
+func (s *InvoiceService) downloadURL(ctx context.Context, order *domain.Order) (*domain.Invoice, string, error) {
+	invoice, doc, err := s.IssueForOrder(ctx, order)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := s.storage.Head(ctx, invoice.FileKey); err != nil {
+		if err := s.storage.Upload(ctx, invoice.FileKey, "application/pdf", doc); err != nil {
+			return nil, "", fmt.Errorf("failed to store invoice: %w", err)
+		}
+	}
+	url, err := s.storage.GeneratePresignedDownloadURL(ctx, invoice.FileKey, 15*time.Minute)
+	if err != nil {
+		return nil, "", err
+	}
+	return invoice, url, nil
+}
+
+// TaxSummary totals the creator's invoices for a month (YYYY-MM, Indian time) by place of
+// supply and rate, and returns the invoices themselves for export.
+func (s *InvoiceService) TaxSummary(ctx context.Context, creatorID primitive.ObjectID, month string) (*domain.TaxSummary, []*domain.Invoice, error) {
+	from, err := time.ParseInLocation("2006-01", month, domain.InvoiceZone)
+	if err != nil {
+		return nil, nil, ErrInvalidTaxMonth
+	}
+	invoices, err := s.invoiceRepo.FindByCreatorAndPeriod(ctx, creatorID, from, from.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	summary := &domain.TaxSummary{Month: month, Rows: []domain.TaxSummaryRow{}}
+	rows := map[string]*domain.TaxSummaryRow{}
+	for _, inv := range invoices {
+		key := fmt.Sprintf("%s|%g|%t", inv.PlaceOfSupply, inv.TaxRate, inv.Interstate)
+		row, ok := rows[key]
+		if !ok {
+			row = &domain.TaxSummaryRow{
+				PlaceOfSupply: inv.PlaceOfSupply,
+				StateName:     domain.GSTStates[inv.PlaceOfSupply],
+				Interstate:    inv.Interstate,
+				TaxRate:       inv.TaxRate,
+			}
+			rows[key] = row
+		}
+		row.Invoices++
+		row.TaxableAmount += inv.TaxableAmount
+		row.CGST += inv.CGST
+		row.SGST += inv.SGST
+		row.IGST += inv.IGST
+		row.Total += inv.Total
+
+		summary.Invoices++
+		summary.TaxableAmount += inv.TaxableAmount
+		summary.CGST += inv.CGST
+		summary.SGST += inv.SGST
+		summary.IGST += inv.IGST
+		summary.TotalTax += inv.TotalTax
+		summary.Total += inv.Total
+	}
+
+	for _, row := range rows {
+		summary.Rows = append(summary.Rows, *row)
+	}
+	sort.Slice(summary.Rows, func(i, j int) bool {
+		a, b := summary.Rows[i], summary.Rows[j]
+		if a.PlaceOfSupply != b.PlaceOfSupply {
+			return a.PlaceOfSupply < b.PlaceOfSupply
+		}
+		return a.TaxRate < b.TaxRate
+	})
+	return summary, invoices, nil
+}
+
+// buildInvoice works out the parties, place of supply and tax split for an order. The number
+// and file key are assigned by the caller.
+func buildInvoice(order *domain.Order, settings *domain.InvoiceSettings, creator *domain.User) *domain.Invoice {
+	issuedAt := time.Now()
+	if order.PaidAt != nil {
+		issuedAt = *order.PaidAt
+	} else if order.UpdatedAt.After(order.CreatedAt) {
+		issuedAt = order.UpdatedAt // Paid before payment times were recorded
+	}
+
+	invoice := &domain.Invoice{
+		CreatorID:     order.CreatorID,
+		OrderID:       order.ID,
+		FinancialYear: financialYear(issuedAt),
+		Type:          domain.InvoiceTypeBillOfSupply,
+		IssuedAt:      issuedAt,
+		Seller:        domain.InvoiceParty{Name: displayNameOrEmail(creator), Email: creator.Email},
+		Buyer: domain.InvoiceParty{
+			Name:  order.CustomerName,
+			Email: order.CustomerEmail,
+		},
+		Total:    order.Amount,
+		Currency: order.Currency,
+	}
+	sac := defaultSACCode
+	if settings != nil && settings.LegalName != "" {
+		invoice.Seller = domain.InvoiceParty{
+			Name:      settings.LegalName,
+			Email:     creator.Email,
+			GSTIN:     settings.GSTIN,
+			Address:   settingsAddress(settings),
+			StateCode: settings.StateCode,
+		}
+		sac = settings.SACCode
+	}
+	if order.Billing != nil {
+		if order.Billing.BusinessName != "" {
+			invoice.Buyer.Name = order.Billing.BusinessName
+		}
+		invoice.Buyer.GSTIN = order.Billing.GSTIN
+		invoice.Buyer.StateCode = order.Billing.StateCode
+		if invoice.Buyer.GSTIN != "" {
+			invoice.Buyer.StateCode = invoice.Buyer.GSTIN[:2]
+		}
+	}
+
+	// Place of supply is the buyer's state; without one it is the seller's own state
+	invoice.PlaceOfSupply = invoice.Buyer.StateCode
+	if invoice.PlaceOfSupply == "" {
+		invoice.PlaceOfSupply = invoice.Seller.StateCode
+	}
+
+	var tax int64
+	if settings.Registered() {
+		invoice.Type = domain.InvoiceTypeTax
+		invoice.TaxRate = order.TaxRate
+		tax = order.TaxAmount
+		// Orders from before GST was set up were priced at the creator's current inclusive rate
+		if order.TaxRate == 0 && order.TaxAmount == 0 {
+			invoice.TaxRate = settings.TaxRate
+			tax = inclusiveTax(order.Amount, settings.TaxRate)
+		}
+		invoice.Interstate = invoice.PlaceOfSupply != settings.StateCode
+	}
+	invoice.TaxableAmount = order.Amount - tax
+	invoice.TotalTax = tax
+	invoice.IGST, invoice.CGST, invoice.SGST = splitGST(tax, invoice.Interstate)
+
+	// Spread the taxable amount and tax across line items by price; any rounding remainder
+	// goes on the last line so lines always add up to the invoice
+	items := order.LineItems
+	var itemTotal int64
+	for _, item := range items {
+		itemTotal += item.Amount
+	}
+	if itemTotal <= 0 {
+		items = []domain.LineItem{{Title: "Order " + order.ID.Hex(), Amount: 1}}
+		itemTotal = 1
+	}
+	var taxableLeft, taxLeft = invoice.TaxableAmount, tax
+	for i, item := range items {
+		taxable, lineTax := taxableLeft, taxLeft
+		if i < len(items)-1 {
+			taxable = invoice.TaxableAmount * item.Amount / itemTotal
+			lineTax = tax * item.Amount / itemTotal
+		}
+		// Split the running total rather than each line, so the odd paise don't pile up on
+		// SGST and the lines' CGST and SGST add up to the invoice's
+		igstBefore, cgstBefore, sgstBefore := splitGST(tax-taxLeft, invoice.Interstate)
+		taxableLeft -= taxable
+		taxLeft -= lineTax
+		igstAfter, cgstAfter, sgstAfter := splitGST(tax-taxLeft, invoice.Interstate)
+
+		invoice.Lines = append(invoice.Lines, domain.InvoiceLine{
+			Description:   item.Title,
+			SACCode:       sac,
+			TaxableAmount: taxable,
+			IGST:          igstAfter - igstBefore,
+			CGST:          cgstAfter - cgstBefore,
+			SGST:          sgstAfter - sgstBefore,
+			Total:         taxable + lineTax,
+		})
+	}
+	return invoice
+}
+
+// splitGST returns the IGST, CGST and SGST parts of tax. Intra-state tax is split evenly,
+// with any odd paisa going to SGST.
+func splitGST(tax int64, interstate bool) (int64, int64, int64) {
+	if interstate {
+		return tax, 0, 0
+	}
+	cgst := tax / 2
+	return 0, cgst, tax - cgst
+}
+
+// inclusiveTax returns the GST contained in a tax-inclusive amount.
+func inclusiveTax(amount int64, rate float64) int64 {
+	taxable := int64(math.Round(float64(amount) * 100 / (100 + rate)))
+	return amount - taxable
+}
+
+// financialYear returns the Indian financial year (April to March) containing t, e.g. "26-27".
+func financialYear(t time.Time) string {
+	t = t.In(domain.InvoiceZone)
+	start := t.Year()
+	if t.Month() < time.April {
+		start--
+	}
+	return fmt.Sprintf("%02d-%02d", start%100, (start+1)%100)
+}
+
+func isGSTRate(rate float64) bool {
+	for _, r := range domain.GSTRates {
+		if rate == r {
+			return true
+		}
+	}
+	return false
+}
+
+func settingsAddress(settings *domain.InvoiceSettings) []string {
+	lines := []string{settings.AddressLine1}
+	if settings.AddressLine2 != "" {
+		lines = append(lines, settings.AddressLine2)
+	}
+	lines = append(lines, fmt.Sprintf("%s - %s", settings.City, settings.PostalCode))
+	if state := domain.GSTStates[settings.StateCode]; state != "" {
+		lines = append(lines, fmt.Sprintf("%s (%s)", state, settings.StateCode))
+	}
+	return lines
+}
+
+// formatPaise renders an amount for the invoice PDF. The built-in fonts have no rupee sign.
+func formatPaise(paise int64) string {
+	sign := ""
+	if paise < 0 {
+		sign = "-"
+		paise = -paise
+	}
+	return fmt.Sprintf("%sRs. %d.%02d", sign, paise/100, paise%100)
+}
+
+// renderInvoice lays out a portrait A4 tax invoice or bill of supply.
+func renderInvoice(inv *domain.Invoice) []byte {
+	muted := pdf.Color{R: 0.4, G: 0.45, B: 0.53}
+	rule := pdf.Color{R: 0.85, G: 0.87, B: 0.9}
+	band := pdf.Color{R: 0.95, G: 0.96, B: 0.98}
+
+	title := "Tax Invoice"
+	if inv.Type == domain.InvoiceTypeBillOfSupply {
+		title = "Bill of Supply"
+	}
+
+	doc := pdf.New(pdf.A4Width, pdf.A4Height)
+	doc.SetTitle(title + " " + inv.Number)
+	w := doc.Width()
+	left, right := 50.0, w-50
+	page := doc.AddPage()
+
+	page.Text(left, 70, pdf.HelveticaBold, 22, pdf.Black, title)
+	page.TextRight(right, 62, pdf.HelveticaBold, 11, pdf.Black, inv.Number)
+	page.TextRight(right, 78, pdf.Helvetica, 10, muted, "Date: "+inv.IssuedAt.In(domain.InvoiceZone).Format("2 Jan 2006"))
+
+	// Seller and buyer blocks side by side
+	party := func(x, y float64, label string, p domain.InvoiceParty) float64 {
+		page.Text(x, y, pdf.Helvetica, 9, muted, label)
+		y += 16
+		name := pdfName(p.Name, p.Email)
+		page.Text(x, y, pdf.HelveticaBold, 11, pdf.Black, name)
+		lines := append([]string{}, p.Address...)
+		if p.GSTIN != "" {
+			lines = append(lines, "GSTIN: "+p.GSTIN)
+		}
+		if p.Email != "" && p.Email != name {
+			lines = append(lines, p.Email)
+		}
+		for _, line := range lines {
+			y += 14
+			page.Text(x, y, pdf.Helvetica, 10, pdf.Black, line)
+		}
+		return y
+	}
+	sellerEnd := party(left, 120, "SOLD BY", inv.Seller)
+	buyerEnd := party(w/2+10, 120, "BILL TO", inv.Buyer)
+	y := math.Max(sellerEnd, buyerEnd) + 26
+
+	if inv.PlaceOfSupply != "" {
+		page.Text(left, y, pdf.Helvetica, 10, muted, fmt.Sprintf("Place of supply: %s (%s)", domain.GSTStates[inv.PlaceOfSupply], inv.PlaceOfSupply))
+		y += 20
+	}
+
+	// Line items
+	taxCols := inv.Type == domain.InvoiceTypeTax
+	page.FillRect(left, y, right-left, 22, band)
+	page.Text(left+8, y+15, pdf.HelveticaBold, 9, pdf.Black, "Description")
+	page.Text(left+250, y+15, pdf.HelveticaBold, 9, pdf.Black, "SAC")
+	page.TextRight(left+370, y+15, pdf.HelveticaBold, 9, pdf.Black, "Taxable value")
+	if taxCols {
+		page.TextRight(left+440, y+15, pdf.HelveticaBold, 9, pdf.Black, "Tax")
+	}
+	page.TextRight(right-8, y+15, pdf.HelveticaBold, 9, pdf.Black, "Amount")
+	y += 22
+
+	for _, line := range inv.Lines {
+		desc := pdf.WrapText(pdf.Helvetica, 10, line.Description, 235)
+		rowY := y + 16
+		for i, d := range desc {
+			page.Text(left+8, rowY+float64(i)*13, pdf.Helvetica, 10, pdf.Black, d)
+		}
+		page.Text(left+250, rowY, pdf.Helvetica, 10, pdf.Black, line.SACCode)
+		page.TextRight(left+370, rowY, pdf.Helvetica, 10, pdf.Black, formatPaise(line.TaxableAmount))
+		if taxCols {
+			page.TextRight(left+440, rowY, pdf.Helvetica, 10, pdf.Black, formatPaise(line.CGST+line.SGST+line.IGST))
+		}
+		page.TextRight(right-8, rowY, pdf.Helvetica, 10, pdf.Black, formatPaise(line.Total))
+		y += 10 + float64(len(desc))*13
+		page.Line(left, y, right, y, 0.5, rule)
+	}
+
+	// Totals
+	y += 24
+	total := func(label, value string, font pdf.Font) {
+		page.TextRight(right-120, y, font, 10, pdf.Black, label)
+		page.TextRight(right-8, y, font, 10, pdf.Black, value)
+		y += 16
+	}
+	total("Taxable value", formatPaise(inv.TaxableAmount), pdf.Helvetica)
+	if taxCols {
+		if inv.Interstate {
+			total(fmt.Sprintf("IGST @ %g%%", inv.TaxRate), formatPaise(inv.IGST), pdf.Helvetica)
+		} else {
+			total(fmt.Sprintf("CGST @ %g%%", inv.TaxRate/2), formatPaise(inv.CGST), pdf.Helvetica)
+			total(fmt.Sprintf("SGST @ %g%%", inv.TaxRate/2), formatPaise(inv.SGST), pdf.Helvetica)
+		}
+	}
+	page.Line(right-260, y-8, right, y-8, 0.75, rule)
+	y += 4
+	total("Total ("+inv.Currency+")", formatPaise(inv.Total), pdf.HelveticaBold)
+
+	footer := "This is a computer-generated invoice and does not require a signature."
+	if !taxCols {
+		footer = "Supplier is not registered under GST; no GST has been charged."
+	}
+	page.TextCentered(doc.Height()-50, pdf.Helvetica, 8, muted, footer)
+
+	return doc.Bytes()
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+)
+
+func TestSplitGST(t *testing.T) {
+	tests := []struct {
+		name       string
+		tax        int64
+		interstate bool
+		igst       int64
+		cgst       int64
+		sgst       int64
+	}{
+		{"intra-state even", 1800, false, 0, 900, 900},
+		{"intra-state odd paisa goes to SGST", 1801, false, 0, 900, 901},
+		{"intra-state single paisa", 1, false, 0, 0, 1},
+		{"inter-state", 1801, true, 1801, 0, 0},
+		{"no tax", 0, false, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			igst, cgst, sgst := splitGST(tt.tax, tt.interstate)
+			assert.Equal(t, []int64{tt.igst, tt.cgst, tt.sgst}, []int64{igst, cgst, sgst})
+			assert.Equal(t, tt.tax, igst+cgst+sgst)
+		})
+	}
+}
+
+func TestInclusiveTax(t *testing.T) {
+	tests := []struct {
+		amount int64
+		rate   float64
+		want   int64
+	}{
+		{11800, 18, 1800},
+		{10500, 5, 500},
+		{11200, 12, 1200},
+		{100, 18, 15}, // Taxable 84.75 rounds to 85
+		{99900, 18, 15239},
+		{1000, 0, 0},
+		{0, 18, 0},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, inclusiveTax(tt.amount, tt.rate), "%d at %v%%", tt.amount, tt.rate)
+	}
+}
+
+func TestFinancialYear(t *testing.T) {
+	tests := []struct {
+		name string
+		at   time.Time
+		want string
+	}{
+		{"mid year", time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC), "26-27"},
+		{"first of April", time.Date(2026, time.April, 1, 0, 0, 0, 0, domain.InvoiceZone), "26-27"},
+		{"last of March", time.Date(2026, time.March, 31, 23, 59, 0, 0, domain.InvoiceZone), "25-26"},
+		// 20:00 UTC on 31 March is already 1 April in India
+		{"April in IST but March in UTC", time.Date(2026, time.March, 31, 20, 0, 0, 0, time.UTC), "26-27"},
+		{"century", time.Date(2000, time.January, 15, 0, 0, 0, 0, time.UTC), "99-00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, financialYear(tt.at))
+		})
+	}
+}
+
+func TestBuildInvoice_DistributesLineRemainders(t *testing.T) {
+	creator := &domain.User{ID: primitive.NewObjectID(), DisplayName: "Asha", Email: "asha@example.com"}
+	registered := &domain.InvoiceSettings{LegalName: "Asha Studio", GSTIN: "29ABCDE1234F1Z5", StateCode: "29", TaxRate: 18, SACCode: "998431"}
+	paidAt := time.Date(2026, time.March, 31, 20, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		settings   *domain.InvoiceSettings
+		items      []int64
+		amount     int64
+		taxAmount  int64
+		billing    *domain.BillingDetails
+		wantType   domain.InvoiceType
+		interstate bool
+		wantLines  []int64 // Taxable amount per line
+	}{
+		{
+			name: "equal lines leave the remainder on the last", settings: registered,
+			items: []int64{10000, 10000, 10000}, amount: 30000, taxAmount: 4576,
+			wantType: domain.InvoiceTypeTax, wantLines: []int64{8474, 8474, 8476},
+		},
+		{
+			name: "uneven lines", settings: registered,
+			items: []int64{999, 1, 5000}, amount: 5999, taxAmount: 915,
+			wantType: domain.InvoiceTypeTax, wantLines: []int64{846, 0, 4238},
+		},
+		{
+			name: "buyer GSTIN in another state", settings: registered,
+			items: []int64{700, 300}, amount: 1000, taxAmount: 153,
+			billing:  &domain.BillingDetails{GSTIN: "27ABCDE1234F1Z5", StateCode: "29"},
+			wantType: domain.InvoiceTypeTax, interstate: true, wantLines: []int64{592, 255},
+		},
+		{
+			name: "legacy order without tax fields", settings: registered,
+			items: []int64{11800}, amount: 11800,
+			wantType: domain.InvoiceTypeTax, wantLines: []int64{10000},
+		},
+		{
+			name: "no line items", settings: registered,
+			amount: 11800, taxAmount: 1800,
+			wantType: domain.InvoiceTypeTax, wantLines: []int64{10000},
+		},
+		{
+			name: "bill of supply", settings: nil,
+			items: []int64{333, 667}, amount: 1000,
+			wantType: domain.InvoiceTypeBillOfSupply, wantLines: []int64{333, 667},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &domain.Order{
+				ID:        primitive.NewObjectID(),
+				CreatorID: creator.ID,
+				Amount:    tt.amount,
+				TaxAmount: tt.taxAmount,
+				Billing:   tt.billing,
+				Currency:  "INR",
+				PaidAt:    &paidAt,
+			}
+			if tt.taxAmount > 0 {
+				order.TaxRate = 18
+			}
+			for _, amount := range tt.items {
+				order.LineItems = append(order.LineItems, domain.LineItem{Title: "Item", Amount: amount})
+			}
+
+			inv := buildInvoice(order, tt.settings, creator)
+			assert.Equal(t, tt.wantType, inv.Type)
+			assert.Equal(t, tt.interstate, inv.Interstate)
+			assert.Equal(t, paidAt, inv.IssuedAt)
+			assert.Equal(t, "26-27", inv.FinancialYear)
+			require.Len(t, inv.Lines, len(tt.wantLines))
+
+			var taxable, igst, cgst, sgst, total int64
+			for i, line := range inv.Lines {
+				assert.Equal(t, tt.wantLines[i], line.TaxableAmount, "line %d", i)
+				assert.Equal(t, line.TaxableAmount+line.IGST+line.CGST+line.SGST, line.Total, "line %d", i)
+				taxable += line.TaxableAmount
+				igst += line.IGST
+				cgst += line.CGST
+				sgst += line.SGST
+				total += line.Total
+			}
+			assert.Equal(t, inv.TaxableAmount, taxable)
+			assert.Equal(t, inv.IGST, igst)
+			assert.Equal(t, inv.CGST, cgst)
+			assert.Equal(t, inv.SGST, sgst)
+			assert.Equal(t, inv.Total, total)
+			assert.Equal(t, tt.amount, inv.TaxableAmount+inv.TotalTax)
+		})
+	}
+}
+
+func TestBuildInvoice_IssueDate(t *testing.T) {
+	creator := &domain.User{Email: "asha@example.com"}
+	created := time.Date(2026, time.March, 30, 10, 0, 0, 0, time.UTC)
+	paid := created.Add(time.Hour)
+	updated := created.Add(48 * time.Hour)
+
+	tests := []struct {
+		name  string
+		order domain.Order
+		want  time.Time
+	}{
+		{"payment time", domain.Order{CreatedAt: created, UpdatedAt: updated, PaidAt: &paid}, paid},
+		{"legacy order uses last update", domain.Order{CreatedAt: created, UpdatedAt: updated}, updated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := tt.order
+			order.Amount = 1000
+			inv := buildInvoice(&order, nil, creator)
+			assert.Equal(t, tt.want, inv.IssuedAt)
+			assert.Equal(t, financialYear(tt.want), inv.FinancialYear)
+		})
+	}
+}
@@ -10,12 +10,19 @@ import (
 const (
 	jwtExpiry      = 7 * 24 * time.Hour // 7 days (creators)
 	BuyerJWTExpiry = 24 * time.Hour     // 24 hours (buyers)
+
+	// AccessTokenExpiry is the lifetime of access tokens tied to a session; clients renew
+	// them with the session's refresh token.
+	AccessTokenExpiry = 15 * time.Minute
 )
 
 // Claims represents the JWT payload.
 type Claims struct {
-	UserID string `json:"userId"`
-	Role   string `json:"role"`
+	UserID    string `json:"userId"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid,omitempty"`
+	// ImpersonatorID is the admin behind an impersonation token; empty for normal logins.
+	ImpersonatorID string `json:"imp,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -38,10 +45,27 @@ func (s *JWTService) GenerateToken(userID, role string) (string, error) {
 
 // GenerateTokenWithExpiry creates a signed JWT token with a configurable expiry duration.
 func (s *JWTService) GenerateTokenWithExpiry(userID, role string, expiry time.Duration) (string, error) {
+	return s.generate(userID, role, "", "", expiry)
+}
+
+// GenerateSessionToken creates a short-lived access token bound to a session.
+func (s *JWTService) GenerateSessionToken(userID, role, sessionID string) (string, error) {
+	return s.generate(userID, role, sessionID, "", AccessTokenExpiry)
+}
+
+// GenerateImpersonationToken creates an access token that lets an admin view the user's
+// account for the lifetime of an impersonation session. It cannot be refreshed.
+func (s *JWTService) GenerateImpersonationToken(userID, role, sessionID, impersonatorID string, expiry time.Duration) (string, error) {
+	return s.generate(userID, role, sessionID, impersonatorID, expiry)
+}
+
+func (s *JWTService) generate(userID, role, sessionID, impersonatorID string, expiry time.Duration) (string, error) {
 	now := time.Now()
 	claims := Claims{
-		UserID: userID,
-		Role:   role,
+		UserID:         userID,
+		Role:           role,
+		SessionID:      sessionID,
+		ImpersonatorID: impersonatorID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(expiry)),
 			IssuedAt:  jwt.NewNumericDate(now),
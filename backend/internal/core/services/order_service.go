@@ -12,6 +12,17 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ErrOrderAccessDenied is returned when a request for an order's files carries neither the
+// order link token nor the session of the buyer who placed the order.
+var ErrOrderAccessDenied = errors.New("this link does not give access to the order")
+
+// OrderAccess is what a caller offers to reach an order's files: the signed token from the
+// order link, or the ID of the signed-in buyer.
+type OrderAccess struct {
+	Token   string
+	BuyerID string
+}
+
 type OrderService struct {
 	orderRepo         domain.OrderRepository
 	productRepo       domain.ProductRepository
@@ -27,6 +38,9 @@ type OrderService struct {
 	campaignRepo      domain.CampaignRepository
 	emailQueueRepo    domain.EmailQueueRepository
 	affiliateSvc      *AffiliateService // New tracking dependency
+	downloadSvc       *DownloadService
+	webhooks          *CreatorWebhookService
+	invoices          *InvoiceService
 	workerClient      *asynq.Client
 	frontendURL       string
 }
@@ -71,13 +85,28 @@ func (s *OrderService) SetWorkerClient(client *asynq.Client) {
 	s.workerClient = client
 }
 
+// SetWebhookService sends order, subscription and subscriber events to creators' webhooks
+func (s *OrderService) SetWebhookService(webhooks *CreatorWebhookService) {
+	s.webhooks = webhooks
+}
+
+// SetInvoiceService charges GST at checkout and attaches invoices to confirmation emails
+func (s *OrderService) SetInvoiceService(invoices *InvoiceService) {
+	s.invoices = invoices
+}
+
 // SetFrontendURL sets the frontend base URL for email links
 func (s *OrderService) SetFrontendURL(url string) {
 	s.frontendURL = url
 }
 
+// SetDownloadService routes downloads through signed, limited entitlements
+func (s *OrderService) SetDownloadService(downloadSvc *DownloadService) {
+	s.downloadSvc = downloadSvc
+}
+
 // CreateOrder initiates a purchase for a product
-func (s *OrderService) CreateOrder(ctx context.Context, productID primitive.ObjectID, customerName, customerEmail string, bumpAccepted bool, bookingSlotStartStr string, referralCode string) (*domain.Order, error) {
+func (s *OrderService) CreateOrder(ctx context.Context, productID primitive.ObjectID, customerName, customerEmail string, bumpAccepted bool, bookingSlotStartStr string, referralCode string, billing *domain.BillingDetails) (*domain.Order, error) {
 	// 1. Fetch Product
 	product, err := s.productRepo.FindByID(ctx, productID)
 	if err != nil {
@@ -118,6 +147,7 @@ func (s *OrderService) CreateOrder(ctx context.Context, productID primitive.Obje
 
 	// 3. Handle free products (lead magnets) — skip Razorpay
 	if totalAmount == 0 || product.ProductType == domain.ProductTypeLeadMagnet {
+		now := time.Now()
 		order := &domain.Order{
 			ProductID:       product.ID,
 			CreatorID:       product.CreatorID,
@@ -128,12 +158,14 @@ func (s *OrderService) CreateOrder(ctx context.Context, productID primitive.Obje
 			Currency:        currency,
 			RazorpayOrderID: "free_" + primitive.NewObjectID().Hex(),
 			Status:          domain.OrderStatusPaid, // Immediately paid
+			PaidAt:          &now,
 			ReferralCode:    referralCode,
 		}
 
 		if err := s.orderRepo.Create(ctx, order); err != nil {
 			return nil, fmt.Errorf("failed to save order: %w", err)
 		}
+		s.webhooks.Publish(order.CreatorID, domain.WebhookEventOrderPaid, order)
 
 		// Add subscriber (async, best-effort)
 		if s.subscriberRepo != nil {
@@ -145,8 +177,10 @@ func (s *OrderService) CreateOrder(ctx context.Context, productID primitive.Obje
 					Source:       product.ID,
 					ConsentGiven: true,
 				}
-				if err := s.subscriberRepo.Upsert(context.Background(), sub); err != nil {
+				if created, err := s.subscriberRepo.Upsert(context.Background(), sub); err != nil {
 					fmt.Printf("Failed to add subscriber: %v\n", err)
+				} else if created {
+					s.webhooks.Publish(sub.CreatorID, domain.WebhookEventSubscriberAdded, sub)
 				}
 			}()
 		}
@@ -154,7 +188,7 @@ func (s *OrderService) CreateOrder(ctx context.Context, productID primitive.Obje
 		// Send email with download link (async)
 		go func() {
 			bgCtx := context.Background()
-			downloadURL, err := s.uploadSvc.GenerateDownloadURL(bgCtx, product.FileURL)
+			downloadURL, err := s.confirmationDownloadURL(bgCtx, order, product)
 			if err != nil {
 				downloadURL = "#"
 			}
@@ -175,6 +209,24 @@ func (s *OrderService) CreateOrder(ctx context.Context, productID primitive.Obje
 		return order, nil
 	}
 
+	// 3.5 Apply GST for registered creators; exclusive pricing adds it on top
+	var taxAmount int64
+	var taxRate float64
+	planAmount := product.Price
+	if s.invoices != nil {
+		var err error
+		totalAmount, taxAmount, taxRate, err = s.invoices.ApplyTax(ctx, product.CreatorID, totalAmount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate tax: %w", err)
+		}
+		if product.ProductType == domain.ProductTypeMembership {
+			planAmount, _, _, err = s.invoices.ApplyTax(ctx, product.CreatorID, product.Price)
+			if err != nil {
+				return nil, fmt.Errorf("failed to calculate tax: %w", err)
+			}
+		}
+	}
+
 	var razorpayOrderID string
 	var errRP error
 
@@ -185,7 +237,7 @@ func (s *OrderService) CreateOrder(ctx context.Context, productID primitive.Obje
 			interval = "monthly"
 		}
 		// 1. Create a dynamic Razorpay Plan for this membership checkout
-		planID, err := s.paymentSvc.CreateRazorpayPlan(product.Title, product.Price, currency, interval)
+		planID, err := s.paymentSvc.CreateRazorpayPlan(product.Title, planAmount, currency, interval)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create razorpay plan: %w", err)
 		}
@@ -251,6 +303,9 @@ func (s *OrderService) CreateOrder(ctx context.Context, productID primitive.Obje
 		CustomerName:     customerName,
 		CustomerEmail:    customerEmail,
 		Amount:           totalAmount,
+		TaxRate:          taxRate,
+		TaxAmount:        taxAmount,
+		Billing:          billing,
 		Currency:         currency,
 		RazorpayOrderID:  razorpayOrderID,
 		Status:           domain.OrderStatusCreated,
@@ -296,7 +351,11 @@ func (s *OrderService) CancelSubscription(ctx context.Context, subID primitive.O
 	}
 
 	sub.Status = domain.SubscriptionStatusCancelled
-	return s.subRepo.Update(ctx, sub)
+	if err := s.subRepo.Update(ctx, sub); err != nil {
+		return err
+	}
+	s.webhooks.Publish(sub.CreatorID, domain.WebhookEventSubscriptionCancelled, sub)
+	return nil
 }
 
 // HandleSubscriptionEvent processes Razorpay subscription webhooks
@@ -326,6 +385,7 @@ func (s *OrderService) HandleSubscriptionEvent(ctx context.Context, eventName st
 	status, _ := entity["status"].(string)
 	paidCountFloat, _ := entity["paid_count"].(float64)
 
+	previousStatus := sub.Status
 	sub.Status = domain.SubscriptionStatus(status)
 	sub.PaidCount = int(paidCountFloat)
 
@@ -342,6 +402,9 @@ func (s *OrderService) HandleSubscriptionEvent(ctx context.Context, eventName st
 	if err := s.subRepo.Update(ctx, sub); err != nil {
 		return fmt.Errorf("failed to update subscription: %w", err)
 	}
+	if sub.Status == domain.SubscriptionStatusCancelled && previousStatus != domain.SubscriptionStatusCancelled {
+		s.webhooks.Publish(sub.CreatorID, domain.WebhookEventSubscriptionCancelled, sub)
+	}
 
 	// For MVP, we'll mark the initial Order as Paid on the first charge so the dashboard shows the sale.
 	if eventName == "subscription.charged" && sub.PaidCount == 1 {
@@ -402,6 +465,12 @@ func (s *OrderService) HandlePaymentSuccess(ctx context.Context, razorpayOrderID
 		fmt.Printf("CRITICAL: Failed to credit wallet for order %s: %v\n", order.ID.Hex(), err)
 	}
 
+	paidAt := time.Now()
+	order.Status = domain.OrderStatusPaid
+	order.RazorpayPaymentID = paymentID
+	order.PaidAt = &paidAt
+	s.webhooks.Publish(order.CreatorID, domain.WebhookEventOrderPaid, order)
+
 	// 3.5 Add subscriber (async, best-effort) to allow creators to market to buyers
 	if s.subscriberRepo != nil {
 		go func() {
@@ -420,8 +489,10 @@ func (s *OrderService) HandlePaymentSuccess(ctx context.Context, razorpayOrderID
 				Source:       primaryProductID,
 				ConsentGiven: true,
 			}
-			if err := s.subscriberRepo.Upsert(bgCtx, sub); err != nil {
+			if created, err := s.subscriberRepo.Upsert(bgCtx, sub); err != nil {
 				fmt.Printf("Failed to add subscriber for paid order: %v\n", err)
+			} else if created {
+				s.webhooks.Publish(sub.CreatorID, domain.WebhookEventSubscriberAdded, sub)
 			}
 		}()
 	}
@@ -467,14 +538,29 @@ func (s *OrderService) HandlePaymentSuccess(ctx context.Context, razorpayOrderID
 		}
 
 		// Generate Download Link
-		downloadURL, err := s.uploadSvc.GenerateDownloadURL(bgCtx, product.FileURL)
+		downloadURL, err := s.confirmationDownloadURL(bgCtx, order, product)
 		if err != nil {
 			fmt.Printf("Error generating download link for email: %v\n", err)
 			// Might want to send email without link or a generic link
 			downloadURL = "#"
 		}
 
-		if err := s.emailSvc.SendOrderConfirmation(bgCtx, order, product, downloadURL); err != nil {
+		// Attach the invoice; the email still goes out if it can't be issued
+		var attachments []domain.EmailAttachment
+		if s.invoices != nil {
+			invoice, doc, err := s.invoices.IssueForOrder(bgCtx, order)
+			if err != nil {
+				fmt.Printf("Error issuing invoice for order %s: %v\n", order.ID.Hex(), err)
+			} else {
+				attachments = append(attachments, domain.EmailAttachment{
+					Filename:    strings.ReplaceAll(invoice.Number, "/", "-") + ".pdf",
+					ContentType: "application/pdf",
+					Data:        doc,
+				})
+			}
+		}
+
+		if err := s.emailSvc.SendOrderConfirmation(bgCtx, order, product, downloadURL, attachments...); err != nil {
 			fmt.Printf("Error sending confirmation email: %v\n", err)
 		}
 	}()
@@ -500,16 +586,78 @@ func (s *OrderService) HandlePaymentSuccess(ctx context.Context, razorpayOrderID
 	return nil
 }
 
-// GetOrderDownloadURL verifies order status and returns a download link.
-func (s *OrderService) GetOrderDownloadURL(ctx context.Context, orderID primitive.ObjectID, requestedProductID string) (string, error) {
-	// 1. Fetch Order
-	// Note: We currently don't have FindByID in OrderRepo interface exposed plainly,
+// confirmationDownloadURL returns the link placed in the order confirmation email.
+// Products with several files link to the order page, which lists all of them.
+func (s *OrderService) confirmationDownloadURL(ctx context.Context, order *domain.Order, product *domain.Product) (string, error) {
+	files := product.DownloadFiles()
+	if len(files) > 1 && s.frontendURL != "" {
+		if s.downloadSvc != nil {
+			if err := s.downloadSvc.PrepareDownloads(ctx, order, product); err != nil {
+				return "", err
+			}
+		}
+		return fmt.Sprintf("%s/order/%s?token=%s", strings.TrimRight(s.frontendURL, "/"), order.ID.Hex(), s.OrderAccessToken(order)), nil
+	}
+	if s.downloadSvc != nil && len(files) > 0 {
+		return s.downloadSvc.EmailDownloadURL(ctx, order, product, primitive.NilObjectID)
+	}
+	return s.uploadSvc.GenerateDownloadURL(ctx, product.FileURL)
+}
+
+// OrderAccessToken returns the token that opens the order page's downloads for whoever holds
+// the link. It is empty when signed downloads are not configured.
+func (s *OrderService) OrderAccessToken(order *domain.Order) string {
+	if s.downloadSvc == nil {
+		return ""
+	}
+	return s.downloadSvc.OrderToken(order)
+}
+
+// authorizedOrder fetches an order and checks that the caller may reach its files.
+func (s *OrderService) authorizedOrder(ctx context.Context, orderID primitive.ObjectID, access OrderAccess) (*domain.Order, error) {
 	order, err := s.orderRepo.FindByID(ctx, orderID)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch order: %w", err)
+		return nil, fmt.Errorf("failed to fetch order: %w", err)
 	}
 	if order == nil {
-		return "", errors.New("order not found")
+		return nil, errors.New("order not found")
+	}
+
+	if access.Token != "" && s.downloadSvc != nil && s.downloadSvc.VerifyOrderToken(order, access.Token) {
+		return order, nil
+	}
+	if access.BuyerID != "" {
+		buyer, err := s.userRepo.FindByID(ctx, access.BuyerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch buyer: %w", err)
+		}
+		if buyer != nil && strings.EqualFold(strings.TrimSpace(order.CustomerEmail), buyer.Email) {
+			return order, nil
+		}
+	}
+	return nil, ErrOrderAccessDenied
+}
+
+// GetOrderFiles returns the latest version of every file in a paid order, keyed by product ID.
+func (s *OrderService) GetOrderFiles(ctx context.Context, orderID primitive.ObjectID, access OrderAccess) (map[string][]domain.ProductFile, error) {
+	order, err := s.authorizedOrder(ctx, orderID, access)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != domain.OrderStatusPaid {
+		return map[string][]domain.ProductFile{}, nil
+	}
+	return s.GetPurchasedFiles(ctx, []*domain.Order{order})
+}
+
+// GetOrderDownloadURL verifies order access and status and returns a download link for the
+// latest version of a product file; an empty requestedFileID selects the product's first file.
+// The ip and userAgent are recorded in the creator's download audit log.
+func (s *OrderService) GetOrderDownloadURL(ctx context.Context, orderID primitive.ObjectID, access OrderAccess, requestedProductID, requestedFileID, ip, userAgent string) (string, error) {
+	// 1. Fetch Order
+	order, err := s.authorizedOrder(ctx, orderID, access)
+	if err != nil {
+		return "", err
 	}
 
 	// 2. Validate Payment
@@ -557,12 +705,63 @@ func (s *OrderService) GetOrderDownloadURL(ctx context.Context, orderID primitiv
 	if product.ProductType != domain.ProductTypeDownload && product.ProductType != domain.ProductTypeCourse {
 		// allow digital products
 	}
-	if product.FileURL == "" {
-		return "", errors.New("product has no file")
+	var fileID primitive.ObjectID
+	if requestedFileID != "" {
+		if fileID, err = primitive.ObjectIDFromHex(requestedFileID); err != nil {
+			return "", ErrProductFileNotFound
+		}
+	}
+	file := product.DownloadFile(fileID)
+	if file == nil {
+		if fileID.IsZero() {
+			return "", errors.New("product has no file")
+		}
+		return "", ErrProductFileNotFound
 	}
 
-	// 6. Generate URL
-	return s.uploadSvc.GenerateDownloadURL(ctx, product.FileURL)
+	// 6. Generate URL, counting it against the buyer's entitlement
+	if s.downloadSvc != nil {
+		return s.downloadSvc.Redeem(ctx, order, product, file.ID, ip, userAgent)
+	}
+	return s.uploadSvc.GenerateDownloadURL(ctx, file.FileKey)
+}
+
+// GetPurchasedFiles returns the latest version of every file of the products in the given
+// orders, keyed by product ID. Version history is left out; buyers always get the newest release.
+func (s *OrderService) GetPurchasedFiles(ctx context.Context, orders []*domain.Order) (map[string][]domain.ProductFile, error) {
+	result := map[string][]domain.ProductFile{}
+	seen := map[primitive.ObjectID]bool{}
+	for _, order := range orders {
+		productIDs := []primitive.ObjectID{order.ProductID}
+		for _, li := range order.LineItems {
+			productIDs = append(productIDs, li.ProductID)
+		}
+		for _, productID := range productIDs {
+			if productID.IsZero() || seen[productID] {
+				continue
+			}
+			seen[productID] = true
+
+			product, err := s.productRepo.FindByID(ctx, productID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch product: %w", err)
+			}
+			if product == nil {
+				continue
+			}
+			files := product.DownloadFiles()
+			if len(files) == 0 {
+				continue
+			}
+			latest := make([]domain.ProductFile, len(files))
+			for i, f := range files {
+				f.History = nil
+				latest[i] = f
+			}
+			result[productID.Hex()] = latest
+		}
+	}
+	return result, nil
 }
 
 // GetOrder fetches a single order by its ID
@@ -570,13 +769,22 @@ func (s *OrderService) GetOrder(ctx context.Context, orderID primitive.ObjectID)
 	return s.orderRepo.FindByID(ctx, orderID)
 }
 
-// GetCreatorOrders fetches all orders for a specific creator
-func (s *OrderService) GetCreatorOrders(ctx context.Context, creatorID primitive.ObjectID) ([]*domain.Order, error) {
-	orders, err := s.orderRepo.FindAllByCreatorID(ctx, creatorID)
+// SearchCreatorOrders returns a page of a creator's orders matching filter, along with totals
+// for every matching order.
+func (s *OrderService) SearchCreatorOrders(ctx context.Context, filter domain.OrderFilter, sort domain.OrderSort, pagination *domain.Pagination) ([]*domain.Order, *domain.PaginationMeta, *domain.OrderTotals, error) {
+	if filter.CreatorID.IsZero() {
+		return nil, nil, nil, errors.New("creator id is required")
+	}
+
+	orders, meta, err := s.orderRepo.Search(ctx, filter, sort, pagination)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to search creator orders: %w", err)
+	}
+	totals, err := s.orderRepo.Totals(ctx, filter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch creator orders: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to total creator orders: %w", err)
 	}
-	return orders, nil
+	return orders, meta, totals, nil
 }
 
 // GetBuyerOrders fetches all purchases made by a specific buyer email
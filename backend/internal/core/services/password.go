@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrWeakPassword             = errors.New("password is too weak")
+	ErrInvalidResetToken        = errors.New("password reset link is invalid or has expired")
+	ErrPasswordResetRateLimited = errors.New("too many password reset requests, please try again later")
+	ErrNoPassword               = errors.New("this account has no password yet, use forgot password to set one")
+	ErrSamePassword             = errors.New("new password must be different from the current one")
+)
+
+const (
+	minPasswordLength = 10
+	maxPasswordLength = 72 // bcrypt ignores anything longer
+
+	passwordResetTTL         = 30 * time.Minute
+	passwordResetEmailLimit  = 3 // Reset emails per address per window
+	passwordResetEmailWindow = time.Hour
+	passwordResetIPLimit     = 10 // Reset requests per client IP per window
+	passwordResetIPWindow    = time.Hour
+)
+
+// commonPasswords are rejected outright even when they pass the character rules.
+var commonPasswords = map[string]bool{
+	"password123": true, "password1234": true, "passw0rd123": true, "qwerty12345": true,
+	"qwertyuiop1": true, "1234567890a": true, "a1234567890": true, "abc1234567": true,
+	"iloveyou123": true, "welcome123": true, "letmein123": true, "admin12345": true,
+	"changeme123": true, "football123": true, "sunshine123": true, "princess123": true,
+	"monkey12345": true, "dragon12345": true, "mystore123": true, "miostore123": true,
+}
+
+// validatePassword enforces the password policy: 10 to 72 characters, at least one letter and
+// one digit, not a well-known password and not built from the email address.
+func validatePassword(password, email string) error {
+	if len(password) < minPasswordLength {
+		return fmt.Errorf("%w: use at least %d characters", ErrWeakPassword, minPasswordLength)
+	}
+	if len(password) > maxPasswordLength {
+		return fmt.Errorf("%w: use at most %d characters", ErrWeakPassword, maxPasswordLength)
+	}
+
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	if !hasLetter || !hasDigit {
+		return fmt.Errorf("%w: include both letters and numbers", ErrWeakPassword)
+	}
+
+	lower := strings.ToLower(password)
+	if commonPasswords[lower] {
+		return fmt.Errorf("%w: this password is too common", ErrWeakPassword)
+	}
+	if local, _, _ := strings.Cut(strings.ToLower(email), "@"); len(local) >= 4 && strings.Contains(lower, local) {
+		return fmt.Errorf("%w: don't use your email address in your password", ErrWeakPassword)
+	}
+	return nil
+}
+
+// RequestPasswordReset emails a single-use reset link to a creator or admin account. It
+// returns nil for unknown addresses so the endpoint can't be used to discover accounts.
+func (s *AuthService) RequestPasswordReset(ctx context.Context, email, ip string) error {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return fmt.Errorf("email is required")
+	}
+
+	if ok, err := s.allowAttempt(ctx, "auth:password_reset_rate:email:"+email, passwordResetEmailLimit, passwordResetEmailWindow); err != nil {
+		return err
+	} else if !ok {
+		return ErrPasswordResetRateLimited
+	}
+	if ip != "" {
+		if ok, err := s.allowAttempt(ctx, "auth:password_reset_rate:ip:"+ip, passwordResetIPLimit, passwordResetIPWindow); err != nil {
+			return err
+		} else if !ok {
+			return ErrPasswordResetRateLimited
+		}
+	}
+
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return fmt.Errorf("lookup failed: %w", err)
+	}
+	if user == nil || user.Role == domain.RoleBuyer || user.Status == domain.UserStatusBanned {
+		return nil
+	}
+
+	token := generateMagicLinkToken()
+	tokenHash := hashToken(token)
+	userID := user.ID.Hex()
+
+	// Only the latest link works: replace any reset still pending for this user.
+	previous, err := s.redis.SetArgs(ctx, passwordResetUserKey(userID), tokenHash, redis.SetArgs{TTL: passwordResetTTL, Get: true}).Result()
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf("redis set: %w", err)
+	}
+	_, err = s.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if previous != "" {
+			pipe.Del(ctx, passwordResetKey(previous))
+		}
+		pipe.Set(ctx, passwordResetKey(tokenHash), userID, passwordResetTTL)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("store reset token: %w", err)
+	}
+
+	resetURL := fmt.Sprintf("%s/reset-password?token=%s", s.frontendURL, url.QueryEscape(token))
+	if s.emailService == nil {
+		logger.Info("password reset link generated (email disabled)", "email", email, "url", resetURL)
+		return nil
+	}
+
+	body := fmt.Sprintf(
+		"<div style='font-family:sans-serif;max-width:480px;margin:0 auto;padding:32px;'>"+
+			"<h2 style='color:#6C5CE7;'>Reset your password</h2>"+
+			"<p>We received a request to reset the password for your Mio Store account.</p>"+
+			"<p style='text-align:center;margin:24px 0;'><a href='%s' style='background:#6C5CE7;color:#fff;padding:12px 24px;border-radius:8px;text-decoration:none;font-weight:bold;'>Choose a new password</a></p>"+
+			"<p style='color:#888;font-size:14px;'>This link expires in %d minutes and can only be used once. If you didn't ask to reset your password, you can ignore this email.</p>"+
+			"</div>",
+		html.EscapeString(resetURL), int(passwordResetTTL.Minutes()),
+	)
+	if err := s.emailService.Send(ctx, email, "Reset your Mio Store password", body); err != nil {
+		return fmt.Errorf("send reset email: %w", err)
+	}
+	return nil
+}
+
+// ResetPassword sets a new password using a reset token and signs the user out everywhere.
+func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	tokenHash := hashToken(token)
+
+	// Check the token without consuming it, so a password that fails the policy can be retried.
+	userID, err := s.redis.Get(ctx, passwordResetKey(tokenHash)).Result()
+	if err == redis.Nil {
+		return ErrInvalidResetToken
+	} else if err != nil {
+		return fmt.Errorf("redis get: %w", err)
+	}
+
+	user, err := s.GetCurrentUser(ctx, userID)
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+	if err := validatePassword(newPassword, user.Email); err != nil {
+		return err
+	}
+
+	// Consume the token; if a concurrent request got there first, this one loses.
+	deleted, err := s.redis.Del(ctx, passwordResetKey(tokenHash)).Result()
+	if err != nil {
+		return fmt.Errorf("redis del: %w", err)
+	}
+	if deleted == 0 {
+		return ErrInvalidResetToken
+	}
+	s.redis.Del(ctx, passwordResetUserKey(userID))
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+	if err := s.userRepo.UpdatePassword(ctx, userID, string(hash)); err != nil {
+		return fmt.Errorf("update password: %w", err)
+	}
+
+	if s.sessions != nil {
+		if _, err := s.sessions.RevokeAll(ctx, userID); err != nil {
+			logger.Error("failed to revoke sessions after password reset", "error", err, "user_id", userID)
+		}
+	}
+	s.sendPasswordChangedEmail(ctx, user)
+	return nil
+}
+
+// ChangePassword replaces the password of a signed-in user after checking the current one.
+// Every other session is signed out; the one making the change stays signed in.
+func (s *AuthService) ChangePassword(ctx context.Context, userID, currentSessionID, currentPassword, newPassword string) error {
+	user, err := s.GetCurrentUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.PasswordHash == "" {
+		return ErrNoPassword
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPassword)); err != nil {
+		return ErrIncorrectPassword
+	}
+	if currentPassword == newPassword {
+		return ErrSamePassword
+	}
+	if err := validatePassword(newPassword, user.Email); err != nil {
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+	if err := s.userRepo.UpdatePassword(ctx, userID, string(hash)); err != nil {
+		return fmt.Errorf("update password: %w", err)
+	}
+
+	if s.sessions != nil {
+		if _, err := s.sessions.RevokeOthers(ctx, userID, currentSessionID); err != nil {
+			logger.Error("failed to revoke sessions after password change", "error", err, "user_id", userID)
+		}
+	}
+	s.sendPasswordChangedEmail(ctx, user)
+	return nil
+}
+
+// sendPasswordChangedEmail tells the user their password changed, in case it wasn't them.
+func (s *AuthService) sendPasswordChangedEmail(ctx context.Context, user *domain.User) {
+	if s.emailService == nil {
+		return
+	}
+	body := "<div style='font-family:sans-serif;max-width:480px;margin:0 auto;padding:32px;'>" +
+		"<h2 style='color:#6C5CE7;'>Your password was changed</h2>" +
+		"<p>The password for your Mio Store account was just changed and other devices have been signed out.</p>" +
+		"<p style='color:#888;font-size:14px;'>If this wasn't you, reset your password right away and contact support.</p>" +
+		"</div>"
+	if err := s.emailService.Send(ctx, user.Email, "Your Mio Store password was changed", body); err != nil {
+		logger.Error("failed to send password changed email", "error", err, "user_id", user.ID.Hex())
+	}
+}
+
+func passwordResetKey(tokenHash string) string {
+	return fmt.Sprintf("auth:password_reset:%s", tokenHash)
+}
+
+func passwordResetUserKey(userID string) string {
+	return fmt.Sprintf("auth:password_reset_user:%s", userID)
+}
@@ -29,6 +29,7 @@ type PayoutService struct {
 	accountNumber   string // RazorpayX business account number
 	keyID           string
 	keySecret       string
+	audit           *AuditService
 }
 
 // NewPayoutService creates a new PayoutService.
@@ -50,10 +51,15 @@ func NewPayoutService(
 	}
 }
 
+// SetAuditService injects the audit log (optional, records bank detail changes and payouts).
+func (s *PayoutService) SetAuditService(audit *AuditService) {
+	s.audit = audit
+}
+
 // ─── Configuration Methods ───
 
 // SavePayoutConfig orchestrates Contact → Fund Account → DB update.
-func (s *PayoutService) SavePayoutConfig(ctx context.Context, creatorID primitive.ObjectID, details domain.BankDetails) (*domain.PayoutConfig, error) {
+func (s *PayoutService) SavePayoutConfig(ctx context.Context, actor AuditActor, creatorID primitive.ObjectID, details domain.BankDetails) (*domain.PayoutConfig, error) {
 	if details.AccountHolderName == "" {
 		return nil, fmt.Errorf("account holder name is required")
 	}
@@ -109,11 +115,13 @@ func (s *PayoutService) SavePayoutConfig(ctx context.Context, creatorID primitiv
 		IsVerified:          true,
 	}
 
+	before := user.PayoutConfig
 	user.PayoutConfig = payoutCfg
 	user.UpdatedAt = time.Now()
 	if _, err := s.userRepo.Update(ctx, creatorID.Hex(), user); err != nil {
 		return nil, fmt.Errorf("failed to save payout config: %w", err)
 	}
+	s.audit.Record(ctx, actor, domain.AuditPayoutSettings, domain.AuditTargetUser, creatorID.Hex(), before, payoutCfg, "")
 
 	return payoutCfg, nil
 }
@@ -141,7 +149,7 @@ type BalanceSummary struct {
 }
 
 // WithdrawFunds initiates a payout to the creator's bank account.
-func (s *PayoutService) WithdrawFunds(ctx context.Context, creatorID primitive.ObjectID, amount int64) (*domain.Payout, error) {
+func (s *PayoutService) WithdrawFunds(ctx context.Context, actor AuditActor, creatorID primitive.ObjectID, amount int64) (*domain.Payout, error) {
 	// 1. Validate minimum amount
 	if amount < minWithdrawalAmount {
 		return nil, fmt.Errorf("minimum withdrawal amount is ₹100 (10000 paise)")
@@ -192,6 +200,7 @@ func (s *PayoutService) WithdrawFunds(ctx context.Context, creatorID primitive.O
 	if err := s.payoutRepo.Create(ctx, payout); err != nil {
 		return nil, fmt.Errorf("failed to save payout record: %w", err)
 	}
+	s.audit.Record(ctx, actor, domain.AuditPayoutWithdraw, domain.AuditTargetPayout, payout.ID.Hex(), nil, payout, "")
 
 	// 7. Create debit transaction
 	debitTx := &domain.Transaction{
@@ -211,10 +220,20 @@ func (s *PayoutService) WithdrawFunds(ctx context.Context, creatorID primitive.O
 
 // HandlePayoutWebhook processes Razorpay payout webhook events.
 func (s *PayoutService) HandlePayoutWebhook(ctx context.Context, razorpayPayoutID string, status domain.PayoutStatus) error {
+	before, err := s.payoutRepo.FindByRazorpayPayoutID(ctx, razorpayPayoutID)
+	if err != nil {
+		return fmt.Errorf("failed to find payout: %w", err)
+	}
+
 	// Update payout record
 	if err := s.payoutRepo.UpdateStatus(ctx, razorpayPayoutID, status); err != nil {
 		return fmt.Errorf("failed to update payout status: %w", err)
 	}
+	if before != nil {
+		s.audit.Record(ctx, SystemActor, domain.AuditPayoutStatus, domain.AuditTargetPayout, before.ID.Hex(),
+			map[string]domain.PayoutStatus{"status": before.Status}, map[string]domain.PayoutStatus{"status": status},
+			"razorpay webhook "+razorpayPayoutID)
+	}
 
 	// If failed/reversed, create a credit (reversal) transaction
 	if status == domain.PayoutStatusFailed || status == domain.PayoutStatusReversed {
@@ -19,6 +19,7 @@ type PlatformSubscriptionService struct {
 	userRepo        domain.UserRepository
 	referralRepo    domain.PlatformReferralRepository
 	transactionRepo domain.TransactionRepository
+	audit           *AuditService
 }
 
 // NewPlatformSubscriptionService creates a new PlatformSubscriptionService.
@@ -36,6 +37,11 @@ func NewPlatformSubscriptionService(
 	}
 }
 
+// SetAuditService injects the audit log (optional, records admin grants, revokes and new creators).
+func (s *PlatformSubscriptionService) SetAuditService(audit *AuditService) {
+	s.audit = audit
+}
+
 // StartTrial creates a 30-day trial subscription for a new creator.
 func (s *PlatformSubscriptionService) StartTrial(ctx context.Context, creatorID primitive.ObjectID, email, name string) error {
 	// Check if subscription already exists
@@ -176,11 +182,16 @@ func (s *PlatformSubscriptionService) CancelSubscription(ctx context.Context, cr
 // --- Admin Operations ---
 
 // AdminGrantSubscription manually grants a subscription to a creator.
-func (s *PlatformSubscriptionService) AdminGrantSubscription(ctx context.Context, creatorID primitive.ObjectID, months int) error {
+func (s *PlatformSubscriptionService) AdminGrantSubscription(ctx context.Context, actor AuditActor, creatorID primitive.ObjectID, months int, reason string) error {
 	sub, err := s.subRepo.FindByCreatorID(ctx, creatorID)
 	if err != nil {
 		return fmt.Errorf("failed to fetch subscription: %w", err)
 	}
+	var before *domain.PlatformSubscription
+	if sub != nil {
+		snapshot := *sub
+		before = &snapshot
+	}
 
 	now := time.Now()
 	periodEnd := now.AddDate(0, months, 0)
@@ -224,6 +235,8 @@ func (s *PlatformSubscriptionService) AdminGrantSubscription(ctx context.Context
 		s.userRepo.Update(ctx, creatorID.Hex(), user)
 	}
 
+	s.audit.Record(ctx, actor, domain.AuditSubscriptionGrant, domain.AuditTargetSubscription, creatorID.Hex(), before, sub, reason)
+
 	// Process referral commission (20% of ₹499 = ₹99.80 = 9980 paise)
 	s.processReferralCommission(ctx, creatorID, sub)
 
@@ -231,12 +244,22 @@ func (s *PlatformSubscriptionService) AdminGrantSubscription(ctx context.Context
 }
 
 // AdminRevokeSubscription revokes a creator's subscription.
-func (s *PlatformSubscriptionService) AdminRevokeSubscription(ctx context.Context, creatorID primitive.ObjectID) error {
-	return s.CancelSubscription(ctx, creatorID)
+func (s *PlatformSubscriptionService) AdminRevokeSubscription(ctx context.Context, actor AuditActor, creatorID primitive.ObjectID, reason string) error {
+	before, err := s.subRepo.FindByCreatorID(ctx, creatorID)
+	if err != nil {
+		return fmt.Errorf("failed to fetch subscription: %w", err)
+	}
+	if err := s.CancelSubscription(ctx, creatorID); err != nil {
+		return err
+	}
+
+	s.audit.Record(ctx, actor, domain.AuditSubscriptionRevoke, domain.AuditTargetSubscription, creatorID.Hex(),
+		map[string]string{"status": before.Status}, map[string]string{"status": domain.SubStatusCancelled}, reason)
+	return nil
 }
 
 // AdminAddCreator creates a new creator user with a trial subscription.
-func (s *PlatformSubscriptionService) AdminAddCreator(ctx context.Context, email, name string) (*domain.User, error) {
+func (s *PlatformSubscriptionService) AdminAddCreator(ctx context.Context, actor AuditActor, email, name string) (*domain.User, error) {
 	// Check if user already exists
 	existing, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
@@ -265,6 +288,7 @@ func (s *PlatformSubscriptionService) AdminAddCreator(ctx context.Context, email
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
+	s.audit.Record(ctx, actor, domain.AuditCreatorCreate, domain.AuditTargetUser, createdUser.ID.Hex(), nil, createdUser, "")
 
 	// Start trial for the new user
 	if err := s.StartTrial(ctx, createdUser.ID, email, name); err != nil {
@@ -0,0 +1,639 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+var (
+	ErrExportInProgress  = errors.New("a data export is already being prepared")
+	ErrExportRateLimited = errors.New("you can request one data export per day")
+	ErrExportNotFound    = errors.New("data export not found")
+	ErrExportNotReady    = errors.New("this data export is not ready to download")
+	ErrDeletionScheduled = errors.New("account deletion is already scheduled")
+	ErrNoDeletionPending = errors.New("no account deletion is scheduled")
+	ErrDeletionBlocked   = errors.New("cancel your active subscriptions before deleting your account")
+	ErrAdminSelfDeletion = errors.New("admin accounts can't be deleted from account settings")
+	ErrAccountDeleted    = errors.New("this account has been deleted")
+)
+
+const (
+	// dataExportRetention is how long a finished archive stays downloadable.
+	dataExportRetention = 7 * 24 * time.Hour
+	// dataExportInterval limits how often a user can build a new archive.
+	dataExportInterval = 24 * time.Hour
+	// dataExportStale is when a pending export is assumed lost and may be requested again.
+	dataExportStale   = time.Hour
+	dataExportLinkTTL = 15 * time.Minute
+	// accountDeletionCoolingOff is how long a deletion request can be cancelled.
+	accountDeletionCoolingOff = 14 * 24 * time.Hour
+)
+
+// PrivacyService gives buyers and creators a copy of their personal data and erases it on
+// request. Exports are zip archives of JSON files built in the background and handed out
+// through short-lived storage links. Deletion waits out a cooling-off period, then removes the
+// user's uploads, subscriber entries and course progress and anonymises the records that must
+// be kept for accounting, such as orders, bookings and the user record itself.
+type PrivacyService struct {
+	userRepo          domain.UserRepository
+	exportRepo        domain.DataExportRepository
+	orderRepo         domain.OrderRepository
+	subRepo           domain.SubscriptionRepository
+	bookingRepo       domain.BookingRepository
+	progressRepo      domain.CourseProgressRepository
+	subscriberRepo    domain.EmailSubscriberRepository
+	affiliateRepo     domain.AffiliateRepository
+	affiliateSaleRepo domain.AffiliateSaleRepository
+	platformSubRepo   domain.PlatformSubscriptionRepository
+	productRepo       domain.ProductRepository
+	teamRepo          domain.TeamMemberRepository
+	storage           domain.FileStorage
+	worker            *asynq.Client
+	emailService      domain.EmailService
+	sessions          *SessionService
+	audit             *AuditService
+	frontendURL       string
+}
+
+// NewPrivacyService creates a new PrivacyService.
+func NewPrivacyService(
+	userRepo domain.UserRepository,
+	exportRepo domain.DataExportRepository,
+	orderRepo domain.OrderRepository,
+	subRepo domain.SubscriptionRepository,
+	bookingRepo domain.BookingRepository,
+	progressRepo domain.CourseProgressRepository,
+	subscriberRepo domain.EmailSubscriberRepository,
+	affiliateRepo domain.AffiliateRepository,
+	affiliateSaleRepo domain.AffiliateSaleRepository,
+	platformSubRepo domain.PlatformSubscriptionRepository,
+	productRepo domain.ProductRepository,
+	teamRepo domain.TeamMemberRepository,
+	storage domain.FileStorage,
+	frontendURL string,
+) *PrivacyService {
+	return &PrivacyService{
+		userRepo:          userRepo,
+		exportRepo:        exportRepo,
+		orderRepo:         orderRepo,
+		subRepo:           subRepo,
+		bookingRepo:       bookingRepo,
+		progressRepo:      progressRepo,
+		subscriberRepo:    subscriberRepo,
+		affiliateRepo:     affiliateRepo,
+		affiliateSaleRepo: affiliateSaleRepo,
+		platformSubRepo:   platformSubRepo,
+		productRepo:       productRepo,
+		teamRepo:          teamRepo,
+		storage:           storage,
+		frontendURL:       strings.TrimRight(frontendURL, "/"),
+	}
+}
+
+// SetWorkerClient lets exports be built in the background worker.
+func (s *PrivacyService) SetWorkerClient(client *asynq.Client) {
+	s.worker = client
+}
+
+// SetEmailService enables the export-ready and deletion notices.
+func (s *PrivacyService) SetEmailService(emailService domain.EmailService) {
+	s.emailService = emailService
+}
+
+// SetSessionService lets deletion sign the user out everywhere.
+func (s *PrivacyService) SetSessionService(sessions *SessionService) {
+	s.sessions = sessions
+}
+
+// SetAuditService records deletion requests and completed deletions in the audit log.
+func (s *PrivacyService) SetAuditService(audit *AuditService) {
+	s.audit = audit
+}
+
+// --- Data export ---
+
+// RequestExport queues a new export of the user's data. Only one export can be in progress,
+// and a finished one can be rebuilt once a day.
+func (s *PrivacyService) RequestExport(ctx context.Context, userID string) (*domain.DataExport, error) {
+	user, err := s.activeUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := s.exportRepo.FindLatestByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if latest != nil {
+		age := time.Since(latest.CreatedAt)
+		if latest.Status == domain.DataExportPending && age < dataExportStale {
+			return nil, ErrExportInProgress
+		}
+		if latest.Status == domain.DataExportReady && age < dataExportInterval {
+			return nil, ErrExportRateLimited
+		}
+	}
+
+	export := &domain.DataExport{UserID: user.ID, Status: domain.DataExportPending}
+	if err := s.exportRepo.Create(ctx, export); err != nil {
+		return nil, err
+	}
+	s.scheduleExport(export.ID.Hex())
+	return export, nil
+}
+
+func (s *PrivacyService) scheduleExport(exportID string) {
+	if s.worker != nil {
+		if err := EnqueueDataExportTask(s.worker, exportID); err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+			logger.Error("failed to enqueue data export", "export_id", exportID, "error", err)
+		}
+		return
+	}
+	go func() {
+		if err := s.BuildExport(context.Background(), exportID); err != nil {
+			logger.Error("data export failed", "export_id", exportID, "error", err)
+		}
+	}()
+}
+
+// GetLatestExport returns the user's most recent export, or nil if they have never requested one.
+func (s *PrivacyService) GetLatestExport(ctx context.Context, userID string) (*domain.DataExport, error) {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	return s.exportRepo.FindLatestByUser(ctx, oid)
+}
+
+// DownloadURL returns a short-lived storage link to one of the user's finished exports.
+func (s *PrivacyService) DownloadURL(ctx context.Context, userID, exportID string) (string, error) {
+	oid, err := primitive.ObjectIDFromHex(exportID)
+	if err != nil {
+		return "", ErrExportNotFound
+	}
+	export, err := s.exportRepo.FindByID(ctx, oid)
+	if err != nil {
+		return "", err
+	}
+	if export == nil || export.UserID.Hex() != userID {
+		return "", ErrExportNotFound
+	}
+	if export.Status != domain.DataExportReady || export.ExpiresAt == nil || time.Now().After(*export.ExpiresAt) {
+		return "", ErrExportNotReady
+	}
+	return s.storage.GeneratePresignedDownloadURL(ctx, export.FileKey, dataExportLinkTTL)
+}
+
+// BuildExport assembles the archive for a pending export, stores it and tells the user it is
+// ready. Exports that are no longer pending are left alone, so a redelivered job is harmless.
+func (s *PrivacyService) BuildExport(ctx context.Context, exportID string) error {
+	oid, err := primitive.ObjectIDFromHex(exportID)
+	if err != nil {
+		return ErrExportNotFound
+	}
+	export, err := s.exportRepo.FindByID(ctx, oid)
+	if err != nil {
+		return err
+	}
+	if export == nil {
+		return ErrExportNotFound
+	}
+	if export.Status != domain.DataExportPending {
+		return nil
+	}
+
+	fail := func(cause error) error {
+		export.Status = domain.DataExportFailed
+		export.Error = "We couldn't prepare your export. Please try again."
+		if err := s.exportRepo.Update(ctx, export); err != nil {
+			logger.Error("failed to mark data export as failed", "export_id", exportID, "error", err)
+		}
+		return cause
+	}
+
+	user, err := s.userRepo.FindByID(ctx, export.UserID.Hex())
+	if err != nil {
+		return fail(err)
+	}
+	if user == nil || user.Status == domain.UserStatusDeleted {
+		return fail(ErrAccountDeleted)
+	}
+
+	archive, err := s.buildArchive(ctx, user)
+	if err != nil {
+		return fail(err)
+	}
+	key := fmt.Sprintf("exports/%s/%s.zip", user.ID.Hex(), export.ID.Hex())
+	if err := s.storage.Upload(ctx, key, "application/zip", archive); err != nil {
+		return fail(fmt.Errorf("upload data export: %w", err))
+	}
+
+	now := time.Now()
+	expires := now.Add(dataExportRetention)
+	export.Status = domain.DataExportReady
+	export.FileKey = key
+	export.Size = int64(len(archive))
+	export.CompletedAt = &now
+	export.ExpiresAt = &expires
+	if err := s.exportRepo.Update(ctx, export); err != nil {
+		return err
+	}
+
+	s.notify(ctx, user.Email, "Your Mio Store data export is ready",
+		"Your data export is ready",
+		fmt.Sprintf("The copy of your data you asked for is ready. You can download it from your account settings until %s.",
+			expires.UTC().Format("2 January 2006")),
+		"Download your data", s.accountURL(user))
+	return nil
+}
+
+// affiliateExport is one affiliate programme the user joined, with the sales they referred.
+type affiliateExport struct {
+	Affiliate *domain.Affiliate       `json:"affiliate"`
+	Sales     []*domain.AffiliateSale `json:"sales"`
+}
+
+// buildArchive collects everything stored about the user into a zip of JSON files.
+func (s *PrivacyService) buildArchive(ctx context.Context, user *domain.User) ([]byte, error) {
+	email := user.Email
+	profile := *user
+	profile.Impersonation = nil
+
+	orders, err := s.orderRepo.FindAllByCustomerEmail(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("load orders: %w", err)
+	}
+	subscriptions, err := s.subRepo.FindAllByCustomerEmail(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("load subscriptions: %w", err)
+	}
+	bookings, err := s.bookingRepo.FindByBuyerEmail(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("load bookings: %w", err)
+	}
+	progress, err := s.progressRepo.FindByUser(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("load course progress: %w", err)
+	}
+	subscribers, err := s.subscriberRepo.FindAllByEmail(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("load subscriber records: %w", err)
+	}
+	affiliates, err := s.affiliateRepo.FindAllByEmail(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("load affiliates: %w", err)
+	}
+	affiliateData := make([]affiliateExport, 0, len(affiliates))
+	for _, aff := range affiliates {
+		sales, err := s.affiliateSaleRepo.FindAllByAffiliate(ctx, aff.ID)
+		if err != nil {
+			return nil, fmt.Errorf("load affiliate sales: %w", err)
+		}
+		affiliateData = append(affiliateData, affiliateExport{Affiliate: aff, Sales: emptyIfNil(sales)})
+	}
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", profile},
+		{"orders.json", emptyIfNil(orders)},
+		{"subscriptions.json", emptyIfNil(subscriptions)},
+		{"bookings.json", emptyIfNil(bookings)},
+		{"course_progress.json", emptyIfNil(progress)},
+		{"subscriber_records.json", emptyIfNil(subscribers)},
+		{"affiliates.json", affiliateData},
+	}
+	if user.Role == domain.RoleCreator {
+		platformSub, err := s.platformSubRepo.FindByCreatorID(ctx, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("load platform subscription: %w", err)
+		}
+		files = append(files, struct {
+			name string
+			data interface{}
+		}{"platform_subscription.json", platformSub})
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.data); err != nil {
+			return nil, fmt.Errorf("encode %s: %w", f.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// PurgeExpiredExports deletes archives past their retention period.
+func (s *PrivacyService) PurgeExpiredExports(ctx context.Context) error {
+	expired, err := s.exportRepo.FindExpired(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, export := range expired {
+		if err := s.storage.Delete(ctx, export.FileKey); err != nil {
+			logger.Error("failed to delete expired data export", "export_id", export.ID.Hex(), "error", err)
+			continue
+		}
+		export.Status = domain.DataExportFailed
+		export.Error = "This export has expired. Request a new one."
+		export.FileKey = ""
+		if err := s.exportRepo.Update(ctx, export); err != nil {
+			logger.Error("failed to mark data export as expired", "export_id", export.ID.Hex(), "error", err)
+		}
+	}
+	return nil
+}
+
+// --- Account deletion ---
+
+// RequestDeletion schedules the user's account for deletion once the cooling-off period ends.
+// Until then the user can keep signing in and cancel.
+func (s *PrivacyService) RequestDeletion(ctx context.Context, actor AuditActor, userID string) (*domain.User, error) {
+	user, err := s.activeUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == domain.RoleAdmin {
+		return nil, ErrAdminSelfDeletion
+	}
+	if user.DeletionScheduledFor != nil {
+		return nil, ErrDeletionScheduled
+	}
+	if err := s.checkDeletable(ctx, user); err != nil {
+		return nil, err
+	}
+
+	at := time.Now().Add(accountDeletionCoolingOff)
+	if err := s.userRepo.UpdateDeletionSchedule(ctx, userID, &at); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	user.DeletionRequestedAt = &now
+	user.DeletionScheduledFor = &at
+
+	s.audit.Record(ctx, actor, domain.AuditAccountDeleteRequest, domain.AuditTargetUser, userID, nil,
+		map[string]interface{}{"deletion_scheduled_for": at}, "")
+	s.notify(ctx, user.Email, "Your Mio Store account will be deleted",
+		"Account deletion scheduled",
+		fmt.Sprintf("We'll permanently delete your account and personal data on %s. Orders and payouts are kept for our records with your name and email removed. Changed your mind? Cancel from your account settings before then.",
+			at.UTC().Format("2 January 2006")),
+		"Keep my account", s.accountURL(user))
+	return user, nil
+}
+
+// CancelDeletion withdraws a pending deletion request.
+func (s *PrivacyService) CancelDeletion(ctx context.Context, actor AuditActor, userID string) (*domain.User, error) {
+	user, err := s.activeUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.DeletionScheduledFor == nil {
+		return nil, ErrNoDeletionPending
+	}
+	if err := s.userRepo.UpdateDeletionSchedule(ctx, userID, nil); err != nil {
+		return nil, err
+	}
+	user.DeletionRequestedAt = nil
+	user.DeletionScheduledFor = nil
+
+	s.audit.Record(ctx, actor, domain.AuditAccountDeleteCancel, domain.AuditTargetUser, userID, nil, nil, "")
+	return user, nil
+}
+
+// ProcessDueDeletions erases every account whose cooling-off period has ended. An account that
+// can't be erased fully stays scheduled and is retried on the next run.
+func (s *PrivacyService) ProcessDueDeletions(ctx context.Context) error {
+	users, err := s.userRepo.FindDueForDeletion(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		if err := s.deleteAccount(ctx, user); err != nil {
+			logger.Error("failed to delete account", "user_id", user.ID.Hex(), "error", err)
+		}
+	}
+	return nil
+}
+
+// deleteAccount erases one account. The user record is anonymised last, so a failure part way
+// leaves the email the other steps look records up by, and the next run picks up from there.
+func (s *PrivacyService) deleteAccount(ctx context.Context, user *domain.User) error {
+	if err := s.checkDeletable(ctx, user); err != nil {
+		return err
+	}
+
+	userID := user.ID.Hex()
+	email := user.Email
+	anonEmail := deletedUserEmail(userID)
+	name := domain.DeletedUserName
+
+	// Financial records are kept, without the buyer's name or email
+	if err := s.orderRepo.AnonymiseCustomer(ctx, email, anonEmail, name); err != nil {
+		return fmt.Errorf("anonymise orders: %w", err)
+	}
+	if err := s.subRepo.AnonymiseCustomer(ctx, email, anonEmail, name); err != nil {
+		return fmt.Errorf("anonymise subscriptions: %w", err)
+	}
+	if err := s.bookingRepo.AnonymiseBuyer(ctx, email, anonEmail, name); err != nil {
+		return err
+	}
+	if err := s.affiliateRepo.Anonymise(ctx, email, anonEmail, name); err != nil {
+		return fmt.Errorf("anonymise affiliates: %w", err)
+	}
+
+	if err := s.subscriberRepo.DeleteByEmail(ctx, email); err != nil {
+		return fmt.Errorf("remove from subscriber lists: %w", err)
+	}
+	if err := s.progressRepo.DeleteByUser(ctx, user.ID); err != nil {
+		return err
+	}
+	memberships, err := s.teamRepo.FindActiveByUser(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if user.Role == domain.RoleCreator {
+		team, err := s.teamRepo.FindByCreator(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		memberships = append(memberships, team...)
+	}
+	for _, member := range memberships {
+		if err := s.teamRepo.Delete(ctx, member.ID); err != nil {
+			return err
+		}
+	}
+
+	if user.Role == domain.RoleCreator {
+		if err := s.closeStore(ctx, user, anonEmail); err != nil {
+			return err
+		}
+	}
+	if err := s.deleteFiles(ctx, "exports/"+userID+"/"); err != nil {
+		return err
+	}
+	if err := s.exportRepo.DeleteByUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	if err := s.userRepo.Anonymise(ctx, userID, anonEmail); err != nil {
+		return err
+	}
+	if s.sessions != nil {
+		if _, err := s.sessions.RevokeAll(ctx, userID); err != nil {
+			logger.Error("failed to revoke sessions of deleted account", "user_id", userID, "error", err)
+		}
+	}
+
+	s.audit.Record(ctx, SystemActor, domain.AuditAccountDelete, domain.AuditTargetUser, userID, nil, nil, "")
+	s.notify(ctx, email, "Your Mio Store account has been deleted",
+		"Your account has been deleted",
+		"As you asked, we've deleted your Mio Store account and the personal data attached to it. Thanks for being with us.",
+		"", "")
+	return nil
+}
+
+// closeStore takes a deleted creator's store down: products are unpublished through the usual
+// soft delete and every upload under creators/{id}/ is removed.
+func (s *PrivacyService) closeStore(ctx context.Context, user *domain.User, anonEmail string) error {
+	products, err := s.productRepo.FindAllByCreatorID(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("load products: %w", err)
+	}
+	for _, product := range products {
+		if err := s.productRepo.Delete(ctx, product.ID); err != nil {
+			return fmt.Errorf("delete product %s: %w", product.ID.Hex(), err)
+		}
+	}
+
+	platformSub, err := s.platformSubRepo.FindByCreatorID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if platformSub != nil {
+		platformSub.CreatorEmail = anonEmail
+		platformSub.CreatorName = domain.DeletedUserName
+		if err := s.platformSubRepo.Update(ctx, platformSub); err != nil {
+			return err
+		}
+	}
+
+	return s.deleteFiles(ctx, "creators/"+user.ID.Hex()+"/")
+}
+
+func (s *PrivacyService) deleteFiles(ctx context.Context, prefix string) error {
+	objects, err := s.storage.List(ctx, prefix)
+	if err != nil {
+		return fmt.Errorf("list %s: %w", prefix, err)
+	}
+	for _, obj := range objects {
+		if err := s.storage.Delete(ctx, obj.Key); err != nil {
+			return fmt.Errorf("delete %s: %w", obj.Key, err)
+		}
+	}
+	return nil
+}
+
+// checkDeletable refuses to delete an account that still has recurring charges running, which
+// would otherwise keep billing someone we can no longer identify.
+func (s *PrivacyService) checkDeletable(ctx context.Context, user *domain.User) error {
+	subs, err := s.subRepo.FindAllByCustomerEmail(ctx, user.Email)
+	if err != nil {
+		return err
+	}
+	for _, sub := range subs {
+		if sub.Status == domain.SubscriptionStatusActive || sub.Status == domain.SubscriptionStatusPastDue {
+			return ErrDeletionBlocked
+		}
+	}
+
+	if user.Role == domain.RoleCreator {
+		platformSub, err := s.platformSubRepo.FindByCreatorID(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		if platformSub != nil && platformSub.Status == domain.SubStatusActive && platformSub.RazorpaySubID != "" {
+			return ErrDeletionBlocked
+		}
+	}
+	return nil
+}
+
+func (s *PrivacyService) activeUser(ctx context.Context, userID string) (*domain.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	if user.Status == domain.UserStatusDeleted {
+		return nil, ErrAccountDeleted
+	}
+	return user, nil
+}
+
+// accountURL is where the user manages their data: dashboard settings for creators, the
+// purchases page for buyers.
+func (s *PrivacyService) accountURL(user *domain.User) string {
+	if user.Role == domain.RoleBuyer {
+		return s.frontendURL + "/my-purchases"
+	}
+	return s.frontendURL + "/dashboard/settings"
+}
+
+func (s *PrivacyService) notify(ctx context.Context, to, subject, heading, text, linkLabel, link string) {
+	if s.emailService == nil {
+		return
+	}
+	button := ""
+	if link != "" {
+		button = fmt.Sprintf("<p style='text-align:center;margin:24px 0;'><a href='%s' style='background:#6C5CE7;color:#fff;padding:12px 24px;border-radius:8px;text-decoration:none;font-weight:bold;'>%s</a></p>",
+			html.EscapeString(link), html.EscapeString(linkLabel))
+	}
+	body := fmt.Sprintf(
+		"<div style='font-family:sans-serif;max-width:480px;margin:0 auto;padding:32px;'>"+
+			"<h2 style='color:#6C5CE7;'>%s</h2><p>%s</p>%s</div>",
+		html.EscapeString(heading), html.EscapeString(text), button,
+	)
+	if err := s.emailService.Send(ctx, to, subject, body); err != nil {
+		logger.Error("failed to send privacy email", "subject", subject, "error", err)
+	}
+}
+
+// deletedUserEmail is the placeholder address left on records of a deleted account. It is
+// unique per user so kept records can still be told apart, and .invalid never delivers.
+func deletedUserEmail(userID string) string {
+	return fmt.Sprintf("deleted-%s@deleted.invalid", userID)
+}
+
+func emptyIfNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+)
+
+// maxProductFiles caps how many files a single product can deliver.
+const maxProductFiles = 20
+
+var (
+	ErrFileLabelRequired = errors.New("file label is required")
+	ErrFileLabelTooLong  = errors.New("file label exceeds 100 characters")
+	ErrTooManyFiles      = fmt.Errorf("a product can have at most %d files", maxProductFiles)
+	ErrLastProductFile   = errors.New("a product must keep at least one file")
+	ErrFileOrderMismatch = errors.New("file IDs must list each of the product's files exactly once")
+	ErrFileUnchanged     = errors.New("this file is already the current version")
+)
+
+// AddFile attaches a new labelled file to the end of a product's file list.
+func (s *ProductService) AddFile(ctx context.Context, id, creatorID string, req *domain.AddProductFileRequest) (*domain.Product, error) {
+	product, err := s.ownedProduct(ctx, id, creatorID)
+	if err != nil {
+		return nil, err
+	}
+	label, err := validateFileLabel(req.Label)
+	if err != nil {
+		return nil, err
+	}
+	adoptLegacyFile(product)
+	if len(product.Files) >= maxProductFiles {
+		return nil, ErrTooManyFiles
+	}
+	key, err := s.verifiedFileKey(ctx, product.CreatorID, req.FileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	product.Files = append(product.Files, domain.ProductFile{
+		ID:         primitive.NewObjectID(),
+		Label:      label,
+		FileKey:    key,
+		Version:    1,
+		Notes:      strings.TrimSpace(req.Notes),
+		ReleasedAt: time.Now(),
+	})
+	return s.saveFiles(ctx, product)
+}
+
+// ReplaceFile releases a new version of a file. The previous version is kept in the file's
+// history and buyers are optionally emailed a link to the update.
+func (s *ProductService) ReplaceFile(ctx context.Context, id, creatorID, fileID string, req *domain.ReplaceProductFileRequest) (*domain.Product, error) {
+	product, err := s.ownedProduct(ctx, id, creatorID)
+	if err != nil {
+		return nil, err
+	}
+	adoptLegacyFile(product)
+	i, err := fileIndex(product, fileID)
+	if err != nil {
+		return nil, err
+	}
+	key, err := s.verifiedFileKey(ctx, product.CreatorID, req.FileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &product.Files[i]
+	if key == file.FileKey {
+		return nil, ErrFileUnchanged
+	}
+	now := time.Now()
+	file.History = append(file.History, domain.FileVersion{
+		Version:    file.Version,
+		FileKey:    file.FileKey,
+		Notes:      file.Notes,
+		ReleasedAt: file.ReleasedAt,
+		ReplacedAt: now,
+	})
+	file.Version++
+	file.FileKey = key
+	file.Notes = strings.TrimSpace(req.Notes)
+	file.ReleasedAt = now
+
+	updated, err := s.saveFiles(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+	if req.NotifyBuyers && s.downloadSvc != nil {
+		s.downloadSvc.ScheduleFileUpdateNotice(updated, &updated.Files[i])
+	}
+	return updated, nil
+}
+
+// UpdateFile relabels a file without releasing a new version.
+func (s *ProductService) UpdateFile(ctx context.Context, id, creatorID, fileID string, req *domain.UpdateProductFileRequest) (*domain.Product, error) {
+	product, err := s.ownedProduct(ctx, id, creatorID)
+	if err != nil {
+		return nil, err
+	}
+	label, err := validateFileLabel(req.Label)
+	if err != nil {
+		return nil, err
+	}
+	adoptLegacyFile(product)
+	i, err := fileIndex(product, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	product.Files[i].Label = label
+	return s.saveFiles(ctx, product)
+}
+
+// RemoveFile detaches a file from a product. Buyers lose access to it; the stored objects are
+// left for the storage clean-up to reclaim.
+func (s *ProductService) RemoveFile(ctx context.Context, id, creatorID, fileID string) (*domain.Product, error) {
+	product, err := s.ownedProduct(ctx, id, creatorID)
+	if err != nil {
+		return nil, err
+	}
+	adoptLegacyFile(product)
+	i, err := fileIndex(product, fileID)
+	if err != nil {
+		return nil, err
+	}
+	if len(product.Files) == 1 {
+		return nil, ErrLastProductFile
+	}
+
+	product.Files = append(product.Files[:i], product.Files[i+1:]...)
+	return s.saveFiles(ctx, product)
+}
+
+// ReorderFiles sets the display order of a product's files. fileIDs must name every file once.
+func (s *ProductService) ReorderFiles(ctx context.Context, id, creatorID string, fileIDs []string) (*domain.Product, error) {
+	product, err := s.ownedProduct(ctx, id, creatorID)
+	if err != nil {
+		return nil, err
+	}
+	adoptLegacyFile(product)
+	if len(fileIDs) != len(product.Files) {
+		return nil, ErrFileOrderMismatch
+	}
+
+	byID := make(map[string]domain.ProductFile, len(product.Files))
+	for _, f := range product.Files {
+		byID[f.ID.Hex()] = f
+	}
+	ordered := make([]domain.ProductFile, 0, len(fileIDs))
+	for _, fid := range fileIDs {
+		f, ok := byID[fid]
+		if !ok {
+			return nil, ErrFileOrderMismatch
+		}
+		ordered = append(ordered, f)
+		delete(byID, fid)
+	}
+
+	product.Files = ordered
+	return s.saveFiles(ctx, product)
+}
+
+func (s *ProductService) ownedProduct(ctx context.Context, id, creatorID string) (*domain.Product, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("invalid product ID")
+	}
+	product, err := s.repo.FindByID(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+	if product.CreatorID.Hex() != creatorID {
+		return nil, errors.New("unauthorized: you do not own this product")
+	}
+	return product, nil
+}
+
+// verifiedFileKey normalises an uploaded file reference to its storage key.
+func (s *ProductService) verifiedFileKey(ctx context.Context, creatorID primitive.ObjectID, value string) (string, error) {
+	if s.uploadSvc != nil {
+		return s.uploadSvc.RequireVerified(ctx, creatorID, value)
+	}
+	if key := uploadKey(value); key != "" {
+		return key, nil
+	}
+	return "", ErrUploadUnverified
+}
+
+func (s *ProductService) saveFiles(ctx context.Context, product *domain.Product) (*domain.Product, error) {
+	product.FileURL = product.Files[0].FileKey
+	product.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, product); err != nil {
+		return nil, fmt.Errorf("failed to update product: %w", err)
+	}
+	s.invalidateStoreCache(ctx, product.CreatorID.Hex())
+	return product, nil
+}
+
+// adoptLegacyFile converts a single-file product to the file list before it is edited,
+// keeping the product ID as the file ID so existing download links keep working.
+func adoptLegacyFile(product *domain.Product) {
+	if len(product.Files) == 0 {
+		product.Files = product.DownloadFiles()
+	}
+}
+
+func fileIndex(product *domain.Product, fileID string) (int, error) {
+	for i, f := range product.Files {
+		if f.ID.Hex() == fileID {
+			return i, nil
+		}
+	}
+	return -1, ErrProductFileNotFound
+}
+
+func validateFileLabel(label string) (string, error) {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return "", ErrFileLabelRequired
+	}
+	if len(label) > 100 {
+		return "", ErrFileLabelTooLong
+	}
+	return label, nil
+}
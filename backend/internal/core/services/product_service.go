@@ -13,8 +13,10 @@ import (
 
 // ProductService handles business logic for products.
 type ProductService struct {
-	repo  domain.ProductRepository
-	cache domain.Cache
+	repo        domain.ProductRepository
+	cache       domain.Cache
+	uploadSvc   *UploadService
+	downloadSvc *DownloadService
 }
 
 // NewProductService creates a new ProductService.
@@ -25,6 +27,37 @@ func NewProductService(repo domain.ProductRepository, cache domain.Cache) *Produ
 	}
 }
 
+// SetUploadService requires product files and uploaded cover images to be verified uploads.
+func (s *ProductService) SetUploadService(uploadSvc *UploadService) {
+	s.uploadSvc = uploadSvc
+}
+
+// SetDownloadService lets file updates be announced to a product's buyers.
+func (s *ProductService) SetDownloadService(downloadSvc *DownloadService) {
+	s.downloadSvc = downloadSvc
+}
+
+// checkUploads verifies the files attached to a product. The product file is normalised to its
+// storage key; cover images hosted elsewhere are left alone.
+func (s *ProductService) checkUploads(ctx context.Context, p *domain.Product, checkFile, checkCover bool) error {
+	if s.uploadSvc == nil {
+		return nil
+	}
+	if checkFile && p.FileURL != "" {
+		key, err := s.uploadSvc.RequireVerified(ctx, p.CreatorID, p.FileURL)
+		if err != nil {
+			return err
+		}
+		p.FileURL = key
+	}
+	if checkCover && uploadKey(p.CoverImageURL) != "" {
+		if _, err := s.uploadSvc.RequireVerified(ctx, p.CreatorID, p.CoverImageURL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *ProductService) invalidateStoreCache(ctx context.Context, creatorID string) {
 	if s.cache != nil {
 		_ = s.cache.Delete(ctx, fmt.Sprintf("cache:store:id:%s", creatorID))
@@ -36,6 +69,9 @@ func (s *ProductService) CreateProduct(ctx context.Context, input *domain.Produc
 	if err := s.validateProduct(input); err != nil {
 		return nil, err
 	}
+	if err := s.checkUploads(ctx, input, true, true); err != nil {
+		return nil, err
+	}
 
 	// Set default values
 	input.ID = primitive.NewObjectID()
@@ -123,8 +159,11 @@ func (s *ProductService) UpdateProduct(ctx context.Context, id string, creatorID
 	if updates.Price != 0 {
 		existing.Price = updates.Price
 	}
-	if updates.CoverImageURL != "" {
+	if updates.CoverImageURL != "" && updates.CoverImageURL != existing.CoverImageURL {
 		existing.CoverImageURL = updates.CoverImageURL
+		if err := s.checkUploads(ctx, existing, false, true); err != nil {
+			return nil, err
+		}
 	}
 	if updates.DurationMinutes != 0 {
 		existing.DurationMinutes = updates.DurationMinutes
@@ -135,6 +174,15 @@ func (s *ProductService) UpdateProduct(ctx context.Context, id string, creatorID
 	if updates.CancellationWindowHours != 0 {
 		existing.CancellationWindowHours = updates.CancellationWindowHours
 	}
+	if updates.SeatsPerSlot != 0 {
+		existing.SeatsPerSlot = updates.SeatsPerSlot
+	}
+	if updates.DownloadLimit != 0 {
+		existing.DownloadLimit = updates.DownloadLimit
+	}
+	if updates.DownloadExpiryDays != 0 {
+		existing.DownloadExpiryDays = updates.DownloadExpiryDays
+	}
 	if updates.Availability != nil {
 		existing.Availability = updates.Availability
 	}
@@ -226,6 +274,15 @@ func (s *ProductService) validateProduct(p *domain.Product) error {
 	if p.Price > 10000000 {
 		return errors.New("price exceeds limit of ₹1,00,000")
 	}
+	if p.SeatsPerSlot < 0 || p.SeatsPerSlot > 500 {
+		return errors.New("seats per slot must be between 1 and 500")
+	}
+	if p.DownloadLimit < 0 || p.DownloadLimit > 1000 {
+		return errors.New("download limit must be between 0 and 1000")
+	}
+	if p.DownloadExpiryDays < 0 || p.DownloadExpiryDays > 3650 {
+		return errors.New("download expiry must be between 0 and 3650 days")
+	}
 	return nil
 }
 
@@ -290,6 +347,44 @@ func (s *ProductService) ReorderProducts(ctx context.Context, creatorID string,
 	return err
 }
 
+// UpdatePDFStamping turns buyer-personalised PDF downloads on or off for a product.
+func (s *ProductService) UpdatePDFStamping(ctx context.Context, id string, creatorID string, enabled bool) (*domain.Product, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("invalid product ID")
+	}
+
+	product, err := s.repo.FindByID(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, errors.New("product not found")
+	}
+	if product.CreatorID.Hex() != creatorID {
+		return nil, errors.New("unauthorized: you do not own this product")
+	}
+	if enabled && !hasPDFFile(product) {
+		return nil, errors.New("stamping is only available for products with a PDF file")
+	}
+
+	product.StampPDF = enabled
+	product.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, product); err != nil {
+		return nil, fmt.Errorf("failed to update product: %w", err)
+	}
+	return product, nil
+}
+
+func hasPDFFile(product *domain.Product) bool {
+	for _, f := range product.DownloadFiles() {
+		if isPDFKey(f.FileKey) {
+			return true
+		}
+	}
+	return false
+}
+
 // UpdateBumpConfig updates the bump configuration for a product.
 func (s *ProductService) UpdateBumpConfig(ctx context.Context, id string, creatorID string, bumpProductID string, bumpDiscount int64) error {
 	oid, err := primitive.ObjectIDFromHex(id)
@@ -0,0 +1,491 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used")
+	ErrSessionRevoked      = errors.New("session has been revoked")
+	ErrSessionNotFound     = errors.New("session not found")
+)
+
+// sessionTouchInterval limits how often a request refreshes the session's last-seen time.
+const sessionTouchInterval = time.Minute
+
+// rotateRefreshScript swaps the session's refresh token hash only if the presented token is
+// the current one, so two concurrent refreshes can't both succeed.
+// Returns 1 on success, 0 if the token does not match, -1 if the session is gone.
+var rotateRefreshScript = redis.NewScript(`
+local current = redis.call('HGET', KEYS[1], 'refresh_hash')
+if not current then return -1 end
+if current ~= ARGV[1] then return 0 end
+redis.call('HSET', KEYS[1], 'refresh_hash', ARGV[2], 'last_seen_at', ARGV[3], 'ip', ARGV[4], 'expires_at', ARGV[5])
+redis.call('EXPIRE', KEYS[1], ARGV[6])
+return 1
+`)
+
+// touchSessionScript records activity without recreating a session revoked meanwhile.
+var touchSessionScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+  redis.call('HSET', KEYS[1], 'last_seen_at', ARGV[1], 'ip', ARGV[2])
+end
+return 0
+`)
+
+// ClientInfo describes the device a request came from.
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
+
+// SessionTokens is the token pair issued when a session is created or refreshed.
+type SessionTokens struct {
+	SessionID        string
+	AccessToken      string
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// SessionService manages signed-in sessions in Redis. Each session holds the hash of its
+// current refresh token; access tokens carry the session ID and are checked against Redis on
+// every request, so revoking a session takes effect immediately.
+type SessionService struct {
+	redis      *redis.Client
+	jwtService *JWTService
+	userRepo   domain.UserRepository
+}
+
+// NewSessionService creates a new SessionService.
+func NewSessionService(redisClient *redis.Client, jwtService *JWTService, userRepo domain.UserRepository) *SessionService {
+	return &SessionService{
+		redis:      redisClient,
+		jwtService: jwtService,
+		userRepo:   userRepo,
+	}
+}
+
+// Create starts a new session for user and returns its first token pair.
+func (s *SessionService) Create(ctx context.Context, user *domain.User, client ClientInfo) (*SessionTokens, error) {
+	sessionID, err := randomToken(16, hex.EncodeToString)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomToken(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return nil, err
+	}
+
+	userID := user.ID.Hex()
+	lifetime := refreshLifetime(user.Role)
+	now := time.Now()
+	expiresAt := now.Add(lifetime)
+
+	_, err = s.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, sessionKey(sessionID), map[string]interface{}{
+			"user_id":      userID,
+			"refresh_hash": hashToken(secret),
+			"user_agent":   truncate(client.UserAgent, 512),
+			"ip":           client.IP,
+			"created_at":   now.Unix(),
+			"last_seen_at": now.Unix(),
+			"expires_at":   expiresAt.Unix(),
+		})
+		pipe.Expire(ctx, sessionKey(sessionID), lifetime)
+		pipe.SAdd(ctx, userSessionsKey(userID), sessionID)
+		pipe.Expire(ctx, userSessionsKey(userID), jwtExpiry)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("store session: %w", err)
+	}
+
+	return s.issue(user, sessionID, secret, expiresAt)
+}
+
+// CreateImpersonation opens a session on creator's account for adminID and returns its
+// access token. The session has no refresh token and lapses after ttl. It is indexed with the
+// creator's sessions, so revoking all of them (for example on a ban) ends it too, but List
+// leaves it out, since it is not one of their devices.
+func (s *SessionService) CreateImpersonation(ctx context.Context, adminID string, creator *domain.User, reason string, ttl time.Duration, client ClientInfo) (*domain.Impersonation, string, error) {
+	sessionID, err := randomToken(16, hex.EncodeToString)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	imp := &domain.Impersonation{
+		SessionID: sessionID,
+		AdminID:   adminID,
+		CreatorID: creator.ID.Hex(),
+		Reason:    reason,
+		ReadOnly:  true,
+		StartedAt: now.UTC(),
+		ExpiresAt: now.Add(ttl).UTC(),
+	}
+	_, err = s.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, sessionKey(sessionID), map[string]interface{}{
+			"user_id":         imp.CreatorID,
+			"impersonator_id": adminID,
+			"reason":          truncate(reason, 500),
+			"user_agent":      truncate(client.UserAgent, 512),
+			"ip":              client.IP,
+			"created_at":      now.Unix(),
+			"last_seen_at":    now.Unix(),
+			"expires_at":      imp.ExpiresAt.Unix(),
+		})
+		pipe.Expire(ctx, sessionKey(sessionID), ttl)
+		pipe.SAdd(ctx, userSessionsKey(imp.CreatorID), sessionID)
+		pipe.Expire(ctx, userSessionsKey(imp.CreatorID), jwtExpiry)
+		return nil
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("store impersonation session: %w", err)
+	}
+
+	token, err := s.jwtService.GenerateImpersonationToken(imp.CreatorID, creator.Role, sessionID, adminID, ttl)
+	if err != nil {
+		return nil, "", fmt.Errorf("generate token: %w", err)
+	}
+	return imp, token, nil
+}
+
+// Impersonation returns the live impersonation session with this ID.
+func (s *SessionService) Impersonation(ctx context.Context, sessionID string) (*domain.Impersonation, error) {
+	vals, err := s.redis.HMGet(ctx, sessionKey(sessionID), "user_id", "impersonator_id", "reason", "created_at", "expires_at").Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis get: %w", err)
+	}
+	creatorID, _ := vals[0].(string)
+	adminID, _ := vals[1].(string)
+	if creatorID == "" || adminID == "" {
+		return nil, ErrSessionNotFound
+	}
+	reason, _ := vals[2].(string)
+	createdAt, _ := vals[3].(string)
+	expiresAt, _ := vals[4].(string)
+	return &domain.Impersonation{
+		SessionID: sessionID,
+		AdminID:   adminID,
+		CreatorID: creatorID,
+		Reason:    reason,
+		ReadOnly:  true,
+		StartedAt: unixField(createdAt),
+		ExpiresAt: unixField(expiresAt),
+	}, nil
+}
+
+// Refresh rotates a refresh token, returning a new token pair and the session's user.
+// Presenting a token that has already been rotated revokes the session, since either the
+// user or an attacker is holding a stolen copy.
+func (s *SessionService) Refresh(ctx context.Context, refreshToken string, client ClientInfo) (*SessionTokens, *domain.User, error) {
+	sessionID, secret, ok := strings.Cut(refreshToken, ".")
+	if !ok || sessionID == "" || secret == "" {
+		return nil, nil, ErrInvalidRefreshToken
+	}
+
+	userID, err := s.redis.HGet(ctx, sessionKey(sessionID), "user_id").Result()
+	if err == redis.Nil {
+		return nil, nil, ErrInvalidRefreshToken
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("redis get: %w", err)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("find user: %w", err)
+	}
+	if user == nil || user.Status == domain.UserStatusBanned || user.Status == domain.UserStatusDeleted {
+		s.revoke(ctx, userID, sessionID)
+		return nil, nil, ErrSessionRevoked
+	}
+
+	newSecret, err := randomToken(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return nil, nil, err
+	}
+	lifetime := refreshLifetime(user.Role)
+	now := time.Now()
+	expiresAt := now.Add(lifetime)
+
+	res, err := rotateRefreshScript.Run(ctx, s.redis, []string{sessionKey(sessionID)},
+		hashToken(secret), hashToken(newSecret), now.Unix(), client.IP, expiresAt.Unix(), int(lifetime.Seconds()),
+	).Int()
+	if err != nil {
+		return nil, nil, fmt.Errorf("rotate refresh token: %w", err)
+	}
+	switch res {
+	case -1:
+		return nil, nil, ErrInvalidRefreshToken
+	case 0:
+		logger.Warn("refresh token reuse detected, revoking session", "user_id", userID, "session_id", sessionID, "ip", client.IP)
+		s.revoke(ctx, userID, sessionID)
+		return nil, nil, ErrRefreshTokenReused
+	}
+	s.redis.Expire(ctx, userSessionsKey(userID), jwtExpiry)
+
+	tokens, err := s.issue(user, sessionID, newSecret, expiresAt)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tokens, user, nil
+}
+
+// Validate checks that an access token's session is still live and records activity on it.
+func (s *SessionService) Validate(ctx context.Context, sessionID, userID, ip string) error {
+	vals, err := s.redis.HMGet(ctx, sessionKey(sessionID), "user_id", "last_seen_at").Result()
+	if err != nil {
+		return fmt.Errorf("redis get: %w", err)
+	}
+	owner, _ := vals[0].(string)
+	if owner == "" || owner != userID {
+		return ErrSessionRevoked
+	}
+
+	lastSeen, _ := vals[1].(string)
+	if unix, _ := strconv.ParseInt(lastSeen, 10, 64); time.Since(time.Unix(unix, 0)) > sessionTouchInterval {
+		touchSessionScript.Run(ctx, s.redis, []string{sessionKey(sessionID)}, time.Now().Unix(), ip)
+	}
+	return nil
+}
+
+// List returns the user's live sessions, most recently active first. The session identified
+// by currentID is flagged as current.
+func (s *SessionService) List(ctx context.Context, userID, currentID string) ([]domain.Session, error) {
+	ids, err := s.redis.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis smembers: %w", err)
+	}
+
+	sessions := make([]domain.Session, 0, len(ids))
+	for _, id := range ids {
+		vals, err := s.redis.HGetAll(ctx, sessionKey(id)).Result()
+		if err != nil {
+			return nil, fmt.Errorf("redis get: %w", err)
+		}
+		if vals["user_id"] != userID {
+			// Expired; drop the stale index entry
+			s.redis.SRem(ctx, userSessionsKey(userID), id)
+			continue
+		}
+		if vals["impersonator_id"] != "" {
+			continue
+		}
+		sessions = append(sessions, domain.Session{
+			ID:         id,
+			UserID:     userID,
+			Device:     describeDevice(vals["user_agent"]),
+			UserAgent:  vals["user_agent"],
+			IP:         vals["ip"],
+			CreatedAt:  unixField(vals["created_at"]),
+			LastSeenAt: unixField(vals["last_seen_at"]),
+			ExpiresAt:  unixField(vals["expires_at"]),
+			Current:    id == currentID,
+		})
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+	return sessions, nil
+}
+
+// Revoke ends one of the user's sessions.
+func (s *SessionService) Revoke(ctx context.Context, userID, sessionID string) error {
+	owner, err := s.redis.HGet(ctx, sessionKey(sessionID), "user_id").Result()
+	if err == redis.Nil || (err == nil && owner != userID) {
+		return ErrSessionNotFound
+	} else if err != nil {
+		return fmt.Errorf("redis get: %w", err)
+	}
+	return s.revoke(ctx, userID, sessionID)
+}
+
+// RevokeByRefreshToken ends the session a refresh token belongs to, as long as the token is
+// the session's current one.
+func (s *SessionService) RevokeByRefreshToken(ctx context.Context, refreshToken string) error {
+	sessionID, secret, ok := strings.Cut(refreshToken, ".")
+	if !ok || sessionID == "" {
+		return ErrInvalidRefreshToken
+	}
+	vals, err := s.redis.HMGet(ctx, sessionKey(sessionID), "user_id", "refresh_hash").Result()
+	if err != nil {
+		return fmt.Errorf("redis get: %w", err)
+	}
+	userID, _ := vals[0].(string)
+	hash, _ := vals[1].(string)
+	if userID == "" || hash != hashToken(secret) {
+		return ErrInvalidRefreshToken
+	}
+	return s.revoke(ctx, userID, sessionID)
+}
+
+// RevokeAll ends every session of the user and returns how many were revoked.
+func (s *SessionService) RevokeAll(ctx context.Context, userID string) (int, error) {
+	return s.RevokeOthers(ctx, userID, "")
+}
+
+// RevokeOthers ends every session of the user except keepID and returns how many were revoked.
+func (s *SessionService) RevokeOthers(ctx context.Context, userID, keepID string) (int, error) {
+	ids, err := s.redis.SMembers(ctx, userSessionsKey(userID)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("redis smembers: %w", err)
+	}
+
+	ended := make([]string, 0, len(ids))
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == keepID {
+			continue
+		}
+		ended = append(ended, id)
+		keys = append(keys, sessionKey(id))
+	}
+
+	var revoked *redis.IntCmd
+	_, err = s.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if len(keys) > 0 {
+			revoked = pipe.Del(ctx, keys...)
+		}
+		if keepID == "" {
+			pipe.Del(ctx, userSessionsKey(userID))
+		} else if len(ended) > 0 {
+			pipe.SRem(ctx, userSessionsKey(userID), ended)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("revoke sessions: %w", err)
+	}
+	if revoked == nil {
+		return 0, nil
+	}
+	return int(revoked.Val()), nil
+}
+
+func (s *SessionService) revoke(ctx context.Context, userID, sessionID string) error {
+	_, err := s.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, sessionKey(sessionID))
+		pipe.SRem(ctx, userSessionsKey(userID), sessionID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("revoke session: %w", err)
+	}
+	return nil
+}
+
+func (s *SessionService) issue(user *domain.User, sessionID, secret string, expiresAt time.Time) (*SessionTokens, error) {
+	accessToken, err := s.jwtService.GenerateSessionToken(user.ID.Hex(), user.Role, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("generate token: %w", err)
+	}
+	return &SessionTokens{
+		SessionID:        sessionID,
+		AccessToken:      accessToken,
+		RefreshToken:     sessionID + "." + secret,
+		RefreshExpiresAt: expiresAt,
+	}, nil
+}
+
+// refreshLifetime keeps the previous login lengths: buyers stay signed in for a day of
+// inactivity, everyone else for a week.
+func refreshLifetime(role string) time.Duration {
+	if role == domain.RoleBuyer {
+		return BuyerJWTExpiry
+	}
+	return jwtExpiry
+}
+
+func sessionKey(sessionID string) string {
+	return fmt.Sprintf("auth:session:%s", sessionID)
+}
+
+func userSessionsKey(userID string) string {
+	return fmt.Sprintf("auth:user_sessions:%s", userID)
+}
+
+// hashToken is how secrets sent to users (refresh tokens, reset links) are stored.
+func hashToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomToken(n int, encode func([]byte) string) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	return encode(b), nil
+}
+
+func unixField(v string) time.Time {
+	unix, _ := strconv.ParseInt(v, 10, 64)
+	return time.Unix(unix, 0).UTC()
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
+
+// describeDevice turns a User-Agent into a short label such as "Chrome on macOS".
+func describeDevice(ua string) string {
+	browser := ""
+	switch {
+	case strings.Contains(ua, "Edg/"):
+		browser = "Edge"
+	case strings.Contains(ua, "OPR/"):
+		browser = "Opera"
+	case strings.Contains(ua, "Firefox/"), strings.Contains(ua, "FxiOS/"):
+		browser = "Firefox"
+	case strings.Contains(ua, "Chrome/"), strings.Contains(ua, "CriOS/"):
+		browser = "Chrome"
+	case strings.Contains(ua, "Safari/"):
+		browser = "Safari"
+	}
+
+	os := ""
+	switch {
+	case strings.Contains(ua, "iPhone"):
+		os = "iPhone"
+	case strings.Contains(ua, "iPad"):
+		os = "iPad"
+	case strings.Contains(ua, "Android"):
+		os = "Android"
+	case strings.Contains(ua, "Windows"):
+		os = "Windows"
+	case strings.Contains(ua, "Mac OS X"):
+		os = "macOS"
+	case strings.Contains(ua, "Linux"):
+		os = "Linux"
+	}
+
+	switch {
+	case browser != "" && os != "":
+		return browser + " on " + os
+	case browser != "":
+		return browser
+	case os != "":
+		return os
+	}
+	return "Unknown device"
+}
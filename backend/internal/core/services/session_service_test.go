@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+)
+
+// sessionUserRepo serves a single user whose status the test can change.
+type sessionUserRepo struct {
+	domain.UserRepository
+	user *domain.User
+}
+
+func (r *sessionUserRepo) FindByID(ctx context.Context, id string) (*domain.User, error) {
+	if r.user == nil || r.user.ID.Hex() != id {
+		return nil, nil
+	}
+	u := *r.user
+	return &u, nil
+}
+
+// testRedis connects to REDIS_URL, skipping the test when no server is reachable.
+func testRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	addr := os.Getenv("REDIS_URL")
+	if addr == "" {
+		addr = "redis://localhost:6379"
+	}
+	opts, err := redis.ParseURL(addr)
+	require.NoError(t, err)
+	client := redis.NewClient(opts)
+	t.Cleanup(func() { client.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		t.Skipf("redis not available at %s: %v", addr, err)
+	}
+	return client
+}
+
+func TestSessionRefresh(t *testing.T) {
+	rdb := testRedis(t)
+	jwtService := NewJWTService("test-secret")
+	client := ClientInfo{UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_0) Chrome/120.0", IP: "203.0.113.7"}
+
+	tests := []struct {
+		name        string
+		status      string
+		present     func(first, rotated *SessionTokens) string
+		wantErr     error
+		wantRevoked bool
+	}{
+		{
+			name:    "latest token rotates",
+			status:  domain.UserStatusActive,
+			present: func(first, rotated *SessionTokens) string { return rotated.RefreshToken },
+		},
+		{
+			name:        "already rotated token revokes the session",
+			status:      domain.UserStatusActive,
+			present:     func(first, rotated *SessionTokens) string { return first.RefreshToken },
+			wantErr:     ErrRefreshTokenReused,
+			wantRevoked: true,
+		},
+		{
+			name:        "forged secret revokes the session",
+			status:      domain.UserStatusActive,
+			present:     func(first, rotated *SessionTokens) string { return rotated.SessionID + ".forged" },
+			wantErr:     ErrRefreshTokenReused,
+			wantRevoked: true,
+		},
+		{
+			name:        "banned user",
+			status:      domain.UserStatusBanned,
+			present:     func(first, rotated *SessionTokens) string { return rotated.RefreshToken },
+			wantErr:     ErrSessionRevoked,
+			wantRevoked: true,
+		},
+		{
+			name:        "deleted user",
+			status:      domain.UserStatusDeleted,
+			present:     func(first, rotated *SessionTokens) string { return rotated.RefreshToken },
+			wantErr:     ErrSessionRevoked,
+			wantRevoked: true,
+		},
+		{
+			name:    "unknown session",
+			status:  domain.UserStatusActive,
+			present: func(first, rotated *SessionTokens) string { return "0123456789abcdef." + "secret" },
+			wantErr: ErrInvalidRefreshToken,
+		},
+		{
+			name:    "malformed token",
+			status:  domain.UserStatusActive,
+			present: func(first, rotated *SessionTokens) string { return rotated.SessionID },
+			wantErr: ErrInvalidRefreshToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			user := &domain.User{ID: primitive.NewObjectID(), Role: domain.RoleCreator, Status: domain.UserStatusActive}
+			repo := &sessionUserRepo{user: user}
+			svc := NewSessionService(rdb, jwtService, repo)
+			userID := user.ID.Hex()
+			t.Cleanup(func() { svc.RevokeAll(context.Background(), userID) })
+
+			first, err := svc.Create(ctx, user, client)
+			require.NoError(t, err)
+			rotated, _, err := svc.Refresh(ctx, first.RefreshToken, client)
+			require.NoError(t, err)
+			require.Equal(t, first.SessionID, rotated.SessionID)
+			require.NotEqual(t, first.RefreshToken, rotated.RefreshToken)
+
+			repo.user.Status = tt.status
+			revoked := tt.wantRevoked
+			tokens, got, err := svc.Refresh(ctx, tt.present(first, rotated), client)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, userID, got.ID.Hex())
+				claims, err := jwtService.ValidateToken(tokens.AccessToken)
+				require.NoError(t, err)
+				assert.Equal(t, rotated.SessionID, claims.SessionID)
+
+				// The token just presented is now spent too
+				_, _, err = svc.Refresh(ctx, rotated.RefreshToken, client)
+				assert.ErrorIs(t, err, ErrRefreshTokenReused)
+				revoked = true
+			}
+
+			err = svc.Validate(ctx, first.SessionID, userID, client.IP)
+			if revoked {
+				assert.ErrorIs(t, err, ErrSessionRevoked)
+				sessions, err := svc.List(ctx, userID, "")
+				require.NoError(t, err)
+				assert.Empty(t, sessions)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSessionRefresh_ConcurrentRotationOnlyOneWins(t *testing.T) {
+	rdb := testRedis(t)
+	ctx := context.Background()
+	user := &domain.User{ID: primitive.NewObjectID(), Role: domain.RoleCreator, Status: domain.UserStatusActive}
+	svc := NewSessionService(rdb, NewJWTService("test-secret"), &sessionUserRepo{user: user})
+	t.Cleanup(func() { svc.RevokeAll(context.Background(), user.ID.Hex()) })
+
+	tokens, err := svc.Create(ctx, user, ClientInfo{})
+	require.NoError(t, err)
+
+	const attempts = 8
+	errs := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		go func() {
+			_, _, err := svc.Refresh(ctx, tokens.RefreshToken, ClientInfo{})
+			errs <- err
+		}()
+	}
+	var ok int
+	for i := 0; i < attempts; i++ {
+		if err := <-errs; err == nil {
+			ok++
+		} else {
+			assert.True(t, err == ErrRefreshTokenReused || err == ErrInvalidRefreshToken, "unexpected error %v", err)
+		}
+	}
+	assert.Equal(t, 1, ok)
+}
+
+func TestDescribeDevice(t *testing.T) {
+	tests := []struct {
+		ua   string
+		want string
+	}{
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 14_0) AppleWebKit/537.36 Chrome/120.0 Safari/537.36", "Chrome on macOS"},
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 Chrome/120.0 Safari/537.36 Edg/120.0", "Edge on Windows"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 Version/17.0 Mobile Safari/604.1", "Safari on iPhone"},
+		{"Mozilla/5.0 (Android 14; Mobile; rv:121.0) Gecko/121.0 Firefox/121.0", "Firefox on Android"},
+		{"curl/8.4.0", "Unknown device"},
+		{"", "Unknown device"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, describeDevice(tt.ua), tt.ua)
+	}
+}
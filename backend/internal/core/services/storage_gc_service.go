@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+// uploadKeyPattern finds upload keys inside URLs and free text such as lesson bodies.
+var uploadKeyPattern = regexp.MustCompile(`creators/[0-9a-f]{24}/[^\s"'<>()?#]+`)
+
+// StorageGCService finds objects under creators/{id}/ that no product, course, testimonial,
+// profile or blog post references and deletes them once they are older than the grace period.
+// Only upload prefixes are swept; certificates and stamped copies have their own lifecycle.
+type StorageGCService struct {
+	storage         domain.FileStorage
+	productRepo     domain.ProductRepository
+	courseRepo      domain.CourseRepository
+	testimonialRepo domain.TestimonialRepository
+	userRepo        domain.UserRepository
+	blogRepo        domain.BlogRepository
+	orderRepo       domain.OrderRepository
+	uploadRepo      domain.UploadRepository
+	gracePeriod     time.Duration
+}
+
+// NewStorageGCService creates a new StorageGCService.
+func NewStorageGCService(
+	storage domain.FileStorage,
+	productRepo domain.ProductRepository,
+	courseRepo domain.CourseRepository,
+	testimonialRepo domain.TestimonialRepository,
+	userRepo domain.UserRepository,
+	blogRepo domain.BlogRepository,
+	orderRepo domain.OrderRepository,
+	uploadRepo domain.UploadRepository,
+	gracePeriod time.Duration,
+) *StorageGCService {
+	return &StorageGCService{
+		storage:         storage,
+		productRepo:     productRepo,
+		courseRepo:      courseRepo,
+		testimonialRepo: testimonialRepo,
+		userRepo:        userRepo,
+		blogRepo:        blogRepo,
+		orderRepo:       orderRepo,
+		uploadRepo:      uploadRepo,
+		gracePeriod:     gracePeriod,
+	}
+}
+
+// Sweep scans storage for orphaned uploads. With dryRun set nothing is deleted and the report
+// lists what would be. A creator whose references cannot be loaded is skipped entirely rather
+// than risk deleting live files.
+func (s *StorageGCService) Sweep(ctx context.Context, dryRun bool) (*domain.StorageSweepReport, error) {
+	report := &domain.StorageSweepReport{
+		DryRun:      dryRun,
+		GracePeriod: s.gracePeriod.String(),
+		Orphans:     []domain.OrphanedFile{},
+		StartedAt:   time.Now(),
+	}
+
+	objects, err := s.storage.List(ctx, "creators/")
+	if err != nil {
+		return nil, err
+	}
+	byCreator := map[string][]domain.FileInfo{}
+	for _, obj := range objects {
+		creatorID, ok := sweepableCreator(obj.Key)
+		if !ok {
+			continue
+		}
+		byCreator[creatorID] = append(byCreator[creatorID], obj)
+	}
+
+	// Blog posts are written by admins and may use any uploaded image
+	blogRefs := map[string]bool{}
+	blogs, _, err := s.blogRepo.FindMany(ctx, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range blogs {
+		addReferences(blogRefs, b.CoverImage, b.Content)
+	}
+
+	creatorIDs := make([]string, 0, len(byCreator))
+	for id := range byCreator {
+		creatorIDs = append(creatorIDs, id)
+	}
+	sort.Strings(creatorIDs)
+
+	cutoff := report.StartedAt.Add(-s.gracePeriod)
+	for _, creatorID := range creatorIDs {
+		refs, refPrefixes, err := s.creatorReferences(ctx, creatorID)
+		if err != nil {
+			logger.Error("storage sweep: failed to load references", "creator_id", creatorID, "error", err)
+			report.FailedCreators = append(report.FailedCreators, creatorID)
+			continue
+		}
+
+		for _, obj := range byCreator[creatorID] {
+			report.Scanned++
+			if refs[obj.Key] || blogRefs[obj.Key] || hasAnyPrefix(obj.Key, refPrefixes) {
+				report.Referenced++
+				continue
+			}
+			if obj.LastModified.After(cutoff) {
+				report.InGracePeriod++
+				continue
+			}
+
+			orphan := domain.OrphanedFile{Key: obj.Key, CreatorID: creatorID, Size: obj.Size, LastModified: obj.LastModified}
+			if !dryRun {
+				if err := s.storage.Delete(ctx, obj.Key); err != nil {
+					orphan.Error = err.Error()
+				} else {
+					orphan.Deleted = true
+					report.Deleted++
+				}
+			}
+			report.Orphans = append(report.Orphans, orphan)
+			report.OrphanBytes += obj.Size
+		}
+	}
+
+	report.FinishedAt = time.Now()
+	logger.Info("storage sweep finished",
+		"dry_run", dryRun,
+		"scanned", report.Scanned,
+		"orphans", len(report.Orphans),
+		"deleted", report.Deleted,
+		"orphan_bytes", report.OrphanBytes,
+	)
+	return report, nil
+}
+
+// creatorReferences returns the set of keys a creator's data points at, including the image
+// variants generated from referenced uploads, and the prefixes holding HLS output of referenced
+// videos. Files of deleted products stay referenced while buyers who paid for them can still
+// download.
+func (s *StorageGCService) creatorReferences(ctx context.Context, creatorID string) (map[string]bool, []string, error) {
+	oid, err := primitive.ObjectIDFromHex(creatorID)
+	if err != nil {
+		return nil, nil, err
+	}
+	refs := map[string]bool{}
+	var prefixes []string
+
+	user, err := s.userRepo.FindByID(ctx, creatorID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user != nil {
+		addReferences(refs, user.AvatarURL, user.CoverPhotoURL, user.Bio)
+	}
+
+	products, err := s.productRepo.FindAllByCreatorID(ctx, oid)
+	if err != nil {
+		return nil, nil, err
+	}
+	deleted, err := s.productRepo.FindDeletedByCreatorID(ctx, oid)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, p := range deleted {
+		orders, err := s.orderRepo.FindPaidByProductID(ctx, p.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(orders) > 0 {
+			products = append(products, p)
+		}
+	}
+
+	for _, p := range products {
+		addReferences(refs, p.CoverImageURL, p.FileURL, p.Description)
+		for _, f := range p.Files {
+			addReferences(refs, f.FileKey)
+			for _, v := range f.History {
+				addReferences(refs, v.FileKey)
+			}
+		}
+
+		testimonials, err := s.testimonialRepo.FindByProductID(ctx, p.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, t := range testimonials {
+			addReferences(refs, t.AvatarURL)
+		}
+
+		if p.ProductType != domain.ProductTypeCourse {
+			continue
+		}
+		course, err := s.courseRepo.FindByProductID(ctx, p.ID)
+		if err != nil {
+			// A course product whose curriculum was never saved has nothing to reference
+			if err.Error() == ErrCourseNotFound.Error() {
+				continue
+			}
+			return nil, nil, err
+		}
+		for _, m := range course.Modules {
+			for _, l := range m.Lessons {
+				addReferences(refs, l.Content)
+			}
+		}
+	}
+
+	if len(refs) > 0 && s.uploadRepo != nil {
+		keys := make([]string, 0, len(refs))
+		for k := range refs {
+			keys = append(keys, k)
+		}
+		uploads, err := s.uploadRepo.FindByKeys(ctx, keys)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, u := range uploads {
+			for _, v := range u.Variants {
+				refs[v.FileKey] = true
+			}
+			if u.HLSPrefix != "" {
+				prefixes = append(prefixes, u.HLSPrefix)
+			}
+		}
+	}
+	return refs, prefixes, nil
+}
+
+// sweepableCreator returns the creator ID for keys under an upload prefix.
+func sweepableCreator(key string) (string, bool) {
+	parts := strings.SplitN(key, "/", 3)
+	if len(parts) < 3 || !primitive.IsValidObjectID(parts[1]) {
+		return "", false
+	}
+	for _, policy := range uploadPolicies {
+		if strings.HasPrefix(parts[2], policy.prefix+"/") {
+			return parts[1], true
+		}
+	}
+	return "", false
+}
+
+func hasAnyPrefix(key string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func addReferences(refs map[string]bool, values ...string) {
+	for _, v := range values {
+		for _, key := range uploadKeyPattern.FindAllString(v, -1) {
+			refs[key] = true
+		}
+	}
+}
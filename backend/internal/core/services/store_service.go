@@ -19,16 +19,18 @@ type StoreResponse struct {
 
 // PublicProfile represents a sanitized user profile.
 type PublicProfile struct {
-	ID            string              `json:"id"`
-	DisplayName   string              `json:"displayName"`
-	Username      string              `json:"username"`
-	Bio           string              `json:"bio"`
-	Theme         string              `json:"theme"`
-	BrandColor    string              `json:"brandColor,omitempty"`
-	FontFamily    string              `json:"fontFamily,omitempty"`
-	CoverPhotoURL string              `json:"coverPhotoUrl,omitempty"`
-	AvatarURL     string              `json:"avatarUrl"`
-	SocialLinks   []domain.SocialLink `json:"socialLinks"`
+	ID            string                  `json:"id"`
+	DisplayName   string                  `json:"displayName"`
+	Username      string                  `json:"username"`
+	Bio           string                  `json:"bio"`
+	Theme         string                  `json:"theme"`
+	BrandColor    string                  `json:"brandColor,omitempty"`
+	FontFamily    string                  `json:"fontFamily,omitempty"`
+	CoverPhotoURL string                  `json:"coverPhotoUrl,omitempty"`
+	CoverPhoto    *domain.ResponsiveImage `json:"coverPhoto,omitempty"`
+	AvatarURL     string                  `json:"avatarUrl"`
+	Avatar        *domain.ResponsiveImage `json:"avatar,omitempty"`
+	SocialLinks   []domain.SocialLink     `json:"socialLinks"`
 }
 
 // StoreService handles business logic for the public storefront.
@@ -36,6 +38,7 @@ type StoreService struct {
 	userRepo    domain.UserRepository
 	productRepo domain.ProductRepository
 	cache       domain.Cache
+	imageSvc    *ImageService
 }
 
 // NewStoreService creates a new StoreService.
@@ -47,6 +50,11 @@ func NewStoreService(userRepo domain.UserRepository, productRepo domain.ProductR
 	}
 }
 
+// SetImageService adds responsive image URLs to storefront responses.
+func (s *StoreService) SetImageService(imageSvc *ImageService) {
+	s.imageSvc = imageSvc
+}
+
 // GetStoreByUsername fetches the store data for a given username.
 func (s *StoreService) GetStoreByUsername(ctx context.Context, username string) (*StoreResponse, error) {
 	// 1. Find User by Username
@@ -117,6 +125,10 @@ func (s *StoreService) GetStoreByUsername(ctx context.Context, username string)
 		SocialLinks:   user.SocialLinks,
 	}
 
+	if err := s.attachResponsiveImages(ctx, profile, products); err != nil {
+		return nil, err
+	}
+
 	resp := &StoreResponse{
 		Creator:  profile,
 		Products: products,
@@ -130,3 +142,25 @@ func (s *StoreService) GetStoreByUsername(ctx context.Context, username string)
 
 	return resp, nil
 }
+
+// attachResponsiveImages fills in resized variants for the profile photos and product covers.
+func (s *StoreService) attachResponsiveImages(ctx context.Context, profile *PublicProfile, products []*domain.Product) error {
+	if s.imageSvc == nil {
+		return nil
+	}
+	urls := []string{profile.AvatarURL, profile.CoverPhotoURL}
+	for _, p := range products {
+		urls = append(urls, p.CoverImageURL)
+	}
+	images, err := s.imageSvc.ResponsiveImages(ctx, urls)
+	if err != nil {
+		return err
+	}
+
+	profile.Avatar = images[profile.AvatarURL]
+	profile.CoverPhoto = images[profile.CoverPhotoURL]
+	for _, p := range products {
+		p.CoverImage = images[p.CoverImageURL]
+	}
+	return nil
+}
@@ -0,0 +1,378 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+var (
+	ErrNotTeamMember          = errors.New("you are not a member of this creator's team")
+	ErrTeamPermissionDenied   = errors.New("your team role does not allow this")
+	ErrTeamMemberNotFound     = errors.New("team member not found")
+	ErrTeamInviteInvalid      = errors.New("team invite is invalid or has expired")
+	ErrTeamInviteWrongAccount = errors.New("this invite was sent to a different email address")
+	ErrTeamMemberExists       = errors.New("this person is already on your team")
+	ErrInvalidTeamRoles       = errors.New("choose at least one valid team role")
+	ErrCannotInviteSelf       = errors.New("you can't invite yourself to your own team")
+)
+
+const (
+	teamInviteTTL  = 7 * 24 * time.Hour
+	maxTeamMembers = 10
+)
+
+// TeamService manages collaborators on creator accounts and decides what they may do.
+type TeamService struct {
+	memberRepo   domain.TeamMemberRepository
+	activityRepo domain.TeamActivityRepository
+	userRepo     domain.UserRepository
+	emailService domain.EmailService
+	frontendURL  string
+}
+
+// NewTeamService creates a new TeamService.
+func NewTeamService(memberRepo domain.TeamMemberRepository, activityRepo domain.TeamActivityRepository, userRepo domain.UserRepository, frontendURL string) *TeamService {
+	return &TeamService{
+		memberRepo:   memberRepo,
+		activityRepo: activityRepo,
+		userRepo:     userRepo,
+		frontendURL:  strings.TrimRight(frontendURL, "/"),
+	}
+}
+
+// SetEmailService enables invite emails. Without it, invite links are only logged.
+func (s *TeamService) SetEmailService(emailService domain.EmailService) {
+	s.emailService = emailService
+}
+
+// Authorize checks that userID may act on creatorID's account in area. write is true for
+// requests that change data. The returned member is the acting user's membership.
+func (s *TeamService) Authorize(ctx context.Context, creatorID, userID string, areas []string, write bool) (*domain.TeamMember, error) {
+	member, err := s.membership(ctx, creatorID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	needed := domain.TeamAccessRead
+	if write {
+		needed = domain.TeamAccessWrite
+	}
+	for _, area := range areas {
+		if member.Access(area) >= needed {
+			return member, nil
+		}
+	}
+	return nil, ErrTeamPermissionDenied
+}
+
+// Invite adds a collaborator to the creator's team and emails them a link to accept.
+func (s *TeamService) Invite(ctx context.Context, creatorID, email string, roles []string) (*domain.TeamMember, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" || !strings.Contains(email, "@") {
+		return nil, fmt.Errorf("a valid email is required")
+	}
+	roles, err := normaliseTeamRoles(roles)
+	if err != nil {
+		return nil, err
+	}
+
+	creator, err := s.userRepo.FindByID(ctx, creatorID)
+	if err != nil || creator == nil {
+		return nil, fmt.Errorf("creator not found")
+	}
+	if strings.EqualFold(creator.Email, email) {
+		return nil, ErrCannotInviteSelf
+	}
+
+	existing, err := s.memberRepo.FindByEmail(ctx, creator.ID, email)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.Status == domain.TeamMemberActive {
+		return nil, ErrTeamMemberExists
+	}
+
+	members, err := s.memberRepo.FindByCreator(ctx, creator.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil && len(members) >= maxTeamMembers {
+		return nil, fmt.Errorf("teams are limited to %d members", maxTeamMembers)
+	}
+
+	token := generateMagicLinkToken()
+	expiresAt := time.Now().Add(teamInviteTTL)
+
+	// Re-inviting a pending member refreshes their link and roles
+	member := existing
+	if member == nil {
+		member = &domain.TeamMember{CreatorID: creator.ID, Email: email}
+	}
+	member.Roles = roles
+	member.Status = domain.TeamMemberInvited
+	member.InvitedBy = creator.ID
+	member.InviteTokenHash = hashToken(token)
+	member.InviteExpiresAt = &expiresAt
+
+	if existing == nil {
+		err = s.memberRepo.Create(ctx, member)
+	} else {
+		err = s.memberRepo.Update(ctx, member)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.sendInvite(ctx, creator, member, token)
+	s.recordUser(ctx, creator.ID, creator, "team.invite", email)
+	return member, nil
+}
+
+func (s *TeamService) sendInvite(ctx context.Context, creator *domain.User, member *domain.TeamMember, token string) {
+	acceptURL := fmt.Sprintf("%s/team/accept?token=%s", s.frontendURL, url.QueryEscape(token))
+	if s.emailService == nil {
+		logger.Info("team invite generated (email disabled)", "email", member.Email, "url", acceptURL)
+		return
+	}
+
+	body := fmt.Sprintf(
+		"<div style='font-family:sans-serif;max-width:480px;margin:0 auto;padding:32px;'>"+
+			"<h2 style='color:#6C5CE7;'>You've been invited to a team</h2>"+
+			"<p><strong>%s</strong> invited you to help run their Mio Store as: %s.</p>"+
+			"<p style='text-align:center;margin:24px 0;'><a href='%s' style='background:#6C5CE7;color:#fff;padding:12px 24px;border-radius:8px;text-decoration:none;font-weight:bold;'>Accept invite</a></p>"+
+			"<p style='color:#888;font-size:14px;'>Sign in or create an account with this email address to accept. The invite expires in 7 days.</p>"+
+			"</div>",
+		html.EscapeString(creator.DisplayName), html.EscapeString(strings.Join(member.Roles, ", ")), html.EscapeString(acceptURL),
+	)
+	if err := s.emailService.Send(ctx, member.Email, creator.DisplayName+" invited you to their Mio Store team", body); err != nil {
+		logger.Error("failed to send team invite", "error", err, "email", member.Email)
+	}
+}
+
+// AcceptInvite joins the signed-in user to the team that invited them. The invite must have
+// been sent to the user's own email address.
+func (s *TeamService) AcceptInvite(ctx context.Context, userID, token string) (*domain.TeamMember, error) {
+	member, err := s.memberRepo.FindByInviteToken(ctx, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if member == nil || member.InviteExpiresAt == nil || time.Now().After(*member.InviteExpiresAt) {
+		return nil, ErrTeamInviteInvalid
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil || user == nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	if !strings.EqualFold(user.Email, member.Email) {
+		return nil, ErrTeamInviteWrongAccount
+	}
+	if user.ID == member.CreatorID {
+		return nil, ErrCannotInviteSelf
+	}
+
+	now := time.Now()
+	member.UserID = user.ID
+	member.Status = domain.TeamMemberActive
+	member.AcceptedAt = &now
+	member.InviteTokenHash = ""
+	member.InviteExpiresAt = nil
+	if err := s.memberRepo.Update(ctx, member); err != nil {
+		return nil, err
+	}
+
+	s.recordUser(ctx, member.CreatorID, user, "team.join", "")
+	return member, nil
+}
+
+// ListMembers returns the creator's team, including pending invites.
+func (s *TeamService) ListMembers(ctx context.Context, creatorID string) ([]*domain.TeamMember, error) {
+	oid, err := primitive.ObjectIDFromHex(creatorID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid creator id")
+	}
+	return s.memberRepo.FindByCreator(ctx, oid)
+}
+
+// UpdateRoles changes what a team member may do.
+func (s *TeamService) UpdateRoles(ctx context.Context, creatorID, memberID string, roles []string) (*domain.TeamMember, error) {
+	roles, err := normaliseTeamRoles(roles)
+	if err != nil {
+		return nil, err
+	}
+	member, err := s.ownedMember(ctx, creatorID, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	member.Roles = roles
+	if err := s.memberRepo.Update(ctx, member); err != nil {
+		return nil, err
+	}
+
+	s.recordByCreator(ctx, member.CreatorID, "team.update_roles", member.Email+": "+strings.Join(roles, ","))
+	return member, nil
+}
+
+// RemoveMember takes a collaborator off the team, or cancels their invite.
+func (s *TeamService) RemoveMember(ctx context.Context, creatorID, memberID string) error {
+	member, err := s.ownedMember(ctx, creatorID, memberID)
+	if err != nil {
+		return err
+	}
+	if err := s.memberRepo.Delete(ctx, member.ID); err != nil {
+		return err
+	}
+
+	s.recordByCreator(ctx, member.CreatorID, "team.remove", member.Email)
+	return nil
+}
+
+// ListAccounts returns the creator accounts the user works on as a team member.
+func (s *TeamService) ListAccounts(ctx context.Context, userID string) ([]domain.TeamAccount, error) {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user id")
+	}
+	members, err := s.memberRepo.FindActiveByUser(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]domain.TeamAccount, 0, len(members))
+	for _, m := range members {
+		creator, err := s.userRepo.FindByID(ctx, m.CreatorID.Hex())
+		if err != nil || creator == nil || creator.Status == domain.UserStatusBanned {
+			continue
+		}
+		accounts = append(accounts, domain.TeamAccount{
+			CreatorID:   creator.ID,
+			DisplayName: creator.DisplayName,
+			Username:    creator.Username,
+			AvatarURL:   creator.AvatarURL,
+			Roles:       m.Roles,
+		})
+	}
+	return accounts, nil
+}
+
+// Leave removes the user from a creator's team.
+func (s *TeamService) Leave(ctx context.Context, userID, creatorID string) error {
+	member, err := s.membership(ctx, creatorID, userID)
+	if errors.Is(err, ErrNotTeamMember) {
+		return ErrTeamMemberNotFound
+	} else if err != nil {
+		return err
+	}
+	if err := s.memberRepo.Delete(ctx, member.ID); err != nil {
+		return err
+	}
+
+	if user, err := s.userRepo.FindByID(ctx, userID); err == nil && user != nil {
+		s.recordUser(ctx, member.CreatorID, user, "team.leave", "")
+	}
+	return nil
+}
+
+// membership returns the user's active membership on the creator's team.
+func (s *TeamService) membership(ctx context.Context, creatorID, userID string) (*domain.TeamMember, error) {
+	creatorOID, err := primitive.ObjectIDFromHex(creatorID)
+	if err != nil {
+		return nil, ErrNotTeamMember
+	}
+	userOID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, ErrNotTeamMember
+	}
+
+	member, err := s.memberRepo.FindActive(ctx, creatorOID, userOID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrNotTeamMember
+	}
+	return member, nil
+}
+
+// ListActivity returns the audit trail of what team members did on the creator's account.
+func (s *TeamService) ListActivity(ctx context.Context, creatorID string, limit int64) ([]*domain.TeamActivity, error) {
+	oid, err := primitive.ObjectIDFromHex(creatorID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid creator id")
+	}
+	return s.activityRepo.FindByCreator(ctx, oid, limit)
+}
+
+// Record adds an entry to the creator's team audit trail. Failures are logged, not returned,
+// so auditing never blocks the action itself.
+func (s *TeamService) Record(ctx context.Context, activity *domain.TeamActivity) {
+	if err := s.activityRepo.Create(ctx, activity); err != nil {
+		logger.Error("failed to record team activity", "error", err, "creator_id", activity.CreatorID.Hex(), "action", activity.Action)
+	}
+}
+
+// recordUser records an action taken by user on creatorID's team.
+func (s *TeamService) recordUser(ctx context.Context, creatorID primitive.ObjectID, user *domain.User, action, target string) {
+	s.Record(ctx, &domain.TeamActivity{
+		CreatorID:  creatorID,
+		ActorID:    user.ID,
+		ActorEmail: user.Email,
+		Action:     action,
+		Target:     target,
+	})
+}
+
+func (s *TeamService) recordByCreator(ctx context.Context, creatorID primitive.ObjectID, action, target string) {
+	creator, err := s.userRepo.FindByID(ctx, creatorID.Hex())
+	if err != nil || creator == nil {
+		return
+	}
+	s.recordUser(ctx, creatorID, creator, action, target)
+}
+
+// ownedMember loads a team member and checks it belongs to creatorID's team.
+func (s *TeamService) ownedMember(ctx context.Context, creatorID, memberID string) (*domain.TeamMember, error) {
+	oid, err := primitive.ObjectIDFromHex(memberID)
+	if err != nil {
+		return nil, ErrTeamMemberNotFound
+	}
+	member, err := s.memberRepo.FindByID(ctx, oid)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil || member.CreatorID.Hex() != creatorID {
+		return nil, ErrTeamMemberNotFound
+	}
+	return member, nil
+}
+
+// normaliseTeamRoles validates roles and removes duplicates.
+func normaliseTeamRoles(roles []string) ([]string, error) {
+	seen := make(map[string]bool, len(roles))
+	out := make([]string, 0, len(roles))
+	for _, role := range roles {
+		role = strings.TrimSpace(role)
+		if !domain.IsValidTeamRole(role) {
+			return nil, ErrInvalidTeamRoles
+		}
+		if !seen[role] {
+			seen[role] = true
+			out = append(out, role)
+		}
+	}
+	if len(out) == 0 {
+		return nil, ErrInvalidTeamRoles
+	}
+	return out, nil
+}
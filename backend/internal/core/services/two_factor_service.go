@@ -0,0 +1,399 @@
+package services
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+)
+
+var (
+	ErrInvalidTwoFactorCode   = errors.New("invalid authentication code")
+	ErrTwoFactorNotEnabled    = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorAlreadyOn     = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorSetupExpired  = errors.New("two-factor setup expired, please start again")
+	ErrTwoFactorMandatory     = errors.New("two-factor authentication is required for admin accounts")
+	ErrInvalidTwoFactorLogin  = errors.New("login verification expired, please sign in again")
+	ErrTooManyTwoFactorErrors = errors.New("too many incorrect codes, please sign in again")
+)
+
+const (
+	totpPeriod         = 30 // seconds
+	totpDigits         = 6
+	totpSkew           = 1 // Accept codes one step either side of now for clock drift
+	recoveryCodeCount  = 10
+	twoFactorSetupTTL  = 15 * time.Minute
+	twoFactorLoginTTL  = 5 * time.Minute
+	twoFactorMaxErrors = 5 // Incorrect codes allowed per login challenge
+	twoFactorIssuer    = "Mio Store"
+)
+
+// StepUpWindow is how long a step-up re-authentication unlocks sensitive actions.
+const StepUpWindow = 10 * time.Minute
+
+// TwoFactorSetup is returned when enrolment starts. The frontend renders OTPAuthURL as a QR
+// code; Secret is shown for manual entry.
+type TwoFactorSetup struct {
+	Secret     string `json:"secret"`
+	OTPAuthURL string `json:"otpauthUrl"`
+}
+
+// TwoFactorService implements TOTP (RFC 6238) two-factor authentication: enrolment, code and
+// recovery code verification, login challenges and step-up re-authentication.
+type TwoFactorService struct {
+	userRepo domain.UserRepository
+	redis    *redis.Client
+	key      []byte // AES-256 key for stored secrets
+}
+
+// NewTwoFactorService creates a new TwoFactorService. TOTP secrets are encrypted at rest with
+// a key derived from secret.
+func NewTwoFactorService(userRepo domain.UserRepository, redisClient *redis.Client, secret string) *TwoFactorService {
+	key := sha256.Sum256([]byte("two-factor:" + secret))
+	return &TwoFactorService{
+		userRepo: userRepo,
+		redis:    redisClient,
+		key:      key[:],
+	}
+}
+
+// BeginSetup generates a new TOTP secret for user. It is held in Redis until confirmed with
+// a valid code through Enable.
+func (s *TwoFactorService) BeginSetup(ctx context.Context, user *domain.User) (*TwoFactorSetup, error) {
+	if user.HasTwoFactor() {
+		return nil, ErrTwoFactorAlreadyOn
+	}
+
+	raw := make([]byte, 20)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("generate secret: %w", err)
+	}
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(raw)
+
+	if err := s.redis.Set(ctx, twoFactorSetupKey(user.ID.Hex()), secret, twoFactorSetupTTL).Err(); err != nil {
+		return nil, fmt.Errorf("redis set: %w", err)
+	}
+
+	label := url.PathEscape(twoFactorIssuer + ":" + user.Email)
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", twoFactorIssuer)
+	q.Set("digits", fmt.Sprint(totpDigits))
+	q.Set("period", fmt.Sprint(totpPeriod))
+
+	return &TwoFactorSetup{
+		Secret:     secret,
+		OTPAuthURL: "otpauth://totp/" + label + "?" + q.Encode(),
+	}, nil
+}
+
+// Enable confirms enrolment with a code from the authenticator app and returns the recovery
+// codes. They are only shown this once.
+func (s *TwoFactorService) Enable(ctx context.Context, user *domain.User, code string) ([]string, error) {
+	if user.HasTwoFactor() {
+		return nil, ErrTwoFactorAlreadyOn
+	}
+
+	userID := user.ID.Hex()
+	secret, err := s.redis.Get(ctx, twoFactorSetupKey(userID)).Result()
+	if err == redis.Nil {
+		return nil, ErrTwoFactorSetupExpired
+	} else if err != nil {
+		return nil, fmt.Errorf("redis get: %w", err)
+	}
+
+	step, ok := matchTOTP(secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	encrypted, err := s.encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.userRepo.UpdateTwoFactor(ctx, userID, &domain.TwoFactor{
+		Enabled:       true,
+		Secret:        encrypted,
+		RecoveryCodes: hashes,
+		LastUsedStep:  step,
+		EnabledAt:     &now,
+	}); err != nil {
+		return nil, err
+	}
+	s.redis.Del(ctx, twoFactorSetupKey(userID))
+
+	return codes, nil
+}
+
+// Disable removes 2FA after checking a current code. Admins can't turn it off.
+func (s *TwoFactorService) Disable(ctx context.Context, user *domain.User, code string) error {
+	if user.Role == domain.RoleAdmin {
+		return ErrTwoFactorMandatory
+	}
+	if err := s.Verify(ctx, user, code); err != nil {
+		return err
+	}
+	return s.userRepo.UpdateTwoFactor(ctx, user.ID.Hex(), nil)
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes after checking a current code.
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, user *domain.User, code string) ([]string, error) {
+	if err := s.Verify(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	// Reload so the replay marker set by Verify is kept
+	fresh, err := s.userRepo.FindByID(ctx, user.ID.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("find user: %w", err)
+	}
+	if fresh == nil || !fresh.HasTwoFactor() {
+		return nil, ErrTwoFactorNotEnabled
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	tf := *fresh.TwoFactor
+	tf.RecoveryCodes = hashes
+	if err := s.userRepo.UpdateTwoFactor(ctx, user.ID.Hex(), &tf); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Verify checks a 6-digit TOTP code or a recovery code. Each TOTP code and each recovery code
+// can be used only once.
+func (s *TwoFactorService) Verify(ctx context.Context, user *domain.User, code string) error {
+	if !user.HasTwoFactor() {
+		return ErrTwoFactorNotEnabled
+	}
+	userID := user.ID.Hex()
+	code = strings.TrimSpace(code)
+
+	if len(code) == totpDigits && isDigits(code) {
+		secret, err := s.decrypt(user.TwoFactor.Secret)
+		if err != nil {
+			return fmt.Errorf("decrypt secret: %w", err)
+		}
+		step, ok := matchTOTP(secret, code, time.Now())
+		if !ok {
+			return ErrInvalidTwoFactorCode
+		}
+		fresh, err := s.userRepo.ConsumeTOTPStep(ctx, userID, step)
+		if err != nil {
+			return err
+		}
+		if !fresh {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	ok, err := s.userRepo.ConsumeRecoveryCode(ctx, userID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
+
+// CreateLoginChallenge records that userID passed the first factor and returns the token
+// the client completes the login with.
+func (s *TwoFactorService) CreateLoginChallenge(ctx context.Context, userID string) (string, error) {
+	token, err := randomToken(32, base64.RawURLEncoding.EncodeToString)
+	if err != nil {
+		return "", err
+	}
+	if err := s.redis.Set(ctx, twoFactorLoginKey(token), userID, twoFactorLoginTTL).Err(); err != nil {
+		return "", fmt.Errorf("redis set: %w", err)
+	}
+	return token, nil
+}
+
+// LoginChallengeUser returns the user a login challenge belongs to.
+func (s *TwoFactorService) LoginChallengeUser(ctx context.Context, token string) (string, error) {
+	userID, err := s.redis.Get(ctx, twoFactorLoginKey(token)).Result()
+	if err == redis.Nil {
+		return "", ErrInvalidTwoFactorLogin
+	} else if err != nil {
+		return "", fmt.Errorf("redis get: %w", err)
+	}
+	return userID, nil
+}
+
+// FailLoginChallenge counts an incorrect code against a challenge, discarding the challenge
+// once too many have been tried.
+func (s *TwoFactorService) FailLoginChallenge(ctx context.Context, token string) error {
+	key := twoFactorLoginKey(token) + ":errors"
+	n, err := s.redis.Incr(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("redis incr: %w", err)
+	}
+	s.redis.Expire(ctx, key, twoFactorLoginTTL)
+	if n >= twoFactorMaxErrors {
+		s.redis.Del(ctx, twoFactorLoginKey(token), key)
+		return ErrTooManyTwoFactorErrors
+	}
+	return nil
+}
+
+// CompleteLoginChallenge discards a challenge once the login has succeeded.
+func (s *TwoFactorService) CompleteLoginChallenge(ctx context.Context, token string) {
+	s.redis.Del(ctx, twoFactorLoginKey(token), twoFactorLoginKey(token)+":errors")
+}
+
+// MarkStepUp records a fresh re-authentication for key (a session or user).
+func (s *TwoFactorService) MarkStepUp(ctx context.Context, key string) error {
+	if err := s.redis.Set(ctx, stepUpKey(key), time.Now().Unix(), StepUpWindow).Err(); err != nil {
+		return fmt.Errorf("redis set: %w", err)
+	}
+	return nil
+}
+
+// HasStepUp reports whether key re-authenticated within the step-up window.
+func (s *TwoFactorService) HasStepUp(ctx context.Context, key string) (bool, error) {
+	n, err := s.redis.Exists(ctx, stepUpKey(key)).Result()
+	if err != nil {
+		return false, fmt.Errorf("redis exists: %w", err)
+	}
+	return n > 0, nil
+}
+
+// encrypt seals a TOTP secret with AES-GCM.
+func (s *TwoFactorService) encrypt(plain string) (string, error) {
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, []byte(plain), nil)), nil
+}
+
+func (s *TwoFactorService) decrypt(sealed string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil {
+		return "", err
+	}
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", errors.New("ciphertext too short")
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// matchTOTP checks code against the time steps around now and returns the matching step.
+func matchTOTP(secret, code string, now time.Time) (int64, bool) {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false
+	}
+	current := now.Unix() / totpPeriod
+	for i := int64(-totpSkew); i <= totpSkew; i++ {
+		step := current + i
+		if hmac.Equal([]byte(totpCode(key, step)), []byte(code)) {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// totpCode computes the HOTP value (RFC 4226) for a time step.
+func totpCode(key []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%06d", value%1_000_000)
+}
+
+// generateRecoveryCodes returns recovery codes formatted as xxxxx-xxxxx, with their hashes.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		raw, err := randomToken(5, hex.EncodeToString)
+		if err != nil {
+			return nil, nil, err
+		}
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode normalises a recovery code so case and the separator don't matter.
+func hashRecoveryCode(code string) string {
+	normalised := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalised))
+	return hex.EncodeToString(sum[:])
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func twoFactorSetupKey(userID string) string {
+	return fmt.Sprintf("auth:2fa_setup:%s", userID)
+}
+
+func twoFactorLoginKey(token string) string {
+	return fmt.Sprintf("auth:2fa_login:%s", token)
+}
+
+func stepUpKey(key string) string {
+	return fmt.Sprintf("auth:step_up:%s", key)
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+)
+
+// rfc6238Key is the SHA-1 seed from RFC 6238 appendix B.
+var rfc6238Key = []byte("12345678901234567890")
+
+func TestTOTPCode_RFC6238Vectors(t *testing.T) {
+	// The RFC lists 8-digit codes; ours are the last 6 digits of the same values.
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, totpCode(rfc6238Key, tt.unix/totpPeriod), "time %d", tt.unix)
+	}
+}
+
+func TestMatchTOTP(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(rfc6238Key)
+	now := time.Unix(1111111111, 0)
+	step := now.Unix() / totpPeriod
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		wantStep int64
+		wantOK   bool
+	}{
+		{"current step", secret, totpCode(rfc6238Key, step), step, true},
+		{"previous step within skew", secret, totpCode(rfc6238Key, step-1), step - 1, true},
+		{"next step within skew", secret, totpCode(rfc6238Key, step+1), step + 1, true},
+		{"two steps old", secret, totpCode(rfc6238Key, step-2), 0, false},
+		{"wrong code", secret, "000000", 0, false},
+		{"lowercase secret", "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", totpCode(rfc6238Key, step), step, true},
+		{"invalid secret", "not base32!", totpCode(rfc6238Key, step), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := matchTOTP(tt.secret, tt.code, now)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantStep, got)
+		})
+	}
+}
+
+// twoFactorUserRepo keeps the replay marker and recovery codes in memory.
+type twoFactorUserRepo struct {
+	domain.UserRepository
+	lastStep      int64
+	recoveryCodes map[string]bool
+}
+
+func (r *twoFactorUserRepo) ConsumeTOTPStep(ctx context.Context, id string, step int64) (bool, error) {
+	if step <= r.lastStep {
+		return false, nil
+	}
+	r.lastStep = step
+	return true, nil
+}
+
+func (r *twoFactorUserRepo) ConsumeRecoveryCode(ctx context.Context, id string, codeHash string) (bool, error) {
+	if !r.recoveryCodes[codeHash] {
+		return false, nil
+	}
+	delete(r.recoveryCodes, codeHash)
+	return true, nil
+}
+
+func TestTwoFactorVerify_RejectsReplayedSteps(t *testing.T) {
+	repo := &twoFactorUserRepo{}
+	svc := NewTwoFactorService(repo, nil, "test-secret")
+
+	key := []byte("0123456789abcdefghij")
+	sealed, err := svc.encrypt(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(key))
+	require.NoError(t, err)
+	user := &domain.User{ID: primitive.NewObjectID(), TwoFactor: &domain.TwoFactor{Enabled: true, Secret: sealed}}
+
+	step := time.Now().Unix() / totpPeriod
+	tests := []struct {
+		name    string
+		code    string
+		wantErr error
+	}{
+		{"fresh code", totpCode(key, step), nil},
+		{"same code again", totpCode(key, step), ErrInvalidTwoFactorCode},
+		{"earlier step after a later one", totpCode(key, step-1), ErrInvalidTwoFactorCode},
+		{"code with surrounding spaces", " " + totpCode(key, step+1) + " ", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, svc.Verify(context.Background(), user, tt.code), tt.wantErr)
+		})
+	}
+}
+
+func TestHashRecoveryCode_Normalises(t *testing.T) {
+	want := hashRecoveryCode("ab12c-3de45")
+	tests := []struct {
+		name  string
+		input string
+		same  bool
+	}{
+		{"as issued", "ab12c-3de45", true},
+		{"upper case", "AB12C-3DE45", true},
+		{"without separator", "ab12c3de45", true},
+		{"space instead of separator", "ab12c 3de45", true},
+		{"different code", "ab12c-3de46", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.same, hashRecoveryCode(tt.input) == want)
+		})
+	}
+}
+
+func TestTwoFactorVerify_RecoveryCodesWorkOnce(t *testing.T) {
+	codes, hashes, err := generateRecoveryCodes()
+	require.NoError(t, err)
+	require.Len(t, codes, recoveryCodeCount)
+
+	repo := &twoFactorUserRepo{recoveryCodes: map[string]bool{}}
+	for _, h := range hashes {
+		repo.recoveryCodes[h] = true
+	}
+	svc := NewTwoFactorService(repo, nil, "test-secret")
+	user := &domain.User{ID: primitive.NewObjectID(), TwoFactor: &domain.TwoFactor{Enabled: true, Secret: "sealed"}}
+
+	assert.NoError(t, svc.Verify(context.Background(), user, codes[0]))
+	assert.ErrorIs(t, svc.Verify(context.Background(), user, codes[0]), ErrInvalidTwoFactorCode)
+	assert.NoError(t, svc.Verify(context.Background(), user, " "+codes[1]+" "))
+}
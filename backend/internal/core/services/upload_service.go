@@ -1,20 +1,48 @@
 package services
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
 )
 
+var (
+	ErrUploadNotFound    = errors.New("upload not found")
+	ErrUploadNotReceived = errors.New("file has not been uploaded yet")
+	ErrUploadRejected    = errors.New("upload rejected")
+	ErrUploadUnverified  = errors.New("file upload has not been verified")
+	ErrScanUnavailable   = errors.New("virus scan is temporarily unavailable, please retry")
+)
+
+// uploadPolicy describes what may be uploaded for a purpose.
+type uploadPolicy struct {
+	prefix   string
+	exts     []string
+	maxBytes int64
+}
+
+var uploadPolicies = map[domain.FilePurpose]uploadPolicy{
+	domain.PurposeProductFile: {prefix: "products/files", exts: []string{".pdf", ".zip", ".mp4", ".mp3"}, maxBytes: 2 << 30},
+	domain.PurposeCoverImage:  {prefix: "products/covers", exts: []string{".jpg", ".jpeg", ".png", ".webp"}, maxBytes: 5 << 20},
+}
+
 // UploadService handles file upload logic.
 type UploadService struct {
-	storage domain.FileStorage
+	storage    domain.FileStorage
+	uploadRepo domain.UploadRepository
+	scanner    domain.MalwareScanner
+	imageSvc   *ImageService
 }
 
 // NewUploadService creates a new UploadService.
@@ -24,34 +52,33 @@ func NewUploadService(storage domain.FileStorage) *UploadService {
 	}
 }
 
+// SetVerification enables upload tracking and post-upload verification.
+// Without it, uploads are not recorded and every key is accepted.
+func (s *UploadService) SetVerification(repo domain.UploadRepository, scanner domain.MalwareScanner) {
+	s.uploadRepo = repo
+	s.scanner = scanner
+}
+
+// SetImageService processes verified cover images and profile photos into resized variants.
+func (s *UploadService) SetImageService(imageSvc *ImageService) {
+	s.imageSvc = imageSvc
+}
+
 // GeneratePresignedURL validates the request and generates a pre-signed URL.
 func (s *UploadService) GeneratePresignedURL(ctx context.Context, userID string, req *domain.UploadRequest) (*domain.UploadResponse, error) {
-	// 1. Validate File Purpose & Size Limit (handled by frontend mostly, but good to know limits)
-	// We can't enforce size limit purely via presigned URL without signing headers,
-	// but for V1 we rely on client behavior + potentially bucket policy (if we could set it dynamically).
-	// Here we validate extensions.
-
+	// Size and content are enforced after the PUT by CompleteUpload; here we validate extensions.
 	ext := strings.ToLower(filepath.Ext(req.FileName))
 	if ext == "" {
 		return nil, fmt.Errorf("file extension required")
 	}
 
-	var validExts []string
-	var prefix string
-
-	switch req.Purpose {
-	case domain.PurposeProductFile:
-		validExts = []string{".pdf", ".zip", ".mp4", ".mp3"}
-		prefix = "products/files"
-	case domain.PurposeCoverImage:
-		validExts = []string{".jpg", ".jpeg", ".png", ".webp"}
-		prefix = "products/covers"
-	default:
+	policy, ok := uploadPolicies[req.Purpose]
+	if !ok {
 		return nil, fmt.Errorf("invalid purpose")
 	}
 
 	isValidExt := false
-	for _, e := range validExts {
+	for _, e := range policy.exts {
 		if e == ext {
 			isValidExt = true
 			break
@@ -65,7 +92,7 @@ func (s *UploadService) GeneratePresignedURL(ctx context.Context, userID string,
 	// Key Format: creators/{creator_id}/{prefix}/{uuid}{ext}
 	// Example: creators/123/products/files/abc-123.pdf
 	fileUUID := uuid.New().String()
-	fileKey := fmt.Sprintf("creators/%s/%s/%s%s", userID, prefix, fileUUID, ext)
+	fileKey := fmt.Sprintf("creators/%s/%s/%s%s", userID, policy.prefix, fileUUID, ext)
 
 	// 3. Generate Pre-signed URL
 	// Expiry: 15 minutes
@@ -75,12 +102,136 @@ func (s *UploadService) GeneratePresignedURL(ctx context.Context, userID string,
 		return nil, err
 	}
 
+	// 4. Track the upload so it can be verified once the client finishes
+	if s.uploadRepo != nil {
+		creatorID, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user ID")
+		}
+		upload := &domain.Upload{
+			CreatorID: creatorID,
+			FileKey:   fileKey,
+			Purpose:   req.Purpose,
+			Status:    domain.UploadStatusPending,
+		}
+		if err := s.uploadRepo.Create(ctx, upload); err != nil {
+			return nil, err
+		}
+	}
+
 	return &domain.UploadResponse{
 		UploadURL: url,
 		FileKey:   fileKey,
 	}, nil
 }
 
+// CompleteUpload verifies an object after the client's presigned PUT: it must exist, fit the
+// purpose's size limit, have magic bytes matching its extension and pass the malware scan.
+// Rejected objects are deleted from storage.
+func (s *UploadService) CompleteUpload(ctx context.Context, userID string, fileKey string) (*domain.Upload, error) {
+	if s.uploadRepo == nil {
+		return nil, errors.New("upload verification is not configured")
+	}
+
+	upload, err := s.uploadRepo.FindByKey(ctx, fileKey)
+	if err != nil {
+		return nil, err
+	}
+	if upload == nil || upload.CreatorID.Hex() != userID {
+		return nil, ErrUploadNotFound
+	}
+	switch upload.Status {
+	case domain.UploadStatusVerified:
+		return upload, nil
+	case domain.UploadStatusRejected:
+		return nil, fmt.Errorf("%w: %s", ErrUploadRejected, upload.RejectReason)
+	}
+
+	policy := uploadPolicies[upload.Purpose]
+
+	info, err := s.storage.Head(ctx, fileKey)
+	if err != nil {
+		return nil, ErrUploadNotReceived
+	}
+	if info.Size == 0 {
+		return nil, s.reject(ctx, upload, "file is empty")
+	}
+	if info.Size > policy.maxBytes {
+		return nil, s.reject(ctx, upload, fmt.Sprintf("file exceeds the %d MB limit", policy.maxBytes>>20))
+	}
+
+	body, err := s.storage.Open(ctx, fileKey)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	reader := bufio.NewReaderSize(body, 512)
+	header, _ := reader.Peek(512)
+	detected := sniffExtension(header)
+	if detected == "" || detected != canonicalExtension(filepath.Ext(fileKey)) {
+		return nil, s.reject(ctx, upload, "file content does not match its extension")
+	}
+
+	if s.scanner != nil {
+		result, err := s.scanner.Scan(ctx, reader)
+		if err != nil {
+			logger.Error("malware scan failed", "file_key", fileKey, "error", err)
+			return nil, ErrScanUnavailable
+		}
+		if !result.Clean {
+			logger.Warn("malware detected in upload", "file_key", fileKey, "creator_id", userID, "signature", result.Signature)
+			return nil, s.reject(ctx, upload, "file failed the virus scan")
+		}
+	}
+
+	now := time.Now()
+	upload.Status = domain.UploadStatusVerified
+	upload.Size = info.Size
+	upload.ContentType = contentTypes[detected]
+	upload.VerifiedAt = &now
+	if err := s.uploadRepo.Update(ctx, upload); err != nil {
+		return nil, err
+	}
+	if upload.Purpose == domain.PurposeCoverImage && s.imageSvc != nil {
+		s.imageSvc.Schedule(upload.FileKey)
+	}
+	return upload, nil
+}
+
+// RequireVerified checks that value (a storage key, or a public URL containing one) refers to
+// a verified upload owned by the creator, and returns the bare key.
+func (s *UploadService) RequireVerified(ctx context.Context, creatorID primitive.ObjectID, value string) (string, error) {
+	key := uploadKey(value)
+	if key == "" {
+		return "", ErrUploadUnverified
+	}
+	if s.uploadRepo == nil {
+		return key, nil
+	}
+
+	upload, err := s.uploadRepo.FindByKey(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	if upload == nil || upload.CreatorID != creatorID || upload.Status != domain.UploadStatusVerified {
+		return "", ErrUploadUnverified
+	}
+	return key, nil
+}
+
+func (s *UploadService) reject(ctx context.Context, upload *domain.Upload, reason string) error {
+	upload.Status = domain.UploadStatusRejected
+	upload.RejectReason = reason
+	if err := s.uploadRepo.Update(ctx, upload); err != nil {
+		return err
+	}
+	if err := s.storage.Delete(ctx, upload.FileKey); err != nil {
+		logger.Error("failed to delete rejected upload", "file_key", upload.FileKey, "error", err)
+	}
+	return fmt.Errorf("%w: %s", ErrUploadRejected, reason)
+}
+
 // GenerateDownloadURL generates a pre-signed URL for downloading a file.
 func (s *UploadService) GenerateDownloadURL(ctx context.Context, key string) (string, error) {
 	// Standard validation or transformation if needed
@@ -89,3 +240,52 @@ func (s *UploadService) GenerateDownloadURL(ctx context.Context, key string) (st
 	expiry := 1 * time.Hour
 	return s.storage.GeneratePresignedDownloadURL(ctx, key, expiry)
 }
+
+// uploadKey extracts the storage key from a key or a public URL, or "" if value is not an upload.
+func uploadKey(value string) string {
+	i := strings.Index(value, "creators/")
+	if i < 0 {
+		return ""
+	}
+	key, _, _ := strings.Cut(value[i:], "?")
+	return key
+}
+
+var contentTypes = map[string]string{
+	".pdf":  "application/pdf",
+	".zip":  "application/zip",
+	".mp4":  "video/mp4",
+	".mp3":  "audio/mpeg",
+	".jpg":  "image/jpeg",
+	".png":  "image/png",
+	".webp": "image/webp",
+}
+
+func canonicalExtension(ext string) string {
+	ext = strings.ToLower(ext)
+	if ext == ".jpeg" {
+		return ".jpg"
+	}
+	return ext
+}
+
+// sniffExtension identifies the allowed upload types by their magic bytes.
+func sniffExtension(h []byte) string {
+	switch {
+	case bytes.HasPrefix(h, []byte("%PDF-")):
+		return ".pdf"
+	case bytes.HasPrefix(h, []byte("PK\x03\x04")), bytes.HasPrefix(h, []byte("PK\x05\x06")):
+		return ".zip"
+	case len(h) >= 12 && bytes.Equal(h[4:8], []byte("ftyp")):
+		return ".mp4"
+	case bytes.HasPrefix(h, []byte("ID3")), len(h) >= 2 && h[0] == 0xFF && h[1]&0xE0 == 0xE0:
+		return ".mp3"
+	case bytes.HasPrefix(h, []byte{0xFF, 0xD8, 0xFF}):
+		return ".jpg"
+	case bytes.HasPrefix(h, []byte("\x89PNG\r\n\x1a\n")):
+		return ".png"
+	case len(h) >= 12 && bytes.Equal(h[0:4], []byte("RIFF")) && bytes.Equal(h[8:12], []byte("WEBP")):
+		return ".webp"
+	}
+	return ""
+}
@@ -0,0 +1,417 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"github.com/devanshbhargava/stan-store/internal/core/domain"
+	"github.com/devanshbhargava/stan-store/pkg/logger"
+)
+
+var (
+	ErrInvalidStreamToken = errors.New("invalid or expired video link")
+	ErrStreamNotReady     = errors.New("video is not ready for streaming")
+)
+
+const (
+	// streamURLExpiry is how long a signed playlist link from the course page works.
+	streamURLExpiry = 2 * time.Hour
+	// fallbackURLExpiry applies to the presigned MP4 served while HLS is unavailable.
+	fallbackURLExpiry = time.Hour
+)
+
+// hlsRenditionSpecs are the quality levels lesson videos are transcoded to, smallest first.
+// Levels taller than the source are skipped.
+var hlsRenditionSpecs = []domain.HLSRenditionSpec{
+	{Name: "360p", Height: 360, VideoBitrate: 800_000, AudioBitrate: 96_000},
+	{Name: "720p", Height: 720, VideoBitrate: 2_800_000, AudioBitrate: 128_000},
+	{Name: "1080p", Height: 1080, VideoBitrate: 5_000_000, AudioBitrate: 128_000},
+}
+
+// VideoService transcodes uploaded lesson videos to HLS and serves them to purchasers through
+// signed playlist links. Segments are fetched straight from storage with presigned URLs.
+type VideoService struct {
+	storage    domain.FileStorage
+	uploadRepo domain.UploadRepository
+	courseSvc  *CourseService
+	transcoder domain.VideoTranscoder
+	worker     *asynq.Client
+	secret     []byte
+	apiBaseURL string
+}
+
+// NewVideoService creates a new VideoService. Stream links are signed with a key derived from
+// secret and point at apiBaseURL.
+func NewVideoService(storage domain.FileStorage, uploadRepo domain.UploadRepository, courseSvc *CourseService, secret string, apiBaseURL string) *VideoService {
+	return &VideoService{
+		storage:    storage,
+		uploadRepo: uploadRepo,
+		courseSvc:  courseSvc,
+		secret:     deriveKey(secret, "videos"),
+		apiBaseURL: strings.TrimRight(apiBaseURL, "/"),
+	}
+}
+
+// SetTranscoder enables HLS transcoding. Without it lesson videos are served as short-lived
+// MP4 links.
+func (s *VideoService) SetTranscoder(transcoder domain.VideoTranscoder) {
+	s.transcoder = transcoder
+}
+
+// SetWorkerClient lets videos be transcoded in the background worker.
+func (s *VideoService) SetWorkerClient(client *asynq.Client) {
+	s.worker = client
+}
+
+// Prepare queues transcoding when a video lesson points at one of the creator's verified MP4
+// uploads. External URLs and already processed uploads are left alone.
+func (s *VideoService) Prepare(ctx context.Context, creatorID primitive.ObjectID, content string) {
+	key := uploadKey(content)
+	if key == "" || !isVideoKey(key) || s.transcoder == nil {
+		return
+	}
+	upload, err := s.uploadRepo.FindByKey(ctx, key)
+	if err != nil {
+		logger.Error("failed to look up lesson video", "file_key", key, "error", err)
+		return
+	}
+	if upload == nil || upload.CreatorID != creatorID || upload.Status != domain.UploadStatusVerified || upload.ProcessedAt != nil {
+		return
+	}
+	s.Schedule(key)
+}
+
+// Schedule queues transcoding of a verified video upload.
+func (s *VideoService) Schedule(fileKey string) {
+	if s.worker != nil {
+		if err := EnqueueVideoTranscodeTask(s.worker, fileKey); err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+			logger.Error("failed to enqueue video transcoding", "file_key", fileKey, "error", err)
+		}
+		return
+	}
+	go func() {
+		if err := s.ProcessUpload(context.Background(), fileKey); err != nil {
+			logger.Error("video transcoding failed", "file_key", fileKey, "error", err)
+		}
+	}()
+}
+
+// ProcessUpload transcodes a verified MP4 upload into HLS renditions stored under
+// {key without extension}_hls/. It is a no-op for uploads that are not verified videos or have
+// already been processed. Videos ffmpeg cannot read are marked as failed rather than retried.
+func (s *VideoService) ProcessUpload(ctx context.Context, fileKey string) error {
+	if s.transcoder == nil {
+		return errors.New("video transcoding is not configured")
+	}
+	upload, err := s.uploadRepo.FindByKey(ctx, fileKey)
+	if err != nil {
+		return err
+	}
+	if upload == nil {
+		return ErrUploadNotFound
+	}
+	if !isVideoKey(fileKey) || upload.Status != domain.UploadStatusVerified || upload.ProcessedAt != nil {
+		return nil
+	}
+
+	dir, err := os.MkdirTemp("", "hls-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	source := filepath.Join(dir, "source.mp4")
+	if err := s.downloadTo(ctx, fileKey, source); err != nil {
+		return err
+	}
+
+	probe, err := s.transcoder.Probe(ctx, source)
+	if err != nil {
+		return s.finish(ctx, upload, err)
+	}
+	upload.Width, upload.Height = probe.Width, probe.Height
+	upload.DurationSeconds = probe.DurationSeconds
+
+	outDir := filepath.Join(dir, "hls")
+	if err := os.Mkdir(outDir, 0o755); err != nil {
+		return err
+	}
+	upload.Renditions = nil
+	for i, spec := range hlsRenditionSpecs {
+		if spec.Height > probe.Height && i > 0 {
+			break
+		}
+		if err := s.transcoder.TranscodeHLS(ctx, source, outDir, spec); err != nil {
+			return s.finish(ctx, upload, err)
+		}
+		width := probe.Width * spec.Height / probe.Height
+		upload.Renditions = append(upload.Renditions, domain.HLSRendition{
+			Name:      spec.Name,
+			Width:     width + width%2,
+			Height:    spec.Height,
+			Bandwidth: spec.VideoBitrate*107/100 + spec.AudioBitrate,
+		})
+	}
+
+	prefix := strings.TrimSuffix(fileKey, path.Ext(fileKey)) + "_hls/"
+	entries, err := os.ReadDir(outDir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(outDir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		contentType := "video/mp2t"
+		if strings.HasSuffix(entry.Name(), ".m3u8") {
+			contentType = "application/vnd.apple.mpegurl"
+		}
+		if err := s.storage.Upload(ctx, prefix+entry.Name(), contentType, data); err != nil {
+			return fmt.Errorf("failed to store %s: %w", entry.Name(), err)
+		}
+	}
+	upload.HLSPrefix = prefix
+
+	return s.finish(ctx, upload, nil)
+}
+
+func (s *VideoService) downloadTo(ctx context.Context, fileKey, dst string) error {
+	body, err := s.storage.Open(ctx, fileKey)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to download source video: %w", err)
+	}
+	return f.Close()
+}
+
+// finish records the transcoding outcome.
+func (s *VideoService) finish(ctx context.Context, upload *domain.Upload, procErr error) error {
+	now := time.Now()
+	upload.ProcessedAt = &now
+	upload.ProcessError = ""
+	if procErr != nil {
+		logger.Warn("video transcoding failed", "file_key", upload.FileKey, "error", procErr)
+		upload.ProcessError = procErr.Error()
+		upload.Renditions = nil
+		upload.HLSPrefix = ""
+	}
+	return s.uploadRepo.Update(ctx, upload)
+}
+
+// AttachStreams fills in the stream for each unlocked video lesson that uses an uploaded file.
+// With hideSource set, the lesson's content is cleared so buyers never see the original file key.
+func (s *VideoService) AttachStreams(ctx context.Context, course *domain.Course, viewerID primitive.ObjectID, hideSource bool) error {
+	var keys []string
+	for _, mod := range course.Modules {
+		for _, les := range mod.Lessons {
+			if key := uploadKey(les.Content); les.Type == domain.LessonTypeVideo && key != "" && isVideoKey(key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	uploads, err := s.uploadRepo.FindByKeys(ctx, keys)
+	if err != nil {
+		return err
+	}
+	byKey := make(map[string]*domain.Upload, len(uploads))
+	for _, u := range uploads {
+		byKey[u.FileKey] = u
+	}
+
+	expiresAt := time.Now().Add(streamURLExpiry)
+	for i := range course.Modules {
+		for j := range course.Modules[i].Lessons {
+			les := &course.Modules[i].Lessons[j]
+			key := uploadKey(les.Content)
+			if les.Type != domain.LessonTypeVideo || les.Locked || key == "" || !isVideoKey(key) {
+				continue
+			}
+
+			stream := &domain.LessonStream{Status: videoStatus(byKey[key], s.transcoder != nil), ExpiresAt: expiresAt}
+			if stream.Status == domain.VideoStatusReady {
+				token := s.signStreamToken(course.ProductID, les.ID, viewerID, expiresAt)
+				stream.URL = fmt.Sprintf("%s/api/v1/videos/%s/master.m3u8", s.apiBaseURL, token)
+			} else {
+				url, err := s.storage.GeneratePresignedDownloadURL(ctx, key, fallbackURLExpiry)
+				if err != nil {
+					return err
+				}
+				stream.FallbackURL = url
+				stream.ExpiresAt = time.Now().Add(fallbackURLExpiry)
+			}
+			les.Stream = stream
+			if hideSource {
+				les.Content = ""
+			}
+		}
+	}
+	return nil
+}
+
+// videoStatus reports the streaming state of a lesson video upload. Without a transcoder,
+// untranscoded videos can never become ready and are reported as failed.
+func videoStatus(upload *domain.Upload, canTranscode bool) string {
+	switch {
+	case upload != nil && len(upload.Renditions) > 0:
+		return domain.VideoStatusReady
+	case upload == nil || upload.ProcessError != "" || !canTranscode:
+		return domain.VideoStatusFailed
+	default:
+		return domain.VideoStatusProcessing
+	}
+}
+
+// MasterPlaylist returns the HLS master playlist for a signed stream link. Access is checked
+// again against the buyer's purchase and the lesson's drip schedule on every request.
+func (s *VideoService) MasterPlaylist(ctx context.Context, token string) (string, error) {
+	upload, _, err := s.authorize(ctx, token)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n#EXT-X-VERSION:3\n")
+	for _, r := range upload.Renditions {
+		fmt.Fprintf(&b, "#EXT-X-STREAM-INF:BANDWIDTH=%d,RESOLUTION=%dx%d,NAME=\"%s\"\n", r.Bandwidth, r.Width, r.Height, r.Name)
+		// Relative, so it resolves against the signed master URL
+		b.WriteString(r.Name + ".m3u8\n")
+	}
+	return b.String(), nil
+}
+
+// RenditionPlaylist returns one rendition's media playlist with every segment replaced by a
+// presigned storage URL that lasts for the length of the video plus some slack.
+func (s *VideoService) RenditionPlaylist(ctx context.Context, token string, name string) (string, error) {
+	upload, _, err := s.authorize(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	found := false
+	for _, r := range upload.Renditions {
+		if r.Name == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "", ErrStreamNotReady
+	}
+
+	playlist, err := s.storage.Download(ctx, upload.HLSPrefix+name+".m3u8")
+	if err != nil {
+		return "", err
+	}
+
+	expiry := time.Duration(upload.DurationSeconds)*time.Second + 30*time.Minute
+	if expiry < streamURLExpiry {
+		expiry = streamURLExpiry
+	}
+	var b strings.Builder
+	scanner := bufio.NewScanner(strings.NewReader(string(playlist)))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			url, err := s.storage.GeneratePresignedDownloadURL(ctx, upload.HLSPrefix+path.Base(line), expiry)
+			if err != nil {
+				return "", err
+			}
+			line = url
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String(), scanner.Err()
+}
+
+// authorize verifies a stream token and that its viewer may still open the lesson, and returns
+// the lesson's transcoded upload.
+func (s *VideoService) authorize(ctx context.Context, token string) (*domain.Upload, *domain.Lesson, error) {
+	productID, lessonID, viewerID, err := s.verifyStreamToken(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	lesson, err := s.courseSvc.AuthorizeLesson(ctx, productID, viewerID, lessonID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key := uploadKey(lesson.Content)
+	if lesson.Type != domain.LessonTypeVideo || key == "" {
+		return nil, nil, ErrStreamNotReady
+	}
+	upload, err := s.uploadRepo.FindByKey(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if upload == nil || len(upload.Renditions) == 0 || upload.HLSPrefix == "" {
+		return nil, nil, ErrStreamNotReady
+	}
+	return upload, lesson, nil
+}
+
+func (s *VideoService) signStreamToken(productID primitive.ObjectID, lessonID string, viewerID primitive.ObjectID, expiresAt time.Time) string {
+	payload := productID.Hex() + "." + lessonID + "." + viewerID.Hex() + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	return payload + "." + s.streamSignature(payload)
+}
+
+func (s *VideoService) streamSignature(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("video." + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *VideoService) verifyStreamToken(token string) (primitive.ObjectID, string, primitive.ObjectID, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 5 {
+		return primitive.NilObjectID, "", primitive.NilObjectID, ErrInvalidStreamToken
+	}
+	payload := strings.Join(parts[:4], ".")
+	if !hmac.Equal([]byte(s.streamSignature(payload)), []byte(parts[4])) {
+		return primitive.NilObjectID, "", primitive.NilObjectID, ErrInvalidStreamToken
+	}
+	expires, err := strconv.ParseInt(parts[3], 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return primitive.NilObjectID, "", primitive.NilObjectID, ErrInvalidStreamToken
+	}
+	productID, err := primitive.ObjectIDFromHex(parts[0])
+	if err != nil {
+		return primitive.NilObjectID, "", primitive.NilObjectID, ErrInvalidStreamToken
+	}
+	viewerID, err := primitive.ObjectIDFromHex(parts[2])
+	if err != nil {
+		return primitive.NilObjectID, "", primitive.NilObjectID, ErrInvalidStreamToken
+	}
+	return productID, parts[1], viewerID, nil
+}
+
+func isVideoKey(key string) bool {
+	return strings.EqualFold(path.Ext(key), ".mp4")
+}
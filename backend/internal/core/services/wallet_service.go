@@ -34,17 +34,17 @@ func (s *WalletService) CreditTransaction(ctx context.Context, creatorID primiti
 	return nil
 }
 
-// GetWalletDetails returns the current balance and recent transactions
-func (s *WalletService) GetWalletDetails(ctx context.Context, creatorID primitive.ObjectID) (int64, []*domain.Transaction, error) {
-	balance, err := s.repo.GetBalance(ctx, creatorID)
+// GetWalletDetails returns the current balance and a page of matching transactions, newest first
+func (s *WalletService) GetWalletDetails(ctx context.Context, filter domain.TransactionFilter, pagination *domain.Pagination) (int64, []*domain.Transaction, *domain.PaginationMeta, error) {
+	balance, err := s.repo.GetBalance(ctx, filter.CreatorID)
 	if err != nil {
-		return 0, nil, fmt.Errorf("failed to get balance: %w", err)
+		return 0, nil, nil, fmt.Errorf("failed to get balance: %w", err)
 	}
 
-	transactions, err := s.repo.FindAllByCreatorID(ctx, creatorID)
+	transactions, meta, err := s.repo.Search(ctx, filter, pagination)
 	if err != nil {
-		return 0, nil, fmt.Errorf("failed to get transactions: %w", err)
+		return 0, nil, nil, fmt.Errorf("failed to get transactions: %w", err)
 	}
 
-	return balance, transactions, nil
+	return balance, transactions, meta, nil
 }
@@ -19,6 +19,15 @@ const (
 	TypeEmailDrip          = "email:drip_campaign"
 	TypeAnalyticsAggregate = "analytics:aggregate"
 	TypeInstagramDM        = "instagram:dm"
+	TypeBookingReminder    = "booking:reminder"
+	TypeSlotReminder       = "booking:slot_reminder"
+	TypeBookingComplete    = "booking:complete"
+	TypePDFStamp           = "download:pdf_stamp"
+	TypeFileUpdateNotify   = "download:file_update_notify"
+	TypeImageProcess       = "image:process"
+	TypeVideoTranscode     = "video:transcode"
+	TypeDataExport         = "privacy:data_export"
+	TypeWebhookDeliver     = "webhook:deliver"
 )
 
 // Payload structs definition
@@ -54,6 +63,49 @@ type InstagramDMPayload struct {
 	Message   string `json:"message"`
 }
 
+type BookingReminderPayload struct {
+	BookingID string `json:"booking_id"`
+	LeadTime  string `json:"lead_time"` // Human readable lead time, e.g. "24 hours"
+}
+
+type SlotReminderPayload struct {
+	ProductID string    `json:"product_id"`
+	SlotStart time.Time `json:"slot_start"`
+	LeadTime  string    `json:"lead_time"`
+}
+
+type BookingCompletePayload struct {
+	BookingID string `json:"booking_id"`
+}
+
+type PDFStampPayload struct {
+	EntitlementID string `json:"entitlement_id"`
+	FileID        string `json:"file_id"`
+}
+
+type ImageProcessPayload struct {
+	FileKey string `json:"file_key"`
+}
+
+type VideoTranscodePayload struct {
+	FileKey string `json:"file_key"`
+}
+
+type DataExportPayload struct {
+	ExportID string `json:"export_id"`
+}
+
+type WebhookDeliveryPayload struct {
+	DeliveryID string `json:"delivery_id"`
+	Attempt    int    `json:"attempt"`
+}
+
+type FileUpdatePayload struct {
+	ProductID string `json:"product_id"`
+	FileID    string `json:"file_id"`
+	Version   int    `json:"version"`
+}
+
 type IGDeliverService interface {
 	SendDM(ctx context.Context, creatorID string, recipientIGID string, message string) error
 }
@@ -70,6 +122,12 @@ type WorkerService struct {
 	igConnRepo   domain.InstagramConnectionRepository
 	igAutoRepo   domain.InstagramAutomationRepository
 	igDeliverSvc IGDeliverService
+	bookingSvc   *BookingService
+	downloadSvc  *DownloadService
+	imageSvc     *ImageService
+	videoSvc     *VideoService
+	privacySvc   *PrivacyService
+	webhookSvc   *CreatorWebhookService
 	analyticsSvc *AnalyticsService
 	dailyRepo    domain.AnalyticsDailyRepository
 	aggregator   interface {
@@ -171,6 +229,15 @@ func (s *WorkerService) registerHandlers() {
 	s.mux.HandleFunc(TypeEmailDrip, s.handleDripCampaign)
 	s.mux.HandleFunc(TypeAnalyticsAggregate, s.handleAnalyticsAggregate)
 	s.mux.HandleFunc(TypeInstagramDM, s.handleInstagramDM)
+	s.mux.HandleFunc(TypeBookingReminder, s.handleBookingReminder)
+	s.mux.HandleFunc(TypeSlotReminder, s.handleSlotReminder)
+	s.mux.HandleFunc(TypeBookingComplete, s.handleBookingComplete)
+	s.mux.HandleFunc(TypePDFStamp, s.handlePDFStamp)
+	s.mux.HandleFunc(TypeFileUpdateNotify, s.handleFileUpdateNotify)
+	s.mux.HandleFunc(TypeImageProcess, s.handleImageProcess)
+	s.mux.HandleFunc(TypeVideoTranscode, s.handleVideoTranscode)
+	s.mux.HandleFunc(TypeDataExport, s.handleDataExport)
+	s.mux.HandleFunc(TypeWebhookDeliver, s.handleWebhookDeliver)
 }
 
 func (s *WorkerService) SetDependencies(
@@ -197,6 +264,36 @@ func (s *WorkerService) SetInstagramDeliverService(svc IGDeliverService) {
 	s.igDeliverSvc = svc
 }
 
+// SetBookingService injects the booking service used by reminder and completion jobs
+func (s *WorkerService) SetBookingService(svc *BookingService) {
+	s.bookingSvc = svc
+}
+
+// SetDownloadService injects the download service used by the PDF stamping and file update jobs
+func (s *WorkerService) SetDownloadService(svc *DownloadService) {
+	s.downloadSvc = svc
+}
+
+// SetImageService injects the image service used to generate resized variants
+func (s *WorkerService) SetImageService(svc *ImageService) {
+	s.imageSvc = svc
+}
+
+// SetVideoService injects the video service used to transcode lesson videos
+func (s *WorkerService) SetVideoService(svc *VideoService) {
+	s.videoSvc = svc
+}
+
+// SetPrivacyService injects the privacy service used to build personal data exports
+func (s *WorkerService) SetPrivacyService(svc *PrivacyService) {
+	s.privacySvc = svc
+}
+
+// SetCreatorWebhookService injects the service that delivers creator webhooks
+func (s *WorkerService) SetCreatorWebhookService(svc *CreatorWebhookService) {
+	s.webhookSvc = svc
+}
+
 // --- Handlers ---
 
 func (s *WorkerService) handleEmailSend(ctx context.Context, t *asynq.Task) error {
@@ -315,6 +412,180 @@ func (s *WorkerService) handleInstagramDM(ctx context.Context, t *asynq.Task) er
 	return nil
 }
 
+// handleBookingReminder sends the pre-session reminder to buyer and creator
+func (s *WorkerService) handleBookingReminder(ctx context.Context, t *asynq.Task) error {
+	var payload BookingReminderPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	if s.bookingSvc == nil {
+		return fmt.Errorf("booking service missing in worker service")
+	}
+
+	logger.Info("Processing booking reminder", "booking_id", payload.BookingID, "lead_time", payload.LeadTime)
+
+	if err := s.bookingSvc.ExecuteBookingReminder(ctx, payload.BookingID, payload.LeadTime); err != nil {
+		logger.Error("Failed to send booking reminder", "error", err, "booking_id", payload.BookingID)
+		return err
+	}
+
+	return nil
+}
+
+// handleSlotReminder sends the creator's pre-session reminder for a slot
+func (s *WorkerService) handleSlotReminder(ctx context.Context, t *asynq.Task) error {
+	var payload SlotReminderPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	if s.bookingSvc == nil {
+		return fmt.Errorf("booking service missing in worker service")
+	}
+
+	if err := s.bookingSvc.ExecuteSlotReminder(ctx, payload.ProductID, payload.SlotStart, payload.LeadTime); err != nil {
+		logger.Error("Failed to send slot reminder", "error", err, "product_id", payload.ProductID)
+		return err
+	}
+
+	return nil
+}
+
+// handleBookingComplete transitions a booking to completed once its slot has ended
+func (s *WorkerService) handleBookingComplete(ctx context.Context, t *asynq.Task) error {
+	var payload BookingCompletePayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	if s.bookingSvc == nil {
+		return fmt.Errorf("booking service missing in worker service")
+	}
+
+	if err := s.bookingSvc.CompleteBooking(ctx, payload.BookingID); err != nil {
+		logger.Error("Failed to complete booking", "error", err, "booking_id", payload.BookingID)
+		return err
+	}
+
+	return nil
+}
+
+// handlePDFStamp personalises a large PDF for a buyer ahead of their download
+func (s *WorkerService) handlePDFStamp(ctx context.Context, t *asynq.Task) error {
+	var payload PDFStampPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	if s.downloadSvc == nil {
+		return fmt.Errorf("download service missing in worker service")
+	}
+
+	if err := s.downloadSvc.StampEntitlement(ctx, payload.EntitlementID, payload.FileID); err != nil {
+		logger.Error("Failed to stamp pdf", "error", err, "entitlement_id", payload.EntitlementID, "file_id", payload.FileID)
+		return err
+	}
+
+	return nil
+}
+
+// handleFileUpdateNotify emails every buyer of a product about a new version of one of its files
+func (s *WorkerService) handleFileUpdateNotify(ctx context.Context, t *asynq.Task) error {
+	var payload FileUpdatePayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	if s.downloadSvc == nil {
+		return fmt.Errorf("download service missing in worker service")
+	}
+
+	if err := s.downloadSvc.NotifyFileUpdate(ctx, payload.ProductID, payload.FileID, payload.Version); err != nil {
+		logger.Error("Failed to notify buyers of file update", "error", err, "product_id", payload.ProductID, "file_id", payload.FileID)
+		return err
+	}
+
+	return nil
+}
+
+// handleImageProcess strips metadata from an uploaded image and generates its variants
+func (s *WorkerService) handleImageProcess(ctx context.Context, t *asynq.Task) error {
+	var payload ImageProcessPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	if s.imageSvc == nil {
+		return fmt.Errorf("image service missing in worker service")
+	}
+
+	if err := s.imageSvc.ProcessUpload(ctx, payload.FileKey); err != nil {
+		logger.Error("Failed to process image", "error", err, "file_key", payload.FileKey)
+		return err
+	}
+
+	return nil
+}
+
+// handleVideoTranscode converts an uploaded lesson video to HLS renditions
+func (s *WorkerService) handleVideoTranscode(ctx context.Context, t *asynq.Task) error {
+	var payload VideoTranscodePayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	if s.videoSvc == nil {
+		return fmt.Errorf("video service missing in worker service")
+	}
+
+	if err := s.videoSvc.ProcessUpload(ctx, payload.FileKey); err != nil {
+		logger.Error("Failed to transcode video", "error", err, "file_key", payload.FileKey)
+		return err
+	}
+
+	return nil
+}
+
+// handleDataExport builds a user's personal data archive
+func (s *WorkerService) handleDataExport(ctx context.Context, t *asynq.Task) error {
+	var payload DataExportPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	if s.privacySvc == nil {
+		return fmt.Errorf("privacy service missing in worker service")
+	}
+
+	if err := s.privacySvc.BuildExport(ctx, payload.ExportID); err != nil {
+		logger.Error("Failed to build data export", "error", err, "export_id", payload.ExportID)
+		return err
+	}
+
+	return nil
+}
+
+// handleWebhookDeliver makes one attempt at delivering a creator webhook. The service schedules
+// its own retries when the endpoint fails; the queue only retries errors saving the outcome.
+func (s *WorkerService) handleWebhookDeliver(ctx context.Context, t *asynq.Task) error {
+	var payload WebhookDeliveryPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	if s.webhookSvc == nil {
+		return fmt.Errorf("creator webhook service missing in worker service")
+	}
+
+	if err := s.webhookSvc.Deliver(ctx, payload.DeliveryID, payload.Attempt); err != nil {
+		logger.Error("Failed to deliver webhook", "error", err, "delivery_id", payload.DeliveryID, "attempt", payload.Attempt)
+		return err
+	}
+
+	return nil
+}
+
 // --- Task Enqueue Helpers ---
 
 // EnqueueEmailTask helper function to fire off an email task
@@ -388,3 +659,117 @@ func EnqueueDripCampaignStepTask(client *asynq.Client, queueID string, creatorID
 	_, err = client.Enqueue(task, asynq.ProcessIn(delay))
 	return err
 }
+
+// EnqueueBookingReminderTask schedules a reminder email for a booking at the given time
+func EnqueueBookingReminderTask(client *asynq.Client, bookingID string, leadTime string, at time.Time) error {
+	payload := BookingReminderPayload{BookingID: bookingID, LeadTime: leadTime}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	task := asynq.NewTask(TypeBookingReminder, bytes, asynq.MaxRetry(3))
+	_, err = client.Enqueue(task, asynq.ProcessAt(at))
+	return err
+}
+
+// EnqueueSlotReminderTask schedules the creator's reminder for a slot. The task ID keeps it to
+// one per slot and lead time, in which case asynq.ErrTaskIDConflict is returned.
+func EnqueueSlotReminderTask(client *asynq.Client, productID string, slotStart time.Time, leadTime string, at time.Time) error {
+	payload := SlotReminderPayload{ProductID: productID, SlotStart: slotStart.UTC(), LeadTime: leadTime}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	task := asynq.NewTask(TypeSlotReminder, bytes, asynq.MaxRetry(3))
+	id := fmt.Sprintf("slot_reminder:%s:%d:%s", productID, slotStart.Unix(), leadTime)
+	_, err = client.Enqueue(task, asynq.ProcessAt(at), asynq.TaskID(id))
+	return err
+}
+
+// EnqueueBookingCompleteTask schedules the completion transition for when a booking's slot ends
+func EnqueueBookingCompleteTask(client *asynq.Client, bookingID string, at time.Time) error {
+	payload := BookingCompletePayload{BookingID: bookingID}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	task := asynq.NewTask(TypeBookingComplete, bytes)
+	_, err = client.Enqueue(task, asynq.ProcessAt(at))
+	return err
+}
+
+// EnqueuePDFStampTask queues stamping of a buyer's PDF. The task ID dedupes concurrent requests
+// for the same file version, in which case asynq.ErrTaskIDConflict is returned.
+func EnqueuePDFStampTask(client *asynq.Client, entitlementID, fileID string, version int) error {
+	payload := PDFStampPayload{EntitlementID: entitlementID, FileID: fileID}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	task := asynq.NewTask(TypePDFStamp, bytes, asynq.MaxRetry(3), asynq.Timeout(10*time.Minute))
+	_, err = client.Enqueue(task, asynq.TaskID(fmt.Sprintf("pdf_stamp:%s:%s:%d", entitlementID, fileID, version)))
+	return err
+}
+
+// EnqueueFileUpdateNotifyTask schedules the "new version available" email to a product's buyers.
+// The task ID makes repeated requests for the same release a no-op.
+func EnqueueFileUpdateNotifyTask(client *asynq.Client, productID, fileID string, version int) error {
+	payload := FileUpdatePayload{ProductID: productID, FileID: fileID, Version: version}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	task := asynq.NewTask(TypeFileUpdateNotify, bytes, asynq.MaxRetry(3), asynq.Timeout(30*time.Minute))
+	_, err = client.Enqueue(task, asynq.TaskID(fmt.Sprintf("file_update:%s:%s:%d", productID, fileID, version)))
+	return err
+}
+
+// EnqueueImageProcessTask queues metadata stripping and variant generation for an uploaded image.
+func EnqueueImageProcessTask(client *asynq.Client, fileKey string) error {
+	payload := ImageProcessPayload{FileKey: fileKey}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	task := asynq.NewTask(TypeImageProcess, bytes, asynq.MaxRetry(3), asynq.Timeout(5*time.Minute))
+	_, err = client.Enqueue(task, asynq.TaskID("image_process:"+fileKey))
+	return err
+}
+
+// EnqueueVideoTranscodeTask queues HLS transcoding for an uploaded lesson video.
+func EnqueueVideoTranscodeTask(client *asynq.Client, fileKey string) error {
+	payload := VideoTranscodePayload{FileKey: fileKey}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	task := asynq.NewTask(TypeVideoTranscode, bytes, asynq.MaxRetry(2), asynq.Timeout(5*time.Hour))
+	_, err = client.Enqueue(task, asynq.TaskID("video_transcode:"+fileKey))
+	return err
+}
+
+// EnqueueDataExportTask queues building a personal data export. A failed build marks the export
+// as failed for the user to retry, so the task itself is not retried.
+func EnqueueDataExportTask(client *asynq.Client, exportID string) error {
+	payload := DataExportPayload{ExportID: exportID}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	task := asynq.NewTask(TypeDataExport, bytes, asynq.MaxRetry(0), asynq.Timeout(15*time.Minute))
+	_, err = client.Enqueue(task, asynq.TaskID("data_export:"+exportID))
+	return err
+}
+
+// EnqueueWebhookDeliveryTask queues attempt n of a creator webhook delivery after delay. The
+// task ID makes each attempt run once.
+func EnqueueWebhookDeliveryTask(client *asynq.Client, deliveryID string, n int, delay time.Duration) error {
+	payload := WebhookDeliveryPayload{DeliveryID: deliveryID, Attempt: n}
+	bytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	task := asynq.NewTask(TypeWebhookDeliver, bytes, asynq.MaxRetry(2), asynq.Timeout(time.Minute))
+	_, err = client.Enqueue(task, asynq.ProcessIn(delay), asynq.TaskID(fmt.Sprintf("webhook_delivery:%s:%d", deliveryID, n)))
+	return err
+}
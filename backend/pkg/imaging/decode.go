@@ -0,0 +1,147 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png" // register the PNG decoder
+)
+
+// ErrUnsupportedFormat is returned for formats the standard library cannot decode (e.g. WebP).
+var ErrUnsupportedFormat = errors.New("unsupported image format")
+
+// Decode decodes a JPEG or PNG image and applies its EXIF orientation, so the result is
+// upright once the metadata has been dropped.
+func Decode(data []byte) (image.Image, string, error) {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		if errors.Is(err, image.ErrFormat) {
+			return nil, "", ErrUnsupportedFormat
+		}
+		return nil, "", err
+	}
+	if format == "jpeg" {
+		img = Orient(img, JPEGOrientation(data))
+	}
+	return img, format, nil
+}
+
+// EncodeJPEG encodes img as a baseline JPEG. Transparent areas are flattened onto white.
+func EncodeJPEG(img image.Image, quality int) ([]byte, error) {
+	if !opaque(img) {
+		img = flatten(img)
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func opaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return false
+}
+
+func flatten(img image.Image) image.Image {
+	src := toNRGBA(img)
+	b := src.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			p := src.NRGBAAt(b.Min.X+x, b.Min.Y+y)
+			a := uint32(p.A)
+			blend := func(c uint8) uint8 { return uint8((uint32(c)*a + 255*(255-a) + 127) / 255) }
+			dst.SetRGBA(x, y, color.RGBA{R: blend(p.R), G: blend(p.G), B: blend(p.B), A: 255})
+		}
+	}
+	return dst
+}
+
+// JPEGOrientation returns the EXIF orientation (1-8) of a JPEG, or 1 if it has none.
+func JPEGOrientation(data []byte) int {
+	for _, seg := range jpegSegments(data) {
+		if seg.marker != 0xE1 || !bytes.HasPrefix(seg.payload, []byte("Exif\x00\x00")) {
+			continue
+		}
+		if o := tiffOrientation(seg.payload[6:]); o >= 1 && o <= 8 {
+			return o
+		}
+	}
+	return 1
+}
+
+// tiffOrientation reads tag 0x0112 from the first IFD of an EXIF TIFF block.
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd+2 > len(tiff) {
+		return 0
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 0
+}
+
+// Orient transforms img according to an EXIF orientation value.
+func Orient(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+	src := toNRGBA(img)
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirrored horizontally
+				dx, dy = w-1-x, y
+			case 3: // rotated 180
+				dx, dy = w-1-x, h-1-y
+			case 4: // mirrored vertically
+				dx, dy = x, h-1-y
+			case 5: // transposed
+				dx, dy = y, x
+			case 6: // rotated 90 clockwise
+				dx, dy = h-1-y, x
+			case 7: // transversed
+				dx, dy = h-1-y, w-1-x
+			case 8: // rotated 90 counter-clockwise
+				dx, dy = y, w-1-x
+			}
+			dst.SetNRGBA(dx, dy, src.NRGBAAt(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
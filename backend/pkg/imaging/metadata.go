@@ -0,0 +1,154 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// ErrMalformed is returned when an image container cannot be parsed.
+var ErrMalformed = errors.New("malformed image")
+
+// StripMetadata removes EXIF, XMP, IPTC and comment blocks from a JPEG, PNG or WebP file
+// without re-encoding the pixels. JPEGs whose EXIF orientation is not upright are
+// re-encoded rotated instead, since dropping the tag would turn them sideways.
+func StripMetadata(data []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8}):
+		if JPEGOrientation(data) != 1 {
+			img, _, err := Decode(data)
+			if err != nil {
+				return nil, err
+			}
+			return EncodeJPEG(img, 92)
+		}
+		return stripJPEG(data)
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return stripPNG(data)
+	case len(data) >= 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return stripWebP(data)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+type jpegSegment struct {
+	marker     byte
+	start, end int // bounds of the whole segment, marker included
+	payload    []byte
+}
+
+// jpegSegments returns the marker segments that precede the image data (SOS).
+func jpegSegments(data []byte) []jpegSegment {
+	var segs []jpegSegment
+	i := 2
+	for i+4 <= len(data) {
+		if data[i] != 0xFF {
+			return segs
+		}
+		marker := data[i+1]
+		if marker == 0xFF { // fill byte
+			i++
+			continue
+		}
+		if marker == 0xDA || marker == 0xD9 {
+			return segs
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			return segs
+		}
+		segs = append(segs, jpegSegment{marker: marker, start: i, end: end, payload: data[i+4 : end]})
+		i = end
+	}
+	return segs
+}
+
+func stripJPEG(data []byte) ([]byte, error) {
+	segs := jpegSegments(data)
+	if len(segs) == 0 {
+		return nil, ErrMalformed
+	}
+	out := make([]byte, 0, len(data))
+	out = append(out, 0xFF, 0xD8)
+	for _, seg := range segs {
+		if dropJPEGSegment(seg) {
+			continue
+		}
+		out = append(out, data[seg.start:seg.end]...)
+	}
+	return append(out, data[segs[len(segs)-1].end:]...), nil
+}
+
+// dropJPEGSegment keeps JFIF (APP0), ICC profiles (APP2) and the Adobe colour transform
+// (APP14), and drops every other application segment and comments.
+func dropJPEGSegment(seg jpegSegment) bool {
+	switch {
+	case seg.marker == 0xFE:
+		return true
+	case seg.marker == 0xE0, seg.marker == 0xEE:
+		return false
+	case seg.marker == 0xE2:
+		return !bytes.HasPrefix(seg.payload, []byte("ICC_PROFILE\x00"))
+	case seg.marker >= 0xE1 && seg.marker <= 0xEF:
+		return true
+	}
+	return false
+}
+
+func stripPNG(data []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:8]...)
+	i := 8
+	for i < len(data) {
+		if i+12 > len(data) {
+			return nil, ErrMalformed
+		}
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		end := i + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, ErrMalformed
+		}
+		switch string(data[i+4 : i+8]) {
+		case "eXIf", "tEXt", "zTXt", "iTXt", "tIME":
+		default:
+			out = append(out, data[i:end]...)
+		}
+		if string(data[i+4:i+8]) == "IEND" {
+			break
+		}
+		i = end
+	}
+	return out, nil
+}
+
+func stripWebP(data []byte) ([]byte, error) {
+	out := make([]byte, 12, len(data))
+	copy(out, data[:12])
+	i := 12
+	for i+8 <= len(data) {
+		fourCC := string(data[i : i+4])
+		size := int(binary.LittleEndian.Uint32(data[i+4:]))
+		end := i + 8 + size + size&1
+		if end > len(data) {
+			if i+8+size > len(data) {
+				return nil, ErrMalformed
+			}
+			end = len(data)
+		}
+		switch fourCC {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			chunk := append([]byte(nil), data[i:end]...)
+			if len(chunk) > 8 {
+				chunk[8] &^= 0x08 | 0x04 // clear the EXIF and XMP flags
+			}
+			out = append(out, chunk...)
+		default:
+			out = append(out, data[i:end]...)
+		}
+		i = end
+	}
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	return out, nil
+}
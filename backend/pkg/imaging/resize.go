@@ -0,0 +1,132 @@
+// Package imaging provides the small set of image operations the upload pipeline needs:
+// decoding with EXIF orientation applied, area-averaging downscaling, JPEG encoding and
+// lossless metadata removal. Images are never upscaled.
+package imaging
+
+import (
+	"image"
+	"image/draw"
+)
+
+// Fit scales img down so it fits within maxW x maxH, keeping its aspect ratio.
+// Images that already fit are returned unchanged.
+func Fit(img image.Image, maxW, maxH int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxW && h <= maxH {
+		return img
+	}
+	scale := min(float64(maxW)/float64(w), float64(maxH)/float64(h))
+	dw := max(1, int(float64(w)*scale+0.5))
+	dh := max(1, int(float64(h)*scale+0.5))
+	return resample(toNRGBA(img), dw, dh)
+}
+
+// Fill crops img to the aspect ratio of size x size around its centre and scales it down to
+// a square of that size, or of the shorter side if the image is smaller.
+func Fill(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	side := min(b.Dx(), b.Dy())
+	x0 := b.Min.X + (b.Dx()-side)/2
+	y0 := b.Min.Y + (b.Dy()-side)/2
+	cropped := toNRGBA(img).SubImage(image.Rect(x0, y0, x0+side, y0+side)).(*image.NRGBA)
+	if side <= size {
+		return cropped
+	}
+	return resample(cropped, size, size)
+}
+
+func toNRGBA(img image.Image) *image.NRGBA {
+	if n, ok := img.(*image.NRGBA); ok {
+		return n
+	}
+	b := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+	return dst
+}
+
+// contribution is the share of one source pixel in one destination pixel.
+type contribution struct {
+	index  int
+	weight float32
+}
+
+// spans computes, for each of dstLen output pixels, the source pixels it covers and by how
+// much. Weights for each output pixel sum to 1.
+func spans(srcLen, dstLen int) [][]contribution {
+	scale := float64(srcLen) / float64(dstLen)
+	out := make([][]contribution, dstLen)
+	for i := range out {
+		start := float64(i) * scale
+		end := start + scale
+		for j := int(start); j < srcLen && float64(j) < end; j++ {
+			overlap := min(end, float64(j+1)) - max(start, float64(j))
+			if overlap > 0 {
+				out[i] = append(out[i], contribution{index: j, weight: float32(overlap / scale)})
+			}
+		}
+	}
+	return out
+}
+
+// resample downscales src to w x h by area averaging, in premultiplied alpha so transparent
+// pixels do not bleed their colour into the result.
+func resample(src *image.NRGBA, w, h int) *image.NRGBA {
+	sb := src.Bounds()
+	sw, sh := sb.Dx(), sb.Dy()
+
+	// Horizontal pass: sw x sh -> w x sh.
+	cols := spans(sw, w)
+	tmp := make([]float32, w*sh*4)
+	for y := 0; y < sh; y++ {
+		row := src.Pix[(y)*src.Stride:]
+		for x, contribs := range cols {
+			var r, g, b, a float32
+			for _, c := range contribs {
+				p := row[c.index*4 : c.index*4+4]
+				alpha := float32(p[3]) * c.weight
+				r += float32(p[0]) * alpha
+				g += float32(p[1]) * alpha
+				b += float32(p[2]) * alpha
+				a += alpha
+			}
+			o := (y*w + x) * 4
+			tmp[o], tmp[o+1], tmp[o+2], tmp[o+3] = r, g, b, a
+		}
+	}
+
+	// Vertical pass: w x sh -> w x h.
+	rows := spans(sh, h)
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y, contribs := range rows {
+		for x := 0; x < w; x++ {
+			var r, g, b, a float32
+			for _, c := range contribs {
+				o := (c.index*w + x) * 4
+				r += tmp[o] * c.weight
+				g += tmp[o+1] * c.weight
+				b += tmp[o+2] * c.weight
+				a += tmp[o+3] * c.weight
+			}
+			o := y*dst.Stride + x*4
+			if a > 0 {
+				dst.Pix[o] = clamp(r / a)
+				dst.Pix[o+1] = clamp(g / a)
+				dst.Pix[o+2] = clamp(b / a)
+			}
+			dst.Pix[o+3] = clamp(a)
+		}
+	}
+	return dst
+}
+
+func clamp(v float32) uint8 {
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 255:
+		return 255
+	}
+	return uint8(v + 0.5)
+}
@@ -0,0 +1,44 @@
+package pdf
+
+// Glyph widths (in 1/1000 em) for printable ASCII 32..126, from the Adobe AFM files.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // space .. /
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, // 0 .. 9
+	278, 278, 584, 584, 584, 556, 1015, // : .. @
+	667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, // A .. M
+	722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, // N .. Z
+	278, 278, 278, 469, 556, 333, // [ .. `
+	556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, // a .. m
+	556, 556, 556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, // n .. z
+	334, 260, 334, 584, // { .. ~
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556,
+	333, 333, 584, 584, 584, 611, 975,
+	722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833,
+	722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611,
+	333, 278, 333, 584, 556, 333,
+	556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889,
+	611, 611, 611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500,
+	389, 280, 389, 584,
+}
+
+// TextWidth returns the rendered width of s in points.
+func TextWidth(font Font, size float64, s string) float64 {
+	widths := &helveticaWidths
+	if font == HelveticaBold {
+		widths = &helveticaBoldWidths
+	}
+
+	total := 0
+	for _, r := range Transliterate(s) {
+		if r >= 32 && r < 127 {
+			total += widths[r-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
@@ -0,0 +1,251 @@
+// Package pdf is a small, dependency-free PDF writer for generated documents
+// such as certificates and invoices. It supports the standard Helvetica fonts,
+// text, lines and rectangles — enough for simple single-purpose layouts. Text
+// outside WinAnsi is transliterated where possible (see Transliterate).
+//
+// Coordinates are in points with the origin at the top-left corner of the page.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Page sizes in points.
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// Font identifies one of the built-in PDF base fonts.
+type Font string
+
+const (
+	Helvetica        Font = "Helvetica"
+	HelveticaBold    Font = "Helvetica-Bold"
+	HelveticaOblique Font = "Helvetica-Oblique"
+)
+
+// fontResources maps fonts to their resource names inside page content streams.
+var fontResources = []struct {
+	Font Font
+	Name string
+}{
+	{Helvetica, "F1"},
+	{HelveticaBold, "F2"},
+	{HelveticaOblique, "F3"},
+}
+
+// Color is an RGB colour with components in the 0..1 range.
+type Color struct {
+	R, G, B float64
+}
+
+// Black is the default drawing colour.
+var Black = Color{0, 0, 0}
+
+// HexColor parses "#RRGGBB" (the leading # is optional). Invalid input yields Black.
+func HexColor(hex string) Color {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) != 6 {
+		return Black
+	}
+	var r, g, b uint8
+	if _, err := fmt.Sscanf(hex, "%02x%02x%02x", &r, &g, &b); err != nil {
+		return Black
+	}
+	return Color{float64(r) / 255, float64(g) / 255, float64(b) / 255}
+}
+
+// Document is a PDF under construction.
+type Document struct {
+	width, height float64
+	title         string
+	pages         []*Page
+}
+
+// New creates an empty document whose pages have the given size in points.
+func New(width, height float64) *Document {
+	return &Document{width: width, height: height}
+}
+
+// SetTitle sets the document title shown by PDF viewers.
+func (d *Document) SetTitle(title string) {
+	d.title = title
+}
+
+// Width returns the page width in points.
+func (d *Document) Width() float64 { return d.width }
+
+// Height returns the page height in points.
+func (d *Document) Height() float64 { return d.height }
+
+// AddPage appends a blank page and returns it for drawing.
+func (d *Document) AddPage() *Page {
+	p := &Page{doc: d}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// Page is a single page's content stream.
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+}
+
+// y converts a top-left based coordinate into PDF user space.
+func (p *Page) y(y float64) float64 {
+	return p.doc.height - y
+}
+
+// Text draws s with its baseline starting at (x, y).
+func (p *Page) Text(x, y float64, font Font, size float64, color Color, s string) {
+	fmt.Fprintf(&p.content, "BT /%s %.2f Tf %.3f %.3f %.3f rg %.2f %.2f Td (%s) Tj ET\n",
+		resourceName(font), size, color.R, color.G, color.B, x, p.y(y), escape(s))
+}
+
+// TextCentered draws s horizontally centred on the page.
+func (p *Page) TextCentered(y float64, font Font, size float64, color Color, s string) {
+	p.Text((p.doc.width-TextWidth(font, size, s))/2, y, font, size, color, s)
+}
+
+// TextRight draws s so that it ends at x.
+func (p *Page) TextRight(x, y float64, font Font, size float64, color Color, s string) {
+	p.Text(x-TextWidth(font, size, s), y, font, size, color, s)
+}
+
+// Line draws a straight line.
+func (p *Page) Line(x1, y1, x2, y2, width float64, color Color) {
+	fmt.Fprintf(&p.content, "%.3f %.3f %.3f RG %.2f w %.2f %.2f m %.2f %.2f l S\n",
+		color.R, color.G, color.B, width, x1, p.y(y1), x2, p.y(y2))
+}
+
+// Rect strokes a rectangle whose top-left corner is (x, y).
+func (p *Page) Rect(x, y, w, h, lineWidth float64, color Color) {
+	fmt.Fprintf(&p.content, "%.3f %.3f %.3f RG %.2f w %.2f %.2f %.2f %.2f re S\n",
+		color.R, color.G, color.B, lineWidth, x, p.y(y+h), w, h)
+}
+
+// FillRect fills a rectangle whose top-left corner is (x, y).
+func (p *Page) FillRect(x, y, w, h float64, color Color) {
+	fmt.Fprintf(&p.content, "%.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f\n",
+		color.R, color.G, color.B, x, p.y(y+h), w, h)
+}
+
+// WrapText splits s into lines no wider than maxWidth.
+func WrapText(font Font, size float64, s string, maxWidth float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		line := words[0]
+		for _, w := range words[1:] {
+			candidate := line + " " + w
+			if TextWidth(font, size, candidate) > maxWidth {
+				lines = append(lines, line)
+				line = w
+				continue
+			}
+			line = candidate
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Bytes serialises the document.
+func (d *Document) Bytes() []byte {
+	var buf bytes.Buffer
+	var offsets []int
+
+	// Object numbering: 1 catalog, 2 page tree, 3 info, then fonts, then a (page, content) pair per page.
+	fontBase := 4
+	pageBase := fontBase + len(fontResources)
+	objCount := pageBase + 2*len(d.pages) - 1
+
+	startObj := func(n int) {
+		for len(offsets) < n {
+			offsets = append(offsets, 0)
+		}
+		offsets[n-1] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n", n)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	startObj(1)
+	buf.WriteString("<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+
+	startObj(2)
+	kids := make([]string, 0, len(d.pages))
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageBase+2*i))
+	}
+	fmt.Fprintf(&buf, "<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %.2f %.2f] >>\nendobj\n",
+		strings.Join(kids, " "), len(d.pages), d.width, d.height)
+
+	startObj(3)
+	fmt.Fprintf(&buf, "<< /Title (%s) /Producer (stan-store) >>\nendobj\n", escape(d.title))
+
+	var fontDict strings.Builder
+	for i, f := range fontResources {
+		startObj(fontBase + i)
+		fmt.Fprintf(&buf, "<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>\nendobj\n", f.Font)
+		fmt.Fprintf(&fontDict, "/%s %d 0 R ", f.Name, fontBase+i)
+	}
+
+	for i, p := range d.pages {
+		pageObj := pageBase + 2*i
+		startObj(pageObj)
+		fmt.Fprintf(&buf, "<< /Type /Page /Parent 2 0 R /Resources << /Font << %s>> >> /Contents %d 0 R >>\nendobj\n",
+			fontDict.String(), pageObj+1)
+
+		startObj(pageObj + 1)
+		fmt.Fprintf(&buf, "<< /Length %d >>\nstream\n", p.content.Len())
+		buf.Write(p.content.Bytes())
+		buf.WriteString("\nendstream\nendobj\n")
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", objCount+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", objCount+1, xref)
+
+	return buf.Bytes()
+}
+
+func resourceName(font Font) string {
+	for _, f := range fontResources {
+		if f.Font == font {
+			return f.Name
+		}
+	}
+	return fontResources[0].Name
+}
+
+// escape transliterates s to WinAnsi and escapes PDF string delimiters.
+// Characters that can't be transliterated are replaced with '?'.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range Transliterate(s) {
+		c, ok := winAnsiByte(r)
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case !ok:
+			b.WriteByte('?')
+		case c < 127:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "\\%03o", c)
+		}
+	}
+	return b.String()
+}
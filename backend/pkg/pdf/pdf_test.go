@@ -0,0 +1,149 @@
+package pdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Hello", "Hello"},
+		{"(a) \\ b", `\(a\) \\ b`},
+		{"Café", `Caf\351`},
+		{"₹100", "?100"},
+		{"€5 – “quoted”", `\200` + "5 " + `\226 \223quoted\224`},
+		{"Dāsa", "Dasa"},
+		{"प्रिया", "Priya"},
+		{"中文", "??"},
+	}
+	for _, tt := range tests {
+		if got := escape(tt.in); got != tt.want {
+			t.Errorf("escape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	tests := []struct {
+		font Font
+		size float64
+		s    string
+		want float64
+	}{
+		{Helvetica, 10, "", 0},
+		{Helvetica, 10, "A", 6.67},
+		{HelveticaBold, 10, "A", 7.22},
+		{Helvetica, 20, "ii", 8.88},
+		{Helvetica, 10, "é", 5.56},        // Outside ASCII uses an average width
+		{Helvetica, 10, "Ā", 6.67},        // Transliterated to A first
+		{HelveticaOblique, 10, "A", 6.67}, // Oblique shares Helvetica's widths
+	}
+	for _, tt := range tests {
+		got := TextWidth(tt.font, tt.size, tt.s)
+		if diff := got - tt.want; diff > 0.001 || diff < -0.001 {
+			t.Errorf("TextWidth(%s, %v, %q) = %v, want %v", tt.font, tt.size, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	lines := WrapText(Helvetica, 10, "the quick brown fox jumps over the lazy dog", 60)
+	if len(lines) < 2 {
+		t.Fatalf("WrapText returned %d lines, want several", len(lines))
+	}
+	for _, line := range lines {
+		if TextWidth(Helvetica, 10, line) > 60 && strings.Contains(line, " ") {
+			t.Errorf("line %q is wider than the limit", line)
+		}
+	}
+	if got := strings.Join(lines, " "); got != "the quick brown fox jumps over the lazy dog" {
+		t.Errorf("wrapped lines rejoin to %q", got)
+	}
+}
+
+func TestHexColor(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Color
+	}{
+		{"#ffffff", Color{1, 1, 1}},
+		{"000000", Black},
+		{"#ff0000", Color{1, 0, 0}},
+		{"#fff", Black},
+		{"#zzzzzz", Black},
+	}
+	for _, tt := range tests {
+		if got := HexColor(tt.in); got != tt.want {
+			t.Errorf("HexColor(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDocumentRoundTrip(t *testing.T) {
+	doc := New(A4Width, A4Height)
+	doc.SetTitle("Invoice (draft)")
+	doc.AddPage().Text(50, 60, Helvetica, 12, Black, "Hello (world)")
+	doc.AddPage().Text(50, 60, HelveticaBold, 12, Black, "Priya – शर्मा")
+	out := doc.Bytes()
+
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatalf("missing PDF header or trailer")
+	}
+
+	r, err := newReader(out)
+	if err != nil {
+		t.Fatalf("newReader: %v", err)
+	}
+	pages, err := r.pages()
+	if err != nil {
+		t.Fatalf("pages: %v", err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("got %d pages, want 2", len(pages))
+	}
+
+	wantText := []string{`(Hello \(world\)) Tj`, `(Priya \226 Sharma) Tj`}
+	for i, pg := range pages {
+		if pg.mediaBox != [4]float64{0, 0, A4Width, A4Height} {
+			t.Errorf("page %d media box = %v", i, pg.mediaBox)
+		}
+		if _, ok := r.resolve(pg.resources).(pdfDict)["Font"]; !ok {
+			t.Errorf("page %d has no fonts", i)
+		}
+		if got := pageContent(t, r, pg); !strings.Contains(got, wantText[i]) {
+			t.Errorf("page %d content %q does not contain %q", i, got, wantText[i])
+		}
+	}
+
+	info, _ := r.resolve(r.trailer["Info"]).(pdfDict)
+	if title, _ := info["Title"].(pdfString); string(title) != "Invoice (draft)" {
+		t.Errorf("title = %q", title)
+	}
+}
+
+// pageContent returns a page's decoded content streams joined together.
+func pageContent(t *testing.T, r *reader, pg pageInfo) string {
+	t.Helper()
+	refs := pdfArray{pg.dict["Contents"]}
+	if arr, ok := r.resolve(pg.dict["Contents"]).(pdfArray); ok {
+		refs = arr
+	}
+	var b strings.Builder
+	for _, ref := range refs {
+		stm, ok := r.resolve(ref).(*pdfStream)
+		if !ok {
+			t.Fatalf("content %v is not a stream", ref)
+		}
+		data, err := r.decode(stm)
+		if err != nil {
+			t.Fatalf("decode content: %v", err)
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,712 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+var (
+	// ErrMalformed is returned when a document cannot be parsed.
+	ErrMalformed = errors.New("pdf: malformed document")
+	// ErrEncrypted is returned for password-protected documents, which cannot be modified.
+	ErrEncrypted = errors.New("pdf: encrypted documents are not supported")
+)
+
+// PDF object model used when reading existing documents.
+type (
+	pdfName   string
+	pdfString []byte
+	pdfRef    struct{ num, gen int }
+	pdfDict   map[string]any
+	pdfArray  []any
+	pdfStream struct {
+		dict pdfDict
+		data []byte // still encoded
+	}
+)
+
+// xrefEntry locates an object: type 1 entries are at a byte offset, type 2 live inside an object stream.
+type xrefEntry struct {
+	typ    int
+	offset int64
+	gen    int
+	stream int
+	index  int
+}
+
+// reader resolves objects in an existing PDF.
+type reader struct {
+	buf            []byte
+	xref           map[int]xrefEntry
+	trailer        pdfDict
+	startxref      int64
+	usesXRefStream bool
+	cache          map[int]any
+}
+
+func newReader(buf []byte) (*reader, error) {
+	r := &reader{buf: buf, xref: map[int]xrefEntry{}, cache: map[int]any{}}
+
+	idx := bytes.LastIndex(buf, []byte("startxref"))
+	if idx < 0 {
+		return nil, ErrMalformed
+	}
+	p := &lexer{buf: buf, pos: idx + len("startxref")}
+	v, err := p.object()
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	offset, ok := v.(int64)
+	if !ok || offset < 0 || offset >= int64(len(buf)) {
+		return nil, ErrMalformed
+	}
+	r.startxref = offset
+
+	if err := r.loadXRef(offset, map[int64]bool{}, true); err != nil {
+		return nil, err
+	}
+	if r.trailer == nil {
+		return nil, ErrMalformed
+	}
+	if _, ok := r.trailer["Encrypt"]; ok {
+		return nil, ErrEncrypted
+	}
+	return r, nil
+}
+
+// loadXRef reads the cross-reference section at offset and every older section it chains to.
+// Sections are read newest first, so entries already present take precedence.
+func (r *reader) loadXRef(offset int64, seen map[int64]bool, newest bool) error {
+	if seen[offset] || offset < 0 || offset >= int64(len(r.buf)) {
+		return nil
+	}
+	seen[offset] = true
+
+	p := &lexer{buf: r.buf, pos: int(offset)}
+	p.skipSpace()
+	if bytes.HasPrefix(r.buf[p.pos:], []byte("xref")) {
+		p.pos += len("xref")
+		trailer, err := r.readXRefTable(p)
+		if err != nil {
+			return err
+		}
+		if r.trailer == nil {
+			r.trailer = trailer
+		}
+		if stm, ok := trailer["XRefStm"].(int64); ok {
+			if err := r.loadXRef(stm, seen, false); err != nil {
+				return err
+			}
+		}
+		if prev, ok := trailer["Prev"].(int64); ok {
+			return r.loadXRef(prev, seen, false)
+		}
+		return nil
+	}
+
+	_, obj, err := r.indirectAt(int(offset))
+	if err != nil {
+		return err
+	}
+	stm, ok := obj.(*pdfStream)
+	if !ok || stm.dict["Type"] != pdfName("XRef") {
+		return ErrMalformed
+	}
+	if newest {
+		r.usesXRefStream = true
+	}
+	if err := r.readXRefStream(stm); err != nil {
+		return err
+	}
+	if r.trailer == nil {
+		r.trailer = stm.dict
+	}
+	if prev, ok := stm.dict["Prev"].(int64); ok {
+		return r.loadXRef(prev, seen, false)
+	}
+	return nil
+}
+
+func (r *reader) readXRefTable(p *lexer) (pdfDict, error) {
+	for {
+		p.skipSpace()
+		if bytes.HasPrefix(p.buf[p.pos:], []byte("trailer")) {
+			p.pos += len("trailer")
+			v, err := p.object()
+			if err != nil {
+				return nil, err
+			}
+			d, ok := v.(pdfDict)
+			if !ok {
+				return nil, ErrMalformed
+			}
+			return d, nil
+		}
+
+		start, err1 := p.integer()
+		count, err2 := p.integer()
+		if err1 != nil || err2 != nil {
+			return nil, ErrMalformed
+		}
+		for i := 0; i < int(count); i++ {
+			off, err1 := p.integer()
+			gen, err2 := p.integer()
+			kind := p.keyword()
+			if err1 != nil || err2 != nil || (kind != "n" && kind != "f") {
+				return nil, ErrMalformed
+			}
+			num := int(start) + i
+			if _, exists := r.xref[num]; exists {
+				continue
+			}
+			if kind == "n" {
+				r.xref[num] = xrefEntry{typ: 1, offset: off, gen: int(gen)}
+			} else {
+				r.xref[num] = xrefEntry{typ: 0}
+			}
+		}
+	}
+}
+
+func (r *reader) readXRefStream(stm *pdfStream) error {
+	data, err := r.decode(stm)
+	if err != nil {
+		return err
+	}
+
+	w, ok := stm.dict["W"].(pdfArray)
+	if !ok || len(w) != 3 {
+		return ErrMalformed
+	}
+	var widths [3]int
+	for i, v := range w {
+		n, ok := v.(int64)
+		if !ok || n < 0 || n > 8 {
+			return ErrMalformed
+		}
+		widths[i] = int(n)
+	}
+	rowLen := widths[0] + widths[1] + widths[2]
+	if rowLen == 0 {
+		return ErrMalformed
+	}
+
+	size, _ := stm.dict["Size"].(int64)
+	index := pdfArray{int64(0), size}
+	if idx, ok := stm.dict["Index"].(pdfArray); ok {
+		index = idx
+	}
+
+	field := func(b []byte, def int64) int64 {
+		if len(b) == 0 {
+			return def
+		}
+		var v int64
+		for _, c := range b {
+			v = v<<8 | int64(c)
+		}
+		return v
+	}
+
+	pos := 0
+	for i := 0; i+1 < len(index); i += 2 {
+		start, ok1 := index[i].(int64)
+		count, ok2 := index[i+1].(int64)
+		if !ok1 || !ok2 {
+			return ErrMalformed
+		}
+		for j := int64(0); j < count; j++ {
+			if pos+rowLen > len(data) {
+				return ErrMalformed
+			}
+			row := data[pos : pos+rowLen]
+			pos += rowLen
+
+			num := int(start + j)
+			if _, exists := r.xref[num]; exists {
+				continue
+			}
+			typ := field(row[:widths[0]], 1)
+			f2 := field(row[widths[0]:widths[0]+widths[1]], 0)
+			f3 := field(row[widths[0]+widths[1]:], 0)
+			switch typ {
+			case 1:
+				r.xref[num] = xrefEntry{typ: 1, offset: f2, gen: int(f3)}
+			case 2:
+				r.xref[num] = xrefEntry{typ: 2, stream: int(f2), index: int(f3)}
+			default:
+				r.xref[num] = xrefEntry{typ: 0}
+			}
+		}
+	}
+	return nil
+}
+
+// indirectAt parses "num gen obj ... endobj" at offset.
+func (r *reader) indirectAt(offset int) (pdfRef, any, error) {
+	p := &lexer{buf: r.buf, pos: offset}
+	num, err1 := p.integer()
+	gen, err2 := p.integer()
+	if err1 != nil || err2 != nil || p.keyword() != "obj" {
+		return pdfRef{}, nil, ErrMalformed
+	}
+	obj, err := p.object()
+	if err != nil {
+		return pdfRef{}, nil, err
+	}
+
+	d, isDict := obj.(pdfDict)
+	p.skipSpace()
+	if !isDict || !bytes.HasPrefix(r.buf[p.pos:], []byte("stream")) {
+		return pdfRef{int(num), int(gen)}, obj, nil
+	}
+
+	p.pos += len("stream")
+	if p.pos < len(r.buf) && r.buf[p.pos] == '\r' {
+		p.pos++
+	}
+	if p.pos < len(r.buf) && r.buf[p.pos] == '\n' {
+		p.pos++
+	}
+	start := p.pos
+
+	length, _ := r.resolve(d["Length"]).(int64)
+	end := start + int(length)
+	if length <= 0 || end > len(r.buf) || !bytes.Contains(r.buf[end:min(end+32, len(r.buf))], []byte("endstream")) {
+		// Missing or wrong /Length: fall back to scanning for the end marker.
+		i := bytes.Index(r.buf[start:], []byte("endstream"))
+		if i < 0 {
+			return pdfRef{}, nil, ErrMalformed
+		}
+		end = start + i
+		for end > start && (r.buf[end-1] == '\n' || r.buf[end-1] == '\r') {
+			end--
+		}
+	}
+	return pdfRef{int(num), int(gen)}, &pdfStream{dict: d, data: r.buf[start:end]}, nil
+}
+
+// resolve follows indirect references.
+func (r *reader) resolve(v any) any {
+	for i := 0; i < 8; i++ {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		v = r.object(ref.num)
+	}
+	return nil
+}
+
+func (r *reader) object(num int) any {
+	if v, ok := r.cache[num]; ok {
+		return v
+	}
+	r.cache[num] = nil // guards against reference cycles
+
+	var obj any
+	entry, ok := r.xref[num]
+	switch {
+	case !ok:
+	case entry.typ == 1:
+		if _, v, err := r.indirectAt(int(entry.offset)); err == nil {
+			obj = v
+		}
+	case entry.typ == 2:
+		obj = r.objectFromStream(entry.stream, entry.index)
+	}
+
+	r.cache[num] = obj
+	return obj
+}
+
+func (r *reader) objectFromStream(streamNum, index int) any {
+	stm, ok := r.object(streamNum).(*pdfStream)
+	if !ok {
+		return nil
+	}
+	data, err := r.decode(stm)
+	if err != nil {
+		return nil
+	}
+	n, _ := stm.dict["N"].(int64)
+	first, _ := stm.dict["First"].(int64)
+	if index >= int(n) || int(first) > len(data) {
+		return nil
+	}
+
+	header := &lexer{buf: data[:first]}
+	var offset int64
+	for i := 0; i <= index; i++ {
+		if _, err := header.integer(); err != nil {
+			return nil
+		}
+		off, err := header.integer()
+		if err != nil {
+			return nil
+		}
+		offset = off
+	}
+	if int(first+offset) >= len(data) {
+		return nil
+	}
+	p := &lexer{buf: data, pos: int(first + offset)}
+	obj, err := p.object()
+	if err != nil {
+		return nil
+	}
+	return obj
+}
+
+// decode applies a stream's filters. Only FlateDecode (with optional PNG predictors) is supported.
+func (r *reader) decode(stm *pdfStream) ([]byte, error) {
+	var filters pdfArray
+	switch f := r.resolve(stm.dict["Filter"]).(type) {
+	case nil:
+		return stm.data, nil
+	case pdfName:
+		filters = pdfArray{f}
+	case pdfArray:
+		filters = f
+	}
+	if len(filters) == 0 {
+		return stm.data, nil
+	}
+	if len(filters) > 1 || filters[0] != pdfName("FlateDecode") {
+		return nil, fmt.Errorf("pdf: unsupported filter %v", filters)
+	}
+
+	zr, err := zlib.NewReader(bytes.NewReader(stm.data))
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil && len(data) == 0 {
+		return nil, ErrMalformed
+	}
+
+	var parms pdfDict
+	switch dp := r.resolve(stm.dict["DecodeParms"]).(type) {
+	case pdfDict:
+		parms = dp
+	case pdfArray:
+		if len(dp) > 0 {
+			parms, _ = r.resolve(dp[0]).(pdfDict)
+		}
+	}
+	return unpredict(data, parms)
+}
+
+// unpredict reverses PNG row predictors (Predictor >= 10).
+func unpredict(data []byte, parms pdfDict) ([]byte, error) {
+	predictor, _ := parms["Predictor"].(int64)
+	if predictor < 10 {
+		if predictor > 1 {
+			return nil, fmt.Errorf("pdf: unsupported predictor %d", predictor)
+		}
+		return data, nil
+	}
+
+	colors, bpc, columns := int64(1), int64(8), int64(1)
+	if v, ok := parms["Colors"].(int64); ok {
+		colors = v
+	}
+	if v, ok := parms["BitsPerComponent"].(int64); ok {
+		bpc = v
+	}
+	if v, ok := parms["Columns"].(int64); ok {
+		columns = v
+	}
+	bpp := int(max((colors*bpc+7)/8, 1))
+	rowLen := int((colors*bpc*columns + 7) / 8)
+
+	out := make([]byte, 0, len(data))
+	prev := make([]byte, rowLen)
+	for pos := 0; pos+rowLen+1 <= len(data); pos += rowLen + 1 {
+		kind := data[pos]
+		row := append([]byte(nil), data[pos+1:pos+1+rowLen]...)
+		for i := range row {
+			var left, upLeft byte
+			if i >= bpp {
+				left, upLeft = row[i-bpp], prev[i-bpp]
+			}
+			up := prev[i]
+			switch kind {
+			case 1:
+				row[i] += left
+			case 2:
+				row[i] += up
+			case 3:
+				row[i] += byte((int(left) + int(up)) / 2)
+			case 4:
+				row[i] += paeth(left, up, upLeft)
+			}
+		}
+		out = append(out, row...)
+		prev = row
+	}
+	return out, nil
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	switch {
+	case pa <= pb && pa <= pc:
+		return a
+	case pb <= pc:
+		return b
+	}
+	return c
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// lexer parses PDF objects from a byte slice.
+type lexer struct {
+	buf []byte
+	pos int
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isDelimiter(c byte) bool {
+	return bytes.IndexByte([]byte("()<>[]{}/%"), c) >= 0
+}
+
+func (p *lexer) skipSpace() {
+	for p.pos < len(p.buf) {
+		c := p.buf[p.pos]
+		if c == '%' {
+			for p.pos < len(p.buf) && p.buf[p.pos] != '\n' && p.buf[p.pos] != '\r' {
+				p.pos++
+			}
+			continue
+		}
+		if !isSpace(c) {
+			return
+		}
+		p.pos++
+	}
+}
+
+// token reads a run of regular characters.
+func (p *lexer) token() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.buf) && !isSpace(p.buf[p.pos]) && !isDelimiter(p.buf[p.pos]) {
+		p.pos++
+	}
+	return string(p.buf[start:p.pos])
+}
+
+func (p *lexer) keyword() string {
+	return p.token()
+}
+
+func (p *lexer) integer() (int64, error) {
+	return strconv.ParseInt(p.token(), 10, 64)
+}
+
+func (p *lexer) object() (any, error) {
+	p.skipSpace()
+	if p.pos >= len(p.buf) {
+		return nil, ErrMalformed
+	}
+
+	switch c := p.buf[p.pos]; {
+	case c == '/':
+		p.pos++
+		return p.name(), nil
+	case c == '<' && p.pos+1 < len(p.buf) && p.buf[p.pos+1] == '<':
+		p.pos += 2
+		return p.dict()
+	case c == '<':
+		p.pos++
+		return p.hexString()
+	case c == '[':
+		p.pos++
+		return p.array()
+	case c == '(':
+		p.pos++
+		return p.literalString()
+	case c == '+' || c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		return p.number()
+	}
+
+	switch tok := p.token(); tok {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	default:
+		return nil, ErrMalformed
+	}
+}
+
+func (p *lexer) name() pdfName {
+	var b []byte
+	for p.pos < len(p.buf) && !isSpace(p.buf[p.pos]) && !isDelimiter(p.buf[p.pos]) {
+		c := p.buf[p.pos]
+		if c == '#' && p.pos+2 < len(p.buf) {
+			if v, err := strconv.ParseUint(string(p.buf[p.pos+1:p.pos+3]), 16, 8); err == nil {
+				b = append(b, byte(v))
+				p.pos += 3
+				continue
+			}
+		}
+		b = append(b, c)
+		p.pos++
+	}
+	return pdfName(b)
+}
+
+func (p *lexer) dict() (pdfDict, error) {
+	d := pdfDict{}
+	for {
+		p.skipSpace()
+		if p.pos+1 < len(p.buf) && p.buf[p.pos] == '>' && p.buf[p.pos+1] == '>' {
+			p.pos += 2
+			return d, nil
+		}
+		if p.pos >= len(p.buf) || p.buf[p.pos] != '/' {
+			return nil, ErrMalformed
+		}
+		p.pos++
+		key := p.name()
+		val, err := p.object()
+		if err != nil {
+			return nil, err
+		}
+		d[string(key)] = val
+	}
+}
+
+func (p *lexer) array() (pdfArray, error) {
+	a := pdfArray{}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.buf) {
+			return nil, ErrMalformed
+		}
+		if p.buf[p.pos] == ']' {
+			p.pos++
+			return a, nil
+		}
+		v, err := p.object()
+		if err != nil {
+			return nil, err
+		}
+		a = append(a, v)
+	}
+}
+
+func (p *lexer) hexString() (pdfString, error) {
+	var digits []byte
+	for p.pos < len(p.buf) && p.buf[p.pos] != '>' {
+		if !isSpace(p.buf[p.pos]) {
+			digits = append(digits, p.buf[p.pos])
+		}
+		p.pos++
+	}
+	p.pos++
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	for i := range out {
+		v, err := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		if err != nil {
+			return nil, ErrMalformed
+		}
+		out[i] = byte(v)
+	}
+	return out, nil
+}
+
+func (p *lexer) literalString() (pdfString, error) {
+	var out []byte
+	depth := 1
+	for p.pos < len(p.buf) {
+		c := p.buf[p.pos]
+		p.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return out, nil
+			}
+		case '\\':
+			if p.pos >= len(p.buf) {
+				return nil, ErrMalformed
+			}
+			e := p.buf[p.pos]
+			p.pos++
+			switch e {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if p.pos < len(p.buf) && p.buf[p.pos] == '\n' {
+					p.pos++
+				}
+				continue
+			case '\n':
+				continue
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && p.pos < len(p.buf) && p.buf[p.pos] >= '0' && p.buf[p.pos] <= '7'; i++ {
+						v = v*8 + int(p.buf[p.pos]-'0')
+						p.pos++
+					}
+					c = byte(v)
+				} else {
+					c = e
+				}
+			}
+		}
+		out = append(out, c)
+	}
+	return nil, ErrMalformed
+}
+
+// number parses an integer or real, recognising "num gen R" references.
+func (p *lexer) number() (any, error) {
+	tok := p.token()
+	if n, err := strconv.ParseInt(tok, 10, 64); err == nil {
+		save := p.pos
+		if gen, err := strconv.ParseInt(p.token(), 10, 64); err == nil && p.token() == "R" {
+			return pdfRef{int(n), int(gen)}, nil
+		}
+		p.pos = save
+		return n, nil
+	}
+	f, err := strconv.ParseFloat(tok, 64)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	return f, nil
+}
@@ -0,0 +1,344 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// stampFontName is the resource name under which the stamp's font is added to each page.
+const stampFontName = "StanStampF1"
+
+// Stamp returns a copy of src with text drawn in a footer band on every page.
+//
+// The original bytes are left untouched and the stamp is appended as an incremental
+// update, so fonts, images and compression in the source document are preserved.
+// Encrypted documents return ErrEncrypted.
+func Stamp(src []byte, text string, color Color) ([]byte, error) {
+	r, err := newReader(src)
+	if err != nil {
+		return nil, err
+	}
+	pages, err := r.pages()
+	if err != nil {
+		return nil, err
+	}
+	if len(pages) == 0 {
+		return nil, ErrMalformed
+	}
+
+	size, _ := r.trailer["Size"].(int64)
+	for num := range r.xref {
+		if int64(num) >= size {
+			size = int64(num) + 1
+		}
+	}
+
+	u := &update{buf: bytes.NewBuffer(append([]byte(nil), src...)), next: int(size), offsets: map[int]int64{}}
+	if !bytes.HasSuffix(src, []byte("\n")) {
+		u.buf.WriteByte('\n')
+	}
+
+	fontRef := u.add(pdfDict{
+		"Type": pdfName("Font"), "Subtype": pdfName("Type1"),
+		"BaseFont": pdfName(Helvetica), "Encoding": pdfName("WinAnsiEncoding"),
+	})
+	saveRef := u.addStream([]byte("q\n"))
+
+	for _, pg := range pages {
+		contents := pdfArray{}
+		switch c := r.resolve(pg.dict["Contents"]).(type) {
+		case *pdfStream:
+			contents = append(contents, pg.dict["Contents"])
+		case pdfArray:
+			contents = append(contents, c...)
+		}
+
+		var stamp bytes.Buffer
+		if len(contents) > 0 {
+			// Restore the graphics state the page content may have changed.
+			stamp.WriteString("Q\n")
+			contents = append(pdfArray{saveRef}, contents...)
+		}
+		writeFooter(&stamp, pg.mediaBox, text, color)
+		contents = append(contents, u.addStream(stamp.Bytes()))
+
+		page := pdfDict{}
+		for k, v := range pg.dict {
+			page[k] = v
+		}
+		page["Contents"] = contents
+		page["Resources"] = withFont(r, pg.resources, fontRef)
+		u.set(pg.ref, page)
+	}
+
+	return u.finish(r)
+}
+
+// writeFooter draws text centred in a band along the bottom edge of the page.
+func writeFooter(w *bytes.Buffer, box [4]float64, text string, color Color) {
+	pageWidth := box[2] - box[0]
+	size := 8.0
+	if width := TextWidth(Helvetica, size, text); width > pageWidth*0.92 {
+		size *= pageWidth * 0.92 / width
+	}
+	width := TextWidth(Helvetica, size, text)
+	x := box[0] + (pageWidth-width)/2
+	y := box[1] + 10
+
+	fmt.Fprintf(w, "q 1 1 1 rg %.2f %.2f %.2f %.2f re f ", x-6, y-4, width+12, size+7)
+	fmt.Fprintf(w, "BT /%s %.2f Tf %.3f %.3f %.3f rg %.2f %.2f Td (%s) Tj ET Q\n",
+		stampFontName, size, color.R, color.G, color.B, x, y, escape(text))
+}
+
+// withFont copies a page's resource dictionary and adds the stamp font to it.
+func withFont(r *reader, resources any, fontRef pdfRef) pdfDict {
+	res := pdfDict{}
+	if d, ok := r.resolve(resources).(pdfDict); ok {
+		for k, v := range d {
+			res[k] = v
+		}
+	}
+	fonts := pdfDict{}
+	if d, ok := r.resolve(res["Font"]).(pdfDict); ok {
+		for k, v := range d {
+			fonts[k] = v
+		}
+	}
+	fonts[stampFontName] = fontRef
+	res["Font"] = fonts
+	return res
+}
+
+type pageInfo struct {
+	ref       pdfRef
+	dict      pdfDict
+	resources any
+	mediaBox  [4]float64
+}
+
+// pages walks the page tree, resolving inherited resources and media boxes.
+func (r *reader) pages() ([]pageInfo, error) {
+	catalog, ok := r.resolve(r.trailer["Root"]).(pdfDict)
+	if !ok {
+		return nil, ErrMalformed
+	}
+	root, ok := catalog["Pages"].(pdfRef)
+	if !ok {
+		return nil, ErrMalformed
+	}
+
+	var out []pageInfo
+	seen := map[int]bool{}
+	var walk func(ref pdfRef, resources any, box [4]float64, depth int) error
+	walk = func(ref pdfRef, resources any, box [4]float64, depth int) error {
+		if seen[ref.num] || depth > 64 {
+			return ErrMalformed
+		}
+		seen[ref.num] = true
+
+		node, ok := r.resolve(ref).(pdfDict)
+		if !ok {
+			return ErrMalformed
+		}
+		if res, ok := node["Resources"]; ok {
+			resources = res
+		}
+		if mb, ok := r.resolve(node["MediaBox"]).(pdfArray); ok && len(mb) == 4 {
+			for i, v := range mb {
+				box[i] = toFloat(r.resolve(v))
+			}
+		}
+
+		if node["Type"] == pdfName("Page") {
+			out = append(out, pageInfo{ref: ref, dict: node, resources: resources, mediaBox: box})
+			return nil
+		}
+		kids, _ := r.resolve(node["Kids"]).(pdfArray)
+		for _, kid := range kids {
+			kidRef, ok := kid.(pdfRef)
+			if !ok {
+				return ErrMalformed
+			}
+			if err := walk(kidRef, resources, box, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	letter := [4]float64{0, 0, 612, 792}
+	if err := walk(root, nil, letter, 0); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func toFloat(v any) float64 {
+	switch n := v.(type) {
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	}
+	return 0
+}
+
+// update accumulates objects appended to a document as an incremental update.
+type update struct {
+	buf     *bytes.Buffer
+	next    int
+	offsets map[int]int64
+	gens    map[int]int
+}
+
+func (u *update) add(obj any) pdfRef {
+	ref := pdfRef{num: u.next}
+	u.next++
+	u.set(ref, obj)
+	return ref
+}
+
+func (u *update) addStream(data []byte) pdfRef {
+	return u.add(&pdfStream{dict: pdfDict{"Length": int64(len(data))}, data: data})
+}
+
+// set writes obj as the new revision of ref.
+func (u *update) set(ref pdfRef, obj any) {
+	if u.gens == nil {
+		u.gens = map[int]int{}
+	}
+	u.offsets[ref.num] = int64(u.buf.Len())
+	u.gens[ref.num] = ref.gen
+
+	fmt.Fprintf(u.buf, "%d %d obj\n", ref.num, ref.gen)
+	if stm, ok := obj.(*pdfStream); ok {
+		writeValue(u.buf, stm.dict)
+		u.buf.WriteString("\nstream\n")
+		u.buf.Write(stm.data)
+		u.buf.WriteString("\nendstream")
+	} else {
+		writeValue(u.buf, obj)
+	}
+	u.buf.WriteString("\nendobj\n")
+}
+
+// finish writes the cross-reference section in the same style as the source document.
+func (u *update) finish(r *reader) ([]byte, error) {
+	trailer := pdfDict{"Root": r.trailer["Root"], "Prev": r.startxref}
+	for _, key := range []string{"Info", "ID"} {
+		if v, ok := r.trailer[key]; ok {
+			trailer[key] = v
+		}
+	}
+
+	if r.usesXRefStream {
+		xrefRef := pdfRef{num: u.next}
+		u.next++
+		u.offsets[xrefRef.num] = int64(u.buf.Len())
+		u.gens[xrefRef.num] = 0
+
+		nums := u.sortedNums()
+		var index pdfArray
+		var data bytes.Buffer
+		for _, num := range nums {
+			index = append(index, int64(num), int64(1))
+			data.WriteByte(1)
+			binary.Write(&data, binary.BigEndian, uint32(u.offsets[num]))
+			binary.Write(&data, binary.BigEndian, uint16(u.gens[num]))
+		}
+
+		trailer["Type"] = pdfName("XRef")
+		trailer["Size"] = int64(u.next)
+		trailer["W"] = pdfArray{int64(1), int64(4), int64(2)}
+		trailer["Index"] = index
+		trailer["Length"] = int64(data.Len())
+
+		fmt.Fprintf(u.buf, "%d 0 obj\n", xrefRef.num)
+		writeValue(u.buf, trailer)
+		u.buf.WriteString("\nstream\n")
+		u.buf.Write(data.Bytes())
+		u.buf.WriteString("\nendstream\nendobj\n")
+		fmt.Fprintf(u.buf, "startxref\n%d\n%%%%EOF\n", u.offsets[xrefRef.num])
+		return u.buf.Bytes(), nil
+	}
+
+	xref := u.buf.Len()
+	u.buf.WriteString("xref\n")
+	for _, num := range u.sortedNums() {
+		fmt.Fprintf(u.buf, "%d 1\n%010d %05d n \n", num, u.offsets[num], u.gens[num])
+	}
+	trailer["Size"] = int64(u.next)
+	u.buf.WriteString("trailer\n")
+	writeValue(u.buf, trailer)
+	fmt.Fprintf(u.buf, "\nstartxref\n%d\n%%%%EOF\n", xref)
+	return u.buf.Bytes(), nil
+}
+
+func (u *update) sortedNums() []int {
+	nums := make([]int, 0, len(u.offsets))
+	for num := range u.offsets {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	return nums
+}
+
+// writeValue serialises a PDF object.
+func writeValue(w *bytes.Buffer, v any) {
+	switch v := v.(type) {
+	case nil:
+		w.WriteString("null")
+	case bool:
+		w.WriteString(strconv.FormatBool(v))
+	case int64:
+		w.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		w.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+	case pdfName:
+		writeName(w, string(v))
+	case pdfString:
+		fmt.Fprintf(w, "<%x>", []byte(v))
+	case pdfRef:
+		fmt.Fprintf(w, "%d %d R", v.num, v.gen)
+	case pdfArray:
+		w.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				w.WriteByte(' ')
+			}
+			writeValue(w, item)
+		}
+		w.WriteByte(']')
+	case pdfDict:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		w.WriteString("<<")
+		for _, k := range keys {
+			writeName(w, k)
+			w.WriteByte(' ')
+			writeValue(w, v[k])
+			w.WriteByte(' ')
+		}
+		w.WriteString(">>")
+	default:
+		w.WriteString("null")
+	}
+}
+
+func writeName(w *bytes.Buffer, s string) {
+	w.WriteByte('/')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 0x21 || c > 0x7e || c == '#' || isDelimiter(c) {
+			fmt.Fprintf(w, "#%02X", c)
+			continue
+		}
+		w.WriteByte(c)
+	}
+}
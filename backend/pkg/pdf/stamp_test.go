@@ -0,0 +1,176 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// fixture builds a one-page PDF the way other producers do: a compressed content array,
+// fonts and media box inherited from the page tree and, with xrefStream, the page object
+// packed into an object stream behind a cross-reference stream.
+func fixture(t *testing.T, xrefStream bool) []byte {
+	t.Helper()
+	var content bytes.Buffer
+	zw := zlib.NewWriter(&content)
+	zw.Write([]byte("q 2 0 0 2 0 0 cm BT /F9 12 Tf 10 10 Td (Original) Tj ET"))
+	zw.Close()
+
+	page := "<< /Type /Page /Parent 2 0 R /Contents [4 0 R] >>"
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /MediaBox [0 0 300 400] /Resources << /Font << /F9 5 0 R >> >> >>",
+		page,
+		fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", content.Len(), content.Bytes()),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>",
+	}
+	if xrefStream {
+		header := "3 0 "
+		objects[2] = "null"
+		objects = append(objects, fmt.Sprintf("<< /Type /ObjStm /N 1 /First %d /Length %d >>\nstream\n%s%s\nendstream",
+			len(header), len(header)+len(page), header, page))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.5\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	if !xrefStream {
+		xref := buf.Len()
+		fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+		for _, off := range offsets {
+			fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+		}
+		fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+		return buf.Bytes()
+	}
+
+	xrefNum := len(objects) + 1
+	xref := buf.Len()
+	var rows bytes.Buffer
+	row := func(typ byte, f2 uint32, f3 uint16) {
+		rows.WriteByte(typ)
+		binary.Write(&rows, binary.BigEndian, f2)
+		binary.Write(&rows, binary.BigEndian, f3)
+	}
+	row(0, 0, 65535)
+	for i, off := range offsets {
+		if i == 2 {
+			row(2, uint32(len(objects)), 0) // Page 3 is the first object in the object stream
+			continue
+		}
+		row(1, uint32(off), 0)
+	}
+	row(1, uint32(xref), 0)
+	fmt.Fprintf(&buf, "%d 0 obj\n<< /Type /XRef /Size %d /W [1 4 2] /Root 1 0 R /Length %d >>\nstream\n",
+		xrefNum, xrefNum+1, rows.Len())
+	buf.Write(rows.Bytes())
+	fmt.Fprintf(&buf, "\nendstream\nendobj\nstartxref\n%d\n%%%%EOF\n", xref)
+	return buf.Bytes()
+}
+
+func TestStamp(t *testing.T) {
+	generated := New(A4Width, A4Height)
+	generated.AddPage().Text(50, 60, Helvetica, 12, Black, "Chapter one")
+	generated.AddPage().Text(50, 60, Helvetica, 12, Black, "Chapter two")
+
+	tests := []struct {
+		name    string
+		src     []byte
+		pages   int
+		box     [4]float64
+		content string
+	}{
+		{"generated document", generated.Bytes(), 2, [4]float64{0, 0, A4Width, A4Height}, "(Chapter one) Tj"},
+		{"xref table with inherited resources", fixture(t, false), 1, [4]float64{0, 0, 300, 400}, "(Original) Tj"},
+		{"xref and object streams", fixture(t, true), 1, [4]float64{0, 0, 300, 400}, "(Original) Tj"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := Stamp(tt.src, "Licensed to Priya (priya@example.com)", Black)
+			if err != nil {
+				t.Fatalf("Stamp: %v", err)
+			}
+			if !bytes.HasPrefix(out, tt.src) {
+				t.Fatal("stamping changed the original bytes")
+			}
+
+			// Stamping an already stamped copy chains a second update onto the first
+			out, err = Stamp(out, "Second stamp", Black)
+			if err != nil {
+				t.Fatalf("second Stamp: %v", err)
+			}
+
+			r, err := newReader(out)
+			if err != nil {
+				t.Fatalf("newReader: %v", err)
+			}
+			pages, err := r.pages()
+			if err != nil {
+				t.Fatalf("pages: %v", err)
+			}
+			if len(pages) != tt.pages {
+				t.Fatalf("got %d pages, want %d", len(pages), tt.pages)
+			}
+
+			for i, pg := range pages {
+				if pg.mediaBox != tt.box {
+					t.Errorf("page %d media box = %v, want %v", i, pg.mediaBox, tt.box)
+				}
+				fonts, _ := r.resolve(r.resolve(pg.resources).(pdfDict)["Font"]).(pdfDict)
+				if _, ok := fonts[stampFontName]; !ok {
+					t.Errorf("page %d is missing the stamp font", i)
+				}
+				if len(fonts) < 2 {
+					t.Errorf("page %d lost its own fonts: %v", i, fonts)
+				}
+
+				got := pageContent(t, r, pg)
+				for _, want := range []string{`(Licensed to Priya \(priya@example.com\)) Tj`, "(Second stamp) Tj"} {
+					if !strings.Contains(got, want) {
+						t.Errorf("page %d content is missing %q", i, want)
+					}
+				}
+				if i == 0 && !strings.Contains(got, tt.content) {
+					t.Errorf("page %d lost its original content %q", i, tt.content)
+				}
+				// Each stamp restores the graphics state the page saved before it
+				if strings.Count(got, "q\n") < 2 || strings.Count(got, "Q\n") < 2 {
+					t.Errorf("page %d content is not wrapped in q/Q: %q", i, got)
+				}
+			}
+		})
+	}
+}
+
+func TestStamp_Errors(t *testing.T) {
+	src := fixture(t, false)
+	encrypted := bytes.Replace(src, []byte("/Root 1 0 R >>\nstartxref"), []byte("/Root 1 0 R /Encrypt 9 0 R >>\nstartxref"), 1)
+
+	tests := []struct {
+		name string
+		src  []byte
+		want error
+	}{
+		{"empty", nil, ErrMalformed},
+		{"not a PDF", []byte("hello world"), ErrMalformed},
+		{"truncated", src[:len(src)/2], ErrMalformed},
+		{"startxref out of range", append(bytes.Clone(src[:bytes.LastIndex(src, []byte("startxref"))]), "startxref\n999999\n%%EOF\n"...), ErrMalformed},
+		{"encrypted", encrypted, ErrEncrypted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Stamp(tt.src, "Licensed", Black); !errors.Is(err, tt.want) {
+				t.Errorf("Stamp error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package pdf
+
+import (
+	"strings"
+	"unicode"
+)
+
+// The base fonts only cover WinAnsi, and embedding fonts for Indian scripts would also need
+// glyph shaping, so text is transliterated before it is drawn: accented Latin letters lose
+// their accents and the Indian scripts are romanised. Anything else, such as CJK or Arabic,
+// still comes out as '?'; Representable reports whether that will happen.
+
+// winAnsiExtra maps the characters WinAnsi places in 0x80..0x9F to their byte values.
+var winAnsiExtra = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88,
+	'‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91, '’': 0x92, '“': 0x93,
+	'”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B,
+	'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// Base letters for Latin Extended-A (U+0100..U+017F) and Latin Extended Additional
+// (U+1E00..U+1EFF), which covers IAST spellings such as "Kṛṣṇa". '?' marks no mapping.
+const (
+	latinExtA = "AaAaAaCcCcCcCcDdDdEeEeEeEeEeGgGgGgGgHhHhIiIiIiIiIi??JjKkkLlLlLlL" +
+		"lLlNnNnNn?NnOoOoOo??RrRrRrSsSsSsSsTtTtTtUuUuUuUuUuUuWwYyYZzZzZzs"
+	latinExtAdditional = "AaBbBbBbCcDdDdDdDdDdEeEeEeEeEeFfGgHhHhHhHhHhIiIiKkKkKkLlLlLlLlMm" +
+		"MmMmNnNnNnNnOoOoOoOoPpPpRrRrRrRrSsSsSsSsSsTtTtTtTtUuUuUuUuUuVvVv" +
+		"WwWwWwWwWwXxXxYyZzZzZzhtwy??????AaAaAaAaAaAaAaAaAaAaAaAaEeEeEeEe" +
+		"EeEeEeEeIiIiOoOoOoOoOoOoOoOoOoOoOoOoUuUuUuUuUuUuUuYyYyYyYy??????"
+)
+
+// Indian scripts share Devanagari's layout, each in its own 128-character block, so one
+// table of offsets romanises all of them.
+const (
+	indicFirst = 0x0900 // Devanagari
+	indicLast  = 0x0D7F // Malayalam
+
+	indicVirama = 0x4D // Suppresses the inherent vowel
+	indicNukta  = 0x3C
+)
+
+var (
+	indicVowels = map[rune]string{
+		0x05: "a", 0x06: "aa", 0x07: "i", 0x08: "ee", 0x09: "u", 0x0A: "oo", 0x0B: "ri",
+		0x0D: "e", 0x0E: "e", 0x0F: "e", 0x10: "ai", 0x11: "o", 0x12: "o", 0x13: "o", 0x14: "au",
+	}
+	indicConsonants = map[rune]string{
+		0x15: "k", 0x16: "kh", 0x17: "g", 0x18: "gh", 0x19: "n",
+		0x1A: "ch", 0x1B: "chh", 0x1C: "j", 0x1D: "jh", 0x1E: "n",
+		0x1F: "t", 0x20: "th", 0x21: "d", 0x22: "dh", 0x23: "n",
+		0x24: "t", 0x25: "th", 0x26: "d", 0x27: "dh", 0x28: "n", 0x29: "n",
+		0x2A: "p", 0x2B: "ph", 0x2C: "b", 0x2D: "bh", 0x2E: "m",
+		0x2F: "y", 0x30: "r", 0x31: "r", 0x32: "l", 0x33: "l", 0x34: "zh", 0x35: "v",
+		0x36: "sh", 0x37: "sh", 0x38: "s", 0x39: "h",
+	}
+	indicVowelSigns = map[rune]string{
+		0x3E: "a", 0x3F: "i", 0x40: "ee", 0x41: "u", 0x42: "oo", 0x43: "ri", 0x44: "ri",
+		0x45: "e", 0x46: "e", 0x47: "e", 0x48: "ai", 0x49: "o", 0x4A: "o", 0x4B: "o", 0x4C: "au",
+		0x57: "au",
+	}
+	indicSigns = map[rune]string{0x01: "n", 0x02: "n", 0x03: "h", 0x64: ".", 0x65: "."}
+)
+
+// Transliterate rewrites s using only characters the base fonts can draw where it knows how.
+// Words written in an Indian script are capitalised, as they are usually names.
+func Transliterate(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r >= indicFirst && r <= indicLast:
+			j := i
+			for j < len(runes) && (runes[j] >= indicFirst && runes[j] <= indicLast || isJoiner(runes[j])) {
+				j++
+			}
+			b.WriteString(romanise(runes[i:j]))
+			i = j - 1
+		case r >= 0x0100 && r <= 0x017F && latinExtA[r-0x0100] != '?':
+			b.WriteByte(latinExtA[r-0x0100])
+		case r >= 0x1E00 && r <= 0x1EFF && latinExtAdditional[r-0x1E00] != '?':
+			b.WriteByte(latinExtAdditional[r-0x1E00])
+		case r == 'Ĳ':
+			b.WriteString("IJ")
+		case r == 'ĳ':
+			b.WriteString("ij")
+		case isJoiner(r):
+			// Zero-width (non-)joiners only affect shaping
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// romanise transliterates a run of Indian script text.
+func romanise(run []rune) string {
+	var b strings.Builder
+	script := run[0] &^ 0x7F
+	// Hindi and its neighbours drop a word's final inherent vowel ("Ram", not "Rama")
+	dropFinal := script == 0x0900 || script == 0x0980 || script == 0x0A00 || script == 0x0A80
+
+	pending := false // A consonant was written and its inherent vowel hasn't been resolved
+	flush := func(final bool) {
+		if pending && !(final && dropFinal) {
+			b.WriteByte('a')
+		}
+		pending = false
+	}
+	for _, r := range run {
+		if isJoiner(r) {
+			continue
+		}
+		if r&^0x7F != script {
+			flush(true)
+			script = r &^ 0x7F
+		}
+		off := r - script
+		switch {
+		case off == indicVirama:
+			pending = false
+		case off == indicNukta:
+		case indicVowelSigns[off] != "":
+			pending = false
+			b.WriteString(indicVowelSigns[off])
+		case indicConsonants[off] != "":
+			flush(false)
+			b.WriteString(indicConsonants[off])
+			pending = true
+		case indicVowels[off] != "":
+			flush(false)
+			b.WriteString(indicVowels[off])
+		case off >= 0x66 && off <= 0x6F:
+			flush(true)
+			b.WriteByte(byte('0' + off - 0x66))
+		case indicSigns[off] != "":
+			// Anusvara and visarga follow the vowel
+			flush(off == 0x64 || off == 0x65)
+			b.WriteString(indicSigns[off])
+		}
+	}
+	flush(true)
+
+	word := []rune(b.String())
+	if len(word) > 0 {
+		word[0] = unicode.ToUpper(word[0])
+	}
+	return string(word)
+}
+
+// isJoiner reports whether r is a zero-width joiner or non-joiner, which can appear inside
+// words in the Indian scripts.
+func isJoiner(r rune) bool {
+	return r == '\u200C' || r == '\u200D'
+}
+
+// Representable reports whether s can be drawn without any character replaced by '?'.
+func Representable(s string) bool {
+	for _, r := range Transliterate(s) {
+		if _, ok := winAnsiByte(r); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// winAnsiByte returns the WinAnsi code for r.
+func winAnsiByte(r rune) (byte, bool) {
+	switch {
+	case r >= 32 && r < 127, r >= 160 && r <= 255:
+		return byte(r), true
+	}
+	c, ok := winAnsiExtra[r]
+	return c, ok
+}
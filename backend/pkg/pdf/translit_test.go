@@ -0,0 +1,56 @@
+package pdf
+
+import "testing"
+
+func TestTransliterate(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"ASCII", "Priya Sharma", "Priya Sharma"},
+		{"Latin-1 kept", "José Müller", "José Müller"},
+		{"Latin Extended-A", "Łukasz Dvořák", "Lukasz Dvorák"},
+		{"IAST", "Kṛṣṇa Dāsa", "Krsna Dasa"},
+		{"ligature", "Ĳssel", "IJssel"},
+		{"Devanagari", "प्रिया शर्मा", "Priya Sharma"},
+		{"Devanagari final consonant", "राम", "Ram"},
+		{"Devanagari anusvara", "संजय", "Sanjay"},
+		{"Tamil", "ரமேஷ் குமார்", "Ramesh Kumar"},
+		{"Bengali", "অমিত", "Amit"},
+		{"Devanagari digits", "१२३", "123"},
+		{"zero-width joiner", "क्‍ष", "Ksh"},
+		{"mixed", "Order for राहुल", "Order for Rahul"},
+		{"unsupported left alone", "李明", "李明"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Transliterate(tt.in); got != tt.want {
+				t.Errorf("Transliterate(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepresentable(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"", true},
+		{"Priya Sharma", true},
+		{"Zoë – “Café”", true},
+		{"Kṛṣṇa", true},
+		{"प्रिया", true},
+		{"മലയാളം", true},
+		{"李明", false},
+		{"محمد", false},
+		{"Ivan Иванов", false},
+		{"₹500", false},
+	}
+	for _, tt := range tests {
+		if got := Representable(tt.in); got != tt.want {
+			t.Errorf("Representable(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -62,18 +62,31 @@ func TestDownloadFlow(t *testing.T) {
 
 	var orderResp struct {
 		Data domain.Order `json:"data"`
+		Meta struct {
+			AccessToken string `json:"access_token"`
+		} `json:"meta"`
 	}
 	json.NewDecoder(resp.Body).Decode(&orderResp)
 	orderID := orderResp.Data.ID
+	assert.NotEmpty(t, orderResp.Meta.AccessToken)
 
 	// Mark as PAID directly in DB
 	orderColl := client.Database("stanstore_test").Collection("orders")
 	_, err = orderColl.UpdateOne(context.TODO(), bson.M{"_id": orderID}, bson.M{"$set": bson.M{"status": domain.OrderStatusPaid}})
 	assert.NoError(t, err)
 
-	// 3. Test Download Endpoint (Success)
+	// 3. Test Download Endpoint without the order link token (Forbidden)
 	req = httptest.NewRequest("GET", "/api/v1/orders/"+orderID.Hex()+"/download", nil)
 	resp, _ = app.Test(req)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	req = httptest.NewRequest("GET", "/api/v1/orders/"+orderID.Hex()+"/download?token=forged", nil)
+	resp, _ = app.Test(req)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	// 4. Test Download Endpoint (Success)
+	req = httptest.NewRequest("GET", "/api/v1/orders/"+orderID.Hex()+"/download?token="+orderResp.Meta.AccessToken, nil)
+	resp, _ = app.Test(req)
 	assert.Equal(t, http.StatusOK, resp.StatusCode)
 
 	var downloadResp struct {
@@ -85,17 +98,35 @@ func TestDownloadFlow(t *testing.T) {
 	assert.Contains(t, downloadResp.Data["download_url"], "creators/123/products/files/ebook.pdf")
 	assert.Contains(t, downloadResp.Data["download_url"], "signature=mock_download")
 
-	// 4. Test Download Endpoint (Not Paid) - create another order
+	// The public order no longer lists the files
+	req = httptest.NewRequest("GET", "/api/v1/orders/"+orderID.Hex(), nil)
+	resp, _ = app.Test(req)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	var publicOrderResp struct {
+		Meta map[string]interface{} `json:"meta"`
+	}
+	json.NewDecoder(resp.Body).Decode(&publicOrderResp)
+	assert.NotContains(t, publicOrderResp.Meta, "product_files")
+
+	// 5. Test Download Endpoint (Not Paid) - create another order
 	req = httptest.NewRequest("POST", "/api/v1/orders", bytes.NewReader(body))
 	req.Header.Set("Content-Type", "application/json")
 	resp, _ = app.Test(req)
 	var unpaidOrderResp struct {
 		Data domain.Order `json:"data"`
+		Meta struct {
+			AccessToken string `json:"access_token"`
+		} `json:"meta"`
 	}
 	json.NewDecoder(resp.Body).Decode(&unpaidOrderResp)
 	unpaidOrderID := unpaidOrderResp.Data.ID
 
-	req = httptest.NewRequest("GET", "/api/v1/orders/"+unpaidOrderID.Hex()+"/download", nil)
+	// A token for one order does not open another
+	req = httptest.NewRequest("GET", "/api/v1/orders/"+unpaidOrderID.Hex()+"/download?token="+orderResp.Meta.AccessToken, nil)
+	resp, _ = app.Test(req)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	req = httptest.NewRequest("GET", "/api/v1/orders/"+unpaidOrderID.Hex()+"/download?token="+unpaidOrderResp.Meta.AccessToken, nil)
 	resp, _ = app.Test(req)
 	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
 }
@@ -3,9 +3,11 @@ package integration
 import (
 	"bytes"
 	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -60,15 +62,35 @@ func (m *MockFileStorage) GeneratePresignedDownloadURL(ctx context.Context, key
 	return "https://mock-r2-bucket.r2.cloudflarestorage.com/" + key + "?signature=mock_download", nil
 }
 
+func (m *MockFileStorage) Upload(ctx context.Context, key string, contentType string, data []byte) error {
+	return nil
+}
+
+func (m *MockFileStorage) Head(ctx context.Context, key string) (*domain.FileInfo, error) {
+	return &domain.FileInfo{Key: key, Size: 1024, ContentType: "application/octet-stream"}, nil
+}
+
+func (m *MockFileStorage) Download(ctx context.Context, key string) ([]byte, error) {
+	return nil, nil
+}
+
+func (m *MockFileStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader("")), nil
+}
+
 func (m *MockFileStorage) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+func (m *MockFileStorage) List(ctx context.Context, prefix string) ([]domain.FileInfo, error) {
+	return nil, nil
+}
+
 type MockEmailService struct {
 	Called bool
 }
 
-func (m *MockEmailService) SendOrderConfirmation(ctx context.Context, order *domain.Order, product *domain.Product, downloadURL string) error {
+func (m *MockEmailService) SendOrderConfirmation(ctx context.Context, order *domain.Order, product *domain.Product, downloadURL string, attachments ...domain.EmailAttachment) error {
 	m.Called = true
 	return nil
 }
@@ -166,6 +188,9 @@ func setupTestApp(t *testing.T) (*fiber.App, func()) {
 		nil, // emailQueueRepo
 		nil, // affiliateSvc
 	)
+	downloadService := services.NewDownloadService(storage.NewMongoDownloadEntitlementRepository(testStorageDB), storage.NewMongoDownloadLogRepository(testStorageDB),
+		productRepo, orderRepo, userRepo, &MockFileStorage{}, testJWTSecret, "http://localhost:8080")
+	orderService.SetDownloadService(downloadService)
 
 	testimonialRepo := storage.NewMongoTestimonialRepository(testStorageDB.Database)
 	testimonialService := services.NewTestimonialService(testimonialRepo, productRepo, cache)
@@ -8,9 +8,13 @@ import OnboardingPage from './pages/onboarding/OnboardingPage';
 import StorePage from './pages/StorePage';
 import AffiliateRegistrationPage from './pages/storefront/AffiliateRegistrationPage';
 import CustomerOrderPage from './pages/OrderPage';
+import CertificateVerifyPage from './pages/CertificateVerifyPage';
+import TeamAcceptPage from './pages/TeamAcceptPage';
 import BuyerAuthPage from './pages/buyer/BuyerAuthPage';
 import LoginPage from './pages/auth/LoginPage';
 import VerifyOTPPage from './pages/auth/VerifyOTPPage';
+import ForgotPasswordPage from './pages/auth/ForgotPasswordPage';
+import ResetPasswordPage from './pages/auth/ResetPasswordPage';
 import LandingPage from './pages/LandingPage';
 import PricingPage from './pages/PricingPage';
 import AboutPage from './pages/AboutPage';
@@ -84,12 +88,16 @@ function AnimatedRoutes() {
             <Route path="/store/:username" element={<StorePage />} />
             <Route path="/store/:username/affiliate" element={<AffiliateRegistrationPage />} />
             <Route path="/order/:orderId" element={<CustomerOrderPage />} />
+            <Route path="/certificates/:verificationId" element={<CertificateVerifyPage />} />
           </Route>
 
           {/* Auth pages (standalone, no header/footer) */}
           <Route path="/login" element={<LoginPage />} />
           <Route path="/verify-otp" element={<VerifyOTPPage />} />
+          <Route path="/forgot-password" element={<ForgotPasswordPage />} />
+          <Route path="/reset-password" element={<ResetPasswordPage />} />
           <Route path="/buyer/login" element={<BuyerAuthPage />} />
+          <Route path="/team/accept" element={<TeamAcceptPage />} />
           <Route path="/my-purchases" element={<MyPurchasesPage />} />
           <Route path="/course-player/:productId" element={<CoursePlayer />} />
 
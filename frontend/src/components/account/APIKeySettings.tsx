@@ -0,0 +1,173 @@
+import { useEffect, useState } from 'react';
+import { api, ApiError } from '../../lib/api';
+
+interface APIKey {
+    id: string;
+    name: string;
+    prefix: string;
+    scopes: string[];
+    rate_limit: number;
+    last_used_at?: string;
+    revoked_at?: string;
+    created_at: string;
+}
+
+const SCOPES = [
+    { value: 'products:read', label: 'Read products' },
+    { value: 'products:write', label: 'Edit products' },
+    { value: 'orders:read', label: 'Read orders' },
+    { value: 'subscribers:read', label: 'Read subscribers' },
+    { value: 'subscribers:write', label: 'Edit subscribers' },
+    { value: 'coupons:read', label: 'Read coupons' },
+    { value: 'coupons:write', label: 'Manage coupons' },
+];
+
+/**
+ * Creator API keys for scripts and integrations. A new key's secret is shown once. Creating a
+ * key asks the creator to confirm their identity first.
+ */
+export default function APIKeySettings() {
+    const [keys, setKeys] = useState<APIKey[]>([]);
+    const [name, setName] = useState('');
+    const [scopes, setScopes] = useState<string[]>([]);
+    const [secret, setSecret] = useState<string | null>(null);
+    const [password, setPassword] = useState('');
+    const [confirming, setConfirming] = useState(false);
+    const [busy, setBusy] = useState(false);
+    const [error, setError] = useState<string | null>(null);
+
+    const loadKeys = async () => {
+        try {
+            const res = await api.get<APIKey[]>('/creator/api-keys');
+            setKeys(res.data || []);
+        } catch {
+            // Leave the list as it was
+        }
+    };
+
+    useEffect(() => {
+        loadKeys();
+    }, []);
+
+    const toggleScope = (scope: string) => {
+        setScopes(prev => prev.includes(scope) ? prev.filter(s => s !== scope) : [...prev, scope]);
+    };
+
+    const createKey = async (verify: boolean) => {
+        setBusy(true);
+        setError(null);
+        try {
+            if (verify) {
+                await api.post('/auth/step-up', { password });
+            }
+            const res = await api.post<{ key: APIKey; secret: string }>('/creator/api-keys', { name, scopes });
+            setSecret(res.data?.secret || null);
+            setName('');
+            setScopes([]);
+            setPassword('');
+            setConfirming(false);
+            await loadKeys();
+        } catch (err) {
+            if (err instanceof ApiError && err.code === 'ERR_STEP_UP_REQUIRED') {
+                setConfirming(true);
+            } else {
+                setError(err instanceof ApiError ? err.message : 'Failed to create API key');
+            }
+        } finally {
+            setBusy(false);
+        }
+    };
+
+    const revokeKey = async (id: string) => {
+        if (!confirm('Revoke this key? Anything using it will stop working immediately.')) return;
+        try {
+            await api.delete(`/creator/api-keys/${id}`);
+            await loadKeys();
+        } catch (err) {
+            setError(err instanceof ApiError ? err.message : 'Failed to revoke API key');
+        }
+    };
+
+    return (
+        <div className="bg-white dark:bg-[#1e2135] p-6 rounded-xl shadow-sm border border-gray-200 dark:border-white/10 space-y-6">
+            <div>
+                <h2 className="text-lg font-semibold text-gray-900 dark:text-white">API keys</h2>
+                <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+                    Use keys to manage products, orders, subscribers and coupons from your own scripts. Send them as <code>Authorization: Bearer &lt;key&gt;</code>.
+                </p>
+            </div>
+
+            {error && <div className="bg-red-50 text-red-700 p-3 rounded-md text-sm">{error}</div>}
+            {secret && (
+                <div className="bg-green-50 p-3 rounded-md text-sm text-green-800 space-y-1">
+                    <p>Copy your new key now. You won't be able to see it again.</p>
+                    <code className="block break-all font-mono text-xs">{secret}</code>
+                </div>
+            )}
+
+            <div className="space-y-3">
+                <input
+                    type="text"
+                    value={name}
+                    onChange={e => setName(e.target.value)}
+                    placeholder="Key name, e.g. Zapier"
+                    className="w-full px-3 py-2 text-sm rounded-md border border-gray-300 dark:border-white/10 dark:bg-transparent dark:text-white"
+                />
+                <div className="flex flex-wrap gap-3">
+                    {SCOPES.map(scope => (
+                        <label key={scope.value} className="flex items-center gap-2 text-sm text-gray-700 dark:text-gray-300">
+                            <input type="checkbox" checked={scopes.includes(scope.value)} onChange={() => toggleScope(scope.value)} />
+                            {scope.label}
+                        </label>
+                    ))}
+                </div>
+                {confirming && (
+                    <input
+                        type="password"
+                        value={password}
+                        onChange={e => setPassword(e.target.value)}
+                        placeholder="Confirm your password"
+                        className="w-full px-3 py-2 text-sm rounded-md border border-gray-300 dark:border-white/10 dark:bg-transparent dark:text-white"
+                    />
+                )}
+                <button
+                    type="button"
+                    onClick={() => createKey(confirming)}
+                    disabled={busy || !name || scopes.length === 0 || (confirming && !password)}
+                    className="px-4 py-2 text-sm font-medium rounded-md text-white bg-[#6786f5] hover:bg-[#5570e0] disabled:opacity-50 transition"
+                >
+                    Create key
+                </button>
+            </div>
+
+            {keys.length > 0 && (
+                <ul className="divide-y divide-gray-100 dark:divide-white/10">
+                    {keys.map(key => (
+                        <li key={key.id} className="py-3 flex items-center justify-between gap-3">
+                            <div className="min-w-0">
+                                <p className="text-sm font-medium text-gray-900 dark:text-white">
+                                    {key.name} <span className="font-mono text-xs text-gray-500">{key.prefix}…</span>
+                                </p>
+                                <p className="text-xs text-gray-500 dark:text-gray-400">
+                                    {key.scopes.join(', ')} · {key.rate_limit}/min ·{' '}
+                                    {key.last_used_at ? `last used ${new Date(key.last_used_at).toLocaleString()}` : 'never used'}
+                                </p>
+                            </div>
+                            {key.revoked_at ? (
+                                <span className="text-xs text-gray-400">Revoked</span>
+                            ) : (
+                                <button
+                                    type="button"
+                                    onClick={() => revokeKey(key.id)}
+                                    className="text-sm text-red-600 hover:text-red-700"
+                                >
+                                    Revoke
+                                </button>
+                            )}
+                        </li>
+                    ))}
+                </ul>
+            )}
+        </div>
+    );
+}
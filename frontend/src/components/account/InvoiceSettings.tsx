@@ -0,0 +1,168 @@
+import { useEffect, useState } from 'react';
+import { api, ApiError } from '../../lib/api';
+
+const API_BASE_URL = import.meta.env.VITE_API_BASE_URL || '/api/v1';
+
+interface Settings {
+    legal_name: string;
+    gstin?: string;
+    address_line1: string;
+    address_line2?: string;
+    city: string;
+    postal_code: string;
+    state_code: string;
+    tax_rate: number;
+    prices_include_tax: boolean;
+    invoice_prefix: string;
+    sac_code: string;
+}
+
+interface TaxSummary {
+    month: string;
+    invoices: number;
+    taxable_amount: number;
+    cgst: number;
+    sgst: number;
+    igst: number;
+    total_tax: number;
+    total: number;
+}
+
+const inputClass = 'w-full px-3 py-2 text-sm rounded-md border border-gray-300 dark:border-white/10 dark:bg-transparent dark:text-white';
+
+const formatRupees = (paise: number) => `₹${(paise / 100).toFixed(2)}`;
+
+/**
+ * Business details printed on buyers' invoices, the GST charged at checkout, and a monthly
+ * tax summary for filing returns. Without a GSTIN invoices are issued as bills of supply.
+ */
+export default function InvoiceSettings() {
+    const [settings, setSettings] = useState<Settings | null>(null);
+    const [states, setStates] = useState<Record<string, string>>({});
+    const [rates, setRates] = useState<number[]>([]);
+    const [month, setMonth] = useState(new Date().toISOString().slice(0, 7));
+    const [summary, setSummary] = useState<TaxSummary | null>(null);
+    const [busy, setBusy] = useState(false);
+    const [saved, setSaved] = useState(false);
+    const [error, setError] = useState<string | null>(null);
+
+    useEffect(() => {
+        api.get<{ settings: Settings; states: Record<string, string>; rates: number[] }>('/creator/invoice-settings')
+            .then(res => {
+                if (!res.data) return;
+                setSettings(res.data.settings);
+                setStates(res.data.states);
+                setRates(res.data.rates);
+            })
+            .catch(() => {
+                // Section stays hidden
+            });
+    }, []);
+
+    useEffect(() => {
+        api.get<TaxSummary>(`/creator/tax-summary?month=${month}`)
+            .then(res => setSummary(res.data))
+            .catch(() => setSummary(null));
+    }, [month]);
+
+    if (!settings) return null;
+
+    const update = (changes: Partial<Settings>) => {
+        setSettings(prev => (prev ? { ...prev, ...changes } : prev));
+        setSaved(false);
+    };
+
+    const save = async () => {
+        setBusy(true);
+        setError(null);
+        try {
+            const res = await api.put<Settings>('/creator/invoice-settings', settings);
+            if (res.data) setSettings(res.data);
+            setSaved(true);
+        } catch (err) {
+            setError(err instanceof ApiError ? err.message : 'Failed to save invoice settings');
+        } finally {
+            setBusy(false);
+        }
+    };
+
+    return (
+        <div className="bg-white dark:bg-[#1e2135] p-6 rounded-xl shadow-sm border border-gray-200 dark:border-white/10 space-y-6">
+            <div>
+                <h2 className="text-lg font-semibold text-gray-900 dark:text-white">Invoices &amp; GST</h2>
+                <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+                    Buyers get a PDF invoice with their confirmation email. Add your GSTIN to charge GST and issue tax invoices.
+                </p>
+            </div>
+
+            {error && <div className="bg-red-50 text-red-700 p-3 rounded-md text-sm">{error}</div>}
+            {saved && <div className="bg-green-50 text-green-800 p-3 rounded-md text-sm">Invoice settings saved.</div>}
+
+            <div className="grid grid-cols-1 sm:grid-cols-2 gap-3">
+                <input type="text" value={settings.legal_name} onChange={e => update({ legal_name: e.target.value })} placeholder="Legal name" className={inputClass} />
+                <input type="text" value={settings.gstin || ''} onChange={e => update({ gstin: e.target.value.toUpperCase() })} maxLength={15} placeholder="GSTIN (optional)" className={inputClass} />
+                <input type="text" value={settings.address_line1} onChange={e => update({ address_line1: e.target.value })} placeholder="Address line 1" className={inputClass} />
+                <input type="text" value={settings.address_line2 || ''} onChange={e => update({ address_line2: e.target.value })} placeholder="Address line 2" className={inputClass} />
+                <input type="text" value={settings.city} onChange={e => update({ city: e.target.value })} placeholder="City" className={inputClass} />
+                <input type="text" value={settings.postal_code} onChange={e => update({ postal_code: e.target.value })} placeholder="PIN code" className={inputClass} />
+                <select value={settings.state_code} onChange={e => update({ state_code: e.target.value })} className={inputClass}>
+                    <option value="">State</option>
+                    {Object.entries(states)
+                        .sort(([, a], [, b]) => a.localeCompare(b))
+                        .map(([code, name]) => (
+                            <option key={code} value={code}>{name}</option>
+                        ))}
+                </select>
+                <select value={settings.tax_rate} onChange={e => update({ tax_rate: Number(e.target.value) })} className={inputClass}>
+                    {rates.map(rate => (
+                        <option key={rate} value={rate}>GST {rate}%</option>
+                    ))}
+                </select>
+                <input type="text" value={settings.invoice_prefix} onChange={e => update({ invoice_prefix: e.target.value.toUpperCase() })} maxLength={5} placeholder="Invoice prefix" className={inputClass} />
+                <input type="text" value={settings.sac_code} onChange={e => update({ sac_code: e.target.value })} placeholder="SAC code" className={inputClass} />
+            </div>
+            <label className="flex items-center gap-2 text-sm text-gray-700 dark:text-gray-300">
+                <input type="checkbox" checked={settings.prices_include_tax} onChange={e => update({ prices_include_tax: e.target.checked })} />
+                My prices already include GST
+            </label>
+            <button
+                type="button"
+                onClick={save}
+                disabled={busy}
+                className="px-4 py-2 text-sm font-medium rounded-md text-white bg-[#6786f5] hover:bg-[#5570e0] disabled:opacity-50 transition"
+            >
+                Save invoice settings
+            </button>
+
+            <div className="border-t border-gray-100 dark:border-white/10 pt-6 space-y-3">
+                <div className="flex flex-wrap items-center justify-between gap-3">
+                    <h3 className="text-sm font-semibold text-gray-900 dark:text-white">Monthly tax summary</h3>
+                    <div className="flex items-center gap-3">
+                        <input type="month" value={month} onChange={e => setMonth(e.target.value)} className={`${inputClass} w-auto`} />
+                        <a
+                            href={`${API_BASE_URL}/creator/tax-summary/export?month=${month}`}
+                            className="text-sm font-medium text-[#6786f5] hover:text-[#5570e0]"
+                        >
+                            Download CSV
+                        </a>
+                    </div>
+                </div>
+                {summary && (
+                    <div className="grid grid-cols-2 sm:grid-cols-4 gap-3 text-sm">
+                        {[
+                            { label: 'Invoices', value: `${summary.invoices}` },
+                            { label: 'Taxable value', value: formatRupees(summary.taxable_amount) },
+                            { label: 'GST (CGST + SGST + IGST)', value: formatRupees(summary.total_tax) },
+                            { label: 'Total', value: formatRupees(summary.total) },
+                        ].map(stat => (
+                            <div key={stat.label}>
+                                <p className="text-xs text-gray-500 dark:text-gray-400">{stat.label}</p>
+                                <p className="font-semibold text-gray-900 dark:text-white">{stat.value}</p>
+                            </div>
+                        ))}
+                    </div>
+                )}
+            </div>
+        </div>
+    );
+}
@@ -0,0 +1,97 @@
+import { useState } from 'react';
+import { Link } from 'react-router-dom';
+import { api, ApiError } from '../../lib/api';
+
+const inputClass = 'w-full px-3 py-2 text-sm rounded-md border border-gray-300 dark:border-white/10 dark:bg-transparent dark:text-white';
+
+/**
+ * Change the account password. Other signed-in devices are signed out; accounts created with
+ * Google have no password yet and set one through the forgot password flow.
+ */
+export default function PasswordSettings() {
+    const [currentPassword, setCurrentPassword] = useState('');
+    const [newPassword, setNewPassword] = useState('');
+    const [confirmPassword, setConfirmPassword] = useState('');
+    const [busy, setBusy] = useState(false);
+    const [message, setMessage] = useState<string | null>(null);
+    const [error, setError] = useState<string | null>(null);
+
+    const save = async (e: React.FormEvent) => {
+        e.preventDefault();
+        setError(null);
+        setMessage(null);
+        if (newPassword !== confirmPassword) {
+            setError('New passwords do not match');
+            return;
+        }
+
+        setBusy(true);
+        try {
+            await api.post('/auth/password/change', { currentPassword, newPassword });
+            setCurrentPassword('');
+            setNewPassword('');
+            setConfirmPassword('');
+            setMessage('Your password has been changed. Other devices have been signed out.');
+        } catch (err) {
+            setError(err instanceof ApiError ? err.message : 'Failed to change password');
+        } finally {
+            setBusy(false);
+        }
+    };
+
+    return (
+        <div className="bg-white dark:bg-[#1e2135] p-6 rounded-xl shadow-sm border border-gray-200 dark:border-white/10 space-y-6">
+            <div>
+                <h2 className="text-lg font-semibold text-gray-900 dark:text-white">Password</h2>
+                <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+                    Use at least 10 characters with both letters and numbers. Signed up with Google?{' '}
+                    <Link to="/forgot-password" className="text-[#6786f5] hover:underline">Set a password</Link> by email instead.
+                </p>
+            </div>
+
+            {message && <div className="bg-green-50 text-green-700 p-3 rounded-md text-sm">{message}</div>}
+            {error && <div className="bg-red-50 text-red-700 p-3 rounded-md text-sm">{error}</div>}
+
+            <form onSubmit={save} className="grid gap-4 sm:grid-cols-3">
+                <input
+                    type="password"
+                    required
+                    autoComplete="current-password"
+                    value={currentPassword}
+                    onChange={e => setCurrentPassword(e.target.value)}
+                    placeholder="Current password"
+                    className={inputClass}
+                />
+                <input
+                    type="password"
+                    required
+                    minLength={10}
+                    autoComplete="new-password"
+                    value={newPassword}
+                    onChange={e => setNewPassword(e.target.value)}
+                    placeholder="New password"
+                    className={inputClass}
+                />
+                <input
+                    type="password"
+                    required
+                    minLength={10}
+                    autoComplete="new-password"
+                    value={confirmPassword}
+                    onChange={e => setConfirmPassword(e.target.value)}
+                    placeholder="Confirm new password"
+                    className={inputClass}
+                />
+                <div className="sm:col-span-3">
+                    <button
+                        type="submit"
+                        disabled={busy}
+                        className="px-4 py-2 text-sm font-medium rounded-md text-white bg-[#6786f5] hover:bg-[#5570e0] disabled:opacity-50 transition"
+                    >
+                        {busy ? 'Saving…' : 'Change password'}
+                    </button>
+                </div>
+            </form>
+        </div>
+    );
+}
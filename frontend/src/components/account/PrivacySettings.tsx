@@ -0,0 +1,239 @@
+import { useEffect, useState } from 'react';
+import { api, ApiError } from '../../lib/api';
+import { useAuth } from '../../context/AuthContext';
+
+interface DataExport {
+    id: string;
+    status: 'pending' | 'ready' | 'failed';
+    size?: number;
+    error?: string;
+    created_at: string;
+    completed_at?: string;
+    expires_at?: string;
+}
+
+/**
+ * Data export and account deletion controls, shared by the creator settings page and the
+ * buyer dashboard. Deleting an account asks the user to confirm their identity first.
+ */
+export default function PrivacySettings() {
+    const { user, checkAuth } = useAuth();
+    const [latestExport, setLatestExport] = useState<DataExport | null>(null);
+    const [busy, setBusy] = useState(false);
+    const [message, setMessage] = useState<string | null>(null);
+    const [error, setError] = useState<string | null>(null);
+
+    const [confirming, setConfirming] = useState(false);
+    const [password, setPassword] = useState('');
+    const [code, setCode] = useState('');
+    const [codeSent, setCodeSent] = useState(false);
+
+    const loadExport = async () => {
+        try {
+            const res = await api.get<DataExport | null>('/account/export');
+            setLatestExport(res.data);
+        } catch {
+            // Leave the last known state in place
+        }
+    };
+
+    useEffect(() => {
+        loadExport();
+    }, []);
+
+    const requestExport = async () => {
+        setBusy(true);
+        setError(null);
+        setMessage(null);
+        try {
+            const res = await api.post<DataExport>('/account/export');
+            setLatestExport(res.data);
+            setMessage("We're preparing your data. You'll get an email when it's ready to download.");
+        } catch (err) {
+            setError(err instanceof ApiError ? err.message : 'Failed to request data export');
+        } finally {
+            setBusy(false);
+        }
+    };
+
+    const downloadExport = async () => {
+        if (!latestExport) return;
+        setError(null);
+        try {
+            const res = await api.get<{ url: string }>(`/account/export/${latestExport.id}/download`);
+            if (res.data?.url) window.location.href = res.data.url;
+        } catch (err) {
+            setError(err instanceof ApiError ? err.message : 'Failed to generate download link');
+        }
+    };
+
+    const sendCode = async () => {
+        setError(null);
+        try {
+            await api.post('/auth/step-up/email');
+            setCodeSent(true);
+        } catch (err) {
+            setError(err instanceof ApiError ? err.message : 'Failed to send code');
+        }
+    };
+
+    const requestDeletion = async (verify: boolean) => {
+        setBusy(true);
+        setError(null);
+        setMessage(null);
+        try {
+            if (verify) {
+                await api.post('/auth/step-up', code ? { code } : { password });
+            }
+            await api.post('/account/deletion');
+            setConfirming(false);
+            setPassword('');
+            setCode('');
+            setCodeSent(false);
+            await checkAuth();
+        } catch (err) {
+            if (err instanceof ApiError && err.code === 'ERR_STEP_UP_REQUIRED') {
+                setConfirming(true);
+            } else {
+                setError(err instanceof ApiError ? err.message : 'Failed to delete account');
+            }
+        } finally {
+            setBusy(false);
+        }
+    };
+
+    const cancelDeletion = async () => {
+        setBusy(true);
+        setError(null);
+        try {
+            await api.delete('/account/deletion');
+            setMessage('Account deletion cancelled.');
+            await checkAuth();
+        } catch (err) {
+            setError(err instanceof ApiError ? err.message : 'Failed to cancel deletion');
+        } finally {
+            setBusy(false);
+        }
+    };
+
+    const scheduledFor = user?.deletionScheduledFor ? new Date(user.deletionScheduledFor) : null;
+
+    return (
+        <div className="bg-white dark:bg-[#1e2135] p-6 rounded-xl shadow-sm border border-gray-200 dark:border-white/10 space-y-6">
+            <div>
+                <h2 className="text-lg font-semibold text-gray-900 dark:text-white">Your data</h2>
+                <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+                    Download a copy of your profile, orders, subscriptions and other records, or delete your account.
+                </p>
+            </div>
+
+            {message && <div className="bg-green-50 text-green-700 p-3 rounded-md text-sm">{message}</div>}
+            {error && <div className="bg-red-50 text-red-700 p-3 rounded-md text-sm">{error}</div>}
+
+            <div className="flex flex-col sm:flex-row sm:items-center sm:justify-between gap-3">
+                <div className="text-sm text-gray-600 dark:text-gray-300">
+                    {!latestExport && 'No data export requested yet.'}
+                    {latestExport?.status === 'pending' && 'Your export is being prepared.'}
+                    {latestExport?.status === 'ready' && latestExport.expires_at &&
+                        `Your export is ready until ${new Date(latestExport.expires_at).toLocaleDateString()}.`}
+                    {latestExport?.status === 'failed' && 'Your last export failed. Please try again.'}
+                </div>
+                <div className="flex gap-2">
+                    {latestExport?.status === 'ready' && (
+                        <button
+                            type="button"
+                            onClick={downloadExport}
+                            className="px-4 py-2 text-sm font-medium rounded-md text-white bg-[#6786f5] hover:bg-[#5570e0] transition"
+                        >
+                            Download
+                        </button>
+                    )}
+                    <button
+                        type="button"
+                        onClick={requestExport}
+                        disabled={busy || latestExport?.status === 'pending'}
+                        className="px-4 py-2 text-sm font-medium rounded-md border border-gray-300 dark:border-white/10 text-gray-700 dark:text-gray-200 hover:bg-gray-50 dark:hover:bg-white/5 disabled:opacity-50 transition"
+                    >
+                        Request export
+                    </button>
+                </div>
+            </div>
+
+            <div className="border-t border-gray-100 dark:border-white/10 pt-6">
+                <h3 className="text-sm font-semibold text-red-600">Delete account</h3>
+                {scheduledFor ? (
+                    <div className="mt-2 flex flex-col sm:flex-row sm:items-center sm:justify-between gap-3">
+                        <p className="text-sm text-gray-600 dark:text-gray-300">
+                            Your account will be permanently deleted on {scheduledFor.toLocaleDateString()}. You can cancel until then.
+                        </p>
+                        <button
+                            type="button"
+                            onClick={cancelDeletion}
+                            disabled={busy}
+                            className="px-4 py-2 text-sm font-medium rounded-md border border-gray-300 dark:border-white/10 text-gray-700 dark:text-gray-200 hover:bg-gray-50 dark:hover:bg-white/5 disabled:opacity-50 transition"
+                        >
+                            Cancel deletion
+                        </button>
+                    </div>
+                ) : confirming ? (
+                    <div className="mt-3 space-y-3">
+                        <p className="text-sm text-gray-600 dark:text-gray-300">
+                            Confirm it's you with your password or a code sent to your email.
+                        </p>
+                        <input
+                            type="password"
+                            value={password}
+                            onChange={e => setPassword(e.target.value)}
+                            placeholder="Password"
+                            className="w-full px-3 py-2 text-sm rounded-md border border-gray-300 dark:border-white/10 dark:bg-transparent dark:text-white"
+                        />
+                        {codeSent ? (
+                            <input
+                                type="text"
+                                value={code}
+                                onChange={e => setCode(e.target.value)}
+                                placeholder="Code from email"
+                                className="w-full px-3 py-2 text-sm rounded-md border border-gray-300 dark:border-white/10 dark:bg-transparent dark:text-white"
+                            />
+                        ) : (
+                            <button type="button" onClick={sendCode} className="text-sm text-[#6786f5] hover:underline">
+                                Email me a code instead
+                            </button>
+                        )}
+                        <div className="flex gap-2">
+                            <button
+                                type="button"
+                                onClick={() => requestDeletion(true)}
+                                disabled={busy || (!password && !code)}
+                                className="px-4 py-2 text-sm font-medium rounded-md text-white bg-red-600 hover:bg-red-700 disabled:opacity-50 transition"
+                            >
+                                Delete my account
+                            </button>
+                            <button
+                                type="button"
+                                onClick={() => setConfirming(false)}
+                                className="px-4 py-2 text-sm font-medium rounded-md text-gray-600 dark:text-gray-300 hover:text-gray-900"
+                            >
+                                Back
+                            </button>
+                        </div>
+                    </div>
+                ) : (
+                    <div className="mt-2 flex flex-col sm:flex-row sm:items-center sm:justify-between gap-3">
+                        <p className="text-sm text-gray-600 dark:text-gray-300">
+                            Your account is deleted 14 days after you ask, and you can change your mind until then.
+                        </p>
+                        <button
+                            type="button"
+                            onClick={() => requestDeletion(false)}
+                            disabled={busy}
+                            className="px-4 py-2 text-sm font-medium rounded-md border border-red-200 text-red-700 bg-red-50 hover:bg-red-100 disabled:opacity-50 transition"
+                        >
+                            Delete account
+                        </button>
+                    </div>
+                )}
+            </div>
+        </div>
+    );
+}
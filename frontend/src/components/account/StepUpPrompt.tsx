@@ -0,0 +1,98 @@
+import { useState } from 'react';
+import { Loader2, ShieldCheck } from 'lucide-react';
+import { api, ApiError } from '../../lib/api';
+
+interface StepUpPromptProps {
+    /** Called once the server has accepted the confirmation; retry the blocked request here */
+    onConfirmed: () => void;
+    onCancel: () => void;
+    confirmLabel?: string;
+}
+
+/**
+ * Asks the user to confirm it's them before a sensitive action that failed with
+ * ERR_STEP_UP_REQUIRED. Accepts the account password, a code from an authenticator app, or a
+ * code sent to their email (for accounts without a password or 2FA).
+ */
+export const StepUpPrompt = ({ onConfirmed, onCancel, confirmLabel = 'Confirm' }: StepUpPromptProps) => {
+    const [password, setPassword] = useState('');
+    const [code, setCode] = useState('');
+    const [codeSent, setCodeSent] = useState(false);
+    const [busy, setBusy] = useState(false);
+    const [error, setError] = useState<string | null>(null);
+
+    const sendCode = async () => {
+        setError(null);
+        try {
+            await api.post('/auth/step-up/email');
+            setCodeSent(true);
+        } catch (err) {
+            setError(err instanceof ApiError ? err.message : 'Failed to send code');
+        }
+    };
+
+    const confirm = async (e: React.FormEvent) => {
+        e.preventDefault();
+        setBusy(true);
+        setError(null);
+        try {
+            await api.post('/auth/step-up', code ? { code: code.trim() } : { password });
+            onConfirmed();
+        } catch (err) {
+            setError(err instanceof ApiError ? err.message : 'Failed to confirm your identity');
+        } finally {
+            setBusy(false);
+        }
+    };
+
+    const inputClass = 'w-full px-3 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-[#6786f5] focus:border-[#6786f5] outline-none';
+
+    return (
+        <form onSubmit={confirm} className="space-y-3">
+            <p className="text-sm text-gray-600 flex items-start gap-2">
+                <ShieldCheck className="w-5 h-5 text-[#6786f5] shrink-0" />
+                Confirm it's you with your password or a code from your authenticator app.
+            </p>
+            {error && <div className="p-3 text-sm text-red-600 bg-red-50 rounded-lg">{error}</div>}
+            <input
+                type="password"
+                value={password}
+                onChange={e => setPassword(e.target.value)}
+                placeholder="Password"
+                autoComplete="current-password"
+                className={inputClass}
+            />
+            <input
+                type="text"
+                inputMode="numeric"
+                autoComplete="one-time-code"
+                value={code}
+                onChange={e => setCode(e.target.value)}
+                placeholder={codeSent ? 'Code from email' : 'Authenticator code'}
+                className={inputClass}
+            />
+            {!codeSent && (
+                <button type="button" onClick={sendCode} className="text-sm text-[#6786f5] hover:underline">
+                    No password or authenticator? Email me a code
+                </button>
+            )}
+            <div className="flex gap-2 pt-1">
+                <button
+                    type="submit"
+                    disabled={busy || (!password && !code)}
+                    className="flex-1 flex items-center justify-center gap-2 bg-[#6786f5] hover:bg-[#5570e0] text-white font-medium py-2.5 rounded-lg transition-colors disabled:opacity-50"
+                >
+                    {busy && <Loader2 className="w-4 h-4 animate-spin" />}
+                    {confirmLabel}
+                </button>
+                <button
+                    type="button"
+                    onClick={onCancel}
+                    className="px-4 py-2.5 text-sm font-medium rounded-lg text-gray-600 hover:text-gray-900"
+                >
+                    Back
+                </button>
+            </div>
+        </form>
+    );
+};
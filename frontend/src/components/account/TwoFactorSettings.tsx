@@ -0,0 +1,190 @@
+import { useEffect, useState } from 'react';
+import { api, ApiError } from '../../lib/api';
+
+const inputClass = 'w-full sm:w-48 px-3 py-2 text-sm rounded-md border border-gray-300 dark:border-white/10 dark:bg-transparent dark:text-white';
+const buttonClass = 'px-4 py-2 text-sm font-medium rounded-md text-white bg-[#6786f5] hover:bg-[#5570e0] disabled:opacity-50 transition';
+const secondaryButtonClass = 'px-4 py-2 text-sm font-medium rounded-md border border-gray-300 dark:border-white/10 text-gray-700 dark:text-gray-200 hover:bg-gray-50 dark:hover:bg-white/5 disabled:opacity-50 transition';
+
+interface TwoFactorStatus {
+    enabled: boolean;
+    required: boolean;
+    recoveryCodesRemaining: number;
+}
+
+/**
+ * Turn authenticator app two-factor authentication on or off and replace recovery codes.
+ * Admin accounts must keep it on, so they can only regenerate codes.
+ */
+export default function TwoFactorSettings() {
+    const [status, setStatus] = useState<TwoFactorStatus | null>(null);
+    const [setup, setSetup] = useState<{ secret: string; otpauthUrl: string } | null>(null);
+    // Which action the code field is for while 2FA is on
+    const [action, setAction] = useState<'disable' | 'regenerate' | null>(null);
+    const [code, setCode] = useState('');
+    const [recoveryCodes, setRecoveryCodes] = useState<string[] | null>(null);
+    const [busy, setBusy] = useState(false);
+    const [message, setMessage] = useState<string | null>(null);
+    const [error, setError] = useState<string | null>(null);
+
+    const load = async () => {
+        try {
+            const res = await api.get<TwoFactorStatus>('/auth/2fa');
+            setStatus(res.data);
+        } catch (err) {
+            setError(err instanceof ApiError ? err.message : 'Failed to load two-factor settings');
+        }
+    };
+
+    useEffect(() => {
+        load();
+    }, []);
+
+    const run = async (fn: () => Promise<void>) => {
+        setBusy(true);
+        setError(null);
+        setMessage(null);
+        try {
+            await fn();
+        } catch (err) {
+            setError(err instanceof ApiError ? err.message : 'Something went wrong');
+        } finally {
+            setBusy(false);
+        }
+    };
+
+    const beginSetup = () => run(async () => {
+        const res = await api.post<{ secret: string; otpauthUrl: string }>('/auth/2fa/setup');
+        setSetup(res.data);
+        setRecoveryCodes(null);
+        setCode('');
+    });
+
+    const enable = (e: React.FormEvent) => {
+        e.preventDefault();
+        run(async () => {
+            const res = await api.post<{ enabled: boolean; recoveryCodes: string[] }>('/auth/2fa/enable', { code: code.trim() });
+            setSetup(null);
+            setCode('');
+            setRecoveryCodes(res.data.recoveryCodes);
+            await load();
+        });
+    };
+
+    const confirmAction = (e: React.FormEvent) => {
+        e.preventDefault();
+        run(async () => {
+            if (action === 'disable') {
+                await api.post('/auth/2fa/disable', { code: code.trim() });
+                setRecoveryCodes(null);
+                setMessage('Two-factor authentication is off.');
+            } else {
+                const res = await api.post<{ recoveryCodes: string[] }>('/auth/2fa/recovery-codes', { code: code.trim() });
+                setRecoveryCodes(res.data.recoveryCodes);
+            }
+            setAction(null);
+            setCode('');
+            await load();
+        });
+    };
+
+    return (
+        <div className="bg-white dark:bg-[#1e2135] p-6 rounded-xl shadow-sm border border-gray-200 dark:border-white/10 space-y-6">
+            <div>
+                <h2 className="text-lg font-semibold text-gray-900 dark:text-white">Two-factor authentication</h2>
+                <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+                    Ask for a code from an authenticator app when you sign in and before payouts or other sensitive changes.
+                </p>
+            </div>
+
+            {message && <div className="bg-green-50 text-green-700 p-3 rounded-md text-sm">{message}</div>}
+            {error && <div className="bg-red-50 text-red-700 p-3 rounded-md text-sm">{error}</div>}
+
+            {recoveryCodes && (
+                <div className="space-y-2">
+                    <p className="text-sm text-gray-700 dark:text-gray-300">
+                        Save these recovery codes somewhere safe. Each one signs you in once if you lose your authenticator, and you won't see them again.
+                    </p>
+                    <div className="grid grid-cols-2 sm:grid-cols-5 gap-2 p-4 rounded-md bg-gray-50 dark:bg-white/5 font-mono text-sm text-gray-800 dark:text-gray-200">
+                        {recoveryCodes.map(rc => <span key={rc}>{rc}</span>)}
+                    </div>
+                </div>
+            )}
+
+            {status && !status.enabled && !setup && (
+                <button type="button" onClick={beginSetup} disabled={busy} className={buttonClass}>
+                    Set up two-factor authentication
+                </button>
+            )}
+
+            {setup && (
+                <form onSubmit={enable} className="space-y-3">
+                    <p className="text-sm text-gray-700 dark:text-gray-300">
+                        Add this key to an authenticator app (Google Authenticator, 1Password, Authy), then enter the 6-digit code it shows.
+                    </p>
+                    <div className="p-3 rounded-md bg-gray-50 dark:bg-white/5 space-y-1">
+                        <code className="block break-all font-mono text-sm text-gray-800 dark:text-gray-200">{setup.secret}</code>
+                        <a href={setup.otpauthUrl} className="text-sm text-[#6786f5] hover:underline">Open in authenticator app</a>
+                    </div>
+                    <div className="flex flex-col sm:flex-row gap-3">
+                        <input
+                            type="text"
+                            required
+                            inputMode="numeric"
+                            autoComplete="one-time-code"
+                            value={code}
+                            onChange={e => setCode(e.target.value)}
+                            placeholder="6-digit code"
+                            className={inputClass}
+                        />
+                        <button type="submit" disabled={busy || !code.trim()} className={buttonClass}>
+                            {busy ? 'Checking…' : 'Turn on'}
+                        </button>
+                        <button type="button" onClick={() => { setSetup(null); setCode(''); }} className={secondaryButtonClass}>
+                            Cancel
+                        </button>
+                    </div>
+                </form>
+            )}
+
+            {status?.enabled && (
+                <div className="space-y-3">
+                    <p className="text-sm text-gray-700 dark:text-gray-300">
+                        Two-factor authentication is on. {status.recoveryCodesRemaining} recovery code{status.recoveryCodesRemaining === 1 ? '' : 's'} left.
+                        {status.required && ' It is required for admin accounts.'}
+                    </p>
+                    {action ? (
+                        <form onSubmit={confirmAction} className="flex flex-col sm:flex-row gap-3">
+                            <input
+                                type="text"
+                                required
+                                autoFocus
+                                autoComplete="one-time-code"
+                                value={code}
+                                onChange={e => setCode(e.target.value)}
+                                placeholder="Authenticator code"
+                                className={inputClass}
+                            />
+                            <button type="submit" disabled={busy || !code.trim()} className={buttonClass}>
+                                {action === 'disable' ? 'Turn off' : 'Get new codes'}
+                            </button>
+                            <button type="button" onClick={() => { setAction(null); setCode(''); }} className={secondaryButtonClass}>
+                                Cancel
+                            </button>
+                        </form>
+                    ) : (
+                        <div className="flex flex-wrap gap-3">
+                            <button type="button" onClick={() => setAction('regenerate')} className={secondaryButtonClass}>
+                                New recovery codes
+                            </button>
+                            {!status.required && (
+                                <button type="button" onClick={() => setAction('disable')} className={secondaryButtonClass}>
+                                    Turn off
+                                </button>
+                            )}
+                        </div>
+                    )}
+                </div>
+            )}
+        </div>
+    );
+}
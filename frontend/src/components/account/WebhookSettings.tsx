@@ -0,0 +1,290 @@
+import { useEffect, useState } from 'react';
+import { api, ApiError } from '../../lib/api';
+
+interface WebhookEndpoint {
+    id: string;
+    url: string;
+    description?: string;
+    events: string[];
+    enabled: boolean;
+    disabled_reason?: string;
+    consecutive_failures: number;
+    created_at: string;
+}
+
+interface WebhookDelivery {
+    id: string;
+    event_id: string;
+    event_type: string;
+    status: 'pending' | 'succeeded' | 'failed';
+    response_status?: number;
+    attempts: { at: string; response_status?: number; error?: string; manual?: boolean }[];
+    next_attempt_at?: string;
+    created_at: string;
+}
+
+const EVENTS = [
+    { value: 'order.paid', label: 'Order paid' },
+    { value: 'subscription.cancelled', label: 'Subscription cancelled' },
+    { value: 'booking.created', label: 'Booking created' },
+    { value: 'subscriber.added', label: 'Subscriber added' },
+    { value: 'affiliate.sale', label: 'Affiliate sale' },
+];
+
+const STATUS_STYLES: Record<WebhookDelivery['status'], string> = {
+    pending: 'text-yellow-600',
+    succeeded: 'text-green-600',
+    failed: 'text-red-600',
+};
+
+/**
+ * Outbound webhooks that send store events to the creator's own HTTPS endpoints. Signing
+ * secrets are shown once, after creating an endpoint or rotating its secret; both ask the
+ * creator to confirm their identity first.
+ */
+export default function WebhookSettings() {
+    const [endpoints, setEndpoints] = useState<WebhookEndpoint[]>([]);
+    const [url, setUrl] = useState('');
+    const [events, setEvents] = useState<string[]>([]);
+    const [secret, setSecret] = useState<string | null>(null);
+    const [password, setPassword] = useState('');
+    const [pending, setPending] = useState<(() => Promise<void>) | null>(null);
+    const [openId, setOpenId] = useState<string | null>(null);
+    const [deliveries, setDeliveries] = useState<WebhookDelivery[]>([]);
+    const [busy, setBusy] = useState(false);
+    const [error, setError] = useState<string | null>(null);
+
+    const loadEndpoints = async () => {
+        try {
+            const res = await api.get<WebhookEndpoint[]>('/creator/webhooks');
+            setEndpoints(res.data || []);
+        } catch {
+            // Leave the list as it was
+        }
+    };
+
+    const loadDeliveries = async (id: string) => {
+        try {
+            const res = await api.get<WebhookDelivery[]>(`/creator/webhooks/${id}/deliveries?pageSize=20`);
+            setDeliveries(res.data || []);
+        } catch (err) {
+            setError(err instanceof ApiError ? err.message : 'Failed to load deliveries');
+        }
+    };
+
+    useEffect(() => {
+        loadEndpoints();
+    }, []);
+
+    // Runs action, asking for the password first when the server wants step-up.
+    const withStepUp = async (action: () => Promise<void>, fallback: string) => {
+        setBusy(true);
+        setError(null);
+        try {
+            await action();
+            setPending(null);
+        } catch (err) {
+            if (err instanceof ApiError && err.code === 'ERR_STEP_UP_REQUIRED') {
+                setPending(() => action);
+            } else {
+                setError(err instanceof ApiError ? err.message : fallback);
+            }
+        } finally {
+            setBusy(false);
+        }
+    };
+
+    const confirmStepUp = async () => {
+        if (!pending) return;
+        setBusy(true);
+        setError(null);
+        try {
+            await api.post('/auth/step-up', { password });
+            setPassword('');
+        } catch (err) {
+            setError(err instanceof ApiError ? err.message : 'Failed to confirm your password');
+            setBusy(false);
+            return;
+        }
+        await withStepUp(pending, 'Request failed');
+    };
+
+    const createEndpoint = () => withStepUp(async () => {
+        const res = await api.post<{ endpoint: WebhookEndpoint; secret: string }>('/creator/webhooks', { url, events });
+        setSecret(res.data?.secret || null);
+        setUrl('');
+        setEvents([]);
+        await loadEndpoints();
+    }, 'Failed to create webhook');
+
+    const rotateSecret = (id: string) => withStepUp(async () => {
+        const res = await api.post<{ secret: string }>(`/creator/webhooks/${id}/rotate-secret`);
+        setSecret(res.data?.secret || null);
+    }, 'Failed to rotate secret');
+
+    const setEnabled = async (id: string, enabled: boolean) => {
+        try {
+            await api.patch(`/creator/webhooks/${id}`, { enabled });
+            await loadEndpoints();
+        } catch (err) {
+            setError(err instanceof ApiError ? err.message : 'Failed to update webhook');
+        }
+    };
+
+    const deleteEndpoint = async (id: string) => {
+        if (!confirm('Delete this webhook and its delivery log?')) return;
+        try {
+            await api.delete(`/creator/webhooks/${id}`);
+            if (openId === id) setOpenId(null);
+            await loadEndpoints();
+        } catch (err) {
+            setError(err instanceof ApiError ? err.message : 'Failed to delete webhook');
+        }
+    };
+
+    const toggleDeliveries = async (id: string) => {
+        if (openId === id) {
+            setOpenId(null);
+            return;
+        }
+        setOpenId(id);
+        setDeliveries([]);
+        await loadDeliveries(id);
+    };
+
+    const redeliver = async (endpointId: string, deliveryId: string) => {
+        try {
+            await api.post(`/creator/webhooks/deliveries/${deliveryId}/redeliver`);
+            await loadDeliveries(endpointId);
+        } catch (err) {
+            setError(err instanceof ApiError ? err.message : 'Failed to redeliver');
+        }
+    };
+
+    const toggleEvent = (event: string) => {
+        setEvents(prev => prev.includes(event) ? prev.filter(e => e !== event) : [...prev, event]);
+    };
+
+    return (
+        <div className="bg-white dark:bg-[#1e2135] p-6 rounded-xl shadow-sm border border-gray-200 dark:border-white/10 space-y-6">
+            <div>
+                <h2 className="text-lg font-semibold text-gray-900 dark:text-white">Webhooks</h2>
+                <p className="mt-1 text-sm text-gray-500 dark:text-gray-400">
+                    Send store events to Zapier, Notion or your own server. Each request is signed in the <code>X-Mio-Signature</code> header
+                    as <code>t=&lt;timestamp&gt;,v1=&lt;HMAC-SHA256 of "timestamp.body"&gt;</code>.
+                </p>
+            </div>
+
+            {error && <div className="bg-red-50 text-red-700 p-3 rounded-md text-sm">{error}</div>}
+            {secret && (
+                <div className="bg-green-50 p-3 rounded-md text-sm text-green-800 space-y-1">
+                    <p>Copy your signing secret now. You won't be able to see it again.</p>
+                    <code className="block break-all font-mono text-xs">{secret}</code>
+                </div>
+            )}
+            {pending && (
+                <div className="flex gap-2">
+                    <input
+                        type="password"
+                        value={password}
+                        onChange={e => setPassword(e.target.value)}
+                        placeholder="Confirm your password"
+                        className="flex-1 px-3 py-2 text-sm rounded-md border border-gray-300 dark:border-white/10 dark:bg-transparent dark:text-white"
+                    />
+                    <button
+                        type="button"
+                        onClick={confirmStepUp}
+                        disabled={busy || !password}
+                        className="px-4 py-2 text-sm font-medium rounded-md text-white bg-[#6786f5] hover:bg-[#5570e0] disabled:opacity-50 transition"
+                    >
+                        Confirm
+                    </button>
+                </div>
+            )}
+
+            <div className="space-y-3">
+                <input
+                    type="url"
+                    value={url}
+                    onChange={e => setUrl(e.target.value)}
+                    placeholder="https://hooks.example.com/mio"
+                    className="w-full px-3 py-2 text-sm rounded-md border border-gray-300 dark:border-white/10 dark:bg-transparent dark:text-white"
+                />
+                <div className="flex flex-wrap gap-3">
+                    {EVENTS.map(event => (
+                        <label key={event.value} className="flex items-center gap-2 text-sm text-gray-700 dark:text-gray-300">
+                            <input type="checkbox" checked={events.includes(event.value)} onChange={() => toggleEvent(event.value)} />
+                            {event.label}
+                        </label>
+                    ))}
+                </div>
+                <button
+                    type="button"
+                    onClick={createEndpoint}
+                    disabled={busy || !url || events.length === 0}
+                    className="px-4 py-2 text-sm font-medium rounded-md text-white bg-[#6786f5] hover:bg-[#5570e0] disabled:opacity-50 transition"
+                >
+                    Add endpoint
+                </button>
+            </div>
+
+            {endpoints.length > 0 && (
+                <ul className="divide-y divide-gray-100 dark:divide-white/10">
+                    {endpoints.map(endpoint => (
+                        <li key={endpoint.id} className="py-3 space-y-2">
+                            <div className="flex items-center justify-between gap-3">
+                                <div className="min-w-0">
+                                    <p className="text-sm font-medium text-gray-900 dark:text-white truncate">{endpoint.url}</p>
+                                    <p className="text-xs text-gray-500 dark:text-gray-400">
+                                        {endpoint.events.join(', ')}
+                                        {!endpoint.enabled && ` · Disabled${endpoint.disabled_reason ? `: ${endpoint.disabled_reason}` : ''}`}
+                                        {endpoint.enabled && endpoint.consecutive_failures > 0 && ` · ${endpoint.consecutive_failures} failed attempts`}
+                                    </p>
+                                </div>
+                                <div className="flex items-center gap-3 text-sm shrink-0">
+                                    <button type="button" onClick={() => toggleDeliveries(endpoint.id)} className="text-[#6786f5] hover:underline">
+                                        {openId === endpoint.id ? 'Hide log' : 'Log'}
+                                    </button>
+                                    <button type="button" onClick={() => setEnabled(endpoint.id, !endpoint.enabled)} className="text-gray-600 dark:text-gray-300 hover:underline">
+                                        {endpoint.enabled ? 'Disable' : 'Enable'}
+                                    </button>
+                                    <button type="button" onClick={() => rotateSecret(endpoint.id)} className="text-gray-600 dark:text-gray-300 hover:underline">
+                                        Rotate secret
+                                    </button>
+                                    <button type="button" onClick={() => deleteEndpoint(endpoint.id)} className="text-red-600 hover:text-red-700">
+                                        Delete
+                                    </button>
+                                </div>
+                            </div>
+
+                            {openId === endpoint.id && (
+                                deliveries.length === 0 ? (
+                                    <p className="text-xs text-gray-500 dark:text-gray-400">No deliveries in the last 30 days.</p>
+                                ) : (
+                                    <ul className="text-xs space-y-1">
+                                        {deliveries.map(delivery => (
+                                            <li key={delivery.id} className="flex items-center justify-between gap-3">
+                                                <span className="text-gray-700 dark:text-gray-300">
+                                                    {new Date(delivery.created_at).toLocaleString()} · {delivery.event_type} ·{' '}
+                                                    <span className={STATUS_STYLES[delivery.status]}>{delivery.status}</span>
+                                                    {delivery.response_status ? ` (${delivery.response_status})` : ''} · {delivery.attempts.length} attempts
+                                                </span>
+                                                <button
+                                                    type="button"
+                                                    onClick={() => redeliver(endpoint.id, delivery.id)}
+                                                    className="text-[#6786f5] hover:underline"
+                                                >
+                                                    Redeliver
+                                                </button>
+                                            </li>
+                                        ))}
+                                    </ul>
+                                )
+                            )}
+                        </li>
+                    ))}
+                </ul>
+            )}
+        </div>
+    );
+}
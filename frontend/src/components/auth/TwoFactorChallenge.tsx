@@ -0,0 +1,135 @@
+import { useEffect, useRef, useState } from 'react';
+import { api, ApiError } from '../../lib/api';
+import { Loader2, ShieldCheck, ArrowLeft } from 'lucide-react';
+
+interface TwoFactorChallengeProps {
+    challengeToken: string;
+    /** The account must enrol an authenticator before it can sign in (admins) */
+    setup: boolean;
+    /** Called once the session cookies are set, with where the user should land */
+    onComplete: (redirectUrl: string) => void;
+    onRestart: () => void;
+}
+
+interface TwoFactorLoginResult {
+    redirectUrl: string;
+    recoveryCodes?: string[];
+}
+
+/**
+ * Second sign-in step for accounts with two-factor authentication. The password, Google or
+ * magic link step returns a challenge token; this collects an authenticator or recovery code
+ * for it, or walks admins without 2FA through enrolment first.
+ */
+export const TwoFactorChallenge = ({ challengeToken, setup, onComplete, onRestart }: TwoFactorChallengeProps) => {
+    const [enrolment, setEnrolment] = useState<{ secret: string; otpauthUrl: string } | null>(null);
+    const [code, setCode] = useState('');
+    const [recoveryCodes, setRecoveryCodes] = useState<string[] | null>(null);
+    const [redirectUrl, setRedirectUrl] = useState('');
+    const [isLoading, setIsLoading] = useState(false);
+    const [error, setError] = useState('');
+    // Each setup request issues a new secret; only ask once per challenge
+    const setupRequested = useRef(false);
+
+    useEffect(() => {
+        if (!setup || setupRequested.current) return;
+        setupRequested.current = true;
+        api.post<{ secret: string; otpauthUrl: string }>('/auth/2fa/login-setup', { challengeToken })
+            .then(res => setEnrolment(res.data))
+            .catch(err => setError(err instanceof ApiError ? err.message : 'Failed to start two-factor setup'));
+    }, [setup, challengeToken]);
+
+    const handleSubmit = async (e: React.FormEvent) => {
+        e.preventDefault();
+        setError('');
+        setIsLoading(true);
+        try {
+            const res = await api.post<TwoFactorLoginResult>('/auth/2fa/verify', { challengeToken, code: code.trim() });
+            const target = res.data?.redirectUrl || '/dashboard';
+            if (res.data?.recoveryCodes?.length) {
+                // Enrolment finished during sign-in; show the codes before leaving the page
+                setRecoveryCodes(res.data.recoveryCodes);
+                setRedirectUrl(target);
+            } else {
+                onComplete(target);
+            }
+        } catch (err) {
+            setError(err instanceof ApiError ? err.message : 'Invalid code. Please try again.');
+            setCode('');
+        } finally {
+            setIsLoading(false);
+        }
+    };
+
+    const inputClass = 'w-full px-4 py-3 rounded-xl border border-gray-200 dark:border-gray-700 bg-white dark:bg-[#1a1d2b] text-gray-900 dark:text-white placeholder-gray-400 focus:border-[#6786f5] focus:outline-none focus:ring-2 focus:ring-[#6786f5]/20';
+    const buttonClass = 'w-full flex items-center justify-center gap-2 rounded-xl bg-[#6786f5] px-6 py-3 text-base font-semibold text-white shadow-sm transition-all hover:bg-[#5570e0] disabled:opacity-50';
+
+    if (recoveryCodes) {
+        return (
+            <div className="space-y-4">
+                <p className="text-sm text-gray-600 dark:text-gray-300">
+                    Two-factor authentication is on. Save these recovery codes somewhere safe. Each one signs you in once if you lose your authenticator, and you won't see them again.
+                </p>
+                <div className="grid grid-cols-2 gap-2 p-4 rounded-xl bg-gray-50 dark:bg-white/5 font-mono text-sm text-gray-800 dark:text-gray-200">
+                    {recoveryCodes.map(rc => <span key={rc}>{rc}</span>)}
+                </div>
+                <button type="button" onClick={() => onComplete(redirectUrl)} className={buttonClass}>
+                    I've saved my codes
+                </button>
+            </div>
+        );
+    }
+
+    return (
+        <div className="space-y-4">
+            <div className="flex items-start gap-3 text-sm text-gray-600 dark:text-gray-300">
+                <ShieldCheck className="w-5 h-5 text-[#6786f5] shrink-0" />
+                {setup ? (
+                    <p>Your account needs two-factor authentication. Add this key to an authenticator app (Google Authenticator, 1Password, Authy), then enter the 6-digit code it shows.</p>
+                ) : (
+                    <p>Enter the 6-digit code from your authenticator app, or one of your recovery codes.</p>
+                )}
+            </div>
+
+            {setup && enrolment && (
+                <div className="p-4 rounded-xl bg-gray-50 dark:bg-white/5 space-y-2">
+                    <code className="block break-all font-mono text-sm text-gray-800 dark:text-gray-200">{enrolment.secret}</code>
+                    <a href={enrolment.otpauthUrl} className="text-sm text-[#6786f5] hover:underline">
+                        Open in authenticator app
+                    </a>
+                </div>
+            )}
+
+            {error && (
+                <div className="p-3 bg-red-50 dark:bg-red-500/10 text-red-700 dark:text-red-400 rounded-xl text-sm">
+                    {error}
+                </div>
+            )}
+
+            <form onSubmit={handleSubmit} className="space-y-4">
+                <input
+                    type="text"
+                    required
+                    autoFocus
+                    autoComplete="one-time-code"
+                    value={code}
+                    onChange={(e) => setCode(e.target.value)}
+                    placeholder={setup ? '6-digit code' : 'Code or recovery code'}
+                    className={inputClass}
+                />
+                <button type="submit" disabled={isLoading || !code.trim() || (setup && !enrolment)} className={buttonClass}>
+                    {isLoading ? <Loader2 className="w-5 h-5 animate-spin" /> : 'Verify'}
+                </button>
+            </form>
+
+            <button
+                type="button"
+                onClick={onRestart}
+                className="flex items-center justify-center gap-2 w-full text-sm text-gray-500 hover:text-gray-700 dark:text-gray-400 dark:hover:text-gray-200"
+            >
+                <ArrowLeft className="w-4 h-4" />
+                Sign in again
+            </button>
+        </div>
+    );
+};
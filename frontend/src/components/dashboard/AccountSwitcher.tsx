@@ -0,0 +1,65 @@
+import { useEffect, useState } from 'react';
+import { useAuth } from '../../context/AuthContext';
+import { api, getActiveCreatorId, setActiveCreatorId } from '../../lib/api';
+
+interface TeamAccount {
+    creator_id: string;
+    display_name: string;
+    username?: string;
+    avatar_url?: string;
+    roles: string[];
+}
+
+/**
+ * Lets a team member switch between their own account and the creator accounts they work on.
+ * Hidden for users who are not on anyone's team.
+ */
+export const AccountSwitcher = () => {
+    const { user } = useAuth();
+    const [accounts, setAccounts] = useState<TeamAccount[]>([]);
+    const active = getActiveCreatorId();
+
+    useEffect(() => {
+        api.get<TeamAccount[]>('/team/accounts')
+            .then(res => {
+                const list = res.data || [];
+                setAccounts(list);
+                // Removed from the team since the account was chosen
+                if (active && !list.some(a => a.creator_id === active)) {
+                    switchTo(null);
+                }
+            })
+            .catch(() => {
+                // Switcher stays hidden
+            });
+    }, []);
+
+    const switchTo = (creatorId: string | null) => {
+        setActiveCreatorId(creatorId);
+        // Start from a clean slate so no page keeps the other account's data
+        window.location.href = '/dashboard';
+    };
+
+    if (accounts.length === 0) return null;
+
+    return (
+        <div className="px-4 pt-4">
+            <label htmlFor="account-switcher" className="block px-1 mb-1 text-xs font-medium uppercase tracking-wide text-gray-400">
+                Account
+            </label>
+            <select
+                id="account-switcher"
+                value={active || ''}
+                onChange={(e) => switchTo(e.target.value || null)}
+                className="w-full px-3 py-2 text-sm rounded-xl border border-gray-200 dark:border-gray-700 bg-white dark:bg-[#252838] text-gray-900 dark:text-white focus:border-[#6786f5] focus:outline-none"
+            >
+                <option value="">{user?.displayName || 'My account'} (you)</option>
+                {accounts.map(account => (
+                    <option key={account.creator_id} value={account.creator_id}>
+                        {account.display_name || account.username || account.creator_id}
+                    </option>
+                ))}
+            </select>
+        </div>
+    );
+};
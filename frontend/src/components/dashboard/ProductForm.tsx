@@ -1,6 +1,6 @@
 import React, { useState } from 'react';
 import { useMutation, useQueryClient } from '@tanstack/react-query';
-import { createProduct, updateProduct, getPresignedUrl, uploadFileToUrl, updateBumpConfig } from '../../lib/api/products';
+import { createProduct, updateProduct, getPresignedUrl, uploadFileToUrl, completeUpload, updateBumpConfig } from '../../lib/api/products';
 import type { CreateProductDTO } from '../../lib/api/products';
 import type { Product, BumpConfig } from '../../lib/api/store';
 import { Loader2, Upload, X, Sparkles, FileText, Mail, Video, BookOpen, Users, ExternalLink } from 'lucide-react';
@@ -149,6 +149,7 @@ const ProductForm: React.FC<ProductFormProps> = ({ product, defaultProductType,
             if (imageFile) {
                 const presigned = await getPresignedUrl(imageFile.name, imageFile.type, 'cover_image');
                 await uploadFileToUrl(presigned.url, imageFile);
+                await completeUpload(presigned.key);
                 finalImageUrl = presigned.key; // Store the key or the public URL depending on backend logic. Assuming key for now, or backend constructs URL.
                 // Actually, previous implementation of backend returns key. Service constructs URL?
                 // Let's assume backend expects the full URL or just the key. 
@@ -176,8 +177,9 @@ const ProductForm: React.FC<ProductFormProps> = ({ product, defaultProductType,
             if (productFile) {
                 const presigned = await getPresignedUrl(productFile.name, productFile.type, 'product_file');
                 await uploadFileToUrl(presigned.url, productFile);
-                const r2PublicUrl = import.meta.env.VITE_R2_PUBLIC_URL || 'https://pub-your-r2-domain.r2.dev';
-                finalFileUrl = `${r2PublicUrl}/${presigned.key}`; // Placeholder
+                await completeUpload(presigned.key);
+                // Product files are private; the backend serves them through signed download links.
+                finalFileUrl = presigned.key;
             }
 
             // Force price to 0 if it's a lead magnet or external link
@@ -5,6 +5,7 @@ import { LayoutDashboard, ShoppingBag, Wallet, Settings, Menu, X, Megaphone, Plu
 import { useAuth } from '../../context/AuthContext';
 import { MioLogo } from '../brand/MioLogo';
 import { ThemeToggle } from '../ui/ThemeToggle';
+import { AccountSwitcher } from './AccountSwitcher';
 
 const navItems = [
     { name: 'Analytics', path: '/dashboard/analytics', icon: TrendingUp },
@@ -111,6 +112,7 @@ const Sidebar = () => {
                             </button>
                         </div>
                         <div className="flex-1">
+                            <AccountSwitcher />
                             <NavLinks onNavigate={() => setMobileOpen(false)} />
                         </div>
                         <LogoutButton />
@@ -126,6 +128,7 @@ const Sidebar = () => {
                     </a>
                 </div>
                 <div className="flex-1">
+                    <AccountSwitcher />
                     <NavLinks />
                 </div>
                 <LogoutButton />
@@ -1,6 +1,7 @@
 import { Outlet, useLocation } from 'react-router-dom';
 import Sidebar from '../dashboard/Sidebar';
 import { SubscriptionGate } from '../dashboard/SubscriptionGate';
+import { ImpersonationBanner } from './ImpersonationBanner';
 
 export const DashboardLayout = () => {
     const location = useLocation();
@@ -11,6 +12,7 @@ export const DashboardLayout = () => {
 
     return (
         <SubscriptionGate>
+            <ImpersonationBanner />
             <div className="flex min-h-screen bg-[#f8f9ff] dark:bg-[#0f111a] transition-colors">
                 <Sidebar />
                 {/* Main content area */}
@@ -0,0 +1,40 @@
+import { useState } from 'react';
+import { useAuth } from '../../context/AuthContext';
+import { api } from '../../lib/api';
+
+/** Sticky warning shown while an admin is viewing a creator's account read-only. */
+export const ImpersonationBanner = () => {
+    const { user } = useAuth();
+    const [stopping, setStopping] = useState(false);
+
+    const impersonation = user?.impersonation;
+    if (!impersonation) return null;
+
+    const stop = async () => {
+        setStopping(true);
+        try {
+            await api.post('/auth/impersonation/stop', {});
+        } finally {
+            // The admin's own session is restored from the refresh cookie on the next request
+            window.location.href = '/admin';
+        }
+    };
+
+    const expires = new Date(impersonation.expires_at).toLocaleTimeString([], { hour: '2-digit', minute: '2-digit' });
+
+    return (
+        <div className="sticky top-0 z-40 bg-amber-500 text-black px-4 py-2 text-sm flex flex-wrap items-center justify-between gap-2">
+            <span>
+                Viewing <strong>{user?.email}</strong> as {impersonation.admin_email || 'an admin'} (read-only, ends at {expires}).
+                Reason: {impersonation.reason}
+            </span>
+            <button
+                onClick={stop}
+                disabled={stopping}
+                className="px-3 py-1 rounded-md bg-black text-white font-semibold disabled:opacity-60"
+            >
+                {stopping ? 'Stopping…' : 'Stop impersonating'}
+            </button>
+        </div>
+    );
+};
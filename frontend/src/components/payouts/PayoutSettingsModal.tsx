@@ -1,6 +1,8 @@
 import React, { useState } from 'react';
 import { X, Loader2, Landmark } from 'lucide-react';
 import { savePayoutSettings } from '../../features/payouts/api';
+import { ApiError } from '../../lib/api';
+import { StepUpPrompt } from '../account/StepUpPrompt';
 
 interface PayoutSettingsModalProps {
     isOpen: boolean;
@@ -23,11 +25,12 @@ export const PayoutSettingsModal: React.FC<PayoutSettingsModalProps> = ({ isOpen
     });
     const [loading, setLoading] = useState(false);
     const [error, setError] = useState('');
+    // Changing where money goes needs a fresh confirmation of the user's identity
+    const [confirming, setConfirming] = useState(false);
 
     if (!isOpen) return null;
 
-    const handleSubmit = async (e: React.FormEvent) => {
-        e.preventDefault();
+    const save = async () => {
         setLoading(true);
         setError('');
 
@@ -36,12 +39,21 @@ export const PayoutSettingsModal: React.FC<PayoutSettingsModalProps> = ({ isOpen
             onSuccess();
             onClose();
         } catch (err: any) {
-            setError(err.message || 'Failed to save payout settings');
+            if (err instanceof ApiError && err.code === 'ERR_STEP_UP_REQUIRED') {
+                setConfirming(true);
+            } else {
+                setError(err.message || 'Failed to save payout settings');
+            }
         } finally {
             setLoading(false);
         }
     };
 
+    const handleSubmit = (e: React.FormEvent) => {
+        e.preventDefault();
+        save();
+    };
+
     return (
         <div className="fixed inset-0 z-50 flex items-center justify-center p-4 bg-black/50 backdrop-blur-sm">
             <div className="bg-white rounded-2xl shadow-xl w-full max-w-md overflow-hidden animate-in fade-in zoom-in duration-200">
@@ -60,77 +72,87 @@ export const PayoutSettingsModal: React.FC<PayoutSettingsModalProps> = ({ isOpen
                     </p>
                 </div>
 
-                <form onSubmit={handleSubmit} className="p-4 space-y-4">
-                    {error && (
-                        <div className="p-3 text-sm text-red-600 bg-red-50 rounded-lg">
-                            {error}
-                        </div>
-                    )}
-
-                    <div>
-                        <label htmlFor="account_holder_name" className="block text-sm font-medium text-gray-700 mb-1">Account Holder Name</label>
-                        <input
-                            type="text"
-                            id="account_holder_name"
-                            required
-                            value={formData.account_holder_name}
-                            onChange={(e) => setFormData({ ...formData, account_holder_name: e.target.value })}
-                            className="w-full px-3 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-[#6786f5] focus:border-[#6786f5] outline-none"
-                            placeholder="John Doe"
+                {confirming ? (
+                    <div className="p-4">
+                        <StepUpPrompt
+                            confirmLabel="Confirm and save"
+                            onConfirmed={() => { setConfirming(false); save(); }}
+                            onCancel={() => setConfirming(false)}
                         />
                     </div>
+                ) : (
+                    <form onSubmit={handleSubmit} className="p-4 space-y-4">
+                        {error && (
+                            <div className="p-3 text-sm text-red-600 bg-red-50 rounded-lg">
+                                {error}
+                            </div>
+                        )}
 
-                    <div>
-                        <label htmlFor="account_number" className="block text-sm font-medium text-gray-700 mb-1">Account Number</label>
-                        <input
-                            type="password"
-                            id="account_number"
-                            required
-                            value={formData.account_number}
-                            onChange={(e) => setFormData({ ...formData, account_number: e.target.value })}
-                            className="w-full px-3 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-[#6786f5] focus:border-[#6786f5] outline-none font-mono"
-                            placeholder="*************"
-                        />
-                    </div>
+                        <div>
+                            <label htmlFor="account_holder_name" className="block text-sm font-medium text-gray-700 mb-1">Account Holder Name</label>
+                            <input
+                                type="text"
+                                id="account_holder_name"
+                                required
+                                value={formData.account_holder_name}
+                                onChange={(e) => setFormData({ ...formData, account_holder_name: e.target.value })}
+                                className="w-full px-3 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-[#6786f5] focus:border-[#6786f5] outline-none"
+                                placeholder="John Doe"
+                            />
+                        </div>
 
-                    <div>
-                        <label htmlFor="ifsc_code" className="block text-sm font-medium text-gray-700 mb-1">IFSC Code</label>
-                        <input
-                            type="text"
-                            id="ifsc_code"
-                            required
-                            value={formData.ifsc_code}
-                            onChange={(e) => setFormData({ ...formData, ifsc_code: e.target.value.toUpperCase() })}
-                            className="w-full px-3 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-[#6786f5] focus:border-[#6786f5] outline-none font-mono uppercase"
-                            placeholder="HDFC0001234"
-                            maxLength={11}
-                        />
-                    </div>
+                        <div>
+                            <label htmlFor="account_number" className="block text-sm font-medium text-gray-700 mb-1">Account Number</label>
+                            <input
+                                type="password"
+                                id="account_number"
+                                required
+                                value={formData.account_number}
+                                onChange={(e) => setFormData({ ...formData, account_number: e.target.value })}
+                                className="w-full px-3 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-[#6786f5] focus:border-[#6786f5] outline-none font-mono"
+                                placeholder="*************"
+                            />
+                        </div>
 
-                    <div>
-                        <label htmlFor="account_type" className="block text-sm font-medium text-gray-700 mb-1">Account Type</label>
-                        <select
-                            id="account_type"
-                            value={formData.account_type}
-                            onChange={(e) => setFormData({ ...formData, account_type: e.target.value })}
-                            className="w-full px-3 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-[#6786f5] focus:border-[#6786f5] outline-none"
-                        >
-                            <option value="savings">Savings Account</option>
-                            <option value="current">Current Account</option>
-                        </select>
-                    </div>
+                        <div>
+                            <label htmlFor="ifsc_code" className="block text-sm font-medium text-gray-700 mb-1">IFSC Code</label>
+                            <input
+                                type="text"
+                                id="ifsc_code"
+                                required
+                                value={formData.ifsc_code}
+                                onChange={(e) => setFormData({ ...formData, ifsc_code: e.target.value.toUpperCase() })}
+                                className="w-full px-3 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-[#6786f5] focus:border-[#6786f5] outline-none font-mono uppercase"
+                                placeholder="HDFC0001234"
+                                maxLength={11}
+                            />
+                        </div>
 
-                    <div className="pt-2">
-                        <button
-                            type="submit"
-                            disabled={loading}
-                            className="w-full flex items-center justify-center gap-2 bg-[#6786f5] hover:bg-[#5570e0] text-white font-medium py-2.5 rounded-lg transition-colors disabled:opacity-50"
-                        >
-                            {loading && <Loader2 className="w-4 h-4 animate-spin" />}
-                            {loading ? 'Saving details...' : 'Save Bank Details'}
-                        </button>
-                    </div>
-                </form>
+                        <div>
+                            <label htmlFor="account_type" className="block text-sm font-medium text-gray-700 mb-1">Account Type</label>
+                            <select
+                                id="account_type"
+                                value={formData.account_type}
+                                onChange={(e) => setFormData({ ...formData, account_type: e.target.value })}
+                                className="w-full px-3 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-[#6786f5] focus:border-[#6786f5] outline-none"
+                            >
+                                <option value="savings">Savings Account</option>
+                                <option value="current">Current Account</option>
+                            </select>
+                        </div>
+
+                        <div className="pt-2">
+                            <button
+                                type="submit"
+                                disabled={loading}
+                                className="w-full flex items-center justify-center gap-2 bg-[#6786f5] hover:bg-[#5570e0] text-white font-medium py-2.5 rounded-lg transition-colors disabled:opacity-50"
+                            >
+                                {loading && <Loader2 className="w-4 h-4 animate-spin" />}
+                                {loading ? 'Saving details...' : 'Save Bank Details'}
+                            </button>
+                        </div>
+                    </form>
+                )}
             </div>
         </div>
     );
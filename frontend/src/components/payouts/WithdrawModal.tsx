@@ -2,6 +2,8 @@ import React, { useState } from 'react';
 import { X, Loader2, ArrowRight } from 'lucide-react';
 import { withdrawFunds } from '../../features/payouts/api';
 import { formatPrice } from '../../lib/utils';
+import { ApiError } from '../../lib/api';
+import { StepUpPrompt } from '../account/StepUpPrompt';
 
 interface WithdrawModalProps {
     isOpen: boolean;
@@ -22,11 +24,17 @@ export const WithdrawModal: React.FC<WithdrawModalProps> = ({
     const [amountRupees, setAmountRupees] = useState((availableBalance / 100).toString());
     const [loading, setLoading] = useState(false);
     const [error, setError] = useState('');
+    // Withdrawals need a fresh confirmation of the user's identity
+    const [confirming, setConfirming] = useState(false);
 
     if (!isOpen) return null;
 
-    const handleSubmit = async (e: React.FormEvent) => {
+    const handleSubmit = (e: React.FormEvent) => {
         e.preventDefault();
+        withdraw();
+    };
+
+    const withdraw = async () => {
         setLoading(true);
         setError('');
 
@@ -49,7 +57,11 @@ export const WithdrawModal: React.FC<WithdrawModalProps> = ({
             onSuccess();
             onClose();
         } catch (err: any) {
-            setError(err.message || 'Failed to initiate withdrawal');
+            if (err instanceof ApiError && err.code === 'ERR_STEP_UP_REQUIRED') {
+                setConfirming(true);
+            } else {
+                setError(err.message || 'Failed to initiate withdrawal');
+            }
         } finally {
             setLoading(false);
         }
@@ -70,53 +82,63 @@ export const WithdrawModal: React.FC<WithdrawModalProps> = ({
                     <p className="text-2xl font-bold text-gray-900 mt-1">{formatPrice(availableBalance)}</p>
                 </div>
 
-                <form onSubmit={handleSubmit} className="p-4 space-y-4">
-                    {error && (
-                        <div className="p-3 text-sm text-red-600 bg-red-50 rounded-lg">
-                            {error}
-                        </div>
-                    )}
+                {confirming ? (
+                    <div className="p-4">
+                        <StepUpPrompt
+                            confirmLabel="Confirm withdrawal"
+                            onConfirmed={() => { setConfirming(false); withdraw(); }}
+                            onCancel={() => setConfirming(false)}
+                        />
+                    </div>
+                ) : (
+                    <form onSubmit={handleSubmit} className="p-4 space-y-4">
+                        {error && (
+                            <div className="p-3 text-sm text-red-600 bg-red-50 rounded-lg">
+                                {error}
+                            </div>
+                        )}
 
-                    <div>
-                        <label htmlFor="amount" className="block text-sm font-medium text-gray-700 mb-1">Amount to withdraw (₹)</label>
-                        <div className="relative">
-                            <span className="absolute inset-y-0 left-0 pl-3 flex items-center text-gray-500">₹</span>
-                            <input
-                                type="number"
-                                id="amount"
-                                required
-                                min="1"
-                                max={availableBalance / 100}
-                                step="any"
-                                value={amountRupees}
-                                onChange={(e) => setAmountRupees(e.target.value)}
-                                className="w-full pl-8 pr-3 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-[#6786f5] focus:border-[#6786f5] outline-none text-lg font-medium"
-                            />
+                        <div>
+                            <label htmlFor="amount" className="block text-sm font-medium text-gray-700 mb-1">Amount to withdraw (₹)</label>
+                            <div className="relative">
+                                <span className="absolute inset-y-0 left-0 pl-3 flex items-center text-gray-500">₹</span>
+                                <input
+                                    type="number"
+                                    id="amount"
+                                    required
+                                    min="1"
+                                    max={availableBalance / 100}
+                                    step="any"
+                                    value={amountRupees}
+                                    onChange={(e) => setAmountRupees(e.target.value)}
+                                    className="w-full pl-8 pr-3 py-2 border border-gray-300 rounded-lg focus:ring-2 focus:ring-[#6786f5] focus:border-[#6786f5] outline-none text-lg font-medium"
+                                />
+                            </div>
                         </div>
-                    </div>
 
-                    <div className="p-3 bg-gray-50 border border-gray-200 rounded-lg flex items-center justify-between">
-                        <span className="text-sm text-gray-500">Transfer to</span>
-                        <span className="text-sm font-medium text-gray-900 font-mono flex items-center gap-1">
-                            •••• {accountEnding}
-                        </span>
-                    </div>
+                        <div className="p-3 bg-gray-50 border border-gray-200 rounded-lg flex items-center justify-between">
+                            <span className="text-sm text-gray-500">Transfer to</span>
+                            <span className="text-sm font-medium text-gray-900 font-mono flex items-center gap-1">
+                                •••• {accountEnding}
+                            </span>
+                        </div>
 
-                    <div className="pt-2">
-                        <button
-                            type="submit"
-                            disabled={loading || !amountRupees || parseFloat(amountRupees) <= 0}
-                            className="w-full flex items-center justify-center gap-2 bg-[#6786f5] hover:bg-[#5570e0] text-white font-medium py-2.5 rounded-lg transition-colors disabled:opacity-50"
-                        >
-                            {loading ? <Loader2 className="w-4 h-4 animate-spin" /> : null}
-                            {loading ? 'Processing...' : 'Confirm Withdrawal'}
-                            {!loading && <ArrowRight className="w-4 h-4" />}
-                        </button>
-                        <p className="text-xs text-center text-gray-400 mt-3">
-                            Transfers usually arrive within 24 hours.
-                        </p>
-                    </div>
-                </form>
+                        <div className="pt-2">
+                            <button
+                                type="submit"
+                                disabled={loading || !amountRupees || parseFloat(amountRupees) <= 0}
+                                className="w-full flex items-center justify-center gap-2 bg-[#6786f5] hover:bg-[#5570e0] text-white font-medium py-2.5 rounded-lg transition-colors disabled:opacity-50"
+                            >
+                                {loading ? <Loader2 className="w-4 h-4 animate-spin" /> : null}
+                                {loading ? 'Processing...' : 'Confirm Withdrawal'}
+                                {!loading && <ArrowRight className="w-4 h-4" />}
+                            </button>
+                            <p className="text-xs text-center text-gray-400 mt-3">
+                                Transfers usually arrive within 24 hours.
+                            </p>
+                        </div>
+                    </form>
+                )}
             </div>
         </div>
     );
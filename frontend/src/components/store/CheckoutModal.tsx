@@ -37,6 +37,11 @@ const CheckoutModal: React.FC<CheckoutModalProps> = ({ isOpen, onClose, product,
     // Bump State
     const [bumpAccepted, setBumpAccepted] = useState(false);
 
+    // GST invoice details (optional, for business buyers)
+    const [showBusinessDetails, setShowBusinessDetails] = useState(false);
+    const [gstin, setGstin] = useState('');
+    const [businessName, setBusinessName] = useState('');
+
     const bumpProduct = React.useMemo(() => {
         if (!product?.bump?.bump_product_id || !allProducts) return null;
         return allProducts.find(p => p.id === product.bump!.bump_product_id) || null;
@@ -90,6 +95,8 @@ const CheckoutModal: React.FC<CheckoutModalProps> = ({ isOpen, onClose, product,
                 booking_slot_start: selectedSlot || undefined,
                 bump_accepted: bumpAccepted,
                 referral_code: refFromStorage || undefined,
+                gstin: gstin.trim() || undefined,
+                business_name: businessName.trim() || undefined,
             });
 
             const options: any = {
@@ -108,7 +115,9 @@ const CheckoutModal: React.FC<CheckoutModalProps> = ({ isOpen, onClose, product,
                     } catch (err) {
                         console.error('Payment verification failed:', err);
                     }
-                    window.location.href = `/order/${orderData.id}`;
+                    window.location.href = orderData.access_token
+                        ? `/order/${orderData.id}?token=${encodeURIComponent(orderData.access_token)}`
+                        : `/order/${orderData.id}`;
                 },
                 prefill: {
                     name: name,
@@ -398,6 +407,34 @@ const CheckoutModal: React.FC<CheckoutModalProps> = ({ isOpen, onClose, product,
                                     </div>
                                 </div>
 
+                                {showBusinessDetails ? (
+                                    <div className="grid grid-cols-1 sm:grid-cols-2 gap-5 sm:gap-6">
+                                        <input
+                                            type="text"
+                                            value={gstin}
+                                            onChange={(e) => setGstin(e.target.value.toUpperCase())}
+                                            maxLength={15}
+                                            className="w-full px-5 py-4 border-2 border-gray-100 dark:border-gray-800 dark:bg-white/5 rounded-2xl focus:border-indigo-500 outline-none transition-all text-gray-900 dark:text-white font-bold shadow-sm placeholder:text-gray-300 dark:placeholder:text-gray-700"
+                                            placeholder="GSTIN"
+                                        />
+                                        <input
+                                            type="text"
+                                            value={businessName}
+                                            onChange={(e) => setBusinessName(e.target.value)}
+                                            className="w-full px-5 py-4 border-2 border-gray-100 dark:border-gray-800 dark:bg-white/5 rounded-2xl focus:border-indigo-500 outline-none transition-all text-gray-900 dark:text-white font-bold shadow-sm placeholder:text-gray-300 dark:placeholder:text-gray-700"
+                                            placeholder="Business name"
+                                        />
+                                    </div>
+                                ) : (
+                                    <button
+                                        type="button"
+                                        onClick={() => setShowBusinessDetails(true)}
+                                        className="text-xs font-bold text-indigo-500 hover:text-indigo-600"
+                                    >
+                                        Need a GST invoice for your business?
+                                    </button>
+                                )}
+
                                 <div className="pt-4">
                                     <motion.button
                                         whileHover={{ scale: 1.02 }}
@@ -48,11 +48,19 @@ const ProductCard: React.FC<ProductCardProps> = ({ product, onBuy }) => {
                     style={{ backgroundColor: 'var(--theme-bg)', opacity: 0.8 }}
                 >
                     {product.cover_image_url ? (
-                        <img
-                            src={product.cover_image_url}
-                            alt={product.title}
-                            className="w-full h-full object-cover transition-transform duration-500 group-hover:scale-110"
-                        />
+                        <picture className="contents">
+                            {product.cover_image?.webp_srcset && (
+                                <source type="image/webp" srcSet={product.cover_image.webp_srcset} sizes="(min-width: 640px) 600px, 100vw" />
+                            )}
+                            <img
+                                src={product.cover_image?.src || product.cover_image_url}
+                                srcSet={product.cover_image?.srcset || undefined}
+                                sizes="(min-width: 640px) 600px, 100vw"
+                                alt={product.title}
+                                loading="lazy"
+                                className="w-full h-full object-cover transition-transform duration-500 group-hover:scale-110"
+                            />
+                        </picture>
                     ) : (
                         <div
                             className="w-full h-full flex flex-col items-center justify-center"
@@ -25,11 +25,18 @@ const StoreHeader: React.FC<StoreHeaderProps> = ({ profile }) => {
             {/* Cover Photo / Banner */}
             <div className={`w-full ${profile.coverPhotoUrl ? 'h-32 sm:h-48' : 'h-24 sm:h-32'} rounded-2xl overflow-hidden mb-4 shadow-sm relative`}>
                 {profile.coverPhotoUrl ? (
-                    <img
-                        src={profile.coverPhotoUrl}
-                        alt="Store banner"
-                        className="w-full h-full object-cover"
-                    />
+                    <picture className="contents">
+                        {profile.coverPhoto?.webp_srcset && (
+                            <source type="image/webp" srcSet={profile.coverPhoto.webp_srcset} sizes="(min-width: 672px) 672px, 100vw" />
+                        )}
+                        <img
+                            src={profile.coverPhoto?.src || profile.coverPhotoUrl}
+                            srcSet={profile.coverPhoto?.srcset || undefined}
+                            sizes="(min-width: 672px) 672px, 100vw"
+                            alt="Store banner"
+                            className="w-full h-full object-cover"
+                        />
+                    </picture>
                 ) : (
                     <div
                         className="w-full h-full"
@@ -46,11 +53,14 @@ const StoreHeader: React.FC<StoreHeaderProps> = ({ profile }) => {
                 style={{ borderColor: 'var(--theme-surface, #fff)' }}
             >
                 {profile.avatarUrl ? (
-                    <img
-                        src={profile.avatarUrl}
-                        alt={profile.displayName}
-                        className="w-full h-full object-cover"
-                    />
+                    <picture className="contents">
+                        {profile.avatar?.thumbnail_webp && <source type="image/webp" srcSet={profile.avatar.thumbnail_webp} />}
+                        <img
+                            src={profile.avatar?.thumbnail || profile.avatarUrl}
+                            alt={profile.displayName}
+                            className="w-full h-full object-cover"
+                        />
+                    </picture>
                 ) : (
                     <div
                         className="w-full h-full flex items-center justify-center text-white text-2xl sm:text-4xl font-bold"
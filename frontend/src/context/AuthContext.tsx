@@ -1,5 +1,5 @@
 import React, { createContext, useContext, useEffect, useState } from 'react';
-import { api } from '../lib/api';
+import { api, setActiveCreatorId } from '../lib/api';
 
 // User type definition matching backend response
 export interface User {
@@ -12,6 +12,19 @@ export interface User {
     socialLinks?: Array<{ platform: string; url: string }>;
     subscriptionTier: string;
     role?: string;
+    /** Set while the account is scheduled for deletion */
+    deletionRequestedAt?: string;
+    deletionScheduledFor?: string;
+    /** Present while an admin is viewing this account through a read-only impersonation session */
+    impersonation?: {
+        session_id: string;
+        admin_id: string;
+        admin_email?: string;
+        reason: string;
+        read_only: boolean;
+        started_at: string;
+        expires_at: string;
+    };
 }
 
 interface AuthContextType {
@@ -46,6 +59,7 @@ export function AuthProvider({ children }: { children: React.ReactNode }) {
     const logout = async () => {
         try {
             await api.post('/auth/logout', {});
+            setActiveCreatorId(null);
             setUser(null);
             // Force reload to clear any client-side state/cache
             window.location.href = '/login';
@@ -52,4 +52,7 @@ export const buyerApi = {
 
     // Cancel a specific subscription
     cancelSubscription: (subId: string) => api.post(`/buyer/subscriptions/${subId}/cancel`),
+
+    // Get the invoice for a paid order with a short-lived PDF link
+    getInvoice: (orderId: string) => api.get<{ download_url: string }>(`/buyer/orders/${orderId}/invoice`),
 };
@@ -13,10 +13,53 @@ export interface Order {
     created_at: string;
 }
 
-export const getSalesHistory = async (): Promise<Order[]> => {
-    const response = await api.get<Order[]>('/sales');
+export interface SalesFilters {
+    status?: string;
+    product_id?: string;
+    from?: string;
+    to?: string;
+    coupon?: string;
+    affiliate_id?: string;
+    min_amount?: number; // In paise
+    max_amount?: number; // In paise
+    q?: string;
+    sort?: 'newest' | 'oldest' | 'amount_desc' | 'amount_asc';
+    page?: number;
+    pageSize?: number;
+}
+
+export interface SalesTotals {
+    count: number;
+    paid_count: number;
+    revenue: number;
+    discounts: number;
+    platform_fees: number;
+    net: number;
+}
+
+export interface SalesPage {
+    orders: Order[];
+    page: number;
+    totalPages: number;
+    totalCount: number;
+    totals: SalesTotals;
+}
+
+export const getSalesHistory = async (filters: SalesFilters = {}): Promise<SalesPage> => {
+    const params = new URLSearchParams();
+    Object.entries(filters).forEach(([key, value]) => {
+        if (value !== undefined && value !== '') params.set(key, String(value));
+    });
+    const response = await api.get<Order[]>(`/sales?${params.toString()}`);
     if (!response.data) {
         throw new Error('Failed to fetch sales history');
     }
-    return response.data;
+    const meta = (response.meta || {}) as Record<string, unknown>;
+    return {
+        orders: response.data,
+        page: Number(meta.page) || 1,
+        totalPages: Number(meta.totalPages) || 1,
+        totalCount: Number(meta.totalCount) || 0,
+        totals: meta.totals as SalesTotals,
+    };
 };
@@ -8,6 +8,8 @@ export interface CreateOrderRequest {
     booking_slot_start?: string;
     bump_accepted?: boolean;
     referral_code?: string;
+    gstin?: string;
+    business_name?: string;
 }
 
 export interface CreateOrderResponse {
@@ -16,6 +18,8 @@ export interface CreateOrderResponse {
     amount: number;
     currency: string;
     status: string;
+    // Signed token for the order page link; it opens the order's downloads (from response meta)
+    access_token?: string;
 }
 
 export const createOrder = async (data: CreateOrderRequest): Promise<CreateOrderResponse> => {
@@ -23,7 +27,7 @@ export const createOrder = async (data: CreateOrderRequest): Promise<CreateOrder
     if (!response.data) {
         throw new Error('Failed to create order: No data received');
     }
-    return response.data;
+    return { ...response.data, access_token: response.meta?.access_token as string | undefined };
 };
 
 export const getAvailableSlots = async (productId: string, dateStr: string): Promise<string[]> => {
@@ -31,6 +35,15 @@ export const getAvailableSlots = async (productId: string, dateStr: string): Pro
     return response.data || [];
 };
 
+// Latest release of one file of a purchased product
+export interface PurchasedFile {
+    id: string;
+    label: string;
+    version: number;
+    notes?: string;
+    released_at: string;
+}
+
 export interface Order {
     id: string;
     product_id: string;
@@ -67,15 +80,39 @@ export const getOrder = async (orderId: string): Promise<Order> => {
     return response.data;
 };
 
+// Order files and downloads need the token from the order link; without one they are
+// requested as the signed-in buyer.
+const orderFilesPath = (orderId: string, token?: string) =>
+    token ? `/orders/${orderId}` : `/buyer/orders/${orderId}`;
+
+// Files of each purchased product keyed by product ID
+export const getOrderFiles = async (orderId: string, token?: string): Promise<Record<string, PurchasedFile[]>> => {
+    const query = token ? `?token=${encodeURIComponent(token)}` : '';
+    const response = await api.get<Record<string, PurchasedFile[]>>(`${orderFilesPath(orderId, token)}/files${query}`);
+    return response.data || {};
+};
+
 export interface DownloadResponse {
-    download_url: string;
-    expires_at: string;
+    download_url?: string;
+    expires_at?: string;
+    // Set while a personalised (stamped) PDF is still being generated
+    status?: 'preparing';
+    message?: string;
 }
 
-export const getOrderDownloadUrl = async (orderId: string, productId?: string): Promise<string> => {
-    const endpoint = productId ? `/orders/${orderId}/download?product_id=${productId}` : `/orders/${orderId}/download`;
+export const getOrderDownloadUrl = async (orderId: string, productId?: string, fileId?: string, token?: string): Promise<string> => {
+    const params = new URLSearchParams();
+    if (token) params.set('token', token);
+    if (productId) params.set('product_id', productId);
+    if (fileId) params.set('file_id', fileId);
+    const query = params.toString();
+    const path = `${orderFilesPath(orderId, token)}/download`;
+    const endpoint = query ? `${path}?${query}` : path;
     const response = await api.get<DownloadResponse>(endpoint);
-    if (!response.data) {
+    if (response.data?.status === 'preparing') {
+        throw new Error(response.data.message || 'Your file is being prepared. Please try again shortly.');
+    }
+    if (!response.data?.download_url) {
         throw new Error('Failed to get download URL');
     }
     return response.data.download_url;
@@ -31,25 +31,70 @@ export class ApiError extends Error {
     }
 }
 
+/** localStorage key for the creator account a team member is working on */
+const ACTIVE_CREATOR_KEY = 'activeCreatorId';
+
+/** The creator account requests act on as a team member, or null for the user's own account */
+export function getActiveCreatorId(): string | null {
+    return localStorage.getItem(ACTIVE_CREATOR_KEY);
+}
+
+/** Switch the account sent in X-Creator-Id; null goes back to the user's own account */
+export function setActiveCreatorId(creatorId: string | null) {
+    if (creatorId) {
+        localStorage.setItem(ACTIVE_CREATOR_KEY, creatorId);
+    } else {
+        localStorage.removeItem(ACTIVE_CREATOR_KEY);
+    }
+}
+
+/** In-flight session refresh, shared so concurrent 401s rotate the refresh token only once */
+let refreshing: Promise<boolean> | null = null;
+
+/** Exchange the refresh cookie for a new access token. Resolves false if the session is gone. */
+function refreshSession(): Promise<boolean> {
+    if (!refreshing) {
+        refreshing = fetch(`${API_BASE_URL}/auth/refresh`, {
+            method: 'POST',
+            credentials: 'include',
+        })
+            .then((res) => res.ok)
+            .catch(() => false)
+            .finally(() => {
+                refreshing = null;
+            });
+    }
+    return refreshing;
+}
+
 /**
  * Make an API request and return the unwrapped data.
- * Throws ApiError on server-reported errors.
+ * Throws ApiError on server-reported errors. An expired access token is renewed once
+ * with the refresh cookie and the request retried.
  */
 async function request<T>(
     endpoint: string,
-    options: RequestInit = {}
+    options: RequestInit = {},
+    retry = true
 ): Promise<ApiResponse<T>> {
     const url = `${API_BASE_URL}${endpoint}`;
+    const creatorId = getActiveCreatorId();
 
     const response = await fetch(url, {
         ...options,
         headers: {
             'Content-Type': 'application/json',
+            // Team routes act on this creator's account; the rest ignore it
+            ...(creatorId ? { 'X-Creator-Id': creatorId } : {}),
             ...options.headers,
         },
         credentials: 'include', // Send HTTP-only cookies
     });
 
+    if (response.status === 401 && retry && !endpoint.startsWith('/auth/refresh') && (await refreshSession())) {
+        return request<T>(endpoint, options, false);
+    }
+
     const body: ApiResponse<T> = await response.json();
 
     if (body.error) {
@@ -0,0 +1,15 @@
+import { api } from '../api';
+
+export interface CertificateVerification {
+    verification_id: string;
+    buyer_name: string;
+    creator_name: string;
+    course_title: string;
+    issued_at: string;
+}
+
+export async function verifyCertificate(verificationId: string) {
+    const response = await api.get<CertificateVerification>(`/certificates/${encodeURIComponent(verificationId)}`);
+    if (!response.data) throw new Error('Certificate not found');
+    return response.data;
+}
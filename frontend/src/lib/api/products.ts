@@ -71,6 +71,42 @@ export async function reorderProducts(items: ReorderItem[]) {
     await api.patch('/products/reorder', { product_ids: items.map(item => item.id) });
 }
 
+export interface ReplaceProductFileDTO {
+    file_key: string;
+    notes?: string;
+    notify_buyers?: boolean; // Email every buyer a link to the new version
+}
+
+export async function addProductFile(id: string, label: string, fileKey: string, notes?: string) {
+    const response = await api.post<Product>(`/products/${id}/files`, { label, file_key: fileKey, notes });
+    if (!response.data) throw new Error('Failed to add file');
+    return response.data;
+}
+
+export async function replaceProductFile(id: string, fileId: string, data: ReplaceProductFileDTO) {
+    const response = await api.post<Product>(`/products/${id}/files/${fileId}/versions`, data);
+    if (!response.data) throw new Error('Failed to upload new version');
+    return response.data;
+}
+
+export async function renameProductFile(id: string, fileId: string, label: string) {
+    const response = await api.put<Product>(`/products/${id}/files/${fileId}`, { label });
+    if (!response.data) throw new Error('Failed to rename file');
+    return response.data;
+}
+
+export async function removeProductFile(id: string, fileId: string) {
+    const response = await api.delete<Product>(`/products/${id}/files/${fileId}`);
+    if (!response.data) throw new Error('Failed to remove file');
+    return response.data;
+}
+
+export async function reorderProductFiles(id: string, fileIds: string[]) {
+    const response = await api.patch<Product>(`/products/${id}/files/reorder`, { file_ids: fileIds });
+    if (!response.data) throw new Error('Failed to reorder files');
+    return response.data;
+}
+
 export async function getPresignedUrl(filename: string, fileType: string, purpose: 'product_file' | 'cover_image' = 'product_file') {
     const response = await api.post<{ upload_url: string; file_key: string }>('/uploads/presigned', {
         file_name: filename,
@@ -81,6 +117,16 @@ export async function getPresignedUrl(filename: string, fileType: string, purpos
     return { url: response.data.upload_url, key: response.data.file_key };
 }
 
+// Asks the backend to verify an uploaded object (size, file type, virus scan).
+// Only verified uploads can be attached to a product.
+export async function completeUpload(fileKey: string) {
+    const response = await api.post<{ file_key: string; status: string; size: number }>('/uploads/complete', {
+        file_key: fileKey
+    });
+    if (!response.data) throw new Error('Failed to verify upload');
+    return response.data;
+}
+
 export async function uploadFileToUrl(url: string, file: File) {
     const response = await fetch(url, {
         method: 'PUT',
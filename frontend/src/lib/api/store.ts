@@ -5,6 +5,16 @@ export interface SocialLink {
     url: string;
 }
 
+export interface ResponsiveImage {
+    src: string;
+    width: number;
+    height: number;
+    srcset: string;
+    webp_srcset?: string;
+    thumbnail: string;
+    thumbnail_webp?: string;
+}
+
 export interface CreatorProfile {
     id: string;
     displayName: string;
@@ -15,6 +25,8 @@ export interface CreatorProfile {
     fontFamily?: string;
     coverPhotoUrl?: string;
     avatarUrl: string;
+    coverPhoto?: ResponsiveImage;
+    avatar?: ResponsiveImage;
     socialLinks: SocialLink[];
 }
 
@@ -29,6 +41,22 @@ export interface BumpConfig {
     bump_discount: number;
 }
 
+export interface ProductFileVersion {
+    version: number;
+    notes?: string;
+    released_at: string;
+    replaced_at: string;
+}
+
+export interface ProductFile {
+    id: string;
+    label: string;
+    version: number;
+    notes?: string;
+    released_at: string;
+    history?: ProductFileVersion[];
+}
+
 export interface Product {
     id: string;
     title: string;
@@ -36,6 +64,7 @@ export interface Product {
     price: number;
     description?: string;
     cover_image_url?: string;
+    cover_image?: ResponsiveImage;
     product_type: string;
     duration_minutes?: number;
     timezone?: string;
@@ -44,6 +73,7 @@ export interface Product {
     bump?: BumpConfig;
     // eslint-disable-next-line @typescript-eslint/no-explicit-any
     file_url?: string;
+    files?: ProductFile[];
     is_visible?: boolean;
     sort_order?: number;
     subscription_interval?: 'daily' | 'weekly' | 'monthly' | 'yearly';
@@ -0,0 +1,76 @@
+import React from 'react';
+import { useParams, Link } from 'react-router-dom';
+import { useQuery } from '@tanstack/react-query';
+import { verifyCertificate } from '../lib/api/certificates';
+import { Award, Loader2, AlertCircle } from 'lucide-react';
+
+const CertificateVerifyPage: React.FC = () => {
+    const { verificationId } = useParams<{ verificationId: string }>();
+
+    const { data: cert, isLoading, error } = useQuery({
+        queryKey: ['certificate', verificationId],
+        queryFn: () => verifyCertificate(verificationId!),
+        enabled: !!verificationId,
+        retry: false
+    });
+
+    if (isLoading) {
+        return (
+            <div className="min-h-screen bg-slate-50 flex items-center justify-center p-4">
+                <Loader2 className="w-8 h-8 animate-spin text-indigo-600" />
+            </div>
+        );
+    }
+
+    if (error || !cert) {
+        return (
+            <div className="min-h-screen bg-slate-50 flex flex-col items-center justify-center p-4 text-center">
+                <div className="w-16 h-16 bg-red-100 rounded-full flex items-center justify-center mb-4">
+                    <AlertCircle className="w-8 h-8 text-red-600" />
+                </div>
+                <h1 className="text-2xl font-bold text-slate-900 mb-2">Certificate Not Found</h1>
+                <p className="text-slate-600 mb-6">No certificate matches ID <span className="font-mono">{verificationId}</span>.</p>
+                <Link to="/" className="text-indigo-600 hover:text-indigo-800 font-medium">
+                    Return to Home
+                </Link>
+            </div>
+        );
+    }
+
+    return (
+        <div className="min-h-screen bg-slate-50 py-12 px-4">
+            <div className="max-w-xl mx-auto bg-white rounded-2xl shadow-sm border border-slate-100 overflow-hidden">
+                <div className="p-8 text-center bg-green-50">
+                    <div className="w-16 h-16 mx-auto rounded-full flex items-center justify-center mb-4 bg-green-100 text-green-600">
+                        <Award className="w-8 h-8" />
+                    </div>
+                    <h1 className="text-2xl font-bold text-slate-900 mb-2">Verified Certificate</h1>
+                    <p className="text-slate-600">
+                        Certificate ID: <span className="font-mono text-slate-800">{cert.verification_id}</span>
+                    </p>
+                </div>
+
+                <dl className="p-8 space-y-4">
+                    <div>
+                        <dt className="text-sm font-medium text-slate-500 uppercase tracking-wide">Awarded to</dt>
+                        <dd className="text-lg font-semibold text-slate-900">{cert.buyer_name}</dd>
+                    </div>
+                    <div>
+                        <dt className="text-sm font-medium text-slate-500 uppercase tracking-wide">Course</dt>
+                        <dd className="text-lg font-semibold text-slate-900">{cert.course_title}</dd>
+                    </div>
+                    <div>
+                        <dt className="text-sm font-medium text-slate-500 uppercase tracking-wide">Instructor</dt>
+                        <dd className="text-slate-800">{cert.creator_name}</dd>
+                    </div>
+                    <div>
+                        <dt className="text-sm font-medium text-slate-500 uppercase tracking-wide">Completed on</dt>
+                        <dd className="text-slate-800">{new Date(cert.issued_at).toLocaleDateString(undefined, { dateStyle: 'long' })}</dd>
+                    </div>
+                </dl>
+            </div>
+        </div>
+    );
+};
+
+export default CertificateVerifyPage;
@@ -1,11 +1,16 @@
 import React from 'react';
-import { useParams, Link } from 'react-router-dom';
+import { useParams, useSearchParams, Link } from 'react-router-dom';
 import { useQuery } from '@tanstack/react-query';
-import { getOrder, getOrderDownloadUrl } from '../features/orders/api';
+import { getOrder, getOrderDownloadUrl, getOrderFiles } from '../features/orders/api';
+import { useAuth } from '../context/AuthContext';
 import { CheckCircle, Download, FileText, Loader2, AlertCircle, Calendar, Video } from 'lucide-react';
 
 const OrderPage: React.FC = () => {
     const { orderId } = useParams<{ orderId: string }>();
+    // Signed token from the checkout redirect or the confirmation email
+    const token = useSearchParams()[0].get('token') || undefined;
+    const { user } = useAuth();
+    const canDownload = !!token || user?.role === 'buyer';
 
     const { data: order, isLoading, error } = useQuery({
         queryKey: ['order', orderId],
@@ -14,13 +19,20 @@ const OrderPage: React.FC = () => {
         retry: 1
     });
 
-    const handleDownload = async (productId?: string) => {
+    const { data: productFiles } = useQuery({
+        queryKey: ['order-files', orderId, token],
+        queryFn: () => getOrderFiles(orderId!, token),
+        enabled: !!orderId && order?.status === 'paid' && canDownload,
+        retry: false
+    });
+
+    const handleDownload = async (productId?: string, fileId?: string) => {
         try {
-            const url = await getOrderDownloadUrl(orderId!, productId);
+            const url = await getOrderDownloadUrl(orderId!, productId, fileId, token);
             window.open(url, '_blank');
         } catch (err) {
             console.error('Download failed', err);
-            alert('Failed to generate download link. Please try again.');
+            alert(err instanceof Error && err.message ? err.message : 'Failed to generate download link. Please try again.');
         }
     };
 
@@ -86,7 +98,15 @@ const OrderPage: React.FC = () => {
                                                 </p>
                                             </div>
                                         </div>
-                                        {isPaid && (
+                                        {isPaid && item.product_type !== 'booking' && !canDownload && (
+                                            <Link
+                                                to="/buyer/login"
+                                                className="w-full sm:w-auto text-center text-sm font-medium text-indigo-600 hover:text-indigo-800"
+                                            >
+                                                Sign in to download
+                                            </Link>
+                                        )}
+                                        {isPaid && (item.product_type === 'booking' || canDownload) && (
                                             <div className="w-full sm:w-auto mt-2 sm:mt-0">
                                                 {item.product_type === 'booking' ? (
                                                     <div className="text-sm text-green-700 bg-green-50 px-3 py-2 rounded-lg border border-green-100 text-center">
@@ -108,6 +128,22 @@ const OrderPage: React.FC = () => {
                                                             </a>
                                                         )}
                                                     </div>
+                                                ) : (productFiles?.[item.product_id]?.length ?? 0) > 1 ? (
+                                                    <div className="flex flex-col gap-2">
+                                                        {productFiles![item.product_id].map((file) => (
+                                                            <button
+                                                                key={file.id}
+                                                                onClick={() => handleDownload(item.product_id, file.id)}
+                                                                className="w-full sm:w-auto flex items-center justify-between gap-3 bg-indigo-600 hover:bg-indigo-700 text-white font-medium py-2 px-4 rounded-lg transition-colors text-sm shadow-sm"
+                                                            >
+                                                                <span className="flex items-center gap-2">
+                                                                    <Download className="w-4 h-4" />
+                                                                    {file.label}
+                                                                </span>
+                                                                {file.version > 1 && <span className="text-xs text-indigo-200">v{file.version}</span>}
+                                                            </button>
+                                                        ))}
+                                                    </div>
                                                 ) : (
                                                     <button
                                                         onClick={() => handleDownload(item.product_id)}
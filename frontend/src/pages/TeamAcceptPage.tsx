@@ -0,0 +1,86 @@
+import { useEffect, useRef, useState } from 'react';
+import { Link, Navigate, useLocation, useSearchParams } from 'react-router-dom';
+import { useAuth } from '../context/AuthContext';
+import { api, ApiError, setActiveCreatorId } from '../lib/api';
+import { MioLogo } from '../components/brand/MioLogo';
+import { Loader2, Users, AlertCircle } from 'lucide-react';
+
+// Landing page for the link in a team invite email (/team/accept?token=...). The invite is
+// accepted by the signed-in user, so visitors are sent to sign in first and brought back.
+export default function TeamAcceptPage() {
+    const { isAuthenticated, isLoading, user } = useAuth();
+    const location = useLocation();
+    const [searchParams] = useSearchParams();
+    const token = searchParams.get('token') || '';
+    const [creatorId, setCreatorId] = useState<string | null>(null);
+    const [error, setError] = useState('');
+    // An invite token works once; don't spend it twice when effects re-run
+    const requested = useRef(false);
+
+    useEffect(() => {
+        if (!isAuthenticated || !token || requested.current) return;
+        requested.current = true;
+        api.post<{ creator_id: string }>('/team/invitations/accept', { token })
+            .then(res => setCreatorId(res.data?.creator_id || null))
+            .catch(err => setError(err instanceof ApiError ? err.message : 'Failed to accept the invite'));
+    }, [isAuthenticated, token]);
+
+    if (isLoading) {
+        return (
+            <div className="flex min-h-screen items-center justify-center bg-[#f8f9ff] dark:bg-[#0f111a]">
+                <Loader2 className="w-8 h-8 animate-spin text-[#6786f5]" />
+            </div>
+        );
+    }
+
+    if (!isAuthenticated) {
+        return <Navigate to="/login" state={{ from: location }} replace />;
+    }
+
+    const openAccount = () => {
+        if (creatorId) setActiveCreatorId(creatorId);
+        window.location.href = '/dashboard';
+    };
+
+    const failed = !token || !!error;
+
+    return (
+        <div className="flex min-h-screen items-center justify-center bg-[#f8f9ff] dark:bg-[#0f111a] px-4 py-12">
+            <div className="w-full max-w-md space-y-8 rounded-2xl bg-white dark:bg-[#1a1d2b] p-10 shadow-xl border border-gray-100 dark:border-gray-800 text-center">
+                <div className="flex flex-col items-center gap-3">
+                    <MioLogo size="lg" />
+                    <div className={`w-16 h-16 rounded-full flex items-center justify-center mx-auto ${failed ? 'bg-red-50 dark:bg-red-500/10' : 'bg-[#6786f51a]'}`}>
+                        {failed ? <AlertCircle className="w-8 h-8 text-red-600" /> : <Users className="w-8 h-8 text-[#6786f5]" />}
+                    </div>
+                    <h2 className="text-xl font-bold text-gray-900 dark:text-white" style={{ fontFamily: "'Lexend', sans-serif" }}>
+                        {failed ? "We couldn't add you to the team" : creatorId ? "You've joined the team" : 'Joining the team…'}
+                    </h2>
+                    <p className="text-sm text-gray-500 dark:text-gray-400">
+                        {!token
+                            ? 'This invite link is incomplete. Ask the creator to send a new invite.'
+                            : error
+                                ? <>{error.charAt(0).toUpperCase() + error.slice(1)}. You are signed in as <span className="font-medium text-gray-700 dark:text-gray-200">{user?.email}</span>, and invites only work for the email they were sent to.</>
+                                : creatorId
+                                    ? 'Switch between your own account and the accounts you work on from the dashboard sidebar.'
+                                    : 'Hang on while we accept your invite.'}
+                    </p>
+                </div>
+
+                {creatorId && (
+                    <button
+                        onClick={openAccount}
+                        className="w-full flex items-center justify-center gap-2 rounded-xl bg-[#6786f5] px-6 py-3 text-base font-semibold text-white shadow-sm transition-all hover:bg-[#5570e0]"
+                    >
+                        Open their dashboard
+                    </button>
+                )}
+                {failed && (
+                    <Link to="/dashboard" className="block text-sm text-gray-500 hover:text-gray-700 dark:text-gray-400 dark:hover:text-gray-200">
+                        Go to your dashboard
+                    </Link>
+                )}
+                {!failed && !creatorId && <Loader2 className="w-6 h-6 mx-auto animate-spin text-[#6786f5]" />}
+            </div>
+        </div>
+    );
+}
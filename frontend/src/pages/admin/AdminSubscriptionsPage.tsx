@@ -1,8 +1,9 @@
 import React, { useEffect, useState } from 'react';
 import { useAuth } from '../../context/AuthContext';
-import { api } from '../../lib/api';
+import { api, ApiError } from '../../lib/api';
+import { StepUpPrompt } from '../../components/account/StepUpPrompt';
 import { Navigate, Link } from 'react-router-dom';
-import { Users, TrendingUp, UserX, Clock, Search, ChevronLeft, ChevronRight, Crown, UserPlus, Shield, XCircle } from 'lucide-react';
+import { Users, TrendingUp, UserX, Clock, Search, ChevronLeft, ChevronRight, Crown, UserPlus, Shield, XCircle, Eye } from 'lucide-react';
 
 interface SubscriptionAnalytics {
   total_active: number;
@@ -51,6 +52,8 @@ const AdminSubscriptionsPage: React.FC = () => {
   const [grantMonths, setGrantMonths] = useState(1);
   const [addEmail, setAddEmail] = useState('');
   const [addName, setAddName] = useState('');
+  // Impersonation waiting on the admin to confirm it's them (ERR_STEP_UP_REQUIRED)
+  const [pendingImpersonation, setPendingImpersonation] = useState<{ creatorId: string; reason: string } | null>(null);
   const [actionLoading, setActionLoading] = useState(false);
 
   if (authLoading) return <div className="flex justify-center items-center h-screen">Loading...</div>;
@@ -110,6 +113,26 @@ const AdminSubscriptionsPage: React.FC = () => {
     }
   };
 
+  const startImpersonation = async (creatorId: string, reason: string) => {
+    try {
+      await api.post(`/admin/creators/${creatorId}/impersonate`, { reason });
+      // The impersonation cookie now replaces the admin session until it is stopped or expires
+      window.location.href = '/dashboard';
+    } catch (err: any) {
+      if (err instanceof ApiError && err.code === 'ERR_STEP_UP_REQUIRED') {
+        setPendingImpersonation({ creatorId, reason });
+      } else {
+        alert(err?.message || 'Failed to start impersonation');
+      }
+    }
+  };
+
+  const handleImpersonate = async (creatorId: string) => {
+    const reason = prompt('Why do you need to view this account? (recorded in the audit log)');
+    if (!reason?.trim()) return;
+    await startImpersonation(creatorId, reason);
+  };
+
   const handleAddCreator = async () => {
     setActionLoading(true);
     try {
@@ -272,6 +295,12 @@ const AdminSubscriptionsPage: React.FC = () => {
                           : '-'}
                       </td>
                       <td className="py-3 px-4 text-right">
+                        <button
+                          onClick={() => handleImpersonate(sub.creator_id)}
+                          className="text-gray-600 hover:text-gray-900 text-xs font-medium flex items-center gap-1 ml-auto mb-1"
+                        >
+                          <Eye className="w-3.5 h-3.5" /> View as creator
+                        </button>
                         {(sub.status === 'active' || sub.status === 'trial') && (
                           <button
                             onClick={() => handleRevoke(sub.creator_id)}
@@ -362,6 +391,23 @@ const AdminSubscriptionsPage: React.FC = () => {
       )}
 
       {/* Add Creator Modal */}
+      {pendingImpersonation && (
+        <div className="fixed inset-0 bg-black/50 flex items-center justify-center z-50 p-4" onClick={() => setPendingImpersonation(null)}>
+          <div className="bg-white rounded-xl shadow-xl max-w-md w-full p-6" onClick={e => e.stopPropagation()}>
+            <h3 className="text-lg font-bold mb-4">Confirm it's you</h3>
+            <StepUpPrompt
+              confirmLabel="View account"
+              onConfirmed={() => {
+                const { creatorId, reason } = pendingImpersonation;
+                setPendingImpersonation(null);
+                startImpersonation(creatorId, reason);
+              }}
+              onCancel={() => setPendingImpersonation(null)}
+            />
+          </div>
+        </div>
+      )}
+
       {showAddModal && (
         <div className="fixed inset-0 bg-black/50 flex items-center justify-center z-50 p-4" onClick={() => setShowAddModal(false)}>
           <div className="bg-white rounded-xl shadow-xl max-w-md w-full p-6" onClick={e => e.stopPropagation()}>
@@ -0,0 +1,85 @@
+import { useState } from 'react';
+import { Link } from 'react-router-dom';
+import { api, ApiError } from '../../lib/api';
+import { MioLogo } from '../../components/brand/MioLogo';
+import { Loader2, Mail, ArrowLeft, KeyRound } from 'lucide-react';
+
+export default function ForgotPasswordPage() {
+    const [email, setEmail] = useState('');
+    const [isLoading, setIsLoading] = useState(false);
+    const [sent, setSent] = useState(false);
+    const [error, setError] = useState('');
+
+    const handleSubmit = async (e: React.FormEvent) => {
+        e.preventDefault();
+        setError('');
+        setIsLoading(true);
+        try {
+            await api.post('/auth/password/forgot', { email });
+            setSent(true);
+        } catch (err) {
+            setError(err instanceof ApiError ? err.message : 'Failed to send reset link');
+        } finally {
+            setIsLoading(false);
+        }
+    };
+
+    return (
+        <div className="flex min-h-screen items-center justify-center bg-[#f8f9ff] dark:bg-[#0f111a] px-4 py-12">
+            <div className="w-full max-w-md space-y-8 rounded-2xl bg-white dark:bg-[#1a1d2b] p-10 shadow-xl border border-gray-100 dark:border-gray-800">
+                <div className="text-center flex flex-col items-center gap-3">
+                    <MioLogo size="lg" />
+                    <div className="w-16 h-16 bg-[#6786f51a] rounded-full flex items-center justify-center mx-auto">
+                        <KeyRound className="w-8 h-8 text-[#6786f5]" />
+                    </div>
+                    <h2 className="text-xl font-bold text-gray-900 dark:text-white" style={{ fontFamily: "'Lexend', sans-serif" }}>
+                        Forgot your password?
+                    </h2>
+                    <p className="text-sm text-gray-500 dark:text-gray-400">
+                        {sent
+                            ? <>If an account exists for <span className="font-medium text-gray-700 dark:text-gray-200">{email}</span>, we've sent a link to reset its password. The link expires in 30 minutes.</>
+                            : "Enter your email and we'll send you a link to choose a new one."}
+                    </p>
+                </div>
+
+                {error && (
+                    <div className="p-3 bg-red-50 dark:bg-red-500/10 text-red-700 dark:text-red-400 rounded-xl text-sm text-center">
+                        {error}
+                    </div>
+                )}
+
+                {!sent && (
+                    <form onSubmit={handleSubmit} className="space-y-4">
+                        <div className="relative">
+                            <Mail className="absolute left-3.5 top-1/2 -translate-y-1/2 w-5 h-5 text-gray-400" />
+                            <input
+                                type="email"
+                                required
+                                autoFocus
+                                value={email}
+                                onChange={(e) => setEmail(e.target.value)}
+                                placeholder="Email address"
+                                className="w-full pl-11 pr-4 py-3 rounded-xl border border-gray-200 dark:border-gray-700 bg-white dark:bg-[#252838] text-gray-900 dark:text-white placeholder-gray-400 focus:border-[#6786f5] focus:outline-none focus:ring-2 focus:ring-[#6786f5]/20"
+                            />
+                        </div>
+                        <button
+                            type="submit"
+                            disabled={isLoading}
+                            className="w-full flex items-center justify-center gap-2 rounded-xl bg-[#6786f5] px-6 py-3 text-base font-semibold text-white shadow-sm transition-all hover:bg-[#5570e0] disabled:opacity-50"
+                        >
+                            {isLoading ? <Loader2 className="w-5 h-5 animate-spin" /> : 'Send reset link'}
+                        </button>
+                    </form>
+                )}
+
+                <Link
+                    to="/login"
+                    className="flex items-center justify-center gap-2 text-sm text-gray-500 hover:text-gray-700 dark:text-gray-400 dark:hover:text-gray-200"
+                >
+                    <ArrowLeft className="w-4 h-4" />
+                    Back to sign in
+                </Link>
+            </div>
+        </div>
+    );
+}
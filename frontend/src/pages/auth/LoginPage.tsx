@@ -1,14 +1,16 @@
 import { useState, useEffect } from 'react';
-import { useLocation, useNavigate } from 'react-router-dom';
+import { Link, useLocation, useNavigate, useSearchParams } from 'react-router-dom';
 import { useAuth } from '../../context/AuthContext';
 import { api } from '../../lib/api';
 import { MioLogo } from '../../components/brand/MioLogo';
+import { TwoFactorChallenge } from '../../components/auth/TwoFactorChallenge';
 import { Loader2, Mail, Lock, Eye, EyeOff, ArrowRight, Zap, TrendingUp } from 'lucide-react';
 
 export default function LoginPage() {
     const { isAuthenticated, user, checkAuth } = useAuth();
     const location = useLocation();
     const navigate = useNavigate();
+    const [searchParams, setSearchParams] = useSearchParams();
     const [mode, setMode] = useState<'login' | 'signup'>('signup');
     const [email, setEmail] = useState('');
     const [password, setPassword] = useState('');
@@ -16,14 +18,38 @@ export default function LoginPage() {
     const [showPassword, setShowPassword] = useState(false);
     const [isLoading, setIsLoading] = useState(false);
     const [error, setError] = useState('');
+    // Accounts with 2FA get a challenge instead of a session; Google and magic link sign-ins
+    // arrive here as /login?twoFactor=verify|setup&challenge=...
+    const [twoFactor, setTwoFactor] = useState<{ step: 'verify' | 'setup'; challenge: string } | null>(() => {
+        const challenge = searchParams.get('challenge');
+        if (!challenge) return null;
+        return { step: searchParams.get('twoFactor') === 'setup' ? 'setup' : 'verify', challenge };
+    });
+    const [twoFactorRedirect, setTwoFactorRedirect] = useState('');
 
     // Redirect if already logged in
     useEffect(() => {
         if (isAuthenticated && user) {
-            const from = (location.state as any)?.from?.pathname || (user.username ? '/dashboard' : '/onboarding');
+            // Keep the query string so links like /team/accept?token=... survive signing in
+            const fromLocation = (location.state as any)?.from;
+            const from = fromLocation?.pathname
+                ? fromLocation.pathname + (fromLocation.search || '')
+                : twoFactorRedirect || (user.username ? '/dashboard' : '/onboarding');
             navigate(from, { replace: true });
         }
-    }, [isAuthenticated, user, navigate, location.state]);
+    }, [isAuthenticated, user, navigate, location.state, twoFactorRedirect]);
+
+    const completeTwoFactor = async (redirectUrl: string) => {
+        setTwoFactorRedirect(redirectUrl);
+        await checkAuth();
+    };
+
+    const restartLogin = () => {
+        setTwoFactor(null);
+        setPassword('');
+        setMode('login');
+        setSearchParams({}, { replace: true });
+    };
 
     const handleGoogleLogin = () => {
         window.location.href = '/api/v1/auth/google';
@@ -41,16 +67,25 @@ export default function LoginPage() {
                     setIsLoading(false);
                     return;
                 }
-                if (password.length < 8) {
-                    setError('Password must be at least 8 characters');
+                if (password.length < 10) {
+                    setError('Password must be at least 10 characters');
                     setIsLoading(false);
                     return;
                 }
                 await api.post('/auth/creator/signup', { email, password });
                 navigate('/verify-otp', { state: { email } });
             } else {
-                const res = await api.post<{ user: any; token: string; redirectUrl: string }>('/auth/creator/login', { email, password });
-                if (res.data?.redirectUrl) {
+                const res = await api.post<{
+                    user: any;
+                    token: string;
+                    redirectUrl: string;
+                    twoFactorRequired?: boolean;
+                    twoFactorSetupRequired?: boolean;
+                    challengeToken?: string;
+                }>('/auth/creator/login', { email, password });
+                if (res.data?.twoFactorRequired && res.data.challengeToken) {
+                    setTwoFactor({ step: res.data.twoFactorSetupRequired ? 'setup' : 'verify', challenge: res.data.challengeToken });
+                } else if (res.data?.redirectUrl) {
                     await checkAuth();
                     // The useEffect above will handle the actual redirection once checkAuth finishes
                 }
@@ -130,136 +165,156 @@ export default function LoginPage() {
 
                     <div>
                         <h2 className="text-2xl font-bold text-gray-900 dark:text-white" style={{ fontFamily: "'Lexend', sans-serif" }}>
-                            {mode === 'signup' ? 'Create your store' : 'Welcome back'}
+                            {twoFactor ? 'Two-factor authentication' : mode === 'signup' ? 'Create your store' : 'Welcome back'}
                         </h2>
                         <p className="mt-2 text-gray-500 dark:text-gray-400">
-                            {mode === 'signup' ? 'Start your journey as an independent creator' : 'Sign in to manage your store'}
+                            {twoFactor
+                                ? (twoFactor.step === 'setup' ? 'Set up an authenticator to finish signing in' : 'One more step to sign in')
+                                : mode === 'signup' ? 'Start your journey as an independent creator' : 'Sign in to manage your store'}
                         </p>
                     </div>
 
-                    <div className="space-y-4">
-                        {/* Google Auth Button */}
-                        <button
-                            onClick={handleGoogleLogin}
-                            className="flex w-full items-center justify-center gap-3 rounded-xl border border-gray-200 dark:border-gray-700 bg-white dark:bg-[#1a1d2b] px-6 py-3 text-sm font-medium text-gray-700 dark:text-gray-200 shadow-sm transition-all hover:bg-gray-50 dark:hover:bg-[#252838] focus:outline-none focus:ring-2 focus:ring-[#6786f5] focus:ring-offset-2"
-                        >
-                            <svg className="h-5 w-5" viewBox="0 0 24 24">
-                                <path d="M22.56 12.25c0-.78-.07-1.53-.2-2.25H12v4.26h5.92c-.26 1.37-1.04 2.53-2.21 3.31v2.77h3.57c2.08-1.92 3.28-4.74 3.28-8.09z" fill="#4285F4" />
-                                <path d="M12 23c2.97 0 5.46-.98 7.28-2.66l-3.57-2.77c-.98.66-2.23 1.06-3.71 1.06-2.86 0-5.29-1.93-6.16-4.53H2.18v2.84C3.99 20.53 7.7 23 12 23z" fill="#34A853" />
-                                <path d="M5.84 14.09c-.22-.66-.35-1.36-.35-2.09s.13-1.43.35-2.09V7.07H2.18C1.43 8.55 1 10.22 1 12s.43 3.45 1.18 4.93l2.85-2.22.81-.62z" fill="#FBBC05" />
-                                <path d="M12 5.38c1.62 0 3.06.56 4.21 1.64l3.15-3.15C17.45 2.09 14.97 1 12 1 7.7 1 3.99 3.47 2.18 7.07l3.66 2.84c.87-2.6 3.3-4.53 6.16-4.53z" fill="#EA4335" />
-                            </svg>
-                            Continue with Google
-                        </button>
-
-                        {/* Divider */}
-                        <div className="relative">
-                            <div className="absolute inset-0 flex items-center">
-                                <div className="w-full border-t border-gray-200 dark:border-gray-700" />
-                            </div>
-                            <div className="relative flex justify-center text-xs uppercase tracking-wider">
-                                <span className="bg-white dark:bg-[#0f111a] px-4 text-gray-400">or email</span>
-                            </div>
-                        </div>
-
-                        {/* Error */}
-                        {error && (
-                            <div className="p-3 bg-red-50 dark:bg-red-500/10 text-red-700 dark:text-red-400 rounded-xl text-sm">
-                                {error}
-                            </div>
-                        )}
+                    {twoFactor ? (
+                        <TwoFactorChallenge
+                            key={twoFactor.challenge}
+                            challengeToken={twoFactor.challenge}
+                            setup={twoFactor.step === 'setup'}
+                            onComplete={completeTwoFactor}
+                            onRestart={restartLogin}
+                        />
+                    ) : (
+                        <div className="space-y-4">
+                            {/* Google Auth Button */}
+                            <button
+                                onClick={handleGoogleLogin}
+                                className="flex w-full items-center justify-center gap-3 rounded-xl border border-gray-200 dark:border-gray-700 bg-white dark:bg-[#1a1d2b] px-6 py-3 text-sm font-medium text-gray-700 dark:text-gray-200 shadow-sm transition-all hover:bg-gray-50 dark:hover:bg-[#252838] focus:outline-none focus:ring-2 focus:ring-[#6786f5] focus:ring-offset-2"
+                            >
+                                <svg className="h-5 w-5" viewBox="0 0 24 24">
+                                    <path d="M22.56 12.25c0-.78-.07-1.53-.2-2.25H12v4.26h5.92c-.26 1.37-1.04 2.53-2.21 3.31v2.77h3.57c2.08-1.92 3.28-4.74 3.28-8.09z" fill="#4285F4" />
+                                    <path d="M12 23c2.97 0 5.46-.98 7.28-2.66l-3.57-2.77c-.98.66-2.23 1.06-3.71 1.06-2.86 0-5.29-1.93-6.16-4.53H2.18v2.84C3.99 20.53 7.7 23 12 23z" fill="#34A853" />
+                                    <path d="M5.84 14.09c-.22-.66-.35-1.36-.35-2.09s.13-1.43.35-2.09V7.07H2.18C1.43 8.55 1 10.22 1 12s.43 3.45 1.18 4.93l2.85-2.22.81-.62z" fill="#FBBC05" />
+                                    <path d="M12 5.38c1.62 0 3.06.56 4.21 1.64l3.15-3.15C17.45 2.09 14.97 1 12 1 7.7 1 3.99 3.47 2.18 7.07l3.66 2.84c.87-2.6 3.3-4.53 6.16-4.53z" fill="#EA4335" />
+                                </svg>
+                                Continue with Google
+                            </button>
 
-                        {/* Form */}
-                        <form onSubmit={handleEmailSubmit} className="space-y-4">
+                            {/* Divider */}
                             <div className="relative">
-                                <Mail className="absolute left-3.5 top-1/2 -translate-y-1/2 w-5 h-5 text-gray-400" />
-                                <input
-                                    type="email"
-                                    required
-                                    value={email}
-                                    onChange={(e) => setEmail(e.target.value)}
-                                    placeholder="Email address"
-                                    className="w-full pl-11 pr-4 py-3 rounded-xl border border-gray-200 dark:border-gray-700 bg-white dark:bg-[#1a1d2b] text-gray-900 dark:text-white placeholder-gray-400 focus:border-[#6786f5] focus:outline-none focus:ring-2 focus:ring-[#6786f5]/20"
-                                />
+                                <div className="absolute inset-0 flex items-center">
+                                    <div className="w-full border-t border-gray-200 dark:border-gray-700" />
+                                </div>
+                                <div className="relative flex justify-center text-xs uppercase tracking-wider">
+                                    <span className="bg-white dark:bg-[#0f111a] px-4 text-gray-400">or email</span>
+                                </div>
                             </div>
 
-                            <div className="relative">
-                                <Lock className="absolute left-3.5 top-1/2 -translate-y-1/2 w-5 h-5 text-gray-400" />
-                                <input
-                                    type={showPassword ? 'text' : 'password'}
-                                    required
-                                    value={password}
-                                    onChange={(e) => setPassword(e.target.value)}
-                                    placeholder="Password"
-                                    minLength={8}
-                                    className="w-full pl-11 pr-12 py-3 rounded-xl border border-gray-200 dark:border-gray-700 bg-white dark:bg-[#1a1d2b] text-gray-900 dark:text-white placeholder-gray-400 focus:border-[#6786f5] focus:outline-none focus:ring-2 focus:ring-[#6786f5]/20"
-                                />
-                                <button
-                                    type="button"
-                                    onClick={() => setShowPassword(!showPassword)}
-                                    className="absolute right-3.5 top-1/2 -translate-y-1/2 text-gray-400 hover:text-gray-600 dark:hover:text-gray-300"
-                                >
-                                    {showPassword ? <EyeOff className="w-5 h-5" /> : <Eye className="w-5 h-5" />}
-                                </button>
-                            </div>
+                            {/* Error */}
+                            {error && (
+                                <div className="p-3 bg-red-50 dark:bg-red-500/10 text-red-700 dark:text-red-400 rounded-xl text-sm">
+                                    {error}
+                                </div>
+                            )}
+
+                            {/* Form */}
+                            <form onSubmit={handleEmailSubmit} className="space-y-4">
+                                <div className="relative">
+                                    <Mail className="absolute left-3.5 top-1/2 -translate-y-1/2 w-5 h-5 text-gray-400" />
+                                    <input
+                                        type="email"
+                                        required
+                                        value={email}
+                                        onChange={(e) => setEmail(e.target.value)}
+                                        placeholder="Email address"
+                                        className="w-full pl-11 pr-4 py-3 rounded-xl border border-gray-200 dark:border-gray-700 bg-white dark:bg-[#1a1d2b] text-gray-900 dark:text-white placeholder-gray-400 focus:border-[#6786f5] focus:outline-none focus:ring-2 focus:ring-[#6786f5]/20"
+                                    />
+                                </div>
 
-                            {mode === 'signup' && (
                                 <div className="relative">
                                     <Lock className="absolute left-3.5 top-1/2 -translate-y-1/2 w-5 h-5 text-gray-400" />
                                     <input
                                         type={showPassword ? 'text' : 'password'}
                                         required
-                                        value={confirmPassword}
-                                        onChange={(e) => setConfirmPassword(e.target.value)}
-                                        placeholder="Confirm password"
-                                        minLength={8}
-                                        className="w-full pl-11 pr-4 py-3 rounded-xl border border-gray-200 dark:border-gray-700 bg-white dark:bg-[#1a1d2b] text-gray-900 dark:text-white placeholder-gray-400 focus:border-[#6786f5] focus:outline-none focus:ring-2 focus:ring-[#6786f5]/20"
+                                        value={password}
+                                        onChange={(e) => setPassword(e.target.value)}
+                                        placeholder="Password"
+                                        minLength={mode === 'signup' ? 10 : undefined}
+                                        className="w-full pl-11 pr-12 py-3 rounded-xl border border-gray-200 dark:border-gray-700 bg-white dark:bg-[#1a1d2b] text-gray-900 dark:text-white placeholder-gray-400 focus:border-[#6786f5] focus:outline-none focus:ring-2 focus:ring-[#6786f5]/20"
                                     />
+                                    <button
+                                        type="button"
+                                        onClick={() => setShowPassword(!showPassword)}
+                                        className="absolute right-3.5 top-1/2 -translate-y-1/2 text-gray-400 hover:text-gray-600 dark:hover:text-gray-300"
+                                    >
+                                        {showPassword ? <EyeOff className="w-5 h-5" /> : <Eye className="w-5 h-5" />}
+                                    </button>
                                 </div>
+
+                                {mode === 'login' && (
+                                    <div className="text-right -mt-2">
+                                        <Link to="/forgot-password" className="text-sm text-[#6786f5] hover:underline">
+                                            Forgot password?
+                                        </Link>
+                                    </div>
+                                )}
+
+                                {mode === 'signup' && (
+                                    <div className="relative">
+                                        <Lock className="absolute left-3.5 top-1/2 -translate-y-1/2 w-5 h-5 text-gray-400" />
+                                        <input
+                                            type={showPassword ? 'text' : 'password'}
+                                            required
+                                            value={confirmPassword}
+                                            onChange={(e) => setConfirmPassword(e.target.value)}
+                                            placeholder="Confirm password"
+                                            minLength={mode === 'signup' ? 10 : undefined}
+                                            className="w-full pl-11 pr-4 py-3 rounded-xl border border-gray-200 dark:border-gray-700 bg-white dark:bg-[#1a1d2b] text-gray-900 dark:text-white placeholder-gray-400 focus:border-[#6786f5] focus:outline-none focus:ring-2 focus:ring-[#6786f5]/20"
+                                        />
+                                    </div>
+                                )}
+
+                                <button
+                                    type="submit"
+                                    disabled={isLoading}
+                                    className="w-full flex items-center justify-center gap-2 rounded-xl bg-[#6786f5] px-6 py-3 text-base font-semibold text-white shadow-sm transition-all hover:bg-[#5570e0] disabled:opacity-50 focus:outline-none focus:ring-2 focus:ring-[#6786f5] focus:ring-offset-2"
+                                >
+                                    {isLoading ? (
+                                        <Loader2 className="w-5 h-5 animate-spin" />
+                                    ) : (
+                                        <>
+                                            {mode === 'signup' ? 'Sign up as Creator' : 'Sign In'}
+                                            <ArrowRight className="w-4 h-4" />
+                                        </>
+                                    )}
+                                </button>
+                            </form>
+
+                            {/* Terms (signup only) */}
+                            {mode === 'signup' && (
+                                <p className="text-xs text-center text-gray-400">
+                                    By signing up, you agree to our{' '}
+                                    <a href="#" className="text-[#6786f5] hover:underline">Terms of Service</a> and{' '}
+                                    <a href="#" className="text-[#6786f5] hover:underline">Privacy Policy</a>.
+                                </p>
                             )}
 
-                            <button
-                                type="submit"
-                                disabled={isLoading}
-                                className="w-full flex items-center justify-center gap-2 rounded-xl bg-[#6786f5] px-6 py-3 text-base font-semibold text-white shadow-sm transition-all hover:bg-[#5570e0] disabled:opacity-50 focus:outline-none focus:ring-2 focus:ring-[#6786f5] focus:ring-offset-2"
-                            >
-                                {isLoading ? (
-                                    <Loader2 className="w-5 h-5 animate-spin" />
+                            {/* Toggle */}
+                            <p className="text-center text-sm text-gray-500 dark:text-gray-400">
+                                {mode === 'login' ? (
+                                    <>Don't have an account?{' '}
+                                        <button type="button" onClick={() => { setMode('signup'); setError(''); }} className="text-[#6786f5] font-semibold hover:underline">
+                                            Sign up
+                                        </button>
+                                    </>
                                 ) : (
-                                    <>
-                                        {mode === 'signup' ? 'Sign up as Creator' : 'Sign In'}
-                                        <ArrowRight className="w-4 h-4" />
+                                    <>Already have an account?{' '}
+                                        <button type="button" onClick={() => { setMode('login'); setError(''); }} className="text-[#6786f5] font-semibold hover:underline">
+                                            Sign in
+                                        </button>
                                     </>
                                 )}
-                            </button>
-                        </form>
-
-                        {/* Terms (signup only) */}
-                        {mode === 'signup' && (
-                            <p className="text-xs text-center text-gray-400">
-                                By signing up, you agree to our{' '}
-                                <a href="#" className="text-[#6786f5] hover:underline">Terms of Service</a> and{' '}
-                                <a href="#" className="text-[#6786f5] hover:underline">Privacy Policy</a>.
                             </p>
-                        )}
-
-                        {/* Toggle */}
-                        <p className="text-center text-sm text-gray-500 dark:text-gray-400">
-                            {mode === 'login' ? (
-                                <>Don't have an account?{' '}
-                                    <button type="button" onClick={() => { setMode('signup'); setError(''); }} className="text-[#6786f5] font-semibold hover:underline">
-                                        Sign up
-                                    </button>
-                                </>
-                            ) : (
-                                <>Already have an account?{' '}
-                                    <button type="button" onClick={() => { setMode('login'); setError(''); }} className="text-[#6786f5] font-semibold hover:underline">
-                                        Sign in
-                                    </button>
-                                </>
-                            )}
-                        </p>
-                    </div>
+                        </div>
+                    )}
                 </div>
             </div>
         </div>
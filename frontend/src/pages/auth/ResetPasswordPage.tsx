@@ -0,0 +1,136 @@
+import { useState } from 'react';
+import { Link, useSearchParams } from 'react-router-dom';
+import { api, ApiError } from '../../lib/api';
+import { MioLogo } from '../../components/brand/MioLogo';
+import { Loader2, Lock, Eye, EyeOff, ArrowLeft, KeyRound } from 'lucide-react';
+
+// Landing page for the link in the password reset email (/reset-password?token=...)
+export default function ResetPasswordPage() {
+    const [searchParams] = useSearchParams();
+    const token = searchParams.get('token') || '';
+    const [password, setPassword] = useState('');
+    const [confirmPassword, setConfirmPassword] = useState('');
+    const [showPassword, setShowPassword] = useState(false);
+    const [isLoading, setIsLoading] = useState(false);
+    const [done, setDone] = useState(false);
+    const [error, setError] = useState('');
+
+    const handleSubmit = async (e: React.FormEvent) => {
+        e.preventDefault();
+        setError('');
+        if (password !== confirmPassword) {
+            setError('Passwords do not match');
+            return;
+        }
+        if (password.length < 10) {
+            setError('Password must be at least 10 characters');
+            return;
+        }
+
+        setIsLoading(true);
+        try {
+            await api.post('/auth/password/reset', { token, password });
+            setDone(true);
+        } catch (err) {
+            setError(err instanceof ApiError ? err.message : 'Failed to reset password');
+        } finally {
+            setIsLoading(false);
+        }
+    };
+
+    const inputClass = 'w-full pl-11 pr-12 py-3 rounded-xl border border-gray-200 dark:border-gray-700 bg-white dark:bg-[#252838] text-gray-900 dark:text-white placeholder-gray-400 focus:border-[#6786f5] focus:outline-none focus:ring-2 focus:ring-[#6786f5]/20';
+
+    return (
+        <div className="flex min-h-screen items-center justify-center bg-[#f8f9ff] dark:bg-[#0f111a] px-4 py-12">
+            <div className="w-full max-w-md space-y-8 rounded-2xl bg-white dark:bg-[#1a1d2b] p-10 shadow-xl border border-gray-100 dark:border-gray-800">
+                <div className="text-center flex flex-col items-center gap-3">
+                    <MioLogo size="lg" />
+                    <div className="w-16 h-16 bg-[#6786f51a] rounded-full flex items-center justify-center mx-auto">
+                        <KeyRound className="w-8 h-8 text-[#6786f5]" />
+                    </div>
+                    <h2 className="text-xl font-bold text-gray-900 dark:text-white" style={{ fontFamily: "'Lexend', sans-serif" }}>
+                        {done ? 'Password updated' : 'Choose a new password'}
+                    </h2>
+                    <p className="text-sm text-gray-500 dark:text-gray-400">
+                        {done
+                            ? 'Your password has been reset and you have been signed out everywhere. Sign in with your new password.'
+                            : 'Use at least 10 characters with both letters and numbers.'}
+                    </p>
+                </div>
+
+                {!token && !done && (
+                    <div className="p-3 bg-red-50 dark:bg-red-500/10 text-red-700 dark:text-red-400 rounded-xl text-sm text-center">
+                        This reset link is incomplete. <Link to="/forgot-password" className="font-semibold underline">Request a new one</Link>.
+                    </div>
+                )}
+
+                {error && (
+                    <div className="p-3 bg-red-50 dark:bg-red-500/10 text-red-700 dark:text-red-400 rounded-xl text-sm text-center">
+                        {error}
+                    </div>
+                )}
+
+                {done ? (
+                    <Link
+                        to="/login"
+                        className="w-full flex items-center justify-center gap-2 rounded-xl bg-[#6786f5] px-6 py-3 text-base font-semibold text-white shadow-sm transition-all hover:bg-[#5570e0]"
+                    >
+                        Sign in
+                    </Link>
+                ) : token && (
+                    <form onSubmit={handleSubmit} className="space-y-4">
+                        <div className="relative">
+                            <Lock className="absolute left-3.5 top-1/2 -translate-y-1/2 w-5 h-5 text-gray-400" />
+                            <input
+                                type={showPassword ? 'text' : 'password'}
+                                required
+                                autoFocus
+                                minLength={10}
+                                value={password}
+                                onChange={(e) => setPassword(e.target.value)}
+                                placeholder="New password"
+                                className={inputClass}
+                            />
+                            <button
+                                type="button"
+                                onClick={() => setShowPassword(!showPassword)}
+                                className="absolute right-3.5 top-1/2 -translate-y-1/2 text-gray-400 hover:text-gray-600 dark:hover:text-gray-300"
+                            >
+                                {showPassword ? <EyeOff className="w-5 h-5" /> : <Eye className="w-5 h-5" />}
+                            </button>
+                        </div>
+                        <div className="relative">
+                            <Lock className="absolute left-3.5 top-1/2 -translate-y-1/2 w-5 h-5 text-gray-400" />
+                            <input
+                                type={showPassword ? 'text' : 'password'}
+                                required
+                                minLength={10}
+                                value={confirmPassword}
+                                onChange={(e) => setConfirmPassword(e.target.value)}
+                                placeholder="Confirm new password"
+                                className={inputClass}
+                            />
+                        </div>
+                        <button
+                            type="submit"
+                            disabled={isLoading}
+                            className="w-full flex items-center justify-center gap-2 rounded-xl bg-[#6786f5] px-6 py-3 text-base font-semibold text-white shadow-sm transition-all hover:bg-[#5570e0] disabled:opacity-50"
+                        >
+                            {isLoading ? <Loader2 className="w-5 h-5 animate-spin" /> : 'Reset password'}
+                        </button>
+                    </form>
+                )}
+
+                {!done && (
+                    <Link
+                        to="/login"
+                        className="flex items-center justify-center gap-2 text-sm text-gray-500 hover:text-gray-700 dark:text-gray-400 dark:hover:text-gray-200"
+                    >
+                        <ArrowLeft className="w-4 h-4" />
+                        Back to sign in
+                    </Link>
+                )}
+            </div>
+        </div>
+    );
+}
@@ -90,7 +90,7 @@ export default function VerifyOTPPage() {
         if (resendCooldown > 0) return;
         setError('');
         try {
-            await api.post('/auth/creator/signup', { email, password: '__resend__' });
+            await api.post('/auth/creator/resend-otp', { email });
             setResendCooldown(60);
         } catch {
             setError('Could not resend OTP. Please try signing up again.');
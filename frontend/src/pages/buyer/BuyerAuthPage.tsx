@@ -59,6 +59,12 @@ export default function BuyerAuthPage() {
                     </div>
                 )}
 
+                {urlError === 'different_browser' && (
+                    <div className="rounded-md bg-red-50 p-4">
+                        <p className="text-sm text-red-700">Please open the login link in the same browser you requested it from, or request a new one here.</p>
+                    </div>
+                )}
+
                 {status === 'success' ? (
                     <div className="rounded-md bg-green-50 p-4 text-center">
                         <h3 className="text-lg font-medium text-green-800">Check your email</h3>
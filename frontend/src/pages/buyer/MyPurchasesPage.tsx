@@ -3,12 +3,16 @@ import { useAuth } from '../../context/AuthContext';
 import { Link, Navigate } from 'react-router-dom';
 import { buyerApi } from '../../features/buyer/api';
 import type { Order, Subscription } from '../../features/buyer/api';
+import { getOrderDownloadUrl } from '../../features/orders/api';
+import type { PurchasedFile } from '../../features/orders/api';
+import PrivacySettings from '../../components/account/PrivacySettings';
 
 export default function MyPurchasesPage() {
     const { isAuthenticated, user, logout } = useAuth();
     const [activeTab, setActiveTab] = useState<'purchases' | 'subscriptions'>('purchases');
 
     const [purchases, setPurchases] = useState<Order[]>([]);
+    const [productFiles, setProductFiles] = useState<Record<string, PurchasedFile[]>>({});
     const [subscriptions, setSubscriptions] = useState<Subscription[]>([]);
     const [loading, setLoading] = useState(true);
     const [error, setError] = useState<string | null>(null);
@@ -31,6 +35,7 @@ export default function MyPurchasesPage() {
                 if (activeTab === 'purchases') {
                     const res = await buyerApi.getPurchases();
                     setPurchases(res.data || []);
+                    setProductFiles((res.meta?.product_files as Record<string, PurchasedFile[]>) || {});
                 } else {
                     const res = await buyerApi.getSubscriptions();
                     setSubscriptions(res.data || []);
@@ -58,6 +63,24 @@ export default function MyPurchasesPage() {
         }
     };
 
+    const handleDownload = async (orderId: string, productId: string, fileId?: string) => {
+        try {
+            const url = await getOrderDownloadUrl(orderId, productId, fileId);
+            window.open(url, '_blank');
+        } catch (err: any) {
+            alert(err.message || 'Failed to generate download link');
+        }
+    };
+
+    const handleInvoice = async (orderId: string) => {
+        try {
+            const res = await buyerApi.getInvoice(orderId);
+            if (res.data?.download_url) window.open(res.data.download_url, '_blank');
+        } catch (err: any) {
+            alert(err.message || 'Failed to fetch invoice');
+        }
+    };
+
     return (
         <div className="min-h-screen bg-gray-50">
             {/* Simple Buyer Navigation */}
@@ -157,15 +180,26 @@ export default function MyPurchasesPage() {
                                 </div>
 
                                 <div className="bg-gray-50 px-6 py-4 border-t border-gray-100">
-                                    {order.line_items && order.line_items.length > 0 && (order.line_items[0].product_type === 'download' || order.line_items[0].product_type === 'lead_magnet') && (
-                                        <a
-                                            href={`/api/v1/orders/${order.id}/download`}
-                                            target="_blank"
-                                            rel="noreferrer"
+                                    {order.line_items && order.line_items.length > 0 && (order.line_items[0].product_type === 'download' || order.line_items[0].product_type === 'lead_magnet') && (productFiles[order.line_items[0].product_id]?.length ?? 0) > 1 ? (
+                                        <div className="space-y-2">
+                                            {productFiles[order.line_items[0].product_id].map((file) => (
+                                                <button
+                                                    key={file.id}
+                                                    onClick={() => handleDownload(order.id, order.line_items![0].product_id, file.id)}
+                                                    className="w-full flex justify-between items-center px-4 py-2 border border-transparent rounded-md shadow-sm text-sm font-medium text-white bg-indigo-600 hover:bg-indigo-700 transition"
+                                                >
+                                                    <span className="truncate">{file.label}</span>
+                                                    {file.version > 1 && <span className="ml-2 text-xs text-indigo-200">v{file.version}</span>}
+                                                </button>
+                                            ))}
+                                        </div>
+                                    ) : order.line_items && order.line_items.length > 0 && (order.line_items[0].product_type === 'download' || order.line_items[0].product_type === 'lead_magnet') && (
+                                        <button
+                                            onClick={() => handleDownload(order.id, order.line_items![0].product_id)}
                                             className="w-full flex justify-center items-center px-4 py-2 border border-transparent rounded-md shadow-sm text-sm font-medium text-white bg-indigo-600 hover:bg-indigo-700 transition"
                                         >
                                             Download Access
-                                        </a>
+                                        </button>
                                     )}
                                     {order.line_items && order.line_items.length > 0 && order.line_items[0].product_type === 'course' && (
                                         <Link
@@ -196,6 +230,15 @@ export default function MyPurchasesPage() {
                                             )}
                                         </div>
                                     )}
+                                    {order.status === 'paid' && order.amount > 0 && (
+                                        <button
+                                            type="button"
+                                            onClick={() => handleInvoice(order.id)}
+                                            className="mt-3 w-full text-center text-sm text-gray-600 hover:text-gray-900 underline"
+                                        >
+                                            Download invoice
+                                        </button>
+                                    )}
                                 </div>
                             </div>
                         ))}
@@ -245,6 +288,10 @@ export default function MyPurchasesPage() {
                         ))}
                     </div>
                 )}
+
+                <div className="mt-10">
+                    <PrivacySettings />
+                </div>
             </main>
         </div>
     );
@@ -1,17 +1,29 @@
 import React, { useEffect, useState } from 'react';
 import { getSalesHistory } from '../../features/dashboard/api';
-import type { Order } from '../../features/dashboard/api';
+import type { Order, SalesFilters, SalesTotals } from '../../features/dashboard/api';
+
+const inputClass = 'px-3 py-2 text-sm rounded-md border border-gray-300 dark:border-white/10 bg-white dark:bg-[#0f111a] dark:text-white';
+
+const formatRupees = (paise: number) => `₹${(paise / 100).toFixed(2)}`;
 
 const OrdersPage: React.FC = () => {
     const [orders, setOrders] = useState<Order[]>([]);
+    const [totals, setTotals] = useState<SalesTotals | null>(null);
+    const [filters, setFilters] = useState<SalesFilters>({ sort: 'newest', page: 1, pageSize: 25 });
+    const [search, setSearch] = useState('');
+    const [totalPages, setTotalPages] = useState(1);
     const [loading, setLoading] = useState(true);
     const [error, setError] = useState('');
 
     useEffect(() => {
         const fetchOrders = async () => {
+            setLoading(true);
             try {
-                const data = await getSalesHistory();
-                setOrders(data);
+                const data = await getSalesHistory(filters);
+                setOrders(data.orders);
+                setTotals(data.totals);
+                setTotalPages(data.totalPages);
+                setError('');
             } catch (err) {
                 setError('Failed to load orders');
             } finally {
@@ -20,15 +32,91 @@ const OrdersPage: React.FC = () => {
         };
 
         fetchOrders();
-    }, []);
+    }, [filters]);
 
-    if (loading) return <div className="dark:text-gray-400">Loading orders...</div>;
-    if (error) return <div className="text-red-500">{error}</div>;
+    // Any filter change starts again from the first page
+    const updateFilter = (changes: Partial<SalesFilters>) => {
+        setFilters(prev => ({ ...prev, ...changes, page: 1 }));
+    };
+
+    const toPaise = (value: string) => (value === '' ? undefined : Math.round(parseFloat(value) * 100));
 
     return (
         <div className="space-y-6">
             <h1 className="text-xl sm:text-2xl font-bold text-gray-900 dark:text-white">Orders</h1>
 
+            <form
+                className="flex flex-wrap gap-3"
+                onSubmit={e => {
+                    e.preventDefault();
+                    updateFilter({ q: search.trim() || undefined });
+                }}
+            >
+                <input
+                    type="search"
+                    value={search}
+                    onChange={e => setSearch(e.target.value)}
+                    placeholder="Search buyer email or name"
+                    className={`${inputClass} flex-1 min-w-[200px]`}
+                />
+                <select value={filters.status || ''} onChange={e => updateFilter({ status: e.target.value || undefined })} className={inputClass}>
+                    <option value="">All statuses</option>
+                    <option value="paid">Paid</option>
+                    <option value="created">Pending</option>
+                    <option value="failed">Failed</option>
+                </select>
+                <input type="date" value={filters.from || ''} onChange={e => updateFilter({ from: e.target.value || undefined })} className={inputClass} aria-label="From" />
+                <input type="date" value={filters.to || ''} onChange={e => updateFilter({ to: e.target.value || undefined })} className={inputClass} aria-label="To" />
+                <input
+                    type="text"
+                    placeholder="Coupon"
+                    onBlur={e => updateFilter({ coupon: e.target.value.trim() || undefined })}
+                    className={`${inputClass} w-28`}
+                />
+                <input
+                    type="number"
+                    min="0"
+                    placeholder="Min ₹"
+                    onBlur={e => updateFilter({ min_amount: toPaise(e.target.value) })}
+                    className={`${inputClass} w-24`}
+                />
+                <input
+                    type="number"
+                    min="0"
+                    placeholder="Max ₹"
+                    onBlur={e => updateFilter({ max_amount: toPaise(e.target.value) })}
+                    className={`${inputClass} w-24`}
+                />
+                <select
+                    value={filters.sort}
+                    onChange={e => updateFilter({ sort: e.target.value as SalesFilters['sort'] })}
+                    className={inputClass}
+                >
+                    <option value="newest">Newest first</option>
+                    <option value="oldest">Oldest first</option>
+                    <option value="amount_desc">Highest amount</option>
+                    <option value="amount_asc">Lowest amount</option>
+                </select>
+            </form>
+
+            {totals && (
+                <div className="grid grid-cols-2 sm:grid-cols-4 gap-4">
+                    {[
+                        { label: 'Orders', value: `${totals.count}` },
+                        { label: 'Paid', value: `${totals.paid_count}` },
+                        { label: 'Revenue', value: formatRupees(totals.revenue) },
+                        { label: 'Net after fees', value: formatRupees(totals.net) },
+                    ].map(stat => (
+                        <div key={stat.label} className="bg-white dark:bg-[#1e2135] p-4 rounded-xl shadow-sm border border-gray-100 dark:border-gray-700">
+                            <p className="text-xs text-gray-500 dark:text-gray-400">{stat.label}</p>
+                            <p className="text-lg font-semibold text-gray-900 dark:text-white">{stat.value}</p>
+                        </div>
+                    ))}
+                </div>
+            )}
+
+            {error && <div className="text-red-500">{error}</div>}
+
             <div className="bg-white dark:bg-[#1e2135] rounded-xl shadow-sm border border-gray-100 dark:border-gray-700 overflow-hidden">
                 <div className="overflow-x-auto">
                     <table className="w-full text-left text-sm text-gray-600 dark:text-gray-400">
@@ -42,7 +130,13 @@ const OrdersPage: React.FC = () => {
                             </tr>
                         </thead>
                         <tbody className="divide-y divide-gray-100 dark:divide-gray-700">
-                            {orders.length > 0 ? (
+                            {loading ? (
+                                <tr>
+                                    <td colSpan={5} className="px-6 py-8 text-center text-gray-500 dark:text-gray-400">
+                                        Loading orders...
+                                    </td>
+                                </tr>
+                            ) : orders.length > 0 ? (
                                 orders.map((order) => (
                                     <tr key={order.id} className="hover:bg-gray-50 dark:hover:bg-white/5">
                                         <td className="px-6 py-4 font-mono text-xs text-gray-500 dark:text-gray-400">
@@ -61,7 +155,7 @@ const OrdersPage: React.FC = () => {
                                             </span>
                                         </td>
                                         <td className="px-6 py-4 text-right font-medium text-gray-900 dark:text-white">
-                                            ₹{(order.amount / 100).toFixed(2)}
+                                            {formatRupees(order.amount)}
                                         </td>
                                     </tr>
                                 ))
@@ -76,6 +170,28 @@ const OrdersPage: React.FC = () => {
                     </table>
                 </div>
             </div>
+
+            {totalPages > 1 && (
+                <div className="flex items-center justify-between text-sm text-gray-600 dark:text-gray-400">
+                    <button
+                        type="button"
+                        onClick={() => setFilters(prev => ({ ...prev, page: (prev.page || 1) - 1 }))}
+                        disabled={loading || (filters.page || 1) <= 1}
+                        className="px-3 py-1.5 rounded-md border border-gray-300 dark:border-white/10 disabled:opacity-50"
+                    >
+                        Previous
+                    </button>
+                    <span>Page {filters.page} of {totalPages}</span>
+                    <button
+                        type="button"
+                        onClick={() => setFilters(prev => ({ ...prev, page: (prev.page || 1) + 1 }))}
+                        disabled={loading || (filters.page || 1) >= totalPages}
+                        className="px-3 py-1.5 rounded-md border border-gray-300 dark:border-white/10 disabled:opacity-50"
+                    >
+                        Next
+                    </button>
+                </div>
+            )}
         </div>
     );
 };
@@ -1,8 +1,14 @@
 import React, { useState, useEffect } from 'react';
 import { api } from '../../lib/api';
-import { getPresignedUrl, uploadFileToUrl } from '../../lib/api/products';
+import { getPresignedUrl, uploadFileToUrl, completeUpload } from '../../lib/api/products';
 import { Loader2, Save, Plus, Trash2, User, Image, Palette, Type, Layout, Upload, Smartphone } from 'lucide-react';
 import ImageCropperModal from '../../components/dashboard/ImageCropperModal';
+import PasswordSettings from '../../components/account/PasswordSettings';
+import TwoFactorSettings from '../../components/account/TwoFactorSettings';
+import PrivacySettings from '../../components/account/PrivacySettings';
+import APIKeySettings from '../../components/account/APIKeySettings';
+import WebhookSettings from '../../components/account/WebhookSettings';
+import InvoiceSettings from '../../components/account/InvoiceSettings';
 
 interface SocialLink {
     platform: string;
@@ -258,6 +264,7 @@ const SettingsPage = () => {
             if (avatarFile) {
                 const presigned = await getPresignedUrl(avatarFile.name, avatarFile.type, 'cover_image');
                 await uploadFileToUrl(presigned.url, avatarFile);
+                await completeUpload(presigned.key);
                 let r2PublicUrl = import.meta.env.VITE_R2_PUBLIC_URL || '';
                 if (r2PublicUrl) {
                     r2PublicUrl = r2PublicUrl.replace(/\/$/, '');
@@ -270,6 +277,7 @@ const SettingsPage = () => {
             if (coverFile) {
                 const presigned = await getPresignedUrl(coverFile.name, coverFile.type, 'cover_image');
                 await uploadFileToUrl(presigned.url, coverFile);
+                await completeUpload(presigned.key);
                 let r2PublicUrl = import.meta.env.VITE_R2_PUBLIC_URL || '';
                 if (r2PublicUrl) {
                     r2PublicUrl = r2PublicUrl.replace(/\/$/, '');
@@ -783,6 +791,30 @@ const SettingsPage = () => {
                 </div>
             </form>
 
+            <div className="mt-8">
+                <InvoiceSettings />
+            </div>
+
+            <div className="mt-8">
+                <APIKeySettings />
+            </div>
+
+            <div className="mt-8">
+                <WebhookSettings />
+            </div>
+
+            <div className="mt-8">
+                <PasswordSettings />
+            </div>
+
+            <div className="mt-8">
+                <TwoFactorSettings />
+            </div>
+
+            <div className="mt-8">
+                <PrivacySettings />
+            </div>
+
             <ImageCropperModal
                 isOpen={cropperConfig.isOpen}
                 onClose={() => setCropperConfig(prev => ({ ...prev, isOpen: false }))}